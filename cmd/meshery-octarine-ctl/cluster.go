@@ -0,0 +1,442 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/layer5io/meshery-octarine/meshes"
+)
+
+func clusterCmd(c pb.MeshServiceClient, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.ClusterCapabilities(ctx, &pb.ClusterCapabilitiesRequest{})
+	if err != nil {
+		return fmt.Errorf("could not inspect the cluster: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not inspect the cluster: %s", resp.GetError())
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Kubernetes version:\t%s\n", resp.GetKubernetesVersion())
+	fmt.Fprintf(w, "CNI plugins:\t%s\n", strings.Join(resp.GetCniPlugins(), ", "))
+	fmt.Fprintf(w, "Admission webhooks:\t%t\n", resp.GetAdmissionWebhooks())
+	fmt.Fprintf(w, "Pod security:\t%s\n", resp.GetPodSecurity())
+	fmt.Fprintf(w, "LoadBalancer services:\t%t\n", resp.GetLoadBalancer())
+	classes := make([]string, 0, len(resp.GetStorageClasses()))
+	for _, sc := range resp.GetStorageClasses() {
+		name := sc.GetName()
+		if sc.GetDefault() {
+			name += " (default)"
+		}
+		classes = append(classes, name)
+	}
+	fmt.Fprintf(w, "Storage classes:\t%s\n", strings.Join(classes, ", "))
+	fmt.Fprintf(w, "Node platforms:\t%s\n", strings.Join(resp.GetNodePlatforms(), ", "))
+	fmt.Fprintf(w, "IP stack:\t%s\n", resp.GetIpStack())
+	for _, note := range resp.GetNotes() {
+		fmt.Fprintf(w, "Note:\t%s\n", note)
+	}
+	return w.Flush()
+}
+
+func adapterCmd(c pb.MeshServiceClient, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.AdapterCapabilities(ctx, &pb.AdapterCapabilitiesRequest{})
+	if err != nil {
+		return fmt.Errorf("could not describe the adapter: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not describe the adapter: %s", resp.GetError())
+	}
+	ops := make([]string, 0, len(resp.GetOperations()))
+	for _, op := range resp.GetOperations() {
+		ops = append(ops, op.GetKey())
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Adapter:\t%s %s (descriptor v%d)\n", resp.GetName(), resp.GetVersion(), resp.GetDescriptorVersion())
+	fmt.Fprintf(w, "RPCs:\t%s\n", strings.Join(resp.GetRpcs(), ", "))
+	fmt.Fprintf(w, "Operations:\t%s\n", strings.Join(ops, ", "))
+	if len(resp.GetDisabledOperations()) > 0 {
+		fmt.Fprintf(w, "Disabled operations:\t%s\n", strings.Join(resp.GetDisabledOperations(), ", "))
+	}
+	fmt.Fprintf(w, "Streaming:\t%s\n", strings.Join(resp.GetStreaming(), ", "))
+	fmt.Fprintf(w, "Multiple clusters:\t%t\n", resp.GetMultiCluster())
+	fmt.Fprintf(w, "Auth modes:\t%s\n", strings.Join(resp.GetAuthModes(), ", "))
+	fmt.Fprintf(w, "SMI conformance:\t%t\n", resp.GetSmi())
+	fmt.Fprintf(w, "SMP results:\t%t\n", resp.GetSmp())
+	for _, c := range resp.GetClusters() {
+		name := c.GetCluster()
+		if name == "" {
+			name = "(default)"
+		}
+		state := c.GetState()
+		if state != "connected" {
+			state = fmt.Sprintf("%s since %s, %d reconnect attempt(s): %s", state, c.GetSince(), c.GetReconnectAttempts(), c.GetError())
+		}
+		fmt.Fprintf(w, "Cluster %s:\t%s\n", name, state)
+	}
+	return w.Flush()
+}
+
+func inventoryCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("inventory", inventoryUsage)
+	namespace := fs.String("namespace", "", "Only list the resources of this namespace")
+	opID := fs.String("operation-id", "", "Only list the resources applied by this operation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tRESOURCE\tOPERATION\tAPPLIED\tHEALTH")
+	req := &pb.InventoryRequest{Namespace: *namespace, OperationId: *opID}
+	for {
+		resp, err := c.Inventory(ctx, req)
+		if err != nil {
+			return fmt.Errorf("could not list the inventory: %v", err)
+		}
+		if resp.GetError() != "" {
+			return fmt.Errorf("could not list the inventory: %s", resp.GetError())
+		}
+		for _, r := range resp.GetResources() {
+			health := r.GetHealth()
+			if r.GetHealthReason() != "" {
+				health += ": " + r.GetHealthReason()
+			}
+			fmt.Fprintf(w, "%s\t%s/%s\t%s\t%s\t%s\n", r.GetNamespace(), r.GetKind(), r.GetName(), r.GetOperation(), r.GetAppliedAt(), health)
+		}
+		if req.PageToken = resp.GetNextPageToken(); req.PageToken == "" {
+			break
+		}
+	}
+	return w.Flush()
+}
+
+func proxiesCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("proxies", proxiesUsage)
+	deployment := fs.String("deployment", "", "The deployment whose sidecars are checked")
+	namespace := fs.String("namespace", "", "Only list the workloads of this namespace")
+	outdated := fs.Bool("outdated", false, "Only list the workloads whose sidecars are out of date")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	req := &pb.ProxyVersionsRequest{Deployment: *deployment, Namespace: *namespace, OutdatedOnly: *outdated}
+	for {
+		resp, err := c.ProxyVersions(ctx, req)
+		if err != nil {
+			return fmt.Errorf("could not list sidecar versions: %v", err)
+		}
+		if resp.GetError() != "" {
+			return fmt.Errorf("could not list sidecar versions: %s", resp.GetError())
+		}
+		if req.PageToken == "" {
+			fmt.Printf("dataplane version %s\n", resp.GetControlPlaneVersion())
+			fmt.Fprintln(w, "NAMESPACE\tWORKLOAD\tSIDECARS\tUP TO DATE")
+		}
+		for _, wl := range resp.GetWorkloads() {
+			fmt.Fprintf(w, "%s\t%s/%s\t%s\t%t\n", wl.GetNamespace(), wl.GetKind(), wl.GetName(), strings.Join(wl.GetVersions(), ", "), wl.GetUpToDate())
+		}
+		if req.PageToken = resp.GetNextPageToken(); req.PageToken == "" {
+			break
+		}
+	}
+	return w.Flush()
+}
+
+func footprintCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("footprint", footprintUsage)
+	deployment := fs.String("deployment", "", "An installed deployment to measure instead of estimating its dataplane")
+	namespaces := fs.String("namespaces", "", "Comma separated namespaces to inject besides the labeled ones")
+	manifest := fs.String("manifest", "", "A rendered dataplane manifest to estimate, - for stdin")
+	sidecarCPU := fs.String("sidecar-cpu", "", "The CPU request of a sidecar")
+	sidecarMemory := fs.String("sidecar-memory", "", "The memory request of a sidecar")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	body, err := readBodyFile(*manifest)
+	if err != nil {
+		return err
+	}
+	req := &pb.EstimateFootprintRequest{
+		Deployment:    *deployment,
+		Manifest:      string(body),
+		SidecarCpu:    *sidecarCPU,
+		SidecarMemory: *sidecarMemory,
+	}
+	if *namespaces != "" {
+		req.Namespaces = strings.Split(*namespaces, ",")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.EstimateFootprint(ctx, req)
+	if err != nil {
+		return fmt.Errorf("could not estimate the footprint: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not estimate the footprint: %s", resp.GetError())
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "COMPONENT\tPODS\tCPU\tMEMORY\tSOURCE")
+	cp := resp.GetControlPlane()
+	fmt.Fprintf(w, "dataplane\t%d\t%s\t%s\t%s\n", cp.GetPods(), cp.GetCpu(), cp.GetMemory(), resp.GetControlPlaneSource())
+	fmt.Fprintf(w, "sidecar (each)\t\t%s\t%s\t%s\n", resp.GetSidecar().GetCpu(), resp.GetSidecar().GetMemory(), resp.GetSidecarSource())
+	for _, ns := range resp.GetNamespaces() {
+		fmt.Fprintf(w, "sidecars in %s\t%d (+%d injected)\t%s\t%s\t\n", ns.GetNamespace(), ns.GetPods(), ns.GetInjected(),
+			ns.GetSidecars().GetCpu(), ns.GetSidecars().GetMemory())
+	}
+	fmt.Fprintf(w, "total\t\t%s\t%s\t\n", resp.GetTotal().GetCpu(), resp.GetTotal().GetMemory())
+	return w.Flush()
+}
+
+func identitiesCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("identities", identitiesUsage)
+	namespace := fs.String("namespace", "", "The namespace whose workloads are reported")
+	deployment := fs.String("deployment", "", "The deployment whose sidecars give the workloads their identity")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *namespace == "" {
+		fs.Usage()
+		return fmt.Errorf("a namespace is required")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	req := &pb.WorkloadIdentitiesRequest{Namespace: *namespace, Deployment: *deployment}
+	var first *pb.WorkloadIdentitiesResponse
+	for {
+		resp, err := c.WorkloadIdentities(ctx, req)
+		if err != nil {
+			return fmt.Errorf("could not report workload identities: %v", err)
+		}
+		if resp.GetError() != "" {
+			return fmt.Errorf("could not report workload identities: %s", resp.GetError())
+		}
+		if first == nil {
+			first = resp
+			fmt.Fprintln(w, "WORKLOAD\tPODS\tSERVICE ACCOUNT\tIDENTITY\tPRIVILEGES\tIMAGES")
+		}
+		for _, wl := range resp.GetWorkloads() {
+			privileges := strings.Join(wl.GetPrivileges(), ", ")
+			if privileges == "" {
+				privileges = "-"
+			}
+			fmt.Fprintf(w, "%s/%s\t%d\t%s\t%t\t%s\t%s\n", wl.GetKind(), wl.GetName(), wl.GetPods(), wl.GetServiceAccount(), wl.GetOctarineIdentity(), privileges, strings.Join(wl.GetImages(), ", "))
+		}
+		if req.PageToken = resp.GetNextPageToken(); req.PageToken == "" {
+			break
+		}
+	}
+	fmt.Fprintln(w, "\nSERVICE ACCOUNT\tEXISTS\tWORKLOADS")
+	for _, sa := range first.GetServiceAccounts() {
+		fmt.Fprintf(w, "%s\t%t\t%s\n", sa.GetName(), sa.GetExists(), strings.Join(sa.GetWorkloads(), ", "))
+	}
+	fmt.Fprintln(w, "\nREGISTRY\tIMAGES")
+	for _, reg := range first.GetRegistries() {
+		fmt.Fprintf(w, "%s\t%s\n", reg.GetRegistry(), strings.Join(reg.GetImages(), ", "))
+	}
+	return w.Flush()
+}
+
+func latencyCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("latency", latencyUsage)
+	namespace := fs.String("namespace", "", "The injected namespace whose last latency probe is printed")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *namespace == "" {
+		fs.Usage()
+		return fmt.Errorf("a namespace is required")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.LatencyProbeReport(ctx, &pb.LatencyProbeReportRequest{Namespace: *namespace})
+	if err != nil {
+		return fmt.Errorf("could not get the latency probe report: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not get the latency probe report: %s", resp.GetError())
+	}
+	fmt.Printf("namespace %s probed at %s, %s over %d connections\n", resp.GetNamespace(), resp.GetProbedAt(), resp.GetDuration(), resp.GetConnections())
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SIDECARS\tP50\tP95\tP99\tQPS\tERRORS")
+	for _, side := range []struct {
+		name   string
+		result *pb.LatencyResult
+	}{{"without", resp.GetPlain()}, {"with", resp.GetMeshed()}} {
+		r := side.result
+		fmt.Fprintf(w, "%s\t%.2fms\t%.2fms\t%.2fms\t%.1f\t%d\n", side.name, r.GetP50Ms(), r.GetP95Ms(), r.GetP99Ms(), r.GetQps(), r.GetErrors())
+	}
+	fmt.Fprintf(w, "overhead\t%+.2fms\t%+.2fms\t%+.2fms\t%+.1f%%\t\n", resp.GetP50OverheadMs(), resp.GetP95OverheadMs(), resp.GetP99OverheadMs(), resp.GetThroughputDeltaPercent())
+	return w.Flush()
+}
+
+func telemetryCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("telemetry", telemetryUsage)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.PreviewTelemetry(ctx, &pb.PreviewTelemetryRequest{})
+	if err != nil {
+		return fmt.Errorf("could not preview the telemetry: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not preview the telemetry: %s", resp.GetError())
+	}
+	if resp.GetEnabled() {
+		fmt.Printf("usage is reported to %s every %s, next at %s\n", resp.GetEndpoint(), resp.GetInterval(), resp.GetNextReport())
+	} else {
+		fmt.Println("telemetry is disabled, nothing is sent; this is what a report would hold:")
+	}
+	var report bytes.Buffer
+	if err := json.Indent(&report, []byte(resp.GetReport()), "", "  "); err != nil {
+		return fmt.Errorf("could not read the report: %v", err)
+	}
+	fmt.Println(report.String())
+	return nil
+}
+
+func imagesCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("images", imagesUsage)
+	version := fs.String("version", "", "The Octarine release whose images are listed, the version of the deployment by default")
+	deployment := fs.String("deployment", "", "The deployment whose account renders the dataplane of the release")
+	images := fs.StringArray("image", nil, "An image to look up instead of the images of the release")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	// the registries are asked for every image and platform, which takes longer than the other calls
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	resp, err := c.ImageManifests(ctx, &pb.ImageManifestsRequest{Version: *version, Deployment: *deployment, Images: *images})
+	if err != nil {
+		return fmt.Errorf("could not look up the image manifests: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not look up the image manifests: %s", resp.GetError())
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "IMAGE\tPLATFORM\tDIGEST\tSIZE")
+	failed := 0
+	for _, image := range resp.GetImages() {
+		if image.GetError() != "" {
+			failed++
+			fmt.Fprintf(w, "%s\t-\t-\t%s\n", image.GetImage(), image.GetError())
+			continue
+		}
+		// the digest the tag resolves to, copying it copies every platform
+		fmt.Fprintf(w, "%s\t*\t%s\t-\n", image.GetImage(), image.GetDigest())
+		for _, p := range image.GetPlatforms() {
+			platform := p.GetOs() + "/" + p.GetArchitecture()
+			if p.GetVariant() != "" {
+				platform += "/" + p.GetVariant()
+			}
+			fmt.Fprintf(w, "\t%s\t%s\t%d\n", platform, p.GetDigest(), p.GetSize_())
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("platforms of every image: %s\n", strings.Join(resp.GetCommonPlatforms(), ", "))
+	if failed > 0 {
+		return fmt.Errorf("%d of %d images could not be looked up", failed, len(resp.GetImages()))
+	}
+	return nil
+}
+
+func kubeconfigCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("kubeconfig", kubeconfigUsage)
+	deployment := fs.String("deployment", "", "The deployment whose namespace the kubeconfig is limited to")
+	user := userFlag(fs, "Who the kubeconfig is issued to")
+	ttl := fs.String("ttl", "", "How long the token stays valid (default 1h)")
+	output := fs.String("output", "", "The file the kubeconfig is written to, stdout when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.ExportKubeconfig(ctx, &pb.ExportKubeconfigRequest{Deployment: *deployment, Username: *user, Ttl: *ttl})
+	if err != nil {
+		return fmt.Errorf("could not export kubeconfig: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not export kubeconfig: %s", resp.GetError())
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(resp.GetKubeconfig())
+		return err
+	}
+	if err := ioutil.WriteFile(*output, resp.GetKubeconfig(), 0600); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "kubeconfig written to %s, valid until %s\n", *output, resp.GetExpiresAt())
+	return nil
+}
+
+func licenseCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("license", licenseUsage)
+	deployment := fs.String("deployment", "", "The deployment whose Octarine account is reported, the default one when empty")
+	cluster := clusterFlag(fs, "of the deployment")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.LicenseStatus(ctx, &pb.LicenseStatusRequest{Deployment: *deployment, Cluster: *cluster})
+	if err != nil {
+		return fmt.Errorf("could not get the license: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not get the license: %s", resp.GetError())
+	}
+	fmt.Printf("account %s, %s license\n", resp.GetAccount(), resp.GetTier())
+	if resp.GetExpires() != "" {
+		fmt.Printf("expires %s, %d days left\n", resp.GetExpires(), resp.GetDaysLeft())
+	}
+	if len(resp.GetFeatures()) > 0 {
+		fmt.Printf("features: %s\n", strings.Join(resp.GetFeatures(), ", "))
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ENTITLEMENT\tUSED\tLIMIT\tAVAILABLE")
+	for _, e := range resp.GetEntitlements() {
+		limit, available := "unlimited", "unlimited"
+		if e.GetLimit() > 0 {
+			limit, available = fmt.Sprint(e.GetLimit()), fmt.Sprint(e.GetAvailable())
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", e.GetName(), e.GetUsed(), limit, available)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, warning := range resp.GetWarnings() {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+	return nil
+}
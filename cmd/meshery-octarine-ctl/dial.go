@@ -0,0 +1,87 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+)
+
+// dial connects to the adapter of --addr with the TLS, message sizes, compression and languages of the global flags
+func dial() (*grpc.ClientConn, error) {
+	callOpts := []grpc.CallOption{grpc.MaxCallRecvMsgSize(*maxMessageSize), grpc.MaxCallSendMsgSize(*maxMessageSize)}
+	if *compress {
+		callOpts = append(callOpts, grpc.UseCompressor(gzip.Name))
+	}
+	security := grpc.WithInsecure()
+	if *useTLS || *tlsCAFile != "" || *tlsCertFile != "" || *tlsKeyFile != "" || *tlsServerName != "" {
+		creds, err := transportCredentials()
+		if err != nil {
+			return nil, err
+		}
+		security = grpc.WithTransportCredentials(creds)
+	}
+	dialOpts := []grpc.DialOption{security, grpc.WithDefaultCallOptions(callOpts...),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{Time: time.Minute, Timeout: 20 * time.Second})}
+	if *lang != "" {
+		dialOpts = append(dialOpts, grpc.WithUnaryInterceptor(unaryLanguage), grpc.WithStreamInterceptor(streamLanguage))
+	}
+	return grpc.Dial(*address, dialOpts...)
+}
+
+// transportCredentials are the TLS credentials of the --tls flags
+func transportCredentials() (credentials.TransportCredentials, error) {
+	cfg := &tls.Config{ServerName: *tlsServerName}
+	if *tlsCAFile != "" {
+		pem, err := ioutil.ReadFile(*tlsCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read --tls-ca-file: %v", err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("--tls-ca-file %s has no PEM certificate", *tlsCAFile)
+		}
+	}
+	if (*tlsCertFile == "") != (*tlsKeyFile == "") {
+		return nil, fmt.Errorf("--tls-cert-file and --tls-key-file go together")
+	}
+	if *tlsCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(*tlsCertFile, *tlsKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the client certificate: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(cfg), nil
+}
+
+// unaryLanguage and streamLanguage send the languages of --lang along with every call
+func unaryLanguage(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(metadata.AppendToOutgoingContext(ctx, "accept-language", *lang), method, req, reply, cc, opts...)
+}
+
+func streamLanguage(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(metadata.AppendToOutgoingContext(ctx, "accept-language", *lang), desc, cc, method, opts...)
+}
@@ -0,0 +1,158 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	pb "github.com/layer5io/meshery-octarine/meshes"
+)
+
+func eventsCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("events", eventsUsage)
+	opID := fs.String("operation-id", "", "Only show events of this operation")
+	minSeverity := fs.String("min-severity", "", "Only show events of this severity or above")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	severity, err := parseSeverity(*minSeverity)
+	if err != nil {
+		return err
+	}
+	stream, err := c.StreamEvents(context.Background(), &pb.EventsRequest{MinSeverity: severity, OperationId: *opID})
+	if err != nil {
+		return fmt.Errorf("could not stream events: %v", err)
+	}
+	return printEvents(stream, *opID)
+}
+
+func historyCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("history", historyUsage)
+	since := fs.String("since", "24h", "The RFC 3339 time to list the events from, or how long ago")
+	until := fs.String("until", "", "The RFC 3339 time to list the events until, now when empty")
+	minSeverity := fs.String("min-severity", "", "Only list events of this severity or above")
+	namespace := fs.String("namespace", "", "Only list the events of the operations in this namespace")
+	opID := fs.String("operation-id", "", "Only list the events of this operation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	severity, err := parseSeverity(*minSeverity)
+	if err != nil {
+		return err
+	}
+	from := *since
+	if ago, err := time.ParseDuration(from); err == nil {
+		from = time.Now().Add(-ago).UTC().Format(time.RFC3339)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	req := &pb.QueryEventsRequest{Since: from, Until: *until, MinSeverity: severity, Namespace: *namespace, OperationId: *opID}
+	for {
+		resp, err := c.QueryEvents(ctx, req)
+		if err != nil {
+			return fmt.Errorf("could not query events: %v", err)
+		}
+		if resp.GetError() != "" {
+			return fmt.Errorf("could not query events: %s", resp.GetError())
+		}
+		for _, event := range resp.GetEvents() {
+			fmt.Printf("%s [%s] %s\n", event.GetTime(), strings.TrimPrefix(event.GetSeverity().String(), "SEVERITY_"), event.GetSummary())
+			if details := strings.TrimSpace(event.GetDetails()); details != "" {
+				fmt.Printf("    %s\n", strings.Replace(colorChanges(details), "\n", "\n    ", -1))
+			}
+		}
+		if req.PageToken = resp.GetNextPageToken(); req.PageToken == "" {
+			return nil
+		}
+	}
+}
+
+func printEvents(stream pb.MeshService_StreamEventsClient, opID string) error {
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if stream.Context().Err() == context.DeadlineExceeded {
+				return nil
+			}
+			return fmt.Errorf("event stream failed: %v", err)
+		}
+		if opID != "" && event.GetOperationId() != opID {
+			continue
+		}
+		level := event.GetEventType().String()
+		if event.GetSeverity() != pb.Severity_SEVERITY_UNSPECIFIED {
+			level = strings.TrimPrefix(event.GetSeverity().String(), "SEVERITY_")
+		}
+		// the time the adapter received the event, in the local time zone; older adapters don't send it
+		at := time.Now()
+		if t, err := time.Parse(time.RFC3339Nano, event.GetTime()); err == nil {
+			at = t.Local()
+		}
+		summary := event.GetSummary()
+		if event.GetElapsed() != "" {
+			summary += fmt.Sprintf(" (+%s)", event.GetElapsed())
+		}
+		fmt.Printf("%s [%s] %s\n", at.Format(time.RFC3339), level, summary)
+		if details := strings.TrimSpace(event.GetDetails()); details != "" {
+			fmt.Printf("    %s\n", strings.Replace(colorChanges(details), "\n", "\n    ", -1))
+		}
+	}
+}
+
+// colorChanges colors the field changes the adapter lists for the resources it updates, added green, removed red
+// and changed yellow
+func colorChanges(details string) string {
+	if !useColor() {
+		return details
+	}
+	lines := strings.Split(details, "\n")
+	for i, line := range lines {
+		code := ""
+		switch {
+		case strings.HasPrefix(line, "+ "):
+			code = "32"
+		case strings.HasPrefix(line, "- "):
+			code = "31"
+		case strings.HasPrefix(line, "~ "):
+			code = "33"
+		}
+		if code != "" {
+			lines[i] = "\x1b[" + code + "m" + line + "\x1b[0m"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func useColor() bool {
+	switch *color {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	pb "github.com/layer5io/meshery-octarine/meshes"
+	flag "github.com/spf13/pflag"
+)
+
+// newFlagSet is the flag set of a command, printing its usage line on errors
+func newFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: meshery-octarine-ctl %s\n", usage)
+		fs.PrintDefaults()
+		fmt.Fprintln(os.Stderr, "Global flags:")
+		fmt.Fprint(os.Stderr, flag.CommandLine.FlagUsages())
+	}
+	return fs
+}
+
+// persistentArgs takes the flags of global out of args wherever they are, before or after the command, it
+// returns them apart from the command, its arguments and its own flags; nothing is taken after --
+func persistentArgs(global *flag.FlagSet, args []string) ([]string, []string) {
+	taken, rest := []string{}, []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "--") {
+			rest = append(rest, arg)
+			continue
+		}
+		name := strings.SplitN(strings.TrimPrefix(arg, "--"), "=", 2)[0]
+		f := global.Lookup(name)
+		if f == nil {
+			rest = append(rest, arg)
+			continue
+		}
+		taken = append(taken, arg)
+		// a flag taking a value has it in the next argument unless it is given as --name=value
+		if f.NoOptDefVal == "" && !strings.Contains(arg, "=") && i+1 < len(args) {
+			i++
+			taken = append(taken, args[i])
+		}
+	}
+	return taken, rest
+}
+
+// clusterFlag is the --cluster flag of the commands targeting a registered cluster, the default one when empty
+func clusterFlag(fs *flag.FlagSet, what string) *string {
+	return fs.String("cluster", "", fmt.Sprintf("The registered cluster %s, the default cluster when empty", what))
+}
+
+// userFlag is the --user flag of the commands recording who made a change, the local user by default
+func userFlag(fs *flag.FlagSet, help string) *string {
+	return fs.String("user", os.Getenv("USER"), help)
+}
+
+func newOperationID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// readBodyFile reads the custom body of an operation, - reads stdin and an empty path is an empty body
+func readBodyFile(path string) ([]byte, error) {
+	switch path {
+	case "":
+		return nil, nil
+	case "-":
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(path)
+}
+
+// parseParameters reads the --param flags, key=value each, the value of a list parameter separates its items
+// with commas
+func parseParameters(params []string) (map[string]string, error) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+	parsed := map[string]string{}
+	for _, param := range params {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("parameter %q is not key=value", param)
+		}
+		parsed[strings.TrimSpace(kv[0])] = kv[1]
+	}
+	return parsed, nil
+}
+
+// printEvents writes events to stdout until the stream ends, the deadline passing is not an error
+// parseSeverity reads a severity written with or without its SEVERITY_ prefix, in any case
+func parseSeverity(value string) (pb.Severity, error) {
+	if value == "" {
+		return pb.Severity_SEVERITY_UNSPECIFIED, nil
+	}
+	severity, ok := pb.Severity_value["SEVERITY_"+strings.TrimPrefix(strings.ToUpper(value), "SEVERITY_")]
+	if !ok {
+		return 0, fmt.Errorf("%s is not one of DEBUG, INFO, WARN, ERROR or CRITICAL", value)
+	}
+	return pb.Severity(severity), nil
+}
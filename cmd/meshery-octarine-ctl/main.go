@@ -0,0 +1,157 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command meshery-octarine-ctl talks to a running Octarine adapter over gRPC.
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	pb "github.com/layer5io/meshery-octarine/meshes"
+	flag "github.com/spf13/pflag"
+)
+
+type command struct {
+	usage string
+	run   func(c pb.MeshServiceClient, args []string) error
+}
+
+const (
+	initUsage        = "init [--kubeconfig <file> [--context <name>] | --server <url> [--ca-file <file>]] [--token-file <file>] [--cluster <name>]"
+	runUsage         = "run <op> [--namespace <ns>] [--cluster <name>] [--delete] [--body-file <file>] [--param <key=value>]... [--applied-operation-id <id>] [--force] [--report-denials] [--resume <operation-id>] [--conflict-policy warn|skip|force] [--follow <duration>]"
+	opsUsage         = "ops [--deployment <name>] [--cluster <name>] [--all]"
+	eventsUsage      = "events [--operation-id <id>] [--min-severity <DEBUG|INFO|WARN|ERROR|CRITICAL>]"
+	vetUsage         = "vet [--timeout <duration>] [--report [--deployment <name>]]"
+	proxiesUsage     = "proxies [--deployment <name>] [--namespace <ns>] [--outdated]"
+	enforcementUsage = "enforcement [--deployment <name>]"
+	violationsUsage  = "violations [--deployment <name>] [--namespace <ns>] [--window <duration>] [--bucket <duration>]"
+	ackUsage         = "ack <alert-id> --reason <text> [--deployment <name>] [--user <name>]"
+	muteUsage        = "mute <alert-id> --duration <duration> --reason <text> [--deployment <name>] [--user <name>]"
+	kubeconfigUsage  = "kubeconfig [--deployment <name>] [--user <name>] [--ttl <duration>] [--output <file>]"
+	scheduleUsage    = "schedule <name> <op> --cron <expression> [--namespace <ns>] [--delete] [--body-file <file>] [--user <name>]"
+	schedulesUsage   = "schedules [--filter <text>]"
+	unscheduleUsage  = "unschedule <name> [--user <name>]"
+	inventoryUsage   = "inventory [--namespace <ns>] [--operation-id <id>]"
+	identitiesUsage  = "identities --namespace <ns> [--deployment <name>]"
+	latencyUsage     = "latency --namespace <ns>"
+	resultUsage      = "result <operation-id> [--cluster <name>]"
+	activeUsage      = "active [--namespace <ns>]"
+	historyUsage     = "history [--since <time|duration>] [--until <time>] [--min-severity <severity>] [--namespace <ns>] [--operation-id <id>]"
+	telemetryUsage   = "telemetry"
+	imagesUsage      = "images [--version <version>] [--deployment <name>] [--image <image>]..."
+	trashUsage       = "trash [--deployment <name>] [--namespace <ns>] [--cluster <name>]"
+	restoreUsage     = "restore <id> [--cluster <name>] [--user <name>]"
+	licenseUsage     = "license [--deployment <name>] [--cluster <name>]"
+	renderUsage      = "render <op> [--namespace <ns>] [--cluster <name>] [--delete] [--body-file <file>] [--param <key=value>]... [--output <file>]"
+	footprintUsage   = "footprint [--deployment <name>] [--namespaces <ns,...>] [--manifest <file>] [--sidecar-cpu <qty>] [--sidecar-memory <qty>]"
+)
+
+var commands = map[string]command{
+	"init":        {initUsage, initCmd},
+	"ops":         {opsUsage, opsCmd},
+	"run":         {runUsage, runCmd},
+	"events":      {eventsUsage, eventsCmd},
+	"vet":         {vetUsage, vetCmd},
+	"cluster":     {"cluster", clusterCmd},
+	"proxies":     {proxiesUsage, proxiesCmd},
+	"enforcement": {enforcementUsage, enforcementCmd},
+	"violations":  {violationsUsage, violationsCmd},
+	"ack":         {ackUsage, ackCmd},
+	"mute":        {muteUsage, muteCmd},
+	"kubeconfig":  {kubeconfigUsage, kubeconfigCmd},
+	"schedule":    {scheduleUsage, scheduleCmd},
+	"schedules":   {schedulesUsage, schedulesCmd},
+	"unschedule":  {unscheduleUsage, unscheduleCmd},
+	"footprint":   {footprintUsage, footprintCmd},
+	"templates":   {"templates", templatesCmd},
+	"adapter":     {"adapter", adapterCmd},
+	"inventory":   {inventoryUsage, inventoryCmd},
+	"render":      {renderUsage, renderCmd},
+	"identities":  {identitiesUsage, identitiesCmd},
+	"latency":     {latencyUsage, latencyCmd},
+	"result":      {resultUsage, resultCmd},
+	"history":     {historyUsage, historyCmd},
+	"active":      {activeUsage, activeCmd},
+	"telemetry":   {telemetryUsage, telemetryCmd},
+	"images":      {imagesUsage, imagesCmd},
+	"trash":       {trashUsage, trashCmd},
+	"restore":     {restoreUsage, restoreCmd},
+	"license":     {licenseUsage, licenseCmd},
+}
+
+// the flags of flag.CommandLine are persistent, they are taken before or after the command as cobra takes its
+// persistent flags
+var (
+	address        = flag.String("addr", "localhost:10003", "The address of the adapter's gRPC server")
+	compress       = flag.Bool("gzip", true, "Compress the requests and responses")
+	maxMessageSize = flag.Int("max-message-size", 16<<20, "The largest gRPC message sent or accepted, in bytes")
+	lang           = flag.String("lang", "", "The languages the adapter answers in, as an Accept-Language list, e.g. es")
+	color          = flag.String("color", "auto", "Color the field changes events list: auto on a terminal without NO_COLOR, always or never")
+
+	useTLS        = flag.Bool("tls", false, "Connect over TLS, implied by the other --tls flags")
+	tlsCAFile     = flag.String("tls-ca-file", "", "The PEM certificates the server certificate is verified with, the system roots when empty")
+	tlsCertFile   = flag.String("tls-cert-file", "", "The PEM client certificate, along with --tls-key-file")
+	tlsKeyFile    = flag.String("tls-key-file", "", "The PEM key of the client certificate")
+	tlsServerName = flag.String("tls-server-name", "", "The name the server certificate is verified for, the host of --addr when empty")
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: meshery-octarine-ctl <command> [flags] [global flags]")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %s\n", commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr, "Global flags:")
+	fmt.Fprint(os.Stderr, flag.CommandLine.FlagUsages())
+}
+
+func main() {
+	flag.Usage = usage
+	global, args := persistentArgs(flag.CommandLine, os.Args[1:])
+	// flag.CommandLine exits on errors
+	_ = flag.CommandLine.Parse(global)
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+	if *color != "auto" && *color != "always" && *color != "never" {
+		fmt.Fprintf(os.Stderr, "--color must be auto, always or never, not %q\n", *color)
+		os.Exit(2)
+	}
+
+	conn, err := dial()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "did not connect: %v\n", err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	if err := cmd.run(pb.NewMeshServiceClient(conn), args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		conn.Close()
+		os.Exit(1)
+	}
+}
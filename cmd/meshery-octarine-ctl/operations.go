@@ -0,0 +1,389 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/layer5io/meshery-octarine/meshes"
+)
+
+func initCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("init", initUsage)
+	kubeconfig := fs.String("kubeconfig", "", "The kubeconfig file of the target cluster, in-cluster config when empty")
+	contextName := fs.String("context", "", "The kubeconfig context to use")
+	tokenFile := fs.String("token-file", "", "A file holding a bearer token to use instead of the credentials of the context")
+	server := fs.String("server", "", "The URL of the API server, to register the cluster with a ServiceAccount token instead of a kubeconfig")
+	caFile := fs.String("ca-file", "", "The PEM file of the certificates the API server given with --server is verified against")
+	cluster := fs.String("cluster", "", "Register the cluster under this name for operations to target, instead of as the default cluster")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var config []byte
+	if *kubeconfig != "" {
+		var err error
+		if config, err = ioutil.ReadFile(*kubeconfig); err != nil {
+			return err
+		}
+	}
+	token := ""
+	if *tokenFile != "" {
+		data, err := ioutil.ReadFile(*tokenFile)
+		if err != nil {
+			return err
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	var ca []byte
+	if *caFile != "" {
+		var err error
+		if ca, err = ioutil.ReadFile(*caFile); err != nil {
+			return err
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.CreateMeshInstance(ctx, &pb.CreateMeshInstanceRequest{
+		K8SConfig:            config,
+		ContextName:          *contextName,
+		Token:                token,
+		Server:               *server,
+		CertificateAuthority: ca,
+		Cluster:              *cluster,
+	})
+	if err != nil {
+		return fmt.Errorf("could not initialize client: %v", err)
+	}
+	fmt.Println("mesh instance created")
+	if access := resp.GetAccess(); access != nil {
+		fmt.Printf("kubernetes %s, kube-system readable: %t\n", access.GetServerVersion(), access.GetKubeSystemReadable())
+		for _, perm := range access.GetMissing() {
+			fmt.Printf("missing in %s: %s\n", access.GetNamespace(), perm)
+		}
+		for _, warning := range access.GetWarnings() {
+			fmt.Printf("warning: %s\n", warning)
+		}
+	}
+	return nil
+}
+
+func opsCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("ops", opsUsage)
+	deployment := fs.String("deployment", "", "Check the operations against the release and licensed features of this deployment, the default one when empty")
+	cluster := clusterFlag(fs, "of the deployment")
+	all := fs.Bool("all", false, "Also list the operations the deployment doesn't support, with the reason")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ops := []*pb.SupportedOperation{}
+	version := ""
+	for req := (&pb.SupportedOperationsRequest{Deployment: *deployment, Cluster: *cluster, IncludeUnavailable: *all}); ; {
+		resp, err := c.SupportedOperations(ctx, req)
+		if err != nil {
+			return fmt.Errorf("could not list operations: %v", err)
+		}
+		if resp.GetError() != "" {
+			return fmt.Errorf("could not list operations: %s", resp.GetError())
+		}
+		ops = append(ops, resp.GetOps()...)
+		version = resp.GetInstalledVersion()
+		if req.PageToken = resp.GetNextPageToken(); req.PageToken == "" {
+			break
+		}
+	}
+	if version != "" {
+		fmt.Printf("installed Octarine %s\n", version)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tCATEGORY\tDESCRIPTION\tUNAVAILABLE")
+	for _, op := range ops {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", op.GetKey(), op.GetCategory(), op.GetValue(), op.GetUnavailable())
+	}
+	return w.Flush()
+}
+
+func runCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("run", runUsage)
+	namespace := fs.String("namespace", "", "The namespace to run the operation in")
+	deleteOp := fs.Bool("delete", false, "Undo the operation instead of applying it")
+	bodyFile := fs.String("body-file", "", "A file with the custom body of the operation, - for stdin")
+	params := fs.StringArray("param", nil, "A parameter of the operation as key=value, overriding the same field of the custom body")
+	username := fs.String("username", "", "The user the operation is run on behalf of")
+	cluster := clusterFlag(fs, "to run the operation in")
+	appliedOpID := fs.String("applied-operation-id", "", "With --delete and no body, delete what the custom operation of this id applied")
+	force := fs.Bool("force", false, "Let a custom operation go over the limits of deleted resources and namespaces")
+	reportDenials := fs.Bool("report-denials", false, "Skip and report the objects the admission webhooks deny instead of failing the operation")
+	resume := fs.String("resume", "", "Retry the failed operation of this id, skipping the manifest documents it applied")
+	conflictPolicy := fs.String("conflict-policy", "", "What to do with the resources whose fields other managers own: warn (the default), skip or force")
+	follow := fs.Duration("follow", 0, "Tail the events of the operation for this long")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("exactly one operation name is required")
+	}
+
+	body, err := readBodyFile(*bodyFile)
+	if err != nil {
+		return err
+	}
+	parameters, err := parseParameters(*params)
+	if err != nil {
+		return err
+	}
+
+	req := &pb.ApplyRuleRequest{
+		OperationId: newOperationID(),
+		OpName:      fs.Arg(0),
+		Namespace:   *namespace,
+		Username:    *username,
+		CustomBody:  string(body),
+		Parameters:  parameters,
+		DeleteOp:    *deleteOp,
+		Cluster:     *cluster,
+
+		AppliedOperationId: *appliedOpID,
+		Force:              *force,
+		ReportDenials:      *reportDenials,
+		ResumeOperationId:  *resume,
+		ConflictPolicy:     *conflictPolicy,
+	}
+	if *follow > 0 {
+		// subscribe before applying so no event of the operation is missed
+		ctx, cancel := context.WithTimeout(context.Background(), *follow)
+		defer cancel()
+		stream, err := c.StreamEvents(ctx, &pb.EventsRequest{})
+		if err != nil {
+			return fmt.Errorf("could not stream events: %v", err)
+		}
+		if err := applyOperation(c, req); err != nil {
+			return err
+		}
+		return printEvents(stream, req.GetOperationId())
+	}
+	return applyOperation(c, req)
+}
+
+func renderCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("render", renderUsage)
+	namespace := fs.String("namespace", "", "The namespace the operation would run in")
+	deleteOp := fs.Bool("delete", false, "Render what undoing the operation would delete")
+	bodyFile := fs.String("body-file", "", "A file with the custom body of the operation, - for stdin")
+	params := fs.StringArray("param", nil, "A parameter of the operation as key=value, overriding the same field of the custom body")
+	username := fs.String("username", "", "The user the operation would run on behalf of")
+	cluster := clusterFlag(fs, "to render the operation for")
+	output := fs.String("output", "", "Write the manifest to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("exactly one operation name is required")
+	}
+	body, err := readBodyFile(*bodyFile)
+	if err != nil {
+		return err
+	}
+	parameters, err := parseParameters(*params)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.RenderOperation(ctx, &pb.RenderOperationRequest{
+		OpName:     fs.Arg(0),
+		Namespace:  *namespace,
+		Username:   *username,
+		CustomBody: string(body),
+		Parameters: parameters,
+		DeleteOp:   *deleteOp,
+		Cluster:    *cluster,
+	})
+	if err != nil {
+		return fmt.Errorf("could not render %s: %v", fs.Arg(0), err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not render %s: %s", fs.Arg(0), resp.GetError())
+	}
+	if *output == "" {
+		fmt.Print(resp.GetManifest())
+		return nil
+	}
+	if err := ioutil.WriteFile(*output, []byte(resp.GetManifest()), 0644); err != nil {
+		return fmt.Errorf("could not write %s: %v", *output, err)
+	}
+	fmt.Printf("%d object(s) written to %s\n", len(resp.GetObjects()), *output)
+	return nil
+}
+
+func applyOperation(c pb.MeshServiceClient, req *pb.ApplyRuleRequest) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.ApplyOperation(ctx, req)
+	if err != nil {
+		return fmt.Errorf("could not apply %s: %v", req.GetOpName(), err)
+	}
+	for _, warning := range resp.GetWarnings() {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+	fmt.Printf("operation %s submitted with id %s\n", req.GetOpName(), req.GetOperationId())
+	return nil
+}
+
+func resultCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("result", resultUsage)
+	cluster := clusterFlag(fs, "the operation ran in")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("exactly one operation id is required")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.GetOperationResult(ctx, &pb.GetOperationResultRequest{OperationId: fs.Arg(0), Cluster: *cluster})
+	if err != nil {
+		return fmt.Errorf("could not get the result of operation %s: %v", fs.Arg(0), err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not get the result of operation %s: %s", fs.Arg(0), resp.GetError())
+	}
+	fmt.Printf("operation %s: %s %s\n", resp.GetOperationId(), resp.GetOpName(), resp.GetStatus())
+	fmt.Printf("started %s, took %s, %d warning(s)\n", resp.GetStarted(), resp.GetDuration(), resp.GetWarnings())
+	if resp.GetResumedFrom() != "" {
+		fmt.Printf("resumed operation %s\n", resp.GetResumedFrom())
+	}
+	if resp.GetAppliedDocuments() > 0 {
+		fmt.Printf("%d manifest document(s) applied\n", resp.GetAppliedDocuments())
+	}
+	for _, r := range resp.GetResources() {
+		fmt.Printf("    %s\n", r)
+	}
+	for _, e := range resp.GetErrors() {
+		fmt.Printf("error: %s\n", strings.Replace(e, "\n", "\n    ", -1))
+	}
+	return nil
+}
+
+func activeCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("active", activeUsage)
+	namespace := fs.String("namespace", "", "Only list the operations of this namespace")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.ListActiveOperations(ctx, &pb.ListActiveOperationsRequest{Namespace: *namespace})
+	if err != nil {
+		return fmt.Errorf("could not list the active operations: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not list the active operations: %s", resp.GetError())
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "OPERATION\tID\tCLUSTER\tNAMESPACE\tRUNNING\tIDLE\tPHASE")
+	for _, op := range resp.GetOperations() {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", op.GetOpName(), op.GetOperationId(), op.GetCluster(), op.GetNamespace(), op.GetRunningFor(), op.GetIdleFor(), op.GetPhase())
+	}
+	return w.Flush()
+}
+
+func templatesCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("templates", "templates")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.LintTemplates(ctx, &pb.LintTemplatesRequest{})
+	if err != nil {
+		return fmt.Errorf("could not lint the templates: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not lint the templates: %s", resp.GetError())
+	}
+	if resp.GetCatalogVersion() != "" {
+		fmt.Printf("Template catalog %s, synced at %s\n", resp.GetCatalogVersion(), resp.GetCatalogSyncedAt())
+	}
+	if resp.GetCatalogError() != "" {
+		fmt.Printf("Last catalog sync failed: %s\n", resp.GetCatalogError())
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "OPERATION\tTEMPLATE\tSET\tOBJECTS\tDISABLED\tERROR")
+	broken := 0
+	for _, t := range resp.GetTemplates() {
+		if t.GetError() != "" {
+			broken++
+		}
+		set := t.GetTemplateSet()
+		if set == "" {
+			set = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%t\t%s\n", t.GetOperation(), t.GetTemplate(), set, t.GetObjects(), t.GetDisabled(), t.GetError())
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if broken > 0 {
+		return fmt.Errorf("%d template(s) are broken", broken)
+	}
+	return nil
+}
+
+func vetCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("vet", vetUsage)
+	timeout := fs.Duration("timeout", 2*time.Minute, "How long to collect vet results for")
+	report := fs.Bool("report", false, "Print the checks of the last vet instead of running one")
+	deployment := fs.String("deployment", "", "The deployment whose last vet is printed")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !*report {
+		return runCmd(c, []string{"octarine_vet", "--follow", timeout.String()})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.VetReport(ctx, &pb.VetReportRequest{Deployment: *deployment})
+	if err != nil {
+		return fmt.Errorf("could not get the vet report: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not get the vet report: %s", resp.GetError())
+	}
+	fmt.Printf("deployment %s vetted at %s, %d of %d checks failed\n", resp.GetDeployment(), resp.GetVettedAt(), resp.GetFailed(), len(resp.GetChecks()))
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tRESULT\tSEVERITY\tADVISORY\tDETAILS")
+	for _, check := range resp.GetChecks() {
+		result, details := "pass", ""
+		switch {
+		case check.GetFailure() != "":
+			result, details = "FAIL", check.GetFailure()
+		case check.GetSkipped() != "":
+			result, details = "skipped", check.GetSkipped()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", check.GetName(), result, check.GetSeverity(), check.GetAdvisory(), details)
+	}
+	return w.Flush()
+}
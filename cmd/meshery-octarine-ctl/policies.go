@@ -0,0 +1,202 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/layer5io/meshery-octarine/meshes"
+)
+
+func enforcementCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("enforcement", enforcementUsage)
+	deployment := fs.String("deployment", "", "The deployment whose enforcement mode is shown")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.EnforcementStatus(ctx, &pb.EnforcementStatusRequest{Deployment: *deployment})
+	if err != nil {
+		return fmt.Errorf("could not get the enforcement status: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not get the enforcement status: %s", resp.GetError())
+	}
+	fmt.Printf("global mode %s\n", resp.GetGlobalMode())
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tMODE\tINHERITED")
+	for _, ns := range resp.GetNamespaces() {
+		fmt.Fprintf(w, "%s\t%s\t%t\n", ns.GetNamespace(), ns.GetMode(), ns.GetInherited())
+	}
+	return w.Flush()
+}
+
+func violationsCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("violations", violationsUsage)
+	deployment := fs.String("deployment", "", "The deployment whose violations are counted")
+	namespace := fs.String("namespace", "", "Only count the violations of this namespace")
+	window := fs.String("window", "", "How far back violations are counted (default 24h)")
+	bucket := fs.String("bucket", "", "The width of the trend buckets (default 1h)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.PolicyViolations(ctx, &pb.PolicyViolationsRequest{
+		Deployment: *deployment,
+		Namespace:  *namespace,
+		Window:     *window,
+		Bucket:     *bucket,
+	})
+	if err != nil {
+		return fmt.Errorf("could not summarize violations: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not summarize violations: %s", resp.GetError())
+	}
+	fmt.Printf("%d violations from %s to %s\n", resp.GetTotal(), resp.GetSince(), resp.GetUntil())
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "POLICY\tVIOLATIONS")
+	for _, p := range resp.GetPolicies() {
+		fmt.Fprintf(w, "%s\t%d\n", p.GetPolicy(), p.GetCount())
+	}
+	fmt.Fprintln(w, "\nNAMESPACE\tWORKLOAD\tVIOLATIONS")
+	for _, wl := range resp.GetWorkloads() {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", wl.GetNamespace(), wl.GetWorkload(), wl.GetCount())
+	}
+	return w.Flush()
+}
+
+func ackCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("ack", ackUsage)
+	deployment := fs.String("deployment", "", "The deployment the alert was raised for")
+	reason := fs.String("reason", "", "Why the alert is acknowledged")
+	user := userFlag(fs, "Who the acknowledgment is recorded for")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("an alert id is required")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.AcknowledgeAlert(ctx, &pb.AcknowledgeAlertRequest{
+		Deployment: *deployment,
+		AlertId:    fs.Arg(0),
+		Reason:     *reason,
+		Username:   *user,
+	})
+	if err != nil {
+		return fmt.Errorf("could not acknowledge alert: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not acknowledge alert: %s", resp.GetError())
+	}
+	fmt.Printf("alert %s acknowledged\n", fs.Arg(0))
+	return nil
+}
+
+func muteCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("mute", muteUsage)
+	deployment := fs.String("deployment", "", "The deployment the alert was raised for")
+	duration := fs.String("duration", "", "How long the alert stays muted, e.g. 8h")
+	reason := fs.String("reason", "", "Why the alert is muted")
+	user := userFlag(fs, "Who the mute is recorded for")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("an alert id is required")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.MuteAlert(ctx, &pb.MuteAlertRequest{
+		Deployment: *deployment,
+		AlertId:    fs.Arg(0),
+		Duration:   *duration,
+		Reason:     *reason,
+		Username:   *user,
+	})
+	if err != nil {
+		return fmt.Errorf("could not mute alert: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not mute alert: %s", resp.GetError())
+	}
+	fmt.Printf("alert %s muted until %s\n", fs.Arg(0), resp.GetMutedUntil())
+	return nil
+}
+
+func trashCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("trash", trashUsage)
+	deployment := fs.String("deployment", "", "Only list the policies deleted from this deployment")
+	namespace := fs.String("namespace", "", "Only list the policies deleted from this namespace")
+	cluster := clusterFlag(fs, "the policies were deleted in")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID	DEPLOYMENT	NAMESPACE	KIND	NAME	DELETED	BY	EXPIRES")
+	req := &pb.ListTrashedPoliciesRequest{Deployment: *deployment, Namespace: *namespace, Cluster: *cluster}
+	for {
+		resp, err := c.ListTrashedPolicies(ctx, req)
+		if err != nil {
+			return fmt.Errorf("could not list the trashed policies: %v", err)
+		}
+		if resp.GetError() != "" {
+			return fmt.Errorf("could not list the trashed policies: %s", resp.GetError())
+		}
+		for _, p := range resp.GetPolicies() {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", p.GetId(), p.GetDeployment(), p.GetNamespace(), p.GetKind(), p.GetName(), p.GetDeleted(), p.GetDeletedBy(), p.GetExpires())
+		}
+		if req.PageToken = resp.GetNextPageToken(); req.PageToken == "" {
+			return w.Flush()
+		}
+	}
+}
+
+func restoreCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("restore", restoreUsage)
+	cluster := clusterFlag(fs, "the policy was deleted in")
+	user := userFlag(fs, "Who the restore is recorded for")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("exactly one trashed policy id is required")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.RestorePolicy(ctx, &pb.RestorePolicyRequest{Id: fs.Arg(0), Cluster: *cluster, Username: *user})
+	if err != nil {
+		return fmt.Errorf("could not restore policy %s: %v", fs.Arg(0), err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not restore policy %s: %s", fs.Arg(0), resp.GetError())
+	}
+	p := resp.GetPolicy()
+	fmt.Printf("policy %s restored to deployment %s\n", p.GetName(), p.GetDeployment())
+	return nil
+}
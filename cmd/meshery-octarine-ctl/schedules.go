@@ -0,0 +1,119 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/layer5io/meshery-octarine/meshes"
+)
+
+func scheduleCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("schedule", scheduleUsage)
+	cron := fs.String("cron", "", "When the operation runs, a cron expression in UTC like \"0 2 * * *\" or @daily")
+	namespace := fs.String("namespace", "", "The namespace to run the operation in")
+	deleteOp := fs.Bool("delete", false, "Undo the operation instead of applying it")
+	bodyFile := fs.String("body-file", "", "A file with the custom body of the operation, - for stdin")
+	user := userFlag(fs, "Who the schedule is recorded for")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("a schedule name and an operation name are required")
+	}
+	body, err := readBodyFile(*bodyFile)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.ScheduleOperation(ctx, &pb.ScheduleOperationRequest{
+		Name: fs.Arg(0),
+		Cron: *cron,
+		Operation: &pb.ApplyRuleRequest{
+			OpName:     fs.Arg(1),
+			Namespace:  *namespace,
+			Username:   *user,
+			CustomBody: string(body),
+			DeleteOp:   *deleteOp,
+		},
+		Username: *user,
+	})
+	if err != nil {
+		return fmt.Errorf("could not schedule operation: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not schedule operation: %s", resp.GetError())
+	}
+	fmt.Printf("schedule %s registered, first run at %s\n", fs.Arg(0), resp.GetNextRun())
+	return nil
+}
+
+func schedulesCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("schedules", schedulesUsage)
+	filter := fs.String("filter", "", "Only list the schedules whose name or operation contain it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCRON\tOPERATION\tNEXT RUN\tLAST RUN\tLAST OPERATION ID\tLAST ERROR")
+	for req := (&pb.ListSchedulesRequest{Filter: *filter}); ; {
+		resp, err := c.ListSchedules(ctx, req)
+		if err != nil {
+			return fmt.Errorf("could not list schedules: %v", err)
+		}
+		if resp.GetError() != "" {
+			return fmt.Errorf("could not list schedules: %s", resp.GetError())
+		}
+		for _, s := range resp.GetSchedules() {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", s.GetName(), s.GetCron(), s.GetOperation().GetOpName(),
+				s.GetNextRun(), s.GetLastRun(), s.GetLastOperationId(), s.GetLastError())
+		}
+		if req.PageToken = resp.GetNextPageToken(); req.PageToken == "" {
+			break
+		}
+	}
+	return w.Flush()
+}
+
+func unscheduleCmd(c pb.MeshServiceClient, args []string) error {
+	fs := newFlagSet("unschedule", unscheduleUsage)
+	user := userFlag(fs, "Who the deletion is recorded for")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("a schedule name is required")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := c.DeleteSchedule(ctx, &pb.DeleteScheduleRequest{Name: fs.Arg(0), Username: *user})
+	if err != nil {
+		return fmt.Errorf("could not delete schedule: %v", err)
+	}
+	if resp.GetError() != "" {
+		return fmt.Errorf("could not delete schedule: %s", resp.GetError())
+	}
+	fmt.Printf("schedule %s deleted\n", fs.Arg(0))
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build devcontrolplane
+// +build devcontrolplane
+
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+
+	mesh "github.com/layer5io/meshery-octarine/meshes"
+	"github.com/layer5io/meshery-octarine/octarine"
+	"github.com/layer5io/meshery-octarine/octarinetest"
+)
+
+// the test fixtures of octarinetest stay out of the adapter image, they are only built with the devcontrolplane tag
+var devControlPlane = flag.Bool("dev-control-plane", false, "Run the operations against a local API server started from the binaries of KUBEBUILDER_ASSETS, no workloads run")
+
+func init() {
+	startDevControlPlane = func(oClient *octarine.Client) func() {
+		if !*devControlPlane {
+			return func() {}
+		}
+		return runDevControlPlane(oClient)
+	}
+}
+
+// runDevControlPlane connects the client to a local control plane for contributors to iterate on templates
+// and operations with the semantics of a real API server, and stops it when the adapter is interrupted
+func runDevControlPlane(oClient *octarine.Client) func() {
+	cp, err := octarinetest.StartControlPlane("")
+	if err != nil {
+		logrus.Fatalln("Failed to start the development control plane:", err)
+	}
+	oClient.SimulateWorkloads()
+	if _, err := oClient.CreateMeshInstance(context.Background(), &mesh.CreateMeshInstanceRequest{K8SConfig: cp.Kubeconfig()}); err != nil {
+		cp.Stop()
+		logrus.Fatalln("Failed to connect to the development control plane:", err)
+	}
+	logrus.Infof("Running operations against the development control plane, its kubeconfig is %s", cp.KubeconfigPath())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		cp.Stop()
+		os.Exit(1)
+	}()
+	return cp.Stop
+}
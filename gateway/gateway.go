@@ -0,0 +1,867 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gateway exposes the adapter's gRPC API over plain HTTP with JSON bodies.
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Gateway translates HTTP requests into calls on a MeshServiceServer
+type Gateway struct {
+	server   meshes.MeshServiceServer
+	mux      *http.ServeMux
+	validate func(req interface{}) error
+}
+
+// New creates a gateway in front of the given server
+func New(server meshes.MeshServiceServer) *Gateway {
+	g := &Gateway{
+		server: server,
+		mux:    http.NewServeMux(),
+	}
+	g.mux.HandleFunc("/api/v1/mesh-instance", g.handleCreateMeshInstance)
+	g.mux.HandleFunc("/api/v1/mesh-name", g.handleMeshName)
+	g.mux.HandleFunc("/api/v1/operations", g.handleOperations)
+	g.mux.HandleFunc("/api/v1/events", g.handleEvents)
+	g.mux.HandleFunc("/api/v1/cluster-capabilities", g.handleClusterCapabilities)
+	g.mux.HandleFunc("/api/v1/proxy-versions", g.handleProxyVersions)
+	g.mux.HandleFunc("/api/v1/enforcement", g.handleEnforcementStatus)
+	g.mux.HandleFunc("/api/v1/violations", g.handlePolicyViolations)
+	g.mux.HandleFunc("/api/v1/alerts/acknowledge", g.handleAcknowledgeAlert)
+	g.mux.HandleFunc("/api/v1/alerts/mute", g.handleMuteAlert)
+	g.mux.HandleFunc("/api/v1/kubeconfig", g.handleExportKubeconfig)
+	g.mux.HandleFunc("/api/v1/schedules", g.handleSchedules)
+	g.mux.HandleFunc("/api/v1/footprint", g.handleEstimateFootprint)
+	g.mux.HandleFunc("/api/v1/templates/lint", g.handleLintTemplates)
+	g.mux.HandleFunc("/api/v1/adapter-capabilities", g.handleAdapterCapabilities)
+	g.mux.HandleFunc("/api/v1/inventory", g.handleInventory)
+	g.mux.HandleFunc("/api/v1/render", g.handleRenderOperation)
+	g.mux.HandleFunc("/api/v1/workload-identities", g.handleWorkloadIdentities)
+	g.mux.HandleFunc("/api/v1/vet", g.handleVetReport)
+	g.mux.HandleFunc("/api/v1/latency", g.handleLatencyProbeReport)
+	g.mux.HandleFunc("/api/v1/operations/result", g.handleGetOperationResult)
+	g.mux.HandleFunc("/api/v1/events/query", g.handleQueryEvents)
+	g.mux.HandleFunc("/api/v1/operations/active", g.handleListActiveOperations)
+	g.mux.HandleFunc("/api/v1/telemetry/preview", g.handlePreviewTelemetry)
+	g.mux.HandleFunc("/api/v1/images", g.handleImageManifests)
+	g.mux.HandleFunc("/api/v1/policies/trash", g.handleListTrashedPolicies)
+	g.mux.HandleFunc("/api/v1/policies/restore", g.handleRestorePolicy)
+	g.mux.HandleFunc("/api/v1/license", g.handleLicenseStatus)
+	return g
+}
+
+// Handle registers an additional handler on the gateway's mux
+func (g *Gateway) Handle(pattern string, handler http.Handler) {
+	g.mux.Handle(pattern, handler)
+}
+
+// Validate makes the gateway check every request with fn before calling the server, the way an
+// interceptor does for gRPC calls
+func (g *Gateway) Validate(fn func(req interface{}) error) {
+	g.validate = fn
+}
+
+// ServeHTTP implements http.Handler
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+var marshaler = &jsonpb.Marshaler{EmitDefaults: true}
+
+func writeMessage(w http.ResponseWriter, msg proto.Message) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := marshaler.Marshal(w, msg); err != nil {
+		logrus.Error(errors.Wrapf(err, "unable to marshal response"))
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+			code = http.StatusBadRequest
+		case codes.NotFound:
+			code = http.StatusNotFound
+		case codes.AlreadyExists, codes.Aborted:
+			code = http.StatusConflict
+		case codes.PermissionDenied:
+			code = http.StatusForbidden
+		case codes.Unauthenticated:
+			code = http.StatusUnauthorized
+		case codes.ResourceExhausted:
+			code = http.StatusTooManyRequests
+		case codes.Unavailable:
+			code = http.StatusServiceUnavailable
+		case codes.DeadlineExceeded:
+			code = http.StatusGatewayTimeout
+		case codes.Unimplemented:
+			code = http.StatusNotImplemented
+		}
+	}
+	http.Error(w, err.Error(), code)
+}
+
+// pageSize reads the page_size of a list request from its query
+func pageSize(q url.Values) (int32, error) {
+	value := q.Get("page_size")
+	if value == "" {
+		return 0, nil
+	}
+	size, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "page_size %q is not a number", value)
+	}
+	return int32(size), nil
+}
+
+// minSeverity reads the min_severity parameter, written with or without its SEVERITY_ prefix
+func minSeverity(q url.Values) (meshes.Severity, error) {
+	value := strings.ToUpper(q.Get("min_severity"))
+	if value == "" {
+		return meshes.Severity_SEVERITY_UNSPECIFIED, nil
+	}
+	if !strings.HasPrefix(value, "SEVERITY_") {
+		value = "SEVERITY_" + value
+	}
+	severity, ok := meshes.Severity_value[value]
+	if !ok {
+		return 0, status.Errorf(codes.InvalidArgument, "min_severity %q is not one of DEBUG, INFO, WARN, ERROR or CRITICAL", q.Get("min_severity"))
+	}
+	return meshes.Severity(severity), nil
+}
+
+// readMessage parses the JSON body of a request into msg and validates it
+func (g *Gateway) readMessage(r *http.Request, msg proto.Message) error {
+	if err := decodeMessage(r, msg); err != nil {
+		return err
+	}
+	return g.check(msg)
+}
+
+// decodeMessage parses the JSON body of a request into msg
+func decodeMessage(r *http.Request, msg proto.Message) error {
+	if r.ContentLength != 0 {
+		if err := jsonpb.Unmarshal(r.Body, msg); err != nil {
+			return status.Errorf(codes.InvalidArgument, "unable to parse request body: %v", err)
+		}
+	}
+	return nil
+}
+
+// check validates a request built from the query of a GET request
+func (g *Gateway) check(msg proto.Message) error {
+	if g.validate == nil {
+		return nil
+	}
+	return g.validate(msg)
+}
+
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if r.Method == m {
+			return true
+		}
+	}
+	w.Header().Set("Allow", fmt.Sprint(methods))
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	return false
+}
+
+func (g *Gateway) handleCreateMeshInstance(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	req := &meshes.CreateMeshInstanceRequest{}
+	if err := g.readMessage(r, req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.CreateMeshInstance(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleMeshName(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	resp, err := g.server.MeshName(callContext(r), &meshes.MeshNameRequest{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleClusterCapabilities(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	resp, err := g.server.ClusterCapabilities(callContext(r), &meshes.ClusterCapabilitiesRequest{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleProxyVersions(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	q := r.URL.Query()
+	size, err := pageSize(q)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	req := &meshes.ProxyVersionsRequest{
+		Deployment:   q.Get("deployment"),
+		PageSize:     size,
+		PageToken:    q.Get("page_token"),
+		Namespace:    q.Get("namespace"),
+		OutdatedOnly: q.Get("outdated_only") == "true",
+	}
+	if err := g.check(req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.ProxyVersions(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleEnforcementStatus(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	req := &meshes.EnforcementStatusRequest{Deployment: r.URL.Query().Get("deployment")}
+	if err := g.check(req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.EnforcementStatus(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handlePolicyViolations(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	q := r.URL.Query()
+	req := &meshes.PolicyViolationsRequest{
+		Deployment: q.Get("deployment"),
+		Namespace:  q.Get("namespace"),
+		Window:     q.Get("window"),
+		Bucket:     q.Get("bucket"),
+	}
+	if err := g.check(req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.PolicyViolations(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleAcknowledgeAlert(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	req := &meshes.AcknowledgeAlertRequest{}
+	if err := g.readMessage(r, req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.AcknowledgeAlert(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleMuteAlert(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	req := &meshes.MuteAlertRequest{}
+	if err := g.readMessage(r, req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.MuteAlert(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleExportKubeconfig(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	req := &meshes.ExportKubeconfigRequest{}
+	if err := g.readMessage(r, req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.ExportKubeconfig(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+// handleSchedules lists the schedules on GET, registers one on POST and deletes the one named by the query on DELETE
+func (g *Gateway) handleSchedules(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost, http.MethodDelete) {
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		size, err := pageSize(q)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		req := &meshes.ListSchedulesRequest{PageSize: size, PageToken: q.Get("page_token"), Filter: q.Get("filter")}
+		if err := g.check(req); err != nil {
+			writeError(w, err)
+			return
+		}
+		resp, err := g.server.ListSchedules(callContext(r), req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeMessage(w, resp)
+	case http.MethodDelete:
+		q := r.URL.Query()
+		req := &meshes.DeleteScheduleRequest{Name: q.Get("name"), Username: q.Get("username")}
+		if err := g.check(req); err != nil {
+			writeError(w, err)
+			return
+		}
+		resp, err := g.server.DeleteSchedule(callContext(r), req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeMessage(w, resp)
+	default:
+		req := &meshes.ScheduleOperationRequest{}
+		if err := g.readMessage(r, req); err != nil {
+			writeError(w, err)
+			return
+		}
+		resp, err := g.server.ScheduleOperation(callContext(r), req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeMessage(w, resp)
+	}
+}
+
+func (g *Gateway) handleEstimateFootprint(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	q := r.URL.Query()
+	req := &meshes.EstimateFootprintRequest{
+		Deployment:    q.Get("deployment"),
+		Namespaces:    q["namespace"],
+		SidecarCpu:    q.Get("sidecar_cpu"),
+		SidecarMemory: q.Get("sidecar_memory"),
+	}
+	if err := g.check(req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.EstimateFootprint(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleLintTemplates(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	resp, err := g.server.LintTemplates(callContext(r), &meshes.LintTemplatesRequest{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleAdapterCapabilities(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	resp, err := g.server.AdapterCapabilities(callContext(r), &meshes.AdapterCapabilitiesRequest{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleInventory(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	q := r.URL.Query()
+	size, err := pageSize(q)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	req := &meshes.InventoryRequest{
+		Namespace:   q.Get("namespace"),
+		OperationId: q.Get("operation_id"),
+		PageSize:    size,
+		PageToken:   q.Get("page_token"),
+	}
+	if err := g.check(req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.Inventory(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleRenderOperation(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	req := &meshes.RenderOperationRequest{}
+	if err := g.readMessage(r, req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.RenderOperation(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleWorkloadIdentities(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	q := r.URL.Query()
+	size, err := pageSize(q)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	req := &meshes.WorkloadIdentitiesRequest{
+		Namespace:  q.Get("namespace"),
+		Deployment: q.Get("deployment"),
+		PageSize:   size,
+		PageToken:  q.Get("page_token"),
+	}
+	if err := g.check(req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.WorkloadIdentities(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleVetReport(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	req := &meshes.VetReportRequest{Deployment: r.URL.Query().Get("deployment")}
+	if err := g.check(req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.VetReport(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleLatencyProbeReport(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	req := &meshes.LatencyProbeReportRequest{Namespace: r.URL.Query().Get("namespace")}
+	if err := g.check(req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.LatencyProbeReport(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleGetOperationResult(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	q := r.URL.Query()
+	req := &meshes.GetOperationResultRequest{OperationId: q.Get("operation_id"), Cluster: q.Get("cluster")}
+	if err := g.check(req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.GetOperationResult(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleQueryEvents(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	q := r.URL.Query()
+	size, err := pageSize(q)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	severity, err := minSeverity(q)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	req := &meshes.QueryEventsRequest{
+		Since:       q.Get("since"),
+		Until:       q.Get("until"),
+		MinSeverity: severity,
+		Namespace:   q.Get("namespace"),
+		OperationId: q.Get("operation_id"),
+		PageSize:    size,
+		PageToken:   q.Get("page_token"),
+	}
+	if err := g.check(req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.QueryEvents(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleListActiveOperations(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	req := &meshes.ListActiveOperationsRequest{Namespace: r.URL.Query().Get("namespace")}
+	if err := g.check(req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.ListActiveOperations(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handlePreviewTelemetry(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	resp, err := g.server.PreviewTelemetry(callContext(r), &meshes.PreviewTelemetryRequest{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleImageManifests(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	q := r.URL.Query()
+	req := &meshes.ImageManifestsRequest{
+		Version:    q.Get("version"),
+		Deployment: q.Get("deployment"),
+		Images:     q["image"],
+	}
+	if err := g.check(req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.ImageManifests(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleListTrashedPolicies(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	q := r.URL.Query()
+	size, err := pageSize(q)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	req := &meshes.ListTrashedPoliciesRequest{
+		Deployment: q.Get("deployment"),
+		Namespace:  q.Get("namespace"),
+		Cluster:    q.Get("cluster"),
+		PageSize:   size,
+		PageToken:  q.Get("page_token"),
+	}
+	if err := g.check(req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.ListTrashedPolicies(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleRestorePolicy(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	req := &meshes.RestorePolicyRequest{}
+	if err := g.readMessage(r, req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.RestorePolicy(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+func (g *Gateway) handleLicenseStatus(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	q := r.URL.Query()
+	req := &meshes.LicenseStatusRequest{Deployment: q.Get("deployment"), Cluster: q.Get("cluster")}
+	if err := g.check(req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.LicenseStatus(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+// callContext passes the Accept-Language header of a request on to the server, as the metadata a gRPC client
+// would send, so the server answers in the language of the caller
+func callContext(r *http.Request) context.Context {
+	if lang := r.Header.Get("Accept-Language"); lang != "" {
+		return metadata.NewIncomingContext(r.Context(), metadata.Pairs("accept-language", lang))
+	}
+	return r.Context()
+}
+
+// operationContext is the context of an operation applied over HTTP, which runs in the background after the
+// response was sent: it outlives the request but carries its Accept-Language as callContext does
+func operationContext(r *http.Request) context.Context {
+	return callContext(r.WithContext(context.Background()))
+}
+
+// handleOperations lists the supported operations on GET and applies one on POST
+func (g *Gateway) handleOperations(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		size, err := pageSize(q)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		req := &meshes.SupportedOperationsRequest{
+			PageSize:           size,
+			PageToken:          q.Get("page_token"),
+			Filter:             q.Get("filter"),
+			Deployment:         q.Get("deployment"),
+			Cluster:            q.Get("cluster"),
+			IncludeUnavailable: q.Get("include_unavailable") == "true",
+		}
+		for _, name := range q["category"] {
+			category, ok := meshes.OpCategory_value[name]
+			if !ok {
+				writeError(w, status.Errorf(codes.InvalidArgument, "unknown operation category %q", name))
+				return
+			}
+			req.Categories = append(req.Categories, meshes.OpCategory(category))
+		}
+		if err := g.check(req); err != nil {
+			writeError(w, err)
+			return
+		}
+		resp, err := g.server.SupportedOperations(callContext(r), req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeMessage(w, resp)
+		return
+	}
+	req := &meshes.ApplyRuleRequest{}
+	if err := decodeMessage(r, req); err != nil {
+		writeError(w, err)
+		return
+	}
+	// the gRPC interceptors don't run for the gateway, a malformed operation must fail before it starts
+	if err := g.check(req); err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := g.server.ApplyOperation(operationContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, resp)
+}
+
+// handleEvents streams events as server-sent events until the client goes away
+func (g *Gateway) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	q := r.URL.Query()
+	severity, err := minSeverity(q)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	req := &meshes.EventsRequest{MinSeverity: severity, OperationId: q.Get("operation_id")}
+	if err := g.check(req); err != nil {
+		writeError(w, err)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	stream := &sseStream{ctx: callContext(r), w: w, flusher: flusher}
+	if err := g.server.StreamEvents(req, stream); err != nil {
+		logrus.Debugf("event stream closed: %v", err)
+	}
+}
+
+// sseStream adapts an HTTP response to the server side of the StreamEvents RPC
+type sseStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (s *sseStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *sseStream) Send(event *meshes.EventsResponse) error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	buf := &bytes.Buffer{}
+	if err := marshaler.Marshal(buf, event); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event.GetEventType().String(), buf.String()); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
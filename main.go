@@ -19,24 +19,42 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"net/http"
 	"os"
 	"time"
 
 	"google.golang.org/grpc"
+	// registers the gzip compressor, responses are compressed when the request was
+	_ "google.golang.org/grpc/encoding/gzip"
 	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/keepalive"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/layer5io/meshery-octarine/gateway"
 	mesh "github.com/layer5io/meshery-octarine/meshes"
 	"github.com/layer5io/meshery-octarine/octarine"
 )
 
 var (
-	gRPCPort = flag.Int("grpc-port", 10003, "The gRPC server port")
+	gRPCPort       = flag.Int("grpc-port", 10003, "The gRPC server port")
+	httpPort       = flag.Int("http-port", 0, "The HTTP gateway port, the gateway is disabled when 0")
+	operatorMode   = flag.Bool("operator", false, "Also reconcile MesheryOctarine custom resources in the cluster the adapter runs in")
+	watchNamespace = flag.String("watch-namespace", "", "The namespace to watch for MesheryOctarine resources, all namespaces when empty")
+
+	maxRecvSize      = flag.Int("grpc-max-recv-size", 16<<20, "The largest gRPC message the server accepts, in bytes")
+	maxSendSize      = flag.Int("grpc-max-send-size", 16<<20, "The largest gRPC message the server sends, in bytes")
+	keepaliveTime    = flag.Duration("grpc-keepalive-time", 2*time.Minute, "How long a connection may be idle before the server pings the client")
+	keepaliveTimeout = flag.Duration("grpc-keepalive-timeout", 20*time.Second, "How long the server waits for the answer to a ping before closing the connection")
+	keepaliveMinTime = flag.Duration("grpc-keepalive-min-time", 30*time.Second, "How often clients may ping the server, connections pinging more often are closed")
 )
 
 var log grpclog.LoggerV2
 
+// startDevControlPlane connects the client to a local control plane in the builds with the devcontrolplane tag,
+// see devcontrolplane.go, and returns what stops it
+var startDevControlPlane func(oClient *octarine.Client) func()
+
 func init() {
 	log = grpclog.NewLoggerV2(os.Stdout, os.Stdout, os.Stdout)
 	grpclog.SetLoggerV2(log)
@@ -55,11 +73,63 @@ func main() {
 		logrus.Fatalln("Failed to listen:", err)
 	}
 	s := grpc.NewServer(
-	// grpc.Creds(credentials.NewServerTLSFromCert(&insecure.Cert)),
+		// grpc.Creds(credentials.NewServerTLSFromCert(&insecure.Cert)),
+		// errors are translated last, so those of the validation are too
+		grpc.ChainUnaryInterceptor(octarine.LocaleInterceptor, octarine.ValidationInterceptor),
+		grpc.ChainStreamInterceptor(octarine.LocaleStreamInterceptor, octarine.ValidationStreamInterceptor),
+		grpc.MaxRecvMsgSize(*maxRecvSize),
+		grpc.MaxSendMsgSize(*maxSendSize),
+		// pings keep event streams open through proxies and load balancers dropping idle connections,
+		// and find the clients which went away without closing them
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    *keepaliveTime,
+			Timeout: *keepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             *keepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
 	)
-	mesh.RegisterMeshServiceServer(s, &octarine.Client{})
+	octarine.DisableBrokenTemplates()
+	if err := octarine.SyncTemplateCatalog(); err != nil {
+		logrus.Fatalln("Failed to set up the template catalog:", err)
+	}
+	if err := octarine.LoadVaultCredentials(); err != nil {
+		logrus.Fatalln("Failed to read the Octarine credentials from Vault:", err)
+	}
+	oClient := &octarine.Client{}
+	mesh.RegisterMeshServiceServer(s, oClient)
 	rand.Seed(time.Now().UnixNano())
+
+	stop := func() {}
+	if startDevControlPlane != nil {
+		stop = startDevControlPlane(oClient)
+	}
+
+	if *operatorMode {
+		controller, err := octarine.NewController(*watchNamespace)
+		if err != nil {
+			logrus.Fatalln("Failed to create the controller:", err)
+		}
+		go func() {
+			logrus.Fatal(controller.Run(make(chan struct{})))
+		}()
+	}
+	if *httpPort != 0 {
+		httpAddr := fmt.Sprintf(":%d", *httpPort)
+		gw := gateway.New(oClient)
+		gw.Validate(octarine.ValidateRequest)
+		gw.Handle("/healthz", oClient.LivenessHandler())
+		gw.Handle("/readyz", oClient.ReadinessHandler())
+		go func() {
+			logrus.Infof("Serving HTTP gateway on %s", httpAddr)
+			logrus.Fatal(http.ListenAndServe(httpAddr, gw))
+		}()
+	}
+
 	// Serve gRPC Server
 	logrus.Infof("Serving gRPC on %s", addr)
-	logrus.Fatal(s.Serve(lis))
+	err = s.Serve(lis)
+	stop()
+	logrus.Fatal(err)
 }
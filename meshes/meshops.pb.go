@@ -52,7 +52,7 @@ func (x OpCategory) String() string {
 	return proto.EnumName(OpCategory_name, int32(x))
 }
 func (OpCategory) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_meshops_70742207f69c0a34, []int{0}
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{0}
 }
 
 type EventType int32
@@ -78,12 +78,63 @@ func (x EventType) String() string {
 	return proto.EnumName(EventType_name, int32(x))
 }
 func (EventType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_meshops_70742207f69c0a34, []int{1}
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{1}
+}
+
+// Severity grades events more finely than EventType, which it maps to: DEBUG and INFO are INFO events, WARN
+// is WARN, ERROR and CRITICAL are ERROR events
+type Severity int32
+
+const (
+	Severity_SEVERITY_UNSPECIFIED Severity = 0
+	// the progress of an operation
+	Severity_SEVERITY_DEBUG Severity = 1
+	// a step or an operation completed
+	Severity_SEVERITY_INFO Severity = 2
+	// something is off but the operation went on, e.g. a stall, drift or a forced change
+	Severity_SEVERITY_WARN Severity = 3
+	// an operation or a check failed
+	Severity_SEVERITY_ERROR Severity = 4
+	// the mesh or the cluster is impaired beyond a single operation, e.g. pods can't be created
+	Severity_SEVERITY_CRITICAL Severity = 5
+)
+
+var Severity_name = map[int32]string{
+	0: "SEVERITY_UNSPECIFIED",
+	1: "SEVERITY_DEBUG",
+	2: "SEVERITY_INFO",
+	3: "SEVERITY_WARN",
+	4: "SEVERITY_ERROR",
+	5: "SEVERITY_CRITICAL",
+}
+var Severity_value = map[string]int32{
+	"SEVERITY_UNSPECIFIED": 0,
+	"SEVERITY_DEBUG":       1,
+	"SEVERITY_INFO":        2,
+	"SEVERITY_WARN":        3,
+	"SEVERITY_ERROR":       4,
+	"SEVERITY_CRITICAL":    5,
+}
+
+func (x Severity) String() string {
+	return proto.EnumName(Severity_name, int32(x))
+}
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{2}
 }
 
 type CreateMeshInstanceRequest struct {
-	K8SConfig            []byte   `protobuf:"bytes,1,opt,name=k8sConfig,proto3" json:"k8sConfig,omitempty"`
-	ContextName          string   `protobuf:"bytes,2,opt,name=contextName,proto3" json:"contextName,omitempty"`
+	K8SConfig   []byte `protobuf:"bytes,1,opt,name=k8sConfig,proto3" json:"k8sConfig,omitempty"`
+	ContextName string `protobuf:"bytes,2,opt,name=contextName,proto3" json:"contextName,omitempty"`
+	// a bearer token replacing the credentials of the context, for kubeconfigs whose auth plugin can't run in the adapter
+	Token string `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	// the address of the API server, with token and certificate_authority, registers the cluster with a ServiceAccount
+	// token in place of a kubeconfig
+	Server string `protobuf:"bytes,4,opt,name=server,proto3" json:"server,omitempty"`
+	// the PEM encoded certificates the API server is verified against, the system roots when empty
+	CertificateAuthority []byte `protobuf:"bytes,5,opt,name=certificate_authority,json=certificateAuthority,proto3" json:"certificate_authority,omitempty"`
+	// registers the cluster under a name operations can target, instead of replacing the default cluster
+	Cluster              string   `protobuf:"bytes,6,opt,name=cluster,proto3" json:"cluster,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -93,7 +144,7 @@ func (m *CreateMeshInstanceRequest) Reset()         { *m = CreateMeshInstanceReq
 func (m *CreateMeshInstanceRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateMeshInstanceRequest) ProtoMessage()    {}
 func (*CreateMeshInstanceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meshops_70742207f69c0a34, []int{0}
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{0}
 }
 func (m *CreateMeshInstanceRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateMeshInstanceRequest.Unmarshal(m, b)
@@ -127,17 +178,47 @@ func (m *CreateMeshInstanceRequest) GetContextName() string {
 	return ""
 }
 
+func (m *CreateMeshInstanceRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *CreateMeshInstanceRequest) GetServer() string {
+	if m != nil {
+		return m.Server
+	}
+	return ""
+}
+
+func (m *CreateMeshInstanceRequest) GetCertificateAuthority() []byte {
+	if m != nil {
+		return m.CertificateAuthority
+	}
+	return nil
+}
+
+func (m *CreateMeshInstanceRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
 type CreateMeshInstanceResponse struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	// what the credentials of the kubeconfig were found to be able to do
+	Access               *ClusterAccess `protobuf:"bytes,1,opt,name=access,proto3" json:"access,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *CreateMeshInstanceResponse) Reset()         { *m = CreateMeshInstanceResponse{} }
 func (m *CreateMeshInstanceResponse) String() string { return proto.CompactTextString(m) }
 func (*CreateMeshInstanceResponse) ProtoMessage()    {}
 func (*CreateMeshInstanceResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meshops_70742207f69c0a34, []int{1}
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{1}
 }
 func (m *CreateMeshInstanceResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateMeshInstanceResponse.Unmarshal(m, b)
@@ -157,6 +238,13 @@ func (m *CreateMeshInstanceResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_CreateMeshInstanceResponse proto.InternalMessageInfo
 
+func (m *CreateMeshInstanceResponse) GetAccess() *ClusterAccess {
+	if m != nil {
+		return m.Access
+	}
+	return nil
+}
+
 type MeshNameRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -167,7 +255,7 @@ func (m *MeshNameRequest) Reset()         { *m = MeshNameRequest{} }
 func (m *MeshNameRequest) String() string { return proto.CompactTextString(m) }
 func (*MeshNameRequest) ProtoMessage()    {}
 func (*MeshNameRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meshops_70742207f69c0a34, []int{2}
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{2}
 }
 func (m *MeshNameRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MeshNameRequest.Unmarshal(m, b)
@@ -198,7 +286,7 @@ func (m *MeshNameResponse) Reset()         { *m = MeshNameResponse{} }
 func (m *MeshNameResponse) String() string { return proto.CompactTextString(m) }
 func (*MeshNameResponse) ProtoMessage()    {}
 func (*MeshNameResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meshops_70742207f69c0a34, []int{3}
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{3}
 }
 func (m *MeshNameResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MeshNameResponse.Unmarshal(m, b)
@@ -226,12 +314,32 @@ func (m *MeshNameResponse) GetName() string {
 }
 
 type ApplyRuleRequest struct {
-	OpName               string   `protobuf:"bytes,1,opt,name=opName,proto3" json:"opName,omitempty"`
-	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
-	Username             string   `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
-	CustomBody           string   `protobuf:"bytes,4,opt,name=custom_body,json=customBody,proto3" json:"custom_body,omitempty"`
-	DeleteOp             bool     `protobuf:"varint,5,opt,name=delete_op,json=deleteOp,proto3" json:"delete_op,omitempty"`
-	OperationId          string   `protobuf:"bytes,6,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	OpName      string `protobuf:"bytes,1,opt,name=opName,proto3" json:"opName,omitempty"`
+	Namespace   string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Username    string `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	CustomBody  string `protobuf:"bytes,4,opt,name=custom_body,json=customBody,proto3" json:"custom_body,omitempty"`
+	DeleteOp    bool   `protobuf:"varint,5,opt,name=delete_op,json=deleteOp,proto3" json:"delete_op,omitempty"`
+	OperationId string `protobuf:"bytes,6,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	// the name a cluster was registered under, the default cluster when empty
+	Cluster string `protobuf:"bytes,7,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	// with delete_op and no custom_body, the custom operation deletes what the operation of this id applied,
+	// the operation_id of the request when empty
+	AppliedOperationId string `protobuf:"bytes,8,opt,name=applied_operation_id,json=appliedOperationId,proto3" json:"applied_operation_id,omitempty"`
+	// lets a custom operation delete or touch more resources and namespaces than the bulk change limits
+	Force bool `protobuf:"varint,9,opt,name=force,proto3" json:"force,omitempty"`
+	// the parameters of the operations which don't take a manifest, e.g. deployment, version or mode, set
+	// over the same fields of custom_body; lists are separated by commas
+	Parameters map[string]string `protobuf:"bytes,10,rep,name=parameters,proto3" json:"parameters,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// the objects of a custom or template operation the admission webhooks of the cluster deny are skipped
+	// and reported, with their manifest, instead of failing the operation
+	ReportDenials bool `protobuf:"varint,11,opt,name=report_denials,json=reportDenials,proto3" json:"report_denials,omitempty"`
+	// retries the failed operation of this id: the same operation in the same namespace, whose manifest
+	// documents already applied, unchanged, are skipped
+	ResumeOperationId string `protobuf:"bytes,12,opt,name=resume_operation_id,json=resumeOperationId,proto3" json:"resume_operation_id,omitempty"`
+	// what to do with a live resource whose fields the operation changes are owned by another field manager,
+	// like Helm, Argo CD or kubectl: warn and overwrite them (the default), skip the resource, or force the
+	// change without a warning
+	ConflictPolicy       string   `protobuf:"bytes,13,opt,name=conflict_policy,json=conflictPolicy,proto3" json:"conflict_policy,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -241,7 +349,7 @@ func (m *ApplyRuleRequest) Reset()         { *m = ApplyRuleRequest{} }
 func (m *ApplyRuleRequest) String() string { return proto.CompactTextString(m) }
 func (*ApplyRuleRequest) ProtoMessage()    {}
 func (*ApplyRuleRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meshops_70742207f69c0a34, []int{4}
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{4}
 }
 func (m *ApplyRuleRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ApplyRuleRequest.Unmarshal(m, b)
@@ -303,9 +411,60 @@ func (m *ApplyRuleRequest) GetOperationId() string {
 	return ""
 }
 
+func (m *ApplyRuleRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+func (m *ApplyRuleRequest) GetAppliedOperationId() string {
+	if m != nil {
+		return m.AppliedOperationId
+	}
+	return ""
+}
+
+func (m *ApplyRuleRequest) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
+
+func (m *ApplyRuleRequest) GetParameters() map[string]string {
+	if m != nil {
+		return m.Parameters
+	}
+	return nil
+}
+
+func (m *ApplyRuleRequest) GetReportDenials() bool {
+	if m != nil {
+		return m.ReportDenials
+	}
+	return false
+}
+
+func (m *ApplyRuleRequest) GetResumeOperationId() string {
+	if m != nil {
+		return m.ResumeOperationId
+	}
+	return ""
+}
+
+func (m *ApplyRuleRequest) GetConflictPolicy() string {
+	if m != nil {
+		return m.ConflictPolicy
+	}
+	return ""
+}
+
 type ApplyRuleResponse struct {
-	Error                string   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	OperationId          string   `protobuf:"bytes,2,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	Error       string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	OperationId string `protobuf:"bytes,2,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	// what the operation was accepted despite, e.g. parameters overriding the custom body
+	Warnings             []string `protobuf:"bytes,3,rep,name=warnings,proto3" json:"warnings,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -315,7 +474,7 @@ func (m *ApplyRuleResponse) Reset()         { *m = ApplyRuleResponse{} }
 func (m *ApplyRuleResponse) String() string { return proto.CompactTextString(m) }
 func (*ApplyRuleResponse) ProtoMessage()    {}
 func (*ApplyRuleResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meshops_70742207f69c0a34, []int{5}
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{5}
 }
 func (m *ApplyRuleResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ApplyRuleResponse.Unmarshal(m, b)
@@ -349,7 +508,27 @@ func (m *ApplyRuleResponse) GetOperationId() string {
 	return ""
 }
 
+func (m *ApplyRuleResponse) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
 type SupportedOperationsRequest struct {
+	// list requests return at most page_size items, 100 by default, and a next_page_token to pass as
+	// page_token for the following page, empty on the last one
+	PageSize  int32  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// only the operations of these categories, all when empty
+	Categories []OpCategory `protobuf:"varint,3,rep,packed,name=categories,proto3,enum=meshes.OpCategory" json:"categories,omitempty"`
+	// only the operations whose key or name contain it
+	Filter string `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
+	// the deployment the operations are checked against, the default one when empty, and its cluster
+	Deployment string `protobuf:"bytes,5,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	Cluster    string `protobuf:"bytes,6,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	// also list the operations the deployment doesn't support, with the reason
+	IncludeUnavailable   bool     `protobuf:"varint,7,opt,name=include_unavailable,json=includeUnavailable,proto3" json:"include_unavailable,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -359,7 +538,7 @@ func (m *SupportedOperationsRequest) Reset()         { *m = SupportedOperationsR
 func (m *SupportedOperationsRequest) String() string { return proto.CompactTextString(m) }
 func (*SupportedOperationsRequest) ProtoMessage()    {}
 func (*SupportedOperationsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meshops_70742207f69c0a34, []int{6}
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{6}
 }
 func (m *SupportedOperationsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SupportedOperationsRequest.Unmarshal(m, b)
@@ -379,19 +558,72 @@ func (m *SupportedOperationsRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_SupportedOperationsRequest proto.InternalMessageInfo
 
+func (m *SupportedOperationsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *SupportedOperationsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *SupportedOperationsRequest) GetCategories() []OpCategory {
+	if m != nil {
+		return m.Categories
+	}
+	return nil
+}
+
+func (m *SupportedOperationsRequest) GetFilter() string {
+	if m != nil {
+		return m.Filter
+	}
+	return ""
+}
+
+func (m *SupportedOperationsRequest) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+func (m *SupportedOperationsRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+func (m *SupportedOperationsRequest) GetIncludeUnavailable() bool {
+	if m != nil {
+		return m.IncludeUnavailable
+	}
+	return false
+}
+
 type SupportedOperationsResponse struct {
-	Ops                  []*SupportedOperation `protobuf:"bytes,1,rep,name=ops,proto3" json:"ops,omitempty"`
-	Error                string                `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
-	XXX_unrecognized     []byte                `json:"-"`
-	XXX_sizecache        int32                 `json:"-"`
+	Ops           []*SupportedOperation `protobuf:"bytes,1,rep,name=ops,proto3" json:"ops,omitempty"`
+	Error         string                `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	NextPageToken string                `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// the release the deployment runs and the features its account is licensed for, empty when unknown
+	InstalledVersion     string   `protobuf:"bytes,4,opt,name=installed_version,json=installedVersion,proto3" json:"installed_version,omitempty"`
+	LicensedFeatures     []string `protobuf:"bytes,5,rep,name=licensed_features,json=licensedFeatures,proto3" json:"licensed_features,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *SupportedOperationsResponse) Reset()         { *m = SupportedOperationsResponse{} }
 func (m *SupportedOperationsResponse) String() string { return proto.CompactTextString(m) }
 func (*SupportedOperationsResponse) ProtoMessage()    {}
 func (*SupportedOperationsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meshops_70742207f69c0a34, []int{7}
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{7}
 }
 func (m *SupportedOperationsResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SupportedOperationsResponse.Unmarshal(m, b)
@@ -425,20 +657,46 @@ func (m *SupportedOperationsResponse) GetError() string {
 	return ""
 }
 
+func (m *SupportedOperationsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *SupportedOperationsResponse) GetInstalledVersion() string {
+	if m != nil {
+		return m.InstalledVersion
+	}
+	return ""
+}
+
+func (m *SupportedOperationsResponse) GetLicensedFeatures() []string {
+	if m != nil {
+		return m.LicensedFeatures
+	}
+	return nil
+}
+
 type SupportedOperation struct {
-	Key                  string     `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	Value                string     `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
-	Category             OpCategory `protobuf:"varint,3,opt,name=category,proto3,enum=meshes.OpCategory" json:"category,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+	Key      string     `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value    string     `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Category OpCategory `protobuf:"varint,3,opt,name=category,proto3,enum=meshes.OpCategory" json:"category,omitempty"`
+	// the first Octarine release running the operation and the licensed features it needs
+	MinVersion string   `protobuf:"bytes,4,opt,name=min_version,json=minVersion,proto3" json:"min_version,omitempty"`
+	Features   []string `protobuf:"bytes,5,rep,name=features,proto3" json:"features,omitempty"`
+	// why the deployment doesn't support the operation, empty when it does
+	Unavailable          string   `protobuf:"bytes,6,opt,name=unavailable,proto3" json:"unavailable,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *SupportedOperation) Reset()         { *m = SupportedOperation{} }
 func (m *SupportedOperation) String() string { return proto.CompactTextString(m) }
 func (*SupportedOperation) ProtoMessage()    {}
 func (*SupportedOperation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meshops_70742207f69c0a34, []int{8}
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{8}
 }
 func (m *SupportedOperation) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SupportedOperation.Unmarshal(m, b)
@@ -479,7 +737,32 @@ func (m *SupportedOperation) GetCategory() OpCategory {
 	return OpCategory_INSTALL
 }
 
+func (m *SupportedOperation) GetMinVersion() string {
+	if m != nil {
+		return m.MinVersion
+	}
+	return ""
+}
+
+func (m *SupportedOperation) GetFeatures() []string {
+	if m != nil {
+		return m.Features
+	}
+	return nil
+}
+
+func (m *SupportedOperation) GetUnavailable() string {
+	if m != nil {
+		return m.Unavailable
+	}
+	return ""
+}
+
 type EventsRequest struct {
+	// only stream the events of at least this severity, all of them when unspecified
+	MinSeverity Severity `protobuf:"varint,1,opt,name=min_severity,json=minSeverity,proto3,enum=meshes.Severity" json:"min_severity,omitempty"`
+	// only stream the events of this operation
+	OperationId          string   `protobuf:"bytes,2,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -489,7 +772,7 @@ func (m *EventsRequest) Reset()         { *m = EventsRequest{} }
 func (m *EventsRequest) String() string { return proto.CompactTextString(m) }
 func (*EventsRequest) ProtoMessage()    {}
 func (*EventsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meshops_70742207f69c0a34, []int{9}
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{9}
 }
 func (m *EventsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_EventsRequest.Unmarshal(m, b)
@@ -509,21 +792,47 @@ func (m *EventsRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_EventsRequest proto.InternalMessageInfo
 
+func (m *EventsRequest) GetMinSeverity() Severity {
+	if m != nil {
+		return m.MinSeverity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (m *EventsRequest) GetOperationId() string {
+	if m != nil {
+		return m.OperationId
+	}
+	return ""
+}
+
 type EventsResponse struct {
-	EventType            EventType `protobuf:"varint,1,opt,name=event_type,json=eventType,proto3,enum=meshes.EventType" json:"event_type,omitempty"`
-	Summary              string    `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
-	Details              string    `protobuf:"bytes,3,opt,name=details,proto3" json:"details,omitempty"`
-	OperationId          string    `protobuf:"bytes,4,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+	EventType   EventType `protobuf:"varint,1,opt,name=event_type,json=eventType,proto3,enum=meshes.EventType" json:"event_type,omitempty"`
+	Summary     string    `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	Details     string    `protobuf:"bytes,3,opt,name=details,proto3" json:"details,omitempty"`
+	OperationId string    `protobuf:"bytes,4,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	Severity    Severity  `protobuf:"varint,5,opt,name=severity,proto3,enum=meshes.Severity" json:"severity,omitempty"`
+	// the watcher which emitted the event, empty for the events of operations. The events of a source are
+	// deduplicated and rate limited.
+	Source string `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`
+	// when the adapter received the event, an RFC 3339 UTC time with nanoseconds
+	Time string `protobuf:"bytes,7,opt,name=time,proto3" json:"time,omitempty"`
+	// increases by one with every event the adapter publishes, from 1 when it starts; a gap tells a stream
+	// missed events, or filtered them out
+	Sequence uint64 `protobuf:"varint,8,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// how long after its operation started the event was emitted, a duration like 1.5s, empty for the events
+	// of no running operation
+	Elapsed              string   `protobuf:"bytes,9,opt,name=elapsed,proto3" json:"elapsed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *EventsResponse) Reset()         { *m = EventsResponse{} }
 func (m *EventsResponse) String() string { return proto.CompactTextString(m) }
 func (*EventsResponse) ProtoMessage()    {}
 func (*EventsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meshops_70742207f69c0a34, []int{10}
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{10}
 }
 func (m *EventsResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_EventsResponse.Unmarshal(m, b)
@@ -571,221 +880,6257 @@ func (m *EventsResponse) GetOperationId() string {
 	return ""
 }
 
-func init() {
-	proto.RegisterType((*CreateMeshInstanceRequest)(nil), "meshes.CreateMeshInstanceRequest")
-	proto.RegisterType((*CreateMeshInstanceResponse)(nil), "meshes.CreateMeshInstanceResponse")
-	proto.RegisterType((*MeshNameRequest)(nil), "meshes.MeshNameRequest")
-	proto.RegisterType((*MeshNameResponse)(nil), "meshes.MeshNameResponse")
-	proto.RegisterType((*ApplyRuleRequest)(nil), "meshes.ApplyRuleRequest")
-	proto.RegisterType((*ApplyRuleResponse)(nil), "meshes.ApplyRuleResponse")
-	proto.RegisterType((*SupportedOperationsRequest)(nil), "meshes.SupportedOperationsRequest")
-	proto.RegisterType((*SupportedOperationsResponse)(nil), "meshes.SupportedOperationsResponse")
-	proto.RegisterType((*SupportedOperation)(nil), "meshes.SupportedOperation")
-	proto.RegisterType((*EventsRequest)(nil), "meshes.EventsRequest")
-	proto.RegisterType((*EventsResponse)(nil), "meshes.EventsResponse")
-	proto.RegisterEnum("meshes.OpCategory", OpCategory_name, OpCategory_value)
-	proto.RegisterEnum("meshes.EventType", EventType_name, EventType_value)
-}
-
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// MeshServiceClient is the client API for MeshService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type MeshServiceClient interface {
-	CreateMeshInstance(ctx context.Context, in *CreateMeshInstanceRequest, opts ...grpc.CallOption) (*CreateMeshInstanceResponse, error)
-	MeshName(ctx context.Context, in *MeshNameRequest, opts ...grpc.CallOption) (*MeshNameResponse, error)
-	ApplyOperation(ctx context.Context, in *ApplyRuleRequest, opts ...grpc.CallOption) (*ApplyRuleResponse, error)
-	SupportedOperations(ctx context.Context, in *SupportedOperationsRequest, opts ...grpc.CallOption) (*SupportedOperationsResponse, error)
-	StreamEvents(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (MeshService_StreamEventsClient, error)
+func (m *EventsResponse) GetSeverity() Severity {
+	if m != nil {
+		return m.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
 }
 
-type meshServiceClient struct {
-	cc *grpc.ClientConn
+func (m *EventsResponse) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
 }
 
-func NewMeshServiceClient(cc *grpc.ClientConn) MeshServiceClient {
-	return &meshServiceClient{cc}
+func (m *EventsResponse) GetTime() string {
+	if m != nil {
+		return m.Time
+	}
+	return ""
 }
 
-func (c *meshServiceClient) CreateMeshInstance(ctx context.Context, in *CreateMeshInstanceRequest, opts ...grpc.CallOption) (*CreateMeshInstanceResponse, error) {
-	out := new(CreateMeshInstanceResponse)
-	err := c.cc.Invoke(ctx, "/meshes.MeshService/CreateMeshInstance", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *EventsResponse) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
 	}
-	return out, nil
+	return 0
 }
 
-func (c *meshServiceClient) MeshName(ctx context.Context, in *MeshNameRequest, opts ...grpc.CallOption) (*MeshNameResponse, error) {
-	out := new(MeshNameResponse)
-	err := c.cc.Invoke(ctx, "/meshes.MeshService/MeshName", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *EventsResponse) GetElapsed() string {
+	if m != nil {
+		return m.Elapsed
 	}
-	return out, nil
+	return ""
 }
 
-func (c *meshServiceClient) ApplyOperation(ctx context.Context, in *ApplyRuleRequest, opts ...grpc.CallOption) (*ApplyRuleResponse, error) {
-	out := new(ApplyRuleResponse)
-	err := c.cc.Invoke(ctx, "/meshes.MeshService/ApplyOperation", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type ClusterCapabilitiesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (c *meshServiceClient) SupportedOperations(ctx context.Context, in *SupportedOperationsRequest, opts ...grpc.CallOption) (*SupportedOperationsResponse, error) {
-	out := new(SupportedOperationsResponse)
-	err := c.cc.Invoke(ctx, "/meshes.MeshService/SupportedOperations", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *ClusterCapabilitiesRequest) Reset()         { *m = ClusterCapabilitiesRequest{} }
+func (m *ClusterCapabilitiesRequest) String() string { return proto.CompactTextString(m) }
+func (*ClusterCapabilitiesRequest) ProtoMessage()    {}
+func (*ClusterCapabilitiesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{11}
+}
+func (m *ClusterCapabilitiesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClusterCapabilitiesRequest.Unmarshal(m, b)
+}
+func (m *ClusterCapabilitiesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClusterCapabilitiesRequest.Marshal(b, m, deterministic)
+}
+func (dst *ClusterCapabilitiesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterCapabilitiesRequest.Merge(dst, src)
+}
+func (m *ClusterCapabilitiesRequest) XXX_Size() int {
+	return xxx_messageInfo_ClusterCapabilitiesRequest.Size(m)
+}
+func (m *ClusterCapabilitiesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterCapabilitiesRequest.DiscardUnknown(m)
 }
 
-func (c *meshServiceClient) StreamEvents(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (MeshService_StreamEventsClient, error) {
+var xxx_messageInfo_ClusterCapabilitiesRequest proto.InternalMessageInfo
+
+// ClusterCapabilitiesResponse describes what the target cluster offers to an Octarine deployment
+type ClusterCapabilitiesResponse struct {
+	KubernetesVersion string `protobuf:"bytes,1,opt,name=kubernetes_version,json=kubernetesVersion,proto3" json:"kubernetes_version,omitempty"`
+	// CNI plugins recognized from the pods running in kube-system
+	CniPlugins        []string `protobuf:"bytes,2,rep,name=cni_plugins,json=cniPlugins,proto3" json:"cni_plugins,omitempty"`
+	AdmissionWebhooks bool     `protobuf:"varint,3,opt,name=admission_webhooks,json=admissionWebhooks,proto3" json:"admission_webhooks,omitempty"`
+	// PodSecurityPolicy, PodSecurityAdmission or None
+	PodSecurity    string          `protobuf:"bytes,4,opt,name=pod_security,json=podSecurity,proto3" json:"pod_security,omitempty"`
+	StorageClasses []*StorageClass `protobuf:"bytes,5,rep,name=storage_classes,json=storageClasses,proto3" json:"storage_classes,omitempty"`
+	LoadBalancer   bool            `protobuf:"varint,6,opt,name=load_balancer,json=loadBalancer,proto3" json:"load_balancer,omitempty"`
+	Error          string          `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	// the os/arch pairs of the schedulable nodes
+	NodePlatforms []string `protobuf:"bytes,8,rep,name=node_platforms,json=nodePlatforms,proto3" json:"node_platforms,omitempty"`
+	// what about the cluster affects Octarine, such as Windows nodes
+	Notes []string `protobuf:"bytes,9,rep,name=notes,proto3" json:"notes,omitempty"`
+	// IPv4, IPv6 or DualStack
+	IpStack              string   `protobuf:"bytes,10,opt,name=ip_stack,json=ipStack,proto3" json:"ip_stack,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClusterCapabilitiesResponse) Reset()         { *m = ClusterCapabilitiesResponse{} }
+func (m *ClusterCapabilitiesResponse) String() string { return proto.CompactTextString(m) }
+func (*ClusterCapabilitiesResponse) ProtoMessage()    {}
+func (*ClusterCapabilitiesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{12}
+}
+func (m *ClusterCapabilitiesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClusterCapabilitiesResponse.Unmarshal(m, b)
+}
+func (m *ClusterCapabilitiesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClusterCapabilitiesResponse.Marshal(b, m, deterministic)
+}
+func (dst *ClusterCapabilitiesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterCapabilitiesResponse.Merge(dst, src)
+}
+func (m *ClusterCapabilitiesResponse) XXX_Size() int {
+	return xxx_messageInfo_ClusterCapabilitiesResponse.Size(m)
+}
+func (m *ClusterCapabilitiesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterCapabilitiesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterCapabilitiesResponse proto.InternalMessageInfo
+
+func (m *ClusterCapabilitiesResponse) GetKubernetesVersion() string {
+	if m != nil {
+		return m.KubernetesVersion
+	}
+	return ""
+}
+
+func (m *ClusterCapabilitiesResponse) GetCniPlugins() []string {
+	if m != nil {
+		return m.CniPlugins
+	}
+	return nil
+}
+
+func (m *ClusterCapabilitiesResponse) GetAdmissionWebhooks() bool {
+	if m != nil {
+		return m.AdmissionWebhooks
+	}
+	return false
+}
+
+func (m *ClusterCapabilitiesResponse) GetPodSecurity() string {
+	if m != nil {
+		return m.PodSecurity
+	}
+	return ""
+}
+
+func (m *ClusterCapabilitiesResponse) GetStorageClasses() []*StorageClass {
+	if m != nil {
+		return m.StorageClasses
+	}
+	return nil
+}
+
+func (m *ClusterCapabilitiesResponse) GetLoadBalancer() bool {
+	if m != nil {
+		return m.LoadBalancer
+	}
+	return false
+}
+
+func (m *ClusterCapabilitiesResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ClusterCapabilitiesResponse) GetNodePlatforms() []string {
+	if m != nil {
+		return m.NodePlatforms
+	}
+	return nil
+}
+
+func (m *ClusterCapabilitiesResponse) GetNotes() []string {
+	if m != nil {
+		return m.Notes
+	}
+	return nil
+}
+
+func (m *ClusterCapabilitiesResponse) GetIpStack() string {
+	if m != nil {
+		return m.IpStack
+	}
+	return ""
+}
+
+type StorageClass struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Provisioner          string   `protobuf:"bytes,2,opt,name=provisioner,proto3" json:"provisioner,omitempty"`
+	Default              bool     `protobuf:"varint,3,opt,name=default,proto3" json:"default,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StorageClass) Reset()         { *m = StorageClass{} }
+func (m *StorageClass) String() string { return proto.CompactTextString(m) }
+func (*StorageClass) ProtoMessage()    {}
+func (*StorageClass) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{13}
+}
+func (m *StorageClass) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StorageClass.Unmarshal(m, b)
+}
+func (m *StorageClass) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StorageClass.Marshal(b, m, deterministic)
+}
+func (dst *StorageClass) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageClass.Merge(dst, src)
+}
+func (m *StorageClass) XXX_Size() int {
+	return xxx_messageInfo_StorageClass.Size(m)
+}
+func (m *StorageClass) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageClass.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StorageClass proto.InternalMessageInfo
+
+func (m *StorageClass) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *StorageClass) GetProvisioner() string {
+	if m != nil {
+		return m.Provisioner
+	}
+	return ""
+}
+
+func (m *StorageClass) GetDefault() bool {
+	if m != nil {
+		return m.Default
+	}
+	return false
+}
+
+type ProxyVersionsRequest struct {
+	// the deployment whose sidecars are checked, may be empty when there is only one
+	Deployment string `protobuf:"bytes,1,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	PageSize   int32  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken  string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// only the workloads of this namespace
+	Namespace string `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// only the workloads whose sidecars don't run the dataplane version
+	OutdatedOnly         bool     `protobuf:"varint,5,opt,name=outdated_only,json=outdatedOnly,proto3" json:"outdated_only,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProxyVersionsRequest) Reset()         { *m = ProxyVersionsRequest{} }
+func (m *ProxyVersionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ProxyVersionsRequest) ProtoMessage()    {}
+func (*ProxyVersionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{14}
+}
+func (m *ProxyVersionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProxyVersionsRequest.Unmarshal(m, b)
+}
+func (m *ProxyVersionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProxyVersionsRequest.Marshal(b, m, deterministic)
+}
+func (dst *ProxyVersionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProxyVersionsRequest.Merge(dst, src)
+}
+func (m *ProxyVersionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ProxyVersionsRequest.Size(m)
+}
+func (m *ProxyVersionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProxyVersionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProxyVersionsRequest proto.InternalMessageInfo
+
+func (m *ProxyVersionsRequest) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+func (m *ProxyVersionsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ProxyVersionsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *ProxyVersionsRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ProxyVersionsRequest) GetOutdatedOnly() bool {
+	if m != nil {
+		return m.OutdatedOnly
+	}
+	return false
+}
+
+// ProxyVersionsResponse compares the sidecars injected into workloads with the dataplane version
+type ProxyVersionsResponse struct {
+	ControlPlaneVersion  string           `protobuf:"bytes,1,opt,name=control_plane_version,json=controlPlaneVersion,proto3" json:"control_plane_version,omitempty"`
+	Workloads            []*WorkloadProxy `protobuf:"bytes,2,rep,name=workloads,proto3" json:"workloads,omitempty"`
+	Error                string           `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	NextPageToken        string           `protobuf:"bytes,4,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ProxyVersionsResponse) Reset()         { *m = ProxyVersionsResponse{} }
+func (m *ProxyVersionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ProxyVersionsResponse) ProtoMessage()    {}
+func (*ProxyVersionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{15}
+}
+func (m *ProxyVersionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProxyVersionsResponse.Unmarshal(m, b)
+}
+func (m *ProxyVersionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProxyVersionsResponse.Marshal(b, m, deterministic)
+}
+func (dst *ProxyVersionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProxyVersionsResponse.Merge(dst, src)
+}
+func (m *ProxyVersionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ProxyVersionsResponse.Size(m)
+}
+func (m *ProxyVersionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProxyVersionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProxyVersionsResponse proto.InternalMessageInfo
+
+func (m *ProxyVersionsResponse) GetControlPlaneVersion() string {
+	if m != nil {
+		return m.ControlPlaneVersion
+	}
+	return ""
+}
+
+func (m *ProxyVersionsResponse) GetWorkloads() []*WorkloadProxy {
+	if m != nil {
+		return m.Workloads
+	}
+	return nil
+}
+
+func (m *ProxyVersionsResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ProxyVersionsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+type WorkloadProxy struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Kind      string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Name      string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// the versions of the sidecars running in the pods of the workload
+	Versions             []string `protobuf:"bytes,4,rep,name=versions,proto3" json:"versions,omitempty"`
+	UpToDate             bool     `protobuf:"varint,5,opt,name=up_to_date,json=upToDate,proto3" json:"up_to_date,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WorkloadProxy) Reset()         { *m = WorkloadProxy{} }
+func (m *WorkloadProxy) String() string { return proto.CompactTextString(m) }
+func (*WorkloadProxy) ProtoMessage()    {}
+func (*WorkloadProxy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{16}
+}
+func (m *WorkloadProxy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkloadProxy.Unmarshal(m, b)
+}
+func (m *WorkloadProxy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkloadProxy.Marshal(b, m, deterministic)
+}
+func (dst *WorkloadProxy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkloadProxy.Merge(dst, src)
+}
+func (m *WorkloadProxy) XXX_Size() int {
+	return xxx_messageInfo_WorkloadProxy.Size(m)
+}
+func (m *WorkloadProxy) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkloadProxy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkloadProxy proto.InternalMessageInfo
+
+func (m *WorkloadProxy) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *WorkloadProxy) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *WorkloadProxy) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *WorkloadProxy) GetVersions() []string {
+	if m != nil {
+		return m.Versions
+	}
+	return nil
+}
+
+func (m *WorkloadProxy) GetUpToDate() bool {
+	if m != nil {
+		return m.UpToDate
+	}
+	return false
+}
+
+type EnforcementStatusRequest struct {
+	Deployment           string   `protobuf:"bytes,1,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EnforcementStatusRequest) Reset()         { *m = EnforcementStatusRequest{} }
+func (m *EnforcementStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*EnforcementStatusRequest) ProtoMessage()    {}
+func (*EnforcementStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{17}
+}
+func (m *EnforcementStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnforcementStatusRequest.Unmarshal(m, b)
+}
+func (m *EnforcementStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnforcementStatusRequest.Marshal(b, m, deterministic)
+}
+func (dst *EnforcementStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnforcementStatusRequest.Merge(dst, src)
+}
+func (m *EnforcementStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_EnforcementStatusRequest.Size(m)
+}
+func (m *EnforcementStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnforcementStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnforcementStatusRequest proto.InternalMessageInfo
+
+func (m *EnforcementStatusRequest) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+// EnforcementStatusResponse tells whether Octarine only monitors (observe) or blocks (enforce) violations
+type EnforcementStatusResponse struct {
+	GlobalMode string `protobuf:"bytes,1,opt,name=global_mode,json=globalMode,proto3" json:"global_mode,omitempty"`
+	// the injected namespaces, with the mode they override the global one with
+	Namespaces           []*NamespaceEnforcement `protobuf:"bytes,2,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	Error                string                  `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *EnforcementStatusResponse) Reset()         { *m = EnforcementStatusResponse{} }
+func (m *EnforcementStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*EnforcementStatusResponse) ProtoMessage()    {}
+func (*EnforcementStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{18}
+}
+func (m *EnforcementStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EnforcementStatusResponse.Unmarshal(m, b)
+}
+func (m *EnforcementStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EnforcementStatusResponse.Marshal(b, m, deterministic)
+}
+func (dst *EnforcementStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EnforcementStatusResponse.Merge(dst, src)
+}
+func (m *EnforcementStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_EnforcementStatusResponse.Size(m)
+}
+func (m *EnforcementStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EnforcementStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EnforcementStatusResponse proto.InternalMessageInfo
+
+func (m *EnforcementStatusResponse) GetGlobalMode() string {
+	if m != nil {
+		return m.GlobalMode
+	}
+	return ""
+}
+
+func (m *EnforcementStatusResponse) GetNamespaces() []*NamespaceEnforcement {
+	if m != nil {
+		return m.Namespaces
+	}
+	return nil
+}
+
+func (m *EnforcementStatusResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type NamespaceEnforcement struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Mode      string `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	// the namespace has no mode of its own and follows the global one
+	Inherited            bool     `protobuf:"varint,3,opt,name=inherited,proto3" json:"inherited,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NamespaceEnforcement) Reset()         { *m = NamespaceEnforcement{} }
+func (m *NamespaceEnforcement) String() string { return proto.CompactTextString(m) }
+func (*NamespaceEnforcement) ProtoMessage()    {}
+func (*NamespaceEnforcement) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{19}
+}
+func (m *NamespaceEnforcement) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NamespaceEnforcement.Unmarshal(m, b)
+}
+func (m *NamespaceEnforcement) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NamespaceEnforcement.Marshal(b, m, deterministic)
+}
+func (dst *NamespaceEnforcement) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NamespaceEnforcement.Merge(dst, src)
+}
+func (m *NamespaceEnforcement) XXX_Size() int {
+	return xxx_messageInfo_NamespaceEnforcement.Size(m)
+}
+func (m *NamespaceEnforcement) XXX_DiscardUnknown() {
+	xxx_messageInfo_NamespaceEnforcement.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NamespaceEnforcement proto.InternalMessageInfo
+
+func (m *NamespaceEnforcement) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *NamespaceEnforcement) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
+func (m *NamespaceEnforcement) GetInherited() bool {
+	if m != nil {
+		return m.Inherited
+	}
+	return false
+}
+
+type PolicyViolationsRequest struct {
+	Deployment string `protobuf:"bytes,1,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	// only count the violations of this namespace, empty for all injected namespaces
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// how far back violations are counted, a duration like 24h which is the default
+	Window string `protobuf:"bytes,3,opt,name=window,proto3" json:"window,omitempty"`
+	// the width of the trend buckets, an hour by default
+	Bucket               string   `protobuf:"bytes,4,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PolicyViolationsRequest) Reset()         { *m = PolicyViolationsRequest{} }
+func (m *PolicyViolationsRequest) String() string { return proto.CompactTextString(m) }
+func (*PolicyViolationsRequest) ProtoMessage()    {}
+func (*PolicyViolationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{20}
+}
+func (m *PolicyViolationsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyViolationsRequest.Unmarshal(m, b)
+}
+func (m *PolicyViolationsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyViolationsRequest.Marshal(b, m, deterministic)
+}
+func (dst *PolicyViolationsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyViolationsRequest.Merge(dst, src)
+}
+func (m *PolicyViolationsRequest) XXX_Size() int {
+	return xxx_messageInfo_PolicyViolationsRequest.Size(m)
+}
+func (m *PolicyViolationsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyViolationsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PolicyViolationsRequest proto.InternalMessageInfo
+
+func (m *PolicyViolationsRequest) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+func (m *PolicyViolationsRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *PolicyViolationsRequest) GetWindow() string {
+	if m != nil {
+		return m.Window
+	}
+	return ""
+}
+
+func (m *PolicyViolationsRequest) GetBucket() string {
+	if m != nil {
+		return m.Bucket
+	}
+	return ""
+}
+
+// PolicyViolationsResponse summarizes the policy violations Octarine reported over a time window
+type PolicyViolationsResponse struct {
+	// the window as RFC 3339 timestamps
+	Since      string            `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`
+	Until      string            `protobuf:"bytes,2,opt,name=until,proto3" json:"until,omitempty"`
+	Total      int64             `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	Namespaces []*ViolationCount `protobuf:"bytes,4,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	Workloads  []*ViolationCount `protobuf:"bytes,5,rep,name=workloads,proto3" json:"workloads,omitempty"`
+	Policies   []*ViolationCount `protobuf:"bytes,6,rep,name=policies,proto3" json:"policies,omitempty"`
+	// the violations of each bucket of the window, oldest first
+	Trend                []*ViolationBucket `protobuf:"bytes,7,rep,name=trend,proto3" json:"trend,omitempty"`
+	Error                string             `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *PolicyViolationsResponse) Reset()         { *m = PolicyViolationsResponse{} }
+func (m *PolicyViolationsResponse) String() string { return proto.CompactTextString(m) }
+func (*PolicyViolationsResponse) ProtoMessage()    {}
+func (*PolicyViolationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{21}
+}
+func (m *PolicyViolationsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PolicyViolationsResponse.Unmarshal(m, b)
+}
+func (m *PolicyViolationsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PolicyViolationsResponse.Marshal(b, m, deterministic)
+}
+func (dst *PolicyViolationsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PolicyViolationsResponse.Merge(dst, src)
+}
+func (m *PolicyViolationsResponse) XXX_Size() int {
+	return xxx_messageInfo_PolicyViolationsResponse.Size(m)
+}
+func (m *PolicyViolationsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PolicyViolationsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PolicyViolationsResponse proto.InternalMessageInfo
+
+func (m *PolicyViolationsResponse) GetSince() string {
+	if m != nil {
+		return m.Since
+	}
+	return ""
+}
+
+func (m *PolicyViolationsResponse) GetUntil() string {
+	if m != nil {
+		return m.Until
+	}
+	return ""
+}
+
+func (m *PolicyViolationsResponse) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *PolicyViolationsResponse) GetNamespaces() []*ViolationCount {
+	if m != nil {
+		return m.Namespaces
+	}
+	return nil
+}
+
+func (m *PolicyViolationsResponse) GetWorkloads() []*ViolationCount {
+	if m != nil {
+		return m.Workloads
+	}
+	return nil
+}
+
+func (m *PolicyViolationsResponse) GetPolicies() []*ViolationCount {
+	if m != nil {
+		return m.Policies
+	}
+	return nil
+}
+
+func (m *PolicyViolationsResponse) GetTrend() []*ViolationBucket {
+	if m != nil {
+		return m.Trend
+	}
+	return nil
+}
+
+func (m *PolicyViolationsResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// ViolationCount is the number of violations of a namespace, a workload or a policy, most violated first
+type ViolationCount struct {
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Workload             string   `protobuf:"bytes,2,opt,name=workload,proto3" json:"workload,omitempty"`
+	Policy               string   `protobuf:"bytes,3,opt,name=policy,proto3" json:"policy,omitempty"`
+	Count                int64    `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ViolationCount) Reset()         { *m = ViolationCount{} }
+func (m *ViolationCount) String() string { return proto.CompactTextString(m) }
+func (*ViolationCount) ProtoMessage()    {}
+func (*ViolationCount) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{22}
+}
+func (m *ViolationCount) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ViolationCount.Unmarshal(m, b)
+}
+func (m *ViolationCount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ViolationCount.Marshal(b, m, deterministic)
+}
+func (dst *ViolationCount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ViolationCount.Merge(dst, src)
+}
+func (m *ViolationCount) XXX_Size() int {
+	return xxx_messageInfo_ViolationCount.Size(m)
+}
+func (m *ViolationCount) XXX_DiscardUnknown() {
+	xxx_messageInfo_ViolationCount.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ViolationCount proto.InternalMessageInfo
+
+func (m *ViolationCount) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ViolationCount) GetWorkload() string {
+	if m != nil {
+		return m.Workload
+	}
+	return ""
+}
+
+func (m *ViolationCount) GetPolicy() string {
+	if m != nil {
+		return m.Policy
+	}
+	return ""
+}
+
+func (m *ViolationCount) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type ViolationBucket struct {
+	Start                string   `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Count                int64    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ViolationBucket) Reset()         { *m = ViolationBucket{} }
+func (m *ViolationBucket) String() string { return proto.CompactTextString(m) }
+func (*ViolationBucket) ProtoMessage()    {}
+func (*ViolationBucket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{23}
+}
+func (m *ViolationBucket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ViolationBucket.Unmarshal(m, b)
+}
+func (m *ViolationBucket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ViolationBucket.Marshal(b, m, deterministic)
+}
+func (dst *ViolationBucket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ViolationBucket.Merge(dst, src)
+}
+func (m *ViolationBucket) XXX_Size() int {
+	return xxx_messageInfo_ViolationBucket.Size(m)
+}
+func (m *ViolationBucket) XXX_DiscardUnknown() {
+	xxx_messageInfo_ViolationBucket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ViolationBucket proto.InternalMessageInfo
+
+func (m *ViolationBucket) GetStart() string {
+	if m != nil {
+		return m.Start
+	}
+	return ""
+}
+
+func (m *ViolationBucket) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type AcknowledgeAlertRequest struct {
+	Deployment string `protobuf:"bytes,1,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	AlertId    string `protobuf:"bytes,2,opt,name=alert_id,json=alertId,proto3" json:"alert_id,omitempty"`
+	Reason     string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// the Meshery user the acknowledgment is recorded for in the audit log
+	Username             string   `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AcknowledgeAlertRequest) Reset()         { *m = AcknowledgeAlertRequest{} }
+func (m *AcknowledgeAlertRequest) String() string { return proto.CompactTextString(m) }
+func (*AcknowledgeAlertRequest) ProtoMessage()    {}
+func (*AcknowledgeAlertRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{24}
+}
+func (m *AcknowledgeAlertRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AcknowledgeAlertRequest.Unmarshal(m, b)
+}
+func (m *AcknowledgeAlertRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AcknowledgeAlertRequest.Marshal(b, m, deterministic)
+}
+func (dst *AcknowledgeAlertRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AcknowledgeAlertRequest.Merge(dst, src)
+}
+func (m *AcknowledgeAlertRequest) XXX_Size() int {
+	return xxx_messageInfo_AcknowledgeAlertRequest.Size(m)
+}
+func (m *AcknowledgeAlertRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AcknowledgeAlertRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AcknowledgeAlertRequest proto.InternalMessageInfo
+
+func (m *AcknowledgeAlertRequest) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+func (m *AcknowledgeAlertRequest) GetAlertId() string {
+	if m != nil {
+		return m.AlertId
+	}
+	return ""
+}
+
+func (m *AcknowledgeAlertRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *AcknowledgeAlertRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+type AcknowledgeAlertResponse struct {
+	Error                string   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AcknowledgeAlertResponse) Reset()         { *m = AcknowledgeAlertResponse{} }
+func (m *AcknowledgeAlertResponse) String() string { return proto.CompactTextString(m) }
+func (*AcknowledgeAlertResponse) ProtoMessage()    {}
+func (*AcknowledgeAlertResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{25}
+}
+func (m *AcknowledgeAlertResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AcknowledgeAlertResponse.Unmarshal(m, b)
+}
+func (m *AcknowledgeAlertResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AcknowledgeAlertResponse.Marshal(b, m, deterministic)
+}
+func (dst *AcknowledgeAlertResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AcknowledgeAlertResponse.Merge(dst, src)
+}
+func (m *AcknowledgeAlertResponse) XXX_Size() int {
+	return xxx_messageInfo_AcknowledgeAlertResponse.Size(m)
+}
+func (m *AcknowledgeAlertResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AcknowledgeAlertResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AcknowledgeAlertResponse proto.InternalMessageInfo
+
+func (m *AcknowledgeAlertResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type MuteAlertRequest struct {
+	Deployment string `protobuf:"bytes,1,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	AlertId    string `protobuf:"bytes,2,opt,name=alert_id,json=alertId,proto3" json:"alert_id,omitempty"`
+	// how long the alert stays muted, a duration like 8h
+	Duration             string   `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	Reason               string   `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Username             string   `protobuf:"bytes,5,opt,name=username,proto3" json:"username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MuteAlertRequest) Reset()         { *m = MuteAlertRequest{} }
+func (m *MuteAlertRequest) String() string { return proto.CompactTextString(m) }
+func (*MuteAlertRequest) ProtoMessage()    {}
+func (*MuteAlertRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{26}
+}
+func (m *MuteAlertRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MuteAlertRequest.Unmarshal(m, b)
+}
+func (m *MuteAlertRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MuteAlertRequest.Marshal(b, m, deterministic)
+}
+func (dst *MuteAlertRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MuteAlertRequest.Merge(dst, src)
+}
+func (m *MuteAlertRequest) XXX_Size() int {
+	return xxx_messageInfo_MuteAlertRequest.Size(m)
+}
+func (m *MuteAlertRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MuteAlertRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MuteAlertRequest proto.InternalMessageInfo
+
+func (m *MuteAlertRequest) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+func (m *MuteAlertRequest) GetAlertId() string {
+	if m != nil {
+		return m.AlertId
+	}
+	return ""
+}
+
+func (m *MuteAlertRequest) GetDuration() string {
+	if m != nil {
+		return m.Duration
+	}
+	return ""
+}
+
+func (m *MuteAlertRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *MuteAlertRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+type MuteAlertResponse struct {
+	// RFC 3339 timestamp the alert fires again after
+	MutedUntil           string   `protobuf:"bytes,1,opt,name=muted_until,json=mutedUntil,proto3" json:"muted_until,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MuteAlertResponse) Reset()         { *m = MuteAlertResponse{} }
+func (m *MuteAlertResponse) String() string { return proto.CompactTextString(m) }
+func (*MuteAlertResponse) ProtoMessage()    {}
+func (*MuteAlertResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{27}
+}
+func (m *MuteAlertResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MuteAlertResponse.Unmarshal(m, b)
+}
+func (m *MuteAlertResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MuteAlertResponse.Marshal(b, m, deterministic)
+}
+func (dst *MuteAlertResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MuteAlertResponse.Merge(dst, src)
+}
+func (m *MuteAlertResponse) XXX_Size() int {
+	return xxx_messageInfo_MuteAlertResponse.Size(m)
+}
+func (m *MuteAlertResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MuteAlertResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MuteAlertResponse proto.InternalMessageInfo
+
+func (m *MuteAlertResponse) GetMutedUntil() string {
+	if m != nil {
+		return m.MutedUntil
+	}
+	return ""
+}
+
+func (m *MuteAlertResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type ExportKubeconfigRequest struct {
+	Deployment string `protobuf:"bytes,1,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	// the operator the kubeconfig is issued to, it names the service account and the audit entry
+	Username string `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	// how long the token stays valid, a duration like 1h which is the default
+	Ttl                  string   `protobuf:"bytes,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportKubeconfigRequest) Reset()         { *m = ExportKubeconfigRequest{} }
+func (m *ExportKubeconfigRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportKubeconfigRequest) ProtoMessage()    {}
+func (*ExportKubeconfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{28}
+}
+func (m *ExportKubeconfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportKubeconfigRequest.Unmarshal(m, b)
+}
+func (m *ExportKubeconfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportKubeconfigRequest.Marshal(b, m, deterministic)
+}
+func (dst *ExportKubeconfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportKubeconfigRequest.Merge(dst, src)
+}
+func (m *ExportKubeconfigRequest) XXX_Size() int {
+	return xxx_messageInfo_ExportKubeconfigRequest.Size(m)
+}
+func (m *ExportKubeconfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportKubeconfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportKubeconfigRequest proto.InternalMessageInfo
+
+func (m *ExportKubeconfigRequest) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+func (m *ExportKubeconfigRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *ExportKubeconfigRequest) GetTtl() string {
+	if m != nil {
+		return m.Ttl
+	}
+	return ""
+}
+
+// ExportKubeconfigResponse holds a kubeconfig limited to the namespace of the deployment
+type ExportKubeconfigResponse struct {
+	Kubeconfig []byte `protobuf:"bytes,1,opt,name=kubeconfig,proto3" json:"kubeconfig,omitempty"`
+	// RFC 3339 timestamp the token expires at
+	ExpiresAt            string   `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportKubeconfigResponse) Reset()         { *m = ExportKubeconfigResponse{} }
+func (m *ExportKubeconfigResponse) String() string { return proto.CompactTextString(m) }
+func (*ExportKubeconfigResponse) ProtoMessage()    {}
+func (*ExportKubeconfigResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{29}
+}
+func (m *ExportKubeconfigResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportKubeconfigResponse.Unmarshal(m, b)
+}
+func (m *ExportKubeconfigResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportKubeconfigResponse.Marshal(b, m, deterministic)
+}
+func (dst *ExportKubeconfigResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportKubeconfigResponse.Merge(dst, src)
+}
+func (m *ExportKubeconfigResponse) XXX_Size() int {
+	return xxx_messageInfo_ExportKubeconfigResponse.Size(m)
+}
+func (m *ExportKubeconfigResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportKubeconfigResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportKubeconfigResponse proto.InternalMessageInfo
+
+func (m *ExportKubeconfigResponse) GetKubeconfig() []byte {
+	if m != nil {
+		return m.Kubeconfig
+	}
+	return nil
+}
+
+func (m *ExportKubeconfigResponse) GetExpiresAt() string {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return ""
+}
+
+func (m *ExportKubeconfigResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// ScheduleOperationRequest registers an operation to run whenever the cron expression matches,
+// registering a name again replaces its schedule
+type ScheduleOperationRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// five fields, minute hour day-of-month month day-of-week, evaluated in UTC, or @hourly, @daily, @weekly, @monthly
+	Cron string `protobuf:"bytes,2,opt,name=cron,proto3" json:"cron,omitempty"`
+	// the operation to run, every run gets a new operation id
+	Operation *ApplyRuleRequest `protobuf:"bytes,3,opt,name=operation,proto3" json:"operation,omitempty"`
+	// the Meshery user the schedule is recorded for in the audit log
+	Username             string   `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ScheduleOperationRequest) Reset()         { *m = ScheduleOperationRequest{} }
+func (m *ScheduleOperationRequest) String() string { return proto.CompactTextString(m) }
+func (*ScheduleOperationRequest) ProtoMessage()    {}
+func (*ScheduleOperationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{30}
+}
+func (m *ScheduleOperationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScheduleOperationRequest.Unmarshal(m, b)
+}
+func (m *ScheduleOperationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScheduleOperationRequest.Marshal(b, m, deterministic)
+}
+func (dst *ScheduleOperationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduleOperationRequest.Merge(dst, src)
+}
+func (m *ScheduleOperationRequest) XXX_Size() int {
+	return xxx_messageInfo_ScheduleOperationRequest.Size(m)
+}
+func (m *ScheduleOperationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduleOperationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScheduleOperationRequest proto.InternalMessageInfo
+
+func (m *ScheduleOperationRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ScheduleOperationRequest) GetCron() string {
+	if m != nil {
+		return m.Cron
+	}
+	return ""
+}
+
+func (m *ScheduleOperationRequest) GetOperation() *ApplyRuleRequest {
+	if m != nil {
+		return m.Operation
+	}
+	return nil
+}
+
+func (m *ScheduleOperationRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+type ScheduleOperationResponse struct {
+	// RFC 3339 timestamp of the first run
+	NextRun              string   `protobuf:"bytes,1,opt,name=next_run,json=nextRun,proto3" json:"next_run,omitempty"`
+	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ScheduleOperationResponse) Reset()         { *m = ScheduleOperationResponse{} }
+func (m *ScheduleOperationResponse) String() string { return proto.CompactTextString(m) }
+func (*ScheduleOperationResponse) ProtoMessage()    {}
+func (*ScheduleOperationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{31}
+}
+func (m *ScheduleOperationResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScheduleOperationResponse.Unmarshal(m, b)
+}
+func (m *ScheduleOperationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScheduleOperationResponse.Marshal(b, m, deterministic)
+}
+func (dst *ScheduleOperationResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduleOperationResponse.Merge(dst, src)
+}
+func (m *ScheduleOperationResponse) XXX_Size() int {
+	return xxx_messageInfo_ScheduleOperationResponse.Size(m)
+}
+func (m *ScheduleOperationResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduleOperationResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScheduleOperationResponse proto.InternalMessageInfo
+
+func (m *ScheduleOperationResponse) GetNextRun() string {
+	if m != nil {
+		return m.NextRun
+	}
+	return ""
+}
+
+func (m *ScheduleOperationResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type ListSchedulesRequest struct {
+	PageSize  int32  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// only the schedules whose name or operation contain it
+	Filter               string   `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSchedulesRequest) Reset()         { *m = ListSchedulesRequest{} }
+func (m *ListSchedulesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSchedulesRequest) ProtoMessage()    {}
+func (*ListSchedulesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{32}
+}
+func (m *ListSchedulesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSchedulesRequest.Unmarshal(m, b)
+}
+func (m *ListSchedulesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSchedulesRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListSchedulesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSchedulesRequest.Merge(dst, src)
+}
+func (m *ListSchedulesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSchedulesRequest.Size(m)
+}
+func (m *ListSchedulesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSchedulesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSchedulesRequest proto.InternalMessageInfo
+
+func (m *ListSchedulesRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListSchedulesRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *ListSchedulesRequest) GetFilter() string {
+	if m != nil {
+		return m.Filter
+	}
+	return ""
+}
+
+type ListSchedulesResponse struct {
+	Schedules            []*Schedule `protobuf:"bytes,1,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	Error                string      `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	NextPageToken        string      `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListSchedulesResponse) Reset()         { *m = ListSchedulesResponse{} }
+func (m *ListSchedulesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSchedulesResponse) ProtoMessage()    {}
+func (*ListSchedulesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{33}
+}
+func (m *ListSchedulesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSchedulesResponse.Unmarshal(m, b)
+}
+func (m *ListSchedulesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSchedulesResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListSchedulesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSchedulesResponse.Merge(dst, src)
+}
+func (m *ListSchedulesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSchedulesResponse.Size(m)
+}
+func (m *ListSchedulesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSchedulesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSchedulesResponse proto.InternalMessageInfo
+
+func (m *ListSchedulesResponse) GetSchedules() []*Schedule {
+	if m != nil {
+		return m.Schedules
+	}
+	return nil
+}
+
+func (m *ListSchedulesResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ListSchedulesResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+type Schedule struct {
+	Name            string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Cron            string            `protobuf:"bytes,2,opt,name=cron,proto3" json:"cron,omitempty"`
+	Operation       *ApplyRuleRequest `protobuf:"bytes,3,opt,name=operation,proto3" json:"operation,omitempty"`
+	NextRun         string            `protobuf:"bytes,4,opt,name=next_run,json=nextRun,proto3" json:"next_run,omitempty"`
+	LastRun         string            `protobuf:"bytes,5,opt,name=last_run,json=lastRun,proto3" json:"last_run,omitempty"`
+	LastOperationId string            `protobuf:"bytes,6,opt,name=last_operation_id,json=lastOperationId,proto3" json:"last_operation_id,omitempty"`
+	// why the last run could not be started, the outcome of the operation itself is reported by its events
+	LastError            string   `protobuf:"bytes,7,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Schedule) Reset()         { *m = Schedule{} }
+func (m *Schedule) String() string { return proto.CompactTextString(m) }
+func (*Schedule) ProtoMessage()    {}
+func (*Schedule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{34}
+}
+func (m *Schedule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Schedule.Unmarshal(m, b)
+}
+func (m *Schedule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Schedule.Marshal(b, m, deterministic)
+}
+func (dst *Schedule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Schedule.Merge(dst, src)
+}
+func (m *Schedule) XXX_Size() int {
+	return xxx_messageInfo_Schedule.Size(m)
+}
+func (m *Schedule) XXX_DiscardUnknown() {
+	xxx_messageInfo_Schedule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Schedule proto.InternalMessageInfo
+
+func (m *Schedule) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Schedule) GetCron() string {
+	if m != nil {
+		return m.Cron
+	}
+	return ""
+}
+
+func (m *Schedule) GetOperation() *ApplyRuleRequest {
+	if m != nil {
+		return m.Operation
+	}
+	return nil
+}
+
+func (m *Schedule) GetNextRun() string {
+	if m != nil {
+		return m.NextRun
+	}
+	return ""
+}
+
+func (m *Schedule) GetLastRun() string {
+	if m != nil {
+		return m.LastRun
+	}
+	return ""
+}
+
+func (m *Schedule) GetLastOperationId() string {
+	if m != nil {
+		return m.LastOperationId
+	}
+	return ""
+}
+
+func (m *Schedule) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+type DeleteScheduleRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Username             string   `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteScheduleRequest) Reset()         { *m = DeleteScheduleRequest{} }
+func (m *DeleteScheduleRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteScheduleRequest) ProtoMessage()    {}
+func (*DeleteScheduleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{35}
+}
+func (m *DeleteScheduleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteScheduleRequest.Unmarshal(m, b)
+}
+func (m *DeleteScheduleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteScheduleRequest.Marshal(b, m, deterministic)
+}
+func (dst *DeleteScheduleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteScheduleRequest.Merge(dst, src)
+}
+func (m *DeleteScheduleRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteScheduleRequest.Size(m)
+}
+func (m *DeleteScheduleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteScheduleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteScheduleRequest proto.InternalMessageInfo
+
+func (m *DeleteScheduleRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DeleteScheduleRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+type DeleteScheduleResponse struct {
+	Error                string   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteScheduleResponse) Reset()         { *m = DeleteScheduleResponse{} }
+func (m *DeleteScheduleResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteScheduleResponse) ProtoMessage()    {}
+func (*DeleteScheduleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{36}
+}
+func (m *DeleteScheduleResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteScheduleResponse.Unmarshal(m, b)
+}
+func (m *DeleteScheduleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteScheduleResponse.Marshal(b, m, deterministic)
+}
+func (dst *DeleteScheduleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteScheduleResponse.Merge(dst, src)
+}
+func (m *DeleteScheduleResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteScheduleResponse.Size(m)
+}
+func (m *DeleteScheduleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteScheduleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteScheduleResponse proto.InternalMessageInfo
+
+func (m *DeleteScheduleResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type ClusterAccess struct {
+	ServerVersion      string `protobuf:"bytes,1,opt,name=server_version,json=serverVersion,proto3" json:"server_version,omitempty"`
+	KubeSystemReadable bool   `protobuf:"varint,2,opt,name=kube_system_readable,json=kubeSystemReadable,proto3" json:"kube_system_readable,omitempty"`
+	// the namespace the permissions were reviewed in
+	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// the rules the credentials are granted there, as "verbs resources"
+	Rules []string `protobuf:"bytes,4,rep,name=rules,proto3" json:"rules,omitempty"`
+	// the permissions operations need which the credentials lack, as "verb resource"
+	Missing              []string `protobuf:"bytes,5,rep,name=missing,proto3" json:"missing,omitempty"`
+	Warnings             []string `protobuf:"bytes,6,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClusterAccess) Reset()         { *m = ClusterAccess{} }
+func (m *ClusterAccess) String() string { return proto.CompactTextString(m) }
+func (*ClusterAccess) ProtoMessage()    {}
+func (*ClusterAccess) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{37}
+}
+func (m *ClusterAccess) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClusterAccess.Unmarshal(m, b)
+}
+func (m *ClusterAccess) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClusterAccess.Marshal(b, m, deterministic)
+}
+func (dst *ClusterAccess) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterAccess.Merge(dst, src)
+}
+func (m *ClusterAccess) XXX_Size() int {
+	return xxx_messageInfo_ClusterAccess.Size(m)
+}
+func (m *ClusterAccess) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterAccess.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterAccess proto.InternalMessageInfo
+
+func (m *ClusterAccess) GetServerVersion() string {
+	if m != nil {
+		return m.ServerVersion
+	}
+	return ""
+}
+
+func (m *ClusterAccess) GetKubeSystemReadable() bool {
+	if m != nil {
+		return m.KubeSystemReadable
+	}
+	return false
+}
+
+func (m *ClusterAccess) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ClusterAccess) GetRules() []string {
+	if m != nil {
+		return m.Rules
+	}
+	return nil
+}
+
+func (m *ClusterAccess) GetMissing() []string {
+	if m != nil {
+		return m.Missing
+	}
+	return nil
+}
+
+func (m *ClusterAccess) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
+type EstimateFootprintRequest struct {
+	// an installed deployment, its running dataplane and sidecars are measured instead of estimated
+	Deployment string `protobuf:"bytes,1,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	// the namespaces to inject besides the ones labeled for injection already
+	Namespaces []string `protobuf:"bytes,2,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	// the rendered dataplane manifest to estimate, when no deployment is given
+	Manifest string `protobuf:"bytes,3,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	// the requests of a sidecar, replacing the measured or default ones
+	SidecarCpu           string   `protobuf:"bytes,4,opt,name=sidecar_cpu,json=sidecarCpu,proto3" json:"sidecar_cpu,omitempty"`
+	SidecarMemory        string   `protobuf:"bytes,5,opt,name=sidecar_memory,json=sidecarMemory,proto3" json:"sidecar_memory,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EstimateFootprintRequest) Reset()         { *m = EstimateFootprintRequest{} }
+func (m *EstimateFootprintRequest) String() string { return proto.CompactTextString(m) }
+func (*EstimateFootprintRequest) ProtoMessage()    {}
+func (*EstimateFootprintRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{38}
+}
+func (m *EstimateFootprintRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EstimateFootprintRequest.Unmarshal(m, b)
+}
+func (m *EstimateFootprintRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EstimateFootprintRequest.Marshal(b, m, deterministic)
+}
+func (dst *EstimateFootprintRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EstimateFootprintRequest.Merge(dst, src)
+}
+func (m *EstimateFootprintRequest) XXX_Size() int {
+	return xxx_messageInfo_EstimateFootprintRequest.Size(m)
+}
+func (m *EstimateFootprintRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EstimateFootprintRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EstimateFootprintRequest proto.InternalMessageInfo
+
+func (m *EstimateFootprintRequest) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+func (m *EstimateFootprintRequest) GetNamespaces() []string {
+	if m != nil {
+		return m.Namespaces
+	}
+	return nil
+}
+
+func (m *EstimateFootprintRequest) GetManifest() string {
+	if m != nil {
+		return m.Manifest
+	}
+	return ""
+}
+
+func (m *EstimateFootprintRequest) GetSidecarCpu() string {
+	if m != nil {
+		return m.SidecarCpu
+	}
+	return ""
+}
+
+func (m *EstimateFootprintRequest) GetSidecarMemory() string {
+	if m != nil {
+		return m.SidecarMemory
+	}
+	return ""
+}
+
+type Footprint struct {
+	// CPU and memory requests as Kubernetes quantities
+	Cpu                  string   `protobuf:"bytes,1,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Memory               string   `protobuf:"bytes,2,opt,name=memory,proto3" json:"memory,omitempty"`
+	Pods                 int32    `protobuf:"varint,3,opt,name=pods,proto3" json:"pods,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Footprint) Reset()         { *m = Footprint{} }
+func (m *Footprint) String() string { return proto.CompactTextString(m) }
+func (*Footprint) ProtoMessage()    {}
+func (*Footprint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{39}
+}
+func (m *Footprint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Footprint.Unmarshal(m, b)
+}
+func (m *Footprint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Footprint.Marshal(b, m, deterministic)
+}
+func (dst *Footprint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Footprint.Merge(dst, src)
+}
+func (m *Footprint) XXX_Size() int {
+	return xxx_messageInfo_Footprint.Size(m)
+}
+func (m *Footprint) XXX_DiscardUnknown() {
+	xxx_messageInfo_Footprint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Footprint proto.InternalMessageInfo
+
+func (m *Footprint) GetCpu() string {
+	if m != nil {
+		return m.Cpu
+	}
+	return ""
+}
+
+func (m *Footprint) GetMemory() string {
+	if m != nil {
+		return m.Memory
+	}
+	return ""
+}
+
+func (m *Footprint) GetPods() int32 {
+	if m != nil {
+		return m.Pods
+	}
+	return 0
+}
+
+type NamespaceFootprint struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// the running pods which would get a sidecar
+	Pods int32 `protobuf:"varint,2,opt,name=pods,proto3" json:"pods,omitempty"`
+	// the running pods which have one already
+	Injected             int32      `protobuf:"varint,3,opt,name=injected,proto3" json:"injected,omitempty"`
+	Sidecars             *Footprint `protobuf:"bytes,4,opt,name=sidecars,proto3" json:"sidecars,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *NamespaceFootprint) Reset()         { *m = NamespaceFootprint{} }
+func (m *NamespaceFootprint) String() string { return proto.CompactTextString(m) }
+func (*NamespaceFootprint) ProtoMessage()    {}
+func (*NamespaceFootprint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{40}
+}
+func (m *NamespaceFootprint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NamespaceFootprint.Unmarshal(m, b)
+}
+func (m *NamespaceFootprint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NamespaceFootprint.Marshal(b, m, deterministic)
+}
+func (dst *NamespaceFootprint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NamespaceFootprint.Merge(dst, src)
+}
+func (m *NamespaceFootprint) XXX_Size() int {
+	return xxx_messageInfo_NamespaceFootprint.Size(m)
+}
+func (m *NamespaceFootprint) XXX_DiscardUnknown() {
+	xxx_messageInfo_NamespaceFootprint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NamespaceFootprint proto.InternalMessageInfo
+
+func (m *NamespaceFootprint) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *NamespaceFootprint) GetPods() int32 {
+	if m != nil {
+		return m.Pods
+	}
+	return 0
+}
+
+func (m *NamespaceFootprint) GetInjected() int32 {
+	if m != nil {
+		return m.Injected
+	}
+	return 0
+}
+
+func (m *NamespaceFootprint) GetSidecars() *Footprint {
+	if m != nil {
+		return m.Sidecars
+	}
+	return nil
+}
+
+type EstimateFootprintResponse struct {
+	ControlPlane *Footprint `protobuf:"bytes,1,opt,name=control_plane,json=controlPlane,proto3" json:"control_plane,omitempty"`
+	// where the control plane numbers come from: a deployment, the manifest or the default estimate
+	ControlPlaneSource   string                `protobuf:"bytes,2,opt,name=control_plane_source,json=controlPlaneSource,proto3" json:"control_plane_source,omitempty"`
+	Sidecar              *Footprint            `protobuf:"bytes,3,opt,name=sidecar,proto3" json:"sidecar,omitempty"`
+	SidecarSource        string                `protobuf:"bytes,4,opt,name=sidecar_source,json=sidecarSource,proto3" json:"sidecar_source,omitempty"`
+	Namespaces           []*NamespaceFootprint `protobuf:"bytes,5,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	Total                *Footprint            `protobuf:"bytes,6,opt,name=total,proto3" json:"total,omitempty"`
+	Error                string                `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *EstimateFootprintResponse) Reset()         { *m = EstimateFootprintResponse{} }
+func (m *EstimateFootprintResponse) String() string { return proto.CompactTextString(m) }
+func (*EstimateFootprintResponse) ProtoMessage()    {}
+func (*EstimateFootprintResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{41}
+}
+func (m *EstimateFootprintResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EstimateFootprintResponse.Unmarshal(m, b)
+}
+func (m *EstimateFootprintResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EstimateFootprintResponse.Marshal(b, m, deterministic)
+}
+func (dst *EstimateFootprintResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EstimateFootprintResponse.Merge(dst, src)
+}
+func (m *EstimateFootprintResponse) XXX_Size() int {
+	return xxx_messageInfo_EstimateFootprintResponse.Size(m)
+}
+func (m *EstimateFootprintResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EstimateFootprintResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EstimateFootprintResponse proto.InternalMessageInfo
+
+func (m *EstimateFootprintResponse) GetControlPlane() *Footprint {
+	if m != nil {
+		return m.ControlPlane
+	}
+	return nil
+}
+
+func (m *EstimateFootprintResponse) GetControlPlaneSource() string {
+	if m != nil {
+		return m.ControlPlaneSource
+	}
+	return ""
+}
+
+func (m *EstimateFootprintResponse) GetSidecar() *Footprint {
+	if m != nil {
+		return m.Sidecar
+	}
+	return nil
+}
+
+func (m *EstimateFootprintResponse) GetSidecarSource() string {
+	if m != nil {
+		return m.SidecarSource
+	}
+	return ""
+}
+
+func (m *EstimateFootprintResponse) GetNamespaces() []*NamespaceFootprint {
+	if m != nil {
+		return m.Namespaces
+	}
+	return nil
+}
+
+func (m *EstimateFootprintResponse) GetTotal() *Footprint {
+	if m != nil {
+		return m.Total
+	}
+	return nil
+}
+
+func (m *EstimateFootprintResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type LintTemplatesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LintTemplatesRequest) Reset()         { *m = LintTemplatesRequest{} }
+func (m *LintTemplatesRequest) String() string { return proto.CompactTextString(m) }
+func (*LintTemplatesRequest) ProtoMessage()    {}
+func (*LintTemplatesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{42}
+}
+func (m *LintTemplatesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LintTemplatesRequest.Unmarshal(m, b)
+}
+func (m *LintTemplatesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LintTemplatesRequest.Marshal(b, m, deterministic)
+}
+func (dst *LintTemplatesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LintTemplatesRequest.Merge(dst, src)
+}
+func (m *LintTemplatesRequest) XXX_Size() int {
+	return xxx_messageInfo_LintTemplatesRequest.Size(m)
+}
+func (m *LintTemplatesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LintTemplatesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LintTemplatesRequest proto.InternalMessageInfo
+
+type TemplateLint struct {
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	Template  string `protobuf:"bytes,2,opt,name=template,proto3" json:"template,omitempty"`
+	// the objects the template rendered, with the first parameter set
+	Objects int32  `protobuf:"varint,3,opt,name=objects,proto3" json:"objects,omitempty"`
+	Error   string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// whether the operation was left out of the supported ones at startup
+	Disabled bool `protobuf:"varint,5,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	// the Octarine release the template set starts at, empty for the unversioned templates
+	TemplateSet          string   `protobuf:"bytes,6,opt,name=template_set,json=templateSet,proto3" json:"template_set,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TemplateLint) Reset()         { *m = TemplateLint{} }
+func (m *TemplateLint) String() string { return proto.CompactTextString(m) }
+func (*TemplateLint) ProtoMessage()    {}
+func (*TemplateLint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{43}
+}
+func (m *TemplateLint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TemplateLint.Unmarshal(m, b)
+}
+func (m *TemplateLint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TemplateLint.Marshal(b, m, deterministic)
+}
+func (dst *TemplateLint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TemplateLint.Merge(dst, src)
+}
+func (m *TemplateLint) XXX_Size() int {
+	return xxx_messageInfo_TemplateLint.Size(m)
+}
+func (m *TemplateLint) XXX_DiscardUnknown() {
+	xxx_messageInfo_TemplateLint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TemplateLint proto.InternalMessageInfo
+
+func (m *TemplateLint) GetOperation() string {
+	if m != nil {
+		return m.Operation
+	}
+	return ""
+}
+
+func (m *TemplateLint) GetTemplate() string {
+	if m != nil {
+		return m.Template
+	}
+	return ""
+}
+
+func (m *TemplateLint) GetObjects() int32 {
+	if m != nil {
+		return m.Objects
+	}
+	return 0
+}
+
+func (m *TemplateLint) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *TemplateLint) GetDisabled() bool {
+	if m != nil {
+		return m.Disabled
+	}
+	return false
+}
+
+func (m *TemplateLint) GetTemplateSet() string {
+	if m != nil {
+		return m.TemplateSet
+	}
+	return ""
+}
+
+type LintTemplatesResponse struct {
+	Templates []*TemplateLint `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
+	Error     string          `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	// the version of the synced template catalog, empty when the templates of the image are used
+	CatalogVersion  string `protobuf:"bytes,3,opt,name=catalog_version,json=catalogVersion,proto3" json:"catalog_version,omitempty"`
+	CatalogSyncedAt string `protobuf:"bytes,4,opt,name=catalog_synced_at,json=catalogSyncedAt,proto3" json:"catalog_synced_at,omitempty"`
+	// why the last catalog sync failed
+	CatalogError         string   `protobuf:"bytes,5,opt,name=catalog_error,json=catalogError,proto3" json:"catalog_error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LintTemplatesResponse) Reset()         { *m = LintTemplatesResponse{} }
+func (m *LintTemplatesResponse) String() string { return proto.CompactTextString(m) }
+func (*LintTemplatesResponse) ProtoMessage()    {}
+func (*LintTemplatesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{44}
+}
+func (m *LintTemplatesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LintTemplatesResponse.Unmarshal(m, b)
+}
+func (m *LintTemplatesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LintTemplatesResponse.Marshal(b, m, deterministic)
+}
+func (dst *LintTemplatesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LintTemplatesResponse.Merge(dst, src)
+}
+func (m *LintTemplatesResponse) XXX_Size() int {
+	return xxx_messageInfo_LintTemplatesResponse.Size(m)
+}
+func (m *LintTemplatesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LintTemplatesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LintTemplatesResponse proto.InternalMessageInfo
+
+func (m *LintTemplatesResponse) GetTemplates() []*TemplateLint {
+	if m != nil {
+		return m.Templates
+	}
+	return nil
+}
+
+func (m *LintTemplatesResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *LintTemplatesResponse) GetCatalogVersion() string {
+	if m != nil {
+		return m.CatalogVersion
+	}
+	return ""
+}
+
+func (m *LintTemplatesResponse) GetCatalogSyncedAt() string {
+	if m != nil {
+		return m.CatalogSyncedAt
+	}
+	return ""
+}
+
+func (m *LintTemplatesResponse) GetCatalogError() string {
+	if m != nil {
+		return m.CatalogError
+	}
+	return ""
+}
+
+type AdapterCapabilitiesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AdapterCapabilitiesRequest) Reset()         { *m = AdapterCapabilitiesRequest{} }
+func (m *AdapterCapabilitiesRequest) String() string { return proto.CompactTextString(m) }
+func (*AdapterCapabilitiesRequest) ProtoMessage()    {}
+func (*AdapterCapabilitiesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{45}
+}
+func (m *AdapterCapabilitiesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdapterCapabilitiesRequest.Unmarshal(m, b)
+}
+func (m *AdapterCapabilitiesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdapterCapabilitiesRequest.Marshal(b, m, deterministic)
+}
+func (dst *AdapterCapabilitiesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdapterCapabilitiesRequest.Merge(dst, src)
+}
+func (m *AdapterCapabilitiesRequest) XXX_Size() int {
+	return xxx_messageInfo_AdapterCapabilitiesRequest.Size(m)
+}
+func (m *AdapterCapabilitiesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdapterCapabilitiesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdapterCapabilitiesRequest proto.InternalMessageInfo
+
+type AdapterCapabilitiesResponse struct {
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	// bumped when the meaning of the descriptor changes
+	DescriptorVersion int32 `protobuf:"varint,3,opt,name=descriptor_version,json=descriptorVersion,proto3" json:"descriptor_version,omitempty"`
+	// the MeshService rpcs the adapter implements
+	Rpcs       []string              `protobuf:"bytes,4,rep,name=rpcs,proto3" json:"rpcs,omitempty"`
+	Operations []*SupportedOperation `protobuf:"bytes,5,rep,name=operations,proto3" json:"operations,omitempty"`
+	// the operations left out because their templates are broken
+	DisabledOperations []string `protobuf:"bytes,6,rep,name=disabled_operations,json=disabledOperations,proto3" json:"disabled_operations,omitempty"`
+	// how events are streamed and what they carry, e.g. events_sse or operation_ids
+	Streaming []string `protobuf:"bytes,7,rep,name=streaming,proto3" json:"streaming,omitempty"`
+	// whether operations can target named clusters
+	MultiCluster bool `protobuf:"varint,8,opt,name=multi_cluster,json=multiCluster,proto3" json:"multi_cluster,omitempty"`
+	// the ways CreateMeshInstance authenticates to a cluster, e.g. kubeconfig or service_account
+	AuthModes []string `protobuf:"bytes,9,rep,name=auth_modes,json=authModes,proto3" json:"auth_modes,omitempty"`
+	// whether the adapter runs Service Mesh Interface conformance tests
+	Smi bool `protobuf:"varint,10,opt,name=smi,proto3" json:"smi,omitempty"`
+	// whether the adapter reports Service Mesh Performance results
+	Smp   bool   `protobuf:"varint,11,opt,name=smp,proto3" json:"smp,omitempty"`
+	Error string `protobuf:"bytes,12,opt,name=error,proto3" json:"error,omitempty"`
+	// the link to the default cluster, named "", and to the registered clusters
+	Clusters             []*ClusterConnection `protobuf:"bytes,13,rep,name=clusters,proto3" json:"clusters,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *AdapterCapabilitiesResponse) Reset()         { *m = AdapterCapabilitiesResponse{} }
+func (m *AdapterCapabilitiesResponse) String() string { return proto.CompactTextString(m) }
+func (*AdapterCapabilitiesResponse) ProtoMessage()    {}
+func (*AdapterCapabilitiesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{46}
+}
+func (m *AdapterCapabilitiesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdapterCapabilitiesResponse.Unmarshal(m, b)
+}
+func (m *AdapterCapabilitiesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdapterCapabilitiesResponse.Marshal(b, m, deterministic)
+}
+func (dst *AdapterCapabilitiesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdapterCapabilitiesResponse.Merge(dst, src)
+}
+func (m *AdapterCapabilitiesResponse) XXX_Size() int {
+	return xxx_messageInfo_AdapterCapabilitiesResponse.Size(m)
+}
+func (m *AdapterCapabilitiesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdapterCapabilitiesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdapterCapabilitiesResponse proto.InternalMessageInfo
+
+func (m *AdapterCapabilitiesResponse) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AdapterCapabilitiesResponse) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *AdapterCapabilitiesResponse) GetDescriptorVersion() int32 {
+	if m != nil {
+		return m.DescriptorVersion
+	}
+	return 0
+}
+
+func (m *AdapterCapabilitiesResponse) GetRpcs() []string {
+	if m != nil {
+		return m.Rpcs
+	}
+	return nil
+}
+
+func (m *AdapterCapabilitiesResponse) GetOperations() []*SupportedOperation {
+	if m != nil {
+		return m.Operations
+	}
+	return nil
+}
+
+func (m *AdapterCapabilitiesResponse) GetDisabledOperations() []string {
+	if m != nil {
+		return m.DisabledOperations
+	}
+	return nil
+}
+
+func (m *AdapterCapabilitiesResponse) GetStreaming() []string {
+	if m != nil {
+		return m.Streaming
+	}
+	return nil
+}
+
+func (m *AdapterCapabilitiesResponse) GetMultiCluster() bool {
+	if m != nil {
+		return m.MultiCluster
+	}
+	return false
+}
+
+func (m *AdapterCapabilitiesResponse) GetAuthModes() []string {
+	if m != nil {
+		return m.AuthModes
+	}
+	return nil
+}
+
+func (m *AdapterCapabilitiesResponse) GetSmi() bool {
+	if m != nil {
+		return m.Smi
+	}
+	return false
+}
+
+func (m *AdapterCapabilitiesResponse) GetSmp() bool {
+	if m != nil {
+		return m.Smp
+	}
+	return false
+}
+
+func (m *AdapterCapabilitiesResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *AdapterCapabilitiesResponse) GetClusters() []*ClusterConnection {
+	if m != nil {
+		return m.Clusters
+	}
+	return nil
+}
+
+type InventoryRequest struct {
+	// only the resources of this namespace
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// only the resources the operation of this id applied
+	OperationId          string   `protobuf:"bytes,2,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	PageSize             int32    `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken            string   `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InventoryRequest) Reset()         { *m = InventoryRequest{} }
+func (m *InventoryRequest) String() string { return proto.CompactTextString(m) }
+func (*InventoryRequest) ProtoMessage()    {}
+func (*InventoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{47}
+}
+func (m *InventoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InventoryRequest.Unmarshal(m, b)
+}
+func (m *InventoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InventoryRequest.Marshal(b, m, deterministic)
+}
+func (dst *InventoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InventoryRequest.Merge(dst, src)
+}
+func (m *InventoryRequest) XXX_Size() int {
+	return xxx_messageInfo_InventoryRequest.Size(m)
+}
+func (m *InventoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InventoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InventoryRequest proto.InternalMessageInfo
+
+func (m *InventoryRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *InventoryRequest) GetOperationId() string {
+	if m != nil {
+		return m.OperationId
+	}
+	return ""
+}
+
+func (m *InventoryRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *InventoryRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+// InventoryResponse lists the resources the adapter manages in the cluster
+type InventoryResponse struct {
+	Resources            []*InventoryResource `protobuf:"bytes,1,rep,name=resources,proto3" json:"resources,omitempty"`
+	Error                string               `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	NextPageToken        string               `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *InventoryResponse) Reset()         { *m = InventoryResponse{} }
+func (m *InventoryResponse) String() string { return proto.CompactTextString(m) }
+func (*InventoryResponse) ProtoMessage()    {}
+func (*InventoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{48}
+}
+func (m *InventoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InventoryResponse.Unmarshal(m, b)
+}
+func (m *InventoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InventoryResponse.Marshal(b, m, deterministic)
+}
+func (dst *InventoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InventoryResponse.Merge(dst, src)
+}
+func (m *InventoryResponse) XXX_Size() int {
+	return xxx_messageInfo_InventoryResponse.Size(m)
+}
+func (m *InventoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_InventoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InventoryResponse proto.InternalMessageInfo
+
+func (m *InventoryResponse) GetResources() []*InventoryResource {
+	if m != nil {
+		return m.Resources
+	}
+	return nil
+}
+
+func (m *InventoryResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *InventoryResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+type InventoryResource struct {
+	ApiVersion string `protobuf:"bytes,1,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	Kind       string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Namespace  string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name       string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	// the operation that applied the resource, with its id when the inventory recorded it
+	Operation   string `protobuf:"bytes,5,opt,name=operation,proto3" json:"operation,omitempty"`
+	OperationId string `protobuf:"bytes,6,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	// when the resource was applied, or created when the inventory didn't record it, in RFC 3339
+	AppliedAt string `protobuf:"bytes,7,opt,name=applied_at,json=appliedAt,proto3" json:"applied_at,omitempty"`
+	// healthy, progressing, degraded, missing, or unknown when it could not be read
+	Health       string `protobuf:"bytes,8,opt,name=health,proto3" json:"health,omitempty"`
+	HealthReason string `protobuf:"bytes,9,opt,name=health_reason,json=healthReason,proto3" json:"health_reason,omitempty"`
+	// the deployment the resource belongs to, if any
+	Deployment           string   `protobuf:"bytes,10,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InventoryResource) Reset()         { *m = InventoryResource{} }
+func (m *InventoryResource) String() string { return proto.CompactTextString(m) }
+func (*InventoryResource) ProtoMessage()    {}
+func (*InventoryResource) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{49}
+}
+func (m *InventoryResource) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InventoryResource.Unmarshal(m, b)
+}
+func (m *InventoryResource) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InventoryResource.Marshal(b, m, deterministic)
+}
+func (dst *InventoryResource) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InventoryResource.Merge(dst, src)
+}
+func (m *InventoryResource) XXX_Size() int {
+	return xxx_messageInfo_InventoryResource.Size(m)
+}
+func (m *InventoryResource) XXX_DiscardUnknown() {
+	xxx_messageInfo_InventoryResource.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InventoryResource proto.InternalMessageInfo
+
+func (m *InventoryResource) GetApiVersion() string {
+	if m != nil {
+		return m.ApiVersion
+	}
+	return ""
+}
+
+func (m *InventoryResource) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *InventoryResource) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *InventoryResource) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *InventoryResource) GetOperation() string {
+	if m != nil {
+		return m.Operation
+	}
+	return ""
+}
+
+func (m *InventoryResource) GetOperationId() string {
+	if m != nil {
+		return m.OperationId
+	}
+	return ""
+}
+
+func (m *InventoryResource) GetAppliedAt() string {
+	if m != nil {
+		return m.AppliedAt
+	}
+	return ""
+}
+
+func (m *InventoryResource) GetHealth() string {
+	if m != nil {
+		return m.Health
+	}
+	return ""
+}
+
+func (m *InventoryResource) GetHealthReason() string {
+	if m != nil {
+		return m.HealthReason
+	}
+	return ""
+}
+
+func (m *InventoryResource) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+// ClusterConnection is the state of the link of the adapter to a cluster: connected, degraded while its checks
+// fail, or disconnected once they failed OCTARINE_CONNECTIVITY_FAILURES times in a row
+type ClusterConnection struct {
+	Cluster string `protobuf:"bytes,1,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	State   string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	// when the checks started failing
+	Since                string   `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	Error                string   `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	ReconnectAttempts    int32    `protobuf:"varint,5,opt,name=reconnect_attempts,json=reconnectAttempts,proto3" json:"reconnect_attempts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClusterConnection) Reset()         { *m = ClusterConnection{} }
+func (m *ClusterConnection) String() string { return proto.CompactTextString(m) }
+func (*ClusterConnection) ProtoMessage()    {}
+func (*ClusterConnection) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{50}
+}
+func (m *ClusterConnection) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClusterConnection.Unmarshal(m, b)
+}
+func (m *ClusterConnection) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClusterConnection.Marshal(b, m, deterministic)
+}
+func (dst *ClusterConnection) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterConnection.Merge(dst, src)
+}
+func (m *ClusterConnection) XXX_Size() int {
+	return xxx_messageInfo_ClusterConnection.Size(m)
+}
+func (m *ClusterConnection) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterConnection.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterConnection proto.InternalMessageInfo
+
+func (m *ClusterConnection) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+func (m *ClusterConnection) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *ClusterConnection) GetSince() string {
+	if m != nil {
+		return m.Since
+	}
+	return ""
+}
+
+func (m *ClusterConnection) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ClusterConnection) GetReconnectAttempts() int32 {
+	if m != nil {
+		return m.ReconnectAttempts
+	}
+	return 0
+}
+
+// RenderOperationRequest takes the fields of an ApplyRuleRequest, the operation is rendered and not applied
+type RenderOperationRequest struct {
+	OpName               string            `protobuf:"bytes,1,opt,name=opName,proto3" json:"opName,omitempty"`
+	Namespace            string            `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Username             string            `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	CustomBody           string            `protobuf:"bytes,4,opt,name=custom_body,json=customBody,proto3" json:"custom_body,omitempty"`
+	DeleteOp             bool              `protobuf:"varint,5,opt,name=delete_op,json=deleteOp,proto3" json:"delete_op,omitempty"`
+	Cluster              string            `protobuf:"bytes,6,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	Parameters           map[string]string `protobuf:"bytes,7,rep,name=parameters,proto3" json:"parameters,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *RenderOperationRequest) Reset()         { *m = RenderOperationRequest{} }
+func (m *RenderOperationRequest) String() string { return proto.CompactTextString(m) }
+func (*RenderOperationRequest) ProtoMessage()    {}
+func (*RenderOperationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{51}
+}
+func (m *RenderOperationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RenderOperationRequest.Unmarshal(m, b)
+}
+func (m *RenderOperationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RenderOperationRequest.Marshal(b, m, deterministic)
+}
+func (dst *RenderOperationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RenderOperationRequest.Merge(dst, src)
+}
+func (m *RenderOperationRequest) XXX_Size() int {
+	return xxx_messageInfo_RenderOperationRequest.Size(m)
+}
+func (m *RenderOperationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RenderOperationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RenderOperationRequest proto.InternalMessageInfo
+
+func (m *RenderOperationRequest) GetOpName() string {
+	if m != nil {
+		return m.OpName
+	}
+	return ""
+}
+
+func (m *RenderOperationRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *RenderOperationRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *RenderOperationRequest) GetCustomBody() string {
+	if m != nil {
+		return m.CustomBody
+	}
+	return ""
+}
+
+func (m *RenderOperationRequest) GetDeleteOp() bool {
+	if m != nil {
+		return m.DeleteOp
+	}
+	return false
+}
+
+func (m *RenderOperationRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+func (m *RenderOperationRequest) GetParameters() map[string]string {
+	if m != nil {
+		return m.Parameters
+	}
+	return nil
+}
+
+type RenderOperationResponse struct {
+	// the objects the operation would apply, or delete, as YAML documents with their fields sorted
+	Manifest string `protobuf:"bytes,1,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	// the namespace the objects are applied in, which replaces their own
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// the kind, namespace and name of every object
+	Objects              []string `protobuf:"bytes,3,rep,name=objects,proto3" json:"objects,omitempty"`
+	Error                string   `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RenderOperationResponse) Reset()         { *m = RenderOperationResponse{} }
+func (m *RenderOperationResponse) String() string { return proto.CompactTextString(m) }
+func (*RenderOperationResponse) ProtoMessage()    {}
+func (*RenderOperationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{52}
+}
+func (m *RenderOperationResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RenderOperationResponse.Unmarshal(m, b)
+}
+func (m *RenderOperationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RenderOperationResponse.Marshal(b, m, deterministic)
+}
+func (dst *RenderOperationResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RenderOperationResponse.Merge(dst, src)
+}
+func (m *RenderOperationResponse) XXX_Size() int {
+	return xxx_messageInfo_RenderOperationResponse.Size(m)
+}
+func (m *RenderOperationResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RenderOperationResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RenderOperationResponse proto.InternalMessageInfo
+
+func (m *RenderOperationResponse) GetManifest() string {
+	if m != nil {
+		return m.Manifest
+	}
+	return ""
+}
+
+func (m *RenderOperationResponse) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *RenderOperationResponse) GetObjects() []string {
+	if m != nil {
+		return m.Objects
+	}
+	return nil
+}
+
+func (m *RenderOperationResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type WorkloadIdentitiesRequest struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// the deployment whose sidecars give workloads their Octarine identity, may be empty when there is only one
+	Deployment           string   `protobuf:"bytes,2,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	PageSize             int32    `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken            string   `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WorkloadIdentitiesRequest) Reset()         { *m = WorkloadIdentitiesRequest{} }
+func (m *WorkloadIdentitiesRequest) String() string { return proto.CompactTextString(m) }
+func (*WorkloadIdentitiesRequest) ProtoMessage()    {}
+func (*WorkloadIdentitiesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{53}
+}
+func (m *WorkloadIdentitiesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkloadIdentitiesRequest.Unmarshal(m, b)
+}
+func (m *WorkloadIdentitiesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkloadIdentitiesRequest.Marshal(b, m, deterministic)
+}
+func (dst *WorkloadIdentitiesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkloadIdentitiesRequest.Merge(dst, src)
+}
+func (m *WorkloadIdentitiesRequest) XXX_Size() int {
+	return xxx_messageInfo_WorkloadIdentitiesRequest.Size(m)
+}
+func (m *WorkloadIdentitiesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkloadIdentitiesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkloadIdentitiesRequest proto.InternalMessageInfo
+
+func (m *WorkloadIdentitiesRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *WorkloadIdentitiesRequest) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+func (m *WorkloadIdentitiesRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *WorkloadIdentitiesRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+// WorkloadIdentitiesResponse is the security posture of the workloads of a namespace, the service accounts and
+// registries sum up the whole namespace while the workloads are paged
+type WorkloadIdentitiesResponse struct {
+	Workloads            []*WorkloadIdentity    `protobuf:"bytes,1,rep,name=workloads,proto3" json:"workloads,omitempty"`
+	ServiceAccounts      []*ServiceAccountUsage `protobuf:"bytes,2,rep,name=service_accounts,json=serviceAccounts,proto3" json:"service_accounts,omitempty"`
+	Registries           []*RegistryUsage       `protobuf:"bytes,3,rep,name=registries,proto3" json:"registries,omitempty"`
+	Error                string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	NextPageToken        string                 `protobuf:"bytes,5,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *WorkloadIdentitiesResponse) Reset()         { *m = WorkloadIdentitiesResponse{} }
+func (m *WorkloadIdentitiesResponse) String() string { return proto.CompactTextString(m) }
+func (*WorkloadIdentitiesResponse) ProtoMessage()    {}
+func (*WorkloadIdentitiesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{54}
+}
+func (m *WorkloadIdentitiesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkloadIdentitiesResponse.Unmarshal(m, b)
+}
+func (m *WorkloadIdentitiesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkloadIdentitiesResponse.Marshal(b, m, deterministic)
+}
+func (dst *WorkloadIdentitiesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkloadIdentitiesResponse.Merge(dst, src)
+}
+func (m *WorkloadIdentitiesResponse) XXX_Size() int {
+	return xxx_messageInfo_WorkloadIdentitiesResponse.Size(m)
+}
+func (m *WorkloadIdentitiesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkloadIdentitiesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkloadIdentitiesResponse proto.InternalMessageInfo
+
+func (m *WorkloadIdentitiesResponse) GetWorkloads() []*WorkloadIdentity {
+	if m != nil {
+		return m.Workloads
+	}
+	return nil
+}
+
+func (m *WorkloadIdentitiesResponse) GetServiceAccounts() []*ServiceAccountUsage {
+	if m != nil {
+		return m.ServiceAccounts
+	}
+	return nil
+}
+
+func (m *WorkloadIdentitiesResponse) GetRegistries() []*RegistryUsage {
+	if m != nil {
+		return m.Registries
+	}
+	return nil
+}
+
+func (m *WorkloadIdentitiesResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *WorkloadIdentitiesResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+type WorkloadIdentity struct {
+	Namespace      string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Kind           string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Name           string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Pods           int32  `protobuf:"varint,4,opt,name=pods,proto3" json:"pods,omitempty"`
+	ServiceAccount string `protobuf:"bytes,5,opt,name=service_account,json=serviceAccount,proto3" json:"service_account,omitempty"`
+	// whether the token of the service account is mounted into the pods
+	AutomountToken bool     `protobuf:"varint,6,opt,name=automount_token,json=automountToken,proto3" json:"automount_token,omitempty"`
+	Images         []string `protobuf:"bytes,7,rep,name=images,proto3" json:"images,omitempty"`
+	// a container of the workload, besides the ones of the dataplane, is privileged
+	Privileged  bool `protobuf:"varint,8,opt,name=privileged,proto3" json:"privileged,omitempty"`
+	HostNetwork bool `protobuf:"varint,9,opt,name=host_network,json=hostNetwork,proto3" json:"host_network,omitempty"`
+	HostPid     bool `protobuf:"varint,10,opt,name=host_pid,json=hostPid,proto3" json:"host_pid,omitempty"`
+	HostIpc     bool `protobuf:"varint,11,opt,name=host_ipc,json=hostIpc,proto3" json:"host_ipc,omitempty"`
+	// everything the pods may do beyond an unprivileged container, e.g. added capabilities or host paths
+	Privileges []string `protobuf:"bytes,12,rep,name=privileges,proto3" json:"privileges,omitempty"`
+	// every pod of the workload carries the sidecar of the deployment
+	OctarineIdentity     bool     `protobuf:"varint,13,opt,name=octarine_identity,json=octarineIdentity,proto3" json:"octarine_identity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WorkloadIdentity) Reset()         { *m = WorkloadIdentity{} }
+func (m *WorkloadIdentity) String() string { return proto.CompactTextString(m) }
+func (*WorkloadIdentity) ProtoMessage()    {}
+func (*WorkloadIdentity) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{55}
+}
+func (m *WorkloadIdentity) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkloadIdentity.Unmarshal(m, b)
+}
+func (m *WorkloadIdentity) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkloadIdentity.Marshal(b, m, deterministic)
+}
+func (dst *WorkloadIdentity) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkloadIdentity.Merge(dst, src)
+}
+func (m *WorkloadIdentity) XXX_Size() int {
+	return xxx_messageInfo_WorkloadIdentity.Size(m)
+}
+func (m *WorkloadIdentity) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkloadIdentity.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkloadIdentity proto.InternalMessageInfo
+
+func (m *WorkloadIdentity) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *WorkloadIdentity) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *WorkloadIdentity) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *WorkloadIdentity) GetPods() int32 {
+	if m != nil {
+		return m.Pods
+	}
+	return 0
+}
+
+func (m *WorkloadIdentity) GetServiceAccount() string {
+	if m != nil {
+		return m.ServiceAccount
+	}
+	return ""
+}
+
+func (m *WorkloadIdentity) GetAutomountToken() bool {
+	if m != nil {
+		return m.AutomountToken
+	}
+	return false
+}
+
+func (m *WorkloadIdentity) GetImages() []string {
+	if m != nil {
+		return m.Images
+	}
+	return nil
+}
+
+func (m *WorkloadIdentity) GetPrivileged() bool {
+	if m != nil {
+		return m.Privileged
+	}
+	return false
+}
+
+func (m *WorkloadIdentity) GetHostNetwork() bool {
+	if m != nil {
+		return m.HostNetwork
+	}
+	return false
+}
+
+func (m *WorkloadIdentity) GetHostPid() bool {
+	if m != nil {
+		return m.HostPid
+	}
+	return false
+}
+
+func (m *WorkloadIdentity) GetHostIpc() bool {
+	if m != nil {
+		return m.HostIpc
+	}
+	return false
+}
+
+func (m *WorkloadIdentity) GetPrivileges() []string {
+	if m != nil {
+		return m.Privileges
+	}
+	return nil
+}
+
+func (m *WorkloadIdentity) GetOctarineIdentity() bool {
+	if m != nil {
+		return m.OctarineIdentity
+	}
+	return false
+}
+
+type ServiceAccountUsage struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// the kind/name of the workloads running as the service account
+	Workloads []string `protobuf:"bytes,2,rep,name=workloads,proto3" json:"workloads,omitempty"`
+	// false when workloads refer to a service account which doesn't exist
+	Exists               bool     `protobuf:"varint,3,opt,name=exists,proto3" json:"exists,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ServiceAccountUsage) Reset()         { *m = ServiceAccountUsage{} }
+func (m *ServiceAccountUsage) String() string { return proto.CompactTextString(m) }
+func (*ServiceAccountUsage) ProtoMessage()    {}
+func (*ServiceAccountUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{56}
+}
+func (m *ServiceAccountUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServiceAccountUsage.Unmarshal(m, b)
+}
+func (m *ServiceAccountUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServiceAccountUsage.Marshal(b, m, deterministic)
+}
+func (dst *ServiceAccountUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServiceAccountUsage.Merge(dst, src)
+}
+func (m *ServiceAccountUsage) XXX_Size() int {
+	return xxx_messageInfo_ServiceAccountUsage.Size(m)
+}
+func (m *ServiceAccountUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServiceAccountUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServiceAccountUsage proto.InternalMessageInfo
+
+func (m *ServiceAccountUsage) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ServiceAccountUsage) GetWorkloads() []string {
+	if m != nil {
+		return m.Workloads
+	}
+	return nil
+}
+
+func (m *ServiceAccountUsage) GetExists() bool {
+	if m != nil {
+		return m.Exists
+	}
+	return false
+}
+
+type RegistryUsage struct {
+	Registry             string   `protobuf:"bytes,1,opt,name=registry,proto3" json:"registry,omitempty"`
+	Images               []string `protobuf:"bytes,2,rep,name=images,proto3" json:"images,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegistryUsage) Reset()         { *m = RegistryUsage{} }
+func (m *RegistryUsage) String() string { return proto.CompactTextString(m) }
+func (*RegistryUsage) ProtoMessage()    {}
+func (*RegistryUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{57}
+}
+func (m *RegistryUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegistryUsage.Unmarshal(m, b)
+}
+func (m *RegistryUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegistryUsage.Marshal(b, m, deterministic)
+}
+func (dst *RegistryUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegistryUsage.Merge(dst, src)
+}
+func (m *RegistryUsage) XXX_Size() int {
+	return xxx_messageInfo_RegistryUsage.Size(m)
+}
+func (m *RegistryUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegistryUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegistryUsage proto.InternalMessageInfo
+
+func (m *RegistryUsage) GetRegistry() string {
+	if m != nil {
+		return m.Registry
+	}
+	return ""
+}
+
+func (m *RegistryUsage) GetImages() []string {
+	if m != nil {
+		return m.Images
+	}
+	return nil
+}
+
+type VetReportRequest struct {
+	// the deployment whose last vet is reported, the only one when empty
+	Deployment           string   `protobuf:"bytes,1,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VetReportRequest) Reset()         { *m = VetReportRequest{} }
+func (m *VetReportRequest) String() string { return proto.CompactTextString(m) }
+func (*VetReportRequest) ProtoMessage()    {}
+func (*VetReportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{58}
+}
+func (m *VetReportRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VetReportRequest.Unmarshal(m, b)
+}
+func (m *VetReportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VetReportRequest.Marshal(b, m, deterministic)
+}
+func (dst *VetReportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VetReportRequest.Merge(dst, src)
+}
+func (m *VetReportRequest) XXX_Size() int {
+	return xxx_messageInfo_VetReportRequest.Size(m)
+}
+func (m *VetReportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VetReportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VetReportRequest proto.InternalMessageInfo
+
+func (m *VetReportRequest) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+type VetReportResponse struct {
+	Deployment string `protobuf:"bytes,1,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	// RFC 3339 time of the last vet of the deployment
+	VettedAt string            `protobuf:"bytes,2,opt,name=vetted_at,json=vettedAt,proto3" json:"vetted_at,omitempty"`
+	Checks   []*VetCheckResult `protobuf:"bytes,3,rep,name=checks,proto3" json:"checks,omitempty"`
+	// the number of checks which failed, advisory ones included
+	Failed               int32    `protobuf:"varint,4,opt,name=failed,proto3" json:"failed,omitempty"`
+	Error                string   `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VetReportResponse) Reset()         { *m = VetReportResponse{} }
+func (m *VetReportResponse) String() string { return proto.CompactTextString(m) }
+func (*VetReportResponse) ProtoMessage()    {}
+func (*VetReportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{59}
+}
+func (m *VetReportResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VetReportResponse.Unmarshal(m, b)
+}
+func (m *VetReportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VetReportResponse.Marshal(b, m, deterministic)
+}
+func (dst *VetReportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VetReportResponse.Merge(dst, src)
+}
+func (m *VetReportResponse) XXX_Size() int {
+	return xxx_messageInfo_VetReportResponse.Size(m)
+}
+func (m *VetReportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_VetReportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VetReportResponse proto.InternalMessageInfo
+
+func (m *VetReportResponse) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+func (m *VetReportResponse) GetVettedAt() string {
+	if m != nil {
+		return m.VettedAt
+	}
+	return ""
+}
+
+func (m *VetReportResponse) GetChecks() []*VetCheckResult {
+	if m != nil {
+		return m.Checks
+	}
+	return nil
+}
+
+func (m *VetReportResponse) GetFailed() int32 {
+	if m != nil {
+		return m.Failed
+	}
+	return 0
+}
+
+func (m *VetReportResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type VetCheckResult struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// set for the checks of a single namespace
+	Namespace string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Passed    bool   `protobuf:"varint,3,opt,name=passed,proto3" json:"passed,omitempty"`
+	Failure   string `protobuf:"bytes,4,opt,name=failure,proto3" json:"failure,omitempty"`
+	// high, medium or low
+	Severity string `protobuf:"bytes,5,opt,name=severity,proto3" json:"severity,omitempty"`
+	// the benchmark recommendations and documentation behind the check
+	References []string `protobuf:"bytes,6,rep,name=references,proto3" json:"references,omitempty"`
+	// advisory checks, e.g. the CIS benchmark ones, don't gate switching to enforce
+	Advisory bool `protobuf:"varint,7,opt,name=advisory,proto3" json:"advisory,omitempty"`
+	// why the check could not be run, it neither passed nor failed
+	Skipped              string   `protobuf:"bytes,8,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VetCheckResult) Reset()         { *m = VetCheckResult{} }
+func (m *VetCheckResult) String() string { return proto.CompactTextString(m) }
+func (*VetCheckResult) ProtoMessage()    {}
+func (*VetCheckResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{60}
+}
+func (m *VetCheckResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VetCheckResult.Unmarshal(m, b)
+}
+func (m *VetCheckResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VetCheckResult.Marshal(b, m, deterministic)
+}
+func (dst *VetCheckResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VetCheckResult.Merge(dst, src)
+}
+func (m *VetCheckResult) XXX_Size() int {
+	return xxx_messageInfo_VetCheckResult.Size(m)
+}
+func (m *VetCheckResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_VetCheckResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VetCheckResult proto.InternalMessageInfo
+
+func (m *VetCheckResult) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *VetCheckResult) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *VetCheckResult) GetPassed() bool {
+	if m != nil {
+		return m.Passed
+	}
+	return false
+}
+
+func (m *VetCheckResult) GetFailure() string {
+	if m != nil {
+		return m.Failure
+	}
+	return ""
+}
+
+func (m *VetCheckResult) GetSeverity() string {
+	if m != nil {
+		return m.Severity
+	}
+	return ""
+}
+
+func (m *VetCheckResult) GetReferences() []string {
+	if m != nil {
+		return m.References
+	}
+	return nil
+}
+
+func (m *VetCheckResult) GetAdvisory() bool {
+	if m != nil {
+		return m.Advisory
+	}
+	return false
+}
+
+func (m *VetCheckResult) GetSkipped() string {
+	if m != nil {
+		return m.Skipped
+	}
+	return ""
+}
+
+type LatencyProbeReportRequest struct {
+	// the injected namespace the probe ran in
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LatencyProbeReportRequest) Reset()         { *m = LatencyProbeReportRequest{} }
+func (m *LatencyProbeReportRequest) String() string { return proto.CompactTextString(m) }
+func (*LatencyProbeReportRequest) ProtoMessage()    {}
+func (*LatencyProbeReportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{61}
+}
+func (m *LatencyProbeReportRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LatencyProbeReportRequest.Unmarshal(m, b)
+}
+func (m *LatencyProbeReportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LatencyProbeReportRequest.Marshal(b, m, deterministic)
+}
+func (dst *LatencyProbeReportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LatencyProbeReportRequest.Merge(dst, src)
+}
+func (m *LatencyProbeReportRequest) XXX_Size() int {
+	return xxx_messageInfo_LatencyProbeReportRequest.Size(m)
+}
+func (m *LatencyProbeReportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LatencyProbeReportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LatencyProbeReportRequest proto.InternalMessageInfo
+
+func (m *LatencyProbeReportRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type LatencyProbeReportResponse struct {
+	Namespace   string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Deployment  string `protobuf:"bytes,2,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	OperationId string `protobuf:"bytes,3,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	// RFC 3339 time the probe finished
+	ProbedAt string `protobuf:"bytes,4,opt,name=probed_at,json=probedAt,proto3" json:"probed_at,omitempty"`
+	// the load both sides were put under
+	Duration    string `protobuf:"bytes,5,opt,name=duration,proto3" json:"duration,omitempty"`
+	Connections int32  `protobuf:"varint,6,opt,name=connections,proto3" json:"connections,omitempty"`
+	// the requested rate, 0 for as fast as possible
+	Qps int32 `protobuf:"varint,7,opt,name=qps,proto3" json:"qps,omitempty"`
+	// echo services without sidecars, in a temporary namespace
+	Plain *LatencyResult `protobuf:"bytes,8,opt,name=plain,proto3" json:"plain,omitempty"`
+	// echo services with sidecars, in the injected namespace
+	Meshed *LatencyResult `protobuf:"bytes,9,opt,name=meshed,proto3" json:"meshed,omitempty"`
+	// what the sidecars add, meshed minus plain
+	P50OverheadMs float64 `protobuf:"fixed64,10,opt,name=p50_overhead_ms,json=p50OverheadMs,proto3" json:"p50_overhead_ms,omitempty"`
+	P95OverheadMs float64 `protobuf:"fixed64,11,opt,name=p95_overhead_ms,json=p95OverheadMs,proto3" json:"p95_overhead_ms,omitempty"`
+	P99OverheadMs float64 `protobuf:"fixed64,12,opt,name=p99_overhead_ms,json=p99OverheadMs,proto3" json:"p99_overhead_ms,omitempty"`
+	// the change of the throughput, negative when the sidecars slow it down
+	ThroughputDeltaPercent float64  `protobuf:"fixed64,13,opt,name=throughput_delta_percent,json=throughputDeltaPercent,proto3" json:"throughput_delta_percent,omitempty"`
+	Error                  string   `protobuf:"bytes,14,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral   struct{} `json:"-"`
+	XXX_unrecognized       []byte   `json:"-"`
+	XXX_sizecache          int32    `json:"-"`
+}
+
+func (m *LatencyProbeReportResponse) Reset()         { *m = LatencyProbeReportResponse{} }
+func (m *LatencyProbeReportResponse) String() string { return proto.CompactTextString(m) }
+func (*LatencyProbeReportResponse) ProtoMessage()    {}
+func (*LatencyProbeReportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{62}
+}
+func (m *LatencyProbeReportResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LatencyProbeReportResponse.Unmarshal(m, b)
+}
+func (m *LatencyProbeReportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LatencyProbeReportResponse.Marshal(b, m, deterministic)
+}
+func (dst *LatencyProbeReportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LatencyProbeReportResponse.Merge(dst, src)
+}
+func (m *LatencyProbeReportResponse) XXX_Size() int {
+	return xxx_messageInfo_LatencyProbeReportResponse.Size(m)
+}
+func (m *LatencyProbeReportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LatencyProbeReportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LatencyProbeReportResponse proto.InternalMessageInfo
+
+func (m *LatencyProbeReportResponse) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *LatencyProbeReportResponse) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+func (m *LatencyProbeReportResponse) GetOperationId() string {
+	if m != nil {
+		return m.OperationId
+	}
+	return ""
+}
+
+func (m *LatencyProbeReportResponse) GetProbedAt() string {
+	if m != nil {
+		return m.ProbedAt
+	}
+	return ""
+}
+
+func (m *LatencyProbeReportResponse) GetDuration() string {
+	if m != nil {
+		return m.Duration
+	}
+	return ""
+}
+
+func (m *LatencyProbeReportResponse) GetConnections() int32 {
+	if m != nil {
+		return m.Connections
+	}
+	return 0
+}
+
+func (m *LatencyProbeReportResponse) GetQps() int32 {
+	if m != nil {
+		return m.Qps
+	}
+	return 0
+}
+
+func (m *LatencyProbeReportResponse) GetPlain() *LatencyResult {
+	if m != nil {
+		return m.Plain
+	}
+	return nil
+}
+
+func (m *LatencyProbeReportResponse) GetMeshed() *LatencyResult {
+	if m != nil {
+		return m.Meshed
+	}
+	return nil
+}
+
+func (m *LatencyProbeReportResponse) GetP50OverheadMs() float64 {
+	if m != nil {
+		return m.P50OverheadMs
+	}
+	return 0
+}
+
+func (m *LatencyProbeReportResponse) GetP95OverheadMs() float64 {
+	if m != nil {
+		return m.P95OverheadMs
+	}
+	return 0
+}
+
+func (m *LatencyProbeReportResponse) GetP99OverheadMs() float64 {
+	if m != nil {
+		return m.P99OverheadMs
+	}
+	return 0
+}
+
+func (m *LatencyProbeReportResponse) GetThroughputDeltaPercent() float64 {
+	if m != nil {
+		return m.ThroughputDeltaPercent
+	}
+	return 0
+}
+
+func (m *LatencyProbeReportResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type LatencyResult struct {
+	P50Ms    float64 `protobuf:"fixed64,1,opt,name=p50_ms,json=p50Ms,proto3" json:"p50_ms,omitempty"`
+	P95Ms    float64 `protobuf:"fixed64,2,opt,name=p95_ms,json=p95Ms,proto3" json:"p95_ms,omitempty"`
+	P99Ms    float64 `protobuf:"fixed64,3,opt,name=p99_ms,json=p99Ms,proto3" json:"p99_ms,omitempty"`
+	AvgMs    float64 `protobuf:"fixed64,4,opt,name=avg_ms,json=avgMs,proto3" json:"avg_ms,omitempty"`
+	Qps      float64 `protobuf:"fixed64,5,opt,name=qps,proto3" json:"qps,omitempty"`
+	Requests int64   `protobuf:"varint,6,opt,name=requests,proto3" json:"requests,omitempty"`
+	// requests which failed or didn't get a 200
+	Errors               int64    `protobuf:"varint,7,opt,name=errors,proto3" json:"errors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LatencyResult) Reset()         { *m = LatencyResult{} }
+func (m *LatencyResult) String() string { return proto.CompactTextString(m) }
+func (*LatencyResult) ProtoMessage()    {}
+func (*LatencyResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{63}
+}
+func (m *LatencyResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LatencyResult.Unmarshal(m, b)
+}
+func (m *LatencyResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LatencyResult.Marshal(b, m, deterministic)
+}
+func (dst *LatencyResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LatencyResult.Merge(dst, src)
+}
+func (m *LatencyResult) XXX_Size() int {
+	return xxx_messageInfo_LatencyResult.Size(m)
+}
+func (m *LatencyResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_LatencyResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LatencyResult proto.InternalMessageInfo
+
+func (m *LatencyResult) GetP50Ms() float64 {
+	if m != nil {
+		return m.P50Ms
+	}
+	return 0
+}
+
+func (m *LatencyResult) GetP95Ms() float64 {
+	if m != nil {
+		return m.P95Ms
+	}
+	return 0
+}
+
+func (m *LatencyResult) GetP99Ms() float64 {
+	if m != nil {
+		return m.P99Ms
+	}
+	return 0
+}
+
+func (m *LatencyResult) GetAvgMs() float64 {
+	if m != nil {
+		return m.AvgMs
+	}
+	return 0
+}
+
+func (m *LatencyResult) GetQps() float64 {
+	if m != nil {
+		return m.Qps
+	}
+	return 0
+}
+
+func (m *LatencyResult) GetRequests() int64 {
+	if m != nil {
+		return m.Requests
+	}
+	return 0
+}
+
+func (m *LatencyResult) GetErrors() int64 {
+	if m != nil {
+		return m.Errors
+	}
+	return 0
+}
+
+type GetOperationResultRequest struct {
+	OperationId string `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	// the registered cluster the operation ran in, the default cluster when empty
+	Cluster              string   `protobuf:"bytes,2,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetOperationResultRequest) Reset()         { *m = GetOperationResultRequest{} }
+func (m *GetOperationResultRequest) String() string { return proto.CompactTextString(m) }
+func (*GetOperationResultRequest) ProtoMessage()    {}
+func (*GetOperationResultRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{64}
+}
+func (m *GetOperationResultRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetOperationResultRequest.Unmarshal(m, b)
+}
+func (m *GetOperationResultRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetOperationResultRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetOperationResultRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetOperationResultRequest.Merge(dst, src)
+}
+func (m *GetOperationResultRequest) XXX_Size() int {
+	return xxx_messageInfo_GetOperationResultRequest.Size(m)
+}
+func (m *GetOperationResultRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetOperationResultRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetOperationResultRequest proto.InternalMessageInfo
+
+func (m *GetOperationResultRequest) GetOperationId() string {
+	if m != nil {
+		return m.OperationId
+	}
+	return ""
+}
+
+func (m *GetOperationResultRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type GetOperationResultResponse struct {
+	OperationId string `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	OpName      string `protobuf:"bytes,2,opt,name=op_name,json=opName,proto3" json:"op_name,omitempty"`
+	Namespace   string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Username    string `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	DeleteOp    bool   `protobuf:"varint,5,opt,name=delete_op,json=deleteOp,proto3" json:"delete_op,omitempty"`
+	// running, succeeded or failed
+	Status string `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	// RFC 3339 times, finished is empty while the operation runs
+	Started  string `protobuf:"bytes,7,opt,name=started,proto3" json:"started,omitempty"`
+	Finished string `protobuf:"bytes,8,opt,name=finished,proto3" json:"finished,omitempty"`
+	Duration string `protobuf:"bytes,9,opt,name=duration,proto3" json:"duration,omitempty"`
+	// the resources the operation applied or deleted, as kind namespace/name
+	Resources []string `protobuf:"bytes,10,rep,name=resources,proto3" json:"resources,omitempty"`
+	// the errors the operation failed with, each followed by its causes
+	Errors []string `protobuf:"bytes,11,rep,name=errors,proto3" json:"errors,omitempty"`
+	// the number of warnings the operation emitted
+	Warnings int32  `protobuf:"varint,12,opt,name=warnings,proto3" json:"warnings,omitempty"`
+	Error    string `protobuf:"bytes,13,opt,name=error,proto3" json:"error,omitempty"`
+	// the operation this one retried, and the number of manifest documents it applied or skipped as already
+	// applied, which a retry of it skips
+	ResumedFrom          string   `protobuf:"bytes,14,opt,name=resumed_from,json=resumedFrom,proto3" json:"resumed_from,omitempty"`
+	AppliedDocuments     int32    `protobuf:"varint,15,opt,name=applied_documents,json=appliedDocuments,proto3" json:"applied_documents,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetOperationResultResponse) Reset()         { *m = GetOperationResultResponse{} }
+func (m *GetOperationResultResponse) String() string { return proto.CompactTextString(m) }
+func (*GetOperationResultResponse) ProtoMessage()    {}
+func (*GetOperationResultResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{65}
+}
+func (m *GetOperationResultResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetOperationResultResponse.Unmarshal(m, b)
+}
+func (m *GetOperationResultResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetOperationResultResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetOperationResultResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetOperationResultResponse.Merge(dst, src)
+}
+func (m *GetOperationResultResponse) XXX_Size() int {
+	return xxx_messageInfo_GetOperationResultResponse.Size(m)
+}
+func (m *GetOperationResultResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetOperationResultResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetOperationResultResponse proto.InternalMessageInfo
+
+func (m *GetOperationResultResponse) GetOperationId() string {
+	if m != nil {
+		return m.OperationId
+	}
+	return ""
+}
+
+func (m *GetOperationResultResponse) GetOpName() string {
+	if m != nil {
+		return m.OpName
+	}
+	return ""
+}
+
+func (m *GetOperationResultResponse) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *GetOperationResultResponse) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *GetOperationResultResponse) GetDeleteOp() bool {
+	if m != nil {
+		return m.DeleteOp
+	}
+	return false
+}
+
+func (m *GetOperationResultResponse) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *GetOperationResultResponse) GetStarted() string {
+	if m != nil {
+		return m.Started
+	}
+	return ""
+}
+
+func (m *GetOperationResultResponse) GetFinished() string {
+	if m != nil {
+		return m.Finished
+	}
+	return ""
+}
+
+func (m *GetOperationResultResponse) GetDuration() string {
+	if m != nil {
+		return m.Duration
+	}
+	return ""
+}
+
+func (m *GetOperationResultResponse) GetResources() []string {
+	if m != nil {
+		return m.Resources
+	}
+	return nil
+}
+
+func (m *GetOperationResultResponse) GetErrors() []string {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+func (m *GetOperationResultResponse) GetWarnings() int32 {
+	if m != nil {
+		return m.Warnings
+	}
+	return 0
+}
+
+func (m *GetOperationResultResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *GetOperationResultResponse) GetResumedFrom() string {
+	if m != nil {
+		return m.ResumedFrom
+	}
+	return ""
+}
+
+func (m *GetOperationResultResponse) GetAppliedDocuments() int32 {
+	if m != nil {
+		return m.AppliedDocuments
+	}
+	return 0
+}
+
+type QueryEventsRequest struct {
+	// RFC 3339 times bounding the events, since is inclusive and until exclusive, either may be empty
+	Since       string   `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`
+	Until       string   `protobuf:"bytes,2,opt,name=until,proto3" json:"until,omitempty"`
+	MinSeverity Severity `protobuf:"varint,3,opt,name=min_severity,json=minSeverity,proto3,enum=meshes.Severity" json:"min_severity,omitempty"`
+	// the namespace of the operations which emitted the events
+	Namespace            string   `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	OperationId          string   `protobuf:"bytes,5,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	PageSize             int32    `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken            string   `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryEventsRequest) Reset()         { *m = QueryEventsRequest{} }
+func (m *QueryEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryEventsRequest) ProtoMessage()    {}
+func (*QueryEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{66}
+}
+func (m *QueryEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryEventsRequest.Unmarshal(m, b)
+}
+func (m *QueryEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryEventsRequest.Marshal(b, m, deterministic)
+}
+func (dst *QueryEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryEventsRequest.Merge(dst, src)
+}
+func (m *QueryEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_QueryEventsRequest.Size(m)
+}
+func (m *QueryEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryEventsRequest proto.InternalMessageInfo
+
+func (m *QueryEventsRequest) GetSince() string {
+	if m != nil {
+		return m.Since
+	}
+	return ""
+}
+
+func (m *QueryEventsRequest) GetUntil() string {
+	if m != nil {
+		return m.Until
+	}
+	return ""
+}
+
+func (m *QueryEventsRequest) GetMinSeverity() Severity {
+	if m != nil {
+		return m.MinSeverity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (m *QueryEventsRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *QueryEventsRequest) GetOperationId() string {
+	if m != nil {
+		return m.OperationId
+	}
+	return ""
+}
+
+func (m *QueryEventsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *QueryEventsRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+type QueryEventsResponse struct {
+	// the events in the order they were emitted
+	Events               []*StoredEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextPageToken        string         `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	Error                string         `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *QueryEventsResponse) Reset()         { *m = QueryEventsResponse{} }
+func (m *QueryEventsResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryEventsResponse) ProtoMessage()    {}
+func (*QueryEventsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{67}
+}
+func (m *QueryEventsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryEventsResponse.Unmarshal(m, b)
+}
+func (m *QueryEventsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryEventsResponse.Marshal(b, m, deterministic)
+}
+func (dst *QueryEventsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryEventsResponse.Merge(dst, src)
+}
+func (m *QueryEventsResponse) XXX_Size() int {
+	return xxx_messageInfo_QueryEventsResponse.Size(m)
+}
+func (m *QueryEventsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryEventsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryEventsResponse proto.InternalMessageInfo
+
+func (m *QueryEventsResponse) GetEvents() []*StoredEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *QueryEventsResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *QueryEventsResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type StoredEvent struct {
+	// RFC 3339 time with nanoseconds
+	Time                 string    `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	EventType            EventType `protobuf:"varint,2,opt,name=event_type,json=eventType,proto3,enum=meshes.EventType" json:"event_type,omitempty"`
+	Severity             Severity  `protobuf:"varint,3,opt,name=severity,proto3,enum=meshes.Severity" json:"severity,omitempty"`
+	Summary              string    `protobuf:"bytes,4,opt,name=summary,proto3" json:"summary,omitempty"`
+	Details              string    `protobuf:"bytes,5,opt,name=details,proto3" json:"details,omitempty"`
+	OperationId          string    `protobuf:"bytes,6,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	Namespace            string    `protobuf:"bytes,7,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Source               string    `protobuf:"bytes,8,opt,name=source,proto3" json:"source,omitempty"`
+	Sequence             uint64    `protobuf:"varint,9,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Elapsed              string    `protobuf:"bytes,10,opt,name=elapsed,proto3" json:"elapsed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *StoredEvent) Reset()         { *m = StoredEvent{} }
+func (m *StoredEvent) String() string { return proto.CompactTextString(m) }
+func (*StoredEvent) ProtoMessage()    {}
+func (*StoredEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{68}
+}
+func (m *StoredEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoredEvent.Unmarshal(m, b)
+}
+func (m *StoredEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoredEvent.Marshal(b, m, deterministic)
+}
+func (dst *StoredEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoredEvent.Merge(dst, src)
+}
+func (m *StoredEvent) XXX_Size() int {
+	return xxx_messageInfo_StoredEvent.Size(m)
+}
+func (m *StoredEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoredEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoredEvent proto.InternalMessageInfo
+
+func (m *StoredEvent) GetTime() string {
+	if m != nil {
+		return m.Time
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetEventType() EventType {
+	if m != nil {
+		return m.EventType
+	}
+	return EventType_INFO
+}
+
+func (m *StoredEvent) GetSeverity() Severity {
+	if m != nil {
+		return m.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (m *StoredEvent) GetSummary() string {
+	if m != nil {
+		return m.Summary
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetDetails() string {
+	if m != nil {
+		return m.Details
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetOperationId() string {
+	if m != nil {
+		return m.OperationId
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *StoredEvent) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *StoredEvent) GetElapsed() string {
+	if m != nil {
+		return m.Elapsed
+	}
+	return ""
+}
+
+type ListActiveOperationsRequest struct {
+	// only list the operations of this namespace
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListActiveOperationsRequest) Reset()         { *m = ListActiveOperationsRequest{} }
+func (m *ListActiveOperationsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListActiveOperationsRequest) ProtoMessage()    {}
+func (*ListActiveOperationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{69}
+}
+func (m *ListActiveOperationsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListActiveOperationsRequest.Unmarshal(m, b)
+}
+func (m *ListActiveOperationsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListActiveOperationsRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListActiveOperationsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListActiveOperationsRequest.Merge(dst, src)
+}
+func (m *ListActiveOperationsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListActiveOperationsRequest.Size(m)
+}
+func (m *ListActiveOperationsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListActiveOperationsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListActiveOperationsRequest proto.InternalMessageInfo
+
+func (m *ListActiveOperationsRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type ListActiveOperationsResponse struct {
+	// the running operations, oldest first
+	Operations           []*ActiveOperation `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	Error                string             `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ListActiveOperationsResponse) Reset()         { *m = ListActiveOperationsResponse{} }
+func (m *ListActiveOperationsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListActiveOperationsResponse) ProtoMessage()    {}
+func (*ListActiveOperationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{70}
+}
+func (m *ListActiveOperationsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListActiveOperationsResponse.Unmarshal(m, b)
+}
+func (m *ListActiveOperationsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListActiveOperationsResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListActiveOperationsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListActiveOperationsResponse.Merge(dst, src)
+}
+func (m *ListActiveOperationsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListActiveOperationsResponse.Size(m)
+}
+func (m *ListActiveOperationsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListActiveOperationsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListActiveOperationsResponse proto.InternalMessageInfo
+
+func (m *ListActiveOperationsResponse) GetOperations() []*ActiveOperation {
+	if m != nil {
+		return m.Operations
+	}
+	return nil
+}
+
+func (m *ListActiveOperationsResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type ActiveOperation struct {
+	OperationId string `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	OpName      string `protobuf:"bytes,2,opt,name=op_name,json=opName,proto3" json:"op_name,omitempty"`
+	// the registered cluster the operation runs in, empty for the default cluster
+	Cluster   string `protobuf:"bytes,3,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	Namespace string `protobuf:"bytes,4,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Username  string `protobuf:"bytes,5,opt,name=username,proto3" json:"username,omitempty"`
+	DeleteOp  bool   `protobuf:"varint,6,opt,name=delete_op,json=deleteOp,proto3" json:"delete_op,omitempty"`
+	// what the operation is doing, e.g. waiting for another operation, waiting on the rollout of deployments,
+	// or stalled
+	Phase string `protobuf:"bytes,7,opt,name=phase,proto3" json:"phase,omitempty"`
+	// RFC 3339 time
+	Started    string `protobuf:"bytes,8,opt,name=started,proto3" json:"started,omitempty"`
+	RunningFor string `protobuf:"bytes,9,opt,name=running_for,json=runningFor,proto3" json:"running_for,omitempty"`
+	// how long ago the operation last made progress
+	IdleFor              string   `protobuf:"bytes,10,opt,name=idle_for,json=idleFor,proto3" json:"idle_for,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ActiveOperation) Reset()         { *m = ActiveOperation{} }
+func (m *ActiveOperation) String() string { return proto.CompactTextString(m) }
+func (*ActiveOperation) ProtoMessage()    {}
+func (*ActiveOperation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{71}
+}
+func (m *ActiveOperation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ActiveOperation.Unmarshal(m, b)
+}
+func (m *ActiveOperation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ActiveOperation.Marshal(b, m, deterministic)
+}
+func (dst *ActiveOperation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActiveOperation.Merge(dst, src)
+}
+func (m *ActiveOperation) XXX_Size() int {
+	return xxx_messageInfo_ActiveOperation.Size(m)
+}
+func (m *ActiveOperation) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActiveOperation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActiveOperation proto.InternalMessageInfo
+
+func (m *ActiveOperation) GetOperationId() string {
+	if m != nil {
+		return m.OperationId
+	}
+	return ""
+}
+
+func (m *ActiveOperation) GetOpName() string {
+	if m != nil {
+		return m.OpName
+	}
+	return ""
+}
+
+func (m *ActiveOperation) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+func (m *ActiveOperation) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ActiveOperation) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *ActiveOperation) GetDeleteOp() bool {
+	if m != nil {
+		return m.DeleteOp
+	}
+	return false
+}
+
+func (m *ActiveOperation) GetPhase() string {
+	if m != nil {
+		return m.Phase
+	}
+	return ""
+}
+
+func (m *ActiveOperation) GetStarted() string {
+	if m != nil {
+		return m.Started
+	}
+	return ""
+}
+
+func (m *ActiveOperation) GetRunningFor() string {
+	if m != nil {
+		return m.RunningFor
+	}
+	return ""
+}
+
+func (m *ActiveOperation) GetIdleFor() string {
+	if m != nil {
+		return m.IdleFor
+	}
+	return ""
+}
+
+type PreviewTelemetryRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PreviewTelemetryRequest) Reset()         { *m = PreviewTelemetryRequest{} }
+func (m *PreviewTelemetryRequest) String() string { return proto.CompactTextString(m) }
+func (*PreviewTelemetryRequest) ProtoMessage()    {}
+func (*PreviewTelemetryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{72}
+}
+func (m *PreviewTelemetryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PreviewTelemetryRequest.Unmarshal(m, b)
+}
+func (m *PreviewTelemetryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PreviewTelemetryRequest.Marshal(b, m, deterministic)
+}
+func (dst *PreviewTelemetryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PreviewTelemetryRequest.Merge(dst, src)
+}
+func (m *PreviewTelemetryRequest) XXX_Size() int {
+	return xxx_messageInfo_PreviewTelemetryRequest.Size(m)
+}
+func (m *PreviewTelemetryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PreviewTelemetryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PreviewTelemetryRequest proto.InternalMessageInfo
+
+type PreviewTelemetryResponse struct {
+	// telemetry is only sent when OCTARINE_TELEMETRY_ENDPOINT is set
+	Enabled  bool   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Endpoint string `protobuf:"bytes,2,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	Interval string `protobuf:"bytes,3,opt,name=interval,proto3" json:"interval,omitempty"`
+	// when the next report is sent, RFC 3339, empty when telemetry is disabled
+	NextReport string `protobuf:"bytes,4,opt,name=next_report,json=nextReport,proto3" json:"next_report,omitempty"`
+	// the JSON body the next report would send if it was sent now
+	Report               string   `protobuf:"bytes,5,opt,name=report,proto3" json:"report,omitempty"`
+	Error                string   `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PreviewTelemetryResponse) Reset()         { *m = PreviewTelemetryResponse{} }
+func (m *PreviewTelemetryResponse) String() string { return proto.CompactTextString(m) }
+func (*PreviewTelemetryResponse) ProtoMessage()    {}
+func (*PreviewTelemetryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{73}
+}
+func (m *PreviewTelemetryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PreviewTelemetryResponse.Unmarshal(m, b)
+}
+func (m *PreviewTelemetryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PreviewTelemetryResponse.Marshal(b, m, deterministic)
+}
+func (dst *PreviewTelemetryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PreviewTelemetryResponse.Merge(dst, src)
+}
+func (m *PreviewTelemetryResponse) XXX_Size() int {
+	return xxx_messageInfo_PreviewTelemetryResponse.Size(m)
+}
+func (m *PreviewTelemetryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PreviewTelemetryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PreviewTelemetryResponse proto.InternalMessageInfo
+
+func (m *PreviewTelemetryResponse) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *PreviewTelemetryResponse) GetEndpoint() string {
+	if m != nil {
+		return m.Endpoint
+	}
+	return ""
+}
+
+func (m *PreviewTelemetryResponse) GetInterval() string {
+	if m != nil {
+		return m.Interval
+	}
+	return ""
+}
+
+func (m *PreviewTelemetryResponse) GetNextReport() string {
+	if m != nil {
+		return m.NextReport
+	}
+	return ""
+}
+
+func (m *PreviewTelemetryResponse) GetReport() string {
+	if m != nil {
+		return m.Report
+	}
+	return ""
+}
+
+func (m *PreviewTelemetryResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type ImageManifestsRequest struct {
+	// the Octarine release whose images are listed, the version octactl defaults to when empty
+	Version string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	// the deployment whose domain octactl renders the dataplane with, the only one when empty
+	Deployment string `protobuf:"bytes,2,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	// images to look up instead of those of the rendered dataplane
+	Images               []string `protobuf:"bytes,3,rep,name=images,proto3" json:"images,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImageManifestsRequest) Reset()         { *m = ImageManifestsRequest{} }
+func (m *ImageManifestsRequest) String() string { return proto.CompactTextString(m) }
+func (*ImageManifestsRequest) ProtoMessage()    {}
+func (*ImageManifestsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{74}
+}
+func (m *ImageManifestsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImageManifestsRequest.Unmarshal(m, b)
+}
+func (m *ImageManifestsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImageManifestsRequest.Marshal(b, m, deterministic)
+}
+func (dst *ImageManifestsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImageManifestsRequest.Merge(dst, src)
+}
+func (m *ImageManifestsRequest) XXX_Size() int {
+	return xxx_messageInfo_ImageManifestsRequest.Size(m)
+}
+func (m *ImageManifestsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImageManifestsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImageManifestsRequest proto.InternalMessageInfo
+
+func (m *ImageManifestsRequest) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *ImageManifestsRequest) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+func (m *ImageManifestsRequest) GetImages() []string {
+	if m != nil {
+		return m.Images
+	}
+	return nil
+}
+
+type ImagePlatform struct {
+	Os           string `protobuf:"bytes,1,opt,name=os,proto3" json:"os,omitempty"`
+	Architecture string `protobuf:"bytes,2,opt,name=architecture,proto3" json:"architecture,omitempty"`
+	Variant      string `protobuf:"bytes,3,opt,name=variant,proto3" json:"variant,omitempty"`
+	// the digest of the manifest of the platform, to pull or copy it by
+	Digest               string   `protobuf:"bytes,4,opt,name=digest,proto3" json:"digest,omitempty"`
+	Size_                int64    `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImagePlatform) Reset()         { *m = ImagePlatform{} }
+func (m *ImagePlatform) String() string { return proto.CompactTextString(m) }
+func (*ImagePlatform) ProtoMessage()    {}
+func (*ImagePlatform) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{75}
+}
+func (m *ImagePlatform) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImagePlatform.Unmarshal(m, b)
+}
+func (m *ImagePlatform) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImagePlatform.Marshal(b, m, deterministic)
+}
+func (dst *ImagePlatform) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImagePlatform.Merge(dst, src)
+}
+func (m *ImagePlatform) XXX_Size() int {
+	return xxx_messageInfo_ImagePlatform.Size(m)
+}
+func (m *ImagePlatform) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImagePlatform.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImagePlatform proto.InternalMessageInfo
+
+func (m *ImagePlatform) GetOs() string {
+	if m != nil {
+		return m.Os
+	}
+	return ""
+}
+
+func (m *ImagePlatform) GetArchitecture() string {
+	if m != nil {
+		return m.Architecture
+	}
+	return ""
+}
+
+func (m *ImagePlatform) GetVariant() string {
+	if m != nil {
+		return m.Variant
+	}
+	return ""
+}
+
+func (m *ImagePlatform) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
+func (m *ImagePlatform) GetSize_() int64 {
+	if m != nil {
+		return m.Size_
+	}
+	return 0
+}
+
+type ImageManifest struct {
+	Image string `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	// the digest of the manifest list or index of a multi-platform image, or of its only manifest
+	Digest    string           `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+	MediaType string           `protobuf:"bytes,3,opt,name=media_type,json=mediaType,proto3" json:"media_type,omitempty"`
+	Platforms []*ImagePlatform `protobuf:"bytes,4,rep,name=platforms,proto3" json:"platforms,omitempty"`
+	// why the manifest of the image couldn't be read, the other images are still reported
+	Error                string   `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImageManifest) Reset()         { *m = ImageManifest{} }
+func (m *ImageManifest) String() string { return proto.CompactTextString(m) }
+func (*ImageManifest) ProtoMessage()    {}
+func (*ImageManifest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{76}
+}
+func (m *ImageManifest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImageManifest.Unmarshal(m, b)
+}
+func (m *ImageManifest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImageManifest.Marshal(b, m, deterministic)
+}
+func (dst *ImageManifest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImageManifest.Merge(dst, src)
+}
+func (m *ImageManifest) XXX_Size() int {
+	return xxx_messageInfo_ImageManifest.Size(m)
+}
+func (m *ImageManifest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImageManifest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImageManifest proto.InternalMessageInfo
+
+func (m *ImageManifest) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *ImageManifest) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
+func (m *ImageManifest) GetMediaType() string {
+	if m != nil {
+		return m.MediaType
+	}
+	return ""
+}
+
+func (m *ImageManifest) GetPlatforms() []*ImagePlatform {
+	if m != nil {
+		return m.Platforms
+	}
+	return nil
+}
+
+func (m *ImageManifest) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type ImageManifestsResponse struct {
+	Version string           `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Images  []*ImageManifest `protobuf:"bytes,2,rep,name=images,proto3" json:"images,omitempty"`
+	// the platforms, as os/architecture[/variant], every image is published for
+	CommonPlatforms      []string `protobuf:"bytes,3,rep,name=common_platforms,json=commonPlatforms,proto3" json:"common_platforms,omitempty"`
+	Error                string   `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImageManifestsResponse) Reset()         { *m = ImageManifestsResponse{} }
+func (m *ImageManifestsResponse) String() string { return proto.CompactTextString(m) }
+func (*ImageManifestsResponse) ProtoMessage()    {}
+func (*ImageManifestsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{77}
+}
+func (m *ImageManifestsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImageManifestsResponse.Unmarshal(m, b)
+}
+func (m *ImageManifestsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImageManifestsResponse.Marshal(b, m, deterministic)
+}
+func (dst *ImageManifestsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImageManifestsResponse.Merge(dst, src)
+}
+func (m *ImageManifestsResponse) XXX_Size() int {
+	return xxx_messageInfo_ImageManifestsResponse.Size(m)
+}
+func (m *ImageManifestsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImageManifestsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImageManifestsResponse proto.InternalMessageInfo
+
+func (m *ImageManifestsResponse) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *ImageManifestsResponse) GetImages() []*ImageManifest {
+	if m != nil {
+		return m.Images
+	}
+	return nil
+}
+
+func (m *ImageManifestsResponse) GetCommonPlatforms() []string {
+	if m != nil {
+		return m.CommonPlatforms
+	}
+	return nil
+}
+
+func (m *ImageManifestsResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// TrashedPolicy is a copy of an Octarine policy an operation deleted, kept to be restored
+type TrashedPolicy struct {
+	Id         string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Deployment string `protobuf:"bytes,2,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	// the Kubernetes namespace of the policy, empty for the policies of the whole domain
+	Namespace string `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name      string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Kind      string `protobuf:"bytes,5,opt,name=kind,proto3" json:"kind,omitempty"`
+	// the policy as octactl printed it before deleting it
+	Manifest string `protobuf:"bytes,6,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	// RFC 3339 times, the copy is pruned after expires
+	Deleted              string   `protobuf:"bytes,7,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	Expires              string   `protobuf:"bytes,8,opt,name=expires,proto3" json:"expires,omitempty"`
+	DeletedBy            string   `protobuf:"bytes,9,opt,name=deleted_by,json=deletedBy,proto3" json:"deleted_by,omitempty"`
+	OperationId          string   `protobuf:"bytes,10,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TrashedPolicy) Reset()         { *m = TrashedPolicy{} }
+func (m *TrashedPolicy) String() string { return proto.CompactTextString(m) }
+func (*TrashedPolicy) ProtoMessage()    {}
+func (*TrashedPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{78}
+}
+func (m *TrashedPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TrashedPolicy.Unmarshal(m, b)
+}
+func (m *TrashedPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TrashedPolicy.Marshal(b, m, deterministic)
+}
+func (dst *TrashedPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrashedPolicy.Merge(dst, src)
+}
+func (m *TrashedPolicy) XXX_Size() int {
+	return xxx_messageInfo_TrashedPolicy.Size(m)
+}
+func (m *TrashedPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrashedPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrashedPolicy proto.InternalMessageInfo
+
+func (m *TrashedPolicy) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *TrashedPolicy) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+func (m *TrashedPolicy) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *TrashedPolicy) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TrashedPolicy) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *TrashedPolicy) GetManifest() string {
+	if m != nil {
+		return m.Manifest
+	}
+	return ""
+}
+
+func (m *TrashedPolicy) GetDeleted() string {
+	if m != nil {
+		return m.Deleted
+	}
+	return ""
+}
+
+func (m *TrashedPolicy) GetExpires() string {
+	if m != nil {
+		return m.Expires
+	}
+	return ""
+}
+
+func (m *TrashedPolicy) GetDeletedBy() string {
+	if m != nil {
+		return m.DeletedBy
+	}
+	return ""
+}
+
+func (m *TrashedPolicy) GetOperationId() string {
+	if m != nil {
+		return m.OperationId
+	}
+	return ""
+}
+
+type ListTrashedPoliciesRequest struct {
+	// only list the policies of this deployment, or of this namespace
+	Deployment string `protobuf:"bytes,1,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	Namespace  string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// the registered cluster the policies were deleted in, the default cluster when empty
+	Cluster              string   `protobuf:"bytes,3,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	PageSize             int32    `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken            string   `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListTrashedPoliciesRequest) Reset()         { *m = ListTrashedPoliciesRequest{} }
+func (m *ListTrashedPoliciesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListTrashedPoliciesRequest) ProtoMessage()    {}
+func (*ListTrashedPoliciesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{79}
+}
+func (m *ListTrashedPoliciesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTrashedPoliciesRequest.Unmarshal(m, b)
+}
+func (m *ListTrashedPoliciesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTrashedPoliciesRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListTrashedPoliciesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTrashedPoliciesRequest.Merge(dst, src)
+}
+func (m *ListTrashedPoliciesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListTrashedPoliciesRequest.Size(m)
+}
+func (m *ListTrashedPoliciesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTrashedPoliciesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTrashedPoliciesRequest proto.InternalMessageInfo
+
+func (m *ListTrashedPoliciesRequest) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+func (m *ListTrashedPoliciesRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ListTrashedPoliciesRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+func (m *ListTrashedPoliciesRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListTrashedPoliciesRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+type ListTrashedPoliciesResponse struct {
+	// the policies in the order they were deleted, without their manifest
+	Policies             []*TrashedPolicy `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	NextPageToken        string           `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	Error                string           `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ListTrashedPoliciesResponse) Reset()         { *m = ListTrashedPoliciesResponse{} }
+func (m *ListTrashedPoliciesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListTrashedPoliciesResponse) ProtoMessage()    {}
+func (*ListTrashedPoliciesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{80}
+}
+func (m *ListTrashedPoliciesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTrashedPoliciesResponse.Unmarshal(m, b)
+}
+func (m *ListTrashedPoliciesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTrashedPoliciesResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListTrashedPoliciesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTrashedPoliciesResponse.Merge(dst, src)
+}
+func (m *ListTrashedPoliciesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListTrashedPoliciesResponse.Size(m)
+}
+func (m *ListTrashedPoliciesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTrashedPoliciesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTrashedPoliciesResponse proto.InternalMessageInfo
+
+func (m *ListTrashedPoliciesResponse) GetPolicies() []*TrashedPolicy {
+	if m != nil {
+		return m.Policies
+	}
+	return nil
+}
+
+func (m *ListTrashedPoliciesResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+func (m *ListTrashedPoliciesResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type RestorePolicyRequest struct {
+	Id      string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Cluster string `protobuf:"bytes,2,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	// who the restore is recorded for in the audit log
+	Username             string   `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestorePolicyRequest) Reset()         { *m = RestorePolicyRequest{} }
+func (m *RestorePolicyRequest) String() string { return proto.CompactTextString(m) }
+func (*RestorePolicyRequest) ProtoMessage()    {}
+func (*RestorePolicyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{81}
+}
+func (m *RestorePolicyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestorePolicyRequest.Unmarshal(m, b)
+}
+func (m *RestorePolicyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestorePolicyRequest.Marshal(b, m, deterministic)
+}
+func (dst *RestorePolicyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestorePolicyRequest.Merge(dst, src)
+}
+func (m *RestorePolicyRequest) XXX_Size() int {
+	return xxx_messageInfo_RestorePolicyRequest.Size(m)
+}
+func (m *RestorePolicyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestorePolicyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestorePolicyRequest proto.InternalMessageInfo
+
+func (m *RestorePolicyRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RestorePolicyRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+func (m *RestorePolicyRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+type RestorePolicyResponse struct {
+	Policy               *TrashedPolicy `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	Error                string         `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *RestorePolicyResponse) Reset()         { *m = RestorePolicyResponse{} }
+func (m *RestorePolicyResponse) String() string { return proto.CompactTextString(m) }
+func (*RestorePolicyResponse) ProtoMessage()    {}
+func (*RestorePolicyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{82}
+}
+func (m *RestorePolicyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestorePolicyResponse.Unmarshal(m, b)
+}
+func (m *RestorePolicyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestorePolicyResponse.Marshal(b, m, deterministic)
+}
+func (dst *RestorePolicyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestorePolicyResponse.Merge(dst, src)
+}
+func (m *RestorePolicyResponse) XXX_Size() int {
+	return xxx_messageInfo_RestorePolicyResponse.Size(m)
+}
+func (m *RestorePolicyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestorePolicyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestorePolicyResponse proto.InternalMessageInfo
+
+func (m *RestorePolicyResponse) GetPolicy() *TrashedPolicy {
+	if m != nil {
+		return m.Policy
+	}
+	return nil
+}
+
+func (m *RestorePolicyResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type LicenseStatusRequest struct {
+	// the deployment whose Octarine account is reported, the default one when empty, and its cluster
+	Deployment           string   `protobuf:"bytes,1,opt,name=deployment,proto3" json:"deployment,omitempty"`
+	Cluster              string   `protobuf:"bytes,2,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LicenseStatusRequest) Reset()         { *m = LicenseStatusRequest{} }
+func (m *LicenseStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*LicenseStatusRequest) ProtoMessage()    {}
+func (*LicenseStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{83}
+}
+func (m *LicenseStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LicenseStatusRequest.Unmarshal(m, b)
+}
+func (m *LicenseStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LicenseStatusRequest.Marshal(b, m, deterministic)
+}
+func (dst *LicenseStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LicenseStatusRequest.Merge(dst, src)
+}
+func (m *LicenseStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_LicenseStatusRequest.Size(m)
+}
+func (m *LicenseStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LicenseStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LicenseStatusRequest proto.InternalMessageInfo
+
+func (m *LicenseStatusRequest) GetDeployment() string {
+	if m != nil {
+		return m.Deployment
+	}
+	return ""
+}
+
+func (m *LicenseStatusRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+// LicenseEntitlement is what a license grants of something it counts, like nodes or workloads
+type LicenseEntitlement struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Used int64  `protobuf:"varint,2,opt,name=used,proto3" json:"used,omitempty"`
+	// limit is 0, and available -1, when the license doesn't limit it
+	Limit                int64    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Available            int64    `protobuf:"varint,4,opt,name=available,proto3" json:"available,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LicenseEntitlement) Reset()         { *m = LicenseEntitlement{} }
+func (m *LicenseEntitlement) String() string { return proto.CompactTextString(m) }
+func (*LicenseEntitlement) ProtoMessage()    {}
+func (*LicenseEntitlement) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{84}
+}
+func (m *LicenseEntitlement) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LicenseEntitlement.Unmarshal(m, b)
+}
+func (m *LicenseEntitlement) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LicenseEntitlement.Marshal(b, m, deterministic)
+}
+func (dst *LicenseEntitlement) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LicenseEntitlement.Merge(dst, src)
+}
+func (m *LicenseEntitlement) XXX_Size() int {
+	return xxx_messageInfo_LicenseEntitlement.Size(m)
+}
+func (m *LicenseEntitlement) XXX_DiscardUnknown() {
+	xxx_messageInfo_LicenseEntitlement.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LicenseEntitlement proto.InternalMessageInfo
+
+func (m *LicenseEntitlement) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *LicenseEntitlement) GetUsed() int64 {
+	if m != nil {
+		return m.Used
+	}
+	return 0
+}
+
+func (m *LicenseEntitlement) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *LicenseEntitlement) GetAvailable() int64 {
+	if m != nil {
+		return m.Available
+	}
+	return 0
+}
+
+type LicenseStatusResponse struct {
+	Account string `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	Tier    string `protobuf:"bytes,2,opt,name=tier,proto3" json:"tier,omitempty"`
+	// RFC 3339, empty for a license which doesn't expire, days_left is negative once it expired
+	Expires      string                `protobuf:"bytes,3,opt,name=expires,proto3" json:"expires,omitempty"`
+	DaysLeft     int32                 `protobuf:"varint,4,opt,name=days_left,json=daysLeft,proto3" json:"days_left,omitempty"`
+	Entitlements []*LicenseEntitlement `protobuf:"bytes,5,rep,name=entitlements,proto3" json:"entitlements,omitempty"`
+	Features     []string              `protobuf:"bytes,6,rep,name=features,proto3" json:"features,omitempty"`
+	// the limits the account is close to or past, also sent as WARN events the first time
+	Warnings             []string `protobuf:"bytes,7,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	Error                string   `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LicenseStatusResponse) Reset()         { *m = LicenseStatusResponse{} }
+func (m *LicenseStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*LicenseStatusResponse) ProtoMessage()    {}
+func (*LicenseStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meshops_32ebf6e0158a3e62, []int{85}
+}
+func (m *LicenseStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LicenseStatusResponse.Unmarshal(m, b)
+}
+func (m *LicenseStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LicenseStatusResponse.Marshal(b, m, deterministic)
+}
+func (dst *LicenseStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LicenseStatusResponse.Merge(dst, src)
+}
+func (m *LicenseStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_LicenseStatusResponse.Size(m)
+}
+func (m *LicenseStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LicenseStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LicenseStatusResponse proto.InternalMessageInfo
+
+func (m *LicenseStatusResponse) GetAccount() string {
+	if m != nil {
+		return m.Account
+	}
+	return ""
+}
+
+func (m *LicenseStatusResponse) GetTier() string {
+	if m != nil {
+		return m.Tier
+	}
+	return ""
+}
+
+func (m *LicenseStatusResponse) GetExpires() string {
+	if m != nil {
+		return m.Expires
+	}
+	return ""
+}
+
+func (m *LicenseStatusResponse) GetDaysLeft() int32 {
+	if m != nil {
+		return m.DaysLeft
+	}
+	return 0
+}
+
+func (m *LicenseStatusResponse) GetEntitlements() []*LicenseEntitlement {
+	if m != nil {
+		return m.Entitlements
+	}
+	return nil
+}
+
+func (m *LicenseStatusResponse) GetFeatures() []string {
+	if m != nil {
+		return m.Features
+	}
+	return nil
+}
+
+func (m *LicenseStatusResponse) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
+func (m *LicenseStatusResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*CreateMeshInstanceRequest)(nil), "meshes.CreateMeshInstanceRequest")
+	proto.RegisterType((*CreateMeshInstanceResponse)(nil), "meshes.CreateMeshInstanceResponse")
+	proto.RegisterType((*MeshNameRequest)(nil), "meshes.MeshNameRequest")
+	proto.RegisterType((*MeshNameResponse)(nil), "meshes.MeshNameResponse")
+	proto.RegisterType((*ApplyRuleRequest)(nil), "meshes.ApplyRuleRequest")
+	proto.RegisterMapType((map[string]string)(nil), "meshes.ApplyRuleRequest.ParametersEntry")
+	proto.RegisterType((*ApplyRuleResponse)(nil), "meshes.ApplyRuleResponse")
+	proto.RegisterType((*SupportedOperationsRequest)(nil), "meshes.SupportedOperationsRequest")
+	proto.RegisterType((*SupportedOperationsResponse)(nil), "meshes.SupportedOperationsResponse")
+	proto.RegisterType((*SupportedOperation)(nil), "meshes.SupportedOperation")
+	proto.RegisterType((*EventsRequest)(nil), "meshes.EventsRequest")
+	proto.RegisterType((*EventsResponse)(nil), "meshes.EventsResponse")
+	proto.RegisterType((*ClusterCapabilitiesRequest)(nil), "meshes.ClusterCapabilitiesRequest")
+	proto.RegisterType((*ClusterCapabilitiesResponse)(nil), "meshes.ClusterCapabilitiesResponse")
+	proto.RegisterType((*StorageClass)(nil), "meshes.StorageClass")
+	proto.RegisterType((*ProxyVersionsRequest)(nil), "meshes.ProxyVersionsRequest")
+	proto.RegisterType((*ProxyVersionsResponse)(nil), "meshes.ProxyVersionsResponse")
+	proto.RegisterType((*WorkloadProxy)(nil), "meshes.WorkloadProxy")
+	proto.RegisterType((*EnforcementStatusRequest)(nil), "meshes.EnforcementStatusRequest")
+	proto.RegisterType((*EnforcementStatusResponse)(nil), "meshes.EnforcementStatusResponse")
+	proto.RegisterType((*NamespaceEnforcement)(nil), "meshes.NamespaceEnforcement")
+	proto.RegisterType((*PolicyViolationsRequest)(nil), "meshes.PolicyViolationsRequest")
+	proto.RegisterType((*PolicyViolationsResponse)(nil), "meshes.PolicyViolationsResponse")
+	proto.RegisterType((*ViolationCount)(nil), "meshes.ViolationCount")
+	proto.RegisterType((*ViolationBucket)(nil), "meshes.ViolationBucket")
+	proto.RegisterType((*AcknowledgeAlertRequest)(nil), "meshes.AcknowledgeAlertRequest")
+	proto.RegisterType((*AcknowledgeAlertResponse)(nil), "meshes.AcknowledgeAlertResponse")
+	proto.RegisterType((*MuteAlertRequest)(nil), "meshes.MuteAlertRequest")
+	proto.RegisterType((*MuteAlertResponse)(nil), "meshes.MuteAlertResponse")
+	proto.RegisterType((*ExportKubeconfigRequest)(nil), "meshes.ExportKubeconfigRequest")
+	proto.RegisterType((*ExportKubeconfigResponse)(nil), "meshes.ExportKubeconfigResponse")
+	proto.RegisterType((*ScheduleOperationRequest)(nil), "meshes.ScheduleOperationRequest")
+	proto.RegisterType((*ScheduleOperationResponse)(nil), "meshes.ScheduleOperationResponse")
+	proto.RegisterType((*ListSchedulesRequest)(nil), "meshes.ListSchedulesRequest")
+	proto.RegisterType((*ListSchedulesResponse)(nil), "meshes.ListSchedulesResponse")
+	proto.RegisterType((*Schedule)(nil), "meshes.Schedule")
+	proto.RegisterType((*DeleteScheduleRequest)(nil), "meshes.DeleteScheduleRequest")
+	proto.RegisterType((*DeleteScheduleResponse)(nil), "meshes.DeleteScheduleResponse")
+	proto.RegisterType((*ClusterAccess)(nil), "meshes.ClusterAccess")
+	proto.RegisterType((*EstimateFootprintRequest)(nil), "meshes.EstimateFootprintRequest")
+	proto.RegisterType((*Footprint)(nil), "meshes.Footprint")
+	proto.RegisterType((*NamespaceFootprint)(nil), "meshes.NamespaceFootprint")
+	proto.RegisterType((*EstimateFootprintResponse)(nil), "meshes.EstimateFootprintResponse")
+	proto.RegisterType((*LintTemplatesRequest)(nil), "meshes.LintTemplatesRequest")
+	proto.RegisterType((*TemplateLint)(nil), "meshes.TemplateLint")
+	proto.RegisterType((*LintTemplatesResponse)(nil), "meshes.LintTemplatesResponse")
+	proto.RegisterType((*AdapterCapabilitiesRequest)(nil), "meshes.AdapterCapabilitiesRequest")
+	proto.RegisterType((*AdapterCapabilitiesResponse)(nil), "meshes.AdapterCapabilitiesResponse")
+	proto.RegisterType((*InventoryRequest)(nil), "meshes.InventoryRequest")
+	proto.RegisterType((*InventoryResponse)(nil), "meshes.InventoryResponse")
+	proto.RegisterType((*InventoryResource)(nil), "meshes.InventoryResource")
+	proto.RegisterType((*ClusterConnection)(nil), "meshes.ClusterConnection")
+	proto.RegisterType((*RenderOperationRequest)(nil), "meshes.RenderOperationRequest")
+	proto.RegisterMapType((map[string]string)(nil), "meshes.RenderOperationRequest.ParametersEntry")
+	proto.RegisterType((*RenderOperationResponse)(nil), "meshes.RenderOperationResponse")
+	proto.RegisterType((*WorkloadIdentitiesRequest)(nil), "meshes.WorkloadIdentitiesRequest")
+	proto.RegisterType((*WorkloadIdentitiesResponse)(nil), "meshes.WorkloadIdentitiesResponse")
+	proto.RegisterType((*WorkloadIdentity)(nil), "meshes.WorkloadIdentity")
+	proto.RegisterType((*ServiceAccountUsage)(nil), "meshes.ServiceAccountUsage")
+	proto.RegisterType((*RegistryUsage)(nil), "meshes.RegistryUsage")
+	proto.RegisterType((*VetReportRequest)(nil), "meshes.VetReportRequest")
+	proto.RegisterType((*VetReportResponse)(nil), "meshes.VetReportResponse")
+	proto.RegisterType((*VetCheckResult)(nil), "meshes.VetCheckResult")
+	proto.RegisterType((*LatencyProbeReportRequest)(nil), "meshes.LatencyProbeReportRequest")
+	proto.RegisterType((*LatencyProbeReportResponse)(nil), "meshes.LatencyProbeReportResponse")
+	proto.RegisterType((*LatencyResult)(nil), "meshes.LatencyResult")
+	proto.RegisterType((*GetOperationResultRequest)(nil), "meshes.GetOperationResultRequest")
+	proto.RegisterType((*GetOperationResultResponse)(nil), "meshes.GetOperationResultResponse")
+	proto.RegisterType((*QueryEventsRequest)(nil), "meshes.QueryEventsRequest")
+	proto.RegisterType((*QueryEventsResponse)(nil), "meshes.QueryEventsResponse")
+	proto.RegisterType((*StoredEvent)(nil), "meshes.StoredEvent")
+	proto.RegisterType((*ListActiveOperationsRequest)(nil), "meshes.ListActiveOperationsRequest")
+	proto.RegisterType((*ListActiveOperationsResponse)(nil), "meshes.ListActiveOperationsResponse")
+	proto.RegisterType((*ActiveOperation)(nil), "meshes.ActiveOperation")
+	proto.RegisterType((*PreviewTelemetryRequest)(nil), "meshes.PreviewTelemetryRequest")
+	proto.RegisterType((*PreviewTelemetryResponse)(nil), "meshes.PreviewTelemetryResponse")
+	proto.RegisterType((*ImageManifestsRequest)(nil), "meshes.ImageManifestsRequest")
+	proto.RegisterType((*ImagePlatform)(nil), "meshes.ImagePlatform")
+	proto.RegisterType((*ImageManifest)(nil), "meshes.ImageManifest")
+	proto.RegisterType((*ImageManifestsResponse)(nil), "meshes.ImageManifestsResponse")
+	proto.RegisterType((*TrashedPolicy)(nil), "meshes.TrashedPolicy")
+	proto.RegisterType((*ListTrashedPoliciesRequest)(nil), "meshes.ListTrashedPoliciesRequest")
+	proto.RegisterType((*ListTrashedPoliciesResponse)(nil), "meshes.ListTrashedPoliciesResponse")
+	proto.RegisterType((*RestorePolicyRequest)(nil), "meshes.RestorePolicyRequest")
+	proto.RegisterType((*RestorePolicyResponse)(nil), "meshes.RestorePolicyResponse")
+	proto.RegisterType((*LicenseStatusRequest)(nil), "meshes.LicenseStatusRequest")
+	proto.RegisterType((*LicenseEntitlement)(nil), "meshes.LicenseEntitlement")
+	proto.RegisterType((*LicenseStatusResponse)(nil), "meshes.LicenseStatusResponse")
+	proto.RegisterEnum("meshes.OpCategory", OpCategory_name, OpCategory_value)
+	proto.RegisterEnum("meshes.EventType", EventType_name, EventType_value)
+	proto.RegisterEnum("meshes.Severity", Severity_name, Severity_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// MeshServiceClient is the client API for MeshService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MeshServiceClient interface {
+	CreateMeshInstance(ctx context.Context, in *CreateMeshInstanceRequest, opts ...grpc.CallOption) (*CreateMeshInstanceResponse, error)
+	MeshName(ctx context.Context, in *MeshNameRequest, opts ...grpc.CallOption) (*MeshNameResponse, error)
+	ApplyOperation(ctx context.Context, in *ApplyRuleRequest, opts ...grpc.CallOption) (*ApplyRuleResponse, error)
+	SupportedOperations(ctx context.Context, in *SupportedOperationsRequest, opts ...grpc.CallOption) (*SupportedOperationsResponse, error)
+	StreamEvents(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (MeshService_StreamEventsClient, error)
+	ClusterCapabilities(ctx context.Context, in *ClusterCapabilitiesRequest, opts ...grpc.CallOption) (*ClusterCapabilitiesResponse, error)
+	ProxyVersions(ctx context.Context, in *ProxyVersionsRequest, opts ...grpc.CallOption) (*ProxyVersionsResponse, error)
+	EnforcementStatus(ctx context.Context, in *EnforcementStatusRequest, opts ...grpc.CallOption) (*EnforcementStatusResponse, error)
+	PolicyViolations(ctx context.Context, in *PolicyViolationsRequest, opts ...grpc.CallOption) (*PolicyViolationsResponse, error)
+	AcknowledgeAlert(ctx context.Context, in *AcknowledgeAlertRequest, opts ...grpc.CallOption) (*AcknowledgeAlertResponse, error)
+	MuteAlert(ctx context.Context, in *MuteAlertRequest, opts ...grpc.CallOption) (*MuteAlertResponse, error)
+	ExportKubeconfig(ctx context.Context, in *ExportKubeconfigRequest, opts ...grpc.CallOption) (*ExportKubeconfigResponse, error)
+	ScheduleOperation(ctx context.Context, in *ScheduleOperationRequest, opts ...grpc.CallOption) (*ScheduleOperationResponse, error)
+	ListSchedules(ctx context.Context, in *ListSchedulesRequest, opts ...grpc.CallOption) (*ListSchedulesResponse, error)
+	DeleteSchedule(ctx context.Context, in *DeleteScheduleRequest, opts ...grpc.CallOption) (*DeleteScheduleResponse, error)
+	EstimateFootprint(ctx context.Context, in *EstimateFootprintRequest, opts ...grpc.CallOption) (*EstimateFootprintResponse, error)
+	LintTemplates(ctx context.Context, in *LintTemplatesRequest, opts ...grpc.CallOption) (*LintTemplatesResponse, error)
+	AdapterCapabilities(ctx context.Context, in *AdapterCapabilitiesRequest, opts ...grpc.CallOption) (*AdapterCapabilitiesResponse, error)
+	Inventory(ctx context.Context, in *InventoryRequest, opts ...grpc.CallOption) (*InventoryResponse, error)
+	RenderOperation(ctx context.Context, in *RenderOperationRequest, opts ...grpc.CallOption) (*RenderOperationResponse, error)
+	WorkloadIdentities(ctx context.Context, in *WorkloadIdentitiesRequest, opts ...grpc.CallOption) (*WorkloadIdentitiesResponse, error)
+	VetReport(ctx context.Context, in *VetReportRequest, opts ...grpc.CallOption) (*VetReportResponse, error)
+	LatencyProbeReport(ctx context.Context, in *LatencyProbeReportRequest, opts ...grpc.CallOption) (*LatencyProbeReportResponse, error)
+	GetOperationResult(ctx context.Context, in *GetOperationResultRequest, opts ...grpc.CallOption) (*GetOperationResultResponse, error)
+	QueryEvents(ctx context.Context, in *QueryEventsRequest, opts ...grpc.CallOption) (*QueryEventsResponse, error)
+	ListActiveOperations(ctx context.Context, in *ListActiveOperationsRequest, opts ...grpc.CallOption) (*ListActiveOperationsResponse, error)
+	PreviewTelemetry(ctx context.Context, in *PreviewTelemetryRequest, opts ...grpc.CallOption) (*PreviewTelemetryResponse, error)
+	ImageManifests(ctx context.Context, in *ImageManifestsRequest, opts ...grpc.CallOption) (*ImageManifestsResponse, error)
+	ListTrashedPolicies(ctx context.Context, in *ListTrashedPoliciesRequest, opts ...grpc.CallOption) (*ListTrashedPoliciesResponse, error)
+	RestorePolicy(ctx context.Context, in *RestorePolicyRequest, opts ...grpc.CallOption) (*RestorePolicyResponse, error)
+	LicenseStatus(ctx context.Context, in *LicenseStatusRequest, opts ...grpc.CallOption) (*LicenseStatusResponse, error)
+}
+
+type meshServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewMeshServiceClient(cc *grpc.ClientConn) MeshServiceClient {
+	return &meshServiceClient{cc}
+}
+
+func (c *meshServiceClient) CreateMeshInstance(ctx context.Context, in *CreateMeshInstanceRequest, opts ...grpc.CallOption) (*CreateMeshInstanceResponse, error) {
+	out := new(CreateMeshInstanceResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/CreateMeshInstance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) MeshName(ctx context.Context, in *MeshNameRequest, opts ...grpc.CallOption) (*MeshNameResponse, error) {
+	out := new(MeshNameResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/MeshName", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) ApplyOperation(ctx context.Context, in *ApplyRuleRequest, opts ...grpc.CallOption) (*ApplyRuleResponse, error) {
+	out := new(ApplyRuleResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/ApplyOperation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) SupportedOperations(ctx context.Context, in *SupportedOperationsRequest, opts ...grpc.CallOption) (*SupportedOperationsResponse, error) {
+	out := new(SupportedOperationsResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/SupportedOperations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) StreamEvents(ctx context.Context, in *EventsRequest, opts ...grpc.CallOption) (MeshService_StreamEventsClient, error) {
 	stream, err := c.cc.NewStream(ctx, &_MeshService_serviceDesc.Streams[0], "/meshes.MeshService/StreamEvents", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &meshServiceStreamEventsClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
+	x := &meshServiceStreamEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MeshService_StreamEventsClient interface {
+	Recv() (*EventsResponse, error)
+	grpc.ClientStream
+}
+
+type meshServiceStreamEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *meshServiceStreamEventsClient) Recv() (*EventsResponse, error) {
+	m := new(EventsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *meshServiceClient) ClusterCapabilities(ctx context.Context, in *ClusterCapabilitiesRequest, opts ...grpc.CallOption) (*ClusterCapabilitiesResponse, error) {
+	out := new(ClusterCapabilitiesResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/ClusterCapabilities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) ProxyVersions(ctx context.Context, in *ProxyVersionsRequest, opts ...grpc.CallOption) (*ProxyVersionsResponse, error) {
+	out := new(ProxyVersionsResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/ProxyVersions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) EnforcementStatus(ctx context.Context, in *EnforcementStatusRequest, opts ...grpc.CallOption) (*EnforcementStatusResponse, error) {
+	out := new(EnforcementStatusResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/EnforcementStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) PolicyViolations(ctx context.Context, in *PolicyViolationsRequest, opts ...grpc.CallOption) (*PolicyViolationsResponse, error) {
+	out := new(PolicyViolationsResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/PolicyViolations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) AcknowledgeAlert(ctx context.Context, in *AcknowledgeAlertRequest, opts ...grpc.CallOption) (*AcknowledgeAlertResponse, error) {
+	out := new(AcknowledgeAlertResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/AcknowledgeAlert", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) MuteAlert(ctx context.Context, in *MuteAlertRequest, opts ...grpc.CallOption) (*MuteAlertResponse, error) {
+	out := new(MuteAlertResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/MuteAlert", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) ExportKubeconfig(ctx context.Context, in *ExportKubeconfigRequest, opts ...grpc.CallOption) (*ExportKubeconfigResponse, error) {
+	out := new(ExportKubeconfigResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/ExportKubeconfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) ScheduleOperation(ctx context.Context, in *ScheduleOperationRequest, opts ...grpc.CallOption) (*ScheduleOperationResponse, error) {
+	out := new(ScheduleOperationResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/ScheduleOperation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) ListSchedules(ctx context.Context, in *ListSchedulesRequest, opts ...grpc.CallOption) (*ListSchedulesResponse, error) {
+	out := new(ListSchedulesResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/ListSchedules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) DeleteSchedule(ctx context.Context, in *DeleteScheduleRequest, opts ...grpc.CallOption) (*DeleteScheduleResponse, error) {
+	out := new(DeleteScheduleResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/DeleteSchedule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) EstimateFootprint(ctx context.Context, in *EstimateFootprintRequest, opts ...grpc.CallOption) (*EstimateFootprintResponse, error) {
+	out := new(EstimateFootprintResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/EstimateFootprint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) LintTemplates(ctx context.Context, in *LintTemplatesRequest, opts ...grpc.CallOption) (*LintTemplatesResponse, error) {
+	out := new(LintTemplatesResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/LintTemplates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) AdapterCapabilities(ctx context.Context, in *AdapterCapabilitiesRequest, opts ...grpc.CallOption) (*AdapterCapabilitiesResponse, error) {
+	out := new(AdapterCapabilitiesResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/AdapterCapabilities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) Inventory(ctx context.Context, in *InventoryRequest, opts ...grpc.CallOption) (*InventoryResponse, error) {
+	out := new(InventoryResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/Inventory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) RenderOperation(ctx context.Context, in *RenderOperationRequest, opts ...grpc.CallOption) (*RenderOperationResponse, error) {
+	out := new(RenderOperationResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/RenderOperation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) WorkloadIdentities(ctx context.Context, in *WorkloadIdentitiesRequest, opts ...grpc.CallOption) (*WorkloadIdentitiesResponse, error) {
+	out := new(WorkloadIdentitiesResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/WorkloadIdentities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) VetReport(ctx context.Context, in *VetReportRequest, opts ...grpc.CallOption) (*VetReportResponse, error) {
+	out := new(VetReportResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/VetReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) LatencyProbeReport(ctx context.Context, in *LatencyProbeReportRequest, opts ...grpc.CallOption) (*LatencyProbeReportResponse, error) {
+	out := new(LatencyProbeReportResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/LatencyProbeReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) GetOperationResult(ctx context.Context, in *GetOperationResultRequest, opts ...grpc.CallOption) (*GetOperationResultResponse, error) {
+	out := new(GetOperationResultResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/GetOperationResult", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) QueryEvents(ctx context.Context, in *QueryEventsRequest, opts ...grpc.CallOption) (*QueryEventsResponse, error) {
+	out := new(QueryEventsResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/QueryEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) ListActiveOperations(ctx context.Context, in *ListActiveOperationsRequest, opts ...grpc.CallOption) (*ListActiveOperationsResponse, error) {
+	out := new(ListActiveOperationsResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/ListActiveOperations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) PreviewTelemetry(ctx context.Context, in *PreviewTelemetryRequest, opts ...grpc.CallOption) (*PreviewTelemetryResponse, error) {
+	out := new(PreviewTelemetryResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/PreviewTelemetry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) ImageManifests(ctx context.Context, in *ImageManifestsRequest, opts ...grpc.CallOption) (*ImageManifestsResponse, error) {
+	out := new(ImageManifestsResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/ImageManifests", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) ListTrashedPolicies(ctx context.Context, in *ListTrashedPoliciesRequest, opts ...grpc.CallOption) (*ListTrashedPoliciesResponse, error) {
+	out := new(ListTrashedPoliciesResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/ListTrashedPolicies", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) RestorePolicy(ctx context.Context, in *RestorePolicyRequest, opts ...grpc.CallOption) (*RestorePolicyResponse, error) {
+	out := new(RestorePolicyResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/RestorePolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshServiceClient) LicenseStatus(ctx context.Context, in *LicenseStatusRequest, opts ...grpc.CallOption) (*LicenseStatusResponse, error) {
+	out := new(LicenseStatusResponse)
+	err := c.cc.Invoke(ctx, "/meshes.MeshService/LicenseStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MeshServiceServer is the server API for MeshService service.
+type MeshServiceServer interface {
+	CreateMeshInstance(context.Context, *CreateMeshInstanceRequest) (*CreateMeshInstanceResponse, error)
+	MeshName(context.Context, *MeshNameRequest) (*MeshNameResponse, error)
+	ApplyOperation(context.Context, *ApplyRuleRequest) (*ApplyRuleResponse, error)
+	SupportedOperations(context.Context, *SupportedOperationsRequest) (*SupportedOperationsResponse, error)
+	StreamEvents(*EventsRequest, MeshService_StreamEventsServer) error
+	ClusterCapabilities(context.Context, *ClusterCapabilitiesRequest) (*ClusterCapabilitiesResponse, error)
+	ProxyVersions(context.Context, *ProxyVersionsRequest) (*ProxyVersionsResponse, error)
+	EnforcementStatus(context.Context, *EnforcementStatusRequest) (*EnforcementStatusResponse, error)
+	PolicyViolations(context.Context, *PolicyViolationsRequest) (*PolicyViolationsResponse, error)
+	AcknowledgeAlert(context.Context, *AcknowledgeAlertRequest) (*AcknowledgeAlertResponse, error)
+	MuteAlert(context.Context, *MuteAlertRequest) (*MuteAlertResponse, error)
+	ExportKubeconfig(context.Context, *ExportKubeconfigRequest) (*ExportKubeconfigResponse, error)
+	ScheduleOperation(context.Context, *ScheduleOperationRequest) (*ScheduleOperationResponse, error)
+	ListSchedules(context.Context, *ListSchedulesRequest) (*ListSchedulesResponse, error)
+	DeleteSchedule(context.Context, *DeleteScheduleRequest) (*DeleteScheduleResponse, error)
+	EstimateFootprint(context.Context, *EstimateFootprintRequest) (*EstimateFootprintResponse, error)
+	LintTemplates(context.Context, *LintTemplatesRequest) (*LintTemplatesResponse, error)
+	AdapterCapabilities(context.Context, *AdapterCapabilitiesRequest) (*AdapterCapabilitiesResponse, error)
+	Inventory(context.Context, *InventoryRequest) (*InventoryResponse, error)
+	RenderOperation(context.Context, *RenderOperationRequest) (*RenderOperationResponse, error)
+	WorkloadIdentities(context.Context, *WorkloadIdentitiesRequest) (*WorkloadIdentitiesResponse, error)
+	VetReport(context.Context, *VetReportRequest) (*VetReportResponse, error)
+	LatencyProbeReport(context.Context, *LatencyProbeReportRequest) (*LatencyProbeReportResponse, error)
+	GetOperationResult(context.Context, *GetOperationResultRequest) (*GetOperationResultResponse, error)
+	QueryEvents(context.Context, *QueryEventsRequest) (*QueryEventsResponse, error)
+	ListActiveOperations(context.Context, *ListActiveOperationsRequest) (*ListActiveOperationsResponse, error)
+	PreviewTelemetry(context.Context, *PreviewTelemetryRequest) (*PreviewTelemetryResponse, error)
+	ImageManifests(context.Context, *ImageManifestsRequest) (*ImageManifestsResponse, error)
+	ListTrashedPolicies(context.Context, *ListTrashedPoliciesRequest) (*ListTrashedPoliciesResponse, error)
+	RestorePolicy(context.Context, *RestorePolicyRequest) (*RestorePolicyResponse, error)
+	LicenseStatus(context.Context, *LicenseStatusRequest) (*LicenseStatusResponse, error)
+}
+
+func RegisterMeshServiceServer(s *grpc.Server, srv MeshServiceServer) {
+	s.RegisterService(&_MeshService_serviceDesc, srv)
+}
+
+func _MeshService_CreateMeshInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMeshInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).CreateMeshInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/CreateMeshInstance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).CreateMeshInstance(ctx, req.(*CreateMeshInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_MeshName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MeshNameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).MeshName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/MeshName",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).MeshName(ctx, req.(*MeshNameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_ApplyOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).ApplyOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/ApplyOperation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).ApplyOperation(ctx, req.(*ApplyRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_SupportedOperations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SupportedOperationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).SupportedOperations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/SupportedOperations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).SupportedOperations(ctx, req.(*SupportedOperationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MeshServiceServer).StreamEvents(m, &meshServiceStreamEventsServer{stream})
+}
+
+type MeshService_StreamEventsServer interface {
+	Send(*EventsResponse) error
+	grpc.ServerStream
+}
+
+type meshServiceStreamEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *meshServiceStreamEventsServer) Send(m *EventsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MeshService_ClusterCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClusterCapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).ClusterCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/ClusterCapabilities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).ClusterCapabilities(ctx, req.(*ClusterCapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_ProxyVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProxyVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).ProxyVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/ProxyVersions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).ProxyVersions(ctx, req.(*ProxyVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_EnforcementStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnforcementStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).EnforcementStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/EnforcementStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).EnforcementStatus(ctx, req.(*EnforcementStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_PolicyViolations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PolicyViolationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).PolicyViolations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/PolicyViolations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).PolicyViolations(ctx, req.(*PolicyViolationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_AcknowledgeAlert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcknowledgeAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).AcknowledgeAlert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/AcknowledgeAlert",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).AcknowledgeAlert(ctx, req.(*AcknowledgeAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_MuteAlert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MuteAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).MuteAlert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/MuteAlert",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).MuteAlert(ctx, req.(*MuteAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_ExportKubeconfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportKubeconfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).ExportKubeconfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/ExportKubeconfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).ExportKubeconfig(ctx, req.(*ExportKubeconfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_ScheduleOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).ScheduleOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/ScheduleOperation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).ScheduleOperation(ctx, req.(*ScheduleOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_ListSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSchedulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).ListSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/ListSchedules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).ListSchedules(ctx, req.(*ListSchedulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_DeleteSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).DeleteSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/DeleteSchedule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).DeleteSchedule(ctx, req.(*DeleteScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_EstimateFootprint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateFootprintRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
+	if interceptor == nil {
+		return srv.(MeshServiceServer).EstimateFootprint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/EstimateFootprint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).EstimateFootprint(ctx, req.(*EstimateFootprintRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_LintTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LintTemplatesRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return x, nil
+	if interceptor == nil {
+		return srv.(MeshServiceServer).LintTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/LintTemplates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).LintTemplates(ctx, req.(*LintTemplatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type MeshService_StreamEventsClient interface {
-	Recv() (*EventsResponse, error)
-	grpc.ClientStream
+func _MeshService_AdapterCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdapterCapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).AdapterCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/AdapterCapabilities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).AdapterCapabilities(ctx, req.(*AdapterCapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type meshServiceStreamEventsClient struct {
-	grpc.ClientStream
+func _MeshService_Inventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InventoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).Inventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/Inventory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).Inventory(ctx, req.(*InventoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *meshServiceStreamEventsClient) Recv() (*EventsResponse, error) {
-	m := new(EventsResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _MeshService_RenderOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenderOperationRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(MeshServiceServer).RenderOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/RenderOperation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).RenderOperation(ctx, req.(*RenderOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// MeshServiceServer is the server API for MeshService service.
-type MeshServiceServer interface {
-	CreateMeshInstance(context.Context, *CreateMeshInstanceRequest) (*CreateMeshInstanceResponse, error)
-	MeshName(context.Context, *MeshNameRequest) (*MeshNameResponse, error)
-	ApplyOperation(context.Context, *ApplyRuleRequest) (*ApplyRuleResponse, error)
-	SupportedOperations(context.Context, *SupportedOperationsRequest) (*SupportedOperationsResponse, error)
-	StreamEvents(*EventsRequest, MeshService_StreamEventsServer) error
+func _MeshService_WorkloadIdentities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkloadIdentitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).WorkloadIdentities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/WorkloadIdentities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).WorkloadIdentities(ctx, req.(*WorkloadIdentitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterMeshServiceServer(s *grpc.Server, srv MeshServiceServer) {
-	s.RegisterService(&_MeshService_serviceDesc, srv)
+func _MeshService_VetReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VetReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).VetReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/VetReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).VetReport(ctx, req.(*VetReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _MeshService_CreateMeshInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateMeshInstanceRequest)
+func _MeshService_LatencyProbeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LatencyProbeReportRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MeshServiceServer).CreateMeshInstance(ctx, in)
+		return srv.(MeshServiceServer).LatencyProbeReport(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/meshes.MeshService/CreateMeshInstance",
+		FullMethod: "/meshes.MeshService/LatencyProbeReport",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MeshServiceServer).CreateMeshInstance(ctx, req.(*CreateMeshInstanceRequest))
+		return srv.(MeshServiceServer).LatencyProbeReport(ctx, req.(*LatencyProbeReportRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MeshService_MeshName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MeshNameRequest)
+func _MeshService_GetOperationResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOperationResultRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MeshServiceServer).MeshName(ctx, in)
+		return srv.(MeshServiceServer).GetOperationResult(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/meshes.MeshService/MeshName",
+		FullMethod: "/meshes.MeshService/GetOperationResult",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MeshServiceServer).MeshName(ctx, req.(*MeshNameRequest))
+		return srv.(MeshServiceServer).GetOperationResult(ctx, req.(*GetOperationResultRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MeshService_ApplyOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ApplyRuleRequest)
+func _MeshService_QueryEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryEventsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MeshServiceServer).ApplyOperation(ctx, in)
+		return srv.(MeshServiceServer).QueryEvents(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/meshes.MeshService/ApplyOperation",
+		FullMethod: "/meshes.MeshService/QueryEvents",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MeshServiceServer).ApplyOperation(ctx, req.(*ApplyRuleRequest))
+		return srv.(MeshServiceServer).QueryEvents(ctx, req.(*QueryEventsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MeshService_SupportedOperations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SupportedOperationsRequest)
+func _MeshService_ListActiveOperations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActiveOperationsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MeshServiceServer).SupportedOperations(ctx, in)
+		return srv.(MeshServiceServer).ListActiveOperations(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/meshes.MeshService/SupportedOperations",
+		FullMethod: "/meshes.MeshService/ListActiveOperations",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MeshServiceServer).SupportedOperations(ctx, req.(*SupportedOperationsRequest))
+		return srv.(MeshServiceServer).ListActiveOperations(ctx, req.(*ListActiveOperationsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MeshService_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(EventsRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _MeshService_PreviewTelemetry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreviewTelemetryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(MeshServiceServer).StreamEvents(m, &meshServiceStreamEventsServer{stream})
+	if interceptor == nil {
+		return srv.(MeshServiceServer).PreviewTelemetry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/PreviewTelemetry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).PreviewTelemetry(ctx, req.(*PreviewTelemetryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type MeshService_StreamEventsServer interface {
-	Send(*EventsResponse) error
-	grpc.ServerStream
+func _MeshService_ImageManifests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImageManifestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).ImageManifests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/ImageManifests",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).ImageManifests(ctx, req.(*ImageManifestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type meshServiceStreamEventsServer struct {
-	grpc.ServerStream
+func _MeshService_ListTrashedPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrashedPoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).ListTrashedPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/ListTrashedPolicies",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).ListTrashedPolicies(ctx, req.(*ListTrashedPoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *meshServiceStreamEventsServer) Send(m *EventsResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _MeshService_RestorePolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestorePolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).RestorePolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/RestorePolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).RestorePolicy(ctx, req.(*RestorePolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeshService_LicenseStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LicenseStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshServiceServer).LicenseStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/meshes.MeshService/LicenseStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshServiceServer).LicenseStatus(ctx, req.(*LicenseStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
 var _MeshService_serviceDesc = grpc.ServiceDesc{
@@ -808,6 +7153,110 @@ var _MeshService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SupportedOperations",
 			Handler:    _MeshService_SupportedOperations_Handler,
 		},
+		{
+			MethodName: "ClusterCapabilities",
+			Handler:    _MeshService_ClusterCapabilities_Handler,
+		},
+		{
+			MethodName: "ProxyVersions",
+			Handler:    _MeshService_ProxyVersions_Handler,
+		},
+		{
+			MethodName: "EnforcementStatus",
+			Handler:    _MeshService_EnforcementStatus_Handler,
+		},
+		{
+			MethodName: "PolicyViolations",
+			Handler:    _MeshService_PolicyViolations_Handler,
+		},
+		{
+			MethodName: "AcknowledgeAlert",
+			Handler:    _MeshService_AcknowledgeAlert_Handler,
+		},
+		{
+			MethodName: "MuteAlert",
+			Handler:    _MeshService_MuteAlert_Handler,
+		},
+		{
+			MethodName: "ExportKubeconfig",
+			Handler:    _MeshService_ExportKubeconfig_Handler,
+		},
+		{
+			MethodName: "ScheduleOperation",
+			Handler:    _MeshService_ScheduleOperation_Handler,
+		},
+		{
+			MethodName: "ListSchedules",
+			Handler:    _MeshService_ListSchedules_Handler,
+		},
+		{
+			MethodName: "DeleteSchedule",
+			Handler:    _MeshService_DeleteSchedule_Handler,
+		},
+		{
+			MethodName: "EstimateFootprint",
+			Handler:    _MeshService_EstimateFootprint_Handler,
+		},
+		{
+			MethodName: "LintTemplates",
+			Handler:    _MeshService_LintTemplates_Handler,
+		},
+		{
+			MethodName: "AdapterCapabilities",
+			Handler:    _MeshService_AdapterCapabilities_Handler,
+		},
+		{
+			MethodName: "Inventory",
+			Handler:    _MeshService_Inventory_Handler,
+		},
+		{
+			MethodName: "RenderOperation",
+			Handler:    _MeshService_RenderOperation_Handler,
+		},
+		{
+			MethodName: "WorkloadIdentities",
+			Handler:    _MeshService_WorkloadIdentities_Handler,
+		},
+		{
+			MethodName: "VetReport",
+			Handler:    _MeshService_VetReport_Handler,
+		},
+		{
+			MethodName: "LatencyProbeReport",
+			Handler:    _MeshService_LatencyProbeReport_Handler,
+		},
+		{
+			MethodName: "GetOperationResult",
+			Handler:    _MeshService_GetOperationResult_Handler,
+		},
+		{
+			MethodName: "QueryEvents",
+			Handler:    _MeshService_QueryEvents_Handler,
+		},
+		{
+			MethodName: "ListActiveOperations",
+			Handler:    _MeshService_ListActiveOperations_Handler,
+		},
+		{
+			MethodName: "PreviewTelemetry",
+			Handler:    _MeshService_PreviewTelemetry_Handler,
+		},
+		{
+			MethodName: "ImageManifests",
+			Handler:    _MeshService_ImageManifests_Handler,
+		},
+		{
+			MethodName: "ListTrashedPolicies",
+			Handler:    _MeshService_ListTrashedPolicies_Handler,
+		},
+		{
+			MethodName: "RestorePolicy",
+			Handler:    _MeshService_RestorePolicy_Handler,
+		},
+		{
+			MethodName: "LicenseStatus",
+			Handler:    _MeshService_LicenseStatus_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -819,51 +7268,338 @@ var _MeshService_serviceDesc = grpc.ServiceDesc{
 	Metadata: "meshops.proto",
 }
 
-func init() { proto.RegisterFile("meshops.proto", fileDescriptor_meshops_70742207f69c0a34) }
-
-var fileDescriptor_meshops_70742207f69c0a34 = []byte{
-	// 676 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x54, 0x61, 0x6f, 0xda, 0x3c,
-	0x10, 0x6e, 0x80, 0x52, 0x38, 0x28, 0x4d, 0xfd, 0xbe, 0xeb, 0xd2, 0xb4, 0xd2, 0x68, 0x26, 0x4d,
-	0xa8, 0x9a, 0x50, 0xc5, 0xbe, 0xec, 0xdb, 0x94, 0x31, 0x5a, 0x45, 0xa2, 0xa4, 0x0a, 0x74, 0x93,
-	0x36, 0x4d, 0x2c, 0x85, 0x5b, 0x8b, 0x0a, 0xb1, 0x17, 0x9b, 0x6a, 0xf9, 0x29, 0xd3, 0x7e, 0xcf,
-	0xfe, 0xd7, 0xe4, 0x24, 0x0e, 0x5d, 0x43, 0xfb, 0xcd, 0xf7, 0xdc, 0xf9, 0xf1, 0x3d, 0xf6, 0x73,
-	0x86, 0xed, 0x05, 0xf2, 0x1b, 0xca, 0x78, 0x9b, 0x85, 0x54, 0x50, 0x52, 0x96, 0x21, 0x72, 0xeb,
-	0x0b, 0xec, 0x77, 0x43, 0xf4, 0x05, 0x9e, 0x23, 0xbf, 0x71, 0x02, 0x2e, 0xfc, 0x60, 0x82, 0x1e,
-	0xfe, 0x58, 0x22, 0x17, 0xe4, 0x10, 0xaa, 0xb7, 0x6f, 0x79, 0x97, 0x06, 0xdf, 0x67, 0xd7, 0x86,
-	0xd6, 0xd4, 0x5a, 0x75, 0x6f, 0x05, 0x90, 0x26, 0xd4, 0x26, 0x34, 0x10, 0xf8, 0x53, 0x0c, 0xfc,
-	0x05, 0x1a, 0x85, 0xa6, 0xd6, 0xaa, 0x7a, 0xf7, 0x21, 0xeb, 0x10, 0xcc, 0x75, 0xe4, 0x9c, 0xd1,
-	0x80, 0xa3, 0xb5, 0x0b, 0x3b, 0x12, 0x97, 0x95, 0xe9, 0x81, 0xd6, 0x2b, 0xd0, 0x57, 0x50, 0x52,
-	0x46, 0x08, 0x94, 0x02, 0xc9, 0xaf, 0xc5, 0xfc, 0xf1, 0xda, 0xfa, 0xa3, 0x81, 0x6e, 0x33, 0x36,
-	0x8f, 0xbc, 0xe5, 0x3c, 0xeb, 0x76, 0x0f, 0xca, 0x94, 0x0d, 0x56, 0xa5, 0x69, 0x24, 0x55, 0xc8,
-	0x4d, 0x9c, 0xf9, 0x13, 0xd5, 0xe5, 0x0a, 0x20, 0x26, 0x54, 0x96, 0x1c, 0xc3, 0xf8, 0x88, 0x62,
-	0x9c, 0xcc, 0x62, 0xf2, 0x02, 0x6a, 0x93, 0x25, 0x17, 0x74, 0x31, 0xbe, 0xa2, 0xd3, 0xc8, 0x28,
-	0xc5, 0x69, 0x48, 0xa0, 0xf7, 0x74, 0x1a, 0x91, 0x03, 0xa8, 0x4e, 0x71, 0x8e, 0x02, 0xc7, 0x94,
-	0x19, 0x9b, 0x4d, 0xad, 0x55, 0xf1, 0x2a, 0x09, 0xe0, 0x32, 0x72, 0x04, 0x75, 0xca, 0x30, 0xf4,
-	0xc5, 0x8c, 0x06, 0xe3, 0xd9, 0xd4, 0x28, 0x27, 0x17, 0x94, 0x61, 0xce, 0xd4, 0xea, 0xc3, 0xee,
-	0x3d, 0x19, 0xa9, 0xe0, 0xff, 0x61, 0x13, 0xc3, 0x90, 0x86, 0xa9, 0x8c, 0x24, 0xc8, 0xb1, 0x15,
-	0xf2, 0x6c, 0x87, 0x60, 0x0e, 0x97, 0x8c, 0xd1, 0x50, 0xe0, 0xd4, 0x55, 0x38, 0x57, 0x77, 0xeb,
-	0xc3, 0xc1, 0xda, 0x6c, 0x7a, 0xea, 0x6b, 0x28, 0x52, 0xc6, 0x0d, 0xad, 0x59, 0x6c, 0xd5, 0x3a,
-	0x66, 0x3b, 0xb1, 0x47, 0x3b, 0xbf, 0xc3, 0x93, 0x65, 0xab, 0x1e, 0x0b, 0xf7, 0x7a, 0xb4, 0xe6,
-	0x40, 0xf2, 0x1b, 0x88, 0x0e, 0xc5, 0x5b, 0x8c, 0x52, 0x35, 0x72, 0x29, 0x77, 0xdf, 0xf9, 0xf3,
-	0xa5, 0x7a, 0x8d, 0x24, 0x20, 0x6d, 0xa8, 0x4c, 0x7c, 0x81, 0xd7, 0x34, 0x8c, 0xe2, 0x97, 0x68,
-	0x74, 0x88, 0x6a, 0xc3, 0x65, 0xdd, 0x34, 0xe3, 0x65, 0x35, 0xd6, 0x0e, 0x6c, 0xf7, 0xee, 0x30,
-	0x10, 0x99, 0xc2, 0x5f, 0x1a, 0x34, 0x14, 0x92, 0xaa, 0x3a, 0x01, 0x40, 0x89, 0x8c, 0x45, 0xc4,
-	0x12, 0x5f, 0x34, 0x3a, 0xbb, 0x8a, 0x35, 0xae, 0x1d, 0x45, 0x0c, 0xbd, 0x2a, 0xaa, 0x25, 0x31,
-	0x60, 0x8b, 0x2f, 0x17, 0x0b, 0x3f, 0x8c, 0xd2, 0xee, 0x54, 0x28, 0x33, 0x53, 0x14, 0xfe, 0x6c,
-	0xce, 0x53, 0xa3, 0xa8, 0x30, 0xf7, 0x36, 0xa5, 0xdc, 0xdb, 0x1c, 0x7f, 0x06, 0x58, 0x89, 0x20,
-	0x35, 0xd8, 0x72, 0x06, 0xc3, 0x91, 0xdd, 0xef, 0xeb, 0x1b, 0x64, 0x0f, 0xc8, 0xd0, 0x3e, 0xbf,
-	0xe8, 0xf7, 0xc6, 0xf6, 0xc5, 0x45, 0xdf, 0xe9, 0xda, 0x23, 0xc7, 0x1d, 0xe8, 0x1a, 0xd9, 0x86,
-	0x6a, 0xd7, 0x1d, 0x9c, 0x3a, 0x67, 0x97, 0x5e, 0x4f, 0x2f, 0x90, 0x3a, 0x54, 0x3e, 0xda, 0x7d,
-	0xe7, 0x83, 0x3d, 0xea, 0xe9, 0x45, 0x02, 0x50, 0xee, 0x5e, 0x0e, 0x47, 0xee, 0xb9, 0x5e, 0x3a,
-	0x3e, 0x86, 0x6a, 0x26, 0x85, 0x54, 0xa0, 0xe4, 0x0c, 0x4e, 0x5d, 0x7d, 0x43, 0xae, 0x3e, 0xd9,
-	0x9e, 0x64, 0xaa, 0xc2, 0x66, 0xcf, 0xf3, 0x5c, 0x4f, 0x2f, 0x74, 0x7e, 0x17, 0xa1, 0x26, 0x47,
-	0x6c, 0x88, 0xe1, 0xdd, 0x6c, 0x82, 0xe4, 0x2b, 0x90, 0xfc, 0x88, 0x92, 0x23, 0x75, 0x45, 0x8f,
-	0xfe, 0x0d, 0xa6, 0xf5, 0x54, 0x49, 0x3a, 0xe1, 0x1b, 0xe4, 0x1d, 0x54, 0xd4, 0x40, 0x93, 0xe7,
-	0x6a, 0xc7, 0x83, 0xa9, 0x37, 0x8d, 0x7c, 0x22, 0x23, 0x38, 0x83, 0x46, 0x3c, 0x21, 0x2b, 0x3b,
-	0x65, 0xd5, 0x0f, 0x3f, 0x00, 0x73, 0x7f, 0x4d, 0x26, 0x23, 0xfa, 0x06, 0xff, 0xad, 0xb1, 0x3f,
-	0xb1, 0x1e, 0x77, 0xba, 0xf2, 0x95, 0xf9, 0xf2, 0xc9, 0x9a, 0xec, 0x04, 0x1b, 0xea, 0x43, 0x11,
-	0xa2, 0xbf, 0x48, 0x3c, 0x48, 0x9e, 0xfd, 0xe3, 0xb3, 0x8c, 0x6d, 0xef, 0x21, 0xac, 0x08, 0x4e,
-	0xb4, 0xab, 0x72, 0xfc, 0x39, 0xbf, 0xf9, 0x1b, 0x00, 0x00, 0xff, 0xff, 0x27, 0xa3, 0x6e, 0x5a,
-	0xad, 0x05, 0x00, 0x00,
+func init() { proto.RegisterFile("meshops.proto", fileDescriptor_meshops_32ebf6e0158a3e62) }
+
+var fileDescriptor_meshops_32ebf6e0158a3e62 = []byte{
+	// 5279 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x3c, 0x4d, 0x6f, 0xe5, 0x58,
+	0x56, 0xed, 0xf7, 0x91, 0xbc, 0x77, 0x92, 0x97, 0xbc, 0xb8, 0x92, 0xd4, 0x8b, 0xeb, 0xb3, 0x5d,
+	0xcc, 0x74, 0x53, 0x3d, 0x5d, 0x14, 0xd5, 0x54, 0xd3, 0xd5, 0xd0, 0x40, 0x2a, 0x95, 0x6a, 0xc2,
+	0xa4, 0x2a, 0xc1, 0x49, 0x75, 0x0f, 0xcc, 0x68, 0x2c, 0xc7, 0xbe, 0x49, 0x3c, 0xf1, 0xb3, 0x3d,
+	0xf6, 0x75, 0xaa, 0xde, 0xac, 0x90, 0x10, 0x82, 0x99, 0xc5, 0x0c, 0xbd, 0xe0, 0x63, 0xc1, 0x87,
+	0xc4, 0x06, 0xc1, 0x02, 0x0d, 0x0b, 0x34, 0x0b, 0xa4, 0xd9, 0xc0, 0x0e, 0x09, 0x69, 0x10, 0x0b,
+	0x24, 0x96, 0x23, 0xb1, 0x61, 0xc7, 0x2f, 0x40, 0xe7, 0x7e, 0xd8, 0xd7, 0x7e, 0xf6, 0x4b, 0x5a,
+	0xd5, 0x48, 0xec, 0xde, 0xf9, 0xb8, 0x9f, 0xe7, 0xdc, 0x73, 0xcf, 0x39, 0xf7, 0xf8, 0xc1, 0x60,
+	0x4c, 0xd2, 0xd3, 0x28, 0x4e, 0xef, 0xc5, 0x49, 0x44, 0x23, 0x7d, 0x0e, 0x41, 0x92, 0x9a, 0xff,
+	0xa1, 0xc1, 0xc6, 0x56, 0x42, 0x1c, 0x4a, 0x9e, 0x91, 0xf4, 0x74, 0x27, 0x4c, 0xa9, 0x13, 0xba,
+	0xc4, 0x22, 0xdf, 0xce, 0x48, 0x4a, 0xf5, 0xeb, 0xd0, 0x3f, 0xfb, 0x20, 0xdd, 0x8a, 0xc2, 0x63,
+	0xff, 0x64, 0xa4, 0xdd, 0xd6, 0xde, 0x5e, 0xb4, 0x0a, 0x84, 0x7e, 0x1b, 0x16, 0xdc, 0x28, 0xa4,
+	0xe4, 0x15, 0x7d, 0xee, 0x8c, 0xc9, 0xa8, 0x75, 0x5b, 0x7b, 0xbb, 0x6f, 0xa9, 0x28, 0x7d, 0x15,
+	0xba, 0x34, 0x3a, 0x23, 0xe1, 0xa8, 0xcd, 0x68, 0x1c, 0xd0, 0xd7, 0x61, 0x2e, 0x25, 0xc9, 0x39,
+	0x49, 0x46, 0x1d, 0x86, 0x16, 0x90, 0xfe, 0x1e, 0xac, 0xb9, 0x24, 0xa1, 0xfe, 0xb1, 0xef, 0x3a,
+	0x94, 0xd8, 0x4e, 0x46, 0x4f, 0xa3, 0xc4, 0xa7, 0x93, 0x51, 0x97, 0x8d, 0xbc, 0xaa, 0x10, 0x37,
+	0x25, 0x4d, 0x1f, 0xc1, 0xbc, 0x1b, 0x64, 0x29, 0x25, 0xc9, 0x68, 0x8e, 0xf5, 0x26, 0x41, 0xf3,
+	0xab, 0x60, 0xd4, 0xad, 0x2c, 0x8d, 0xa3, 0x30, 0x25, 0xfa, 0xbb, 0x30, 0xe7, 0xb8, 0x2e, 0x49,
+	0x53, 0xb6, 0xae, 0x85, 0x07, 0x6b, 0xf7, 0xf8, 0x8e, 0xdc, 0xdb, 0xe2, 0xcd, 0x37, 0x19, 0xd1,
+	0x12, 0x4c, 0xe6, 0x0a, 0x2c, 0x63, 0x37, 0xb8, 0x2a, 0xb1, 0x39, 0xe6, 0x97, 0x61, 0x58, 0xa0,
+	0x44, 0xaf, 0x3a, 0x74, 0x42, 0xdc, 0x0b, 0x8d, 0x4d, 0x85, 0xfd, 0x36, 0x7f, 0xd8, 0x81, 0xe1,
+	0x66, 0x1c, 0x07, 0x13, 0x2b, 0x0b, 0xf2, 0x9d, 0x5d, 0x87, 0xb9, 0x28, 0x7e, 0x5e, 0xb0, 0x0a,
+	0x08, 0x77, 0x1c, 0x1b, 0xa5, 0xb1, 0xe3, 0xca, 0x1d, 0x2d, 0x10, 0xba, 0x01, 0xbd, 0x2c, 0x25,
+	0x09, 0x1b, 0x82, 0x6f, 0x69, 0x0e, 0xeb, 0xb7, 0x60, 0xc1, 0xcd, 0x52, 0x1a, 0x8d, 0xed, 0xa3,
+	0xc8, 0x9b, 0x88, 0xad, 0x05, 0x8e, 0x7a, 0x1c, 0x79, 0x13, 0xfd, 0x1a, 0xf4, 0x3d, 0x12, 0x10,
+	0x4a, 0xec, 0x28, 0x66, 0x5b, 0xda, 0xb3, 0x7a, 0x1c, 0xb1, 0x17, 0xeb, 0x6f, 0xc2, 0x62, 0x14,
+	0x93, 0xc4, 0xa1, 0x7e, 0x14, 0xda, 0xbe, 0x27, 0xf6, 0x72, 0x21, 0xc7, 0xed, 0x78, 0xea, 0x4e,
+	0xcf, 0x97, 0x76, 0x5a, 0xbf, 0x0f, 0xab, 0x4e, 0x1c, 0x07, 0x3e, 0xf1, 0xec, 0x52, 0x27, 0x3d,
+	0xc6, 0xa6, 0x0b, 0xda, 0x9e, 0xd2, 0xd7, 0x2a, 0x74, 0x8f, 0xa3, 0xc4, 0x25, 0xa3, 0x3e, 0x9b,
+	0x07, 0x07, 0xf4, 0x5f, 0x07, 0x88, 0x9d, 0xc4, 0x19, 0x13, 0x4a, 0x92, 0x74, 0x04, 0xb7, 0xdb,
+	0x6f, 0x2f, 0x3c, 0x78, 0x5b, 0xca, 0xa5, 0xba, 0x85, 0xf7, 0xf6, 0x73, 0xd6, 0xed, 0x90, 0x26,
+	0x13, 0x4b, 0x69, 0xab, 0x7f, 0x09, 0x96, 0x12, 0x12, 0x47, 0x09, 0xb5, 0x3d, 0x12, 0xfa, 0x4e,
+	0x90, 0x8e, 0x16, 0xd8, 0x40, 0x03, 0x8e, 0x7d, 0xc2, 0x91, 0xfa, 0x3d, 0xb8, 0x92, 0x90, 0x34,
+	0x1b, 0x93, 0xf2, 0xbc, 0x17, 0xd9, 0xbc, 0x57, 0x38, 0x49, 0x9d, 0xf6, 0x5b, 0xb0, 0xec, 0x46,
+	0xe1, 0x71, 0xe0, 0xbb, 0xd4, 0x8e, 0xa3, 0xc0, 0x77, 0x27, 0xa3, 0x01, 0xe3, 0x5d, 0x92, 0xe8,
+	0x7d, 0x86, 0x35, 0x3e, 0x82, 0xe5, 0xca, 0xf4, 0xf4, 0x21, 0xb4, 0xcf, 0xc8, 0x44, 0x88, 0x1b,
+	0x7f, 0xe2, 0x26, 0x9c, 0x3b, 0x41, 0x26, 0xe5, 0xcc, 0x81, 0x0f, 0x5b, 0x1f, 0x68, 0xe6, 0x29,
+	0xac, 0x28, 0xcb, 0x15, 0xba, 0xb5, 0x0a, 0x5d, 0x92, 0x24, 0x51, 0x22, 0xba, 0xe0, 0xc0, 0x94,
+	0xe0, 0x5a, 0xd3, 0x82, 0x33, 0xa0, 0xf7, 0xd2, 0x49, 0x42, 0x3f, 0x3c, 0x49, 0x47, 0xed, 0xdb,
+	0x6d, 0xd4, 0x1a, 0x09, 0x9b, 0x3f, 0x68, 0x81, 0x71, 0x90, 0xc5, 0xb8, 0x29, 0x8a, 0x84, 0x52,
+	0xa9, 0xa6, 0xd7, 0xa0, 0x1f, 0x3b, 0x27, 0xc4, 0x4e, 0xfd, 0xef, 0x70, 0x4d, 0xed, 0x5a, 0x3d,
+	0x44, 0x1c, 0xf8, 0xdf, 0x21, 0xfa, 0x0d, 0x14, 0xd7, 0x09, 0xb1, 0xf9, 0x11, 0x17, 0xca, 0x8a,
+	0x98, 0x43, 0x44, 0xe8, 0x0f, 0x00, 0xf0, 0xa8, 0x9e, 0x44, 0x89, 0x4f, 0xf8, 0xc0, 0x4b, 0x0f,
+	0x74, 0x29, 0xcd, 0xbd, 0x78, 0x8b, 0xd3, 0x26, 0x96, 0xc2, 0x85, 0xc7, 0xe2, 0xd8, 0x0f, 0x68,
+	0x61, 0x1a, 0x38, 0xa4, 0xdf, 0x04, 0xf0, 0x48, 0x1c, 0x44, 0x93, 0x31, 0x09, 0x29, 0x53, 0xde,
+	0xbe, 0xa5, 0x60, 0x9a, 0xad, 0x80, 0xfe, 0x73, 0x70, 0xc5, 0x0f, 0xdd, 0x20, 0xf3, 0x88, 0x9d,
+	0x85, 0xce, 0xb9, 0xe3, 0x07, 0xce, 0x51, 0x40, 0x98, 0x06, 0xf7, 0x2c, 0x5d, 0x90, 0x5e, 0x14,
+	0x14, 0xf3, 0xa7, 0x1a, 0x5c, 0xab, 0xdd, 0x11, 0x21, 0x86, 0xaf, 0x40, 0x3b, 0x8a, 0xd1, 0x6a,
+	0xa0, 0x76, 0x1a, 0x72, 0x3d, 0xd3, 0x2d, 0x2c, 0x64, 0x2b, 0x84, 0xd6, 0x52, 0x85, 0xf6, 0x65,
+	0x58, 0x0e, 0xc9, 0x2b, 0x6a, 0x2b, 0xdb, 0xc7, 0x8f, 0xf3, 0x00, 0xd1, 0xfb, 0xf9, 0x16, 0xbe,
+	0x03, 0x2b, 0x3e, 0x1a, 0xae, 0x20, 0x20, 0x9e, 0x7d, 0x4e, 0x92, 0xd4, 0x8f, 0x42, 0xb1, 0x33,
+	0xc3, 0x9c, 0xf0, 0x09, 0xc7, 0x23, 0x73, 0xe0, 0xbb, 0x24, 0x4c, 0x89, 0x67, 0x1f, 0x13, 0x87,
+	0x66, 0x09, 0x49, 0x47, 0x5d, 0x26, 0xef, 0xa1, 0x24, 0x3c, 0x15, 0x78, 0xf3, 0x5f, 0x34, 0xd0,
+	0xa7, 0xe7, 0x7c, 0x59, 0x25, 0xd5, 0xef, 0x41, 0x4f, 0x48, 0x6d, 0xc2, 0x66, 0x5e, 0x2f, 0xd9,
+	0x9c, 0x07, 0x8d, 0xd3, 0xd8, 0x0f, 0x2b, 0x4b, 0x80, 0xb1, 0x1f, 0xca, 0xc9, 0x1b, 0xd0, 0xab,
+	0xcc, 0x39, 0x87, 0xf1, 0x9e, 0x51, 0x45, 0x27, 0x4c, 0x93, 0x82, 0x32, 0x4f, 0x60, 0xb0, 0x7d,
+	0x4e, 0x42, 0x9a, 0xeb, 0xed, 0x7b, 0xb0, 0x88, 0xe3, 0xa5, 0xe4, 0x9c, 0xb0, 0x1b, 0x44, 0x63,
+	0x73, 0x1c, 0xe6, 0xd2, 0x12, 0x78, 0x0b, 0x67, 0x25, 0x81, 0x4b, 0x1c, 0x25, 0xf3, 0x6f, 0x5a,
+	0xb0, 0x24, 0x47, 0x12, 0xfa, 0x70, 0x1f, 0x80, 0x20, 0xc6, 0xa6, 0x93, 0x98, 0x88, 0x81, 0x56,
+	0xe4, 0x40, 0x8c, 0xf7, 0x70, 0x12, 0x13, 0xab, 0x4f, 0xe4, 0x4f, 0x54, 0xd6, 0x34, 0x1b, 0x8f,
+	0x9d, 0x64, 0x22, 0x86, 0x90, 0x20, 0x52, 0x3c, 0x42, 0x1d, 0x3f, 0x48, 0x85, 0x3e, 0x48, 0x70,
+	0x6a, 0x6e, 0x9d, 0xe9, 0x63, 0xfe, 0x15, 0xe8, 0xe5, 0xeb, 0xed, 0x36, 0xac, 0x37, 0xe7, 0x60,
+	0x97, 0x70, 0x94, 0x25, 0xae, 0xdc, 0x4f, 0x01, 0xe1, 0x0d, 0x46, 0xfd, 0x31, 0x11, 0x26, 0x9e,
+	0xfd, 0x46, 0xe1, 0xa4, 0xb8, 0xb1, 0xa1, 0x4b, 0x98, 0x4d, 0xef, 0x58, 0x39, 0x8c, 0x53, 0x26,
+	0x81, 0x13, 0xa7, 0xc4, 0x63, 0xb6, 0xbc, 0x6f, 0x49, 0xd0, 0xbc, 0x0e, 0x86, 0xb8, 0x4b, 0xb7,
+	0x9c, 0xd8, 0x39, 0xf2, 0x03, 0x9f, 0xfa, 0x44, 0x4a, 0xc8, 0xfc, 0xac, 0x0d, 0xd7, 0x6a, 0xc9,
+	0xf9, 0xfd, 0xac, 0x9f, 0x65, 0x47, 0x24, 0x09, 0x09, 0x25, 0x69, 0xae, 0x38, 0x5c, 0x31, 0x57,
+	0x0a, 0x8a, 0xd4, 0x1f, 0xbc, 0xfd, 0x42, 0xdf, 0x8e, 0x83, 0xec, 0xc4, 0x0f, 0xd3, 0x51, 0x8b,
+	0xa9, 0x10, 0xb8, 0xa1, 0xbf, 0xcf, 0x31, 0xd8, 0x9f, 0xe3, 0x8d, 0xfd, 0x14, 0xb9, 0xed, 0x97,
+	0xe4, 0xe8, 0x34, 0x8a, 0xce, 0xf8, 0x2e, 0xf7, 0xac, 0x95, 0x9c, 0xf2, 0xa9, 0x20, 0xe0, 0x7e,
+	0xc7, 0x91, 0x67, 0xa7, 0xc4, 0xcd, 0xd8, 0x86, 0x8a, 0xfd, 0x8e, 0x23, 0xef, 0x40, 0xa0, 0xf4,
+	0x8f, 0x60, 0x39, 0xa5, 0x51, 0x82, 0x47, 0xd8, 0x0d, 0x9c, 0x34, 0x15, 0x9a, 0xbb, 0xf0, 0x60,
+	0x35, 0xdf, 0x76, 0x4e, 0xde, 0x42, 0xaa, 0xb5, 0x94, 0x2a, 0x10, 0x49, 0xf5, 0x3b, 0x30, 0x08,
+	0x22, 0xc7, 0xb3, 0x8f, 0x9c, 0x00, 0x1d, 0x13, 0x6e, 0xb8, 0x7a, 0xd6, 0x22, 0x22, 0x1f, 0x0b,
+	0x5c, 0x61, 0x3e, 0xe6, 0x55, 0xf3, 0xf1, 0x25, 0x58, 0x0a, 0x23, 0x8f, 0xd8, 0x71, 0xe0, 0xd0,
+	0xe3, 0x28, 0x19, 0xa7, 0xa3, 0x1e, 0x5b, 0xef, 0x00, 0xb1, 0xfb, 0x12, 0x89, 0x8d, 0xc3, 0x88,
+	0x92, 0x74, 0xd4, 0x67, 0x54, 0x0e, 0xe8, 0x1b, 0xd0, 0xf3, 0x63, 0x3b, 0xa5, 0x8e, 0x7b, 0x36,
+	0x02, 0x2e, 0x31, 0x3f, 0x3e, 0x40, 0xd0, 0xfc, 0x26, 0x2c, 0xaa, 0x53, 0xae, 0xf3, 0x66, 0xf0,
+	0x30, 0xc6, 0x49, 0x74, 0xee, 0xe3, 0x6e, 0x11, 0x69, 0xd6, 0x54, 0x14, 0x57, 0xe2, 0x63, 0x27,
+	0x0b, 0xa8, 0xd8, 0x5e, 0x09, 0x9a, 0xff, 0xa0, 0xc1, 0xea, 0x7e, 0x12, 0xbd, 0x9a, 0x08, 0xa9,
+	0xe5, 0xc7, 0xb5, 0x6c, 0xde, 0xb5, 0x29, 0xf3, 0x5e, 0xba, 0x86, 0x5a, 0x33, 0xaf, 0xa1, 0x76,
+	0xf5, 0x1a, 0x2a, 0x79, 0x54, 0x9d, 0xaa, 0x47, 0x75, 0x07, 0x06, 0x51, 0x46, 0x3d, 0x87, 0xa2,
+	0xef, 0x12, 0x06, 0x13, 0xe1, 0x18, 0x2d, 0x4a, 0xe4, 0x5e, 0x18, 0x4c, 0xcc, 0x1f, 0x6b, 0xb0,
+	0x56, 0x99, 0xb7, 0xd0, 0xd2, 0x07, 0xb0, 0x86, 0xfe, 0x6e, 0x12, 0x05, 0x28, 0x8c, 0x90, 0x54,
+	0x14, 0xf5, 0x8a, 0x20, 0xee, 0x23, 0x4d, 0xaa, 0xea, 0x7b, 0xd0, 0x7f, 0x19, 0x25, 0x67, 0x28,
+	0x67, 0xae, 0xa8, 0x8a, 0xf3, 0xf9, 0xa9, 0x20, 0xb0, 0xd1, 0xac, 0x82, 0xaf, 0x50, 0x84, 0xf6,
+	0x05, 0xf7, 0x48, 0xa7, 0xe6, 0x1e, 0x31, 0x7f, 0xa0, 0xc1, 0xa0, 0xd4, 0x75, 0x79, 0x57, 0xb4,
+	0xea, 0xae, 0xe8, 0xd0, 0x39, 0xf3, 0x43, 0x69, 0x01, 0xd9, 0xef, 0x5c, 0x19, 0xda, 0x8a, 0x32,
+	0x18, 0xd0, 0x13, 0x0b, 0x4e, 0x47, 0x1d, 0x6e, 0xb5, 0x25, 0xac, 0x5f, 0x07, 0xc8, 0x62, 0x9b,
+	0x46, 0x36, 0xee, 0xa3, 0xf4, 0x37, 0xb3, 0xf8, 0x30, 0x7a, 0xe2, 0x50, 0x62, 0x7e, 0x08, 0xa3,
+	0xed, 0x90, 0x79, 0x7d, 0x28, 0xe0, 0x03, 0xea, 0xd0, 0xec, 0xb2, 0xda, 0x60, 0xfe, 0xa1, 0x06,
+	0x1b, 0x35, 0x8d, 0x85, 0x48, 0x6e, 0xc1, 0xc2, 0x49, 0x10, 0x1d, 0x39, 0x81, 0x3d, 0x8e, 0x3c,
+	0xb9, 0x36, 0xe0, 0xa8, 0x67, 0x91, 0x47, 0xf4, 0x5f, 0x06, 0xc8, 0x57, 0x2a, 0x05, 0x70, 0x5d,
+	0x0a, 0xe0, 0xb9, 0xa4, 0x28, 0x03, 0x58, 0x0a, 0x7f, 0xbd, 0x20, 0xcc, 0x63, 0x58, 0xad, 0x6b,
+	0x79, 0xf1, 0x36, 0xb3, 0x39, 0x8a, 0x6d, 0xc6, 0xdf, 0xd8, 0xc2, 0x0f, 0x4f, 0xd1, 0x46, 0x13,
+	0x4f, 0x9c, 0x9f, 0x02, 0x61, 0xfe, 0xbe, 0x06, 0x57, 0xb9, 0x8b, 0xf9, 0x89, 0x1f, 0x05, 0x65,
+	0x5f, 0xed, 0xa2, 0x43, 0x34, 0x3b, 0xb4, 0x58, 0x87, 0xb9, 0x97, 0x7e, 0xe8, 0x45, 0x2f, 0xc5,
+	0xc2, 0x04, 0x84, 0xf8, 0xa3, 0xcc, 0x3d, 0x23, 0x54, 0x7a, 0x64, 0x1c, 0x32, 0xff, 0xa9, 0x05,
+	0xa3, 0xe9, 0x99, 0x14, 0xae, 0x6a, 0xea, 0x87, 0xf9, 0x92, 0x39, 0x80, 0xd8, 0x2c, 0xa4, 0x7e,
+	0x20, 0x5d, 0x09, 0x06, 0xf0, 0x18, 0x91, 0x3a, 0x01, 0x1b, 0xb7, 0x6d, 0x71, 0x40, 0x7f, 0xbf,
+	0x24, 0xa4, 0x0e, 0x13, 0xd2, 0xba, 0x14, 0x52, 0x3e, 0xe2, 0x56, 0x94, 0x55, 0xc4, 0xf3, 0x0b,
+	0xea, 0xe1, 0xea, 0xce, 0x6c, 0x56, 0x30, 0xea, 0x0f, 0xa0, 0xc7, 0xdc, 0x79, 0x9f, 0xa4, 0xa3,
+	0xb9, 0x99, 0x8d, 0x72, 0x3e, 0xfd, 0x5d, 0xe8, 0xd2, 0x84, 0x84, 0xde, 0x68, 0x9e, 0x35, 0xb8,
+	0x3a, 0xd5, 0xe0, 0x31, 0xdb, 0x28, 0x8b, 0x73, 0x15, 0x7a, 0xd3, 0x53, 0xf5, 0xe6, 0x15, 0x2c,
+	0x95, 0x07, 0xb8, 0x40, 0x63, 0xd0, 0x95, 0x17, 0xb3, 0x16, 0xbb, 0x98, 0xc3, 0x28, 0x29, 0x11,
+	0x93, 0x08, 0x09, 0x72, 0x08, 0x47, 0x76, 0xb1, 0x6b, 0x26, 0xc0, 0xb6, 0xc5, 0x01, 0xf3, 0x23,
+	0x58, 0xae, 0xcc, 0x94, 0x49, 0x8d, 0x3a, 0x09, 0xcd, 0xa5, 0x86, 0x40, 0xd1, 0xbc, 0xa5, 0x36,
+	0xff, 0x03, 0x0d, 0xae, 0x6e, 0xba, 0x67, 0x61, 0xf4, 0x32, 0x20, 0xde, 0x09, 0xd9, 0x0c, 0x48,
+	0x42, 0x2f, 0xab, 0x88, 0x1b, 0xd0, 0x73, 0x90, 0xbf, 0xf0, 0xb1, 0xe6, 0x19, 0xbc, 0xc3, 0xd6,
+	0x90, 0x10, 0x27, 0x8d, 0xa4, 0x1d, 0x17, 0x50, 0x29, 0xf0, 0xed, 0x94, 0x03, 0x5f, 0xf3, 0x3e,
+	0x8c, 0xa6, 0x67, 0x32, 0x2b, 0x66, 0x32, 0xff, 0x5c, 0x83, 0xe1, 0xb3, 0x8c, 0x7e, 0x61, 0xb3,
+	0x36, 0xa0, 0xe7, 0x65, 0xdc, 0x0f, 0x93, 0x61, 0xb9, 0x84, 0x95, 0x15, 0x75, 0x1a, 0x57, 0xd4,
+	0xad, 0xac, 0xe8, 0x37, 0x60, 0x45, 0x99, 0x5e, 0x61, 0xd7, 0xc6, 0x19, 0x5e, 0x53, 0xfc, 0x0c,
+	0x89, 0x09, 0x32, 0xd4, 0x0b, 0x79, 0x90, 0xa6, 0x43, 0x0d, 0xf3, 0x04, 0xae, 0x6e, 0xbf, 0x42,
+	0x37, 0xff, 0xab, 0xd9, 0x11, 0x71, 0x59, 0xe2, 0xe6, 0xb2, 0x2b, 0x56, 0xa7, 0xd8, 0x2a, 0x4f,
+	0x11, 0x03, 0x05, 0x4a, 0x03, 0xb1, 0x5a, 0xfc, 0x69, 0x46, 0x30, 0x9a, 0x1e, 0x48, 0xcc, 0xfd,
+	0x26, 0xc0, 0x59, 0x8e, 0x15, 0x89, 0x24, 0x05, 0x83, 0x57, 0x38, 0x79, 0x15, 0xfb, 0x09, 0x49,
+	0x6d, 0x87, 0x4a, 0xdb, 0x24, 0x30, 0x9b, 0xb4, 0xc1, 0xe6, 0xfe, 0xb1, 0x06, 0xa3, 0x03, 0xf7,
+	0x94, 0x78, 0x59, 0x50, 0x04, 0xe9, 0x72, 0x6d, 0x75, 0xae, 0x8b, 0x0e, 0x1d, 0x37, 0x89, 0x64,
+	0xa4, 0xca, 0x7e, 0xeb, 0xef, 0x43, 0x3f, 0xf7, 0xa1, 0x59, 0xf7, 0x0b, 0x0f, 0x46, 0x4d, 0x19,
+	0x07, 0xab, 0x60, 0x9d, 0xa9, 0x90, 0xbb, 0xb0, 0x51, 0x33, 0x2f, 0xb1, 0x15, 0x1b, 0xd0, 0x63,
+	0x57, 0x76, 0x92, 0x49, 0x27, 0x61, 0x1e, 0x61, 0x2b, 0x0b, 0x1b, 0x04, 0xf8, 0x2d, 0x58, 0xdd,
+	0xf5, 0x53, 0x2a, 0x7b, 0xfc, 0x42, 0x42, 0xf3, 0x22, 0xcc, 0x6e, 0xab, 0x61, 0xb6, 0xf9, 0x7b,
+	0x1a, 0xac, 0x55, 0x06, 0x13, 0xd3, 0xbe, 0x07, 0xfd, 0x54, 0x22, 0x45, 0xec, 0x5b, 0x44, 0x17,
+	0x82, 0x60, 0x15, 0x2c, 0xaf, 0x17, 0xf7, 0x9a, 0xff, 0xad, 0x41, 0x4f, 0xf6, 0xfa, 0x7f, 0x2e,
+	0x4a, 0x55, 0x22, 0x9d, 0xb2, 0x44, 0x36, 0xa0, 0x17, 0x38, 0x29, 0x27, 0xf1, 0x43, 0x3a, 0x8f,
+	0x30, 0x92, 0xee, 0xc2, 0x0a, 0x23, 0xd5, 0x64, 0xcd, 0x96, 0x91, 0xa0, 0xa6, 0x8d, 0x6e, 0x00,
+	0x30, 0x5e, 0xd5, 0x95, 0xef, 0x23, 0x66, 0x9b, 0x49, 0xf8, 0x63, 0x58, 0x7b, 0xc2, 0xf2, 0x70,
+	0xf9, 0x46, 0xce, 0x50, 0xe2, 0x19, 0x87, 0xd2, 0xbc, 0x07, 0xeb, 0xd5, 0x8e, 0x66, 0xda, 0xc1,
+	0x7f, 0xd3, 0x60, 0x50, 0x4a, 0x77, 0x62, 0x64, 0xc1, 0x93, 0xb1, 0x15, 0x47, 0x76, 0xc0, 0xb1,
+	0xd2, 0x85, 0xbd, 0x0f, 0xab, 0x78, 0x7a, 0xed, 0x74, 0x92, 0x52, 0x32, 0xb6, 0x13, 0xe2, 0x78,
+	0x2c, 0x34, 0x6f, 0xf1, 0xac, 0x0a, 0xd2, 0x0e, 0x18, 0xc9, 0x12, 0x94, 0xf2, 0xb5, 0xd6, 0xae,
+	0x5e, 0x6b, 0xab, 0xd0, 0x4d, 0xb2, 0x40, 0x5c, 0xf4, 0x7d, 0x8b, 0x03, 0x18, 0x48, 0xb0, 0xb0,
+	0x2c, 0x3c, 0x11, 0x29, 0x01, 0x09, 0x96, 0x32, 0x5a, 0x73, 0x95, 0x8c, 0xd6, 0x8f, 0x35, 0x18,
+	0x6d, 0xa7, 0xd4, 0x1f, 0x3b, 0x94, 0x3c, 0x8d, 0x22, 0x1a, 0x27, 0x7e, 0x78, 0x69, 0x23, 0x7f,
+	0x73, 0xca, 0x37, 0xec, 0x97, 0xdc, 0x0b, 0x03, 0x7a, 0x63, 0x27, 0xf4, 0x8f, 0x49, 0x4a, 0xa5,
+	0xa5, 0x97, 0x30, 0x1a, 0xe8, 0xd4, 0xf7, 0x88, 0xeb, 0x24, 0xb6, 0x1b, 0x67, 0x32, 0xc7, 0x21,
+	0x50, 0x5b, 0x71, 0xc6, 0x36, 0x57, 0x30, 0x8c, 0xc9, 0x18, 0x53, 0x27, 0x5d, 0xb1, 0xb9, 0x1c,
+	0xfb, 0x8c, 0x21, 0xcd, 0x1d, 0xe8, 0xe7, 0xf3, 0x46, 0x3b, 0x8b, 0x9d, 0x89, 0x84, 0x8c, 0x1b,
+	0x67, 0x78, 0x76, 0x45, 0x6b, 0x2e, 0x7e, 0x01, 0xa1, 0xb2, 0xc4, 0x91, 0xc7, 0x43, 0xda, 0xae,
+	0xc5, 0x7e, 0x9b, 0x9f, 0x69, 0xa0, 0xe7, 0x7e, 0x69, 0xd1, 0xe9, 0x85, 0x5e, 0x29, 0xeb, 0xa8,
+	0x55, 0x74, 0x84, 0xeb, 0xf6, 0xc3, 0x6f, 0x11, 0x57, 0x3a, 0xa5, 0x5d, 0x2b, 0x87, 0xf5, 0x77,
+	0xa1, 0x27, 0x16, 0x90, 0xb2, 0x45, 0x2f, 0x14, 0xe9, 0x8f, 0x62, 0xff, 0x73, 0x16, 0xf3, 0xdf,
+	0x5b, 0xb0, 0x51, 0x23, 0x1f, 0xa1, 0xa8, 0xef, 0xc3, 0xa0, 0x14, 0x50, 0x8d, 0xb4, 0xa6, 0x1e,
+	0x17, 0xd5, 0xd8, 0x0a, 0x35, 0xb2, 0x1c, 0x88, 0x89, 0xe4, 0x06, 0xdf, 0x23, 0x5d, 0xe5, 0x3d,
+	0x60, 0x14, 0xfd, 0x1d, 0x98, 0x17, 0x73, 0x1a, 0xb5, 0x9b, 0xc6, 0x90, 0x1c, 0xaa, 0xe8, 0x44,
+	0xc7, 0x9d, 0x92, 0xe8, 0x44, 0x9f, 0x1f, 0x96, 0xd4, 0xa7, 0x5b, 0x4e, 0x11, 0x4e, 0x0b, 0xa2,
+	0xa4, 0x5a, 0x6f, 0x49, 0x3f, 0x78, 0xae, 0x69, 0x36, 0x9c, 0x5e, 0x9f, 0x13, 0x30, 0xd7, 0xf1,
+	0x9a, 0x08, 0xe9, 0x21, 0x19, 0x63, 0x56, 0xa0, 0xc8, 0xb3, 0xfc, 0x48, 0x83, 0x45, 0x89, 0xdc,
+	0x15, 0xc2, 0x2f, 0xcc, 0xa4, 0x10, 0x7e, 0xe9, 0x5e, 0xa3, 0x82, 0x5b, 0x9a, 0x17, 0x09, 0xe3,
+	0x79, 0x8c, 0x8e, 0x50, 0xe8, 0x52, 0xc9, 0x24, 0x58, 0x4c, 0xa9, 0xa3, 0x5a, 0x7b, 0x74, 0x8b,
+	0xfc, 0x14, 0x8f, 0xbf, 0x97, 0xbf, 0x37, 0x08, 0x18, 0xf3, 0x2b, 0xb2, 0x5f, 0x3b, 0x25, 0x54,
+	0x26, 0xf5, 0x24, 0xee, 0x80, 0x50, 0xf3, 0x3f, 0xd9, 0x65, 0x54, 0x5a, 0x52, 0x1e, 0x75, 0xf7,
+	0x25, 0xa3, 0xbc, 0x8c, 0xf2, 0x9c, 0x8b, 0xba, 0x56, 0xab, 0x60, 0x6b, 0xb8, 0x90, 0x30, 0xa1,
+	0xef, 0x50, 0x27, 0x88, 0x4e, 0x72, 0x83, 0xd7, 0x16, 0x09, 0x7d, 0x8e, 0x96, 0x16, 0xef, 0x2e,
+	0xac, 0x48, 0xc6, 0x74, 0x12, 0xba, 0xc4, 0x43, 0x47, 0x85, 0xaf, 0x56, 0xf6, 0x70, 0xc0, 0xf0,
+	0x9b, 0x14, 0x73, 0x0a, 0x92, 0x97, 0x0f, 0xc9, 0x8f, 0xf9, 0xa2, 0x40, 0x72, 0xa3, 0x7f, 0x1d,
+	0x8c, 0x4d, 0xcf, 0x89, 0x1b, 0xb2, 0x63, 0xff, 0xda, 0x86, 0x6b, 0xb5, 0xe4, 0xe6, 0x77, 0x26,
+	0x14, 0x8f, 0x5c, 0x83, 0xf0, 0x4f, 0x05, 0x88, 0xb9, 0x2f, 0x8f, 0xa4, 0x6e, 0xe2, 0xc7, 0x34,
+	0x4a, 0x4a, 0x0b, 0xed, 0x5a, 0x2b, 0x05, 0x45, 0xae, 0x55, 0x87, 0x4e, 0x12, 0xbb, 0xd2, 0x18,
+	0xb3, 0xdf, 0xa8, 0xd9, 0xb9, 0x92, 0x4c, 0x69, 0x76, 0x4d, 0xf2, 0x5b, 0xe1, 0xc6, 0x14, 0xbc,
+	0x94, 0xbb, 0xad, 0x74, 0xc2, 0x0d, 0xb7, 0x2e, 0x49, 0x7b, 0x45, 0x83, 0xeb, 0xd0, 0x4f, 0x69,
+	0x42, 0x9c, 0x31, 0x9a, 0xfe, 0x79, 0xc6, 0x56, 0x20, 0x70, 0x7b, 0xc7, 0x59, 0x40, 0x7d, 0x5b,
+	0x66, 0xfc, 0x7b, 0x3c, 0x65, 0xc3, 0x90, 0xe2, 0x3a, 0xc3, 0x2b, 0x17, 0xdf, 0x0f, 0x59, 0x0e,
+	0x40, 0x26, 0xc0, 0xfa, 0x88, 0xc1, 0x14, 0x40, 0x8a, 0x66, 0x35, 0x1d, 0xfb, 0x2c, 0xff, 0xd5,
+	0xb3, 0xf0, 0x27, 0xc7, 0xc4, 0xe2, 0x99, 0x08, 0x7f, 0x16, 0x1a, 0xb3, 0xa8, 0x6a, 0xcc, 0x43,
+	0xe8, 0x89, 0x71, 0xd3, 0xd1, 0x80, 0x6d, 0xc3, 0x46, 0xe5, 0xe5, 0x70, 0x2b, 0x0a, 0x43, 0xe2,
+	0xb2, 0x5d, 0xc8, 0x59, 0x31, 0x03, 0x33, 0xdc, 0x09, 0x31, 0x05, 0x8c, 0x89, 0xf1, 0xe2, 0x79,
+	0x75, 0x86, 0x1d, 0xbe, 0xc4, 0xcb, 0x4e, 0xc9, 0x07, 0x6c, 0xcf, 0xf4, 0x01, 0x3b, 0x15, 0x1f,
+	0xd0, 0xfc, 0x9e, 0x06, 0x2b, 0xca, 0x8c, 0x84, 0x62, 0xfd, 0x22, 0xf4, 0x13, 0xc2, 0x4d, 0x9c,
+	0x3c, 0x5a, 0xf9, 0xfa, 0x54, 0x6e, 0xc6, 0x61, 0x15, 0xbc, 0xaf, 0xe9, 0xf0, 0xfd, 0xa8, 0x55,
+	0x9e, 0x0c, 0x37, 0xa7, 0xb7, 0x60, 0xc1, 0x89, 0xfd, 0x8a, 0x2b, 0x02, 0x4e, 0xec, 0x2b, 0x9a,
+	0x3a, 0x95, 0xa7, 0x9a, 0xed, 0x69, 0xc8, 0x83, 0xd3, 0x51, 0x0e, 0x4e, 0xc9, 0x22, 0x76, 0xab,
+	0x16, 0xf1, 0x12, 0x2f, 0xa3, 0xa8, 0x6c, 0xe2, 0xfd, 0xd3, 0xa1, 0xd2, 0xbf, 0x13, 0x98, 0x4d,
+	0xf6, 0xd6, 0x7b, 0x4a, 0x9c, 0x80, 0x9e, 0x8a, 0xd8, 0x5f, 0x40, 0xa8, 0xc8, 0xfc, 0x97, 0x2d,
+	0x22, 0x44, 0x9e, 0x40, 0x5f, 0xe4, 0x48, 0x8b, 0xe1, 0x2a, 0x1e, 0x0b, 0x4c, 0x25, 0xc3, 0xfe,
+	0x42, 0x83, 0x95, 0x29, 0xc5, 0x53, 0xdf, 0xc3, 0xb4, 0xf2, 0x7b, 0x18, 0x0f, 0xf2, 0x73, 0xeb,
+	0xce, 0x81, 0x22, 0x61, 0xd3, 0xae, 0x24, 0x6c, 0x6a, 0xcc, 0xfa, 0xbb, 0xa0, 0x27, 0xc4, 0xe5,
+	0x63, 0xd9, 0x0e, 0x45, 0x13, 0x4b, 0x53, 0xb6, 0x6f, 0x5d, 0x6b, 0x25, 0xa7, 0x6c, 0x0a, 0x82,
+	0xf9, 0x93, 0x16, 0xac, 0x5b, 0x24, 0xf4, 0x48, 0x32, 0x15, 0xa4, 0xfd, 0x7f, 0x7b, 0x04, 0x6f,
+	0x7e, 0x45, 0x7c, 0x5e, 0x7a, 0x99, 0xe6, 0x19, 0x9f, 0x7b, 0xf2, 0x5c, 0xd4, 0xaf, 0x6e, 0xd6,
+	0xfb, 0xf4, 0xeb, 0xbe, 0x0f, 0xff, 0xae, 0x06, 0x57, 0xa7, 0x46, 0x15, 0x27, 0x58, 0x75, 0x51,
+	0xb5, 0x8a, 0x8b, 0x3a, 0x7b, 0x63, 0x4b, 0xf7, 0x3b, 0xf3, 0xb7, 0x67, 0xde, 0xef, 0xe6, 0x1f,
+	0x69, 0xb0, 0x21, 0xb3, 0xca, 0x3b, 0x1e, 0x09, 0xa9, 0x7a, 0x85, 0x5d, 0x60, 0xdc, 0xca, 0x6a,
+	0xdd, 0x9a, 0x9d, 0xf1, 0xff, 0x9c, 0x96, 0xed, 0xb3, 0x16, 0x18, 0x75, 0xf3, 0xca, 0x5d, 0x4c,
+	0x25, 0x45, 0xc8, 0x4d, 0xdc, 0xa8, 0x9a, 0x7f, 0x17, 0xcd, 0x4a, 0x29, 0xf8, 0xa7, 0x30, 0xc4,
+	0x28, 0xc8, 0x77, 0x89, 0xed, 0xb8, 0x2c, 0x0b, 0x26, 0xb3, 0xc7, 0xd7, 0x8a, 0x77, 0x36, 0x46,
+	0xdf, 0xe4, 0xe4, 0x17, 0xa9, 0x73, 0x42, 0xac, 0xe5, 0xb4, 0x84, 0x4c, 0xf5, 0x87, 0x00, 0x09,
+	0x39, 0xf1, 0x53, 0x9a, 0xbf, 0x8b, 0x2b, 0x0f, 0x00, 0x16, 0xa7, 0x4c, 0x78, 0x5b, 0x85, 0xb1,
+	0xe1, 0x30, 0xd6, 0x18, 0xd8, 0x6e, 0x9d, 0x81, 0xfd, 0xb3, 0x36, 0x0c, 0xab, 0x8b, 0xfb, 0x82,
+	0x1e, 0x01, 0x64, 0xbc, 0xd0, 0x51, 0xe2, 0x85, 0xb7, 0x60, 0xb9, 0xb2, 0x57, 0x62, 0x5a, 0x4b,
+	0xe5, 0xdd, 0x40, 0x46, 0x27, 0xa3, 0xd1, 0x18, 0x01, 0x31, 0x7f, 0xfe, 0x0e, 0xb6, 0x94, 0xa3,
+	0xf3, 0x94, 0x85, 0x3f, 0x76, 0x4e, 0x48, 0x2a, 0x1c, 0x02, 0x01, 0xa1, 0x22, 0xc5, 0x89, 0x7f,
+	0xee, 0x07, 0xe4, 0x84, 0x78, 0xc2, 0x15, 0x50, 0x30, 0x68, 0xbe, 0x4f, 0xa3, 0x94, 0xda, 0x21,
+	0xa1, 0x28, 0x4a, 0x51, 0x70, 0xb2, 0x80, 0xb8, 0xe7, 0x1c, 0x85, 0x51, 0x3e, 0x63, 0x89, 0x7d,
+	0x4f, 0x78, 0x04, 0xf3, 0x08, 0xef, 0xfb, 0x5e, 0x4e, 0xf2, 0x63, 0x77, 0xb4, 0x50, 0x90, 0x76,
+	0x62, 0xb7, 0x34, 0x70, 0x3a, 0x5a, 0xe4, 0xa1, 0x62, 0x81, 0xc1, 0xe7, 0xf8, 0xc8, 0xa5, 0x4e,
+	0xe2, 0x87, 0xc4, 0xf6, 0xc5, 0x8e, 0xb3, 0x6a, 0x91, 0x9e, 0x35, 0x94, 0x04, 0x29, 0x09, 0xd3,
+	0x86, 0x2b, 0x35, 0xba, 0x53, 0xeb, 0xe6, 0x5d, 0xaf, 0x3e, 0x1f, 0xf5, 0x55, 0x25, 0x5d, 0x87,
+	0x39, 0xf2, 0xca, 0x4f, 0xa9, 0x7c, 0xda, 0x14, 0x90, 0xb9, 0x05, 0x83, 0x92, 0x6a, 0xa1, 0x99,
+	0x10, 0xca, 0x25, 0x6d, 0x4e, 0x0e, 0x2b, 0x7b, 0xdd, 0x52, 0xf7, 0xda, 0x7c, 0x00, 0xc3, 0x4f,
+	0x08, 0xb5, 0x58, 0x09, 0xcd, 0x65, 0x1f, 0x6b, 0xfe, 0x4e, 0x83, 0x15, 0xa5, 0x51, 0x91, 0x10,
+	0xbc, 0xe8, 0xc1, 0xef, 0x9c, 0x50, 0xca, 0x2f, 0x54, 0x11, 0x87, 0x70, 0xc4, 0x26, 0xd5, 0xef,
+	0xc1, 0x9c, 0x7b, 0x4a, 0xdc, 0x33, 0x79, 0x78, 0x8a, 0x5c, 0x3d, 0xa1, 0x5b, 0x48, 0xb0, 0x48,
+	0x9a, 0x05, 0xd4, 0x12, 0x5c, 0x2c, 0xdb, 0xe5, 0xf8, 0x18, 0x85, 0x70, 0x15, 0x15, 0x50, 0x71,
+	0xa2, 0xba, 0xaa, 0x55, 0xfb, 0x2f, 0x0d, 0x96, 0xca, 0x1d, 0x35, 0x89, 0x61, 0xf6, 0x6b, 0x4a,
+	0xec, 0xa4, 0x69, 0xfe, 0x84, 0x23, 0x20, 0x34, 0xb1, 0x38, 0x78, 0x96, 0x48, 0x0f, 0x44, 0x82,
+	0xfc, 0x8d, 0x5d, 0x79, 0xbd, 0xef, 0x2b, 0x6f, 0xf5, 0x37, 0xd1, 0x62, 0x1c, 0x93, 0x84, 0x84,
+	0x2e, 0x91, 0x7e, 0xb3, 0x82, 0xc1, 0xb6, 0x8e, 0x77, 0xee, 0xa7, 0x98, 0x14, 0xe0, 0x85, 0x2d,
+	0x39, 0x8c, 0x23, 0xa6, 0x67, 0x7e, 0x1c, 0x13, 0x59, 0x8e, 0x25, 0x41, 0xf3, 0x11, 0x6c, 0xec,
+	0x3a, 0x94, 0x84, 0xee, 0x64, 0x3f, 0x89, 0x8e, 0x48, 0x59, 0xac, 0x33, 0x4d, 0x83, 0xf9, 0xfd,
+	0x0e, 0x18, 0x75, 0x6d, 0x85, 0x74, 0x5f, 0xcf, 0xf4, 0x57, 0x1d, 0xae, 0x76, 0xbd, 0xdf, 0x8b,
+	0xe3, 0x2a, 0x51, 0x58, 0x8f, 0x23, 0x36, 0x69, 0x29, 0x1b, 0xdf, 0xad, 0x64, 0xe3, 0x79, 0xc9,
+	0xa2, 0xf0, 0x92, 0x52, 0x66, 0x6a, 0xba, 0x96, 0x8a, 0xc2, 0x6b, 0xf8, 0xdb, 0x71, 0xca, 0xb6,
+	0xb1, 0x6b, 0xe1, 0x4f, 0xfd, 0x1d, 0xe8, 0xc6, 0x81, 0xe3, 0x87, 0x6c, 0xff, 0x14, 0x53, 0x2d,
+	0x36, 0x40, 0x28, 0x1b, 0xe7, 0xc1, 0xb2, 0x42, 0x46, 0xe6, 0xd5, 0x10, 0x8d, 0xdc, 0x82, 0x09,
+	0xcd, 0x77, 0xfc, 0xf0, 0xbe, 0x1d, 0x9d, 0x93, 0xe4, 0x94, 0x38, 0x9e, 0x3d, 0x4e, 0x99, 0x05,
+	0xd2, 0xac, 0x41, 0xfc, 0xf0, 0xfe, 0x9e, 0xc0, 0x3e, 0x4b, 0x19, 0xdf, 0xa3, 0x87, 0x25, 0xbe,
+	0x05, 0xc1, 0xf7, 0xe8, 0x61, 0x95, 0xef, 0x51, 0x89, 0x6f, 0x51, 0xf2, 0x3d, 0x52, 0xf8, 0x3e,
+	0x80, 0x11, 0x3d, 0x4d, 0xa2, 0xec, 0xe4, 0x34, 0xce, 0xb0, 0x46, 0x2e, 0xa0, 0x8e, 0x1d, 0x93,
+	0xc4, 0x45, 0x89, 0x0c, 0x58, 0x83, 0xf5, 0x82, 0xfe, 0x04, 0xc9, 0xfb, 0x9c, 0x5a, 0x1c, 0x9a,
+	0x25, 0xf5, 0xd0, 0xfc, 0xbd, 0x06, 0x83, 0xd2, 0x0a, 0xf5, 0x35, 0x98, 0xc3, 0x95, 0x8d, 0x79,
+	0x7d, 0xa5, 0x66, 0x75, 0xe3, 0x87, 0xf7, 0x9f, 0xa5, 0x0c, 0xfd, 0xe8, 0x21, 0xa2, 0x5b, 0x02,
+	0xfd, 0xe8, 0xa1, 0x44, 0x3f, 0x42, 0x74, 0x5b, 0xa2, 0x1f, 0x71, 0xb4, 0x73, 0x7e, 0x82, 0xe8,
+	0x0e, 0x47, 0x3b, 0xe7, 0x27, 0xcf, 0x72, 0x19, 0x75, 0x19, 0x0e, 0x7f, 0x72, 0x6b, 0xc6, 0x34,
+	0x97, 0x0b, 0xb5, 0x6d, 0xe5, 0x30, 0x33, 0x89, 0x38, 0x49, 0x2e, 0xd4, 0xb6, 0x25, 0x20, 0xf3,
+	0x6b, 0xb0, 0xf1, 0x31, 0xa1, 0xaa, 0x03, 0x85, 0x92, 0x11, 0xfa, 0x5f, 0x55, 0x42, 0x6d, 0x66,
+	0x3d, 0x64, 0xab, 0x5c, 0x79, 0xfa, 0x93, 0x36, 0x18, 0x75, 0x5d, 0x8b, 0xe3, 0x71, 0x89, 0xbe,
+	0xaf, 0xc2, 0x7c, 0x14, 0xdb, 0x4a, 0x92, 0xb7, 0xd6, 0x35, 0x6e, 0xcf, 0x72, 0x8d, 0x2b, 0xaf,
+	0x12, 0xb3, 0x3d, 0x5f, 0xac, 0x06, 0x62, 0xcf, 0xe8, 0x79, 0x35, 0x10, 0x83, 0x98, 0xf5, 0xa0,
+	0x0e, 0x86, 0xf6, 0xb2, 0xe6, 0x53, 0x80, 0xac, 0x60, 0xcb, 0x0f, 0x7d, 0xa6, 0xea, 0xdc, 0xb0,
+	0xe4, 0x70, 0xe9, 0x04, 0xf6, 0x2b, 0x27, 0xf0, 0xba, 0x1a, 0x60, 0x02, 0xbf, 0xbe, 0x72, 0x84,
+	0x22, 0xab, 0x05, 0x7e, 0xf3, 0x70, 0xa8, 0x94, 0xf0, 0x5d, 0xe4, 0xde, 0xa0, 0x84, 0x0b, 0x8d,
+	0x1c, 0x54, 0xea, 0x22, 0x79, 0xfd, 0xa6, 0x67, 0x1f, 0x27, 0xd1, 0x58, 0xa8, 0xeb, 0x82, 0xc0,
+	0x3d, 0x4d, 0xa2, 0x31, 0xde, 0xd0, 0x32, 0x6c, 0xf3, 0x22, 0x37, 0x43, 0xe3, 0x93, 0x8e, 0x96,
+	0x59, 0xef, 0x43, 0x41, 0x78, 0x22, 0xf1, 0xe6, 0xff, 0x68, 0xa0, 0xff, 0x66, 0x46, 0x92, 0x49,
+	0xb9, 0xd0, 0xec, 0xf3, 0xbc, 0x74, 0x57, 0x8b, 0xd2, 0xda, 0x97, 0x29, 0x4a, 0x9b, 0x5d, 0xbe,
+	0x52, 0x55, 0xa5, 0xee, 0x05, 0x39, 0x82, 0xb9, 0x99, 0x9e, 0xf4, 0x7c, 0xd5, 0x93, 0xfe, 0x1d,
+	0x0d, 0xae, 0x94, 0x16, 0x2d, 0x34, 0xf8, 0x1d, 0x98, 0x63, 0xe5, 0x6c, 0xd2, 0x7f, 0xbe, 0xa2,
+	0x56, 0x3c, 0x11, 0x8f, 0x71, 0x5b, 0x82, 0xa5, 0xce, 0x45, 0x6d, 0xd5, 0x15, 0x3b, 0xd6, 0xbf,
+	0xf2, 0xfd, 0x73, 0x0b, 0x16, 0x94, 0x5e, 0xf3, 0xfa, 0x34, 0x4d, 0xa9, 0x4f, 0x2b, 0x97, 0xe0,
+	0xb5, 0x2e, 0x51, 0x82, 0xa7, 0xd6, 0xca, 0xb5, 0x2f, 0xac, 0x95, 0x53, 0x0a, 0xf6, 0x3a, 0x8d,
+	0x05, 0x7b, 0xdd, 0xd9, 0x05, 0x7b, 0x35, 0x69, 0x83, 0x92, 0x68, 0xe7, 0x6b, 0x5c, 0x08, 0x91,
+	0x6a, 0xee, 0x95, 0x0a, 0xf4, 0xd4, 0x62, 0xbc, 0x7e, 0x73, 0x31, 0x1e, 0x94, 0x8b, 0xf1, 0x7e,
+	0x09, 0xae, 0xe1, 0xc3, 0xde, 0xa6, 0x4b, 0xfd, 0x73, 0x32, 0x5d, 0xe7, 0x3b, 0xfb, 0xba, 0x1f,
+	0xc3, 0xf5, 0xfa, 0xc6, 0x79, 0xd2, 0x48, 0x4d, 0x0e, 0x6a, 0xe5, 0x7a, 0x88, 0x4a, 0xab, 0x52,
+	0x66, 0xb0, 0xfe, 0xc5, 0xf3, 0x6f, 0x5b, 0xb0, 0x5c, 0x69, 0xf5, 0x5a, 0x36, 0x53, 0x31, 0xd4,
+	0xed, 0x72, 0x58, 0x3f, 0xfb, 0x70, 0xcd, 0x78, 0xa2, 0x2f, 0x5b, 0xd3, 0xb9, 0x8a, 0x35, 0x5d,
+	0x85, 0x6e, 0x7c, 0xea, 0xa4, 0x52, 0xa8, 0x1c, 0x50, 0x6d, 0x69, 0xaf, 0x6c, 0x4b, 0x6f, 0xc1,
+	0x42, 0x92, 0x85, 0x68, 0xcd, 0xec, 0xe3, 0x28, 0x11, 0x26, 0x13, 0x04, 0xea, 0x69, 0x94, 0xb0,
+	0x9a, 0x3d, 0x2f, 0x20, 0x8c, 0x2a, 0x04, 0x8b, 0xf0, 0xd3, 0x28, 0x31, 0x37, 0xe0, 0xea, 0x7e,
+	0x42, 0xce, 0x7d, 0xf2, 0xf2, 0x90, 0x04, 0x64, 0x4c, 0x68, 0x9e, 0x5e, 0x34, 0xff, 0x51, 0x83,
+	0xd1, 0x34, 0x4d, 0xc8, 0x0c, 0x55, 0x25, 0xe4, 0xb9, 0x79, 0x8d, 0x07, 0x36, 0x02, 0xc4, 0x65,
+	0x93, 0xd0, 0x8b, 0x23, 0x3f, 0xf7, 0xce, 0x72, 0x98, 0xbf, 0x03, 0x51, 0x92, 0x9c, 0x3b, 0xf2,
+	0xed, 0x3f, 0x87, 0x71, 0x15, 0xfc, 0x1d, 0x95, 0x39, 0x83, 0x32, 0xf7, 0x82, 0x28, 0xee, 0x1e,
+	0xf2, 0x52, 0x08, 0x46, 0xeb, 0xca, 0x52, 0x08, 0x86, 0xcf, 0xb5, 0x60, 0x4e, 0xd5, 0x02, 0x1f,
+	0xd6, 0x76, 0x30, 0xec, 0x78, 0x26, 0x92, 0x17, 0xb9, 0xae, 0x2a, 0x79, 0x6e, 0xad, 0x9c, 0xe7,
+	0xbe, 0xc8, 0xb3, 0x2c, 0xe2, 0x9a, 0x76, 0x29, 0xae, 0xf9, 0xae, 0x06, 0x03, 0x36, 0x96, 0xac,
+	0x9d, 0xd4, 0x97, 0xa0, 0x15, 0xa5, 0xa2, 0xfb, 0x56, 0x94, 0xea, 0x26, 0x2c, 0x3a, 0x89, 0x7b,
+	0xea, 0x53, 0xe2, 0x52, 0x74, 0xde, 0x79, 0xdf, 0x25, 0x1c, 0x9b, 0x97, 0x93, 0xf8, 0x4e, 0x28,
+	0x9f, 0x06, 0x25, 0x88, 0xe3, 0x7a, 0xfe, 0x09, 0x49, 0xf3, 0x1a, 0x2a, 0x0e, 0xa1, 0x2d, 0x63,
+	0x56, 0xb9, 0xcb, 0xfc, 0x12, 0xf6, 0xdb, 0xfc, 0x6b, 0x39, 0x17, 0xb9, 0x6e, 0xdc, 0x1e, 0x36,
+	0x4f, 0x79, 0xc5, 0x30, 0x40, 0xe9, 0xb3, 0x55, 0xea, 0xf3, 0x06, 0xc0, 0x98, 0x78, 0xbe, 0xc3,
+	0x6d, 0xa1, 0xf0, 0x10, 0x18, 0x86, 0x19, 0xbe, 0xf7, 0xa0, 0x5f, 0x54, 0x8d, 0x76, 0xca, 0xb9,
+	0x87, 0xd2, 0x16, 0x58, 0x05, 0x5f, 0x43, 0xa0, 0xf4, 0x57, 0x1a, 0xac, 0x57, 0x25, 0x54, 0x28,
+	0x57, 0x83, 0x88, 0xde, 0x2d, 0x85, 0x96, 0xd5, 0xc1, 0x65, 0x4f, 0x79, 0x74, 0xff, 0xb3, 0x30,
+	0x74, 0xa3, 0xf1, 0x38, 0x0a, 0x95, 0x5a, 0x57, 0x2e, 0xbb, 0x65, 0x8e, 0xdf, 0x9f, 0x9e, 0x64,
+	0x29, 0x47, 0xf5, 0x27, 0x2d, 0x18, 0x1c, 0x26, 0x0e, 0xba, 0x25, 0xbc, 0x5a, 0x0d, 0x45, 0x9b,
+	0xdb, 0x8f, 0x96, 0xef, 0x5d, 0xa8, 0x34, 0x9f, 0x3f, 0x9f, 0x2c, 0x13, 0x27, 0x5d, 0x25, 0x71,
+	0xa2, 0x66, 0xe5, 0xe6, 0x2a, 0x59, 0x39, 0x76, 0x89, 0x04, 0x44, 0x71, 0xb2, 0x04, 0x88, 0x14,
+	0x51, 0x05, 0x23, 0x4d, 0x86, 0x00, 0x51, 0xcc, 0x82, 0xc9, 0x3e, 0x9a, 0x08, 0x8b, 0x21, 0x2c,
+	0x92, 0xf7, 0x78, 0xba, 0x94, 0x1d, 0xa6, 0x4b, 0xd9, 0x7f, 0xa8, 0x81, 0x81, 0x56, 0x5d, 0xdd,
+	0x1d, 0x25, 0x7d, 0xf7, 0x7a, 0xd5, 0x84, 0xcd, 0x26, 0xb7, 0xe4, 0x8e, 0x74, 0x66, 0xba, 0x23,
+	0xdd, 0xaa, 0x3b, 0xf2, 0x7d, 0x0d, 0xae, 0xd5, 0x4e, 0x59, 0xa8, 0xdd, 0xcf, 0x2b, 0x65, 0x7c,
+	0x5a, 0x59, 0xbd, 0x4a, 0x3a, 0xa0, 0x54, 0xf1, 0xbd, 0x9e, 0x73, 0xf2, 0x0d, 0x58, 0xb5, 0x48,
+	0x4a, 0xa3, 0x84, 0x88, 0x8e, 0xc5, 0xe6, 0x55, 0x75, 0xac, 0x31, 0x54, 0x98, 0x95, 0xcc, 0x36,
+	0xbf, 0x01, 0x6b, 0x95, 0xde, 0x8b, 0x6f, 0xd7, 0x44, 0xa5, 0x5f, 0xe5, 0xdb, 0xb5, 0xf2, 0x2a,
+	0x95, 0x02, 0xc0, 0x9a, 0x5b, 0x76, 0x1f, 0x1f, 0x8c, 0xd9, 0x57, 0x21, 0x9f, 0xab, 0xfa, 0x76,
+	0x46, 0xd8, 0x13, 0x83, 0x2e, 0x7a, 0xdc, 0x0e, 0xa9, 0x4f, 0x03, 0x5e, 0x02, 0xdb, 0x50, 0xbe,
+	0x93, 0xa1, 0x93, 0xc2, 0x4b, 0x0a, 0xd9, 0x6f, 0x9c, 0x65, 0xe0, 0x8f, 0x7d, 0x2a, 0xeb, 0x40,
+	0x19, 0x80, 0x6a, 0x56, 0x7c, 0xf9, 0xc1, 0x0b, 0x18, 0x0b, 0x84, 0xf9, 0xbd, 0x16, 0xac, 0x89,
+	0x21, 0x2b, 0x55, 0xc0, 0x23, 0x98, 0x97, 0x89, 0x47, 0x61, 0x81, 0x04, 0xc8, 0x1d, 0xc8, 0x7c,
+	0xf2, 0xec, 0xb7, 0x7a, 0xce, 0xda, 0xe5, 0x73, 0x86, 0xf7, 0xbc, 0x33, 0x49, 0xed, 0x80, 0x1c,
+	0x53, 0xa9, 0xae, 0x88, 0xd8, 0x25, 0xc7, 0x54, 0xff, 0x15, 0x58, 0x24, 0xc5, 0x4a, 0xa7, 0x9e,
+	0x45, 0xa7, 0x37, 0xc3, 0x2a, 0xf1, 0x97, 0x3e, 0x7a, 0x99, 0xab, 0x7c, 0xf4, 0xa2, 0x46, 0x3c,
+	0xf3, 0xe5, 0x12, 0x97, 0xfa, 0x5a, 0xd2, 0xbb, 0xbf, 0x0d, 0x50, 0x7c, 0x7b, 0xa3, 0x2f, 0xc0,
+	0xfc, 0xce, 0xf3, 0x83, 0xc3, 0xcd, 0xdd, 0xdd, 0xe1, 0x1b, 0xfa, 0x3a, 0xe8, 0x07, 0x9b, 0xcf,
+	0xf6, 0x77, 0xb7, 0xed, 0xcd, 0xfd, 0xfd, 0xdd, 0x9d, 0xad, 0xcd, 0xc3, 0x9d, 0xbd, 0xe7, 0x43,
+	0x4d, 0x1f, 0x40, 0x7f, 0x6b, 0xef, 0xf9, 0xd3, 0x9d, 0x8f, 0x5f, 0x58, 0xdb, 0xc3, 0x96, 0xbe,
+	0x08, 0xbd, 0x4f, 0x36, 0x77, 0x77, 0x9e, 0x6c, 0x1e, 0x6e, 0x0f, 0xdb, 0x3a, 0xc0, 0xdc, 0xd6,
+	0x8b, 0x83, 0xc3, 0xbd, 0x67, 0xc3, 0xce, 0xdd, 0xbb, 0xd0, 0xcf, 0xfd, 0x68, 0xbd, 0x07, 0x9d,
+	0x9d, 0xe7, 0x4f, 0xf7, 0x86, 0x6f, 0xe0, 0xaf, 0x4f, 0x37, 0x2d, 0xec, 0xa9, 0x0f, 0xdd, 0x6d,
+	0xcb, 0xda, 0xb3, 0x86, 0xad, 0xbb, 0xdf, 0xc5, 0xe2, 0xad, 0xc2, 0x75, 0x5e, 0x3d, 0xd8, 0xfe,
+	0x64, 0xdb, 0xda, 0x39, 0xfc, 0x2d, 0xfb, 0xc5, 0xf3, 0x83, 0xfd, 0xed, 0xad, 0x9d, 0xa7, 0x3b,
+	0xdb, 0x4f, 0x86, 0x6f, 0xe8, 0x3a, 0x2c, 0xe5, 0x94, 0x27, 0xdb, 0x8f, 0x5f, 0x7c, 0x3c, 0xd4,
+	0xf4, 0x15, 0x18, 0xe4, 0x38, 0x36, 0x44, 0xab, 0x84, 0x62, 0x63, 0xb5, 0x4b, 0x2d, 0xf9, 0xa0,
+	0x1d, 0x7d, 0x0d, 0x56, 0x72, 0xdc, 0x96, 0xb5, 0x73, 0xb8, 0xb3, 0xb5, 0xb9, 0x3b, 0xec, 0x3e,
+	0xf8, 0xcb, 0x55, 0x58, 0xc0, 0x8f, 0x34, 0x45, 0x72, 0x55, 0xff, 0x3a, 0xe8, 0xd3, 0xdf, 0x84,
+	0xea, 0x6f, 0xe6, 0x2f, 0xb8, 0x4d, 0x5f, 0xc2, 0x1a, 0xe6, 0x2c, 0x16, 0xa1, 0x73, 0x1f, 0x41,
+	0x4f, 0x7e, 0x10, 0xaa, 0xe7, 0xee, 0x6f, 0xe5, 0xab, 0x51, 0x63, 0x34, 0x4d, 0x10, 0xcd, 0xb7,
+	0x61, 0x89, 0x95, 0xa9, 0x15, 0x4e, 0x6f, 0x63, 0xf9, 0x9a, 0xb1, 0x51, 0x43, 0x11, 0xdd, 0x7c,
+	0x13, 0xae, 0xd4, 0x7c, 0xbe, 0xa6, 0x9b, 0xcd, 0x8f, 0xf5, 0xf2, 0xe8, 0x1b, 0x77, 0x66, 0xf2,
+	0x88, 0xfe, 0x7f, 0x15, 0x3f, 0x12, 0x49, 0x88, 0x33, 0xe6, 0x31, 0xa1, 0xbe, 0x56, 0x0a, 0xb4,
+	0xf2, 0xbe, 0xd6, 0xab, 0x68, 0xde, 0xfc, 0xbe, 0x86, 0x13, 0xac, 0xf9, 0xf0, 0xa7, 0x98, 0x60,
+	0xf3, 0x47, 0x43, 0xc6, 0x9d, 0x99, 0x3c, 0x62, 0x82, 0xbb, 0x30, 0x28, 0x7d, 0xac, 0xa1, 0xe7,
+	0xc5, 0xfd, 0x75, 0xdf, 0x9e, 0x18, 0x37, 0x1a, 0xa8, 0xa2, 0xb7, 0xaf, 0xc1, 0xca, 0xd4, 0xb7,
+	0x06, 0xfa, 0xed, 0x7c, 0x71, 0x0d, 0xdf, 0x30, 0x18, 0x6f, 0xce, 0xe0, 0x10, 0x3d, 0xbf, 0x80,
+	0x61, 0xb5, 0x80, 0x5e, 0xbf, 0x95, 0x4f, 0xa6, 0xbe, 0xc8, 0xdf, 0xb8, 0xdd, 0xcc, 0x50, 0x74,
+	0x5b, 0x2d, 0x87, 0x2e, 0xba, 0x6d, 0x28, 0xd9, 0x36, 0x6e, 0x37, 0x33, 0x88, 0x6e, 0x7f, 0x0d,
+	0xfa, 0x79, 0x4d, 0x72, 0xa1, 0x98, 0xd5, 0x2a, 0x6a, 0x63, 0xa3, 0x86, 0x52, 0x4c, 0xac, 0x5a,
+	0x20, 0x5c, 0x4c, 0xac, 0xa1, 0x46, 0xd9, 0xb8, 0xdd, 0xcc, 0x50, 0x08, 0x68, 0xaa, 0xda, 0xb6,
+	0x10, 0x50, 0x53, 0x81, 0xb0, 0xf1, 0xe6, 0x0c, 0x8e, 0x42, 0x91, 0x4a, 0xc5, 0xb0, 0x85, 0x22,
+	0xd5, 0x15, 0xe4, 0x1a, 0x37, 0x1a, 0xa8, 0xa2, 0xb7, 0x3d, 0x58, 0x2a, 0x17, 0x67, 0xea, 0x79,
+	0x83, 0xda, 0xea, 0x4f, 0xe3, 0x66, 0x13, 0x59, 0xd1, 0xcc, 0x6a, 0x1d, 0x9d, 0xa2, 0x99, 0x0d,
+	0x25, 0x90, 0xc6, 0x9b, 0x33, 0x38, 0xd4, 0x85, 0x2b, 0x85, 0x57, 0xea, 0xc2, 0xa7, 0x4b, 0xcc,
+	0x8c, 0x1b, 0x0d, 0xd4, 0xc2, 0x20, 0xd5, 0x94, 0x32, 0x15, 0xe7, 0xbd, 0xb9, 0x0c, 0xca, 0xb8,
+	0x33, 0x93, 0xa7, 0xd0, 0xcc, 0xbc, 0x74, 0xa4, 0xd0, 0xcc, 0x6a, 0xb1, 0x8d, 0x51, 0x5b, 0xc6,
+	0xc2, 0x7b, 0xb0, 0x60, 0xb9, 0xf2, 0x9a, 0xae, 0xdf, 0x9c, 0xfd, 0xb8, 0x6f, 0xdc, 0x6a, 0xa4,
+	0x8b, 0x3e, 0xbf, 0x0e, 0xfa, 0xf4, 0x1b, 0x74, 0x71, 0xd3, 0x34, 0xbe, 0x9b, 0x1b, 0xe6, 0x2c,
+	0x96, 0x62, 0xc9, 0xf9, 0x9b, 0x5a, 0xb1, 0xe4, 0xea, 0xdb, 0x9c, 0xb1, 0x51, 0x43, 0x29, 0xa6,
+	0x37, 0xfd, 0x80, 0x53, 0x4c, 0xaf, 0xf1, 0x61, 0xc8, 0x30, 0x67, 0xb1, 0x14, 0x9d, 0x4f, 0xa7,
+	0xbf, 0x8b, 0xce, 0x1b, 0xb3, 0xee, 0x86, 0x39, 0x8b, 0x45, 0x74, 0xfe, 0x14, 0x16, 0x94, 0x94,
+	0xa4, 0x9e, 0x7b, 0x5b, 0xd3, 0xc9, 0x59, 0xe3, 0x5a, 0x2d, 0x4d, 0xf4, 0xe3, 0xf0, 0xba, 0xfa,
+	0x6a, 0x52, 0x4b, 0xbf, 0xa3, 0x1e, 0xe3, 0x86, 0x7c, 0x99, 0xf1, 0x33, 0xb3, 0x99, 0x14, 0x0b,
+	0x5f, 0xc9, 0xbf, 0x28, 0x16, 0xbe, 0x3e, 0x6b, 0x63, 0xdc, 0x6e, 0x66, 0x28, 0x2c, 0x49, 0x39,
+	0xee, 0x2e, 0x2c, 0x49, 0x6d, 0xc6, 0xc4, 0xb8, 0xd9, 0x44, 0x2e, 0x4e, 0x68, 0x4d, 0x58, 0x55,
+	0x9c, 0xd0, 0xe6, 0x30, 0xd1, 0xb8, 0x33, 0x93, 0xa7, 0xb0, 0x27, 0xa5, 0x40, 0xa6, 0xb0, 0x27,
+	0x75, 0xd1, 0x93, 0x71, 0xa3, 0x81, 0xaa, 0x5a, 0x27, 0xc5, 0xe7, 0x57, 0xad, 0xd3, 0x74, 0x3c,
+	0x63, 0xdc, 0x68, 0xa0, 0xf2, 0xde, 0x1e, 0x77, 0xfe, 0xf4, 0xa7, 0x37, 0xdf, 0x38, 0x9a, 0x63,
+	0xff, 0x8a, 0xf2, 0xde, 0xff, 0x0e, 0x00, 0x9c, 0x09, 0x0a, 0xc4, 0x26, 0x45, 0x00, 0x00,
 }
@@ -0,0 +1,203 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// an unreachable API server should fail the creation of the instance quickly, not after the TCP timeout
+const accessProbeTimeout = 10 * time.Second
+
+// adapterRules are the permissions the operations rely on, a kubeconfig lacking one of them still works
+// but the operations needing it fail
+var adapterRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{""},
+		Resources: []string{"namespaces"},
+		Verbs:     []string{"get", "list", "create", "patch", "delete"},
+	},
+	{
+		APIGroups: []string{""},
+		Resources: []string{"pods", "services", "serviceaccounts", "configmaps", "secrets"},
+		Verbs:     []string{"get", "list", "create", "update", "delete"},
+	},
+	{
+		APIGroups: []string{""},
+		Resources: []string{"pods/log"},
+		Verbs:     []string{"get"},
+	},
+	{
+		APIGroups: []string{"apps"},
+		Resources: []string{"deployments", "daemonsets", "statefulsets"},
+		Verbs:     []string{"get", "list", "create", "update", "patch", "delete"},
+	},
+	{
+		APIGroups: []string{"rbac.authorization.k8s.io"},
+		Resources: []string{"clusterroles", "clusterrolebindings", "roles", "rolebindings"},
+		Verbs:     []string{"get", "create", "update", "delete"},
+	},
+	{
+		APIGroups: []string{"admissionregistration.k8s.io"},
+		Resources: []string{"mutatingwebhookconfigurations", "validatingwebhookconfigurations"},
+		Verbs:     []string{"get", "list", "create", "update", "delete"},
+	},
+}
+
+// probeClusterAccess checks that the API server answers with the credentials of a kubeconfig and reviews
+// what they are allowed to do in the dataplane namespace, only an API server which can't be used fails it
+func probeClusterAccess(config *rest.Config) (*meshes.ClusterAccess, error) {
+	cfg := rest.CopyConfig(config)
+	cfg.Timeout = accessProbeTimeout
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to create the probe client")
+	}
+	version, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		switch {
+		case apierrors.IsUnauthorized(err):
+			return nil, errors.Wrapf(err, "the API server at %s rejected the credentials of the kubeconfig", cfg.Host)
+		case apierrors.IsForbidden(err):
+			return nil, errors.Wrapf(err, "the credentials of the kubeconfig may not even read the version of the API server at %s", cfg.Host)
+		}
+		return nil, errors.Wrapf(err, "unable to reach the API server at %s", cfg.Host)
+	}
+	access := &meshes.ClusterAccess{
+		ServerVersion: version.GitVersion,
+		Namespace:     dataplaneNamespace(),
+	}
+
+	_, err = clientset.CoreV1().Pods(metav1.NamespaceSystem).List(metav1.ListOptions{Limit: 1})
+	switch {
+	case err == nil:
+		access.KubeSystemReadable = true
+	case apierrors.IsForbidden(err):
+		access.Warnings = append(access.Warnings, "pods in kube-system may not be listed, cluster capabilities and diagnostics will be incomplete")
+	default:
+		access.Warnings = append(access.Warnings, fmt.Sprintf("unable to list the pods in kube-system: %v", err))
+	}
+
+	review, err := clientset.AuthorizationV1().SelfSubjectRulesReviews().Create(&authorizationv1.SelfSubjectRulesReview{
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: access.Namespace},
+	})
+	if err != nil {
+		access.Warnings = append(access.Warnings, fmt.Sprintf("unable to review the permissions of the credentials: %v", err))
+		return access, nil
+	}
+	for _, rule := range review.Status.ResourceRules {
+		access.Rules = append(access.Rules, describeResourceRule(rule))
+	}
+	access.Missing = missingPermissions(review.Status.ResourceRules, adapterRules)
+	if review.Status.Incomplete {
+		// webhook authorizers, among others, can't enumerate rules, so what looks missing may well be granted
+		access.Warnings = append(access.Warnings, fmt.Sprintf("the authorizer could not list every rule, the missing permissions may be granted: %s",
+			review.Status.EvaluationError))
+	}
+	return access, nil
+}
+
+func describeResourceRule(rule authorizationv1.ResourceRule) string {
+	resources := make([]string, 0, len(rule.Resources))
+	for _, group := range rule.APIGroups {
+		for _, res := range rule.Resources {
+			if group != "" {
+				res += "." + group
+			}
+			resources = append(resources, res)
+		}
+	}
+	desc := strings.Join(rule.Verbs, ",") + " " + strings.Join(resources, ",")
+	if len(rule.ResourceNames) > 0 {
+		desc += " named " + strings.Join(rule.ResourceNames, ",")
+	}
+	return desc
+}
+
+func ruleMatches(values []string, value string) bool {
+	for _, v := range values {
+		if v == value || v == rbacv1.VerbAll {
+			return true
+		}
+	}
+	return false
+}
+
+// missingPermissions lists the verbs of the required rules no granted rule covers, rules limited to
+// some resource names don't count since the adapter names its own resources
+func missingPermissions(granted []authorizationv1.ResourceRule, required []rbacv1.PolicyRule) []string {
+	missing := []string{}
+	for _, req := range required {
+		for _, group := range req.APIGroups {
+			for _, res := range req.Resources {
+				for _, verb := range req.Verbs {
+					allowed := false
+					for _, rule := range granted {
+						if len(rule.ResourceNames) == 0 && ruleMatches(rule.APIGroups, group) &&
+							ruleMatches(rule.Resources, res) && ruleMatches(rule.Verbs, verb) {
+							allowed = true
+							break
+						}
+					}
+					if !allowed {
+						name := res
+						if group != "" {
+							name += "." + group
+						}
+						missing = append(missing, verb+" "+name)
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// accessEvent summarizes the access of the credentials for the events stream
+func accessEvent(access *meshes.ClusterAccess) *meshes.EventsResponse {
+	lines := []string{}
+	if !access.KubeSystemReadable {
+		lines = append(lines, "kube-system is not readable")
+	}
+	if len(access.Missing) > 0 {
+		lines = append(lines, fmt.Sprintf("missing in namespace %s: %s", access.Namespace, strings.Join(access.Missing, ", ")))
+	}
+	lines = append(lines, access.Warnings...)
+	event := &meshes.EventsResponse{
+		EventType: meshes.EventType_INFO,
+		Summary:   fmt.Sprintf("Connected to Kubernetes %s, the credentials allow every operation", access.ServerVersion),
+		Details:   strings.Join(lines, "\n"),
+	}
+	if len(lines) > 0 {
+		event.EventType = meshes.EventType_WARN
+		event.Summary = fmt.Sprintf("Connected to Kubernetes %s, some operations will fail with these credentials", access.ServerVersion)
+	}
+	logrus.Infof("%s: %s", event.Summary, strings.Join(lines, "; "))
+	return event
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+)
+
+// phase tells what a running operation is doing, from what its watchdog was last told it works on, and how
+// long ago it last made progress. The caller holds the lock of the tracker.
+func (r *operationResult) phase(now time.Time) (string, time.Duration) {
+	wd := r.watchdog
+	if wd == nil {
+		// the operation is still handling its request
+		return "starting", now.Sub(r.Started)
+	}
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	idle := now.Sub(wd.lastProgress)
+	phase := wd.current
+	if phase == "" {
+		phase = "running"
+	}
+	switch {
+	case wd.err != nil:
+		phase = "cancelled after stalling on " + phase
+	case idle >= wd.warnAt:
+		phase = fmt.Sprintf("stalled for %s on %s", idle.Round(time.Second), phase)
+	}
+	return phase, idle
+}
+
+// active lists the running operations of every cluster, oldest first
+func (t *resultTracker) active(namespace string) []*meshes.ActiveOperation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	running := make([]*operationResult, 0, len(t.running))
+	for _, r := range t.running {
+		// finished operations stay in running until their last events went through the broker
+		if r.Finished.IsZero() && (namespace == "" || r.Namespace == namespace) {
+			running = append(running, r)
+		}
+	}
+	sort.Slice(running, func(i, j int) bool {
+		if !running[i].Started.Equal(running[j].Started) {
+			return running[i].Started.Before(running[j].Started)
+		}
+		return running[i].OperationID < running[j].OperationID
+	})
+	ops := make([]*meshes.ActiveOperation, 0, len(running))
+	for _, r := range running {
+		phase, idle := r.phase(now)
+		ops = append(ops, &meshes.ActiveOperation{
+			OperationId: r.OperationID,
+			OpName:      r.Operation,
+			Cluster:     r.client.cluster,
+			Namespace:   r.Namespace,
+			Username:    r.Username,
+			DeleteOp:    r.DeleteOp,
+			Phase:       phase,
+			Started:     r.Started.Format(time.RFC3339),
+			RunningFor:  now.Sub(r.Started).Round(time.Second).String(),
+			IdleFor:     idle.Round(time.Second).String(),
+		})
+	}
+	return ops
+}
+
+// ListActiveOperations lists the operations running in every cluster and what each of them is doing, to see
+// what the adapter is busy with and what a new operation waits for
+func (oClient *Client) ListActiveOperations(_ context.Context, req *meshes.ListActiveOperationsRequest) (*meshes.ListActiveOperationsResponse, error) {
+	broker := oClient.startEvents()
+	return &meshes.ListActiveOperationsResponse{Operations: broker.results.active(req.GetNamespace())}, nil
+}
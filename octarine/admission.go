@@ -0,0 +1,246 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const admissionTestPrefix = "octarine-admission-test-"
+
+// admissionTestWorkloads are submitted when the operation doesn't bring its own, each one
+// exercises a guardrail commonly found in admission policies
+const admissionTestWorkloads = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: compliant
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: compliant
+  template:
+    metadata:
+      labels:
+        app: compliant
+    spec:
+      containers:
+      - name: app
+        image: nginx:1.17
+        resources:
+          limits:
+            cpu: 100m
+            memory: 64Mi
+        securityContext:
+          runAsNonRoot: true
+          allowPrivilegeEscalation: false
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: plain
+spec:
+  containers:
+  - name: app
+    image: nginx:1.17
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: privileged
+spec:
+  containers:
+  - name: app
+    image: nginx:1.17
+    securityContext:
+      privileged: true
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: host-access
+spec:
+  hostNetwork: true
+  hostPID: true
+  containers:
+  - name: app
+    image: nginx:1.17
+    volumeMounts:
+    - name: host
+      mountPath: /host
+  volumes:
+  - name: host
+    hostPath:
+      path: /
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: latest-tag
+spec:
+  containers:
+  - name: app
+    image: nginx:latest
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: no-limits-root
+spec:
+  containers:
+  - name: app
+    image: nginx:1.17
+    securityContext:
+      runAsUser: 0
+`
+
+// admissionResult is the verdict of the admission chain for one test workload
+type admissionResult struct {
+	object  string
+	verdict string
+	reason  string
+}
+
+// admissionChanges describes what the admission chain changed in a workload; the defaults the API server
+// fills in and the service account token it mounts are left out, they aren't the doing of a webhook
+func admissionChanges(submitted, admitted *unstructured.Unstructured) []string {
+	changes := []string{}
+	for _, field := range []string{"labels", "annotations"} {
+		before, _, _ := unstructured.NestedStringMap(submitted.Object, "metadata", field)
+		after, _, _ := unstructured.NestedStringMap(admitted.Object, "metadata", field)
+		for k, v := range after {
+			if old, ok := before[k]; !ok || old != v {
+				changes = append(changes, fmt.Sprintf("set %s %s", strings.TrimSuffix(field, "s"), k))
+			}
+		}
+	}
+	path := podSpecPath(submitted.GetKind())
+	if path == nil {
+		return changes
+	}
+	for _, field := range []string{"initContainers", "containers", "volumes"} {
+		before := namedItems(submitted.Object, append(path, field))
+		for _, name := range sortedKeys(namedItems(admitted.Object, append(path, field))) {
+			if before[name] || field == "volumes" && isServiceAccountVolume(name) {
+				continue
+			}
+			changes = append(changes, fmt.Sprintf("added %s %s", strings.TrimSuffix(field, "s"), name))
+		}
+	}
+	return changes
+}
+
+func namedItems(obj map[string]interface{}, path []string) map[string]bool {
+	names := map[string]bool{}
+	items, _, _ := nestedObjects(obj, path...)
+	for _, item := range items {
+		if name, found, _ := unstructured.NestedString(item, "name"); found {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+func isServiceAccountVolume(name string) bool {
+	return strings.HasPrefix(name, "default-token-") || strings.HasPrefix(name, "kube-api-access-")
+}
+
+// dryRunAdmission submits a workload without persisting it and tells what admission made of it
+func (oClient *Client) dryRunAdmission(ctx context.Context, obj *unstructured.Unstructured, namespace string) admissionResult {
+	obj.SetName(admissionTestPrefix + obj.GetName())
+	obj.SetNamespace(namespace)
+	result := admissionResult{object: fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName())}
+	admitted, err := oClient.dynamicClient(ctx).Resource(resourceFor(obj)).Namespace(namespace).Create(obj,
+		metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if err != nil {
+		result.verdict, result.reason = "denied", err.Error()
+		if denial, ok := asAdmissionDenial(err, obj); ok {
+			result.reason = fmt.Sprintf("%s by %s", denial.describe("; "), denial.webhook)
+		}
+		if strings.Contains(err.Error(), "does not support dry run") {
+			// webhooks with side effects refuse dry-run requests, so their verdict can't be known
+			result.verdict = "untested"
+		}
+		return result
+	}
+	if changes := admissionChanges(obj, admitted); len(changes) > 0 {
+		result.verdict, result.reason = "mutated", strings.Join(changes, ", ")
+		return result
+	}
+	result.verdict = "admitted"
+	return result
+}
+
+// executeAdmissionTest dry-runs the workloads of the custom body, or the built-in ones, in the namespace
+// of the operation and reports the verdicts of the admission webhooks
+func (oClient *Client) executeAdmissionTest(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	if oClient.dynamicClient(ctx) == nil {
+		return fmt.Errorf("error: mesh client has not been created")
+	}
+	namespace := arReq.GetNamespace()
+	if namespace == "" {
+		namespace = "default"
+	}
+	manifest := arReq.GetCustomBody()
+	if strings.TrimSpace(manifest) == "" {
+		manifest = admissionTestWorkloads
+	}
+	manifest, err := normalizeManifest(manifest)
+	if err != nil {
+		return err
+	}
+	objects, err := parseManifestObjects(manifest)
+	if err != nil {
+		return err
+	}
+	if len(objects) == 0 {
+		return fmt.Errorf("error: the custom body holds no workloads to test")
+	}
+	counts := map[string]int{}
+	lines := []string{}
+	for _, obj := range objects {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		workingOn(ctx, "submitting %s %s", obj.GetKind(), obj.GetName())
+		result := oClient.dryRunAdmission(ctx, obj, namespace)
+		progressed(ctx)
+		counts[result.verdict]++
+		line := fmt.Sprintf("%s: %s", result.object, result.verdict)
+		if result.reason != "" {
+			line += " - " + result.reason
+		}
+		lines = append(lines, line)
+	}
+	eventType := meshes.EventType_INFO
+	if counts["untested"] > 0 {
+		eventType = meshes.EventType_WARN
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   eventType,
+		Summary: fmt.Sprintf("Admission test in namespace %s: %d admitted, %d mutated, %d denied, %d untested",
+			namespace, counts["admitted"], counts["mutated"], counts["denied"], counts["untested"]),
+		Details: strings.Join(lines, "\n"),
+	}
+	return nil
+}
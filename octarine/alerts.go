@@ -0,0 +1,105 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// a muted alert nobody remembers is as good as a deleted one
+const maxMuteDuration = 30 * 24 * time.Hour
+
+// alertCommand runs an octactl alert subcommand on the domain of a deployment
+func (oClient *Client) alertCommand(ctx context.Context, d *deployment, args ...string) error {
+	return oClient.asAccount(ctx, d, func() error {
+		cmd := exec.CommandContext(ctx, "octactl", append([]string{"alert"}, args...)...)
+		logrus.Debugf("Running octactl alert %s on domain %s", args[0], d.domain)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			logrus.Errorf("Command finished with error: %v: %s", err, out)
+			return errors.Wrapf(err, "unable to %s alert of domain %s", args[0], d.domain)
+		}
+		return nil
+	})
+}
+
+// AcknowledgeAlert acknowledges an Octarine alert and records who did it and why in the audit log
+func (oClient *Client) AcknowledgeAlert(ctx context.Context, req *meshes.AcknowledgeAlertRequest) (*meshes.AcknowledgeAlertResponse, error) {
+	if oClient.clientset(ctx) == nil {
+		return &meshes.AcknowledgeAlertResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	if req.GetAlertId() == "" || req.GetReason() == "" {
+		return &meshes.AcknowledgeAlertResponse{Error: "error: an alert id and a reason are required"}, nil
+	}
+	d, err := oClient.getDeployment(req.GetDeployment())
+	if err != nil {
+		return &meshes.AcknowledgeAlertResponse{Error: err.Error()}, nil
+	}
+	err = oClient.alertCommand(ctx, d, "ack", d.domain, req.GetAlertId(), "--reason", req.GetReason())
+	recordAudit(auditEntry{
+		User:       req.GetUsername(),
+		Action:     "alert.acknowledge",
+		Deployment: d.name,
+		Target:     req.GetAlertId(),
+		Reason:     req.GetReason(),
+		Details:    fmt.Sprintf("acknowledged alert %s of domain %s", req.GetAlertId(), d.domain),
+	}, err)
+	if err != nil {
+		return &meshes.AcknowledgeAlertResponse{Error: err.Error()}, nil
+	}
+	return &meshes.AcknowledgeAlertResponse{}, nil
+}
+
+// MuteAlert silences an Octarine alert for a while and records who did it and why in the audit log
+func (oClient *Client) MuteAlert(ctx context.Context, req *meshes.MuteAlertRequest) (*meshes.MuteAlertResponse, error) {
+	if oClient.clientset(ctx) == nil {
+		return &meshes.MuteAlertResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	if req.GetAlertId() == "" || req.GetReason() == "" {
+		return &meshes.MuteAlertResponse{Error: "error: an alert id and a reason are required"}, nil
+	}
+	duration, err := time.ParseDuration(req.GetDuration())
+	if err != nil || duration <= 0 {
+		return &meshes.MuteAlertResponse{Error: fmt.Sprintf("error: duration must be a positive duration like 8h, got %q", req.GetDuration())}, nil
+	}
+	if duration > maxMuteDuration {
+		return &meshes.MuteAlertResponse{Error: fmt.Sprintf("error: alerts can be muted for at most %s", maxMuteDuration)}, nil
+	}
+	d, err := oClient.getDeployment(req.GetDeployment())
+	if err != nil {
+		return &meshes.MuteAlertResponse{Error: err.Error()}, nil
+	}
+	until := time.Now().UTC().Add(duration).Truncate(time.Second)
+	err = oClient.alertCommand(ctx, d, "mute", d.domain, req.GetAlertId(),
+		"--until", until.Format(time.RFC3339), "--reason", req.GetReason())
+	recordAudit(auditEntry{
+		User:       req.GetUsername(),
+		Action:     "alert.mute",
+		Deployment: d.name,
+		Target:     req.GetAlertId(),
+		Reason:     req.GetReason(),
+		Details:    fmt.Sprintf("muted alert %s of domain %s until %s", req.GetAlertId(), d.domain, until.Format(time.RFC3339)),
+	}, err)
+	if err != nil {
+		return &meshes.MuteAlertResponse{Error: err.Error()}, nil
+	}
+	return &meshes.MuteAlertResponse{MutedUntil: until.Format(time.RFC3339)}, nil
+}
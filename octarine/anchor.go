@@ -0,0 +1,143 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	anchorName = "octarine-anchor"
+
+	managedByLabel      = "app.kubernetes.io/managed-by"
+	managedByValue      = "meshery-octarine"
+	deploymentNameLabel = "meshery.layer5.io/deployment"
+)
+
+// managedLabels mark every resource the adapter creates for a deployment
+func (d *deployment) managedLabels() map[string]string {
+	return map[string]string{
+		managedByLabel:      managedByValue,
+		deploymentNameLabel: d.name,
+	}
+}
+
+// namespacedKinds asks the cluster which kinds are namespaced, kinds missing from the map are unknown
+func (oClient *Client) namespacedKinds(ctx context.Context) (map[schema.GroupKind]bool, error) {
+	lists, err := oClient.clientset(ctx).Discovery().ServerPreferredResources()
+	if err != nil && len(lists) == 0 {
+		err = errors.Wrapf(err, "unable to discover the resources of the cluster")
+		logrus.Error(err)
+		return nil, err
+	}
+	result := map[schema.GroupKind]bool{}
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			result[schema.GroupKind{Group: gv.Group, Kind: r.Kind}] = r.Namespaced
+		}
+	}
+	return result, nil
+}
+
+// ensureAnchor creates the anchor ConfigMap owning the namespaced resources of the deployment,
+// deleting it cascades to everything the dataplane created in its namespace
+func (oClient *Client) ensureAnchor(ctx context.Context, d *deployment) error {
+	_, err := oClient.clientset(ctx).CoreV1().Namespaces().Create(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: d.namespace, Labels: d.managedLabels()},
+	})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		err = errors.Wrapf(err, "unable to create namespace %s", d.namespace)
+		logrus.Error(err)
+		return err
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resourceName(anchorName),
+			Namespace: d.namespace,
+			Labels:    d.managedLabels(),
+		},
+		Data: map[string]string{
+			"deployment": d.name,
+			"domain":     d.domain,
+			"account":    d.account,
+			"version":    d.version,
+		},
+	}
+	anchor, err := oClient.clientset(ctx).CoreV1().ConfigMaps(d.namespace).Create(cm)
+	if apierrors.IsAlreadyExists(err) {
+		// reinstalling keeps the mesh spec reconciled last
+		if existing, err := oClient.clientset(ctx).CoreV1().ConfigMaps(d.namespace).Get(cm.GetName(), metav1.GetOptions{}); err == nil {
+			if spec, ok := existing.Data[appliedSpecKey]; ok {
+				cm.Data[appliedSpecKey] = spec
+			}
+		}
+		anchor, err = oClient.clientset(ctx).CoreV1().ConfigMaps(d.namespace).Update(cm)
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "unable to create the anchor of deployment %s", d.name)
+		logrus.Error(err)
+		return err
+	}
+
+	namespaced, err := oClient.namespacedKinds(ctx)
+	if err != nil {
+		return err
+	}
+	d.namespacedKinds = namespaced
+	d.anchor = &metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Name:       anchor.GetName(),
+		UID:        anchor.GetUID(),
+	}
+	logrus.Infof("Created anchor %s/%s for deployment %s", d.namespace, anchor.GetName(), d.name)
+	return nil
+}
+
+// anchorOwner is the owner reference to the current anchor of a deployment
+func (oClient *Client) anchorOwner(ctx context.Context, d *deployment) (*metav1.OwnerReference, error) {
+	anchor, err := oClient.clientset(ctx).CoreV1().ConfigMaps(d.namespace).Get(resourceName(anchorName), metav1.GetOptions{})
+	if err != nil {
+		err = errors.Wrapf(err, "unable to get the anchor of deployment %s", d.name)
+		logrus.Error(err)
+		return nil, err
+	}
+	return &metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: anchor.GetName(), UID: anchor.GetUID()}, nil
+}
+
+// deleteAnchor removes the anchor, the garbage collector deletes the resources it owns
+func (oClient *Client) deleteAnchor(ctx context.Context, d *deployment) error {
+	policy := metav1.DeletePropagationBackground
+	err := oClient.clientset(ctx).CoreV1().ConfigMaps(d.namespace).Delete(resourceName(anchorName), &metav1.DeleteOptions{
+		PropagationPolicy: &policy,
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		err = errors.Wrapf(err, "unable to delete the anchor of deployment %s", d.name)
+		logrus.Error(err)
+		return err
+	}
+	return nil
+}
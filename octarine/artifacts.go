@@ -0,0 +1,58 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/storage"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// artifactStore opens the object storage OCTARINE_STORAGE names, with the credentials of the Secret
+// OCTARINE_STORAGE_SECRET names; it returns nil when no object storage is configured
+func (oClient *Client) artifactStore(ctx context.Context) (storage.Store, error) {
+	location := os.Getenv("OCTARINE_STORAGE")
+	if location == "" {
+		return nil, nil
+	}
+	creds := storage.Credentials{}
+	if ref := os.Getenv("OCTARINE_STORAGE_SECRET"); ref != "" {
+		parts := strings.SplitN(ref, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("error: OCTARINE_STORAGE_SECRET must be <namespace>/<name>, got %q", ref)
+		}
+		secret, err := oClient.clientset(ctx).CoreV1().Secrets(parts[0]).Get(parts[1], metav1.GetOptions{})
+		if err != nil {
+			err = errors.Wrapf(err, "unable to get the storage credentials %s", ref)
+			logrus.Error(err)
+			return nil, err
+		}
+		for k, v := range secret.Data {
+			creds[k] = string(v)
+		}
+	}
+	store, err := storage.New(location, creds)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return store, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// auditEntry records an action taken on the Octarine control plane on behalf of a Meshery user
+type auditEntry struct {
+	Time       time.Time `json:"time"`
+	User       string    `json:"user,omitempty"`
+	Action     string    `json:"action"`
+	Deployment string    `json:"deployment,omitempty"`
+	Target     string    `json:"target,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	Details    string    `json:"details,omitempty"`
+	// Error is empty when the action succeeded
+	Error string `json:"error,omitempty"`
+}
+
+var auditMu sync.Mutex
+
+// recordAudit logs the entry, and appends it as a JSON line to the file OCTARINE_AUDIT_LOG names
+func recordAudit(entry auditEntry, err error) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	logrus.WithFields(logrus.Fields{
+		"audit":      true,
+		"user":       entry.User,
+		"action":     entry.Action,
+		"deployment": entry.Deployment,
+		"target":     entry.Target,
+		"reason":     entry.Reason,
+		"error":      entry.Error,
+	}).Info(entry.Details)
+
+	path := os.Getenv("OCTARINE_AUDIT_LOG")
+	if path == "" {
+		return
+	}
+	line, merr := json.Marshal(entry)
+	if merr != nil {
+		logrus.Error(errors.Wrapf(merr, "unable to marshal audit entry"))
+		return
+	}
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	f, ferr := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if ferr != nil {
+		logrus.Error(errors.Wrapf(ferr, "unable to open audit log %s", path))
+		return
+	}
+	defer f.Close()
+	if _, ferr := f.Write(append(line, '\n')); ferr != nil {
+		logrus.Error(errors.Wrapf(ferr, "unable to write audit log %s", path))
+	}
+}
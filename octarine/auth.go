@@ -0,0 +1,136 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	// registers the oidc auth provider, the exec plugins are built into client-go
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
+)
+
+// credentialHelpersEnv lists the directories where exec plugins mounted into the adapter are looked up first
+const credentialHelpersEnv = "OCTARINE_CREDENTIAL_HELPERS_DIR"
+
+const tokenRemedy = "or pass a token for the cluster along with the kubeconfig"
+
+// execAPIVersions are the ExecCredential versions the vendored client-go speaks
+var execAPIVersions = map[string]bool{
+	"client.authentication.k8s.io/v1alpha1": true,
+	"client.authentication.k8s.io/v1beta1":  true,
+}
+
+// execPluginRemedies say how to get the exec plugins of the managed clusters into the adapter
+var execPluginRemedies = map[string]string{
+	"aws-iam-authenticator":  "add aws-iam-authenticator to the adapter image, or generate the kubeconfig with `aws eks update-kubeconfig` and add the aws CLI",
+	"aws":                    "add the aws CLI to the adapter image along with AWS credentials in its environment",
+	"gke-gcloud-auth-plugin": "add gke-gcloud-auth-plugin from the Google Cloud SDK to the adapter image",
+	"gcloud":                 "add the Google Cloud SDK to the adapter image, or generate the kubeconfig with gke-gcloud-auth-plugin",
+	"kubelogin":              "add kubelogin to the adapter image, its non-interactive login modes are the ones that work in a container",
+}
+
+// authProviderRemedies cover the auth providers which aren't built into the adapter
+var authProviderRemedies = map[string]string{
+	"gcp":   "generate the kubeconfig with the gke-gcloud-auth-plugin exec plugin instead",
+	"azure": "convert the kubeconfig to the kubelogin exec plugin with `kubelogin convert-kubeconfig`",
+}
+
+// lookupCredentialHelper finds the binary of an exec plugin in the helper directories, then in PATH
+func lookupCredentialHelper(command string) (string, error) {
+	if !strings.ContainsRune(command, filepath.Separator) {
+		for _, dir := range filepath.SplitList(os.Getenv(credentialHelpersEnv)) {
+			if dir == "" {
+				continue
+			}
+			path := filepath.Join(dir, command)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+				return path, nil
+			}
+		}
+	}
+	return exec.LookPath(command)
+}
+
+// prepareAuth checks that the user of a context can authenticate from within the adapter, resolving the binary
+// of its exec plugin, so a kubeconfig depending on what the adapter lacks is rejected with the remedy instead of
+// failing on the first request; a token replaces whatever credentials the user has
+func prepareAuth(cfg *clientcmdapi.Config, token string) error {
+	kubeContext, ok := cfg.Contexts[cfg.CurrentContext]
+	if !ok {
+		return fmt.Errorf("error: context %q is not in the kubeconfig", cfg.CurrentContext)
+	}
+	authInfo, ok := cfg.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		if token == "" {
+			return nil
+		}
+		authInfo = clientcmdapi.NewAuthInfo()
+		cfg.AuthInfos[kubeContext.AuthInfo] = authInfo
+	}
+	if token != "" {
+		authInfo.Token = token
+		authInfo.TokenFile = ""
+		authInfo.Exec = nil
+		authInfo.AuthProvider = nil
+		authInfo.Username = ""
+		authInfo.Password = ""
+		return nil
+	}
+
+	switch {
+	case authInfo.Exec != nil:
+		command := authInfo.Exec.Command
+		if !execAPIVersions[authInfo.Exec.APIVersion] {
+			return fmt.Errorf("error: the exec plugin %s of user %q speaks %s, the adapter only supports v1alpha1 and v1beta1 of client.authentication.k8s.io: set the apiVersion of the plugin to one of those, %s",
+				command, kubeContext.AuthInfo, authInfo.Exec.APIVersion, tokenRemedy)
+		}
+		path, err := lookupCredentialHelper(command)
+		if err != nil {
+			remedy, ok := execPluginRemedies[filepath.Base(command)]
+			if !ok {
+				remedy = fmt.Sprintf("add %s to the adapter image", command)
+			}
+			return fmt.Errorf("error: the kubeconfig authenticates with the exec plugin %s, which the adapter can't find: %s; or mount the plugin and set %s to its directory; %s",
+				command, remedy, credentialHelpersEnv, tokenRemedy)
+		}
+		logrus.Debugf("user %s authenticates with the exec plugin %s", kubeContext.AuthInfo, path)
+		authInfo.Exec.Command = path
+	case authInfo.AuthProvider != nil:
+		provider := authInfo.AuthProvider
+		if remedy, ok := authProviderRemedies[provider.Name]; ok {
+			return fmt.Errorf("error: the %s auth provider of user %q is not built into the adapter: %s, %s",
+				provider.Name, kubeContext.AuthInfo, remedy, tokenRemedy)
+		}
+		if provider.Name == "oidc" && (provider.Config["refresh-token"] == "" || provider.Config["idp-issuer-url"] == "") {
+			logrus.Warnf("The oidc user %s has no refresh token or issuer, requests will fail once its id-token expires", kubeContext.AuthInfo)
+		}
+	}
+	return nil
+}
+
+// memoryPersister keeps the tokens an auth provider refreshes for the life of the client, the kubeconfig
+// came over the wire so there is no file to write them back to
+type memoryPersister struct{}
+
+func (memoryPersister) Persist(map[string]string) error {
+	return nil
+}
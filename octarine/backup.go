@@ -0,0 +1,371 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/layer5io/meshery-octarine/storage"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	backupLabel   = "octarine.io/backup"
+	backupDataKey = "backup.json.gz"
+	backupPrefix  = "octarine-backup-"
+)
+
+// backup is a snapshot of the Octarine objects of a deployment
+type backup struct {
+	Deployment string                   `json:"deployment"`
+	Version    string                   `json:"version,omitempty"`
+	Created    time.Time                `json:"created"`
+	Reason     string                   `json:"reason,omitempty"`
+	Objects    []map[string]interface{} `json:"objects"`
+}
+
+// backupStore keeps backups by name
+type backupStore interface {
+	save(ctx context.Context, name string, data []byte) error
+	load(ctx context.Context, name string) ([]byte, error)
+}
+
+// secretBackupStore keeps backups in Secrets of the dataplane namespace, since they hold Secrets themselves.
+// They aren't owned by the anchor so uninstalling a deployment doesn't take its backups along.
+type secretBackupStore struct {
+	oClient *Client
+	d       *deployment
+}
+
+func (s *secretBackupStore) save(ctx context.Context, name string, data []byte) error {
+	labels := s.d.managedLabels()
+	labels[backupLabel] = s.d.name
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: s.d.namespace, Labels: labels},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{backupDataKey: data},
+	}
+	if _, err := s.oClient.clientset(ctx).CoreV1().Secrets(s.d.namespace).Create(secret); err != nil {
+		err = errors.Wrapf(err, "unable to store backup %s", name)
+		logrus.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (s *secretBackupStore) load(ctx context.Context, name string) ([]byte, error) {
+	secret, err := s.oClient.clientset(ctx).CoreV1().Secrets(s.d.namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		err = errors.Wrapf(err, "unable to get backup %s", name)
+		logrus.Error(err)
+		return nil, err
+	}
+	if secret.GetLabels()[backupLabel] != s.d.name {
+		return nil, fmt.Errorf("error: %s is not a backup of deployment %s", name, s.d.name)
+	}
+	return secret.Data[backupDataKey], nil
+}
+
+// objectBackupStore keeps backups in the configured object storage, under backups/<deployment>/
+type objectBackupStore struct {
+	store storage.Store
+	d     *deployment
+}
+
+func (s *objectBackupStore) key(name string) string {
+	return "backups/" + s.d.name + "/" + name + ".json.gz"
+}
+
+func (s *objectBackupStore) save(ctx context.Context, name string, data []byte) error {
+	if err := s.store.Put(ctx, s.key(name), data); err != nil {
+		err = errors.Wrapf(err, "unable to store backup %s in %s", name, s.store)
+		logrus.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (s *objectBackupStore) load(ctx context.Context, name string) ([]byte, error) {
+	data, err := s.store.Get(ctx, s.key(name))
+	if err != nil {
+		err = errors.Wrapf(err, "unable to get backup %s from %s", name, s.store)
+		logrus.Error(err)
+		return nil, err
+	}
+	return data, nil
+}
+
+// backupStoreFor keeps backups in object storage when it is configured, in Secrets otherwise
+func (oClient *Client) backupStoreFor(ctx context.Context, d *deployment) (backupStore, error) {
+	store, err := oClient.artifactStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if store != nil {
+		return &objectBackupStore{store: store, d: d}, nil
+	}
+	return &secretBackupStore{oClient: oClient, d: d}, nil
+}
+
+// backupResource is a kind the backup covers
+type backupResource struct {
+	gvr        schema.GroupVersionResource
+	namespaced bool
+}
+
+// octarineResources discovers the custom resources Octarine registered in the cluster
+func (oClient *Client) octarineResources(ctx context.Context) ([]backupResource, error) {
+	lists, err := oClient.clientset(ctx).Discovery().ServerPreferredResources()
+	if err != nil && len(lists) == 0 {
+		err = errors.Wrapf(err, "unable to discover the resources of the cluster")
+		logrus.Error(err)
+		return nil, err
+	}
+	resources := []backupResource{}
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil || !strings.Contains(gv.Group, "octarine") {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if strings.Contains(r.Name, "/") || !stringSet(r.Verbs)["list"] {
+				continue
+			}
+			resources = append(resources, backupResource{gvr: gv.WithResource(r.Name), namespaced: r.Namespaced})
+		}
+	}
+	return resources, nil
+}
+
+// snapshotObjects collects the Secrets and the anchor of the dataplane namespace, then the Octarine custom resources
+func (oClient *Client) snapshotObjects(ctx context.Context, d *deployment) ([]map[string]interface{}, error) {
+	objects := []map[string]interface{}{}
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	list, err := oClient.dynamicClient(ctx).Resource(secrets).Namespace(d.namespace).List(metav1.ListOptions{})
+	if err != nil {
+		err = errors.Wrapf(err, "unable to list the secrets of namespace %s", d.namespace)
+		logrus.Error(err)
+		return nil, err
+	}
+	for _, item := range list.Items {
+		kind, _, _ := unstructured.NestedString(item.Object, "type")
+		// tokens are reissued by the cluster and backups aren't backed up again
+		if kind == string(corev1.SecretTypeServiceAccountToken) || item.GetLabels()[backupLabel] != "" {
+			continue
+		}
+		objects = append(objects, item.Object)
+	}
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	anchor, err := oClient.dynamicClient(ctx).Resource(configMaps).Namespace(d.namespace).Get(resourceName(anchorName), metav1.GetOptions{})
+	if err != nil {
+		err = errors.Wrapf(err, "unable to get the anchor of deployment %s", d.name)
+		logrus.Error(err)
+		return nil, err
+	}
+	objects = append(objects, anchor.Object)
+
+	resources, err := oClient.octarineResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range resources {
+		workingOn(ctx, "backing up %s", r.gvr.Resource)
+		var list *unstructured.UnstructuredList
+		if r.namespaced {
+			list, err = oClient.dynamicClient(ctx).Resource(r.gvr).Namespace(metav1.NamespaceAll).List(metav1.ListOptions{})
+		} else {
+			list, err = oClient.dynamicClient(ctx).Resource(r.gvr).List(metav1.ListOptions{})
+		}
+		if err != nil {
+			err = errors.Wrapf(err, "unable to list %s", r.gvr.String())
+			logrus.Error(err)
+			return nil, err
+		}
+		progressed(ctx)
+		for _, item := range list.Items {
+			objects = append(objects, item.Object)
+		}
+	}
+	return objects, nil
+}
+
+// backupDeployment snapshots the Octarine objects of a deployment and returns the name of the backup
+func (oClient *Client) backupDeployment(ctx context.Context, d *deployment, reason string) (string, error) {
+	objects, err := oClient.snapshotObjects(ctx, d)
+	if err != nil {
+		return "", err
+	}
+	b := backup{Deployment: d.name, Version: d.version, Created: time.Now().UTC(), Reason: reason, Objects: objects}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(b); err != nil {
+		return "", errors.Wrapf(err, "unable to encode the backup")
+	}
+	if err := zw.Close(); err != nil {
+		return "", errors.Wrapf(err, "unable to compress the backup")
+	}
+	name := backupPrefix + d.name + "-" + b.Created.Format("20060102-150405")
+	store, err := oClient.backupStoreFor(ctx, d)
+	if err != nil {
+		return "", err
+	}
+	if err := store.save(ctx, name, buf.Bytes()); err != nil {
+		return "", err
+	}
+	logrus.Infof("Backed up %d objects of deployment %s as %s", len(objects), d.name, name)
+	return name, nil
+}
+
+// backupBeforeUpgrade keeps a backup to restore when an upgrade goes wrong, the upgrade doesn't start without one
+func (oClient *Client) backupBeforeUpgrade(ctx context.Context, d *deployment, version string) error {
+	name, err := oClient.backupDeployment(ctx, d, fmt.Sprintf("before upgrading from %s to %q", d.versionName(), version))
+	if err != nil {
+		return errors.Wrapf(err, "unable to back up deployment %s before the upgrade", d.name)
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: operationIDFrom(ctx),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Deployment %s backed up as %s", d.name, name),
+		Details:     fmt.Sprintf("Restore it with the %s operation if the upgrade goes wrong.", backupRestoreCommand),
+	}
+	return nil
+}
+
+// executeBackup backs up the deployment of the custom body on demand
+func (oClient *Client) executeBackup(ctx context.Context, arReq *meshes.ApplyRuleRequest) (string, error) {
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return "", err
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return "", err
+	}
+	return oClient.backupDeployment(ctx, d, "requested")
+}
+
+func decodeBackup(data []byte) (*backup, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to decompress the backup")
+	}
+	raw, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to decompress the backup")
+	}
+	b := &backup{}
+	if err := json.Unmarshal(raw, b); err != nil {
+		return nil, errors.Wrapf(err, "unable to decode the backup")
+	}
+	return b, nil
+}
+
+// restoreObject recreates an object of a backup, or updates it when it still exists
+func (oClient *Client) restoreObject(ctx context.Context, obj *unstructured.Unstructured, anchor *metav1.OwnerReference) error {
+	owners := []metav1.OwnerReference{}
+	for _, owner := range obj.GetOwnerReferences() {
+		// the anchor is recreated when the dataplane is reinstalled, other owners are gone with the old one
+		if owner.Kind == "ConfigMap" && owner.Name == anchor.Name {
+			owners = append(owners, *anchor)
+		}
+	}
+	obj.SetOwnerReferences(owners)
+	obj.SetResourceVersion("")
+	obj.SetUID("")
+	obj.SetCreationTimestamp(metav1.Time{})
+	obj.SetSelfLink("")
+	unstructured.RemoveNestedField(obj.Object, "status")
+
+	var ri dynamic.ResourceInterface = oClient.dynamicClient(ctx).Resource(resourceFor(obj))
+	if obj.GetNamespace() != "" {
+		ri = oClient.dynamicClient(ctx).Resource(resourceFor(obj)).Namespace(obj.GetNamespace())
+	}
+	existing, err := ri.Get(obj.GetName(), metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = ri.Create(obj, metav1.CreateOptions{})
+	case err == nil:
+		obj.SetResourceVersion(existing.GetResourceVersion())
+		_, err = ri.Update(obj, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "unable to restore %s %s", obj.GetKind(), obj.GetName())
+		logrus.Error(err)
+	}
+	return err
+}
+
+// executeRestore puts the objects of the backup named in the custom body back in place
+func (oClient *Client) executeRestore(ctx context.Context, arReq *meshes.ApplyRuleRequest) (*backup, error) {
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return nil, err
+	}
+	if params.Backup == "" {
+		return nil, errors.New("error: the name of the backup to restore is required")
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return nil, err
+	}
+	store, err := oClient.backupStoreFor(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	data, err := store.load(ctx, params.Backup)
+	if err != nil {
+		return nil, err
+	}
+	b, err := decodeBackup(data)
+	if err != nil {
+		return nil, err
+	}
+	anchor, err := oClient.anchorOwner(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	failed := []string{}
+	for _, o := range b.Objects {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		obj := &unstructured.Unstructured{Object: o}
+		workingOn(ctx, "restoring %s %s", obj.GetKind(), obj.GetName())
+		if err := oClient.restoreObject(ctx, obj, anchor); err != nil {
+			failed = append(failed, err.Error())
+			continue
+		}
+		progressed(ctx)
+	}
+	if len(failed) > 0 {
+		return nil, fmt.Errorf("error: %d of %d objects were not restored:\n%s", len(failed), len(b.Objects), strings.Join(failed, "\n"))
+	}
+	return b, nil
+}
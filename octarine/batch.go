@@ -0,0 +1,299 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	batchReport   = "report"
+	batchShutdown = "shutdown"
+	batchExclude  = "exclude"
+
+	// sidecarShutdownAnnotation has the injected sidecar exit once the other containers of its pod completed,
+	// so the pods of Jobs terminate
+	sidecarShutdownAnnotation = "octarine.io/sidecar-shutdown"
+	sidecarShutdownValue      = "on-completion"
+
+	// the affected Jobs and CronJobs are listed in an event up to this many
+	maxListedBatchWorkloads = 20
+)
+
+var batchModes = map[string]bool{batchReport: true, batchShutdown: true, batchExclude: true}
+
+// batchWorkloads are the batch workloads of the injected namespaces the sidecar gets in the way of
+type batchWorkloads struct {
+	// stuck are the Jobs whose pods only run the sidecar anymore, they never complete
+	stuck []string
+	// injected are the Jobs whose pods run a sidecar
+	injected []string
+	// cronJobs are the CronJobs whose Jobs get a sidecar which doesn't shut down
+	cronJobs []batchv1beta1.CronJob
+}
+
+// validateBatchMode checks the handling of the batch workloads an operation asks for
+func validateBatchMode(mode string) error {
+	if mode != "" && !batchModes[mode] {
+		return fmt.Errorf("error: batch must be %s, %s or %s, got %q", batchReport, batchShutdown, batchExclude, mode)
+	}
+	return nil
+}
+
+// onlySidecarRunning tells whether the sidecar is the last container of a pod still running
+func onlySidecarRunning(pod corev1.Pod, sidecar string) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	sidecarRunning, others := false, 0
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == sidecar {
+			sidecarRunning = status.State.Running != nil
+			continue
+		}
+		if status.State.Terminated == nil {
+			return false
+		}
+		others++
+	}
+	return sidecarRunning && others > 0
+}
+
+// handlesShutdown tells whether the Jobs of a CronJob get a sidecar exiting with them, or no sidecar at all
+func handlesShutdown(cronJob batchv1beta1.CronJob) bool {
+	template := cronJob.Spec.JobTemplate.Spec.Template
+	return template.Annotations[sidecarShutdownAnnotation] == sidecarShutdownValue ||
+		template.Labels[injectOptOutLabel] == injectOptOutValue
+}
+
+// findBatchWorkloads looks for the Jobs and CronJobs of a namespace running, or about to run, the sidecar
+func (oClient *Client) findBatchWorkloads(ctx context.Context, namespace string, prefixes map[string]bool, found *batchWorkloads) error {
+	pods, err := oClient.clientset(ctx).CoreV1().Pods(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "unable to list the pods in namespace %s", namespace)
+	}
+	injected, stuck := map[string]bool{}, map[string]bool{}
+	for _, pod := range pods.Items {
+		owner := metav1.GetControllerOf(&pod)
+		if owner == nil || owner.Kind != "Job" {
+			continue
+		}
+		sidecar := sidecarContainer(pod, prefixes)
+		if sidecar == nil {
+			continue
+		}
+		job := fmt.Sprintf("Job %s/%s", namespace, owner.Name)
+		injected[job] = true
+		if onlySidecarRunning(pod, sidecar.Name) {
+			stuck[job] = true
+		}
+	}
+	found.injected = append(found.injected, sortedKeys(injected)...)
+	found.stuck = append(found.stuck, sortedKeys(stuck)...)
+
+	cronJobs, err := oClient.clientset(ctx).BatchV1beta1().CronJobs(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "unable to list the CronJobs in namespace %s", namespace)
+	}
+	for _, cronJob := range cronJobs.Items {
+		if !handlesShutdown(cronJob) {
+			found.cronJobs = append(found.cronJobs, cronJob)
+		}
+	}
+	return nil
+}
+
+// listWorkloads writes at most maxListedBatchWorkloads workloads on a line
+func listWorkloads(workloads []string) string {
+	if len(workloads) > maxListedBatchWorkloads {
+		return fmt.Sprintf("%s and %d more", strings.Join(workloads[:maxListedBatchWorkloads], ", "), len(workloads)-maxListedBatchWorkloads)
+	}
+	return strings.Join(workloads, ", ")
+}
+
+// shutdownPatch sets, or removes with delete, the sidecar shutdown annotation of the pod template of a CronJob
+func shutdownPatch(ctx context.Context, deleteOp bool) ([]byte, error) {
+	var value interface{} = sidecarShutdownValue
+	if deleteOp {
+		value = nil
+	}
+	patch := map[string]interface{}{"annotations": map[string]interface{}{sidecarShutdownAnnotation: value}}
+	path := templateMetadataPath("CronJob")
+	for i := len(path) - 1; i >= 0; i-- {
+		patch = map[string]interface{}{path[i]: patch}
+	}
+	if annotations := patchAnnotations(ctx, nil); len(annotations) > 0 {
+		patch["metadata"] = map[string]interface{}{"annotations": annotations}
+	}
+	return json.Marshal(patch)
+}
+
+// executeBatchWorkloads reports the Jobs and CronJobs of the injected namespaces the sidecar keeps from
+// completing. The shutdown mode has the sidecars of the Jobs of their CronJobs exit once the Job is done, the
+// exclude mode keeps those Jobs out of injection; delete_op undoes either.
+func (oClient *Client) executeBatchWorkloads(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	mode := params.Batch
+	if mode == "" {
+		mode = batchReport
+	}
+	if err := validateBatchMode(mode); err != nil {
+		return err
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return err
+	}
+	_, prefixes, err := oClient.dataplaneImages(ctx, d)
+	if err != nil {
+		return err
+	}
+	namespaces := []string{arReq.GetNamespace()}
+	if arReq.GetNamespace() == "" {
+		injected, err := oClient.injectedNamespaces(ctx, d.name)
+		if err != nil {
+			return err
+		}
+		namespaces = sortedKeys(injected)
+	}
+
+	if arReq.GetDeleteOp() && mode != batchReport {
+		return oClient.undoBatchHandling(ctx, arReq, namespaces, mode)
+	}
+	found := &batchWorkloads{}
+	for _, namespace := range namespaces {
+		workingOn(ctx, "Jobs and CronJobs in namespace %s", namespace)
+		if err := oClient.findBatchWorkloads(ctx, namespace, prefixes, found); err != nil {
+			return err
+		}
+		progressed(ctx)
+	}
+
+	handled := []string{}
+	pending := []string{}
+	for _, cronJob := range found.cronJobs {
+		target := changeTarget{kind: "CronJob", namespace: cronJob.Namespace, name: cronJob.Name}.String()
+		var patch []byte
+		switch mode {
+		case batchShutdown:
+			patch, err = shutdownPatch(ctx, false)
+		case batchExclude:
+			patch, err = exclusionPatch("CronJob", patchAnnotations(ctx, map[string]interface{}{
+				injectOptOutAnnotation: injectOptOutValue,
+				excludedByAnnotation:   fieldManager,
+			}), injectOptOutValue)
+		default:
+			pending = append(pending, target)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		workingOn(ctx, "changing the sidecar of %s", target)
+		if _, err := oClient.clientset(ctx).BatchV1beta1().CronJobs(cronJob.Namespace).Patch(cronJob.Name, types.MergePatchType, patch); err != nil {
+			return errors.Wrapf(err, "unable to change the sidecar of %s", target)
+		}
+		progressed(ctx)
+		handled = append(handled, target)
+	}
+
+	if len(found.stuck) > 0 {
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: arReq.GetOperationId(),
+			EventType:   meshes.EventType_WARN,
+			Summary:     fmt.Sprintf("%d Job(s) kept from completing by the Octarine sidecar", len(found.stuck)),
+			Details: fmt.Sprintf("Only the sidecar of their pods is still running: %s. Jobs can't change their pods, recreate them annotated %s=%s or %s=%s.",
+				listWorkloads(found.stuck), sidecarShutdownAnnotation, sidecarShutdownValue, injectOptOutAnnotation, injectOptOutValue),
+		}
+	}
+	details := []string{fmt.Sprintf("%d Job(s) run the sidecar, %d of them can't complete.", len(found.injected), len(found.stuck))}
+	switch {
+	case len(handled) > 0 && mode == batchShutdown:
+		details = append(details, "The sidecars of the next Jobs of these CronJobs exit once the Job completes: "+listWorkloads(handled))
+	case len(handled) > 0:
+		details = append(details, "The next Jobs of these CronJobs are kept out of injection: "+listWorkloads(handled))
+	case len(pending) > 0:
+		details = append(details, fmt.Sprintf("The Jobs of these CronJobs get a sidecar which doesn't shut down, run %s with batch set to %s or %s: %s",
+			batchWorkloadsCommand, batchShutdown, batchExclude, listWorkloads(pending)))
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Checked the batch workloads of %d namespace(s)", len(namespaces)),
+		Details:     strings.Join(details, "\n"),
+	}
+	return nil
+}
+
+// undoBatchHandling removes the sidecar shutdown annotation, or the exclusion the operation made, from the
+// CronJobs of the namespaces
+func (oClient *Client) undoBatchHandling(ctx context.Context, arReq *meshes.ApplyRuleRequest, namespaces []string, mode string) error {
+	undone := []string{}
+	for _, namespace := range namespaces {
+		workingOn(ctx, "CronJobs in namespace %s", namespace)
+		cronJobs, err := oClient.clientset(ctx).BatchV1beta1().CronJobs(namespace).List(metav1.ListOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "unable to list the CronJobs in namespace %s", namespace)
+		}
+		for _, cronJob := range cronJobs.Items {
+			var patch []byte
+			template := cronJob.Spec.JobTemplate.Spec.Template
+			switch {
+			case mode == batchShutdown && template.Annotations[sidecarShutdownAnnotation] != "":
+				patch, err = shutdownPatch(ctx, true)
+			case mode == batchExclude && cronJob.Annotations[excludedByAnnotation] == fieldManager:
+				patch, err = exclusionPatch("CronJob", patchAnnotations(ctx, map[string]interface{}{
+					injectOptOutAnnotation: nil,
+					excludedByAnnotation:   nil,
+				}), nil)
+			default:
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			target := changeTarget{kind: "CronJob", namespace: namespace, name: cronJob.Name}.String()
+			if _, err := oClient.clientset(ctx).BatchV1beta1().CronJobs(namespace).Patch(cronJob.Name, types.MergePatchType, patch); err != nil {
+				return errors.Wrapf(err, "unable to change the sidecar of %s", target)
+			}
+			undone = append(undone, target)
+		}
+		progressed(ctx)
+	}
+	details := fmt.Sprintf("No CronJob had its batch handling set to %s.", mode)
+	if len(undone) > 0 {
+		details = "The next Jobs of these CronJobs get the default sidecar again: " + listWorkloads(undone)
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Removed the %s handling of %d CronJob(s)", mode, len(undone)),
+		Details:     details,
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// bootstrapSecretName holds what bootstrapping a deployment made in the Octarine control plane, in the
+	// namespace of the deployment
+	bootstrapSecretName = "octarine-bootstrap"
+
+	bootstrapControlPlaneKey = "control_plane"
+	bootstrapAccountKey      = "account"
+	bootstrapDomainKey       = "domain"
+	bootstrapDeploymentKey   = "deployment"
+	bootstrapUsernameKey     = "username"
+	bootstrapPasswordKey     = "password"
+	// a trial bootstrap also holds when its account expires and the email it was signed up with
+	bootstrapTrialKey   = "trial"
+	bootstrapExpiresKey = "expires"
+	bootstrapEmailKey   = "email"
+)
+
+// bootstrap is the account and domain made for a deployment by the bootstrap or the trial operation
+type bootstrap struct {
+	controlPlane string
+	account      string
+	domain       string
+	deployment   string
+	// trial bootstraps have an account of the trial control plane, with credentials of their own
+	trial    bool
+	username string
+	password string
+	expires  string
+}
+
+// loadBootstrap reads the bootstrap Secret of a namespace, nil when the namespace wasn't bootstrapped
+func (oClient *Client) loadBootstrap(ctx context.Context, namespace string) (*bootstrap, error) {
+	secret, err := oClient.clientset(ctx).CoreV1().Secrets(namespace).Get(resourceName(bootstrapSecretName), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the bootstrap of namespace %s", namespace)
+	}
+	return &bootstrap{
+		controlPlane: string(secret.Data[bootstrapControlPlaneKey]),
+		account:      string(secret.Data[bootstrapAccountKey]),
+		domain:       string(secret.Data[bootstrapDomainKey]),
+		deployment:   string(secret.Data[bootstrapDeploymentKey]),
+		trial:        string(secret.Data[bootstrapTrialKey]) == "true",
+		username:     string(secret.Data[bootstrapUsernameKey]),
+		password:     string(secret.Data[bootstrapPasswordKey]),
+		expires:      string(secret.Data[bootstrapExpiresKey]),
+	}, nil
+}
+
+// executeBootstrap creates the Octarine account and domain of a deployment, without installing its dataplane,
+// and hands their identifiers and the account manager credentials over in a Secret. Installing the
+// deployment later uses them instead of creating an account of its own.
+func (oClient *Client) executeBootstrap(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	if oClient.clientset(ctx) == nil {
+		return errors.New("mesh client has not been created")
+	}
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	name := params.Deployment
+	if name == "" {
+		name = defaultDeploymentName
+	}
+	namespace := arReq.GetNamespace()
+	if namespace == "" {
+		namespace = dataplaneNamespace()
+	}
+	if arReq.GetDeleteOp() {
+		return oClient.deleteBootstrap(ctx, arReq, name, namespace)
+	}
+
+	existing, err := oClient.loadBootstrap(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if existing.trial {
+			return fmt.Errorf("error: namespace %s holds the trial of deployment %s", namespace, existing.deployment)
+		}
+		if existing.deployment != name {
+			return fmt.Errorf("error: namespace %s is already bootstrapped for deployment %s", namespace, existing.deployment)
+		}
+		oClient.eventChan <- bootstrapEvent(arReq, namespace, existing, "is already bootstrapped")
+		return nil
+	}
+	domain := params.Domain
+	if domain == "" {
+		domain = os.Getenv("OCTARINE_DOMAIN")
+	}
+	d := &deployment{name: name, namespace: namespace, domain: domain}
+	_, err = oClient.clientset(ctx).CoreV1().Namespaces().Create(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Labels: d.managedLabels()},
+	})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "unable to create namespace %s", namespace)
+	}
+	progressed(ctx)
+
+	workingOn(ctx, "creating the Octarine account and domain %s", domain)
+	if err := oClient.createCpObjects(ctx, d); err != nil {
+		return errors.Wrapf(err, "unable to create the Octarine account and domain %s", domain)
+	}
+	progressed(ctx)
+	b := &bootstrap{controlPlane: oClient.octarineControlPlane, account: d.account, domain: d.domain, deployment: name}
+	_, err = oClient.clientset(ctx).CoreV1().Secrets(namespace).Create(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: resourceName(bootstrapSecretName), Namespace: namespace, Labels: d.managedLabels()},
+		Type:       corev1.SecretTypeOpaque,
+		StringData: map[string]string{
+			bootstrapControlPlaneKey: b.controlPlane,
+			bootstrapAccountKey:      b.account,
+			bootstrapDomainKey:       b.domain,
+			bootstrapDeploymentKey:   b.deployment,
+			bootstrapUsernameKey:     accMgrUsername + "@" + b.account,
+			bootstrapPasswordKey:     oClient.octarineAccMgrPword,
+		},
+	})
+	if err != nil {
+		// without the Secret nobody would know of the account
+		_ = oClient.deleteCpObjects(ctx, d)
+		return errors.Wrapf(err, "unable to create the bootstrap Secret in namespace %s", namespace)
+	}
+	logrus.Infof("Bootstrapped account %s and domain %s for deployment %s", b.account, b.domain, name)
+	oClient.eventChan <- bootstrapEvent(arReq, namespace, b, "is bootstrapped")
+	return nil
+}
+
+// deleteBootstrap deletes the account of a bootstrap and its Secret, once the deployment is uninstalled
+func (oClient *Client) deleteBootstrap(ctx context.Context, arReq *meshes.ApplyRuleRequest, name, namespace string) error {
+	b, err := oClient.loadBootstrap(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	if b == nil {
+		return fmt.Errorf("error: namespace %s was not bootstrapped", namespace)
+	}
+	if d, err := oClient.getDeployment(b.deployment); err == nil && d.namespace == namespace {
+		return fmt.Errorf("error: deployment %s still uses the bootstrap of namespace %s, uninstall it first", b.deployment, namespace)
+	}
+	details := fmt.Sprintf("Account %s and domain %s were deleted from the control plane, with the Secret %s/%s", b.account, b.domain, namespace, resourceName(bootstrapSecretName))
+	if b.trial {
+		// the credentials of a trial can't delete its account, the trial control plane does when it expires
+		details = fmt.Sprintf("The Secret %s/%s was deleted, the trial account %s expires on %s", namespace, resourceName(bootstrapSecretName), b.account, b.expires)
+	} else {
+		oClient.loadControlPlaneCredentials()
+		workingOn(ctx, "deleting the Octarine account %s", b.account)
+		if err := oClient.deleteCpObjects(ctx, &deployment{name: name, namespace: namespace, account: b.account, domain: b.domain}); err != nil {
+			return errors.Wrapf(err, "unable to delete the Octarine account %s", b.account)
+		}
+		progressed(ctx)
+	}
+	err = oClient.clientset(ctx).CoreV1().Secrets(namespace).Delete(resourceName(bootstrapSecretName), &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "unable to delete the bootstrap Secret of namespace %s", namespace)
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Deleted the bootstrap of deployment %s", b.deployment),
+		Details:     details,
+	}
+	return nil
+}
+
+// useBootstrap makes a new deployment use the account of the bootstrap of its namespace, it tells whether there
+// was one. The octactl commands of a trial log in with the credentials of its own account.
+func (oClient *Client) useBootstrap(ctx context.Context, d *deployment) (bool, error) {
+	b, err := oClient.loadBootstrap(ctx, d.namespace)
+	if err != nil || b == nil {
+		return false, err
+	}
+	if b.deployment != d.name {
+		return false, fmt.Errorf("error: namespace %s is bootstrapped for deployment %s", d.namespace, b.deployment)
+	}
+	oClient.loadControlPlaneCredentials()
+	d.account, d.domain, d.bootstrapped = b.account, b.domain, true
+	if b.trial {
+		d.login = &accountLogin{controlPlane: b.controlPlane, username: b.username, password: b.password}
+	}
+	return true, nil
+}
+
+func bootstrapEvent(arReq *meshes.ApplyRuleRequest, namespace string, b *bootstrap, status string) *meshes.EventsResponse {
+	return &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Deployment %s %s", b.deployment, status),
+		Details: fmt.Sprintf("Control plane: %s\nAccount: %s\nDomain: %s\nThe account manager credentials are in the keys %s and %s of the Secret %s/%s",
+			b.controlPlane, b.account, b.domain, bootstrapUsernameKey, bootstrapPasswordKey, namespace, resourceName(bootstrapSecretName)),
+	}
+}
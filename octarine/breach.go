@@ -0,0 +1,173 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	breachLabel         = "octarine.io/breach-simulation"
+	breachContainerName = "attacker"
+	defaultBreachTarget = "example.com"
+
+	// violations reach the control plane with some delay after they happen
+	breachDetectionTimeout = 2 * time.Minute
+	breachPollInterval     = 10 * time.Second
+)
+
+// breachScanPorts are probed on the API server address, standing in for a workload mapping its neighbours
+var breachScanPorts = []string{"22", "23", "25", "80", "443", "2379", "3306", "5432", "6379", "8080", "9200", "10250", "27017"}
+
+// breachScript only makes connections a workload has no business making, it doesn't send anything over them
+const breachScript = `if wget -q -T 5 -O /dev/null "http://$TARGET/"; then echo "egress allowed"; else echo "egress failed"; fi
+for port in $PORTS; do
+  if nc -w 1 "$KUBERNETES_SERVICE_HOST" "$port" </dev/null >/dev/null 2>&1; then echo "port $port open"; else echo "port $port closed"; fi
+done
+`
+
+// breachOutcome summarizes what the attacker pod got away with from its log
+func breachOutcome(log, target string) []string {
+	lines := []string{}
+	open := []string{}
+	probed := 0
+	for _, line := range strings.Split(log, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 2 && fields[0] == "egress":
+			if fields[1] == "allowed" {
+				lines = append(lines, fmt.Sprintf("egress to %s: allowed", target))
+			} else {
+				lines = append(lines, fmt.Sprintf("egress to %s: failed, blocked or the cluster has no outbound access", target))
+			}
+		case len(fields) == 3 && fields[0] == "port":
+			probed++
+			if fields[2] == "open" {
+				open = append(open, fields[1])
+			}
+		}
+	}
+	if probed > 0 {
+		scan := fmt.Sprintf("port scan of the API server address: %d of %d ports open", len(open), probed)
+		if len(open) > 0 {
+			scan += " (" + strings.Join(open, ", ") + ")"
+		}
+		lines = append(lines, scan)
+	}
+	return lines
+}
+
+// breachViolations waits for the control plane to record violations of the attacker pod
+func (oClient *Client) breachViolations(ctx context.Context, d *deployment, pod *corev1.Pod, since time.Time) (map[string]int64, error) {
+	deadline := time.Now().Add(breachDetectionTimeout)
+	for {
+		records, err := oClient.listViolations(ctx, d, since)
+		if err != nil {
+			return nil, err
+		}
+		progressed(ctx)
+		policies := map[string]int64{}
+		for _, r := range records {
+			if r.Namespace == pod.Namespace && r.Workload == pod.Name {
+				policies[r.Policy] += r.Count
+			}
+		}
+		if len(policies) > 0 || time.Now().After(deadline) {
+			return policies, nil
+		}
+		workingOn(ctx, "violations of the attacker pod %s/%s to be reported", pod.Namespace, pod.Name)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(breachPollInterval):
+		}
+	}
+}
+
+// executeBreachSimulation runs a short lived pod making unexpected connections in an injected namespace
+// and reports whether Octarine recorded the violations
+func (oClient *Client) executeBreachSimulation(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	if oClient.clientset(ctx) == nil {
+		return fmt.Errorf("error: mesh client has not been created")
+	}
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return err
+	}
+	namespace := arReq.GetNamespace()
+	if namespace == "" {
+		return fmt.Errorf("error: the namespace to simulate the breach in is required")
+	}
+	if err := oClient.requireInjected(ctx, d, namespace); err != nil {
+		return err
+	}
+	target := params.Target
+	if target == "" {
+		target = defaultBreachTarget
+	}
+
+	name := resourceName("octarine-breach-" + newOperationID()[:8])
+	labels := d.managedLabels()
+	labels[breachLabel] = "true"
+	pod := probePod(name, namespace, breachContainerName, breachScript, labels, []corev1.EnvVar{
+		{Name: "TARGET", Value: target},
+		{Name: "PORTS", Value: strings.Join(breachScanPorts, " ")},
+	})
+	// allows for the clock of the control plane running behind the adapter's
+	started := time.Now().Add(-time.Minute)
+	log, err := oClient.runProbe(ctx, pod)
+	if err != nil {
+		return err
+	}
+	lines := breachOutcome(log, target)
+
+	policies, err := oClient.breachViolations(ctx, d, pod, started)
+	if err != nil {
+		return err
+	}
+	total := int64(0)
+	names := make([]string, 0, len(policies))
+	for policy, count := range policies {
+		total += count
+		names = append(names, fmt.Sprintf("%s (%d)", policy, count))
+	}
+	sort.Strings(names)
+	event := &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Breach simulation in namespace %s was caught: %d violations recorded", namespace, total),
+	}
+	if total == 0 {
+		event.Severity = meshes.Severity_SEVERITY_CRITICAL
+		event.Summary = fmt.Sprintf("Breach simulation in namespace %s went undetected", namespace)
+		lines = append(lines, fmt.Sprintf("no violations of pod %s were recorded within %s", name, breachDetectionTimeout))
+	} else {
+		lines = append(lines, "violated policies: "+strings.Join(names, ", "))
+	}
+	event.Details = strings.Join(lines, "\n")
+	oClient.eventChan <- event
+	return nil
+}
@@ -0,0 +1,212 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	podSecurityPolicy    = "PodSecurityPolicy"
+	podSecurityAdmission = "PodSecurityAdmission"
+	podSecurityNone      = "None"
+
+	defaultStorageClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	betaDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+	podSecurityEnforceLabel           = "pod-security.kubernetes.io/enforce"
+)
+
+// cniPlugins maps the name fragments of kube-system pods to the CNI plugin they belong to
+var cniPlugins = map[string]string{
+	"calico":      "calico",
+	"cilium":      "cilium",
+	"flannel":     "flannel",
+	"weave-net":   "weave",
+	"canal":       "canal",
+	"aws-node":    "amazon-vpc-cni",
+	"kube-router": "kube-router",
+	"antrea":      "antrea",
+	"azure-cni":   "azure-cni",
+	"azure-cns":   "azure-cni",
+	"kindnet":     "kindnet",
+	"ovnkube":     "ovn-kubernetes",
+	"netd":        "gke-netd",
+}
+
+// ClusterCapabilities inspects the target cluster for the features an Octarine deployment relies on
+func (oClient *Client) ClusterCapabilities(ctx context.Context, _ *meshes.ClusterCapabilitiesRequest) (*meshes.ClusterCapabilitiesResponse, error) {
+	if oClient.clientset(ctx) == nil {
+		return &meshes.ClusterCapabilitiesResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	version, err := oClient.clientset(ctx).Discovery().ServerVersion()
+	if err != nil {
+		err = errors.Wrapf(err, "unable to get the server version")
+		logrus.Error(err)
+		return &meshes.ClusterCapabilitiesResponse{Error: err.Error()}, nil
+	}
+	resp := &meshes.ClusterCapabilitiesResponse{
+		KubernetesVersion: version.GitVersion,
+		CniPlugins:        oClient.cniPlugins(ctx),
+		AdmissionWebhooks: oClient.servesResource(ctx, "admissionregistration.k8s.io", "mutatingwebhookconfigurations"),
+		PodSecurity:       oClient.podSecurityMode(ctx, version.Minor),
+		StorageClasses:    oClient.storageClasses(ctx),
+		LoadBalancer:      oClient.loadBalancerAvailable(ctx),
+	}
+	if stack, err := oClient.ipStack(ctx); err == nil {
+		resp.IpStack = stack
+		if stack != ipStackIPv4 {
+			resp.Notes = append(resp.Notes, fmt.Sprintf("the cluster is %s, which needs Octarine %s or later", stack, ipv6MinVersion))
+		}
+	}
+	if platforms, _, err := oClient.nodePlatforms(ctx); err == nil {
+		for p := range platforms {
+			resp.NodePlatforms = append(resp.NodePlatforms, p.String())
+		}
+		sort.Strings(resp.NodePlatforms)
+		if note := windowsNote(platforms); note != "" {
+			resp.Notes = append(resp.Notes, note)
+		}
+	}
+	return resp, nil
+}
+
+func (oClient *Client) cniPlugins(ctx context.Context) []string {
+	pods, err := oClient.clientset(ctx).CoreV1().Pods(metav1.NamespaceSystem).List(metav1.ListOptions{})
+	if err != nil {
+		logrus.Warnf("unable to list the pods in %s: %v", metav1.NamespaceSystem, err)
+		return nil
+	}
+	found := map[string]bool{}
+	for _, pod := range pods.Items {
+		for fragment, plugin := range cniPlugins {
+			if strings.Contains(pod.GetName(), fragment) {
+				found[plugin] = true
+			}
+		}
+	}
+	return sortedKeys(found)
+}
+
+// servesResource tells whether any version of the API group serves the resource
+func (oClient *Client) servesResource(ctx context.Context, group, resource string) bool {
+	groups, err := oClient.clientset(ctx).Discovery().ServerGroups()
+	if err != nil {
+		logrus.Warnf("unable to list the API groups: %v", err)
+		return false
+	}
+	for _, g := range groups.Groups {
+		if g.Name != group {
+			continue
+		}
+		for _, v := range g.Versions {
+			list, err := oClient.clientset(ctx).Discovery().ServerResourcesForGroupVersion(v.GroupVersion)
+			if err != nil {
+				continue
+			}
+			for _, r := range list.APIResources {
+				if r.Name == resource {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// podSecurityMode reports PodSecurityPolicy when policies are defined, PodSecurityAdmission when
+// namespaces enforce pod security standards or the cluster enables the admission plugin by default
+func (oClient *Client) podSecurityMode(ctx context.Context, minor string) string {
+	if oClient.servesResource(ctx, "policy", "podsecuritypolicies") {
+		psps, err := oClient.clientset(ctx).PolicyV1beta1().PodSecurityPolicies().List(metav1.ListOptions{})
+		if err == nil && len(psps.Items) > 0 {
+			return podSecurityPolicy
+		}
+	}
+	namespaces, err := oClient.clientset(ctx).CoreV1().Namespaces().List(metav1.ListOptions{LabelSelector: podSecurityEnforceLabel})
+	if err == nil && len(namespaces.Items) > 0 {
+		return podSecurityAdmission
+	}
+	// the admission plugin is enabled by default from 1.23 on
+	if n := leadingNumber(minor); n >= 23 {
+		return podSecurityAdmission
+	}
+	return podSecurityNone
+}
+
+func leadingNumber(s string) int {
+	n := 0
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			break
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n
+}
+
+func (oClient *Client) storageClasses(ctx context.Context) []*meshes.StorageClass {
+	list, err := oClient.clientset(ctx).StorageV1().StorageClasses().List(metav1.ListOptions{})
+	if err != nil {
+		logrus.Warnf("unable to list the storage classes: %v", err)
+		return nil
+	}
+	result := make([]*meshes.StorageClass, 0, len(list.Items))
+	for _, sc := range list.Items {
+		annotations := sc.GetAnnotations()
+		result = append(result, &meshes.StorageClass{
+			Name:        sc.GetName(),
+			Provisioner: sc.Provisioner,
+			Default:     annotations[defaultStorageClassAnnotation] == "true" || annotations[betaDefaultStorageClassAnnotation] == "true",
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// loadBalancerAvailable is true when a LoadBalancer service already got an address,
+// or when the nodes are managed by a cloud provider which usually provisions them
+func (oClient *Client) loadBalancerAvailable(ctx context.Context) bool {
+	services, err := oClient.clientset(ctx).CoreV1().Services(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err == nil {
+		for _, svc := range services.Items {
+			if svc.Spec.Type == corev1.ServiceTypeLoadBalancer && len(svc.Status.LoadBalancer.Ingress) > 0 {
+				return true
+			}
+		}
+	}
+	nodes, err := oClient.clientset(ctx).CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil || len(nodes.Items) == 0 {
+		return false
+	}
+	providerID := nodes.Items[0].Spec.ProviderID
+	if providerID == "" {
+		return false
+	}
+	for _, local := range []string{"kind://", "k3s://"} {
+		if strings.HasPrefix(providerID, local) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,210 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+const (
+	// capacityWaitEnv bounds how long a rollout stays paused for capacity before it fails
+	capacityWaitEnv     = "OCTARINE_CAPACITY_WAIT"
+	defaultCapacityWait = 10 * time.Minute
+
+	// scaleUpReason is the event of the cluster autoscaler adding nodes for a pending pod
+	scaleUpReason = "TriggeredScaleUp"
+)
+
+// pressureConditions are the node conditions keeping the scheduler and the kubelet from starting more pods
+var pressureConditions = []corev1.NodeConditionType{
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+}
+
+// capacityReport tells what a namespace misses to schedule its pending pods
+type capacityReport struct {
+	namespace string
+	// unschedulable are the pods the scheduler has no room for
+	unschedulable []string
+	// cpu and memory are the requests of those pods, the least capacity the cluster misses
+	cpu, memory resource.Quantity
+	// pressured are the nodes under pressure, with their conditions
+	pressured []string
+	// scalingUp tells whether the cluster autoscaler is adding nodes for the pods
+	scalingUp bool
+}
+
+func (r *capacityReport) short() bool {
+	return len(r.unschedulable) > 0
+}
+
+// String is the guidance of the report: what is missing, and what is being done about it
+func (r *capacityReport) String() string {
+	lines := []string{
+		fmt.Sprintf("%d pod(s) of namespace %s can't be scheduled for lack of capacity: %s", len(r.unschedulable), r.namespace, truncatedList(r.unschedulable)),
+		fmt.Sprintf("The cluster misses at least cpu=%s memory=%s of allocatable capacity.", r.cpu.String(), r.memory.String()),
+	}
+	if len(r.pressured) > 0 {
+		lines = append(lines, "Nodes under pressure: "+truncatedList(r.pressured))
+	}
+	if r.scalingUp {
+		lines = append(lines, "The cluster autoscaler is adding nodes, the rollout resumes once they are ready.")
+	} else {
+		lines = append(lines, "Add nodes, free that capacity or lower the sidecar resources with octarine_sidecar_resources.")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lacksCapacity tells whether the scheduler left a pod pending because no node has room for it
+func lacksCapacity(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodPending {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse && cond.Reason == corev1.PodReasonUnschedulable {
+			return strings.Contains(cond.Message, "Insufficient") || strings.Contains(cond.Message, "Too many pods")
+		}
+	}
+	return false
+}
+
+// capacityShortfall looks for the pods of a namespace pending for lack of capacity, the nodes under pressure and
+// whether the cluster autoscaler scales up for them
+func (oClient *Client) capacityShortfall(ctx context.Context, namespace string) (*capacityReport, error) {
+	report := &capacityReport{namespace: namespace}
+	pods, err := oClient.clientset(ctx).CoreV1().Pods(namespace).List(metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("status.phase", string(corev1.PodPending)).String(),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the pending pods of namespace %s", namespace)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !lacksCapacity(pod) {
+			continue
+		}
+		report.unschedulable = append(report.unschedulable, pod.Name)
+		for _, c := range pod.Spec.Containers {
+			report.cpu.Add(c.Resources.Requests[corev1.ResourceCPU])
+			report.memory.Add(c.Resources.Requests[corev1.ResourceMemory])
+		}
+		if !report.scalingUp {
+			events, err := oClient.clientset(ctx).CoreV1().Events(namespace).List(metav1.ListOptions{
+				FieldSelector: fields.Set{"involvedObject.name": pod.Name, "reason": scaleUpReason}.AsSelector().String(),
+			})
+			report.scalingUp = err == nil && len(events.Items) > 0
+		}
+	}
+	if !report.short() {
+		return report, nil
+	}
+	sort.Strings(report.unschedulable)
+
+	nodes, err := oClient.clientset(ctx).CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		// the pressure only adds to the guidance
+		logrus.Warnf("Unable to list the nodes for their pressure: %v", err)
+		return report, nil
+	}
+	for _, node := range nodes.Items {
+		conditions := []string{}
+		for _, cond := range node.Status.Conditions {
+			for _, pressure := range pressureConditions {
+				if cond.Type == pressure && cond.Status == corev1.ConditionTrue {
+					conditions = append(conditions, string(cond.Type))
+				}
+			}
+		}
+		if len(conditions) > 0 {
+			report.pressured = append(report.pressured, fmt.Sprintf("%s (%s)", node.Name, strings.Join(conditions, ", ")))
+		}
+	}
+	sort.Strings(report.pressured)
+	return report, nil
+}
+
+// capacityPause is when a rollout paused for capacity, zero while it isn't paused
+type capacityPause struct {
+	since time.Time
+}
+
+// paused checks the capacity of a namespace during a rollout. A shortfall pauses the rollout, with an event
+// telling how much capacity is missing, and fails it with that guidance once the pause exceeds
+// OCTARINE_CAPACITY_WAIT. It returns whether the rollout is paused.
+func (oClient *Client) paused(ctx context.Context, namespace string, pause *capacityPause) (bool, error) {
+	report, err := oClient.capacityShortfall(ctx, namespace)
+	if err != nil {
+		logrus.Warn(err)
+		return false, nil
+	}
+	if !report.short() {
+		if !pause.since.IsZero() {
+			oClient.eventChan <- &meshes.EventsResponse{
+				OperationId: operationIDFrom(ctx),
+				EventType:   meshes.EventType_INFO,
+				Summary:     fmt.Sprintf("Rollout in namespace %s resumed after %s", namespace, time.Since(pause.since).Round(time.Second)),
+			}
+			pause.since = time.Time{}
+		}
+		return false, nil
+	}
+	if pause.since.IsZero() {
+		pause.since = time.Now()
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: operationIDFrom(ctx),
+			EventType:   meshes.EventType_WARN,
+			Summary:     fmt.Sprintf("Rollout in namespace %s paused, the cluster lacks capacity", namespace),
+			Details:     report.String(),
+		}
+	}
+	wait := durationFromEnv(capacityWaitEnv, defaultCapacityWait)
+	if time.Since(pause.since) > wait {
+		return true, fmt.Errorf("error: the rollout in namespace %s was paused for capacity for more than %s\n%s", namespace, wait, report)
+	}
+	workingOn(ctx, "capacity to schedule %d pod(s) in namespace %s", len(report.unschedulable), namespace)
+	// a pause isn't a stall, OCTARINE_CAPACITY_WAIT bounds it instead of the watchdog
+	progressed(ctx)
+	return true, nil
+}
+
+// awaitCapacity holds a rollout back while the pods of a namespace are pending for lack of capacity, so
+// restarting more workloads doesn't add sidecars the cluster can't schedule
+func (oClient *Client) awaitCapacity(ctx context.Context, namespace string) error {
+	pause := &capacityPause{}
+	for {
+		paused, err := oClient.paused(ctx, namespace, pause)
+		if err != nil || !paused {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(rolloutPollInterval):
+		}
+	}
+}
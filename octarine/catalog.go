@@ -0,0 +1,367 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	catalogURLEnv      = "OCTARINE_TEMPLATE_CATALOG"
+	catalogKeyEnv      = "OCTARINE_TEMPLATE_CATALOG_KEY"
+	catalogDirEnv      = "OCTARINE_TEMPLATE_CATALOG_DIR"
+	catalogIntervalEnv = "OCTARINE_TEMPLATE_CATALOG_INTERVAL"
+	catalogPinEnv      = "OCTARINE_TEMPLATE_CATALOG_PIN"
+
+	// catalogVersionFile keeps the version and the digest of the catalog applied last in catalogRoot, across
+	// restarts
+	catalogVersionFile = "version"
+
+	defaultCatalogInterval = time.Hour
+	catalogFetchTimeout    = 30 * time.Second
+	maxCatalogSize         = 8 << 20
+)
+
+// catalog is a signed bundle of operation templates, it replaces the templates of the image once synced
+type catalog struct {
+	// Version is the release number of the catalog, which grows with every catalog published
+	Version    string             `json:"version"`
+	Operations []catalogOperation `json:"operations,omitempty"`
+	// Files are the contents of the templates by their path in config_templates, e.g. 1.10/install.tmpl
+	Files map[string]string `json:"files"`
+}
+
+type catalogOperation struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Template string `json:"template"`
+	// Category is the name of a meshes.OpCategory, e.g. CONFIGURE
+	Category string `json:"category"`
+	// MinVersion is the first Octarine release the operation runs on, Features the licensed features it needs
+	MinVersion string   `json:"min_version,omitempty"`
+	Features   []string `json:"features,omitempty"`
+}
+
+// catalogStatus is the outcome of the last sync, guarded by opsMu
+var catalogStatus struct {
+	version  string
+	syncedAt time.Time
+	err      error
+	ops      map[string]bool
+	previous string
+}
+
+// SyncTemplateCatalog pulls the template catalog set in OCTARINE_TEMPLATE_CATALOG now and then on a schedule,
+// it does nothing when no catalog is set
+func SyncTemplateCatalog() error {
+	url := os.Getenv(catalogURLEnv)
+	if url == "" {
+		return nil
+	}
+	key, err := catalogKey()
+	if err != nil {
+		return err
+	}
+	interval := durationFromEnv(catalogIntervalEnv, defaultCatalogInterval)
+	go func() {
+		for {
+			if err := syncCatalog(url, key); err != nil {
+				logrus.Errorf("Unable to sync the template catalog %s: %v", url, err)
+				opsMu.Lock()
+				catalogStatus.err = err
+				opsMu.Unlock()
+			}
+			time.Sleep(interval)
+		}
+	}()
+	return nil
+}
+
+// catalogKey is the ed25519 public key catalogs must be signed with, an unsigned catalog is never applied
+func catalogKey() (ed25519.PublicKey, error) {
+	encoded := os.Getenv(catalogKeyEnv)
+	if encoded == "" {
+		return nil, fmt.Errorf("error: %s is set but %s, the key the catalog is signed with, is not", catalogURLEnv, catalogKeyEnv)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("error: %s must be a base64 encoded ed25519 public key", catalogKeyEnv)
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+func fetchCatalogFile(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to fetch %s", url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch %s: %s", url, resp.Status)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxCatalogSize+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read %s", url)
+	}
+	if len(body) > maxCatalogSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", url, maxCatalogSize)
+	}
+	return body, nil
+}
+
+// syncCatalog fetches the catalog and its signature, next to it with a .sig suffix, and applies it when it
+// changed and its templates lint clean
+func syncCatalog(url string, key ed25519.PublicKey) error {
+	client := &http.Client{Timeout: catalogFetchTimeout}
+	body, err := fetchCatalogFile(client, url)
+	if err != nil {
+		return err
+	}
+	sig, err := fetchCatalogFile(client, url+".sig")
+	if err != nil {
+		return err
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil || !ed25519.Verify(key, body, signature) {
+		return fmt.Errorf("the signature of the catalog doesn't match its key, the catalog was not applied")
+	}
+	c := &catalog{}
+	if err := json.Unmarshal(body, c); err != nil {
+		return errors.Wrapf(err, "unable to parse the catalog")
+	}
+	sum := sha256.Sum256(body)
+	digest := hex.EncodeToString(sum[:8])
+	dir := path.Join(catalogRoot(), digest)
+	if err := checkCatalogVersion(c.Version, digest); err != nil {
+		return err
+	}
+	if activeTemplatesDir() == dir {
+		opsMu.Lock()
+		catalogStatus.syncedAt, catalogStatus.err = time.Now(), nil
+		opsMu.Unlock()
+		return nil
+	}
+	ops, err := c.operations()
+	if err != nil {
+		return err
+	}
+	// a directory there already is the set before the current one, which renders may still read
+	_, statErr := os.Stat(dir)
+	fresh := os.IsNotExist(statErr)
+	if err := c.write(dir); err != nil {
+		if fresh {
+			_ = os.RemoveAll(dir)
+		}
+		return err
+	}
+	retired, err := applyCatalog(c, dir, ops)
+	if err != nil {
+		if fresh {
+			_ = os.RemoveAll(dir)
+		}
+		return err
+	}
+	if retired != "" {
+		// syncs run one after the other, so the directory isn't written again before it is removed
+		awaitTemplatesDir(retired)
+		_ = os.RemoveAll(retired)
+	}
+	if err := ioutil.WriteFile(filepath.Join(catalogRoot(), catalogVersionFile), []byte(c.Version+" "+digest), 0644); err != nil {
+		logrus.Warnf("Unable to record version %s of the template catalog, an older one is refused until the adapter restarts only: %v", c.Version, err)
+	}
+	logrus.Infof("Applied version %s of the template catalog, %d template(s) and %d operation(s)", c.Version, len(c.Files), len(ops))
+	return nil
+}
+
+// checkCatalogVersion refuses a catalog which isn't newer than the one applied last, a catalog signed once could
+// otherwise be served again to roll the templates back; the one applied last is applied again after a restart.
+// A catalog pinned in OCTARINE_TEMPLATE_CATALOG_PIN is the only one applied, older or not.
+func checkCatalogVersion(version, digest string) error {
+	if pin := os.Getenv(catalogPinEnv); pin != "" {
+		if version != pin {
+			return fmt.Errorf("the catalog is pinned to version %s by %s, version %s was not applied", pin, catalogPinEnv, version)
+		}
+		return nil
+	}
+	if !isRelease(version) {
+		return fmt.Errorf("version %q of the catalog is not a release number, the catalog was not applied", version)
+	}
+	opsMu.RLock()
+	applied, appliedDigest := catalogStatus.version, path.Base(templatesDir)
+	opsMu.RUnlock()
+	if applied == "" {
+		if recorded, err := ioutil.ReadFile(filepath.Join(catalogRoot(), catalogVersionFile)); err == nil {
+			if fields := strings.Fields(string(recorded)); len(fields) == 2 {
+				applied, appliedDigest = fields[0], fields[1]
+			}
+		}
+	}
+	if version == applied && digest == appliedDigest {
+		return nil
+	}
+	if applied != "" && compareVersions(version, applied) <= 0 {
+		return fmt.Errorf("version %s of the catalog is not newer than version %s applied last, the catalog was not applied; set %s to roll back to it", version, applied, catalogPinEnv)
+	}
+	return nil
+}
+
+func catalogRoot() string {
+	if dir := os.Getenv(catalogDirEnv); dir != "" {
+		return dir
+	}
+	return path.Join(os.TempDir(), "octarine-template-catalog")
+}
+
+// operations checks the operations of the catalog, they may replace the template operations of the adapter
+// but not the ones it implements itself
+func (c *catalog) operations() (map[string]supportedOperation, error) {
+	ops := map[string]supportedOperation{}
+	for _, o := range c.Operations {
+		if o.Key == "" || o.Template == "" {
+			return nil, fmt.Errorf("the catalog has an operation without a key or a template")
+		}
+		if builtin, ok := lookupOp(o.Key); ok && builtin.templateName == "" && !catalogOp(o.Key) {
+			return nil, fmt.Errorf("operation %s of the catalog would replace an operation of the adapter", o.Key)
+		}
+		category, ok := meshes.OpCategory_value[o.Category]
+		if !ok {
+			return nil, fmt.Errorf("operation %s of the catalog has an unknown category %q", o.Key, o.Category)
+		}
+		if _, ok := c.Files[o.Template]; !ok && !c.inSets(o.Template) {
+			return nil, fmt.Errorf("operation %s of the catalog renders %s, which the catalog lacks", o.Key, o.Template)
+		}
+		if o.MinVersion != "" && !isRelease(o.MinVersion) {
+			return nil, fmt.Errorf("operation %s of the catalog has a min_version %q which is not a release number", o.Key, o.MinVersion)
+		}
+		ops[o.Key] = supportedOperation{
+			name:         o.Name,
+			templateName: o.Template,
+			opType:       meshes.OpCategory(category),
+			minVersion:   o.MinVersion,
+			features:     o.Features,
+		}
+	}
+	return ops, nil
+}
+
+// inSets tells whether a versioned set of the catalog has a template
+func (c *catalog) inSets(name string) bool {
+	for file := range c.Files {
+		if path.Base(file) == name && path.Dir(file) != "." {
+			return true
+		}
+	}
+	return false
+}
+
+// write stores the templates of the catalog in a new directory, refusing paths outside of it
+func (c *catalog) write(dir string) error {
+	for name, content := range c.Files {
+		clean := path.Clean(name)
+		if path.IsAbs(clean) || clean == "." || strings.HasPrefix(clean, "../") || clean == ".." {
+			return fmt.Errorf("the catalog has a file outside config_templates: %s", name)
+		}
+		file := filepath.Join(dir, filepath.FromSlash(clean))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return errors.Wrapf(err, "unable to store the catalog")
+		}
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			return errors.Wrapf(err, "unable to store the catalog")
+		}
+	}
+	return nil
+}
+
+// catalogOp tells whether an operation came from the catalog
+func catalogOp(key string) bool {
+	opsMu.RLock()
+	defer opsMu.RUnlock()
+	return catalogStatus.ops[key]
+}
+
+// applyCatalog lints every template operation against the templates of the catalog, and switches to them
+// when none is broken. A broken catalog leaves the templates and operations in use untouched. It returns the
+// templates directory no operation renders anymore, empty when there is none to remove.
+func applyCatalog(c *catalog, dir string, ops map[string]supportedOperation) (string, error) {
+	opsMu.Lock()
+	defer opsMu.Unlock()
+	next := map[string]supportedOperation{}
+	for key, op := range supportedOps {
+		if !catalogStatus.ops[key] {
+			next[key] = op
+		}
+	}
+	for key, d := range disabledOps {
+		if !catalogStatus.ops[key] {
+			next[key] = d.op
+		}
+	}
+	for key, op := range ops {
+		next[key] = op
+	}
+	for _, key := range sortedKeys(templateOps(next)) {
+		if _, err := lintTemplateSets(dir, key, next[key].templateName); err != nil {
+			return "", errors.Wrapf(err, "version %s of the catalog was not applied, template %s of operation %s", c.Version, next[key].templateName, key)
+		}
+	}
+
+	supportedOps = next
+	disabledOps = map[string]disabledOp{}
+	// the templates before the current ones are retired, unless the catalog rolls back to them
+	retired := catalogStatus.previous
+	if retired == builtinTemplatesDir || retired == dir || retired == templatesDir {
+		retired = ""
+	}
+	catalogStatus.previous = templatesDir
+	templatesDir = dir
+	catalogStatus.version = c.Version
+	catalogStatus.syncedAt = time.Now()
+	catalogStatus.err = nil
+	catalogStatus.ops = map[string]bool{}
+	for key := range ops {
+		catalogStatus.ops[key] = true
+	}
+	return retired, nil
+}
+
+// catalogSummary describes the last catalog sync for LintTemplates
+func catalogSummary(resp *meshes.LintTemplatesResponse) {
+	opsMu.RLock()
+	defer opsMu.RUnlock()
+	resp.CatalogVersion = catalogStatus.version
+	if !catalogStatus.syncedAt.IsZero() {
+		resp.CatalogSyncedAt = catalogStatus.syncedAt.UTC().Format(time.RFC3339)
+	}
+	if catalogStatus.err != nil {
+		resp.CatalogError = catalogStatus.err.Error()
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// catalogServer serves a catalog signed with a key of its own, as a catalog host would
+type catalogServer struct {
+	*httptest.Server
+	key  ed25519.PublicKey
+	priv ed25519.PrivateKey
+
+	mu   sync.Mutex
+	body []byte
+	sig  []byte
+}
+
+func newCatalogServer(t *testing.T) *catalogServer {
+	key, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &catalogServer{key: key, priv: priv}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if strings.HasSuffix(r.URL.Path, ".sig") {
+			w.Write(s.sig)
+			return
+		}
+		w.Write(s.body)
+	}))
+	return s
+}
+
+// publish has the server serve c from now on
+func (s *catalogServer) publish(t *testing.T, c *catalog) {
+	body, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.body = body
+	s.sig = []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(s.priv, body)))
+}
+
+// testCatalog is a catalog of the templates of the image with an operation of its own, isolating a namespace
+// the way namespace_isolation.tmpl does
+func testCatalog(t *testing.T, version string) *catalog {
+	c := &catalog{
+		Version: version,
+		Operations: []catalogOperation{{
+			Key:      "acme_namespace_isolation",
+			Name:     "Acme namespace isolation",
+			Template: "acme_namespace_isolation.tmpl",
+			Category: "CONFIGURE",
+		}},
+		Files: map[string]string{},
+	}
+	files, err := ioutil.ReadDir(testTemplatesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		content, err := ioutil.ReadFile(filepath.Join(testTemplatesDir, f.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Files[f.Name()] = string(content)
+	}
+	c.Files["acme_namespace_isolation.tmpl"] = c.Files["namespace_isolation.tmpl"]
+	return c
+}
+
+// withCatalogs runs fn with the catalogs stored in a directory of its own, the operations and templates in use
+// before are put back afterwards
+func withCatalogs(t *testing.T, fn func()) {
+	dir, err := ioutil.TempDir("", "octarine-catalog-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Setenv(catalogDirEnv, dir)
+	defer os.Unsetenv(catalogDirEnv)
+
+	opsMu.Lock()
+	ops, disabled, templates, status := supportedOps, disabledOps, templatesDir, catalogStatus
+	opsMu.Unlock()
+	defer func() {
+		opsMu.Lock()
+		supportedOps, disabledOps, templatesDir, catalogStatus = ops, disabled, templates, status
+		opsMu.Unlock()
+	}()
+	fn()
+}
+
+func TestCatalogOperationsCountedUnderCatalog(t *testing.T) {
+	withCatalogs(t, func() {
+		server := newCatalogServer(t)
+		defer server.Close()
+		server.publish(t, testCatalog(t, "1"))
+		if err := syncCatalog(server.URL+"/catalog.json", server.key); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := lookupOp("acme_namespace_isolation"); !ok {
+			t.Fatal("the operation of the catalog is not supported after the sync")
+		}
+
+		_, before := usage.snapshot()
+		countOperation("acme_namespace_isolation")
+		countOperation(namespaceIsolationCommand)
+		_, after := usage.snapshot()
+		if n := after["acme_namespace_isolation"]; n != 0 {
+			t.Errorf("the operation of the catalog was reported by its name %d time(s)", n)
+		}
+		if n := after[catalogOpsName] - before[catalogOpsName]; n != 1 {
+			t.Errorf("%d operation(s) counted under %s, want 1", n, catalogOpsName)
+		}
+		if n := after[namespaceIsolationCommand] - before[namespaceIsolationCommand]; n != 1 {
+			t.Errorf("%d operation(s) counted under %s, want 1", n, namespaceIsolationCommand)
+		}
+	})
+}
+
+// syncedVersion is the version of the catalog in use
+func syncedVersion() string {
+	opsMu.RLock()
+	defer opsMu.RUnlock()
+	return catalogStatus.version
+}
+
+func TestCatalogReplayRefused(t *testing.T) {
+	withCatalogs(t, func() {
+		server := newCatalogServer(t)
+		defer server.Close()
+		url := server.URL + "/catalog.json"
+		older := testCatalog(t, "1.1")
+		server.publish(t, older)
+		if err := syncCatalog(url, server.key); err != nil {
+			t.Fatal(err)
+		}
+		newer := testCatalog(t, "1.2")
+		newer.Files["acme_namespace_isolation.tmpl"] += "\n"
+		server.publish(t, newer)
+		if err := syncCatalog(url, server.key); err != nil {
+			t.Fatal(err)
+		}
+
+		// the older catalog is still signed, served again it would roll the templates back
+		server.publish(t, older)
+		if err := syncCatalog(url, server.key); err == nil || !strings.Contains(err.Error(), "not newer") {
+			t.Errorf("a replay of version 1.1 returned %v, want it refused", err)
+		}
+		if v := syncedVersion(); v != "1.2" {
+			t.Errorf("version %s of the catalog is in use after the replay, want 1.2", v)
+		}
+
+		// once restarted the adapter refuses it too, but takes the catalog applied last again
+		opsMu.Lock()
+		catalogStatus.version = ""
+		templatesDir = builtinTemplatesDir
+		opsMu.Unlock()
+		if err := syncCatalog(url, server.key); err == nil {
+			t.Error("a replay of version 1.1 was applied after a restart")
+		}
+		server.publish(t, newer)
+		if err := syncCatalog(url, server.key); err != nil {
+			t.Errorf("the catalog applied last was refused after a restart: %v", err)
+		}
+
+		os.Setenv(catalogPinEnv, "1.1")
+		defer os.Unsetenv(catalogPinEnv)
+		if err := syncCatalog(url, server.key); err == nil {
+			t.Error("version 1.2 of the catalog was applied while it is pinned to 1.1")
+		}
+		server.publish(t, older)
+		if err := syncCatalog(url, server.key); err != nil {
+			t.Errorf("the pinned version was refused: %v", err)
+		}
+		if v := syncedVersion(); v != "1.1" {
+			t.Errorf("version %s of the catalog is in use, want the pinned 1.1", v)
+		}
+	})
+}
@@ -0,0 +1,377 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// the certificates of a deployment are issued by cert-manager when it is usable, with certificatesAuto,
+	// always by octactl with certificatesSelfSigned, and certificatesCertManager warns when it falls back
+	certificatesAuto        = "auto"
+	certificatesCertManager = "cert-manager"
+	certificatesSelfSigned  = "self-signed"
+
+	certManagerVersion = "cert-manager.io/v1"
+	// certManagerInjectAnnotation has the cainjector of cert-manager fill the caBundle of a webhook
+	// configuration with the CA of a Certificate
+	certManagerInjectAnnotation = "cert-manager.io/inject-ca-from"
+	// certManagerNameAnnotation is set by cert-manager on the Secrets it issues
+	certManagerNameAnnotation = "cert-manager.io/certificate-name"
+	// cainjectorSelector matches the cainjector of the manifests and the Helm chart of cert-manager
+	cainjectorSelector = "app.kubernetes.io/component=cainjector"
+
+	certificateSelfSignedIssuer = "octarine-selfsigned"
+	certificateCAName           = "octarine-ca"
+
+	certificateTimeout = 2 * time.Minute
+)
+
+var (
+	certificateResource = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+)
+
+func validateCertificates(certificates string) error {
+	switch certificates {
+	case "", certificatesAuto, certificatesCertManager, certificatesSelfSigned:
+		return nil
+	}
+	return fmt.Errorf("error: unknown certificates %q, known ones are %s, %s and %s", certificates, certificatesAuto, certificatesCertManager, certificatesSelfSigned)
+}
+
+// certManagerUnusable tells why cert-manager can't issue the certificates of a deployment, empty when it can:
+// its API must be served and its cainjector running to fill the caBundle of the webhooks
+func (oClient *Client) certManagerUnusable(ctx context.Context) string {
+	if _, err := oClient.clientset(ctx).Discovery().ServerResourcesForGroupVersion(certManagerVersion); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf("cert-manager is not installed, %s isn't served", certManagerVersion)
+		}
+		return fmt.Sprintf("unable to discover %s: %v", certManagerVersion, err)
+	}
+	list, err := oClient.clientset(ctx).AppsV1().Deployments(metav1.NamespaceAll).List(metav1.ListOptions{LabelSelector: cainjectorSelector})
+	if err != nil {
+		return fmt.Sprintf("unable to look for the cainjector of cert-manager: %v", err)
+	}
+	for _, depl := range list.Items {
+		if depl.Status.AvailableReplicas > 0 {
+			return ""
+		}
+	}
+	return "the cainjector of cert-manager isn't running, the webhooks would get no CA bundle"
+}
+
+// useCertManager decides whether cert-manager issues the certificates of a new deployment. Deployments
+// fall back to the self-signed certificates of octactl when cert-manager can't, with a warning when it was
+// asked for.
+func (oClient *Client) useCertManager(ctx context.Context, d *deployment, certificates string) bool {
+	if certificates == certificatesSelfSigned {
+		return false
+	}
+	reason := oClient.certManagerUnusable(ctx)
+	if reason == "" {
+		logrus.Infof("cert-manager issues the certificates of deployment %s", d.name)
+		return true
+	}
+	if certificates == certificatesCertManager {
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: operationIDFrom(ctx),
+			EventType:   meshes.EventType_WARN,
+			Summary:     fmt.Sprintf("Deployment %s uses self-signed certificates", d.name),
+			Details:     reason + ", octactl generates the certificates instead.",
+		}
+		return false
+	}
+	logrus.Infof("Deployment %s uses self-signed certificates: %s", d.name, reason)
+	return false
+}
+
+// certManagerManifest replaces the TLS Secrets octactl generates in a dataplane manifest with Certificates
+// for the names of the Services in front of the pods mounting them. They are issued by a CA of the
+// deployment, itself issued by a self-signed Issuer, and the cainjector injects the CA into the webhook
+// configurations calling these Services. Secrets mounted by pods no Service selects are kept.
+func (d *deployment) certManagerManifest(manifest string) (string, error) {
+	objects, err := parseManifestObjects(manifest)
+	if err != nil {
+		return "", err
+	}
+	secrets := map[string]bool{}
+	for _, obj := range objects {
+		if obj.GetKind() != "Secret" {
+			continue
+		}
+		secretType, _, _ := unstructured.NestedString(obj.Object, "type")
+		if _, found, _ := unstructured.NestedString(obj.Object, "data", "tls.crt"); secretType == "kubernetes.io/tls" || found {
+			secrets[obj.GetName()] = true
+		}
+	}
+	if len(secrets) == 0 {
+		return manifest, nil
+	}
+
+	// the labels of the pods mounting each Secret
+	mounts := map[string][]labels.Set{}
+	for _, obj := range objects {
+		path := podSpecPath(obj.GetKind())
+		if path == nil {
+			continue
+		}
+		metadata := append(append([]string{}, path[:len(path)-1]...), "metadata", "labels")
+		podLabels, _, _ := unstructured.NestedStringMap(obj.Object, metadata...)
+		volumes, _, err := nestedObjects(obj.Object, append(path, "volumes")...)
+		if err != nil {
+			return "", err
+		}
+		for _, volume := range volumes {
+			if name, _, _ := unstructured.NestedString(volume, "secret", "secretName"); secrets[name] {
+				mounts[name] = append(mounts[name], labels.Set(podLabels))
+			}
+		}
+	}
+
+	// the Services in front of the pods of each Secret, and the Certificate serving each Service
+	services := map[string][]string{}
+	certificateOf := map[string]string{}
+	for _, obj := range objects {
+		if obj.GetKind() != "Service" {
+			continue
+		}
+		selector, found, _ := unstructured.NestedStringMap(obj.Object, "spec", "selector")
+		if !found || len(selector) == 0 {
+			continue
+		}
+		for _, secret := range sortedKeys(secrets) {
+			for _, podLabels := range mounts[secret] {
+				if labels.SelectorFromSet(selector).Matches(podLabels) {
+					services[secret] = append(services[secret], obj.GetName())
+					certificateOf[obj.GetName()] = secret
+					break
+				}
+			}
+		}
+	}
+	if len(services) == 0 {
+		logrus.Warnf("No Service of deployment %s serves the pods mounting its TLS Secrets, octactl generates their certificates", d.name)
+		return manifest, nil
+	}
+
+	result := d.certificateAuthority()
+	for _, obj := range objects {
+		switch obj.GetKind() {
+		case "Secret":
+			if names := services[obj.GetName()]; len(names) > 0 {
+				obj = d.servingCertificate(obj.GetName(), names)
+			} else if secrets[obj.GetName()] {
+				logrus.Warnf("No Service serves the pods mounting Secret %s of deployment %s, octactl generates its certificate", obj.GetName(), d.name)
+			}
+		case "MutatingWebhookConfiguration", "ValidatingWebhookConfiguration":
+			if err := d.injectCA(obj, certificateOf); err != nil {
+				return "", err
+			}
+		}
+		result = append(result, obj)
+	}
+
+	docs := make([]string, 0, len(result))
+	for _, obj := range result {
+		out, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to serialize manifest document")
+		}
+		docs = append(docs, string(out))
+	}
+	return strings.Join(docs, "\n---\n"), nil
+}
+
+// certificateAuthority is the CA of a deployment: a self-signed Issuer, the CA Certificate it issues and the
+// Issuer signing the serving certificates with it
+func (d *deployment) certificateAuthority() []*unstructured.Unstructured {
+	selfSigned := certManagerObject("Issuer", resourceName(certificateSelfSignedIssuer))
+	selfSigned.Object["spec"] = map[string]interface{}{"selfSigned": map[string]interface{}{}}
+
+	ca := certManagerObject("Certificate", resourceName(certificateCAName))
+	ca.Object["spec"] = map[string]interface{}{
+		"isCA":           true,
+		"commonName":     fmt.Sprintf("Octarine %s CA", d.name),
+		"secretName":     resourceName(certificateCAName),
+		"privateKey":     map[string]interface{}{"algorithm": "ECDSA", "size": int64(256)},
+		"issuerRef":      map[string]interface{}{"name": resourceName(certificateSelfSignedIssuer), "kind": "Issuer"},
+		"secretTemplate": d.issuedSecretTemplate(),
+	}
+
+	issuer := certManagerObject("Issuer", resourceName(certificateCAName))
+	issuer.Object["spec"] = map[string]interface{}{"ca": map[string]interface{}{"secretName": resourceName(certificateCAName)}}
+	return []*unstructured.Unstructured{selfSigned, ca, issuer}
+}
+
+// servingCertificate is issued for the names of the Services in the namespace of the deployment into the
+// Secret octactl would have generated
+func (d *deployment) servingCertificate(secret string, services []string) *unstructured.Unstructured {
+	dnsNames := []interface{}{}
+	for _, svc := range services {
+		dnsNames = append(dnsNames, svc, svc+"."+d.namespace, svc+"."+d.namespace+".svc", svc+"."+d.namespace+".svc.cluster.local")
+	}
+	cert := certManagerObject("Certificate", secret)
+	cert.Object["spec"] = map[string]interface{}{
+		"commonName":     services[0] + "." + d.namespace + ".svc",
+		"dnsNames":       dnsNames,
+		"secretName":     secret,
+		"usages":         []interface{}{"server auth", "digital signature", "key encipherment"},
+		"privateKey":     map[string]interface{}{"algorithm": "ECDSA", "size": int64(256), "rotationPolicy": "Always"},
+		"issuerRef":      map[string]interface{}{"name": resourceName(certificateCAName), "kind": "Issuer"},
+		"secretTemplate": d.issuedSecretTemplate(),
+	}
+	return cert
+}
+
+// injectCA annotates a webhook configuration calling a Service served by a Certificate so the cainjector
+// fills its caBundle, dropping the one of the self-signed certificate
+func (d *deployment) injectCA(obj *unstructured.Unstructured, certificateOf map[string]string) error {
+	webhooks, found, err := nestedObjects(obj.Object, "webhooks")
+	if !found || err != nil {
+		return err
+	}
+	certificate := ""
+	for _, webhook := range webhooks {
+		svc, found, _ := unstructured.NestedString(webhook, "clientConfig", "service", "name")
+		if !found || certificateOf[svc] == "" {
+			continue
+		}
+		if certificate != "" && certificate != certificateOf[svc] {
+			return fmt.Errorf("error: webhook configuration %s calls services served by certificates %s and %s, the cainjector can only inject one", obj.GetName(), certificate, certificateOf[svc])
+		}
+		certificate = certificateOf[svc]
+		unstructured.RemoveNestedField(webhook, "clientConfig", "caBundle")
+	}
+	if certificate == "" {
+		return nil
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[certManagerInjectAnnotation] = d.namespace + "/" + certificate
+	obj.SetAnnotations(annotations)
+	return setNestedObjects(obj.Object, webhooks, "webhooks")
+}
+
+// issuedSecretTemplate labels the Secrets cert-manager issues for a deployment, so they can be found when it
+// is uninstalled
+func (d *deployment) issuedSecretTemplate() map[string]interface{} {
+	secretLabels := map[string]interface{}{}
+	for k, v := range d.managedLabels() {
+		secretLabels[k] = v
+	}
+	return map[string]interface{}{"labels": secretLabels}
+}
+
+func certManagerObject(kind, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetAPIVersion(certManagerVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	return obj
+}
+
+// waitForCertificates waits for cert-manager to issue the Certificates of a deployment, the pods mounting
+// their Secrets can't start before
+func (oClient *Client) waitForCertificates(ctx context.Context, d *deployment) error {
+	selector := labels.SelectorFromSet(d.managedLabels()).String()
+	deadline := time.Now().Add(certificateTimeout)
+	for {
+		list, err := oClient.dynamicClient(ctx).Resource(certificateResource).Namespace(d.namespace).List(metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			err = errors.Wrapf(err, "unable to list the certificates of deployment %s", d.name)
+			logrus.Error(err)
+			return err
+		}
+		pending := map[string]string{}
+		for _, cert := range list.Items {
+			if ready, message := certificateReady(&cert); !ready {
+				pending[cert.GetName()] = message
+			}
+		}
+		if len(pending) == 0 {
+			progressed(ctx)
+			return nil
+		}
+		names := make([]string, 0, len(pending))
+		for name := range pending {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if time.Now().After(deadline) {
+			reasons := make([]string, 0, len(names))
+			for _, name := range names {
+				reasons = append(reasons, fmt.Sprintf("%s: %s", name, pending[name]))
+			}
+			return fmt.Errorf("error: cert-manager didn't issue the certificates of deployment %s within %s, %s", d.name, certificateTimeout, strings.Join(reasons, "; "))
+		}
+		workingOn(ctx, "cert-manager to issue certificates %s in namespace %s", strings.Join(names, ", "), d.namespace)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(rolloutPollInterval):
+		}
+	}
+}
+
+// certificateReady reads the Ready condition of a Certificate, with its message when it isn't
+func certificateReady(cert *unstructured.Unstructured) (bool, string) {
+	conditions, _, _ := nestedObjects(cert.Object, "status", "conditions")
+	for _, condition := range conditions {
+		if t, _, _ := unstructured.NestedString(condition, "type"); t != "Ready" {
+			continue
+		}
+		status, _, _ := unstructured.NestedString(condition, "status")
+		message, _, _ := unstructured.NestedString(condition, "message")
+		return status == "True", message
+	}
+	return false, "not issued yet"
+}
+
+// deleteIssuedSecrets removes the Secrets cert-manager issued for a deployment, which outlive their
+// Certificates
+func (oClient *Client) deleteIssuedSecrets(ctx context.Context, d *deployment) error {
+	selector := labels.SelectorFromSet(d.managedLabels()).String()
+	list, err := oClient.clientset(ctx).CoreV1().Secrets(d.namespace).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return errors.Wrapf(err, "unable to list the secrets of deployment %s", d.name)
+	}
+	for _, secret := range list.Items {
+		if _, ok := secret.Annotations[certManagerNameAnnotation]; !ok {
+			continue
+		}
+		err := oClient.clientset(ctx).CoreV1().Secrets(d.namespace).Delete(secret.Name, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "unable to delete secret %s/%s", d.namespace, secret.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	severityHigh   = "high"
+	severityMedium = "medium"
+	severityLow    = "low"
+
+	cisBenchmark = "https://www.cisecurity.org/benchmark/kubernetes"
+
+	// kubeletReadOnlyPort is the unauthenticated port of the kubelet, open unless --read-only-port=0
+	kubeletReadOnlyPort = 10255
+	kubeletDialTimeout  = 2 * time.Second
+)
+
+// kubeletConfigz is the part of the configuration a kubelet serves at /configz the CIS checks look at
+type kubeletConfigz struct {
+	KubeletConfig struct {
+		ReadOnlyPort   *int32 `json:"readOnlyPort"`
+		Authentication struct {
+			Anonymous struct {
+				Enabled *bool `json:"enabled"`
+			} `json:"anonymous"`
+		} `json:"authentication"`
+		Authorization struct {
+			Mode string `json:"mode"`
+		} `json:"authorization"`
+	} `json:"kubeletconfig"`
+}
+
+// cisChecks runs the recommendations of the CIS Kubernetes Benchmark which matter to the security of the
+// mesh and can be checked through the API: anonymous requests to the API server and the kubelets, the
+// read-only port of the kubelets and the default service accounts of the injected namespaces. They are
+// advisory, the mesh works without them, so they don't gate enforcement.
+func (oClient *Client) cisChecks(ctx context.Context, injected map[string]bool) ([]vetCheck, error) {
+	checks := []vetCheck{oClient.checkAnonymousAuth()}
+	kubelets, err := oClient.checkKubelets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	checks = append(checks, kubelets...)
+	for _, ns := range sortedKeys(injected) {
+		check, err := oClient.checkDefaultServiceAccount(ctx, ns)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// checkAnonymousAuth sends the API server a request without credentials, it must be rejected as
+// unauthenticated rather than run as system:anonymous
+func (oClient *Client) checkAnonymousAuth() vetCheck {
+	check := vetCheck{
+		name:       "cis 1.2.1 api server anonymous auth",
+		severity:   severityMedium,
+		references: []string{"CIS Kubernetes Benchmark 1.2.1", cisBenchmark, "https://kubernetes.io/docs/reference/access-authn-authz/authentication/#anonymous-requests"},
+		advisory:   true,
+	}
+	config := oClient.restConfig()
+	if config == nil {
+		check.skipped = "the adapter has no configuration of the API server to probe"
+		return check
+	}
+	cfg := rest.AnonymousClientConfig(config)
+	cfg.Timeout = accessProbeTimeout
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		check.skipped = fmt.Sprintf("unable to create the anonymous client: %v", err)
+		return check
+	}
+	_, err = clientset.CoreV1().Namespaces().List(metav1.ListOptions{Limit: 1})
+	switch {
+	case err == nil:
+		check.severity = severityHigh
+		check.failure = "anonymous requests are authenticated and may even list namespaces, set --anonymous-auth=false on the API server"
+	case apierrors.IsForbidden(err):
+		check.failure = "anonymous requests are authenticated as system:anonymous, set --anonymous-auth=false on the API server"
+	case apierrors.IsUnauthorized(err):
+	default:
+		check.skipped = fmt.Sprintf("unable to probe the API server: %v", err)
+	}
+	return check
+}
+
+// checkKubelets reads the configuration of every kubelet through the node proxy, a kubelet whose configuration
+// may not be read is checked by dialing its read-only port instead
+func (oClient *Client) checkKubelets(ctx context.Context) ([]vetCheck, error) {
+	anonymous := vetCheck{
+		name:       "cis 4.2.1 kubelet anonymous auth",
+		severity:   severityHigh,
+		references: []string{"CIS Kubernetes Benchmark 4.2.1", "CIS Kubernetes Benchmark 4.2.2", cisBenchmark, "https://kubernetes.io/docs/reference/access-authn-authz/kubelet-authn-authz/"},
+		advisory:   true,
+	}
+	readOnly := vetCheck{
+		name:       "cis 4.2.4 kubelet read-only port",
+		severity:   severityMedium,
+		references: []string{"CIS Kubernetes Benchmark 4.2.4", cisBenchmark, "https://kubernetes.io/docs/reference/command-line-tools-reference/kubelet/"},
+		advisory:   true,
+	}
+	nodes, err := oClient.clientset(ctx).CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the nodes")
+	}
+	anonymousNodes, openNodes, unread := []string{}, []string{}, []string{}
+	for _, node := range nodes.Items {
+		configz, err := oClient.kubeletConfigz(ctx, node.Name)
+		if err != nil {
+			unread = append(unread, node.Name)
+			if kubeletPortOpen(node) {
+				openNodes = append(openNodes, node.Name)
+			}
+			continue
+		}
+		kc := configz.KubeletConfig
+		if (kc.Authentication.Anonymous.Enabled != nil && *kc.Authentication.Anonymous.Enabled) || kc.Authorization.Mode == "AlwaysAllow" {
+			anonymousNodes = append(anonymousNodes, node.Name)
+		}
+		if kc.ReadOnlyPort != nil && *kc.ReadOnlyPort != 0 {
+			openNodes = append(openNodes, node.Name)
+		}
+	}
+	if len(anonymousNodes) > 0 {
+		anonymous.failure = fmt.Sprintf("%d kubelet(s) serve anonymous or unauthorized requests, set --anonymous-auth=false and --authorization-mode=Webhook: %s",
+			len(anonymousNodes), truncatedList(anonymousNodes))
+	} else if len(unread) == len(nodes.Items) && len(unread) > 0 {
+		anonymous.skipped = "the kubelet configuration of no node could be read through the nodes/proxy API"
+	}
+	if len(openNodes) > 0 {
+		readOnly.failure = fmt.Sprintf("%d kubelet(s) open the unauthenticated read-only port, set --read-only-port=0: %s", len(openNodes), truncatedList(openNodes))
+	}
+	return []vetCheck{anonymous, readOnly}, nil
+}
+
+func (oClient *Client) kubeletConfigz(ctx context.Context, node string) (*kubeletConfigz, error) {
+	raw, err := oClient.clientset(ctx).CoreV1().RESTClient().Get().
+		Resource("nodes").Name(node).SubResource("proxy").Suffix("configz").
+		Timeout(accessProbeTimeout).Context(ctx).Do().Raw()
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the kubelet configuration of node %s", node)
+	}
+	configz := &kubeletConfigz{}
+	if err := json.Unmarshal(raw, configz); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the kubelet configuration of node %s", node)
+	}
+	return configz, nil
+}
+
+// kubeletPortOpen dials the read-only port of a node, a port which can't be reached counts as closed
+func kubeletPortOpen(node corev1.Node) bool {
+	for _, addr := range node.Status.Addresses {
+		if addr.Type != corev1.NodeInternalIP {
+			continue
+		}
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(addr.Address, strconv.Itoa(kubeletReadOnlyPort)), kubeletDialTimeout)
+		if err == nil {
+			conn.Close()
+			return true
+		}
+	}
+	return false
+}
+
+// checkDefaultServiceAccount checks that the default service account of an injected namespace doesn't have
+// its token mounted, workloads should run as accounts of their own so their policies can tell them apart
+func (oClient *Client) checkDefaultServiceAccount(ctx context.Context, namespace string) (vetCheck, error) {
+	check := vetCheck{
+		name:       "cis 5.1.5 default service account in " + namespace,
+		namespace:  namespace,
+		severity:   severityLow,
+		references: []string{"CIS Kubernetes Benchmark 5.1.5", "CIS Kubernetes Benchmark 5.1.6", cisBenchmark, "https://kubernetes.io/docs/tasks/configure-pod-container/configure-service-account/"},
+		advisory:   true,
+	}
+	sa, err := oClient.clientset(ctx).CoreV1().ServiceAccounts(namespace).Get("default", metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return check, nil
+	}
+	if err != nil {
+		return check, errors.Wrapf(err, "unable to read the default service account of namespace %s", namespace)
+	}
+	pods, err := oClient.clientset(ctx).CoreV1().Pods(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return check, errors.Wrapf(err, "unable to list the pods of namespace %s", namespace)
+	}
+	saAutomount := sa.AutomountServiceAccountToken == nil || *sa.AutomountServiceAccountToken
+	using := []string{}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || (pod.Spec.ServiceAccountName != "" && pod.Spec.ServiceAccountName != "default") {
+			continue
+		}
+		automount := saAutomount
+		if pod.Spec.AutomountServiceAccountToken != nil {
+			automount = *pod.Spec.AutomountServiceAccountToken
+		}
+		if automount {
+			using = append(using, pod.Name)
+		}
+	}
+	switch {
+	case len(using) > 0:
+		check.severity = severityMedium
+		check.failure = fmt.Sprintf("%d running pod(s) mount the token of the default service account: %s", len(using), truncatedList(using))
+	case saAutomount:
+		check.failure = "the default service account mounts its token, set automountServiceAccountToken: false on it"
+	}
+	return check, nil
+}
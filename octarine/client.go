@@ -15,6 +15,11 @@
 package octarine
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/layer5io/meshery-octarine/meshes"
@@ -22,50 +27,165 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/flowcontrol"
 )
 
+// apiRequestTimeout bounds every request the clients of the adapter send to an API server
+const apiRequestTimeout = time.Minute
+
 // Client represents an Octarine client in Meshery
 type Client struct {
+	// clientsMu guards the config and the clients, a reconnect or a new registration of the cluster replaces them
+	clientsMu        sync.RWMutex
 	config           *rest.Config
 	k8sClientset     *kubernetes.Clientset
 	k8sDynamicClient dynamic.Interface
 	eventChan        chan *meshes.EventsResponse
+	events           *eventBroker
+	eventsOnce       sync.Once
+
+	octarineControlPlane string
+	octarineAccMgrPword  string
+	octarineCreatorPword string
+	octarineDeleterPword string
+
+	deploymentsMu sync.Mutex
+	deployments   map[string]*deployment
+
+	specMu      sync.Mutex
+	desiredSpec *MeshSpec
+	appliedSpec *MeshSpec
+
+	schedulesMu   sync.Mutex
+	schedules     map[string]*schedule
+	schedulerOnce sync.Once
+
+	inventoryMu  sync.Mutex
+	labelStateMu sync.Mutex
+
+	licenseWatchOnce sync.Once
+	licenseMu        sync.Mutex
+	// licenses are the licenses of the deployments by name, licenseWarned the warnings sent about them
+	licenses      map[string]*cachedLicense
+	licenseWarned map[string]map[string]bool
+
+	openAPIMu      sync.Mutex
+	openAPI        *openAPISchema
+	openAPIFetched time.Time
+
+	webhookProbeOnce sync.Once
+	webhooksMu       sync.Mutex
+	failingWebhooks  map[string]bool
+
+	vetMu sync.Mutex
+	// vetRuns are the last vet of each deployment, by name
+	vetRuns map[string]*vetRun
+
+	latencyMu sync.Mutex
+	// latencyProbes are the last latency probe of each injected namespace
+	latencyProbes map[string]*meshes.LatencyProbeReportResponse
+
+	connectivityOnce sync.Once
+	connectivityMu   sync.Mutex
+	// links are the connectivity of the default cluster, named "", and of the registered clusters
+	links map[string]*clusterLink
+
+	telemetryOnce sync.Once
+	telemetryMu   sync.Mutex
+	// telemetryNext is when the next usage report is sent, zero when telemetry is off
+	telemetryNext time.Time
+
+	// simulated is set against a control plane running no workloads, rollouts are then not waited on
+	simulated bool
+
+	// cluster is the name the client was registered under, empty for the default cluster
+	cluster    string
+	clustersMu sync.Mutex
+	clusters   map[string]*Client
+}
+
+// SimulateWorkloads has the operations apply their objects without waiting for the workloads to roll out, for a
+// control plane with no controllers or nodes like the one of the --dev-control-plane flag
+func (oClient *Client) SimulateWorkloads() {
+	oClient.simulated = true
+}
 
-	octarineAccount          string
-	octarineControlPlane     string
-	octarineAccMgrPword      string
-	octarineDomain           string
-	octarineCreatorPword     string
-	octarineDeleterPword     string
-	octarineReleaseVersion   string
-	octarineDataplaneNs      string
-	octarineReleaseUpdatedAt time.Time
+// clusterCredentials are how the adapter reaches the target cluster: a kubeconfig, a server with a
+// ServiceAccount token, or the in-cluster config when neither is given
+type clusterCredentials struct {
+	kubeconfig  []byte
+	contextName string
+	// token replaces the credentials of the kubeconfig context, or goes along with server
+	token  string
+	server string
+	caData []byte
 }
 
-func configClient(kubeconfig []byte, contextName string) (*rest.Config, error) {
-	if len(kubeconfig) > 0 {
-		ccfg, err := clientcmd.Load(kubeconfig)
+func configClient(creds clusterCredentials) (*rest.Config, error) {
+	if creds.server != "" {
+		return serviceAccountConfig(creds.server, creds.token, creds.caData)
+	}
+	if len(creds.kubeconfig) > 0 {
+		ccfg, err := clientcmd.Load(creds.kubeconfig)
 		if err != nil {
 			return nil, err
 		}
-		if contextName != "" {
-			ccfg.CurrentContext = contextName
+		if creds.contextName != "" {
+			ccfg.CurrentContext = creds.contextName
+		}
+		if err := prepareAuth(ccfg, creds.token); err != nil {
+			return nil, err
 		}
 
-		return clientcmd.NewDefaultClientConfig(*ccfg, &clientcmd.ConfigOverrides{}).ClientConfig()
+		config, err := clientcmd.NewDefaultClientConfig(*ccfg, &clientcmd.ConfigOverrides{}).ClientConfig()
+		if err != nil {
+			return nil, err
+		}
+		config.AuthConfigPersister = memoryPersister{}
+		return config, nil
+	}
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
 	}
-	return rest.InClusterConfig()
+	if creds.token != "" {
+		config.BearerToken = creds.token
+		config.BearerTokenFile = ""
+	}
+	return config, nil
 }
 
-func newClient(kubeconfig []byte, contextName string) (*Client, error) {
-	client := Client{}
-	config, err := configClient(kubeconfig, contextName)
+// serviceAccountConfig reaches an API server with a bearer token, as a ServiceAccount outside the cluster would
+func serviceAccountConfig(server, token string, caData []byte) (*rest.Config, error) {
+	if token == "" {
+		return nil, fmt.Errorf("error: a token is required along with the server %s", server)
+	}
+	config := &rest.Config{
+		Host:        server,
+		BearerToken: token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: caData,
+		},
+	}
+	return config, nil
+}
+
+func newClient(creds clusterCredentials) (*Client, error) {
+	config, err := configClient(creds)
 	if err != nil {
 		return nil, err
 	}
 	config.QPS = 100
 	config.Burst = 200
+	// the clients bound to the operations share the rate limit of the cluster
+	config.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(config.QPS, config.Burst)
+	config.Timeout = apiRequestTimeout
+	return clientForConfig(config)
+}
 
+// clientForConfig makes the Kubernetes clients of a config, afresh so their connections are new
+func clientForConfig(config *rest.Config) (*Client, error) {
+	client := Client{}
 	dynamicClient, err := dynamic.NewForConfig(config)
 	if err != nil {
 		return nil, err
@@ -81,3 +201,123 @@ func newClient(kubeconfig []byte, contextName string) (*Client, error) {
 
 	return &client, nil
 }
+
+// clients returns the config and the clients of the cluster
+func (oClient *Client) clients() (*rest.Config, *kubernetes.Clientset, dynamic.Interface) {
+	oClient.clientsMu.RLock()
+	defer oClient.clientsMu.RUnlock()
+	return oClient.config, oClient.k8sClientset, oClient.k8sDynamicClient
+}
+
+// restConfig is the config the clients of the cluster were made with
+func (oClient *Client) restConfig() *rest.Config {
+	config, _, _ := oClient.clients()
+	return config
+}
+
+// setClients replaces the config and the clients of the cluster with the ones of from
+func (oClient *Client) setClients(from *Client) {
+	config, clientset, dynamicClient := from.clients()
+	oClient.clientsMu.Lock()
+	defer oClient.clientsMu.Unlock()
+	oClient.config, oClient.k8sClientset, oClient.k8sDynamicClient = config, clientset, dynamicClient
+}
+
+type boundClientsKey struct{}
+
+// boundClients are the clients of an operation by the cluster they reach, made on first use
+type boundClients struct {
+	ctx     context.Context
+	mu      sync.Mutex
+	clients map[*Client]*boundClient
+}
+
+// boundClient are the clients of an operation made from the clients of a cluster, made again once the cluster is
+// reconnected to
+type boundClient struct {
+	from          *kubernetes.Clientset
+	clientset     *kubernetes.Clientset
+	dynamicClient dynamic.Interface
+}
+
+// bindClients has the clients the operation of ctx reaches the API servers with end their requests with ctx:
+// the client-go of the adapter takes no context, so the context goes along with each request of their
+// transport and a cancelled or timed out operation no longer waits on the API server
+func bindClients(ctx context.Context) context.Context {
+	return context.WithValue(ctx, boundClientsKey{}, &boundClients{ctx: ctx, clients: map[*Client]*boundClient{}})
+}
+
+// bound returns the clients of oClient whose requests end with the operation of ctx, the clients of oClient
+// when ctx carries no operation
+func (oClient *Client) bound(ctx context.Context) (*kubernetes.Clientset, dynamic.Interface) {
+	config, clientset, dynamicClient := oClient.clients()
+	b, ok := ctx.Value(boundClientsKey{}).(*boundClients)
+	if !ok || config == nil {
+		return clientset, dynamicClient
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if bc, ok := b.clients[oClient]; ok && bc.from == clientset {
+		return bc.clientset, bc.dynamicClient
+	}
+	config = rest.CopyConfig(config)
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &contextTransport{ctx: b.ctx, next: rt}
+	})
+	oc, err := clientForConfig(config)
+	if err != nil {
+		// the config made the clients of oClient already
+		return clientset, dynamicClient
+	}
+	b.clients[oClient] = &boundClient{from: clientset, clientset: oc.k8sClientset, dynamicClient: oc.k8sDynamicClient}
+	return oc.k8sClientset, oc.k8sDynamicClient
+}
+
+// clientset is the typed client of the cluster for the operation of ctx
+func (oClient *Client) clientset(ctx context.Context) *kubernetes.Clientset {
+	clientset, _ := oClient.bound(ctx)
+	return clientset
+}
+
+// dynamicClient is the dynamic client of the cluster for the operation of ctx
+func (oClient *Client) dynamicClient(ctx context.Context) dynamic.Interface {
+	_, dynamicClient := oClient.bound(ctx)
+	return dynamicClient
+}
+
+// contextTransport ends the requests of an operation with it, the deadline of each request is then the earlier
+// of apiRequestTimeout and the deadline of the operation
+type contextTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	go func() {
+		select {
+		case <-t.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// the body is read after the round trip, the request ends once it is closed
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// stalledAPIServer answers no request until the request is gone
+func stalledAPIServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+}
+
+func TestCancelledOperationEndsItsRequests(t *testing.T) {
+	server := stalledAPIServer()
+	defer server.Close()
+	oClient, err := clientForConfig(&rest.Config{Host: server.URL, Timeout: apiRequestTimeout})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = bindClients(ctx)
+	done := make(chan error, 1)
+	go func() {
+		_, err := oClient.clientset(ctx).CoreV1().Namespaces().Get("default", metav1.GetOptions{})
+		done <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("the request of a cancelled operation succeeded")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the request of a cancelled operation is still waiting on the API server")
+	}
+	if oClient.clientset(ctx) != oClient.clientset(ctx) {
+		t.Error("an operation made its clients again")
+	}
+}
+
+func TestClientsTimeOutRequests(t *testing.T) {
+	oClient, err := newClient(clusterCredentials{server: "https://127.0.0.1:6443", token: "token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oClient.config.Timeout != apiRequestTimeout {
+		t.Errorf("the requests of the clients time out after %s, want %s", oClient.config.Timeout, apiRequestTimeout)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// registerCluster keeps the client of a named cluster for the operations targeting it, registering a name
+// again replaces its credentials but keeps the deployments the adapter made there
+func (oClient *Client) registerCluster(name string, oc *Client) {
+	oClient.clustersMu.Lock()
+	defer oClient.clustersMu.Unlock()
+	oClient.startEvents()
+	if oClient.clusters == nil {
+		oClient.clusters = map[string]*Client{}
+	}
+	if registered, ok := oClient.clusters[name]; ok {
+		registered.setClients(oc)
+		registered.eventChan = oClient.eventChan
+		registered.events = oClient.events
+		logrus.Infof("Updated the credentials of cluster %s", name)
+		return
+	}
+	oc.cluster = name
+	oc.eventChan = oClient.eventChan
+	oc.events = oClient.events
+	oClient.clusters[name] = oc
+	logrus.Infof("Registered cluster %s", name)
+}
+
+// clusterClient returns the client of a registered cluster
+func (oClient *Client) clusterClient(name string) (*Client, error) {
+	oClient.clustersMu.Lock()
+	defer oClient.clustersMu.Unlock()
+	if oc, ok := oClient.clusters[name]; ok {
+		return oc, nil
+	}
+	if len(oClient.clusters) == 0 {
+		return nil, fmt.Errorf("error: cluster %s has not been registered, create a mesh instance naming it first", name)
+	}
+	names := make([]string, 0, len(oClient.clusters))
+	for n := range oClient.clusters {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("error: cluster %s has not been registered, the registered clusters are %s", name, strings.Join(names, ", "))
+}
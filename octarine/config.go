@@ -0,0 +1,51 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"os"
+	"strings"
+)
+
+// clusterScopedKinds are the kinds in Octarine's manifests which would collide between two installs in one cluster
+var clusterScopedKinds = map[string]bool{
+	"clusterrole":                    true,
+	"clusterrolebinding":             true,
+	"mutatingwebhookconfiguration":   true,
+	"validatingwebhookconfiguration": true,
+	"podsecuritypolicy":              true,
+	"priorityclass":                  true,
+}
+
+// dataplaneNamespace is the namespace used when an install request doesn't name one
+func dataplaneNamespace() string {
+	if ns := os.Getenv("OCTARINE_DATAPLANE_NAMESPACE"); ns != "" {
+		return ns
+	}
+	return defaultDataplaneNs
+}
+
+// resourceName decorates the name of an adapter-created resource with the configured prefix and suffix
+func resourceName(name string) string {
+	prefix := os.Getenv("OCTARINE_RESOURCE_PREFIX")
+	suffix := os.Getenv("OCTARINE_RESOURCE_SUFFIX")
+	if prefix != "" && !strings.HasPrefix(name, prefix) {
+		name = prefix + name
+	}
+	if suffix != "" && !strings.HasSuffix(name, suffix) {
+		name += suffix
+	}
+	return name
+}
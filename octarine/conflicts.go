@@ -0,0 +1,150 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// fieldManager is who the API server records as the manager of the fields the adapter sets
+	fieldManager = "meshery-octarine"
+
+	conflictPolicyKey contextKey = operationIDKey + 6
+
+	conflictWarn  = "warn"
+	conflictSkip  = "skip"
+	conflictForce = "force"
+)
+
+var conflictPolicies = map[string]bool{conflictWarn: true, conflictSkip: true, conflictForce: true}
+
+// ownManagers are the field managers of the adapter, including the one the API server derived from its user
+// agent before it named itself
+var ownManagers = map[string]bool{
+	fieldManager: true,
+	strings.SplitN(rest.DefaultKubernetesUserAgent(), "/", 2)[0]: true,
+}
+
+func withConflictPolicy(ctx context.Context, policy string) context.Context {
+	return context.WithValue(ctx, conflictPolicyKey, policy)
+}
+
+func conflictPolicyFrom(ctx context.Context) string {
+	if policy, _ := ctx.Value(conflictPolicyKey).(string); policy != "" {
+		return policy
+	}
+	return conflictWarn
+}
+
+// fieldConflict is a field an update changes which another field manager owns
+type fieldConflict struct {
+	fieldChange
+	manager string
+}
+
+// fieldConflicts finds the changes to the fields of a live object the other field managers own, as its
+// managedFields record them
+func fieldConflicts(live *unstructured.Unstructured, changes []fieldChange) []fieldConflict {
+	entries, _, _ := nestedObjects(live.Object, "metadata", "managedFields")
+	conflicts := []fieldConflict{}
+	for _, c := range changes {
+		for _, entry := range entries {
+			manager, _, _ := unstructured.NestedString(entry, "manager")
+			if ownManagers[manager] {
+				continue
+			}
+			if subresource, _, _ := unstructured.NestedString(entry, "subresource"); subresource != "" {
+				continue
+			}
+			fields, ok := entry["fieldsV1"].(map[string]interface{})
+			if !ok {
+				// the API servers before 1.15 name them fields
+				fields, _ = entry["fields"].(map[string]interface{})
+			}
+			if managesField(fields, c.keys) {
+				conflicts = append(conflicts, fieldConflict{fieldChange: c, manager: manager})
+				break
+			}
+		}
+	}
+	return conflicts
+}
+
+// managesField tells whether a field set of managedFields holds a field, or fields within it when the path
+// goes into a list
+func managesField(fields map[string]interface{}, keys []string) bool {
+	if len(fields) == 0 || len(keys) == 0 {
+		return false
+	}
+	node := fields
+	for _, key := range keys {
+		child, ok := node["f:"+key].(map[string]interface{})
+		if !ok {
+			return false
+		}
+		node = child
+	}
+	return true
+}
+
+// resolveConflicts applies the conflict policy of the operation to the fields of an update other field
+// managers own: it warns and lets the update through, tells whether to skip the resource, or lets it through
+func (oClient *Client) resolveConflicts(ctx context.Context, live *unstructured.Unstructured, changes []fieldChange) (skip bool) {
+	conflicts := fieldConflicts(live, changes)
+	if len(conflicts) == 0 {
+		return false
+	}
+	policy := conflictPolicyFrom(ctx)
+	target := changeTarget{kind: live.GetKind(), namespace: live.GetNamespace(), name: live.GetName()}.String()
+	managers := map[string]bool{}
+	lines := []string{}
+	for i, c := range conflicts {
+		managers[c.manager] = true
+		if i == maxDiffLines {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(conflicts)-maxDiffLines))
+		} else if i < maxDiffLines {
+			lines = append(lines, fmt.Sprintf("%s (%s)", c.String(), c.manager))
+		}
+	}
+	owners := strings.Join(sortedKeys(managers), ", ")
+	event := &meshes.EventsResponse{
+		OperationId: operationIDFrom(ctx),
+		EventType:   meshes.EventType_WARN,
+		Details:     redactSecrets(ctx, strings.Join(lines, "\n")),
+	}
+	switch policy {
+	case conflictSkip:
+		event.Summary = fmt.Sprintf("Skipped %s, %d of the fields it changes are managed by %s", target, len(conflicts), owners)
+	case conflictForce:
+		event.EventType = meshes.EventType_INFO
+		event.Summary = fmt.Sprintf("Took over %d field(s) of %s from %s", len(conflicts), target, owners)
+	default:
+		event.Summary = fmt.Sprintf("Overwriting %d field(s) of %s managed by %s", len(conflicts), target, owners)
+		event.Details += fmt.Sprintf("\nSet conflict_policy to %s to leave such resources alone, or to %s to take them over without a warning.", conflictSkip, conflictForce)
+	}
+	logrus.Infof("%s (conflict policy %s)", event.Summary, policy)
+	if oClient.eventChan != nil {
+		oClient.eventChan <- event
+	}
+	return policy == conflictSkip
+}
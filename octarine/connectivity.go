@@ -0,0 +1,272 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	connectivityIntervalEnv = "OCTARINE_CONNECTIVITY_INTERVAL"
+	connectivityFailuresEnv = "OCTARINE_CONNECTIVITY_FAILURES"
+	reconnectMaxBackoffEnv  = "OCTARINE_RECONNECT_MAX_BACKOFF"
+
+	defaultConnectivityInterval = 30 * time.Second
+	defaultConnectivityFailures = 3
+	defaultReconnectMaxBackoff  = 5 * time.Minute
+
+	linkConnected    = "connected"
+	linkDegraded     = "degraded"
+	linkDisconnected = "disconnected"
+)
+
+// clusterLink is what the connectivity monitor knows of the link to a cluster
+type clusterLink struct {
+	// failures counts the checks failed in a row, the cluster is disconnected once they reach the limit
+	failures int
+	// since is when the first of these checks failed
+	since   time.Time
+	lastErr error
+	// attempts counts the reconnections which failed since the cluster is disconnected
+	attempts    int
+	nextAttempt time.Time
+}
+
+func (l *clusterLink) state(limit int) string {
+	switch {
+	case l == nil || l.failures == 0:
+		return linkConnected
+	case l.failures < limit:
+		return linkDegraded
+	}
+	return linkDisconnected
+}
+
+// startConnectivityMonitor starts checking that the API servers of the default and the registered clusters
+// can be reached, once, so Meshery learns when the link to a cluster is lost and when it is back
+func (oClient *Client) startConnectivityMonitor() {
+	oClient.connectivityOnce.Do(func() {
+		interval := durationFromEnv(connectivityIntervalEnv, defaultConnectivityInterval)
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for now := range ticker.C {
+				oClient.checkConnectivity(now, interval)
+			}
+		}()
+	})
+}
+
+// checkConnectivity reaches the API server of every cluster the adapter has credentials for, a disconnected
+// cluster is reconnected to with a backoff instead
+func (oClient *Client) checkConnectivity(now time.Time, interval time.Duration) {
+	targets := map[string]*Client{}
+	if oClient.restConfig() != nil {
+		targets[""] = oClient
+	}
+	oClient.clustersMu.Lock()
+	for name, oc := range oClient.clusters {
+		if oc.restConfig() != nil {
+			targets[name] = oc
+		}
+	}
+	oClient.clustersMu.Unlock()
+	names := map[string]bool{}
+	for name := range targets {
+		names[name] = true
+	}
+	limit := intFromEnv(connectivityFailuresEnv, defaultConnectivityFailures)
+	for _, name := range sortedKeys(names) {
+		oClient.connectivityMu.Lock()
+		link := oClient.links[name]
+		disconnected := link.state(limit) == linkDisconnected
+		waiting := disconnected && now.Before(link.nextAttempt)
+		oClient.connectivityMu.Unlock()
+		if waiting {
+			continue
+		}
+		err := pingCluster(targets[name].restConfig())
+		if err == nil && disconnected {
+			err = reconnect(targets[name])
+		}
+		oClient.reportConnectivity(name, err, now, limit, interval)
+	}
+}
+
+// pingCluster asks the API server for its version, with the timeout of the access probe
+func pingCluster(config *rest.Config) error {
+	cfg := rest.CopyConfig(config)
+	cfg.Timeout = accessProbeTimeout
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create the connectivity client")
+	}
+	if _, err := clientset.Discovery().ServerVersion(); err != nil {
+		return errors.Wrapf(err, "unable to reach the API server at %s", cfg.Host)
+	}
+	return nil
+}
+
+// reconnect replaces the clients of a cluster which answers again, the connections of the old ones may
+// have been left broken by the outage
+func reconnect(oc *Client) error {
+	fresh, err := clientForConfig(oc.restConfig())
+	if err != nil {
+		return errors.Wrapf(err, "unable to reconnect")
+	}
+	oc.setClients(fresh)
+	return nil
+}
+
+// reportConnectivity keeps the state of the link to a cluster and emits an event when it is disconnected, after
+// failing limit checks in a row, and when it is connected again; the checks in between stay quiet
+func (oClient *Client) reportConnectivity(name string, err error, now time.Time, limit int, interval time.Duration) {
+	oClient.connectivityMu.Lock()
+	if oClient.links == nil {
+		oClient.links = map[string]*clusterLink{}
+	}
+	link := oClient.links[name]
+	was := link.state(limit)
+	if err == nil {
+		delete(oClient.links, name)
+		oClient.connectivityMu.Unlock()
+		if was == linkDisconnected {
+			down := now.Sub(link.since).Round(time.Second)
+			logrus.Infof("Reconnected to %s after %s", clusterTitle(name), down)
+			oClient.eventChan <- &meshes.EventsResponse{
+				EventType: meshes.EventType_INFO,
+				Summary:   fmt.Sprintf("Connectivity to %s is restored", clusterTitle(name)),
+				Details:   fmt.Sprintf("It could not be reached for %s, since %s", down, link.since.UTC().Format(time.RFC3339)),
+				Source:    sourceConnectivity,
+			}
+		}
+		return
+	}
+	if link == nil {
+		link = &clusterLink{since: now}
+		oClient.links[name] = link
+	}
+	link.failures++
+	link.lastErr = err
+	if was == linkDisconnected {
+		link.attempts++
+	}
+	state := link.state(limit)
+	if state == linkDisconnected {
+		link.nextAttempt = now.Add(reconnectBackoff(interval, link.attempts))
+	}
+	attempts, next := link.attempts, link.nextAttempt
+	oClient.connectivityMu.Unlock()
+
+	switch {
+	case was == linkDisconnected:
+		logrus.Debugf("Reconnecting to %s failed %d time(s), trying again at %s: %v", clusterTitle(name), attempts, next.Format(time.RFC3339), err)
+	case state == linkDisconnected:
+		logrus.Errorf("Lost connectivity to %s: %v", clusterTitle(name), err)
+		oClient.eventChan <- &meshes.EventsResponse{
+			Severity: meshes.Severity_SEVERITY_CRITICAL,
+			Summary:  fmt.Sprintf("Lost connectivity to %s", clusterTitle(name)),
+			Details:  fmt.Sprintf("Operations on it are rejected until it can be reached again: %v", err),
+			Source:   sourceConnectivity,
+		}
+	default:
+		logrus.Warnf("%s failed a connectivity check: %v", clusterTitle(name), err)
+	}
+}
+
+// reconnectBackoff doubles the wait between reconnections, up to OCTARINE_RECONNECT_MAX_BACKOFF
+func reconnectBackoff(interval time.Duration, attempts int) time.Duration {
+	max := durationFromEnv(reconnectMaxBackoffEnv, defaultReconnectMaxBackoff)
+	backoff := interval
+	for i := 0; i < attempts && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		return max
+	}
+	return backoff
+}
+
+// markConnected forgets the failures of a cluster whose instance was just created again, the event of the
+// new instance tells it is connected
+func (oClient *Client) markConnected(name string) {
+	oClient.connectivityMu.Lock()
+	defer oClient.connectivityMu.Unlock()
+	delete(oClient.links, name)
+}
+
+// checkConnected rejects an operation on a disconnected cluster rather than letting it fail halfway
+func (oClient *Client) checkConnected(name string) error {
+	limit := intFromEnv(connectivityFailuresEnv, defaultConnectivityFailures)
+	oClient.connectivityMu.Lock()
+	defer oClient.connectivityMu.Unlock()
+	link := oClient.links[name]
+	if link.state(limit) != linkDisconnected {
+		return nil
+	}
+	return fmt.Errorf("error: %s is unreachable since %s, the operation was not started; the adapter reconnects on its own, try again once it is connected: %v",
+		clusterTitle(name), link.since.UTC().Format(time.RFC3339), link.lastErr)
+}
+
+// clusterConnections reports the link to every cluster the adapter has credentials for
+func (oClient *Client) clusterConnections() []*meshes.ClusterConnection {
+	names := map[string]bool{}
+	if oClient.restConfig() != nil {
+		names[""] = true
+	}
+	oClient.clustersMu.Lock()
+	for name := range oClient.clusters {
+		names[name] = true
+	}
+	oClient.clustersMu.Unlock()
+	limit := intFromEnv(connectivityFailuresEnv, defaultConnectivityFailures)
+	oClient.connectivityMu.Lock()
+	defer oClient.connectivityMu.Unlock()
+	connections := []*meshes.ClusterConnection{}
+	for _, name := range sortedKeys(names) {
+		link := oClient.links[name]
+		connection := &meshes.ClusterConnection{Cluster: name, State: link.state(limit)}
+		if link != nil {
+			connection.Since = link.since.UTC().Format(time.RFC3339)
+			connection.Error = link.lastErr.Error()
+			connection.ReconnectAttempts = int32(link.attempts)
+		}
+		connections = append(connections, connection)
+	}
+	return connections
+}
+
+// instanceFailedEvent tells Meshery a mesh instance couldn't be created, along with the error of the request
+func instanceFailedEvent(name string, err error) *meshes.EventsResponse {
+	return &meshes.EventsResponse{
+		EventType: meshes.EventType_ERROR,
+		Summary:   fmt.Sprintf("Unable to connect to %s", clusterTitle(name)),
+		Details:   err.Error(),
+	}
+}
+
+func clusterTitle(name string) string {
+	if name == "" {
+		return "the cluster"
+	}
+	return "cluster " + name
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// TestReconnectDuringOperation replaces the clients of a cluster while an operation uses them, go test -race
+// reports the clients read and replaced without a lock
+func TestReconnectDuringOperation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"kind":"Namespace","apiVersion":"v1","metadata":{"name":"default"}}`)
+	}))
+	defer server.Close()
+	oClient, err := clientForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = bindClients(ctx)
+	before := oClient.clientset(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			if err := reconnect(oClient); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		if _, err := oClient.clientset(ctx).CoreV1().Namespaces().Get("default", metav1.GetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		// requests out of an operation read the clients of the cluster
+		if _, err := oClient.clientset(context.Background()).CoreV1().Namespaces().Get("default", metav1.GetOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+	if oClient.clientset(ctx) == before {
+		t.Error("the operation kept the clients made before the cluster was reconnected to")
+	}
+}
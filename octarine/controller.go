@@ -0,0 +1,238 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	phaseReconciling = "Reconciling"
+	phaseReady       = "Ready"
+	phaseFailed      = "Failed"
+)
+
+var mesheryOctarineResource = schema.GroupVersionResource{
+	Group:    "meshery.layer5.io",
+	Version:  "v1alpha1",
+	Resource: "mesheryoctarines",
+}
+
+// Controller reconciles MesheryOctarine custom resources, each resource is treated as a MeshSpec
+type Controller struct {
+	base      *Client
+	namespace string
+	queue     workqueue.RateLimitingInterface
+	informer  cache.SharedIndexInformer
+
+	mu      sync.Mutex
+	clients map[string]*Client
+}
+
+// NewController creates a controller using the in-cluster config, an empty namespace watches all namespaces
+func NewController(namespace string) (*Controller, error) {
+	oc, err := newClient(clusterCredentials{})
+	if err != nil {
+		err = errors.Wrapf(err, "unable to create a new Octarine client")
+		logrus.Error(err)
+		return nil, err
+	}
+	// the informer watches, its client goes without the timeout of the requests of the operations
+	watchConfig := rest.CopyConfig(oc.config)
+	watchConfig.Timeout = 0
+	watchClient, err := dynamic.NewForConfig(watchConfig)
+	if err != nil {
+		err = errors.Wrapf(err, "unable to create the watch client")
+		logrus.Error(err)
+		return nil, err
+	}
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(watchClient, 10*time.Minute, namespace, nil)
+	c := &Controller{
+		base:      oc,
+		namespace: namespace,
+		queue:     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "mesheryoctarine"),
+		informer:  factory.ForResource(mesheryOctarineResource).Informer(),
+		clients:   map[string]*Client{},
+	}
+	c.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueue,
+		UpdateFunc: func(oldObj, obj interface{}) {
+			// status updates made by the controller itself don't bump the generation
+			oldRes, newRes := oldObj.(*unstructured.Unstructured), obj.(*unstructured.Unstructured)
+			if oldRes.GetGeneration() == newRes.GetGeneration() && oldRes.GetResourceVersion() != newRes.GetResourceVersion() {
+				return
+			}
+			c.enqueue(obj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+			if err != nil {
+				logrus.Error(err)
+				return
+			}
+			c.mu.Lock()
+			delete(c.clients, key)
+			c.mu.Unlock()
+		},
+	})
+	return c, nil
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// Run blocks until the stop channel is closed
+func (c *Controller) Run(stopCh <-chan struct{}) error {
+	defer c.queue.ShutDown()
+
+	go c.informer.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, c.informer.HasSynced) {
+		return errors.New("unable to sync the MesheryOctarine informer cache")
+	}
+	logrus.Infof("watching %s in namespace %q", mesheryOctarineResource.Resource, c.namespace)
+
+	go func() {
+		for c.processNextItem() {
+		}
+	}()
+	<-stopCh
+	return nil
+}
+
+func (c *Controller) processNextItem() bool {
+	item, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(item)
+
+	key, ok := item.(string)
+	if !ok {
+		logrus.Errorf("unable to reconcile %v: the queue holds a %T instead of a key", item, item)
+		c.queue.Forget(item)
+		return true
+	}
+	if err := c.reconcile(key); err != nil {
+		logrus.Errorf("unable to reconcile %s: %v", key, err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+// clientFor returns the client tracking the applied state of a single resource
+func (c *Controller) clientFor(key string) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	oc, ok := c.clients[key]
+	if !ok {
+		oc = &Client{eventChan: make(chan *meshes.EventsResponse, 100)}
+		oc.setClients(c.base)
+		c.clients[key] = oc
+		go logEvents(key, oc.eventChan)
+	}
+	return oc
+}
+
+// logEvents drains events of clients nobody streams from
+func logEvents(key string, events <-chan *meshes.EventsResponse) {
+	for event := range events {
+		if event.GetEventType() == meshes.EventType_ERROR {
+			logrus.Errorf("%s: %s: %s", key, event.GetSummary(), event.GetDetails())
+			continue
+		}
+		logrus.Infof("%s: %s", key, event.GetSummary())
+	}
+}
+
+func (c *Controller) reconcile(key string) error {
+	obj, exists, err := c.informer.GetIndexer().GetByKey(key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	u := obj.(*unstructured.Unstructured)
+	res, err := mesheryOctarineFromUnstructured(u)
+	if err != nil {
+		// the status of a resource whose fields are of the wrong type is set through its metadata alone
+		res = &MesheryOctarine{ObjectMeta: metav1.ObjectMeta{
+			Name:            u.GetName(),
+			Namespace:       u.GetNamespace(),
+			UID:             u.GetUID(),
+			Generation:      u.GetGeneration(),
+			ResourceVersion: u.GetResourceVersion(),
+		}}
+		return c.updateStatus(res, phaseFailed, err.Error())
+	}
+
+	if res.Status.ObservedGeneration == res.Generation && res.Status.Phase == phaseReady {
+		return nil
+	}
+
+	spec := res.Spec
+	if err := spec.complete(); err != nil {
+		return c.updateStatus(res, phaseFailed, err.Error())
+	}
+	if err := c.updateStatus(res, phaseReconciling, ""); err != nil {
+		return err
+	}
+	oc := c.clientFor(key)
+	opID := string(res.UID)
+	if err := oc.reconcileMeshSpec(withOperationID(context.Background(), opID), opID, &spec); err != nil {
+		if statusErr := c.updateStatus(res, phaseFailed, err.Error()); statusErr != nil {
+			logrus.Error(statusErr)
+		}
+		return err
+	}
+	return c.updateStatus(res, phaseReady, "")
+}
+
+func (c *Controller) updateStatus(res *MesheryOctarine, phase, message string) error {
+	res.Status = MesheryOctarineStatus{
+		Phase:              phase,
+		ObservedGeneration: res.Generation,
+		LastReconcileTime:  time.Now().UTC().Format(time.RFC3339),
+		Message:            message,
+	}
+	if err := newMesheryOctarineClient(c.base.dynamicClient(context.Background())).updateStatus(res); err != nil {
+		logrus.Error(err)
+		return err
+	}
+	logrus.Debugf("%s/%s is now %s", res.Namespace, res.Name, phase)
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+const (
+	bodyYAML   = "yaml"
+	bodyJSON   = "json"
+	bodyValues = "values"
+
+	// templateValuesFile holds the default values of the templates of a set, like the values.yaml of a chart
+	templateValuesFile = "values.yaml"
+)
+
+// bodyFormat tells how a custom body is written: a JSON manifest, one or more objects or arrays of objects,
+// a values overlay, a single mapping which isn't a Kubernetes object, or else a YAML manifest
+func bodyFormat(body string) string {
+	trimmed := strings.TrimSpace(body)
+	if strings.HasPrefix(trimmed, "[") {
+		return bodyJSON
+	}
+	doc := map[string]interface{}{}
+	if strings.HasPrefix(trimmed, "{") {
+		if err := json.Unmarshal([]byte(trimmed), &doc); err == nil && !isObject(doc) {
+			return bodyValues
+		}
+		return bodyJSON
+	}
+	if trimmed == "" || strings.Contains(body, "\n---") || strings.HasPrefix(trimmed, "---") {
+		return bodyYAML
+	}
+	if err := yaml.Unmarshal([]byte(body), &doc); err == nil && len(doc) > 0 && !isObject(doc) {
+		return bodyValues
+	}
+	return bodyYAML
+}
+
+// isObject tells whether a document is a Kubernetes object rather than values
+func isObject(doc map[string]interface{}) bool {
+	_, hasKind := doc["kind"]
+	_, hasVersion := doc["apiVersion"]
+	return hasKind && hasVersion
+}
+
+// normalizeManifest turns a JSON manifest into the YAML documents the manifests are handled as, YAML
+// manifests are returned as they are
+func normalizeManifest(body string) (string, error) {
+	switch bodyFormat(body) {
+	case bodyJSON:
+	case bodyValues:
+		return "", fmt.Errorf("error: the custom body is a values overlay, only the template operations take one")
+	default:
+		return body, nil
+	}
+	docs := []string{}
+	decoder := json.NewDecoder(strings.NewReader(body))
+	for {
+		var value interface{}
+		err := decoder.Decode(&value)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to parse the JSON manifest")
+		}
+		items := []interface{}{value}
+		if list, ok := value.([]interface{}); ok {
+			items = list
+		}
+		for _, item := range items {
+			if _, ok := item.(map[string]interface{}); !ok {
+				return "", fmt.Errorf("error: the JSON manifest holds a %s where an object is expected", jsonType(item))
+			}
+			doc, err := yaml.Marshal(item)
+			if err != nil {
+				return "", errors.Wrapf(err, "unable to convert the JSON manifest")
+			}
+			docs = append(docs, string(doc))
+		}
+	}
+	return strings.Join(docs, "---\n"), nil
+}
+
+// parseValues reads a values overlay, in YAML or JSON
+func parseValues(body string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if strings.TrimSpace(body) == "" {
+		return values, nil
+	}
+	if err := yaml.Unmarshal([]byte(body), &values); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the values")
+	}
+	return values, nil
+}
+
+// templateValues are the values a template of a set is rendered with: the values.yaml of the unversioned
+// set, overlaid by the one of the set and then by the values of the operation. Maps are merged, anything
+// else is replaced.
+func templateValues(set templateSet, overlay map[string]interface{}) (map[string]interface{}, error) {
+	files := []string{path.Join(set.root, templateValuesFile)}
+	if set.dir != set.root {
+		files = append(files, path.Join(set.dir, templateValuesFile))
+	}
+	values := map[string]interface{}{}
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read %s", file)
+		}
+		defaults, err := parseValues(string(bytes.TrimSpace(content)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse %s", file)
+		}
+		merged, ok := mergeValue(values, defaults).(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("error: the values of %s are not a map", file)
+		}
+		values = merged
+	}
+	if overlay != nil {
+		merged, ok := mergeValue(values, overlay).(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("error: the values of the custom body are not a map")
+		}
+		values = merged
+	}
+	return values, nil
+}
@@ -0,0 +1,260 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	demoLabel          = "octarine.io/demo"
+	demoContainerName  = "probe"
+	demoProductPage    = "productpage"
+	demoEgressTarget   = "example.com"
+	demoProbeAttempts  = 6
+	demoSettleTimeout  = time.Minute
+	demoSettleInterval = 10 * time.Second
+)
+
+// demoScenario is a step of the BookInfo demo, a policy along with the probe showing its effect
+type demoScenario struct {
+	title  string
+	policy string
+	// manifest is the Octarine policy, formatted with the namespace of BookInfo
+	manifest string
+	// outside runs the probe from the dataplane namespace, where traffic doesn't go through a sidecar
+	outside bool
+	script  string
+	// blocked reads the log of the probe, telling whether the traffic the policy targets was stopped
+	blocked func(log string) (bool, string)
+}
+
+var demoScenarios = map[string]demoScenario{
+	demoBlockRatingsCommand: {
+		title:  "Block reviews from calling ratings",
+		policy: "bookinfo-block-ratings",
+		manifest: `kind: AccessPolicy
+name: bookinfo-block-ratings
+namespace: %[1]s
+spec:
+  action: deny
+  from:
+  - workload: reviews
+  to:
+  - service: ratings
+    ports: [9080]
+`,
+		// reviews v1 never calls ratings, so a few pages are fetched to hit v2 or v3
+		script: `for i in $(seq $ATTEMPTS); do
+  page=$(wget -q -T 5 -O - "http://$PRODUCTPAGE/productpage?u=normal") || { echo "page failed"; continue; }
+  if echo "$page" | grep -q "Ratings service is currently unavailable"; then echo "page unavailable"
+  elif echo "$page" | grep -q "glyphicon-star"; then echo "page stars"
+  else echo "page none"; fi
+done
+`,
+		blocked: func(log string) (bool, string) {
+			counts := demoResults(log, "page")
+			pages := counts["stars"] + counts["unavailable"] + counts["none"]
+			return counts["stars"] == 0 && counts["unavailable"] > 0,
+				fmt.Sprintf("ratings shown on %d of %d pages, unavailable on %d, %d requests failed",
+					counts["stars"], pages, counts["unavailable"], counts["failed"])
+		},
+	},
+	demoMTLSCommand: {
+		title:  "Require mutual TLS",
+		policy: "bookinfo-mtls",
+		manifest: `kind: MTLSPolicy
+name: bookinfo-mtls
+namespace: %[1]s
+spec:
+  mode: strict
+`,
+		outside: true,
+		script: `if wget -q -T 5 -O /dev/null "http://$PRODUCTPAGE/productpage"; then echo "plaintext allowed"; else echo "plaintext refused"; fi
+`,
+		blocked: func(log string) (bool, string) {
+			refused := demoResults(log, "plaintext")["refused"] > 0
+			if refused {
+				return true, "plaintext requests from outside the mesh are refused"
+			}
+			return false, "plaintext requests from outside the mesh are served"
+		},
+	},
+	demoRestrictEgressCommand: {
+		title:  "Block egress to the internet",
+		policy: "bookinfo-restrict-egress",
+		manifest: `kind: EgressPolicy
+name: bookinfo-restrict-egress
+namespace: %[1]s
+spec:
+  action: deny
+  destinations:
+  - external: true
+`,
+		script: `if wget -q -T 5 -O /dev/null "http://$EGRESS/"; then echo "egress allowed"; else echo "egress failed"; fi
+if wget -q -T 5 -O /dev/null "http://$PRODUCTPAGE/productpage"; then echo "internal allowed"; else echo "internal failed"; fi
+`,
+		blocked: func(log string) (bool, string) {
+			egress, internal := demoResults(log, "egress"), demoResults(log, "internal")
+			detail := fmt.Sprintf("requests to %s %s, requests to productpage %s",
+				demoEgressTarget, demoVerdict(egress), demoVerdict(internal))
+			return egress["failed"] > 0 && internal["allowed"] > 0, detail
+		},
+	},
+}
+
+// demoResults counts the outcomes of the "<kind> <outcome>" lines of a probe log
+func demoResults(log, kind string) map[string]int {
+	counts := map[string]int{}
+	for _, line := range strings.Split(log, "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == kind {
+			counts[fields[1]]++
+		}
+	}
+	return counts
+}
+
+func demoVerdict(counts map[string]int) string {
+	if counts["allowed"] > 0 {
+		return "succeed"
+	}
+	return "fail"
+}
+
+// applyDemoPolicy creates or removes the policy of a scenario in the Octarine domain
+func (oClient *Client) applyDemoPolicy(ctx context.Context, d *deployment, s demoScenario, namespace string, remove bool) error {
+	if remove {
+		return oClient.deletePolicy(ctx, d, namespace, s.policy)
+	}
+	return oClient.asAccount(ctx, d, func() error {
+		cmd := exec.CommandContext(ctx, "octactl", "policy", "apply", d.domain, "--k8s-namespace", namespace, "-f", "-")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf(s.manifest, namespace))
+		if out, err := cmd.CombinedOutput(); err != nil {
+			logrus.Errorf("Command finished with error: %v: %s", err, out)
+			return errors.Wrapf(err, "unable to apply policy %s in namespace %s", s.policy, namespace)
+		}
+		return nil
+	})
+}
+
+// probeDemo runs the probe of a scenario against BookInfo in a namespace
+func (oClient *Client) probeDemo(ctx context.Context, d *deployment, s demoScenario, namespace string) (bool, string, error) {
+	podNamespace := namespace
+	if s.outside {
+		podNamespace = d.namespace
+	}
+	labels := d.managedLabels()
+	labels[demoLabel] = s.policy
+	pod := probePod(resourceName("octarine-demo-"+newOperationID()[:8]), podNamespace, demoContainerName, s.script, labels,
+		[]corev1.EnvVar{
+			{Name: "PRODUCTPAGE", Value: fmt.Sprintf("%s.%s.svc:9080", demoProductPage, namespace)},
+			{Name: "EGRESS", Value: demoEgressTarget},
+			{Name: "ATTEMPTS", Value: fmt.Sprint(demoProbeAttempts)},
+		})
+	log, err := oClient.runProbe(ctx, pod)
+	if err != nil {
+		return false, "", err
+	}
+	blocked, detail := s.blocked(log)
+	return blocked, detail, nil
+}
+
+// executeDemoScenario applies, or removes on delete, the policy of a demo step to BookInfo
+// and reports how the traffic it targets behaves before and after
+func (oClient *Client) executeDemoScenario(ctx context.Context, arReq *meshes.ApplyRuleRequest, s demoScenario) error {
+	if oClient.clientset(ctx) == nil {
+		return fmt.Errorf("error: mesh client has not been created")
+	}
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return err
+	}
+	namespace := arReq.GetNamespace()
+	if namespace == "" {
+		return fmt.Errorf("error: the namespace BookInfo runs in is required")
+	}
+	if err := oClient.requireInjected(ctx, d, namespace); err != nil {
+		return err
+	}
+	if _, err := oClient.clientset(ctx).AppsV1().Deployments(namespace).Get(demoProductPage, metav1.GetOptions{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("error: BookInfo is not installed in namespace %s, run %s first", namespace, installBookInfoCommand)
+		}
+		return errors.Wrapf(err, "unable to get deployment %s/%s", namespace, demoProductPage)
+	}
+
+	workingOn(ctx, "probing BookInfo before changing policy %s", s.policy)
+	_, before, err := oClient.probeDemo(ctx, d, s, namespace)
+	if err != nil {
+		return err
+	}
+	workingOn(ctx, "changing policy %s in namespace %s", s.policy, namespace)
+	if err := oClient.applyDemoPolicy(ctx, d, s, namespace, arReq.GetDeleteOp()); err != nil {
+		return err
+	}
+	progressed(ctx)
+
+	// the policy takes a few seconds to reach the sidecars, so the probe is repeated until it shows
+	want := !arReq.GetDeleteOp()
+	deadline := time.Now().Add(demoSettleTimeout)
+	var blocked bool
+	var after string
+	for {
+		workingOn(ctx, "probing BookInfo after changing policy %s", s.policy)
+		if blocked, after, err = oClient.probeDemo(ctx, d, s, namespace); err != nil {
+			return err
+		}
+		if blocked == want || time.Now().After(deadline) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(demoSettleInterval):
+		}
+	}
+
+	action := "applied"
+	if arReq.GetDeleteOp() {
+		action = "removed"
+	}
+	event := &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("%s: policy %s %s and verified", s.title, s.policy, action),
+		Details:     fmt.Sprintf("before: %s\nafter: %s", before, after),
+	}
+	if blocked != want {
+		event.EventType = meshes.EventType_WARN
+		event.Summary = fmt.Sprintf("%s: policy %s %s, but its effect did not show within %s", s.title, s.policy, action, demoSettleTimeout)
+	}
+	oClient.eventChan <- event
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const denialReportKey contextKey = operationIDKey + 3
+
+var (
+	// webhookDenialPattern matches the message of the API server when an admission webhook rejects a request
+	webhookDenialPattern = regexp.MustCompile(`(?s)admission webhook "([^"]+)" denied the request:\s*(.*)`)
+	// gatekeeperViolation is a line of a Gatekeeper denial, [constraint] message, older releases write
+	// [denied by constraint]
+	gatekeeperViolation = regexp.MustCompile(`^\[(?:denied by )?([^\]]+)\]\s*(.*)$`)
+	// kyvernoRule is a rule of a Kyverno denial, indented under the name of its policy
+	kyvernoRule = regexp.MustCompile(`^\s+([^:\s]+):\s*(.*)$`)
+)
+
+// admissionViolation is a constraint, or a policy rule, an object breaks
+type admissionViolation struct {
+	constraint string
+	message    string
+}
+
+// admissionDenial is an object an admission webhook of the cluster, such as OPA Gatekeeper or Kyverno, refused
+type admissionDenial struct {
+	object     string
+	webhook    string
+	violations []admissionViolation
+	// reason is the message of the webhook as it was sent
+	reason string
+	// manifest is the object as it was submitted, kept when the denial is reported
+	manifest string
+}
+
+func (d *admissionDenial) Error() string {
+	return fmt.Sprintf("error: admission webhook %s denied %s: %s", d.webhook, d.object, d.describe("; "))
+}
+
+// describe lists the violated constraints with their messages, or the reason of the webhook when it names none
+func (d *admissionDenial) describe(sep string) string {
+	if len(d.violations) == 0 {
+		return d.reason
+	}
+	parts := make([]string, 0, len(d.violations))
+	for _, v := range d.violations {
+		parts = append(parts, fmt.Sprintf("constraint %s: %s", v.constraint, v.message))
+	}
+	return strings.Join(parts, sep)
+}
+
+// asAdmissionDenial tells whether an error of the API server is an admission webhook denying an object
+func asAdmissionDenial(err error, obj *unstructured.Unstructured) (*admissionDenial, bool) {
+	if err == nil {
+		return nil, false
+	}
+	if d, ok := errors.Cause(err).(*admissionDenial); ok {
+		return d, true
+	}
+	msg := err.Error()
+	if status, ok := errors.Cause(err).(apierrors.APIStatus); ok {
+		msg = status.Status().Message
+	}
+	m := webhookDenialPattern.FindStringSubmatch(msg)
+	if m == nil {
+		return nil, false
+	}
+	d := &admissionDenial{
+		object:  fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName()),
+		webhook: m[1],
+		reason:  strings.TrimSpace(m[2]),
+	}
+	d.violations = parseViolations(d.reason)
+	return d, true
+}
+
+// parseViolations reads the constraints out of the reason of a denial: Gatekeeper writes a line per
+// violated constraint, Kyverno lists the broken rules under the name of their policy
+func parseViolations(reason string) []admissionViolation {
+	violations := []admissionViolation{}
+	policy := ""
+	for _, line := range strings.Split(reason, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if m := gatekeeperViolation.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			violations = append(violations, admissionViolation{constraint: m[1], message: m[2]})
+			continue
+		}
+		if !strings.HasPrefix(line, " ") && strings.HasSuffix(line, ":") {
+			policy = strings.TrimSuffix(line, ":")
+			continue
+		}
+		if m := kyvernoRule.FindStringSubmatch(line); m != nil && policy != "" {
+			violations = append(violations, admissionViolation{constraint: policy + "/" + m[1], message: strings.Trim(m[2], "'")})
+		}
+	}
+	return violations
+}
+
+// denialReport collects the objects denied while an operation runs with report_denials
+type denialReport struct {
+	mu      sync.Mutex
+	denials []*admissionDenial
+}
+
+func withDenialReport(ctx context.Context, report *denialReport) context.Context {
+	return context.WithValue(ctx, denialReportKey, report)
+}
+
+func denialReportFrom(ctx context.Context) *denialReport {
+	report, _ := ctx.Value(denialReportKey).(*denialReport)
+	return report
+}
+
+// denied handles an object an admission webhook refused: the operation fails with the denial, or when it
+// reports denials the object is skipped and the other objects are applied
+func (oClient *Client) denied(ctx context.Context, d *admissionDenial, obj *unstructured.Unstructured) error {
+	report := denialReportFrom(ctx)
+	if report == nil {
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: operationIDFrom(ctx),
+			EventType:   meshes.EventType_ERROR,
+			Summary:     fmt.Sprintf("Admission webhook %s denied %s", d.webhook, d.object),
+			Details:     d.describe("\n"),
+		}
+		return d
+	}
+	if doc, err := yaml.Marshal(obj.Object); err == nil {
+		d.manifest = redactSecrets(ctx, string(doc))
+	}
+	report.mu.Lock()
+	report.denials = append(report.denials, d)
+	report.mu.Unlock()
+	progressed(ctx)
+	return nil
+}
+
+// reportDenials sends the objects skipped by an operation along with their manifest, and returns a warning for
+// each of them
+func (oClient *Client) reportDenials(ctx context.Context, report *denialReport) []string {
+	report.mu.Lock()
+	defer report.mu.Unlock()
+	if len(report.denials) == 0 {
+		return nil
+	}
+	warnings := make([]string, 0, len(report.denials))
+	sections := make([]string, 0, len(report.denials))
+	for _, d := range report.denials {
+		warnings = append(warnings, strings.TrimPrefix(d.Error(), "error: "))
+		sections = append(sections, fmt.Sprintf("%s, denied by %s:\n%s\n%s", d.object, d.webhook, d.describe("\n"), d.manifest))
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: operationIDFrom(ctx),
+		EventType:   meshes.EventType_WARN,
+		Summary:     fmt.Sprintf("%d object(s) denied by the admission webhooks of the cluster were skipped", len(report.denials)),
+		Details:     strings.Join(sections, "---\n"),
+	}
+	return warnings
+}
@@ -0,0 +1,375 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const defaultDeploymentName = "default"
+
+// deployment is one Octarine domain whose dataplane runs in a namespace of the cluster
+type deployment struct {
+	name      string
+	account   string
+	domain    string
+	namespace string
+	version   string
+	updatedAt time.Time
+	// bootstrapped deployments use the account of the bootstrap operation, which deletes it
+	bootstrapped bool
+	// login are the credentials of an account with its own, like a trial, nil for the account manager
+	login *accountLogin
+	// certManager deployments have their certificates issued by cert-manager instead of octactl
+	certManager bool
+	// mirror is the registry the images of the dataplane are pulled from instead of their own
+	mirror string
+	// replicas is how many replicas each component of the dataplane runs, it is highly available with 2 or more
+	replicas int
+
+	// anchor owns the namespaced resources of the deployment once it was created
+	anchor          *metav1.OwnerReference
+	namespacedKinds map[schema.GroupKind]bool
+}
+
+// deploymentParams are read from the custom body of the operations working with deployments
+type deploymentParams struct {
+	// Deployment is the name of the deployment the operation targets
+	Deployment string `json:"deployment,omitempty"`
+	// Domain is the Octarine domain registered for a new deployment
+	Domain string `json:"domain,omitempty"`
+	// Version is the Octarine release installed by a new deployment
+	Version string `json:"version,omitempty"`
+	// Mode is the enforcement mode to switch to, observe or enforce
+	Mode string `json:"mode,omitempty"`
+	// Features are the runtime protection features to enable or disable
+	Features []string `json:"features,omitempty"`
+	// Backup is the name of the backup to restore
+	Backup string `json:"backup,omitempty"`
+	// Target is the host a breach simulation tries to reach
+	Target string `json:"target,omitempty"`
+	// Duration is how long each side of a latency probe is put under load
+	Duration string `json:"duration,omitempty"`
+	// Connections is the number of concurrent connections of a latency probe
+	Connections int `json:"connections,omitempty"`
+	// QPS is the request rate of a latency probe, as fast as possible when zero
+	QPS int `json:"qps,omitempty"`
+	// Engine is the policy engine protecting the components, gatekeeper or kyverno, detected when empty
+	Engine string `json:"engine,omitempty"`
+	// Exempt are the users still allowed to change the protected components, besides the adapter
+	Exempt []string `json:"exempt,omitempty"`
+	// SpireNamespace is the namespace of the SPIRE server and agents a deployment federates with
+	SpireNamespace string `json:"spire_namespace,omitempty"`
+	// TrustDomain is the trust domain of SPIRE, read from the configuration of its server when empty
+	TrustDomain string `json:"trust_domain,omitempty"`
+	// IdentityTemplate maps the identity of a workload to the SPIFFE ID SPIRE issues it
+	IdentityTemplate string `json:"identity_template,omitempty"`
+	// Certificates tells who issues the certificates of a new deployment: auto, cert-manager or self-signed
+	Certificates string `json:"certificates,omitempty"`
+	// Services are the Octarine services an exposure routes to, the dashboard and the API when empty
+	Services []string `json:"services,omitempty"`
+	// Host is the host name the services are exposed at
+	Host string `json:"host,omitempty"`
+	// Route is the kind of route exposing the services, ingress or gateway
+	Route string `json:"route,omitempty"`
+	// Class is the IngressClass or the GatewayClass of the route
+	Class string `json:"class,omitempty"`
+	// TLS is the source of the certificate of the host: self-signed, secret:<name>, issuer:<name> or
+	// cluster-issuer:<name>
+	TLS string `json:"tls,omitempty"`
+	// Mirror is the registry and path the images are copied to, and a new deployment pulls them from
+	Mirror string `json:"mirror,omitempty"`
+	// Tool is the command copying the images to the mirror, skopeo or crane
+	Tool string `json:"tool,omitempty"`
+	// Execute runs the copy commands in a Job instead of only listing them
+	Execute bool `json:"execute,omitempty"`
+	// PushSecret is the docker config Secret the copy Job pushes to the mirror with
+	PushSecret string `json:"push_secret,omitempty"`
+	// Selector is the label selector of the workloads an injection exclusion applies to
+	Selector string `json:"selector,omitempty"`
+	// Kinds are the kinds of the workloads an injection exclusion applies to, all of them when empty
+	Kinds []string `json:"kinds,omitempty"`
+	// CPURequest, CPULimit, MemoryRequest and MemoryLimit are the resources of the injected sidecars
+	CPURequest    string `json:"cpu_request,omitempty"`
+	CPULimit      string `json:"cpu_limit,omitempty"`
+	MemoryRequest string `json:"memory_request,omitempty"`
+	MemoryLimit   string `json:"memory_limit,omitempty"`
+	// Replicas is how many replicas each component of the dataplane of a new deployment runs, 2 or more make it
+	// highly available
+	Replicas int `json:"replicas,omitempty"`
+	// Batch is how the Jobs of injected namespaces are handled: report, shutdown or exclude
+	Batch string `json:"batch,omitempty"`
+	// Bundle is the YAML of the Octarine policies a policy import makes the policies of its namespace
+	Bundle string `json:"bundle,omitempty"`
+	// Confirm lets a policy import remove more policies than OCTARINE_POLICY_REMOVAL_LIMIT
+	Confirm bool `json:"confirm,omitempty"`
+	// Email is who a trial account is signed up for
+	Email string `json:"email,omitempty"`
+}
+
+// opParams are the keys of deploymentParams each operation reads from its custom body, a key it doesn't read
+// is a mistake of the caller. The operations missing take a manifest, a MeshSpec or the values of a template.
+var opParams = map[string][]string{
+	installOctarineCommand:    {"deployment", "domain", "version", "certificates", "mirror", "replicas"},
+	bootstrapCommand:          {"deployment", "domain"},
+	trialCommand:              {"deployment", "email", "version", "certificates", "mirror", "replicas"},
+	installBookInfoCommand:    {"deployment"},
+	cleanupSamplesCommand:     {},
+	runVet:                    {"deployment"},
+	reconcileMeshSpecCommand:  {},
+	proxyUpgradeCommand:       {"deployment"},
+	enforcementModeCommand:    {"deployment", "mode"},
+	runtimeProtectionCommand:  {"deployment", "features"},
+	backupCommand:             {"deployment"},
+	backupRestoreCommand:      {"deployment", "backup"},
+	breachSimulationCommand:   {"deployment", "target"},
+	latencyProbeCommand:       {"deployment", "duration", "connections", "qps"},
+	protectComponentsCommand:  {"deployment", "engine", "exempt"},
+	spireFederationCommand:    {"deployment", "spire_namespace", "trust_domain", "identity_template"},
+	exposeCommand:             {"deployment", "services", "host", "route", "class", "tls"},
+	routePoliciesCommand:      {"deployment"},
+	mirrorImagesCommand:       {"deployment", "mirror", "version", "tool", "execute", "push_secret"},
+	selfTestCommand:           {},
+	injectionExclusionCommand: {"deployment", "selector", "kinds"},
+	sidecarResourcesCommand:   {"deployment", "cpu_request", "cpu_limit", "memory_request", "memory_limit"},
+	batchWorkloadsCommand:     {"deployment", "batch"},
+	policyImportCommand:       {"deployment", "bundle", "confirm"},
+	demoBlockRatingsCommand:   {"deployment"},
+	demoMTLSCommand:           {"deployment"},
+	demoRestrictEgressCommand: {"deployment"},
+}
+
+// checkOpParams fails on the keys of a custom body the operation doesn't read
+func checkOpParams(op, body string) error {
+	keys, ok := opParams[op]
+	if !ok || strings.TrimSpace(body) == "" {
+		return nil
+	}
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(body), &values); err != nil {
+		return errors.Wrapf(err, "unable to parse the operation parameters")
+	}
+	known := stringSet(keys)
+	unknown := map[string]bool{}
+	for key := range values {
+		if !known[key] {
+			unknown[key] = true
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("error: operation %s takes no custom body, got %s", op, strings.Join(sortedKeys(unknown), ", "))
+	}
+	return fmt.Errorf("error: operation %s doesn't take %s, it takes %s", op, strings.Join(sortedKeys(unknown), ", "), strings.Join(keys, ", "))
+}
+
+func parseDeploymentParams(body string) (*deploymentParams, error) {
+	params := &deploymentParams{}
+	if strings.TrimSpace(body) == "" {
+		return params, nil
+	}
+	if err := yaml.Unmarshal([]byte(body), params); err != nil {
+		err = errors.Wrapf(err, "unable to parse the operation parameters")
+		logrus.Error(err)
+		return nil, err
+	}
+	return params, nil
+}
+
+// injectionValue is the value of the injection label selecting namespaces for this deployment.
+// The default deployment keeps the value octactl's webhook configuration expects.
+func (d *deployment) injectionValue() string {
+	if d.name == defaultDeploymentName {
+		return injectionLabelValue
+	}
+	return d.name
+}
+
+// versionName is the Octarine version of the deployment for messages
+func (d *deployment) versionName() string {
+	if d.version == "" {
+		return "(default version)"
+	}
+	return d.version
+}
+
+// clusterResourceName keeps cluster scoped resources of different deployments apart
+func (d *deployment) clusterResourceName(name string) string {
+	name = resourceName(name)
+	if d.name == defaultDeploymentName || strings.HasSuffix(name, "-"+d.name) {
+		return name
+	}
+	return name + "-" + d.name
+}
+
+// scopeToDeployment labels and renames the resources of a dataplane manifest, makes the namespaced ones
+// owned by the anchor and restricts the injection webhooks to the namespaces labeled for this deployment
+// and the pods which haven't opted out
+func (d *deployment) scopeToDeployment(data *unstructured.Unstructured) error {
+	objLabels := data.GetLabels()
+	if objLabels == nil {
+		objLabels = map[string]string{}
+	}
+	for k, v := range d.managedLabels() {
+		objLabels[k] = v
+	}
+	data.SetLabels(objLabels)
+
+	gvk := data.GroupVersionKind()
+	if d.anchor != nil && d.namespacedKinds[gvk.GroupKind()] {
+		data.SetOwnerReferences(append(data.GetOwnerReferences(), *d.anchor))
+	}
+
+	if clusterScopedKinds[strings.ToLower(data.GetKind())] {
+		data.SetName(d.clusterResourceName(data.GetName()))
+	}
+	if kind, found, _ := unstructured.NestedString(data.Object, "roleRef", "kind"); found && kind == "ClusterRole" {
+		if name, found, _ := unstructured.NestedString(data.Object, "roleRef", "name"); found {
+			if err := unstructured.SetNestedField(data.Object, d.clusterResourceName(name), "roleRef", "name"); err != nil {
+				return err
+			}
+		}
+	}
+	if data.GetKind() != "MutatingWebhookConfiguration" {
+		return nil
+	}
+	if err := addInjectionOptOut(data); err != nil {
+		return err
+	}
+	if d.name == defaultDeploymentName {
+		return nil
+	}
+	webhooks, found, err := nestedObjects(data.Object, "webhooks")
+	if !found || err != nil {
+		return err
+	}
+	for _, webhook := range webhooks {
+		if _, found, _ := unstructured.NestedString(webhook, "namespaceSelector", "matchLabels", injectionLabel); found {
+			if err := unstructured.SetNestedField(webhook, d.injectionValue(), "namespaceSelector", "matchLabels", injectionLabel); err != nil {
+				return err
+			}
+		}
+	}
+	return setNestedObjects(data.Object, webhooks, "webhooks")
+}
+
+// scopeManifest applies scopeToDeployment to every document of a manifest
+func (d *deployment) scopeManifest(manifest string) (string, error) {
+	return transformManifest(manifest, d.scopeToDeployment)
+}
+
+// transformManifest rewrites every document of a manifest with fn
+func transformManifest(manifest string, fn func(*unstructured.Unstructured) error) (string, error) {
+	docs := strings.Split(manifest, "---")
+	result := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		obj := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return "", errors.Wrapf(err, "unable to parse manifest document")
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		data := &unstructured.Unstructured{Object: obj}
+		if err := fn(data); err != nil {
+			return "", err
+		}
+		out, err := yaml.Marshal(data.Object)
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to serialize manifest document")
+		}
+		result = append(result, string(out))
+	}
+	return strings.Join(result, "\n---\n"), nil
+}
+
+// newDeployment registers a deployment, failing when its name, namespace or domain is already in use
+func (oClient *Client) newDeployment(name, namespace, domain, version string) (*deployment, error) {
+	if domain == "" {
+		domain = os.Getenv("OCTARINE_DOMAIN")
+	}
+	oClient.deploymentsMu.Lock()
+	defer oClient.deploymentsMu.Unlock()
+	if oClient.deployments == nil {
+		oClient.deployments = map[string]*deployment{}
+	}
+	for _, d := range oClient.deployments {
+		switch {
+		case d.name == name:
+			return nil, fmt.Errorf("error: deployment %s already exists in namespace %s", name, d.namespace)
+		case d.namespace == namespace:
+			return nil, fmt.Errorf("error: namespace %s is already used by deployment %s", namespace, d.name)
+		case d.domain == domain:
+			return nil, fmt.Errorf("error: domain %s is already used by deployment %s", domain, d.name)
+		}
+	}
+	d := &deployment{
+		name:      name,
+		namespace: namespace,
+		domain:    domain,
+		version:   version,
+	}
+	oClient.deployments[name] = d
+	return d, nil
+}
+
+func (oClient *Client) removeDeployment(name string) {
+	oClient.deploymentsMu.Lock()
+	defer oClient.deploymentsMu.Unlock()
+	delete(oClient.deployments, name)
+}
+
+// getDeployment looks a deployment up by name, an empty name is only accepted when there is a single deployment
+func (oClient *Client) getDeployment(name string) (*deployment, error) {
+	oClient.deploymentsMu.Lock()
+	defer oClient.deploymentsMu.Unlock()
+	if name != "" {
+		d, ok := oClient.deployments[name]
+		if !ok {
+			return nil, fmt.Errorf("error: deployment %s does not exist", name)
+		}
+		return d, nil
+	}
+	switch len(oClient.deployments) {
+	case 0:
+		return nil, errors.New("error: Octarine has not been deployed by this adapter")
+	case 1:
+		for _, d := range oClient.deployments {
+			return d, nil
+		}
+	}
+	names := make([]string, 0, len(oClient.deployments))
+	for n := range oClient.deployments {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("error: a deployment must be specified, one of: %s", strings.Join(names, ", "))
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOpParamsAreDeploymentParams(t *testing.T) {
+	fields := map[string]bool{}
+	typ := reflect.TypeOf(deploymentParams{})
+	for i := 0; i < typ.NumField(); i++ {
+		fields[strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]] = true
+	}
+	for op, keys := range opParams {
+		if _, ok := supportedOps[op]; !ok {
+			t.Errorf("%s is not a supported operation", op)
+		}
+		if manifestBodyOps[op] || op == applyMeshSpecCommand {
+			t.Errorf("%s doesn't take deploymentParams", op)
+		}
+		for _, key := range keys {
+			if !fields[key] {
+				t.Errorf("%s takes %s, which isn't a field of deploymentParams", op, key)
+			}
+		}
+	}
+}
+
+func TestCheckOpParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      string
+		body    string
+		wantErr string
+	}{
+		{name: "empty body", op: enforcementModeCommand},
+		{name: "known keys", op: enforcementModeCommand, body: "deployment: prod\nmode: enforce"},
+		{name: "key of another operation", op: enforcementModeCommand, body: "mode: enforce\nreplicas: 2", wantErr: "doesn't take replicas"},
+		{name: "misspelled key", op: latencyProbeCommand, body: `{"duraton": "30s", "qps": 10}`, wantErr: "doesn't take duraton"},
+		{name: "no custom body", op: selfTestCommand, body: "deployment: prod", wantErr: "takes no custom body"},
+		{name: "template values", op: namespaceIsolationCommand, body: "allowedNamespaces: [monitoring]"},
+		{name: "not a map", op: enforcementModeCommand, body: "- enforce", wantErr: "unable to parse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkOpParams(tt.op, tt.body)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkOpParams() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkOpParams() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
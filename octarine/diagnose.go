@@ -0,0 +1,159 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// diagnosisError carries the root cause found for a failed operation
+type diagnosisError struct {
+	err       error
+	diagnosis string
+}
+
+func (e *diagnosisError) Error() string {
+	return fmt.Sprintf("%v\nRoot cause:\n%s", e.err, e.diagnosis)
+}
+
+// Cause lets errors.Cause see through the diagnosis
+func (e *diagnosisError) Cause() error {
+	return e.err
+}
+
+// withDiagnosis attaches the root cause of stuck deployments to err, if one could be found
+func (oClient *Client) withDiagnosis(ctx context.Context, err error, namespace string, names []string) error {
+	diagnosis := oClient.diagnoseDeployments(ctx, namespace, names)
+	if diagnosis == "" {
+		return err
+	}
+	return &diagnosisError{err: err, diagnosis: diagnosis}
+}
+
+// diagnoseDeployments explains why the pods of the given deployments aren't running
+func (oClient *Client) diagnoseDeployments(ctx context.Context, namespace string, names []string) string {
+	causes := []string{}
+	seen := map[string]bool{}
+	add := func(cause string) {
+		if !seen[cause] {
+			seen[cause] = true
+			causes = append(causes, "- "+cause)
+		}
+	}
+	for _, name := range names {
+		depl, err := oClient.clientset(ctx).AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+		if err != nil || depl.Spec.Selector == nil {
+			continue
+		}
+		pods, err := oClient.clientset(ctx).CoreV1().Pods(namespace).List(metav1.ListOptions{
+			LabelSelector: metav1.FormatLabelSelector(depl.Spec.Selector),
+		})
+		if err != nil {
+			logrus.Warnf("unable to list the pods of deployment %s/%s: %v", namespace, name, err)
+			continue
+		}
+		for i := range pods.Items {
+			for _, cause := range oClient.diagnosePod(ctx, &pods.Items[i]) {
+				add(cause)
+			}
+		}
+	}
+	return strings.Join(causes, "\n")
+}
+
+func (oClient *Client) diagnosePod(ctx context.Context, pod *corev1.Pod) []string {
+	causes := []string{}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
+			causes = append(causes, fmt.Sprintf("pod %s cannot be scheduled (%s): %s", pod.GetName(), schedulingHint(cond.Message), cond.Message))
+		}
+	}
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		if cs.State.Waiting == nil {
+			continue
+		}
+		waiting := cs.State.Waiting
+		switch waiting.Reason {
+		case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+			causes = append(causes, fmt.Sprintf("container %s of pod %s cannot pull image %s (%s): %s",
+				cs.Name, pod.GetName(), cs.Image, pullHint(waiting.Message), waiting.Message))
+		case "CrashLoopBackOff":
+			cause := fmt.Sprintf("container %s of pod %s keeps crashing", cs.Name, pod.GetName())
+			if term := cs.LastTerminationState.Terminated; term != nil {
+				cause += fmt.Sprintf(", last exit code %d (%s)", term.ExitCode, term.Reason)
+			}
+			causes = append(causes, cause)
+		case "CreateContainerConfigError", "CreateContainerError":
+			causes = append(causes, fmt.Sprintf("container %s of pod %s cannot be created: %s", cs.Name, pod.GetName(), waiting.Message))
+		}
+	}
+	if len(causes) > 0 || pod.Status.Phase != corev1.PodPending {
+		return causes
+	}
+
+	// nothing in the status yet, the events usually say what the kubelet or scheduler is unhappy about
+	events, err := oClient.clientset(ctx).CoreV1().Events(pod.GetNamespace()).List(metav1.ListOptions{
+		FieldSelector: fields.Set{
+			"involvedObject.name": pod.GetName(),
+			"type":                corev1.EventTypeWarning,
+		}.AsSelector().String(),
+	})
+	if err != nil {
+		return causes
+	}
+	for _, event := range events.Items {
+		causes = append(causes, fmt.Sprintf("pod %s: %s: %s", pod.GetName(), event.Reason, event.Message))
+	}
+	return causes
+}
+
+func schedulingHint(message string) string {
+	switch {
+	case strings.Contains(message, "taint"):
+		return "no node tolerates the pod, check node taints"
+	case strings.Contains(message, "Insufficient"):
+		return "not enough free resources in the cluster"
+	case strings.Contains(message, "node selector") || strings.Contains(message, "affinity"):
+		return "no node matches the node selector or affinity"
+	case strings.Contains(message, "PersistentVolumeClaim") || strings.Contains(message, "volume"):
+		return "a volume cannot be bound"
+	default:
+		return "unschedulable"
+	}
+}
+
+func pullHint(message string) string {
+	lower := strings.ToLower(message)
+	switch {
+	case strings.Contains(lower, "unauthorized") || strings.Contains(lower, "authentication required") ||
+		strings.Contains(lower, "pull access denied") || strings.Contains(lower, "denied"):
+		return "the pull secret is missing or its credentials are wrong, check OCTARINE_DOCKER_USERNAME and OCTARINE_DOCKER_PASSWORD"
+	case strings.Contains(lower, "not found") || strings.Contains(lower, "manifest unknown"):
+		return "the image or tag does not exist"
+	case strings.Contains(lower, "timeout") || strings.Contains(lower, "no such host") || strings.Contains(lower, "connection refused"):
+		return "the registry cannot be reached from the node"
+	default:
+		return "image pull failed"
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// maxDiffLines bounds the fields an update event lists, the others are counted
+	maxDiffLines = 20
+	// maxDiffValue bounds how much of a value a diff line shows
+	maxDiffValue  = 80
+	redactedValue = "(redacted)"
+)
+
+// ignoredDiffFields are kept up to date by the API server, they change on every update
+var ignoredDiffFields = map[string]bool{
+	"status":                   true,
+	"metadata.resourceVersion": true,
+	"metadata.generation":      true,
+	"metadata.managedFields":   true,
+}
+
+// fieldChange is a field an update changed, old or new being nil when the field was added or removed
+type fieldChange struct {
+	path string
+	// keys are the fields of the path down to the first list, to look up who manages the field
+	keys     []string
+	old, new interface{}
+}
+
+// String writes a change as "+ path: new", "- path: old" or "~ path: old → new"
+func (c fieldChange) String() string {
+	switch {
+	case c.old == nil:
+		return fmt.Sprintf("+ %s: %s", c.path, diffValue(c.new))
+	case c.new == nil:
+		return fmt.Sprintf("- %s: %s", c.path, diffValue(c.old))
+	default:
+		return fmt.Sprintf("~ %s: %s → %s", c.path, diffValue(c.old), diffValue(c.new))
+	}
+}
+
+// objectChanges lists the fields of a live object an update changes, sorted by path. The values of Secrets
+// are redacted.
+func objectChanges(live, updated *unstructured.Unstructured) []fieldChange {
+	changes := []fieldChange{}
+	diffFields("", nil, false, live.Object, updated.Object, &changes)
+	if live.GetKind() == "Secret" {
+		for i, c := range changes {
+			if strings.HasPrefix(c.path, "data.") || strings.HasPrefix(c.path, "stringData.") {
+				if c.old != nil {
+					changes[i].old = redactedValue
+				}
+				if c.new != nil {
+					changes[i].new = redactedValue
+				}
+			}
+		}
+	}
+	return changes
+}
+
+func diffFields(path string, keys []string, listed bool, old, new interface{}, changes *[]fieldChange) {
+	if ignoredDiffFields[path] || reflect.DeepEqual(old, new) {
+		return
+	}
+	oldMap, ok := old.(map[string]interface{})
+	newMap, ok2 := new.(map[string]interface{})
+	if ok && ok2 {
+		fields := map[string]bool{}
+		for key := range oldMap {
+			fields[key] = true
+		}
+		for key := range newMap {
+			fields[key] = true
+		}
+		for _, key := range sortedKeys(fields) {
+			fieldKeys := keys
+			if !listed {
+				fieldKeys = append(append([]string{}, keys...), key)
+			}
+			diffFields(diffPath(path, key), fieldKeys, listed, oldMap[key], newMap[key], changes)
+		}
+		return
+	}
+	oldList, ok := old.([]interface{})
+	newList, ok2 := new.([]interface{})
+	if ok && ok2 && len(oldList) == len(newList) {
+		for i := range oldList {
+			diffFields(fmt.Sprintf("%s[%d]", path, i), keys, true, oldList[i], newList[i], changes)
+		}
+		return
+	}
+	*changes = append(*changes, fieldChange{path: path, keys: keys, old: old, new: new})
+}
+
+// diffPath appends a key to a path, quoting keys like annotation names which have dots in them
+func diffPath(path, key string) string {
+	if strings.ContainsAny(key, ".[]") {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// diffValue writes a value compactly as JSON, cutting it short
+func diffValue(value interface{}) string {
+	s := redactedValue
+	if value != redactedValue {
+		data, err := json.Marshal(value)
+		if err != nil {
+			data = []byte(fmt.Sprint(value))
+		}
+		s = string(data)
+	}
+	if runes := []rune(s); len(runes) > maxDiffValue {
+		s = string(runes[:maxDiffValue-3]) + "..."
+	}
+	return s
+}
+
+// diffLines writes the changes one per line, at most maxDiffLines of them
+func diffLines(changes []fieldChange) string {
+	lines := []string{}
+	for i, c := range changes {
+		if i == maxDiffLines {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(changes)-maxDiffLines))
+			break
+		}
+		lines = append(lines, c.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// reportUpdate tells what an update of a resource changed in an event of the operation and in the audit log, an
+// update changing nothing isn't reported
+func (oClient *Client) reportUpdate(ctx context.Context, updated *unstructured.Unstructured, changes []fieldChange) {
+	if len(changes) == 0 {
+		return
+	}
+	target := changeTarget{kind: updated.GetKind(), namespace: updated.GetNamespace(), name: updated.GetName()}.String()
+	details := redactSecrets(ctx, diffLines(changes))
+	entry := auditEntry{
+		Action:  "resource.update",
+		Target:  target,
+		Details: strings.Replace(details, "\n", "; ", -1),
+	}
+	if r := resultFrom(ctx); r != nil {
+		entry.User = r.Username
+		entry.Details = fmt.Sprintf("operation %s: %s", r.OperationID, entry.Details)
+	}
+	recordAudit(entry, nil)
+	if oClient.eventChan == nil {
+		return
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: operationIDFrom(ctx),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Updated %s, %d field(s) changed", target, len(changes)),
+		Details:     details,
+	}
+}
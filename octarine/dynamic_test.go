@@ -0,0 +1,132 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// memoryDynamic is a dynamic client keeping the objects in memory, the client-go fake needs modules the
+// adapter doesn't depend on
+type memoryDynamic struct {
+	mu      sync.Mutex
+	objects map[string]*unstructured.Unstructured
+	// discard keeps only the names of the objects created and updated, so measuring the heap of an apply
+	// doesn't count the objects the server holds
+	discard bool
+}
+
+func newMemoryDynamic() *memoryDynamic {
+	return &memoryDynamic{objects: map[string]*unstructured.Unstructured{}}
+}
+
+func newDiscardingDynamic() *memoryDynamic {
+	return &memoryDynamic{objects: map[string]*unstructured.Unstructured{}, discard: true}
+}
+
+// returned is the object the server answers a write with
+func (m *memoryDynamic) returned(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	if m.discard {
+		return obj
+	}
+	return obj.DeepCopy()
+}
+
+// stored is the copy of an object the client keeps
+func (m *memoryDynamic) stored(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	if !m.discard {
+		return obj.DeepCopy()
+	}
+	kept := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	kept.SetName(obj.GetName())
+	kept.SetNamespace(obj.GetNamespace())
+	return kept
+}
+
+func (m *memoryDynamic) Resource(res schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &memoryResource{m: m, res: res}
+}
+
+// add stores an object as the API server would have it, whatever the types of its fields
+func (m *memoryDynamic) add(res schema.GroupVersionResource, obj *unstructured.Unstructured) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[memoryKey(res, obj.GetNamespace(), obj.GetName())] = obj.DeepCopy()
+}
+
+func memoryKey(res schema.GroupVersionResource, namespace, name string) string {
+	return res.String() + "/" + namespace + "/" + name
+}
+
+type memoryResource struct {
+	// the methods the tests don't call
+	dynamic.ResourceInterface
+	m         *memoryDynamic
+	res       schema.GroupVersionResource
+	namespace string
+}
+
+func (r *memoryResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &memoryResource{m: r.m, res: r.res, namespace: namespace}
+}
+
+func (r *memoryResource) Create(obj *unstructured.Unstructured, _ metav1.CreateOptions, _ ...string) (*unstructured.Unstructured, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	key := memoryKey(r.res, r.namespace, obj.GetName())
+	if _, ok := r.m.objects[key]; ok {
+		return nil, apierrors.NewAlreadyExists(r.res.GroupResource(), obj.GetName())
+	}
+	r.m.objects[key] = r.m.stored(obj)
+	return r.m.returned(obj), nil
+}
+
+func (r *memoryResource) Update(obj *unstructured.Unstructured, _ metav1.UpdateOptions, _ ...string) (*unstructured.Unstructured, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	key := memoryKey(r.res, r.namespace, obj.GetName())
+	if _, ok := r.m.objects[key]; !ok {
+		return nil, apierrors.NewNotFound(r.res.GroupResource(), obj.GetName())
+	}
+	r.m.objects[key] = r.m.stored(obj)
+	return r.m.returned(obj), nil
+}
+
+func (r *memoryResource) Get(name string, _ metav1.GetOptions, _ ...string) (*unstructured.Unstructured, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	obj, ok := r.m.objects[memoryKey(r.res, r.namespace, name)]
+	if !ok {
+		return nil, apierrors.NewNotFound(r.res.GroupResource(), name)
+	}
+	return obj.DeepCopy(), nil
+}
+
+func (r *memoryResource) Delete(name string, _ *metav1.DeleteOptions, _ ...string) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+	key := memoryKey(r.res, r.namespace, name)
+	if _, ok := r.m.objects[key]; !ok {
+		return apierrors.NewNotFound(r.res.GroupResource(), name)
+	}
+	delete(r.m.objects, key)
+	return nil
+}
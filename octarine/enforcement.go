@@ -0,0 +1,201 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sync"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	enforcementObserve = "observe"
+	enforcementEnforce = "enforce"
+	// Octarine only monitors until told to enforce
+	defaultEnforcementMode = enforcementObserve
+
+	enforcementLabel     = "octarine-enforcement"
+	anchorEnforcementKey = "enforcement"
+)
+
+func validEnforcementMode(mode string) bool {
+	return mode == enforcementObserve || mode == enforcementEnforce
+}
+
+// octactlSession guards the octactl login: octactl keeps the account it is logged in to in its config, which
+// the whole adapter shares, so a login and the commands run as its account hold it
+var octactlSession sync.Mutex
+
+// asAccount runs the octactl commands of fn logged in to the account of a deployment, no other account logs in
+// before fn returns. A deployment of an unknown account, one the adapter doesn't track, uses the login in place.
+func (oClient *Client) asAccount(ctx context.Context, d *deployment, fn func() error) error {
+	octactlSession.Lock()
+	defer octactlSession.Unlock()
+	if d.account != "" {
+		if err := oClient.loginToAccount(ctx, d); err != nil {
+			return err
+		}
+	}
+	return fn()
+}
+
+// loginToAccount makes the following octactl commands work on the account of the deployment, the caller holds
+// octactlSession
+func (oClient *Client) loginToAccount(ctx context.Context, d *deployment) error {
+	if d.account == "" {
+		return fmt.Errorf("error: the Octarine account of deployment %s is unknown", d.name)
+	}
+	login := &accountLogin{controlPlane: oClient.octarineControlPlane, username: accMgrUsername + "@" + d.account, password: oClient.octarineAccMgrPword}
+	if d.login != nil {
+		login = d.login
+	}
+	cmd := exec.CommandContext(ctx, "octactl", "login", login.username, login.controlPlane, "--password", login.password)
+	logrus.Debugf("Login to namespace %s", d.account)
+	if err := cmd.Run(); err != nil {
+		logrus.Errorf("Command finished with error: %v", err)
+		return err
+	}
+	return nil
+}
+
+// setControlPlaneEnforcement switches the domain of the deployment, or only one of its namespaces, to the mode
+func (oClient *Client) setControlPlaneEnforcement(ctx context.Context, d *deployment, namespace, mode string) error {
+	args := []string{"domain", "enforcement", d.domain, mode}
+	if namespace != "" {
+		args = append(args, "--k8s-namespace", namespace)
+	}
+	return oClient.asAccount(ctx, d, func() error {
+		cmd := exec.CommandContext(ctx, "octactl", args...)
+		logrus.Debugf("Setting enforcement mode of domain %s to %s", d.domain, mode)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			logrus.Errorf("Command finished with error: %v: %s", err, out)
+			return errors.Wrapf(err, "unable to set the enforcement mode of domain %s", d.domain)
+		}
+		return nil
+	})
+}
+
+// globalEnforcementMode is recorded on the anchor of the deployment
+func (oClient *Client) globalEnforcementMode(ctx context.Context, d *deployment) (string, error) {
+	cm, err := oClient.clientset(ctx).CoreV1().ConfigMaps(d.namespace).Get(resourceName(anchorName), metav1.GetOptions{})
+	if err != nil {
+		err = errors.Wrapf(err, "unable to get the anchor of deployment %s", d.name)
+		logrus.Error(err)
+		return "", err
+	}
+	if mode := cm.Data[anchorEnforcementKey]; mode != "" {
+		return mode, nil
+	}
+	return defaultEnforcementMode, nil
+}
+
+// executeEnforcementMode switches a namespace, or the whole deployment when no namespace is given,
+// to the mode of the custom body; deleting makes a namespace follow the global mode again
+func (oClient *Client) executeEnforcementMode(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return err
+	}
+	namespace := arReq.GetNamespace()
+	mode := params.Mode
+	if arReq.GetDeleteOp() {
+		if namespace == "" {
+			return errors.New("error: a namespace is required to remove its enforcement mode")
+		}
+		if mode, err = oClient.globalEnforcementMode(ctx, d); err != nil {
+			return err
+		}
+	} else if !validEnforcementMode(mode) {
+		return fmt.Errorf("error: mode must be %s or %s, got %q", enforcementObserve, enforcementEnforce, mode)
+	}
+
+	if namespace != "" {
+		if err := oClient.requireInjected(ctx, d, namespace); err != nil {
+			return err
+		}
+	}
+	if mode == enforcementEnforce {
+		if err := oClient.gateEnforcement(arReq, d, namespace); err != nil {
+			return err
+		}
+	}
+	workingOn(ctx, "setting the enforcement mode of domain %s", d.domain)
+	if err := oClient.setControlPlaneEnforcement(ctx, d, namespace, mode); err != nil {
+		return err
+	}
+	progressed(ctx)
+
+	if namespace == "" {
+		patch := []byte(fmt.Sprintf(`{"data":{%q:%q}}`, anchorEnforcementKey, mode))
+		_, err = oClient.clientset(ctx).CoreV1().ConfigMaps(d.namespace).Patch(resourceName(anchorName), types.MergePatchType, patch)
+	} else {
+		value := fmt.Sprintf("%q", mode)
+		if arReq.GetDeleteOp() {
+			value = "null"
+		}
+		patch := []byte(fmt.Sprintf(`{"metadata":{"labels":{%q:%s}}}`, enforcementLabel, value))
+		_, err = oClient.clientset(ctx).CoreV1().Namespaces().Patch(namespace, types.MergePatchType, patch)
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "unable to record the enforcement mode")
+		logrus.Error(err)
+		return err
+	}
+	return nil
+}
+
+// EnforcementStatus reports the global enforcement mode of a deployment and the mode of each injected namespace
+func (oClient *Client) EnforcementStatus(ctx context.Context, req *meshes.EnforcementStatusRequest) (*meshes.EnforcementStatusResponse, error) {
+	if oClient.clientset(ctx) == nil {
+		return &meshes.EnforcementStatusResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	d, err := oClient.getDeployment(req.GetDeployment())
+	if err != nil {
+		return &meshes.EnforcementStatusResponse{Error: err.Error()}, nil
+	}
+	global, err := oClient.globalEnforcementMode(ctx, d)
+	if err != nil {
+		return &meshes.EnforcementStatusResponse{Error: err.Error()}, nil
+	}
+	nsList, err := oClient.clientset(ctx).CoreV1().Namespaces().List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", injectionLabel, d.injectionValue()),
+	})
+	if err != nil {
+		err = errors.Wrapf(err, "unable to list namespaces labeled for injection")
+		logrus.Error(err)
+		return &meshes.EnforcementStatusResponse{Error: err.Error()}, nil
+	}
+	resp := &meshes.EnforcementStatusResponse{GlobalMode: global}
+	for _, ns := range nsList.Items {
+		entry := &meshes.NamespaceEnforcement{Namespace: ns.GetName(), Mode: ns.GetLabels()[enforcementLabel]}
+		if entry.Mode == "" {
+			entry.Mode = global
+			entry.Inherited = true
+		}
+		resp.Namespaces = append(resp.Namespaces, entry)
+	}
+	return resp, nil
+}
@@ -0,0 +1,256 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// eventQueueSize buffers the events operations emit until the broker fans them out
+	eventQueueSize = 100
+	// subscriberQueueSize is how far a stream may fall behind before its oldest events are dropped
+	subscriberQueueSize = 500
+	// eventBacklogSize is how many events are kept for the next stream while none is open
+	eventBacklogSize = 500
+)
+
+// eventBroker fans the events of eventChan out to a queue per StreamEvents subscriber, so a slow stream
+// neither blocks the operations emitting events nor delays the other streams. Events emitted while no
+// stream is open are kept, up to eventBacklogSize, for the next one.
+type eventBroker struct {
+	mu          sync.Mutex
+	subscribers map[*eventSubscriber]bool
+	backlog     []*meshes.EventsResponse
+	// dropped counts the events lost because the backlog was full
+	dropped int
+
+	results *resultTracker
+	// store persists the events when OCTARINE_EVENT_STORE is set
+	store *eventStore
+	// throttle deduplicates and rate limits the events of the watchers
+	throttle *eventThrottle
+	// sequence is the number of the last event published, only the run loop changes it
+	sequence uint64
+}
+
+type eventSubscriber struct {
+	queue chan *meshes.EventsResponse
+
+	mu      sync.Mutex
+	dropped int
+}
+
+// startEvents creates the event channel of the adapter and the broker draining it, once
+func (oClient *Client) startEvents() *eventBroker {
+	oClient.eventsOnce.Do(func() {
+		if oClient.eventChan == nil {
+			oClient.eventChan = make(chan *meshes.EventsResponse, eventQueueSize)
+		}
+		oClient.events = &eventBroker{
+			subscribers: map[*eventSubscriber]bool{},
+			results: &resultTracker{
+				running: map[string]*operationResult{},
+				done:    map[*meshes.EventsResponse]*operationResult{},
+			},
+			store:    openEventStore(),
+			throttle: newEventThrottle(),
+		}
+		go oClient.events.run(oClient.eventChan)
+	})
+	return oClient.events
+}
+
+func (b *eventBroker) run(in <-chan *meshes.EventsResponse) {
+	ticker := time.NewTicker(throttleFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case event, ok := <-in:
+			if !ok {
+				return
+			}
+			normalizeSeverity(event)
+			now := time.Now()
+			b.stamp(event, now)
+			for _, e := range b.throttle.admit(event, now) {
+				b.deliver(e)
+			}
+		case now := <-ticker.C:
+			for _, e := range b.throttle.flush(now) {
+				b.deliver(e)
+			}
+		}
+	}
+}
+
+// stamp sets when the adapter received an event, and how long after its operation started. The events the
+// throttle reports later are stamped when they are delivered.
+func (b *eventBroker) stamp(event *meshes.EventsResponse, now time.Time) {
+	if event.GetTime() != "" {
+		return
+	}
+	event.Time = now.UTC().Format(time.RFC3339Nano)
+	if started := b.results.started(event.GetOperationId()); !started.IsZero() {
+		event.Elapsed = now.Sub(started).Round(time.Millisecond).String()
+	}
+}
+
+// deliver records the outcome of the operation of an event, or else numbers, persists and publishes it
+func (b *eventBroker) deliver(event *meshes.EventsResponse) {
+	b.stamp(event, time.Now())
+	namespace := b.results.namespace(event.GetOperationId())
+	if b.results.track(event) {
+		return
+	}
+	b.sequence++
+	event.Sequence = b.sequence
+	b.store.append(event, namespace)
+	b.publish(event)
+}
+
+func (b *eventBroker) publish(event *meshes.EventsResponse) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.subscribers) == 0 {
+		if len(b.backlog) == eventBacklogSize {
+			b.backlog = b.backlog[1:]
+			b.dropped++
+		}
+		b.backlog = append(b.backlog, event)
+		return
+	}
+	for sub := range b.subscribers {
+		sub.offer(event)
+	}
+}
+
+// offer queues an event, making room by dropping the oldest queued one when the subscriber fell behind
+func (s *eventSubscriber) offer(event *meshes.EventsResponse) {
+	for {
+		select {
+		case s.queue <- event:
+			return
+		default:
+		}
+		select {
+		case <-s.queue:
+			s.mu.Lock()
+			s.dropped++
+			s.mu.Unlock()
+		default:
+		}
+	}
+}
+
+// takeDropped returns how many events were dropped since it was last called
+func (s *eventSubscriber) takeDropped() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := s.dropped
+	s.dropped = 0
+	return n
+}
+
+// subscribe opens a queue of events, which starts with the events kept while no stream was open
+func (b *eventBroker) subscribe() *eventSubscriber {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sub := &eventSubscriber{queue: make(chan *meshes.EventsResponse, subscriberQueueSize)}
+	for _, event := range b.backlog {
+		sub.offer(event)
+	}
+	b.backlog = nil
+	b.subscribers[sub] = true
+	return sub
+}
+
+// unsubscribe closes a queue, the events it couldn't deliver are kept for the next stream when it was the
+// last one open
+func (b *eventBroker) unsubscribe(sub *eventSubscriber, unsent *meshes.EventsResponse) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subscribers, sub)
+	if len(b.subscribers) > 0 {
+		return
+	}
+	pending := []*meshes.EventsResponse{}
+	if unsent != nil {
+		pending = append(pending, unsent)
+	}
+	for len(sub.queue) > 0 {
+		pending = append(pending, <-sub.queue)
+	}
+	b.backlog = append(pending, b.backlog...)
+	if over := len(b.backlog) - eventBacklogSize; over > 0 {
+		b.backlog = b.backlog[over:]
+		b.dropped += over
+	}
+}
+
+// normalizeSeverity gives an event both a severity and a type: the type follows the severity when it is set,
+// the events which only have a type get the severity matching it
+func normalizeSeverity(event *meshes.EventsResponse) {
+	switch event.GetSeverity() {
+	case meshes.Severity_SEVERITY_UNSPECIFIED:
+		switch event.GetEventType() {
+		case meshes.EventType_WARN:
+			event.Severity = meshes.Severity_SEVERITY_WARN
+		case meshes.EventType_ERROR:
+			event.Severity = meshes.Severity_SEVERITY_ERROR
+		default:
+			event.Severity = meshes.Severity_SEVERITY_INFO
+		}
+	case meshes.Severity_SEVERITY_DEBUG, meshes.Severity_SEVERITY_INFO:
+		event.EventType = meshes.EventType_INFO
+	case meshes.Severity_SEVERITY_WARN:
+		event.EventType = meshes.EventType_WARN
+	default:
+		event.EventType = meshes.EventType_ERROR
+	}
+}
+
+// wantsEvent tells whether an event passes the filters of a stream
+func wantsEvent(req *meshes.EventsRequest, event *meshes.EventsResponse) bool {
+	if event.GetSeverity() < req.GetMinSeverity() {
+		return false
+	}
+	return req.GetOperationId() == "" || event.GetOperationId() == req.GetOperationId()
+}
+
+// droppedEvent tells a stream it fell behind and missed events
+func droppedEvent(n int) *meshes.EventsResponse {
+	logrus.Warnf("An event stream fell behind, %d event(s) were dropped", n)
+	// it has no sequence, the gap it reports is in the sequence of the next event
+	return &meshes.EventsResponse{
+		EventType: meshes.EventType_WARN,
+		Severity:  meshes.Severity_SEVERITY_WARN,
+		Time:      time.Now().UTC().Format(time.RFC3339Nano),
+		Summary:   fmt.Sprintf("%d event(s) were dropped", n),
+		Details:   "The event stream fell behind the events emitted by the operations, the oldest ones were dropped.",
+	}
+}
+
+// stats describes the broker for the readiness probe
+func (b *eventBroker) stats() (subscribers, backlog, dropped int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers), len(b.backlog), b.dropped
+}
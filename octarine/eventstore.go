@@ -0,0 +1,301 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	eventStoreEnv         = "OCTARINE_EVENT_STORE"
+	eventRetentionEnv     = "OCTARINE_EVENT_RETENTION"
+	defaultEventRetention = 14 * 24 * time.Hour
+
+	// the events of a day are appended to events-<day>.jsonl, one JSON object per line, so pruning deletes
+	// whole files
+	eventFilePrefix = "events-"
+	eventFileSuffix = ".jsonl"
+	eventFileDay    = "2006-01-02"
+	// eventKeyTime orders the events of the store, its fixed width keeps the keys sorted as strings
+	eventKeyTime = "2006-01-02T15:04:05.000000000"
+
+	// maxStoredEventSize is the longest line read back, events are far shorter
+	maxStoredEventSize = 4 << 20
+)
+
+// storedEvent is an event as it is written to the store, with the namespace of the operation which emitted it
+type storedEvent struct {
+	Time        time.Time `json:"time"`
+	EventType   string    `json:"eventType"`
+	Severity    string    `json:"severity"`
+	Summary     string    `json:"summary"`
+	Details     string    `json:"details,omitempty"`
+	OperationID string    `json:"operationId,omitempty"`
+	Namespace   string    `json:"namespace,omitempty"`
+	Source      string    `json:"source,omitempty"`
+	Sequence    uint64    `json:"sequence,omitempty"`
+	Elapsed     string    `json:"elapsed,omitempty"`
+}
+
+// eventStore keeps the events in files of the directory OCTARINE_EVENT_STORE names, beyond the events kept in
+// memory for the streams, so they can be looked into long after they were emitted
+type eventStore struct {
+	dir string
+
+	mu   sync.Mutex
+	day  string
+	file *os.File
+}
+
+// openEventStore opens the directory of OCTARINE_EVENT_STORE, nil when events aren't persisted
+func openEventStore() *eventStore {
+	dir := os.Getenv(eventStoreEnv)
+	if dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		logrus.Error(errors.Wrapf(err, "unable to create the event store %s, events are not persisted", dir))
+		return nil
+	}
+	s := &eventStore{dir: dir}
+	s.prune(time.Now().UTC())
+	return s
+}
+
+// append writes an event to the file of the day, the files past the retention are deleted when a day starts
+func (s *eventStore) append(event *meshes.EventsResponse, namespace string) {
+	if s == nil {
+		return
+	}
+	now := time.Now().UTC()
+	at := now
+	if t, err := time.Parse(time.RFC3339Nano, event.GetTime()); err == nil {
+		at = t
+	}
+	line, err := json.Marshal(storedEvent{
+		Time:        at,
+		EventType:   event.GetEventType().String(),
+		Severity:    event.GetSeverity().String(),
+		Summary:     event.GetSummary(),
+		Details:     event.GetDetails(),
+		OperationID: event.GetOperationId(),
+		Namespace:   namespace,
+		Source:      event.GetSource(),
+		Sequence:    event.GetSequence(),
+		Elapsed:     event.GetElapsed(),
+	})
+	if err != nil {
+		logrus.Error(errors.Wrapf(err, "unable to marshal event"))
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if day := now.Format(eventFileDay); day != s.day {
+		if s.file != nil {
+			s.file.Close()
+			s.file = nil
+		}
+		path := filepath.Join(s.dir, eventFilePrefix+day+eventFileSuffix)
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			logrus.Error(errors.Wrapf(err, "unable to open event store file %s", path))
+			return
+		}
+		s.day, s.file = day, f
+		s.prune(now)
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		logrus.Error(errors.Wrapf(err, "unable to write event store file %s", s.file.Name()))
+	}
+}
+
+// days lists the days the store has events of, oldest first
+func (s *eventStore) days() ([]string, error) {
+	entries, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read the event store %s", s.dir)
+	}
+	days := []string{}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, eventFilePrefix) || !strings.HasSuffix(name, eventFileSuffix) {
+			continue
+		}
+		day := strings.TrimSuffix(strings.TrimPrefix(name, eventFilePrefix), eventFileSuffix)
+		if _, err := time.Parse(eventFileDay, day); err == nil {
+			days = append(days, day)
+		}
+	}
+	sort.Strings(days)
+	return days, nil
+}
+
+// prune deletes the files of the days which ended longer than OCTARINE_EVENT_RETENTION ago
+func (s *eventStore) prune(now time.Time) {
+	retention := durationFromEnv(eventRetentionEnv, defaultEventRetention)
+	days, err := s.days()
+	if err != nil {
+		logrus.Warn(err)
+		return
+	}
+	for _, day := range days {
+		start, _ := time.Parse(eventFileDay, day)
+		if now.Sub(start.Add(24*time.Hour)) <= retention {
+			continue
+		}
+		path := filepath.Join(s.dir, eventFilePrefix+day+eventFileSuffix)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			logrus.Warnf("Unable to delete the expired event store file %s: %v", path, err)
+		}
+	}
+}
+
+// eventQuery holds the parsed filters of a QueryEventsRequest
+type eventQuery struct {
+	since, until time.Time
+	min          meshes.Severity
+	namespace    string
+	operationID  string
+}
+
+func (q eventQuery) matches(e *storedEvent) bool {
+	switch {
+	case !q.since.IsZero() && e.Time.Before(q.since):
+		return false
+	case !q.until.IsZero() && !e.Time.Before(q.until):
+		return false
+	case meshes.Severity(meshes.Severity_value[e.Severity]) < q.min:
+		return false
+	case q.namespace != "" && e.Namespace != q.namespace:
+		return false
+	case q.operationID != "" && e.OperationID != q.operationID:
+		return false
+	}
+	return true
+}
+
+// query reads back the events matching a query, with the keys they are paginated by, in the order they were
+// emitted
+func (s *eventStore) query(q eventQuery) ([]string, map[string]*storedEvent, error) {
+	days, err := s.days()
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := []string{}
+	events := map[string]*storedEvent{}
+	for _, day := range days {
+		start, _ := time.Parse(eventFileDay, day)
+		if (!q.since.IsZero() && !start.Add(24*time.Hour).After(q.since)) || (!q.until.IsZero() && !start.Before(q.until)) {
+			continue
+		}
+		path := filepath.Join(s.dir, eventFilePrefix+day+eventFileSuffix)
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "unable to open event store file %s", path)
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), maxStoredEventSize)
+		for n := 0; scanner.Scan(); n++ {
+			e := &storedEvent{}
+			// a line cut short by a crash is skipped, the next ones are whole
+			if err := json.Unmarshal(scanner.Bytes(), e); err != nil || !q.matches(e) {
+				continue
+			}
+			key := fmt.Sprintf("%s/%09d", e.Time.UTC().Format(eventKeyTime), n)
+			keys = append(keys, key)
+			events[key] = e
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "unable to read event store file %s", path)
+		}
+	}
+	sort.Strings(keys)
+	return keys, events, nil
+}
+
+// parseEventQuery reads the filters of a request
+func parseEventQuery(req *meshes.QueryEventsRequest) (eventQuery, error) {
+	q := eventQuery{min: req.GetMinSeverity(), namespace: req.GetNamespace(), operationID: req.GetOperationId()}
+	var err error
+	if req.GetSince() != "" {
+		if q.since, err = time.Parse(time.RFC3339, req.GetSince()); err != nil {
+			return q, fmt.Errorf("error: since %q is not an RFC 3339 time", req.GetSince())
+		}
+	}
+	if req.GetUntil() != "" {
+		if q.until, err = time.Parse(time.RFC3339, req.GetUntil()); err != nil {
+			return q, fmt.Errorf("error: until %q is not an RFC 3339 time", req.GetUntil())
+		}
+	}
+	return q, nil
+}
+
+// QueryEvents looks up the persisted events by time, severity, namespace and operation, to look into what
+// happened after the streams which were open then are gone
+func (oClient *Client) QueryEvents(_ context.Context, req *meshes.QueryEventsRequest) (*meshes.QueryEventsResponse, error) {
+	store := oClient.startEvents().store
+	if store == nil {
+		return &meshes.QueryEventsResponse{Error: fmt.Sprintf("error: events are not persisted, set %s to the directory to keep them in", eventStoreEnv)}, nil
+	}
+	q, err := parseEventQuery(req)
+	if err != nil {
+		return &meshes.QueryEventsResponse{Error: err.Error()}, nil
+	}
+	keys, events, err := store.query(q)
+	if err != nil {
+		logrus.Error(err)
+		return &meshes.QueryEventsResponse{Error: err.Error()}, nil
+	}
+	start, end, next, err := paginate(keys, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return &meshes.QueryEventsResponse{Error: err.Error()}, nil
+	}
+	resp := &meshes.QueryEventsResponse{NextPageToken: next}
+	for _, key := range keys[start:end] {
+		e := events[key]
+		resp.Events = append(resp.Events, &meshes.StoredEvent{
+			Time:        e.Time.UTC().Format(time.RFC3339Nano),
+			EventType:   meshes.EventType(meshes.EventType_value[e.EventType]),
+			Severity:    meshes.Severity(meshes.Severity_value[e.Severity]),
+			Summary:     e.Summary,
+			Details:     e.Details,
+			OperationId: e.OperationID,
+			Namespace:   e.Namespace,
+			Source:      e.Source,
+			Sequence:    e.Sequence,
+			Elapsed:     e.Elapsed,
+		})
+	}
+	return resp, nil
+}
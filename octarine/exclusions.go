@@ -0,0 +1,248 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// injectOptOutAnnotation opts a workload or a pod out of injection, the webhook only selects on labels so the
+	// adapter copies it to the pod template as injectOptOutLabel
+	injectOptOutAnnotation = "octarine.io/inject"
+	// excludedByAnnotation marks the workloads the exclusion operation opted out, only those are opted back in
+	excludedByAnnotation = "octarine.io/excluded-by"
+)
+
+// exclusionKinds are the workloads whose pod templates can be opted out of injection, a Job can't change its
+// template once created and is excluded through its CronJob
+var exclusionKinds = map[string]schema.GroupVersionResource{
+	"deployment":  {Group: "apps", Version: "v1", Resource: "deployments"},
+	"statefulset": {Group: "apps", Version: "v1", Resource: "statefulsets"},
+	"daemonset":   {Group: "apps", Version: "v1", Resource: "daemonsets"},
+	"cronjob":     {Group: "batch", Version: "v1beta1", Resource: "cronjobs"},
+}
+
+// exclusionKindNames lists the kinds of exclusionKinds in order
+func exclusionKindNames() []string {
+	names := make([]string, 0, len(exclusionKinds))
+	for name := range exclusionKinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateExclusionParams checks the selector and the kinds of the workloads an exclusion applies to
+func validateExclusionParams(params *deploymentParams) error {
+	if params.Selector == "" {
+		return errors.New("error: a label selector of the workloads to exclude is required")
+	}
+	if _, err := labels.Parse(params.Selector); err != nil {
+		return fmt.Errorf("error: selector %q is not valid: %v", params.Selector, err)
+	}
+	for _, kind := range params.Kinds {
+		if _, ok := exclusionKinds[strings.ToLower(kind)]; !ok {
+			return fmt.Errorf("error: %s can't be excluded from injection, the kinds are %s", kind, strings.Join(exclusionKindNames(), ", "))
+		}
+	}
+	return nil
+}
+
+// optedOut tells whether the owner of a workload annotated it, or its pod template, to stay out of injection
+func optedOut(obj *unstructured.Unstructured) bool {
+	if obj.GetAnnotations()[injectOptOutAnnotation] == injectOptOutValue {
+		return true
+	}
+	value, _, _ := unstructured.NestedString(obj.Object, append(templateMetadataPath(obj.GetKind()), "annotations", injectOptOutAnnotation)...)
+	return value == injectOptOutValue
+}
+
+// templateMetadataPath is the path to the metadata of the pod template of a workload
+func templateMetadataPath(kind string) []string {
+	path := podSpecPath(kind)
+	return append(append([]string{}, path[:len(path)-1]...), "metadata")
+}
+
+// exclusionPatch sets, or removes with a nil value, the opt-out of a workload and the label of its pod template
+func exclusionPatch(kind string, annotations map[string]interface{}, label interface{}) ([]byte, error) {
+	patch := map[string]interface{}{"labels": map[string]interface{}{injectOptOutLabel: label}}
+	path := templateMetadataPath(kind)
+	for i := len(path) - 1; i >= 0; i-- {
+		patch = map[string]interface{}{path[i]: patch}
+	}
+	if len(annotations) > 0 {
+		patch["metadata"] = map[string]interface{}{"annotations": annotations}
+	}
+	return json.Marshal(patch)
+}
+
+// executeInjectionExclusion opts the workloads matching a selector out of injection, or back in with delete_op.
+// Their pod templates change, so their pods roll out again with or without the sidecar.
+func (oClient *Client) executeInjectionExclusion(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	if err := validateExclusionParams(params); err != nil {
+		return err
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return err
+	}
+	namespaces := []string{arReq.GetNamespace()}
+	if arReq.GetNamespace() == "" {
+		injected, err := oClient.injectedNamespaces(ctx, d.name)
+		if err != nil {
+			return err
+		}
+		namespaces = sortedKeys(injected)
+	} else if !arReq.GetDeleteOp() {
+		// opting back in is allowed after injection was disabled
+		if err := oClient.requireInjected(ctx, d, arReq.GetNamespace()); err != nil {
+			return err
+		}
+	}
+	kinds := params.Kinds
+	if len(kinds) == 0 {
+		kinds = exclusionKindNames()
+	}
+
+	changed, kept := []string{}, []string{}
+	for _, namespace := range namespaces {
+		if !arReq.GetDeleteOp() {
+			oClient.syncInjectionExclusions(ctx, namespace)
+		}
+		for _, kind := range kinds {
+			gvr := exclusionKinds[strings.ToLower(kind)]
+			workingOn(ctx, "%s matching %s in namespace %s", gvr.Resource, params.Selector, namespace)
+			client := oClient.dynamicClient(ctx).Resource(gvr).Namespace(namespace)
+			list, err := client.List(metav1.ListOptions{LabelSelector: params.Selector})
+			if err != nil {
+				return errors.Wrapf(err, "unable to list the %s of namespace %s", gvr.Resource, namespace)
+			}
+			for i := range list.Items {
+				obj := &list.Items[i]
+				target := changeTarget{kind: obj.GetKind(), namespace: namespace, name: obj.GetName()}.String()
+				ours := obj.GetAnnotations()[excludedByAnnotation] == fieldManager
+				var patch []byte
+				switch {
+				case !arReq.GetDeleteOp() && (ours || !optedOut(obj)):
+					patch, err = exclusionPatch(obj.GetKind(), patchAnnotations(ctx, map[string]interface{}{
+						injectOptOutAnnotation: injectOptOutValue,
+						excludedByAnnotation:   fieldManager,
+					}), injectOptOutValue)
+				case arReq.GetDeleteOp() && ours:
+					patch, err = exclusionPatch(obj.GetKind(), patchAnnotations(ctx, map[string]interface{}{
+						injectOptOutAnnotation: nil,
+						excludedByAnnotation:   nil,
+					}), nil)
+				case optedOut(obj):
+					// opted out by its owner, the annotation is theirs to remove
+					kept = append(kept, target)
+					continue
+				default:
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				if _, err := client.Patch(obj.GetName(), types.MergePatchType, patch, metav1.PatchOptions{FieldManager: fieldManager}); err != nil {
+					return errors.Wrapf(err, "unable to change the injection exclusion of %s", target)
+				}
+				changed = append(changed, target)
+			}
+			progressed(ctx)
+		}
+	}
+
+	summary := fmt.Sprintf("Excluded %d workload(s) from sidecar injection", len(changed))
+	details := "Their pods are replaced without the Octarine sidecar as they roll out."
+	if arReq.GetDeleteOp() {
+		summary = fmt.Sprintf("Included %d workload(s) in sidecar injection again", len(changed))
+		details = "Their pods get the Octarine sidecar as they roll out."
+	}
+	if len(changed) == 0 {
+		details = fmt.Sprintf("No workload matching %s needed a change.", params.Selector)
+	} else {
+		details = strings.Join(changed, "\n") + "\n" + details
+	}
+	if len(kept) > 0 {
+		details += fmt.Sprintf("\nKept out of injection, their owners annotated them %s=%s: %s",
+			injectOptOutAnnotation, injectOptOutValue, strings.Join(kept, ", "))
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     summary,
+		Details:     details,
+	}
+	return nil
+}
+
+// syncInjectionExclusions labels the pod templates of the workloads annotated out of injection in a namespace,
+// so the annotations users add themselves are honoured by the webhook
+func (oClient *Client) syncInjectionExclusions(ctx context.Context, namespace string) {
+	synced := []string{}
+	for _, kind := range exclusionKindNames() {
+		client := oClient.dynamicClient(ctx).Resource(exclusionKinds[kind]).Namespace(namespace)
+		list, err := client.List(metav1.ListOptions{})
+		if err != nil {
+			logrus.Warnf("Unable to look for workloads opted out of injection in namespace %s: %v", namespace, err)
+			return
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			value, _, _ := unstructured.NestedString(obj.Object, append(templateMetadataPath(obj.GetKind()), "labels", injectOptOutLabel)...)
+			if !optedOut(obj) || value == injectOptOutValue {
+				continue
+			}
+			patch, err := exclusionPatch(obj.GetKind(), patchAnnotations(ctx, nil), injectOptOutValue)
+			if err == nil {
+				_, err = client.Patch(obj.GetName(), types.MergePatchType, patch, metav1.PatchOptions{FieldManager: fieldManager})
+			}
+			target := changeTarget{kind: obj.GetKind(), namespace: namespace, name: obj.GetName()}.String()
+			if err != nil {
+				logrus.Warnf("Unable to opt %s out of injection: %v", target, err)
+				continue
+			}
+			synced = append(synced, target)
+		}
+	}
+	if len(synced) == 0 {
+		return
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: operationIDFrom(ctx),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Kept %d annotated workload(s) of namespace %s out of injection", len(synced), namespace),
+		Details: fmt.Sprintf("%s are annotated %s=%s, their pod templates were labeled for the webhook to skip them.",
+			strings.Join(synced, ", "), injectOptOutAnnotation, injectOptOutValue),
+	}
+}
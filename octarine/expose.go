@@ -0,0 +1,558 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	exposeRouteIngress = "ingress"
+	exposeRouteGateway = "gateway"
+
+	// the sources of the certificate of the exposed host: a Secret of the adapter, an existing Secret of the
+	// dataplane namespace, or an Issuer or ClusterIssuer of cert-manager
+	exposeTLSSelfSigned    = "self-signed"
+	exposeTLSSecret        = "secret"
+	exposeTLSIssuer        = "issuer"
+	exposeTLSClusterIssuer = "cluster-issuer"
+
+	exposeName = "octarine-expose"
+
+	// load balancers take a while to be provisioned, the exposure warns when it got no address by then
+	exposeAddressTimeout      = time.Minute
+	exposeAddressPollInterval = 5 * time.Second
+	selfSignedValidity        = 365 * 24 * time.Hour
+)
+
+// defaultExposedServices are the Octarine services exposed when the custom body names none
+var defaultExposedServices = []string{"dashboard", "api"}
+
+var (
+	ingressResource = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}
+	gatewayGroup    = "gateway.networking.k8s.io"
+	// gatewayVersions are the versions of the Gateway API the exposure works with, preferred first
+	gatewayVersions = []string{"v1", "v1beta1"}
+)
+
+// exposure is how the services of a deployment are exposed
+type exposure struct {
+	host  string
+	route string
+	class string
+	// tls is the source of the certificate and secret the Secret holding it, or the name of the issuer
+	tls    string
+	secret string
+	issuer string
+	// backends are the Services exposed, by the path they are routed from
+	backends []exposedBackend
+}
+
+type exposedBackend struct {
+	name    string
+	service string
+	port    int64
+	path    string
+}
+
+// parseExposeTLS reads the certificate source of the custom body, self-signed, secret:<name>,
+// issuer:<name> or cluster-issuer:<name>
+func parseExposeTLS(tls string) (string, string, error) {
+	if tls == "" || tls == exposeTLSSelfSigned {
+		return exposeTLSSelfSigned, "", nil
+	}
+	parts := strings.SplitN(tls, ":", 2)
+	if len(parts) == 2 && parts[1] != "" {
+		switch parts[0] {
+		case exposeTLSSecret, exposeTLSIssuer, exposeTLSClusterIssuer:
+			return parts[0], parts[1], nil
+		}
+	}
+	return "", "", fmt.Errorf("error: unknown tls %q, it is %s, %s:<name>, %s:<name> or %s:<name>", tls,
+		exposeTLSSelfSigned, exposeTLSSecret, exposeTLSIssuer, exposeTLSClusterIssuer)
+}
+
+// validateExposeParams checks the host, the route and the certificate source of an exposure, the host is only
+// required to expose the services, not to stop exposing them
+func validateExposeParams(params *deploymentParams, deleteOp bool) error {
+	if params.Host == "" && !deleteOp {
+		return fmt.Errorf("error: a host is required to expose the Octarine services")
+	}
+	if params.Host != "" {
+		if errs := validation.IsDNS1123Subdomain(params.Host); len(errs) > 0 {
+			return fmt.Errorf("error: host %q is not a valid host name: %s", params.Host, strings.Join(errs, "; "))
+		}
+	}
+	switch params.Route {
+	case "", exposeRouteIngress:
+	case exposeRouteGateway:
+		if params.Class == "" && !deleteOp {
+			return fmt.Errorf("error: a class, the GatewayClass of the Gateway, is required for a %s route", exposeRouteGateway)
+		}
+	default:
+		return fmt.Errorf("error: unknown route %q, known routes are %s and %s", params.Route, exposeRouteIngress, exposeRouteGateway)
+	}
+	_, _, err := parseExposeTLS(params.TLS)
+	return err
+}
+
+// exposedBackends looks up the Services of the dataplane to expose, by their name or the name of the
+// component ending it, e.g. dashboard for octarine-dashboard. The first is routed from /, the others from
+// their component name.
+func (oClient *Client) exposedBackends(ctx context.Context, d *deployment, names []string) ([]exposedBackend, error) {
+	if len(names) == 0 {
+		names = defaultExposedServices
+	}
+	list, err := oClient.clientset(ctx).CoreV1().Services(d.namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the services of namespace %s", d.namespace)
+	}
+	available := make([]string, 0, len(list.Items))
+	backends := []exposedBackend{}
+	for i, name := range names {
+		var found *corev1.Service
+		for j := range list.Items {
+			svc := &list.Items[j]
+			if svc.Name == name || strings.HasSuffix(svc.Name, "-"+name) {
+				found = svc
+				break
+			}
+		}
+		if found == nil {
+			for _, svc := range list.Items {
+				available = append(available, svc.Name)
+			}
+			return nil, fmt.Errorf("error: deployment %s has no %s service, the services of namespace %s are %s", d.name, name, d.namespace, strings.Join(available, ", "))
+		}
+		if len(found.Spec.Ports) == 0 {
+			return nil, fmt.Errorf("error: service %s/%s has no port to expose", d.namespace, found.Name)
+		}
+		port := found.Spec.Ports[0]
+		for _, p := range found.Spec.Ports {
+			if p.Name == "http" || p.Name == "https" {
+				port = p
+				break
+			}
+		}
+		path := "/"
+		if i > 0 {
+			path = "/" + name
+		}
+		backends = append(backends, exposedBackend{name: name, service: found.Name, port: int64(port.Port), path: path})
+	}
+	return backends, nil
+}
+
+// gatewayVersion is the version of the Gateway API served by the cluster
+func (oClient *Client) gatewayVersion(ctx context.Context) (string, error) {
+	for _, version := range gatewayVersions {
+		if _, err := oClient.clientset(ctx).Discovery().ServerResourcesForGroupVersion(gatewayGroup + "/" + version); err == nil {
+			return version, nil
+		}
+	}
+	return "", fmt.Errorf("error: the Gateway API isn't installed, %s/%s isn't served", gatewayGroup, gatewayVersions[0])
+}
+
+// exposedObject is an object of the exposure in the namespace of the deployment, owned by its anchor
+func (d *deployment) exposedObject(apiVersion, kind string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(resourceName(exposeName))
+	obj.SetNamespace(d.namespace)
+	obj.SetLabels(d.managedLabels())
+	if d.anchor != nil {
+		obj.SetOwnerReferences([]metav1.OwnerReference{*d.anchor})
+	}
+	return obj
+}
+
+// issuerAnnotations have cert-manager issue the certificate of the host into the Secret of the exposure
+func (e *exposure) issuerAnnotations(obj *unstructured.Unstructured) {
+	switch e.tls {
+	case exposeTLSIssuer:
+		obj.SetAnnotations(map[string]string{"cert-manager.io/issuer": e.issuer})
+	case exposeTLSClusterIssuer:
+		obj.SetAnnotations(map[string]string{"cert-manager.io/cluster-issuer": e.issuer})
+	}
+}
+
+func (d *deployment) exposureIngress(e *exposure) *unstructured.Unstructured {
+	ingress := d.exposedObject("networking.k8s.io/v1", "Ingress")
+	e.issuerAnnotations(ingress)
+	paths := []interface{}{}
+	for _, b := range e.backends {
+		paths = append(paths, map[string]interface{}{
+			"path":     b.path,
+			"pathType": "Prefix",
+			"backend": map[string]interface{}{
+				"service": map[string]interface{}{
+					"name": b.service,
+					"port": map[string]interface{}{"number": b.port},
+				},
+			},
+		})
+	}
+	spec := map[string]interface{}{
+		"tls": []interface{}{map[string]interface{}{
+			"hosts":      []interface{}{e.host},
+			"secretName": e.secret,
+		}},
+		"rules": []interface{}{map[string]interface{}{
+			"host": e.host,
+			"http": map[string]interface{}{"paths": paths},
+		}},
+	}
+	if e.class != "" {
+		spec["ingressClassName"] = e.class
+	}
+	ingress.Object["spec"] = spec
+	return ingress
+}
+
+// exposureGateway is a Gateway of the class terminating TLS for the host, and the HTTPRoute of the services
+// attached to it
+func (d *deployment) exposureGateway(e *exposure, version string) (*unstructured.Unstructured, *unstructured.Unstructured) {
+	apiVersion := gatewayGroup + "/" + version
+	gateway := d.exposedObject(apiVersion, "Gateway")
+	e.issuerAnnotations(gateway)
+	gateway.Object["spec"] = map[string]interface{}{
+		"gatewayClassName": e.class,
+		"listeners": []interface{}{map[string]interface{}{
+			"name":     "https",
+			"protocol": "HTTPS",
+			"port":     int64(443),
+			"hostname": e.host,
+			"tls": map[string]interface{}{
+				"mode":            "Terminate",
+				"certificateRefs": []interface{}{map[string]interface{}{"kind": "Secret", "name": e.secret}},
+			},
+			"allowedRoutes": map[string]interface{}{
+				"namespaces": map[string]interface{}{"from": "Same"},
+			},
+		}},
+	}
+
+	route := d.exposedObject(apiVersion, "HTTPRoute")
+	rules := []interface{}{}
+	for _, b := range e.backends {
+		rules = append(rules, map[string]interface{}{
+			"matches":     []interface{}{map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": b.path}}},
+			"backendRefs": []interface{}{map[string]interface{}{"name": b.service, "port": b.port}},
+		})
+	}
+	route.Object["spec"] = map[string]interface{}{
+		"parentRefs": []interface{}{map[string]interface{}{"name": gateway.GetName()}},
+		"hostnames":  []interface{}{e.host},
+		"rules":      rules,
+	}
+	return gateway, route
+}
+
+// ensureSelfSignedSecret creates the TLS Secret of a self-signed certificate for the host, kept as long as
+// it is for the same host
+func (oClient *Client) ensureSelfSignedSecret(ctx context.Context, d *deployment, name, host string) error {
+	secrets := oClient.clientset(ctx).CoreV1().Secrets(d.namespace)
+	live, err := secrets.Get(name, metav1.GetOptions{})
+	if err == nil && selfSignedFor(live, host) {
+		return nil
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "unable to read secret %s/%s", d.namespace, name)
+	}
+	certPEM, keyPEM, err := selfSignedCertificate(host)
+	if err != nil {
+		return err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: d.namespace, Labels: d.managedLabels()},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM},
+	}
+	if d.anchor != nil {
+		secret.OwnerReferences = []metav1.OwnerReference{*d.anchor}
+	}
+	if live != nil && live.Name != "" {
+		secret.ResourceVersion = live.ResourceVersion
+		_, err = secrets.Update(secret)
+	} else {
+		_, err = secrets.Create(secret)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "unable to write secret %s/%s", d.namespace, name)
+	}
+	return nil
+}
+
+// selfSignedFor tells whether a Secret holds a certificate for the host which isn't about to expire
+func selfSignedFor(secret *corev1.Secret, host string) bool {
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil {
+		return false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+	return cert.VerifyHostname(host) == nil && time.Until(cert.NotAfter) > selfSignedValidity/12
+}
+
+func selfSignedCertificate(host string) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to generate the key of the certificate of %s", host)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to generate the serial number of the certificate of %s", host)
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: host, Organization: []string{"Meshery Adapter for Octarine"}},
+		DNSNames:     []string{host},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(selfSignedValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to create the certificate of %s", host)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to write the key of the certificate of %s", host)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// exposureAddress is the address the controller of the route assigned it, empty until it did
+func exposureAddress(obj *unstructured.Unstructured) string {
+	if obj.GetKind() == "Gateway" {
+		addresses, _, _ := nestedObjects(obj.Object, "status", "addresses")
+		for _, a := range addresses {
+			if value, _, _ := unstructured.NestedString(a, "value"); value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+	ingresses, _, _ := nestedObjects(obj.Object, "status", "loadBalancer", "ingress")
+	for _, i := range ingresses {
+		for _, field := range []string{"hostname", "ip"} {
+			if value, _, _ := unstructured.NestedString(i, field); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// waitForExposureAddress waits a little for the controller of the route to assign it an address
+func (oClient *Client) waitForExposureAddress(ctx context.Context, res schema.GroupVersionResource, obj *unstructured.Unstructured) (string, error) {
+	deadline := time.Now().Add(exposeAddressTimeout)
+	for {
+		live, err := oClient.dynamicClient(ctx).Resource(res).Namespace(obj.GetNamespace()).Get(obj.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to read %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+		}
+		if address := exposureAddress(live); address != "" || time.Now().After(deadline) {
+			return address, nil
+		}
+		workingOn(ctx, "an address for %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(exposeAddressPollInterval):
+		}
+	}
+}
+
+// deleteExposure removes the route of either kind and the self-signed Secret of the exposure of a deployment
+func (oClient *Client) deleteExposure(ctx context.Context, d *deployment) error {
+	name := resourceName(exposeName)
+	resources := []schema.GroupVersionResource{ingressResource}
+	if version, err := oClient.gatewayVersion(ctx); err == nil {
+		resources = append(resources,
+			schema.GroupVersionResource{Group: gatewayGroup, Version: version, Resource: "httproutes"},
+			schema.GroupVersionResource{Group: gatewayGroup, Version: version, Resource: "gateways"})
+	}
+	for _, res := range resources {
+		err := oClient.dynamicClient(ctx).Resource(res).Namespace(d.namespace).Delete(name, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "unable to delete %s %s/%s", res.Resource, d.namespace, name)
+		}
+	}
+	err := oClient.clientset(ctx).CoreV1().Secrets(d.namespace).Delete(name, &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "unable to delete secret %s/%s", d.namespace, name)
+	}
+	return nil
+}
+
+// executeExpose exposes services of a deployment, by default its dashboard and API, on a host over HTTPS
+// through an Ingress or a Gateway API route. Deleting removes the route and the self-signed certificate.
+func (oClient *Client) executeExpose(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	if oClient.clientset(ctx) == nil || oClient.dynamicClient(ctx) == nil {
+		return fmt.Errorf("error: mesh client has not been created")
+	}
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	if err := validateExposeParams(params, arReq.GetDeleteOp()); err != nil {
+		return err
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return err
+	}
+
+	if arReq.GetDeleteOp() {
+		workingOn(ctx, "the exposure of deployment %s", d.name)
+		if err := oClient.deleteExposure(ctx, d); err != nil {
+			return err
+		}
+		progressed(ctx)
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: arReq.GetOperationId(),
+			EventType:   meshes.EventType_INFO,
+			Summary:     fmt.Sprintf("The services of deployment %s are no longer exposed", d.name),
+		}
+		return nil
+	}
+
+	e := &exposure{host: params.Host, route: params.Route, class: params.Class}
+	if e.route == "" {
+		e.route = exposeRouteIngress
+	}
+	var source string
+	e.tls, source, _ = parseExposeTLS(params.TLS)
+	e.secret = resourceName(exposeName)
+	switch e.tls {
+	case exposeTLSSecret:
+		e.secret = source
+		if _, err := oClient.clientset(ctx).CoreV1().Secrets(d.namespace).Get(e.secret, metav1.GetOptions{}); err != nil {
+			return errors.Wrapf(err, "unable to read the TLS secret %s/%s", d.namespace, e.secret)
+		}
+	case exposeTLSIssuer, exposeTLSClusterIssuer:
+		e.issuer = source
+		if _, err := oClient.clientset(ctx).Discovery().ServerResourcesForGroupVersion(certManagerVersion); err != nil {
+			return errors.Wrapf(err, "unable to find cert-manager to issue the certificate of %s, %s isn't served", e.host, certManagerVersion)
+		}
+	}
+	if e.backends, err = oClient.exposedBackends(ctx, d, params.Services); err != nil {
+		return err
+	}
+
+	workingOn(ctx, "the exposure of deployment %s at %s", d.name, e.host)
+	if e.tls == exposeTLSSelfSigned {
+		if err := oClient.ensureSelfSignedSecret(ctx, d, e.secret, e.host); err != nil {
+			return err
+		}
+	}
+	var addressed *unstructured.Unstructured
+	var addressedRes schema.GroupVersionResource
+	switch e.route {
+	case exposeRouteGateway:
+		version, err := oClient.gatewayVersion(ctx)
+		if err != nil {
+			return err
+		}
+		gateway, route := d.exposureGateway(e, version)
+		gatewayRes := schema.GroupVersionResource{Group: gatewayGroup, Version: version, Resource: "gateways"}
+		if err := oClient.applyClusterObject(ctx, gatewayRes, gateway); err != nil {
+			return err
+		}
+		if err := oClient.applyClusterObject(ctx, schema.GroupVersionResource{Group: gatewayGroup, Version: version, Resource: "httproutes"}, route); err != nil {
+			return err
+		}
+		addressed, addressedRes = gateway, gatewayRes
+	default:
+		if _, err := oClient.clientset(ctx).Discovery().ServerResourcesForGroupVersion(ingressResource.GroupVersion().String()); err != nil {
+			return errors.Wrapf(err, "unable to expose the services with an Ingress, %s isn't served", ingressResource.GroupVersion())
+		}
+		ingress := d.exposureIngress(e)
+		if err := oClient.applyClusterObject(ctx, ingressResource, ingress); err != nil {
+			return err
+		}
+		addressed, addressedRes = ingress, ingressResource
+	}
+	progressed(ctx)
+
+	routes := make([]string, 0, len(e.backends))
+	for _, b := range e.backends {
+		routes = append(routes, fmt.Sprintf("https://%s%s → %s:%d", e.host, b.path, b.service, b.port))
+	}
+	details := fmt.Sprintf("%s %s/%s routes %s, with a certificate from %s.", addressed.GetKind(), d.namespace, addressed.GetName(),
+		strings.Join(routes, ", "), describeExposeTLS(e))
+	address, err := oClient.waitForExposureAddress(ctx, addressedRes, addressed)
+	if err != nil {
+		return err
+	}
+	if address == "" {
+		controller := fmt.Sprintf("class %s", e.class)
+		if e.class == "" {
+			controller = "the default IngressClass"
+		}
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: arReq.GetOperationId(),
+			EventType:   meshes.EventType_WARN,
+			Summary:     fmt.Sprintf("The services of deployment %s are exposed at %s but have no address yet", d.name, e.host),
+			Details:     details + fmt.Sprintf(" No address was assigned within %s, check that a controller serves %s.", exposeAddressTimeout, controller),
+		}
+		return nil
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("The services of deployment %s are exposed at %s", d.name, e.host),
+		Details:     details + fmt.Sprintf(" Point %s to %s.", e.host, address),
+	}
+	return nil
+}
+
+func describeExposeTLS(e *exposure) string {
+	switch e.tls {
+	case exposeTLSSecret:
+		return "secret " + e.secret
+	case exposeTLSIssuer:
+		return "cert-manager issuer " + e.issuer
+	case exposeTLSClusterIssuer:
+		return "cert-manager cluster issuer " + e.issuer
+	}
+	return "a self-signed certificate in secret " + e.secret
+}
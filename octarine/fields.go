@@ -0,0 +1,57 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// The fields of unstructured objects are read and written with the unstructured.Nested* functions, which
+// return an error on a field of an unexpected type where a type assertion would panic. The helpers below
+// cover the lists of objects, such as containers, conditions and webhooks, the same way.
+
+// nestedObjects returns a copy of the objects of a list field, an error when the field isn't a list of
+// objects, found is false when the field is missing
+func nestedObjects(obj map[string]interface{}, fields ...string) ([]map[string]interface{}, bool, error) {
+	items, found, err := unstructured.NestedSlice(obj, fields...)
+	if !found || err != nil {
+		return nil, found, err
+	}
+	objects := make([]map[string]interface{}, 0, len(items))
+	for i, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, true, fmt.Errorf("%s[%d] accessor error: %v is of the type %T, expected map[string]interface{}", fieldPath(fields), i, item, item)
+		}
+		objects = append(objects, m)
+	}
+	return objects, true, nil
+}
+
+// setNestedObjects sets a list field to a list of objects, creating the objects of the path as needed
+func setNestedObjects(obj map[string]interface{}, objects []map[string]interface{}, fields ...string) error {
+	items := make([]interface{}, len(objects))
+	for i, o := range objects {
+		items[i] = o
+	}
+	return unstructured.SetNestedSlice(obj, items, fields...)
+}
+
+func fieldPath(fields []string) string {
+	return "." + strings.Join(fields, ".")
+}
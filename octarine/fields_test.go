@@ -0,0 +1,123 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNestedObjects(t *testing.T) {
+	container := map[string]interface{}{"name": "app"}
+	tests := []struct {
+		name    string
+		obj     map[string]interface{}
+		want    []map[string]interface{}
+		found   bool
+		wantErr bool
+	}{
+		{
+			name: "missing",
+			obj:  map[string]interface{}{"spec": map[string]interface{}{}},
+		},
+		{
+			name:  "objects",
+			obj:   map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{container}}},
+			want:  []map[string]interface{}{container},
+			found: true,
+		},
+		{
+			name:  "empty",
+			obj:   map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{}}},
+			want:  []map[string]interface{}{},
+			found: true,
+		},
+		{
+			name:    "not a list",
+			obj:     map[string]interface{}{"spec": map[string]interface{}{"containers": "app"}},
+			wantErr: true,
+		},
+		{
+			name:    "list of scalars",
+			obj:     map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{container, "app"}}},
+			found:   true,
+			wantErr: true,
+		},
+		{
+			name:    "parent not an object",
+			obj:     map[string]interface{}{"spec": int64(3)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found, err := nestedObjects(tt.obj, "spec", "containers")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("nestedObjects() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if found != tt.found {
+				t.Errorf("nestedObjects() found = %v, want %v", found, tt.found)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nestedObjects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetNestedObjects(t *testing.T) {
+	objects := []map[string]interface{}{{"name": "app"}, {"name": "sidecar"}}
+	tests := []struct {
+		name    string
+		obj     map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "missing path",
+			obj:  map[string]interface{}{},
+		},
+		{
+			name: "replaces the list",
+			obj:  map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{"stale"}}},
+		},
+		{
+			name: "replaces a scalar",
+			obj:  map[string]interface{}{"spec": map[string]interface{}{"containers": "stale"}},
+		},
+		{
+			name:    "parent not an object",
+			obj:     map[string]interface{}{"spec": "stale"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := setNestedObjects(tt.obj, objects, "spec", "containers")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setNestedObjects() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, _, err := nestedObjects(tt.obj, "spec", "containers")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, objects) {
+				t.Errorf("setNestedObjects() set %v, want %v", got, objects)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	footprintDeployment = "deployment %s"
+	footprintManifest   = "manifest"
+	footprintDefault    = "default estimate"
+	footprintRequest    = "request"
+)
+
+// the stock dataplane and sidecar requests, for estimates made before anything is installed
+var (
+	defaultDataplaneFootprint = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("600m"),
+		corev1.ResourceMemory: resource.MustParse("768Mi"),
+		corev1.ResourcePods:   resource.MustParse("3"),
+	}
+	defaultSidecarCPU    = resource.MustParse("100m")
+	defaultSidecarMemory = resource.MustParse("128Mi")
+)
+
+func footprintOf(cpu, memory resource.Quantity, pods int64) *meshes.Footprint {
+	return &meshes.Footprint{Cpu: cpu.String(), Memory: memory.String(), Pods: int32(pods)}
+}
+
+// demandFootprint is the CPU and memory requests and the pods of a quota demand
+func demandFootprint(demand *quotaDemand) *meshes.Footprint {
+	pods := demand.resources[corev1.ResourcePods]
+	return footprintOf(demand.resources["requests."+corev1.ResourceCPU], demand.resources["requests."+corev1.ResourceMemory], pods.Value())
+}
+
+// runningDataplaneFootprint sums the requests of the dataplane workloads of an installed deployment
+func (oClient *Client) runningDataplaneFootprint(ctx context.Context, d *deployment) (*meshes.Footprint, error) {
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", deploymentNameLabel, d.name)}
+	apps := oClient.clientset(ctx).AppsV1()
+	demand := newQuotaDemand()
+	depls, err := apps.Deployments(d.namespace).List(opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the dataplane of deployment %s", d.name)
+	}
+	for _, w := range depls.Items {
+		replicas := int64(1)
+		if w.Spec.Replicas != nil {
+			replicas = int64(*w.Spec.Replicas)
+		}
+		demand.addPod("deployment/"+w.Name, w.Spec.Template.Spec, replicas, nil)
+	}
+	daemonSets, err := apps.DaemonSets(d.namespace).List(opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the dataplane of deployment %s", d.name)
+	}
+	for _, w := range daemonSets.Items {
+		demand.addPod("daemonset/"+w.Name, w.Spec.Template.Spec, int64(w.Status.DesiredNumberScheduled), nil)
+	}
+	return demandFootprint(demand), nil
+}
+
+// manifestFootprint sums the requests of the workloads of a rendered dataplane manifest
+func (oClient *Client) manifestFootprint(ctx context.Context, manifest string) (*meshes.Footprint, error) {
+	objects, err := parseManifestObjects(manifest)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := oClient.clientset(ctx).CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the nodes")
+	}
+	demand := newQuotaDemand()
+	for _, data := range objects {
+		podSpec, found, _ := unstructured.NestedMap(data.Object, podSpecPath(data.GetKind())...)
+		if !found {
+			continue
+		}
+		spec := corev1.PodSpec{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(podSpec, &spec); err != nil {
+			return nil, errors.Wrapf(err, "unable to read the pod spec of %s %s", data.GetKind(), data.GetName())
+		}
+		demand.addPod(strings.ToLower(data.GetKind())+"/"+data.GetName(), spec, workloadReplicas(data, int64(len(nodes.Items))), nil)
+	}
+	return demandFootprint(demand), nil
+}
+
+// sidecarContainer finds the container the injection added to a pod, the one from the repositories of the dataplane
+func sidecarContainer(pod corev1.Pod, prefixes map[string]bool) *corev1.Container {
+	for i, c := range pod.Spec.Containers {
+		if prefixes[imageRepoPrefix(c.Image)] {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	return nil
+}
+
+// EstimateFootprint estimates the requests of an Octarine installation: its dataplane and a sidecar for every
+// running pod of the injected namespaces, to plan the capacity it needs before installing or injecting more
+func (oClient *Client) EstimateFootprint(ctx context.Context, req *meshes.EstimateFootprintRequest) (*meshes.EstimateFootprintResponse, error) {
+	if oClient.clientset(ctx) == nil {
+		return &meshes.EstimateFootprintResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	resp, err := oClient.estimateFootprint(ctx, req)
+	if err != nil {
+		logrus.Error(err)
+		return &meshes.EstimateFootprintResponse{Error: err.Error()}, nil
+	}
+	return resp, nil
+}
+
+func (oClient *Client) estimateFootprint(ctx context.Context, req *meshes.EstimateFootprintRequest) (*meshes.EstimateFootprintResponse, error) {
+	resp := &meshes.EstimateFootprintResponse{}
+	var d *deployment
+	var prefixes map[string]bool
+	if req.GetDeployment() != "" {
+		var err error
+		if d, err = oClient.getDeployment(req.GetDeployment()); err != nil {
+			return nil, err
+		}
+		if resp.ControlPlane, err = oClient.runningDataplaneFootprint(ctx, d); err != nil {
+			return nil, err
+		}
+		resp.ControlPlaneSource = fmt.Sprintf(footprintDeployment, d.name)
+		if _, prefixes, err = oClient.dataplaneImages(ctx, d); err != nil {
+			return nil, err
+		}
+	} else if strings.TrimSpace(req.GetManifest()) != "" {
+		var err error
+		if resp.ControlPlane, err = oClient.manifestFootprint(ctx, req.GetManifest()); err != nil {
+			return nil, err
+		}
+		resp.ControlPlaneSource = footprintManifest
+	} else {
+		pods := defaultDataplaneFootprint[corev1.ResourcePods]
+		resp.ControlPlane = footprintOf(defaultDataplaneFootprint[corev1.ResourceCPU], defaultDataplaneFootprint[corev1.ResourceMemory], pods.Value())
+		resp.ControlPlaneSource = footprintDefault
+	}
+
+	namespaces := map[string]bool{}
+	for _, ns := range req.GetNamespaces() {
+		namespaces[ns] = true
+	}
+	selector := injectionLabel
+	if d != nil {
+		selector = fmt.Sprintf("%s=%s", injectionLabel, d.injectionValue())
+	}
+	labeled, err := oClient.clientset(ctx).CoreV1().Namespaces().List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list namespaces labeled for injection")
+	}
+	for _, ns := range labeled.Items {
+		namespaces[ns.Name] = true
+	}
+
+	sidecarCPU, sidecarMemory := defaultSidecarCPU, defaultSidecarMemory
+	resp.SidecarSource = footprintDefault
+	measured := false
+	type namespacePods struct {
+		name     string
+		pods     int64
+		injected int64
+	}
+	counted := []namespacePods{}
+	for _, ns := range sortedKeys(namespaces) {
+		pods, err := oClient.clientset(ctx).CoreV1().Pods(ns).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to list the pods of namespace %s", ns)
+		}
+		n := namespacePods{name: ns}
+		for _, pod := range pods.Items {
+			if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			sidecar := sidecarContainer(pod, prefixes)
+			if sidecar == nil {
+				n.pods++
+				continue
+			}
+			n.injected++
+			if !measured {
+				// the requests of a running sidecar beat the defaults, they include what the deployment configured
+				if cpu, ok := sidecar.Resources.Requests[corev1.ResourceCPU]; ok {
+					sidecarCPU = cpu
+				}
+				if memory, ok := sidecar.Resources.Requests[corev1.ResourceMemory]; ok {
+					sidecarMemory = memory
+				}
+				resp.SidecarSource = fmt.Sprintf("pod %s/%s", ns, pod.Name)
+				measured = true
+			}
+		}
+		counted = append(counted, n)
+	}
+	if req.GetSidecarCpu() != "" || req.GetSidecarMemory() != "" {
+		resp.SidecarSource = footprintRequest
+	}
+	if req.GetSidecarCpu() != "" {
+		if sidecarCPU, err = resource.ParseQuantity(req.GetSidecarCpu()); err != nil {
+			return nil, fmt.Errorf("error: sidecar_cpu %q is not a quantity: %v", req.GetSidecarCpu(), err)
+		}
+	}
+	if req.GetSidecarMemory() != "" {
+		if sidecarMemory, err = resource.ParseQuantity(req.GetSidecarMemory()); err != nil {
+			return nil, fmt.Errorf("error: sidecar_memory %q is not a quantity: %v", req.GetSidecarMemory(), err)
+		}
+	}
+	resp.Sidecar = footprintOf(sidecarCPU, sidecarMemory, 0)
+
+	totalCPU := resource.MustParse(resp.ControlPlane.Cpu)
+	totalMemory := resource.MustParse(resp.ControlPlane.Memory)
+	for _, n := range counted {
+		cpu, memory := resource.Quantity{}, resource.Quantity{}
+		for i := int64(0); i < n.pods; i++ {
+			cpu.Add(sidecarCPU)
+			memory.Add(sidecarMemory)
+		}
+		totalCPU.Add(cpu)
+		totalMemory.Add(memory)
+		resp.Namespaces = append(resp.Namespaces, &meshes.NamespaceFootprint{
+			Namespace: n.name,
+			Pods:      int32(n.pods),
+			Injected:  int32(n.injected),
+			Sidecars:  footprintOf(cpu, memory, 0),
+		})
+	}
+	resp.Total = footprintOf(totalCPU, totalMemory, int64(resp.ControlPlane.Pods))
+	return resp, nil
+}
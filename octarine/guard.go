@@ -0,0 +1,128 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	bulkDeleteLimitEnv    = "OCTARINE_BULK_DELETE_LIMIT"
+	bulkNamespaceLimitEnv = "OCTARINE_BULK_NAMESPACE_LIMIT"
+
+	defaultBulkDeleteLimit    = 25
+	defaultBulkNamespaceLimit = 3
+
+	// the summary of a bulk change lists this many resources
+	maxSummarizedResources = 50
+)
+
+// changeTarget is a resource an operation is about to apply or delete
+type changeTarget struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+func (t changeTarget) String() string {
+	if t.namespace == "" {
+		return fmt.Sprintf("%s %s", t.kind, t.name)
+	}
+	return fmt.Sprintf("%s %s/%s", t.kind, t.namespace, t.name)
+}
+
+func intFromEnv(name string, def int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		logrus.Warnf("ignoring invalid number %q in %s, using %d", value, name, def)
+		return def
+	}
+	return n
+}
+
+// manifestTargets lists the objects of a manifest the way executeManifest applies them
+func manifestTargets(manifest, namespace string) ([]changeTarget, error) {
+	objects, err := parseManifestObjects(manifest)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]changeTarget, 0, len(objects))
+	for _, obj := range objects {
+		ns := obj.GetNamespace()
+		if namespace != "" {
+			ns = namespace
+		}
+		targets = append(targets, changeTarget{kind: obj.GetKind(), namespace: ns, name: obj.GetName()})
+	}
+	return targets, nil
+}
+
+// guardBulkChange stops a custom operation deleting more resources, or touching more namespaces, than the
+// limits unless the request forces it, so a mistaken manifest or selector can't wipe out a cluster. Either
+// way a change over the limits is summarized in an event first.
+func (oClient *Client) guardBulkChange(arReq *meshes.ApplyRuleRequest, targets []changeTarget, delete bool) error {
+	deleteLimit := intFromEnv(bulkDeleteLimitEnv, defaultBulkDeleteLimit)
+	namespaceLimit := intFromEnv(bulkNamespaceLimitEnv, defaultBulkNamespaceLimit)
+	namespaces := map[string]bool{}
+	for _, t := range targets {
+		if t.namespace != "" {
+			namespaces[t.namespace] = true
+		}
+	}
+	if (!delete || len(targets) <= deleteLimit) && len(namespaces) <= namespaceLimit {
+		return nil
+	}
+
+	verb := "apply"
+	if delete {
+		verb = "delete"
+	}
+	lines := []string{"Namespaces: " + strings.Join(sortedKeys(namespaces), ", ")}
+	for i, t := range targets {
+		if i == maxSummarizedResources {
+			lines = append(lines, fmt.Sprintf("and %d more", len(targets)-maxSummarizedResources))
+			break
+		}
+		lines = append(lines, t.String())
+	}
+	event := &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Operation %s would %s %d resource(s) across %d namespace(s)", arReq.GetOpName(), verb, len(targets), len(namespaces)),
+		Details:     strings.Join(lines, "\n"),
+	}
+	if !arReq.GetForce() {
+		event.EventType = meshes.EventType_WARN
+		event.Details = fmt.Sprintf("Nothing was changed, this is over the limits of %d deleted resources and %d namespaces. Run the operation again with force set to proceed.\n%s",
+			deleteLimit, namespaceLimit, event.Details)
+	}
+	oClient.eventChan <- event
+	if !arReq.GetForce() {
+		return fmt.Errorf("error: operation %s would %s %d resource(s) across %d namespace(s), over the limits of %d deleted resources and %d namespaces; set force to proceed",
+			arReq.GetOpName(), verb, len(targets), len(namespaces), deleteLimit, namespaceLimit)
+	}
+	logrus.Warnf("Operation %s is forced to %s %d resource(s) across %d namespace(s)", arReq.GetOperationId(), verb, len(targets), len(namespaces))
+	return nil
+}
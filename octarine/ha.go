@@ -0,0 +1,209 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// maxDataplaneReplicas bounds the replicas of the components of a highly available dataplane
+	maxDataplaneReplicas = 10
+	// antiAffinityWeight prefers spreading the replicas of a component over nodes, a required anti-affinity
+	// would leave replicas pending on clusters with fewer nodes
+	antiAffinityWeight = 100
+	hostnameTopology   = "kubernetes.io/hostname"
+)
+
+// haKinds are the dataplane workloads which get replicas, DaemonSets already run on every node
+var haKinds = map[string]bool{"Deployment": true, "StatefulSet": true}
+
+// validateReplicas checks the replicas of the components of a new dataplane, 2 or more make it highly available
+func validateReplicas(replicas int) error {
+	if replicas < 0 || replicas > maxDataplaneReplicas {
+		return fmt.Errorf("error: replicas must be between 1 and %d, got %d", maxDataplaneReplicas, replicas)
+	}
+	return nil
+}
+
+// highlyAvailable tells whether the components of the dataplane of a deployment run several replicas
+func (d *deployment) highlyAvailable() bool {
+	return d.replicas > 1
+}
+
+// highAvailabilityManifest runs the components of a dataplane manifest with the replicas of the deployment,
+// spread over the nodes, and adds a PodDisruptionBudget for each so draining nodes keeps one replica up
+func (d *deployment) highAvailabilityManifest(manifest string) (string, error) {
+	budgets := []string{}
+	scaled, err := transformManifest(manifest, func(obj *unstructured.Unstructured) error {
+		if !haKinds[obj.GetKind()] {
+			return nil
+		}
+		if replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); !found || replicas < int64(d.replicas) {
+			if err := unstructured.SetNestedField(obj.Object, int64(d.replicas), "spec", "replicas"); err != nil {
+				return err
+			}
+		}
+		selector, found, err := unstructured.NestedMap(obj.Object, "spec", "selector")
+		if err != nil || !found {
+			return err
+		}
+		terms, _, err := nestedObjects(obj.Object, "spec", "template", "spec", "affinity", "podAntiAffinity", "preferredDuringSchedulingIgnoredDuringExecution")
+		if err != nil {
+			return err
+		}
+		terms = append(terms, map[string]interface{}{
+			"weight": int64(antiAffinityWeight),
+			"podAffinityTerm": map[string]interface{}{
+				"labelSelector": selector,
+				"topologyKey":   hostnameTopology,
+			},
+		})
+		if err := setNestedObjects(obj.Object, terms, "spec", "template", "spec", "affinity", "podAntiAffinity", "preferredDuringSchedulingIgnoredDuringExecution"); err != nil {
+			return err
+		}
+		budget, err := disruptionBudget(obj, selector)
+		if err != nil {
+			return err
+		}
+		budgets = append(budgets, budget)
+		return nil
+	})
+	if err != nil || len(budgets) == 0 {
+		return scaled, err
+	}
+	return scaled + "\n---\n" + strings.Join(budgets, "\n---\n"), nil
+}
+
+// disruptionBudget is the PodDisruptionBudget of a dataplane workload, letting one of its replicas go at a time
+func disruptionBudget(obj *unstructured.Unstructured, selector map[string]interface{}) (string, error) {
+	budget := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "policy/v1beta1",
+		"kind":       "PodDisruptionBudget",
+		"spec": map[string]interface{}{
+			"maxUnavailable": int64(1),
+			"selector":       selector,
+		},
+	}}
+	budget.SetName(obj.GetName())
+	budget.SetNamespace(obj.GetNamespace())
+	out, err := yaml.Marshal(budget.Object)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to write the PodDisruptionBudget of %s %s", obj.GetKind(), obj.GetName())
+	}
+	return string(out), nil
+}
+
+// reapplyDataplane renders the dataplane of a changed deployment again and applies it. Going back from
+// several replicas to one deletes the PodDisruptionBudgets, they would block draining the node of the last one.
+func (oClient *Client) reapplyDataplane(ctx context.Context, d *deployment, previous int) error {
+	dataplaneYaml, err := oClient.getOctarineYAMLs(ctx, d)
+	if err != nil {
+		return err
+	}
+	if err := oClient.applyConfigChange(ctx, dataplaneYaml, d.namespace, false); err != nil {
+		return err
+	}
+	if err := oClient.restoreSidecarResources(ctx, d); err != nil {
+		return err
+	}
+	if d.highlyAvailable() || previous <= 1 {
+		return nil
+	}
+	budgets := oClient.clientset(ctx).PolicyV1beta1().PodDisruptionBudgets(d.namespace)
+	list, err := budgets.List(metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", deploymentNameLabel, d.name)})
+	if err != nil {
+		return errors.Wrapf(err, "unable to list the PodDisruptionBudgets of deployment %s", d.name)
+	}
+	for _, budget := range list.Items {
+		workingOn(ctx, "deleting PodDisruptionBudget %s/%s", d.namespace, budget.Name)
+		if err := budgets.Delete(budget.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "unable to delete PodDisruptionBudget %s/%s", d.namespace, budget.Name)
+		}
+		progressed(ctx)
+	}
+	return nil
+}
+
+// highAvailabilityCheck is the vet check of the HA posture of a dataplane: every component runs several ready
+// replicas on different nodes and a PodDisruptionBudget covers it. It only gates enforcement for deployments
+// installed highly available.
+func (oClient *Client) highAvailabilityCheck(ctx context.Context, d *deployment, depls []appsv1.Deployment) vetCheck {
+	check := vetCheck{
+		name:     "high availability",
+		severity: severityMedium,
+		advisory: !d.highlyAvailable(),
+	}
+	budgets, err := oClient.clientset(ctx).PolicyV1beta1().PodDisruptionBudgets(d.namespace).List(metav1.ListOptions{})
+	if err != nil {
+		check.skipped = fmt.Sprintf("unable to list the PodDisruptionBudgets of namespace %s: %v", d.namespace, err)
+		return check
+	}
+	problems := []string{}
+	for _, depl := range depls {
+		replicas := int32(1)
+		if depl.Spec.Replicas != nil {
+			replicas = *depl.Spec.Replicas
+		}
+		if replicas < 2 {
+			problems = append(problems, fmt.Sprintf("%s runs %d replica(s)", depl.Name, replicas))
+			continue
+		}
+		if depl.Status.ReadyReplicas < 2 {
+			problems = append(problems, fmt.Sprintf("%s has %d of %d replicas ready", depl.Name, depl.Status.ReadyReplicas, replicas))
+		}
+		selector, err := metav1.LabelSelectorAsSelector(depl.Spec.Selector)
+		if err != nil {
+			continue
+		}
+		pods, err := oClient.clientset(ctx).CoreV1().Pods(d.namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+		if err == nil {
+			nodes := map[string]bool{}
+			for _, pod := range pods.Items {
+				if pod.Spec.NodeName != "" {
+					nodes[pod.Spec.NodeName] = true
+				}
+			}
+			if len(nodes) < 2 {
+				problems = append(problems, fmt.Sprintf("%s has its replicas on %d node(s)", depl.Name, len(nodes)))
+			}
+		}
+		covered := false
+		for _, budget := range budgets.Items {
+			budgetSelector, err := metav1.LabelSelectorAsSelector(budget.Spec.Selector)
+			if err == nil && !budgetSelector.Empty() && budgetSelector.Matches(labels.Set(depl.Spec.Template.Labels)) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			problems = append(problems, fmt.Sprintf("%s has no PodDisruptionBudget", depl.Name))
+		}
+	}
+	if len(problems) > 0 {
+		check.failure = strings.Join(problems, ", ")
+	}
+	return check
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+)
+
+// AdapterVersion is the version of the adapter, set at build time with
+// -ldflags "-X github.com/layer5io/meshery-octarine/octarine.AdapterVersion=<version>"
+var AdapterVersion = "dev"
+
+// descriptorVersion is the version of the capability descriptor, bump it when the meaning of a field changes
+const descriptorVersion = 1
+
+var (
+	streamingFeatures = []string{
+		// StreamEvents over gRPC, and as server-sent events on /api/v1/events of the HTTP gateway
+		"events_grpc",
+		"events_sse",
+		// events carry the id of the operation they belong to
+		"operation_ids",
+		// long operations report their progress, and warn when they stall
+		"progress",
+		"stall_warnings",
+	}
+	authModes = []string{
+		"kubeconfig",
+		"kubeconfig_context",
+		"exec_plugin",
+		"oidc",
+		"bearer_token",
+		"service_account",
+	}
+)
+
+// meshServiceRPCs lists the rpcs of the MeshService, all of which the adapter implements
+func meshServiceRPCs() []string {
+	t := reflect.TypeOf((*meshes.MeshServiceServer)(nil)).Elem()
+	rpcs := make([]string, 0, t.NumMethod())
+	for i := 0; i < t.NumMethod(); i++ {
+		rpcs = append(rpcs, t.Method(i).Name)
+	}
+	sort.Strings(rpcs)
+	return rpcs
+}
+
+// AdapterCapabilities describes what the adapter offers, so Meshery can feature-detect it instead of
+// assuming a fixed API surface. It doesn't need a mesh instance.
+func (oClient *Client) AdapterCapabilities(ctx context.Context, _ *meshes.AdapterCapabilitiesRequest) (*meshes.AdapterCapabilitiesResponse, error) {
+	// far fewer operations than a page holds, one page lists them all, including those the installed release
+	// or the licensed features don't support yet
+	ops, err := oClient.SupportedOperations(ctx, &meshes.SupportedOperationsRequest{PageSize: maxPageSize, IncludeUnavailable: true})
+	if err != nil {
+		return &meshes.AdapterCapabilitiesResponse{Error: err.Error()}, nil
+	}
+	opsMu.RLock()
+	disabled := make([]string, 0, len(disabledOps))
+	for key := range disabledOps {
+		disabled = append(disabled, key)
+	}
+	opsMu.RUnlock()
+	sort.Strings(disabled)
+	name, _ := oClient.MeshName(ctx, &meshes.MeshNameRequest{})
+	return &meshes.AdapterCapabilitiesResponse{
+		Name:               name.GetName(),
+		Version:            AdapterVersion,
+		DescriptorVersion:  descriptorVersion,
+		Rpcs:               meshServiceRPCs(),
+		Operations:         ops.Ops,
+		DisabledOperations: disabled,
+		Streaming:          streamingFeatures,
+		MultiCluster:       true,
+		AuthModes:          authModes,
+		Clusters:           oClient.clusterConnections(),
+	}, nil
+}
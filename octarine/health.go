@@ -0,0 +1,180 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	checkPass    = "pass"
+	checkFail    = "fail"
+	checkSkipped = "skipped"
+)
+
+// requiredEnvVars must be set for the install operation to work
+var requiredEnvVars = []string{
+	"OCTARINE_CP",
+	"OCTARINE_DOMAIN",
+	"OCTARINE_ACC_MGR_PASSWD",
+	"OCTARINE_CREATOR_PASSWD",
+	"OCTARINE_DELETER_PASSWD",
+}
+
+type checkResult struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type healthReport struct {
+	Status string        `json:"status"`
+	Checks []checkResult `json:"checks"`
+}
+
+type healthCheck struct {
+	name string
+	run  func() (string, string)
+}
+
+func runChecks(checks []healthCheck) (*healthReport, bool) {
+	report := &healthReport{Status: checkPass}
+	healthy := true
+	for _, check := range checks {
+		start := time.Now()
+		status, message := check.run()
+		report.Checks = append(report.Checks, checkResult{
+			Name:     check.name,
+			Status:   status,
+			Message:  message,
+			Duration: time.Since(start).String(),
+		})
+		if status == checkFail {
+			healthy = false
+			report.Status = checkFail
+		}
+	}
+	return report, healthy
+}
+
+func (oClient *Client) checkEventBroker() (string, string) {
+	if oClient.eventChan == nil || oClient.events == nil {
+		return checkSkipped, "no mesh instance has been created yet"
+	}
+	// the broker drains the queue, it only fills up when the broker is stuck
+	if len(oClient.eventChan) == cap(oClient.eventChan) {
+		return checkFail, fmt.Sprintf("event queue is full (%d events), events aren't dispatched", cap(oClient.eventChan))
+	}
+	subscribers, backlog, dropped := oClient.events.stats()
+	return checkPass, fmt.Sprintf("%d stream(s) open, %d event(s) kept for the next stream, %d dropped", subscribers, backlog, dropped)
+}
+
+func (oClient *Client) checkKubeConnectivity() (string, string) {
+	ctx := context.Background()
+	if oClient.clientset(ctx) == nil {
+		return checkSkipped, "no mesh instance has been created yet"
+	}
+	version, err := oClient.clientset(ctx).Discovery().ServerVersion()
+	if err != nil {
+		return checkFail, err.Error()
+	}
+	degraded := []string{}
+	for _, c := range oClient.clusterConnections() {
+		if c.GetState() != linkConnected {
+			degraded = append(degraded, fmt.Sprintf("%s is %s", clusterTitle(c.GetCluster()), c.GetState()))
+		}
+	}
+	if len(degraded) > 0 {
+		return checkPass, fmt.Sprintf("connected to Kubernetes %s, %s", version.GitVersion, strings.Join(degraded, ", "))
+	}
+	return checkPass, fmt.Sprintf("connected to Kubernetes %s", version.GitVersion)
+}
+
+func checkConfig() (string, string) {
+	missing := []string{}
+	for _, name := range requiredEnvVars {
+		if vaultCredentials != nil && vaultCredentials.providesEnv(name) {
+			continue
+		}
+		if os.Getenv(name) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return checkFail, fmt.Sprintf("missing environment variables: %s", strings.Join(missing, ", "))
+	}
+	if _, err := exec.LookPath("octactl"); err != nil {
+		return checkFail, err.Error()
+	}
+	return checkPass, ""
+}
+
+func checkTemplates() (string, string) {
+	root, release := useTemplatesDir()
+	defer release()
+	ops := supportedOpsSnapshot()
+	count := 0
+	for _, key := range sortedKeys(templateOps(ops)) {
+		if _, err := lintTemplateSets(root, key, ops[key].templateName); err != nil {
+			return checkFail, fmt.Sprintf("template of %s: %v", key, err)
+		}
+		count++
+	}
+	opsMu.RLock()
+	disabled := len(disabledOps)
+	opsMu.RUnlock()
+	if disabled > 0 {
+		return checkPass, fmt.Sprintf("%d template(s) loaded, %d operation(s) disabled", count, disabled)
+	}
+	return checkPass, fmt.Sprintf("%d template(s) loaded", count)
+}
+
+func serveReport(w http.ResponseWriter, checks []healthCheck) {
+	report, healthy := runChecks(checks)
+	w.Header().Set("Content-Type", "application/json")
+	if !healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// LivenessHandler reports whether the adapter process is serving requests, for use as a liveness probe.
+// Dependencies are left to the readiness probe, restarting the adapter wouldn't fix them.
+func (oClient *Client) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveReport(w, nil)
+	})
+}
+
+// ReadinessHandler reports whether the adapter can serve operations, for use as a readiness probe
+func (oClient *Client) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveReport(w, []healthCheck{
+			{name: "event-broker", run: oClient.checkEventBroker},
+			{name: "kubernetes", run: oClient.checkKubeConnectivity},
+			{name: "config", run: checkConfig},
+			{name: "vault", run: checkVault},
+			{name: "templates", run: checkTemplates},
+		})
+	})
+}
@@ -0,0 +1,246 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// podPrivileges lists what a pod may do beyond an unprivileged container, leaving out the containers of the
+// dataplane, whose sidecar and init container need to manage the network of the pod
+func podPrivileges(spec corev1.PodSpec, prefixes map[string]bool) []string {
+	privileges := map[string]bool{}
+	if spec.HostNetwork {
+		privileges["hostNetwork"] = true
+	}
+	if spec.HostPID {
+		privileges["hostPID"] = true
+	}
+	if spec.HostIPC {
+		privileges["hostIPC"] = true
+	}
+	podRoot := spec.SecurityContext != nil && spec.SecurityContext.RunAsUser != nil && *spec.SecurityContext.RunAsUser == 0
+	for _, v := range spec.Volumes {
+		if v.HostPath != nil {
+			privileges["hostPath "+v.HostPath.Path] = true
+		}
+	}
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, c := range containers {
+		if prefixes[imageRepoPrefix(c.Image)] {
+			continue
+		}
+		sc := c.SecurityContext
+		root := podRoot
+		if sc != nil && sc.RunAsUser != nil {
+			root = *sc.RunAsUser == 0
+		}
+		if root {
+			privileges[fmt.Sprintf("container %s runs as root", c.Name)] = true
+		}
+		if sc == nil {
+			continue
+		}
+		if sc.Privileged != nil && *sc.Privileged {
+			privileges[fmt.Sprintf("container %s is privileged", c.Name)] = true
+		}
+		if sc.AllowPrivilegeEscalation != nil && *sc.AllowPrivilegeEscalation {
+			privileges[fmt.Sprintf("container %s allows privilege escalation", c.Name)] = true
+		}
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				privileges[fmt.Sprintf("container %s adds %s", c.Name, capability)] = true
+			}
+		}
+	}
+	return sortedKeys(privileges)
+}
+
+func isPrivileged(spec corev1.PodSpec, prefixes map[string]bool) bool {
+	for _, c := range append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...) {
+		if !prefixes[imageRepoPrefix(c.Image)] && c.SecurityContext != nil && c.SecurityContext.Privileged != nil && *c.SecurityContext.Privileged {
+			return true
+		}
+	}
+	return false
+}
+
+// workloadIdentities reports the service accounts, images and privileges of the workloads of a namespace, and
+// whether their pods carry the sidecar of a deployment, which gives them their Octarine identity
+func (oClient *Client) workloadIdentities(ctx context.Context, namespace, deploymentName string) (*meshes.WorkloadIdentitiesResponse, error) {
+	oClient.deploymentsMu.Lock()
+	undeployed := deploymentName == "" && len(oClient.deployments) == 0
+	oClient.deploymentsMu.Unlock()
+	// without a deployment no workload has an identity, the rest of the report still holds
+	prefixes := map[string]bool{}
+	if !undeployed {
+		d, err := oClient.getDeployment(deploymentName)
+		if err != nil {
+			return nil, err
+		}
+		if _, prefixes, err = oClient.dataplaneImages(ctx, d); err != nil {
+			return nil, err
+		}
+	}
+
+	pods, err := oClient.clientset(ctx).CoreV1().Pods(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the pods of namespace %s", namespace)
+	}
+	accounts, err := oClient.clientset(ctx).CoreV1().ServiceAccounts(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the service accounts of namespace %s", namespace)
+	}
+	existing := map[string]*corev1.ServiceAccount{}
+	for i := range accounts.Items {
+		existing[accounts.Items[i].Name] = &accounts.Items[i]
+	}
+
+	workloads := map[workloadRef]*meshes.WorkloadIdentity{}
+	images := map[workloadRef]map[string]bool{}
+	privileges := map[workloadRef]map[string]bool{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed || pod.GetDeletionTimestamp() != nil {
+			continue
+		}
+		ref := oClient.podWorkload(ctx, pod)
+		w, ok := workloads[ref]
+		if !ok {
+			account := pod.Spec.ServiceAccountName
+			if account == "" {
+				account = "default"
+			}
+			automount := true
+			if pod.Spec.AutomountServiceAccountToken != nil {
+				automount = *pod.Spec.AutomountServiceAccountToken
+			} else if sa := existing[account]; sa != nil && sa.AutomountServiceAccountToken != nil {
+				automount = *sa.AutomountServiceAccountToken
+			}
+			w = &meshes.WorkloadIdentity{
+				Namespace:        ref.namespace,
+				Kind:             ref.kind,
+				Name:             ref.name,
+				ServiceAccount:   account,
+				AutomountToken:   automount,
+				OctarineIdentity: true,
+			}
+			workloads[ref] = w
+			images[ref] = map[string]bool{}
+			privileges[ref] = map[string]bool{}
+		}
+		w.Pods++
+		for _, c := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+			images[ref][c.Image] = true
+		}
+		for _, p := range podPrivileges(pod.Spec, prefixes) {
+			privileges[ref][p] = true
+		}
+		w.Privileged = w.Privileged || isPrivileged(pod.Spec, prefixes)
+		w.HostNetwork = w.HostNetwork || pod.Spec.HostNetwork
+		w.HostPid = w.HostPid || pod.Spec.HostPID
+		w.HostIpc = w.HostIpc || pod.Spec.HostIPC
+		// a workload only has its identity when every one of its pods is injected
+		if _, injected := sidecarVersion(pod, prefixes); !injected {
+			w.OctarineIdentity = false
+		}
+	}
+
+	resp := &meshes.WorkloadIdentitiesResponse{}
+	byAccount := map[string][]string{}
+	byRegistry := map[string]map[string]bool{}
+	for ref, w := range workloads {
+		w.Images = sortedKeys(images[ref])
+		w.Privileges = sortedKeys(privileges[ref])
+		byAccount[w.ServiceAccount] = append(byAccount[w.ServiceAccount], ref.kind+"/"+ref.name)
+		for _, image := range w.Images {
+			registry := parseImageRef(image).registry
+			if byRegistry[registry] == nil {
+				byRegistry[registry] = map[string]bool{}
+			}
+			byRegistry[registry][image] = true
+		}
+		resp.Workloads = append(resp.Workloads, w)
+	}
+	accountNames := map[string]bool{}
+	for name := range existing {
+		accountNames[name] = true
+	}
+	for name := range byAccount {
+		accountNames[name] = true
+	}
+	for _, name := range sortedKeys(accountNames) {
+		used := byAccount[name]
+		sort.Strings(used)
+		resp.ServiceAccounts = append(resp.ServiceAccounts, &meshes.ServiceAccountUsage{
+			Name:      name,
+			Workloads: used,
+			Exists:    existing[name] != nil,
+		})
+	}
+	registries := make([]string, 0, len(byRegistry))
+	for registry := range byRegistry {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+	for _, registry := range registries {
+		resp.Registries = append(resp.Registries, &meshes.RegistryUsage{Registry: registry, Images: sortedKeys(byRegistry[registry])})
+	}
+	return resp, nil
+}
+
+// WorkloadIdentities reports the security posture of the workloads of a namespace: the service accounts they
+// run as, privileged and host namespace pods, the images and registries in use and which workloads have an
+// Octarine identity, the material for tightening their policies
+func (oClient *Client) WorkloadIdentities(ctx context.Context, req *meshes.WorkloadIdentitiesRequest) (*meshes.WorkloadIdentitiesResponse, error) {
+	if oClient.clientset(ctx) == nil {
+		return &meshes.WorkloadIdentitiesResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	if req.GetNamespace() == "" {
+		return &meshes.WorkloadIdentitiesResponse{Error: "error: a namespace is required"}, nil
+	}
+	resp, err := oClient.workloadIdentities(ctx, req.GetNamespace(), req.GetDeployment())
+	if err != nil {
+		logrus.Error(err)
+		return &meshes.WorkloadIdentitiesResponse{Error: err.Error()}, nil
+	}
+	keys := make([]string, 0, len(resp.Workloads))
+	byKey := map[string]*meshes.WorkloadIdentity{}
+	for _, w := range resp.Workloads {
+		key := strings.Join([]string{w.Kind, w.Name}, "/")
+		keys = append(keys, key)
+		byKey[key] = w
+	}
+	sort.Strings(keys)
+	start, end, next, err := paginate(keys, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return &meshes.WorkloadIdentitiesResponse{Error: err.Error()}, nil
+	}
+	resp.Workloads = resp.Workloads[:0]
+	for _, key := range keys[start:end] {
+		resp.Workloads = append(resp.Workloads, byKey[key])
+	}
+	resp.NextPageToken = next
+	return resp, nil
+}
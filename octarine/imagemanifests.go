@@ -0,0 +1,220 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ImageManifests lists the platforms and digests of the images of an Octarine release, so they can be mirrored
+// to an air-gapped registry by digest. The images are those of the dataplane octactl renders for the version,
+// including the sidecar images the dataplane refers to, unless the request names them.
+func (oClient *Client) ImageManifests(ctx context.Context, req *meshes.ImageManifestsRequest) (*meshes.ImageManifestsResponse, error) {
+	images := req.GetImages()
+	version := req.GetVersion()
+	if len(images) == 0 {
+		d, err := oClient.releaseDeployment(ctx, req.GetDeployment(), version)
+		if err != nil {
+			return &meshes.ImageManifestsResponse{Version: version, Error: err.Error()}, nil
+		}
+		if version == "" {
+			version = d.version
+		}
+		if images, err = oClient.releaseImages(ctx, d); err != nil {
+			return &meshes.ImageManifestsResponse{Version: version, Error: err.Error()}, nil
+		}
+	}
+	resp := &meshes.ImageManifestsResponse{Version: version}
+	rc := newRegistryClient()
+	var common map[string]bool
+	for _, image := range images {
+		manifest := &meshes.ImageManifest{Image: image}
+		resp.Images = append(resp.Images, manifest)
+		index, err := rc.imageIndex(ctx, image)
+		if err != nil {
+			manifest.Error = err.Error()
+			continue
+		}
+		manifest.Digest = index.digest
+		manifest.MediaType = index.mediaType
+		available := map[string]bool{}
+		for _, p := range index.platforms {
+			name := platformVariantName(p)
+			manifest.Platforms = append(manifest.Platforms, &meshes.ImagePlatform{
+				Os:           p.os,
+				Architecture: p.arch,
+				Variant:      p.variant,
+				Digest:       p.digest,
+				Size_:        p.size,
+			})
+			if common == nil || common[name] {
+				available[name] = true
+			}
+		}
+		sortPlatforms(manifest.Platforms)
+		common = available
+	}
+	resp.CommonPlatforms = sortedKeys(common)
+	return resp, nil
+}
+
+// releaseDeployment is the deployment whose account renders the dataplane of a release: an installed
+// deployment or one using the bootstrapped namespace, the default one when no name is given
+func (oClient *Client) releaseDeployment(ctx context.Context, name, version string) (*deployment, error) {
+	installed, err := oClient.getDeployment(name)
+	if err != nil {
+		if name == "" {
+			name = defaultDeploymentName
+		}
+		d := &deployment{name: name, namespace: dataplaneNamespace(), version: version}
+		bootstrapped, err := oClient.useBootstrap(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		if !bootstrapped {
+			return nil, fmt.Errorf("error: deployment %s has no Octarine domain yet, bootstrap it with %s or name the images to look up", name, bootstrapCommand)
+		}
+		return d, nil
+	}
+	// the release is looked up without changing the deployment
+	d := *installed
+	if version != "" {
+		d.version = version
+	}
+	return &d, nil
+}
+
+// releaseImages lists the images of the dataplane of a deployment's release, including those its containers
+// and ConfigMaps refer to, like the sidecars the dataplane injects
+func (oClient *Client) releaseImages(ctx context.Context, d *deployment) ([]string, error) {
+	oClient.loadControlPlaneCredentials()
+	manifest, err := oClient.getOctarineDataplaneYAML(ctx, d)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to render the dataplane of Octarine %s", d.versionName())
+	}
+	images, err := releaseManifestImages(manifest)
+	if err != nil {
+		return nil, err
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("error: the dataplane of Octarine %s has no images", d.versionName())
+	}
+	return images, nil
+}
+
+// releaseManifestImages lists the container images of a dataplane manifest and the images its containers
+// and ConfigMaps refer to from the same repositories
+func releaseManifestImages(manifest string) ([]string, error) {
+	images, err := manifestImages(manifest)
+	if err != nil {
+		return nil, err
+	}
+	prefixes := map[string]bool{}
+	found := map[string]bool{}
+	for _, image := range images {
+		prefixes[imageRepoPrefix(image)] = true
+		found[image] = true
+	}
+	objects, err := parseManifestObjects(manifest)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objects {
+		for _, value := range referencedValues(obj) {
+			if looksLikeImage(value) && prefixes[imageRepoPrefix(value)] {
+				found[value] = true
+			}
+		}
+	}
+	return sortedKeys(found), nil
+}
+
+// referencedValues are the values of a manifest object which may name an image: the env and args of its
+// containers, the data of a ConfigMap
+func referencedValues(obj *unstructured.Unstructured) []string {
+	values := []string{}
+	if obj.GetKind() == "ConfigMap" {
+		data, _, _ := unstructured.NestedStringMap(obj.Object, "data")
+		for _, value := range data {
+			values = append(values, strings.Fields(value)...)
+		}
+		return values
+	}
+	path := podSpecPath(obj.GetKind())
+	if path == nil {
+		return nil
+	}
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _, _ := nestedObjects(obj.Object, append(append([]string{}, path...), field)...)
+		for _, container := range containers {
+			args, _, _ := unstructured.NestedStringSlice(container, "args")
+			for _, arg := range args {
+				// --sidecar-image=repo/image:tag
+				if i := strings.Index(arg, "="); i >= 0 {
+					arg = arg[i+1:]
+				}
+				values = append(values, arg)
+			}
+			env, _, _ := nestedObjects(container, "env")
+			for _, e := range env {
+				if value, _, _ := unstructured.NestedString(e, "value"); value != "" {
+					values = append(values, value)
+				}
+			}
+		}
+	}
+	return values
+}
+
+// looksLikeImage tells a tagged or pinned image reference apart from other values naming a path or a host
+func looksLikeImage(value string) bool {
+	if value == "" || strings.ContainsAny(value, " \t,;='\"") || strings.Contains(value, "://") {
+		return false
+	}
+	if strings.Contains(value, "@sha256:") {
+		return true
+	}
+	slash := strings.LastIndex(value, "/")
+	return slash > 0 && strings.LastIndex(value, ":") > slash
+}
+
+// platformVariantName names a platform the way image tools take it, os/arch[/variant]
+func platformVariantName(p platformManifest) string {
+	if p.variant == "" {
+		return p.String()
+	}
+	return p.String() + "/" + p.variant
+}
+
+// sortPlatforms orders the platforms of a manifest by name, as the registry lists them in no particular order
+func sortPlatforms(platforms []*meshes.ImagePlatform) {
+	sort.Slice(platforms, func(i, j int) bool {
+		a, b := platforms[i], platforms[j]
+		if a.GetOs() != b.GetOs() {
+			return a.GetOs() < b.GetOs()
+		}
+		if a.GetArchitecture() != b.GetArchitecture() {
+			return a.GetArchitecture() < b.GetArchitecture()
+		}
+		return a.GetVariant() < b.GetVariant()
+	})
+}
@@ -15,6 +15,7 @@
 package octarine
 
 import (
+	"context"
 	"io/ioutil"
 	"math/rand"
 	"os"
@@ -38,12 +39,21 @@ func randSeq(n int) string {
 	return string(b)
 }
 
-func (oClient *Client) createCpObjects() error {
-	oClient.octarineControlPlane = os.Getenv("OCTARINE_CP")
-	oClient.octarineAccMgrPword = os.Getenv("OCTARINE_ACC_MGR_PASSWD")
-	oClient.octarineCreatorPword = os.Getenv("OCTARINE_CREATOR_PASSWD")
-	oClient.octarineDeleterPword = os.Getenv("OCTARINE_DELETER_PASSWD")
-	oClient.octarineDomain = os.Getenv("OCTARINE_DOMAIN")
+// loadControlPlaneCredentials reads where the Octarine control plane is and the passwords of its users, from
+// Vault when the adapter keeps them there
+func (oClient *Client) loadControlPlaneCredentials() {
+	get := os.Getenv
+	if vaultCredentials != nil {
+		get = vaultCredentials.get
+	}
+	oClient.octarineControlPlane = get("OCTARINE_CP")
+	oClient.octarineAccMgrPword = get("OCTARINE_ACC_MGR_PASSWD")
+	oClient.octarineCreatorPword = get("OCTARINE_CREATOR_PASSWD")
+	oClient.octarineDeleterPword = get("OCTARINE_DELETER_PASSWD")
+}
+
+func (oClient *Client) createCpObjects(ctx context.Context, d *deployment) error {
+	oClient.loadControlPlaneCredentials()
 	dockerUser, userVar := os.LookupEnv("OCTARINE_DOCKER_USERNAME")
 	dockerEmail, emailVar := os.LookupEnv("OCTARINE_DOCKER_EMAIL")
 	dockerPassword, passwordVar := os.LookupEnv("OCTARINE_DOCKER_PASSWORD")
@@ -59,7 +69,9 @@ func (oClient *Client) createCpObjects() error {
 		os.Setenv("OCTARINE_DOCKER.PASSWORD", dockerPassword)
 		logrus.Debugf("Docker password %s", dockerPassword)
 	}
-	cmd := exec.Command("octactl", "login", "creator@octarine", oClient.octarineControlPlane, "--password",
+	octactlSession.Lock()
+	defer octactlSession.Unlock()
+	cmd := exec.CommandContext(ctx, "octactl", "login", "creator@octarine", oClient.octarineControlPlane, "--password",
 		oClient.octarineCreatorPword)
 	logrus.Debugf("Login to namespace octarine")
 	err := cmd.Run()
@@ -67,25 +79,20 @@ func (oClient *Client) createCpObjects() error {
 		logrus.Errorf("Command finished with error: %v", err)
 		return err
 	}
-	oClient.octarineAccount = "meshery-" + randSeq(6)
-	cmd = exec.Command("octactl", "account", "create", oClient.octarineAccount, accMgrUsername,
+	d.account = "meshery-" + randSeq(6)
+	cmd = exec.CommandContext(ctx, "octactl", "account", "create", d.account, accMgrUsername,
 		oClient.octarineAccMgrPword)
-	logrus.Debugf("Creating account %s", oClient.octarineAccount)
+	logrus.Debugf("Creating account %s", d.account)
 	err = cmd.Run()
 	if err != nil {
 		logrus.Errorf("Command finished with error: %v", err)
 		return err
 	}
-	cmd = exec.Command("octactl", "login", accMgrUsername+"@"+oClient.octarineAccount,
-		oClient.octarineControlPlane, "--password", oClient.octarineAccMgrPword)
-	logrus.Debugf("Login to namespace %s", oClient.octarineAccount)
-	err = cmd.Run()
-	if err != nil {
-		logrus.Errorf("Command finished with error: %v", err)
+	if err := oClient.loginToAccount(ctx, d); err != nil {
 		return err
 	}
-	cmd = exec.Command("octactl", "domain", "create", oClient.octarineDomain)
-	logrus.Debugf("Creating domain %s in namespace %s", oClient.octarineDomain, oClient.octarineAccount)
+	cmd = exec.CommandContext(ctx, "octactl", "domain", "create", d.domain)
+	logrus.Debugf("Creating domain %s in namespace %s", d.domain, d.account)
 	err = cmd.Run()
 	if err != nil {
 		logrus.Errorf("Command finished with error: %v", err)
@@ -94,8 +101,10 @@ func (oClient *Client) createCpObjects() error {
 	return nil
 }
 
-func (oClient *Client) deleteCpObjects() error {
-	cmd := exec.Command("octactl", "login", "deleter@octarine", oClient.octarineControlPlane, "--password",
+func (oClient *Client) deleteCpObjects(ctx context.Context, d *deployment) error {
+	octactlSession.Lock()
+	defer octactlSession.Unlock()
+	cmd := exec.CommandContext(ctx, "octactl", "login", "deleter@octarine", oClient.octarineControlPlane, "--password",
 		oClient.octarineDeleterPword)
 	logrus.Debugf("Login as deleter to account octarine")
 	err := cmd.Run()
@@ -103,8 +112,8 @@ func (oClient *Client) deleteCpObjects() error {
 		logrus.Errorf("Command finished with error: %v", err)
 		return err
 	}
-	cmd = exec.Command("octactl", "account", "delete", oClient.octarineAccount, "--force")
-	logrus.Debugf("Deleting account %s", oClient.octarineAccount)
+	cmd = exec.CommandContext(ctx, "octactl", "account", "delete", d.account, "--force")
+	logrus.Debugf("Deleting account %s", d.account)
 	err = cmd.Run()
 	if err != nil {
 		logrus.Errorf("Command finished with error: %v", err)
@@ -114,10 +123,19 @@ func (oClient *Client) deleteCpObjects() error {
 }
 
 // For this function to work, OCTARINE_DOCKER_USERNAME, OCTARINE_DOCKER_EMAIL, OCTARINE_DOCKER_PASSWORD (based64) must be set.
-func (oClient *Client) getOctarineDataplaneYAML(namespace string) (string, error) {
-	cmd := exec.Command("octactl", "dataplane", "install", "--k8s-namespace", namespace, oClient.octarineDomain)
-	logrus.Debugf("Creating dataplane yaml for deployment %s in namespace %s", oClient.octarineDomain, namespace)
-	dp, err := cmd.Output()
+func (oClient *Client) getOctarineDataplaneYAML(ctx context.Context, d *deployment) (string, error) {
+	cmd := exec.CommandContext(ctx, "octactl", "dataplane", "install", "--k8s-namespace", d.namespace, d.domain)
+	if d.version != "" {
+		// octactl picks up its config keys from OCTARINE_ prefixed env vars
+		cmd.Env = append(os.Environ(), "OCTARINE_VERSION_TAG="+d.version)
+	}
+	logrus.Debugf("Creating dataplane yaml for deployment %s in namespace %s", d.domain, d.namespace)
+	var dp []byte
+	err := oClient.asAccount(ctx, d, func() error {
+		var err error
+		dp, err = cmd.Output()
+		return err
+	})
 	if err != nil {
 		logrus.Errorf("Command finished with error: %v", err)
 		return "", err
@@ -129,13 +147,43 @@ const (
 	bookInfoInstallFile = "/bookinfo.yaml"
 )
 
-func (oClient *Client) getOctarineYAMLs(namespace string) (string, error) {
-	dp, err := oClient.getOctarineDataplaneYAML(namespace)
+func (oClient *Client) getOctarineYAMLs(ctx context.Context, d *deployment) (string, error) {
+	dp, err := oClient.getOctarineDataplaneYAML(ctx, d)
 	if err != nil {
 		err = errors.Wrap(err, "unable to create dataplane yaml")
 		logrus.Error(err)
 		return "", err
 	}
+	if d.mirror != "" {
+		dp, err = mirrorManifest(dp, d.mirror)
+		if err != nil {
+			err = errors.Wrap(err, "unable to pull the dataplane images from the mirror")
+			logrus.Error(err)
+			return "", err
+		}
+	}
+	if d.certManager {
+		dp, err = d.certManagerManifest(dp)
+		if err != nil {
+			err = errors.Wrap(err, "unable to issue the dataplane certificates with cert-manager")
+			logrus.Error(err)
+			return "", err
+		}
+	}
+	if d.highlyAvailable() {
+		dp, err = d.highAvailabilityManifest(dp)
+		if err != nil {
+			err = errors.Wrap(err, "unable to make the dataplane highly available")
+			logrus.Error(err)
+			return "", err
+		}
+	}
+	dp, err = d.scopeManifest(dp)
+	if err != nil {
+		err = errors.Wrap(err, "unable to rename dataplane resources")
+		logrus.Error(err)
+		return "", err
+	}
 	return dp, nil
 }
 
@@ -0,0 +1,468 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	inventoryName    = "octarine-inventory"
+	inventoryDataKey = "inventory.json"
+)
+
+// inventoryEntry is an object the adapter applied, the inventory is kept in a ConfigMap of the dataplane
+// namespace so what an operation applied can be deleted without resubmitting its YAML
+type inventoryEntry struct {
+	Group       string    `json:"group,omitempty"`
+	Version     string    `json:"version"`
+	Resource    string    `json:"resource"`
+	Kind        string    `json:"kind"`
+	Namespace   string    `json:"namespace,omitempty"`
+	Name        string    `json:"name"`
+	Operation   string    `json:"operation"`
+	OperationID string    `json:"operationId,omitempty"`
+	Applied     time.Time `json:"applied"`
+}
+
+func (e *inventoryEntry) gvr() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: e.Group, Version: e.Version, Resource: e.Resource}
+}
+
+func (e *inventoryEntry) key() string {
+	return inventoryKey(e.gvr().GroupResource(), e.Namespace, e.Name)
+}
+
+// inventoryKey identifies an object whatever version it is served at, the keys sort by namespace, then kind
+func inventoryKey(gr schema.GroupResource, namespace, name string) string {
+	return strings.Join([]string{namespace, gr.String(), name}, "/")
+}
+
+// loadInventory reads the inventory of the cluster, the caller holds inventoryMu
+func (oClient *Client) loadInventory(ctx context.Context) (map[string]*inventoryEntry, error) {
+	ns := dataplaneNamespace()
+	entries := map[string]*inventoryEntry{}
+	cm, err := oClient.clientset(ctx).CoreV1().ConfigMaps(ns).Get(resourceName(inventoryName), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get the inventory in namespace %s", ns)
+	}
+	stored := []*inventoryEntry{}
+	if err := json.Unmarshal([]byte(cm.Data[inventoryDataKey]), &stored); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the inventory in %s/%s", ns, cm.GetName())
+	}
+	for _, e := range stored {
+		entries[e.key()] = e
+	}
+	return entries, nil
+}
+
+// saveInventory writes the inventory to the cluster, the caller holds inventoryMu
+func (oClient *Client) saveInventory(ctx context.Context, entries map[string]*inventoryEntry) error {
+	stored := make([]*inventoryEntry, 0, len(entries))
+	for _, key := range sortedInventoryKeys(entries) {
+		stored = append(stored, entries[key])
+	}
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	ns := dataplaneNamespace()
+	labels := map[string]string{managedByLabel: managedByValue}
+	_, err = oClient.clientset(ctx).CoreV1().Namespaces().Create(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: ns, Labels: labels},
+	})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "unable to create namespace %s", ns)
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: resourceName(inventoryName), Namespace: ns, Labels: labels},
+		Data:       map[string]string{inventoryDataKey: string(data)},
+	}
+	_, err = oClient.clientset(ctx).CoreV1().ConfigMaps(ns).Update(cm)
+	if apierrors.IsNotFound(err) {
+		_, err = oClient.clientset(ctx).CoreV1().ConfigMaps(ns).Create(cm)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "unable to save the inventory in namespace %s", ns)
+	}
+	return nil
+}
+
+func sortedInventoryKeys(entries map[string]*inventoryEntry) []string {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// inventoryEntries describes the objects of a manifest the way executeManifest applies them
+func inventoryEntries(manifest, namespace, opName, opID string, applied time.Time) ([]*inventoryEntry, error) {
+	objects, err := parseManifestObjects(manifest)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*inventoryEntry, 0, len(objects))
+	for _, obj := range objects {
+		if namespace != "" {
+			obj.SetNamespace(namespace)
+		}
+		res := resourceFor(obj)
+		entries = append(entries, &inventoryEntry{
+			Group:       res.Group,
+			Version:     res.Version,
+			Resource:    res.Resource,
+			Kind:        obj.GetKind(),
+			Namespace:   obj.GetNamespace(),
+			Name:        obj.GetName(),
+			Operation:   opName,
+			OperationID: opID,
+			Applied:     applied,
+		})
+	}
+	return entries, nil
+}
+
+// recordInventory updates the inventory with the objects an operation applied, or deleted
+func (oClient *Client) recordInventory(ctx context.Context, arReq *meshes.ApplyRuleRequest, manifest string) error {
+	entries, err := inventoryEntries(manifest, arReq.GetNamespace(), arReq.GetOpName(), arReq.GetOperationId(), time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	oClient.inventoryMu.Lock()
+	defer oClient.inventoryMu.Unlock()
+	inventory, err := oClient.loadInventory(ctx)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if arReq.GetDeleteOp() {
+			delete(inventory, e.key())
+			continue
+		}
+		// applying an object again hands it to the latest operation
+		inventory[e.key()] = e
+	}
+	return oClient.saveInventory(ctx, inventory)
+}
+
+// deleteInventory deletes the objects the operation of applied_operation_id, or of the request itself,
+// applied. Objects already gone are skipped and every deleted object leaves the inventory.
+func (oClient *Client) deleteInventory(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	if oClient.clientset(ctx) == nil || oClient.dynamicClient(ctx) == nil {
+		return fmt.Errorf("error: mesh instance has not been created")
+	}
+	opID := arReq.GetAppliedOperationId()
+	if opID == "" {
+		opID = arReq.GetOperationId()
+	}
+	oClient.inventoryMu.Lock()
+	defer oClient.inventoryMu.Unlock()
+	inventory, err := oClient.loadInventory(ctx)
+	if err != nil {
+		return err
+	}
+	keys := []string{}
+	for _, key := range sortedInventoryKeys(inventory) {
+		if id := inventory[key].OperationID; id != "" && id == opID {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("error: no resources applied by operation %s are recorded", opID)
+	}
+	targets := make([]changeTarget, 0, len(keys))
+	for _, key := range keys {
+		e := inventory[key]
+		targets = append(targets, changeTarget{kind: e.Kind, namespace: e.Namespace, name: e.Name})
+	}
+	if err := oClient.guardBulkChange(arReq, targets, true); err != nil {
+		return err
+	}
+
+	progress := oClient.newProgressReporter(ctx, len(keys), true)
+	var deleteErr error
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			deleteErr = err
+			break
+		}
+		e := inventory[key]
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(schema.GroupVersion{Group: e.Group, Version: e.Version}.String())
+		obj.SetKind(e.Kind)
+		obj.SetNamespace(e.Namespace)
+		obj.SetName(e.Name)
+		workingOn(ctx, "%s %s", e.Kind, e.Name)
+		if err := oClient.deleteResource(ctx, e.gvr(), obj); err != nil && !apierrors.IsNotFound(errors.Cause(err)) {
+			deleteErr = err
+			break
+		}
+		delete(inventory, key)
+		progressed(ctx)
+		progress.step()
+	}
+	// the objects deleted before a failure are gone either way
+	if err := oClient.saveInventory(ctx, inventory); err != nil {
+		logrus.Error(err)
+		if deleteErr == nil {
+			deleteErr = err
+		}
+	}
+	return deleteErr
+}
+
+const (
+	healthHealthy     = "healthy"
+	healthProgressing = "progressing"
+	healthDegraded    = "degraded"
+	healthMissing     = "missing"
+	healthUnknown     = "unknown"
+)
+
+// managedResources lists the live resources labeled as managed by the adapter, of every kind the cluster
+// lists. Cluster scoped resources are left out when a namespace is given.
+func (oClient *Client) managedResources(ctx context.Context, namespace string) ([]matchedResource, error) {
+	lists, err := oClient.clientset(ctx).Discovery().ServerPreferredResources()
+	if err != nil && len(lists) == 0 {
+		err = errors.Wrapf(err, "unable to discover the resources of the cluster")
+		logrus.Error(err)
+		return nil, err
+	}
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", managedByLabel, managedByValue)}
+	matched := []matchedResource{}
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if strings.Contains(r.Name, "/") || !stringSet(r.Verbs)["list"] || (!r.Namespaced && namespace != "") {
+				continue
+			}
+			res := gv.WithResource(r.Name)
+			var items *unstructured.UnstructuredList
+			if r.Namespaced {
+				items, err = oClient.dynamicClient(ctx).Resource(res).Namespace(namespace).List(opts)
+			} else {
+				items, err = oClient.dynamicClient(ctx).Resource(res).List(opts)
+			}
+			if err != nil {
+				// a kind the adapter may not list can't hold what it applied either
+				logrus.Warnf("Unable to list the managed %s for the inventory: %v", res.String(), err)
+				continue
+			}
+			for i := range items.Items {
+				matched = append(matched, matchedResource{res: res, data: &items.Items[i]})
+			}
+		}
+	}
+	return matched, nil
+}
+
+// describeManaged describes a live resource, the operation is inferred from its labels until the inventory
+// says otherwise
+func describeManaged(m matchedResource) *meshes.InventoryResource {
+	r := &meshes.InventoryResource{
+		ApiVersion: m.data.GetAPIVersion(),
+		Kind:       m.data.GetKind(),
+		Namespace:  m.data.GetNamespace(),
+		Name:       m.data.GetName(),
+		AppliedAt:  m.data.GetCreationTimestamp().UTC().Format(time.RFC3339),
+		Deployment: m.data.GetLabels()[deploymentNameLabel],
+	}
+	switch {
+	case r.Deployment != "":
+		r.Operation = installOctarineCommand
+	case m.data.GetLabels()[sampleAppLabel] == sampleAppBookInfo:
+		r.Operation = installBookInfoCommand
+	}
+	r.Health, r.HealthReason = resourceHealth(m.data)
+	return r
+}
+
+// describeRecorded describes an object of the inventory which the label search didn't find
+func (oClient *Client) describeRecorded(ctx context.Context, e *inventoryEntry) *meshes.InventoryResource {
+	r := &meshes.InventoryResource{
+		ApiVersion: schema.GroupVersion{Group: e.Group, Version: e.Version}.String(),
+		Kind:       e.Kind,
+		Namespace:  e.Namespace,
+		Name:       e.Name,
+	}
+	obj, err := oClient.dynamicClient(ctx).Resource(e.gvr()).Namespace(e.Namespace).Get(e.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		r.Health, r.HealthReason = healthMissing, "the resource no longer exists"
+	case err != nil:
+		r.Health, r.HealthReason = healthUnknown, err.Error()
+	default:
+		r.Deployment = obj.GetLabels()[deploymentNameLabel]
+		r.Health, r.HealthReason = resourceHealth(obj)
+	}
+	return r
+}
+
+// resourceHealth judges a resource by its status the way kubectl rollout status does for workloads, other
+// kinds are healthy unless a Ready condition says otherwise
+func resourceHealth(obj *unstructured.Unstructured) (string, string) {
+	status := func(field string) int64 {
+		n, _, _ := unstructured.NestedInt64(obj.Object, "status", field)
+		return n
+	}
+	observed := status("observedGeneration")
+	if observed != 0 && observed < obj.GetGeneration() {
+		return healthProgressing, "the latest spec is not observed yet"
+	}
+	switch obj.GetKind() {
+	case "Deployment", "StatefulSet", "ReplicaSet":
+		if c := condition(obj, "Progressing"); c != nil && c["status"] == "False" {
+			return healthDegraded, fmt.Sprint(c["message"])
+		}
+		replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		if !found {
+			replicas = 1
+		}
+		if ready := status("readyReplicas"); ready < replicas {
+			return healthProgressing, fmt.Sprintf("%d/%d replicas ready", ready, replicas)
+		}
+	case "DaemonSet":
+		if ready, desired := status("numberReady"), status("desiredNumberScheduled"); ready < desired {
+			return healthProgressing, fmt.Sprintf("%d/%d pods ready", ready, desired)
+		}
+	case "Job":
+		if c := condition(obj, "Failed"); c != nil && c["status"] == "True" {
+			return healthDegraded, fmt.Sprint(c["message"])
+		}
+		if c := condition(obj, "Complete"); c == nil || c["status"] != "True" {
+			return healthProgressing, "the job has not completed"
+		}
+	case "Pod":
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		statuses, _, err := nestedObjects(obj.Object, "status", "containerStatuses")
+		if err != nil {
+			return healthUnknown, err.Error()
+		}
+		for _, s := range statuses {
+			reason, _, _ := unstructured.NestedString(s, "state", "waiting", "reason")
+			if reason == "CrashLoopBackOff" || reason == "ImagePullBackOff" || reason == "ErrImagePull" {
+				return healthDegraded, fmt.Sprintf("a container is in %s", reason)
+			}
+		}
+		switch phase {
+		case "Succeeded":
+			return healthHealthy, ""
+		case "Failed":
+			return healthDegraded, "the pod failed"
+		case "Running":
+			if c := condition(obj, "Ready"); c != nil && c["status"] != "True" {
+				return healthProgressing, "the pod is not ready"
+			}
+		default:
+			return healthProgressing, fmt.Sprintf("the pod is %s", strings.ToLower(phase))
+		}
+	default:
+		if c := condition(obj, "Ready"); c != nil && c["status"] == "False" {
+			return healthDegraded, fmt.Sprint(c["message"])
+		}
+	}
+	return healthHealthy, ""
+}
+
+// condition finds a status condition of a resource by its type
+func condition(obj *unstructured.Unstructured, kind string) map[string]interface{} {
+	conditions, _, _ := nestedObjects(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		if t, _, _ := unstructured.NestedString(c, "type"); t == kind {
+			return c
+		}
+	}
+	return nil
+}
+
+// Inventory lists the resources the adapter manages: the live resources carrying its managed-by label and
+// the objects the inventory recorded, with the operation that applied them and their health
+func (oClient *Client) Inventory(ctx context.Context, req *meshes.InventoryRequest) (*meshes.InventoryResponse, error) {
+	if oClient.clientset(ctx) == nil || oClient.dynamicClient(ctx) == nil {
+		return &meshes.InventoryResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	oClient.inventoryMu.Lock()
+	recorded, err := oClient.loadInventory(ctx)
+	oClient.inventoryMu.Unlock()
+	if err != nil {
+		return &meshes.InventoryResponse{Error: err.Error()}, nil
+	}
+
+	byKey := map[string]*meshes.InventoryResource{}
+	// resources of an operation are only found in the inventory
+	if req.GetOperationId() == "" {
+		live, err := oClient.managedResources(ctx, req.GetNamespace())
+		if err != nil {
+			return &meshes.InventoryResponse{Error: err.Error()}, nil
+		}
+		for _, m := range live {
+			byKey[inventoryKey(m.res.GroupResource(), m.data.GetNamespace(), m.data.GetName())] = describeManaged(m)
+		}
+	}
+	for key, e := range recorded {
+		if (req.GetNamespace() != "" && e.Namespace != req.GetNamespace()) ||
+			(req.GetOperationId() != "" && e.OperationID != req.GetOperationId()) {
+			continue
+		}
+		r, ok := byKey[key]
+		if !ok {
+			r = oClient.describeRecorded(ctx, e)
+			byKey[key] = r
+		}
+		r.Operation, r.OperationId = e.Operation, e.OperationID
+		r.AppliedAt = e.Applied.UTC().Format(time.RFC3339)
+	}
+
+	keys := make([]string, 0, len(byKey))
+	for key := range byKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	start, end, next, err := paginate(keys, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return &meshes.InventoryResponse{Error: err.Error()}, nil
+	}
+	resp := &meshes.InventoryResponse{NextPageToken: next}
+	for _, key := range keys[start:end] {
+		resp.Resources = append(resp.Resources, byKey[key])
+	}
+	return resp, nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	ipStackIPv4      = "IPv4"
+	ipStackIPv6      = "IPv6"
+	ipStackDualStack = "DualStack"
+
+	// ipv6MinVersion is the first Octarine release whose components listen on IPv6
+	ipv6MinVersion = "1.9"
+)
+
+func ipFamily(address string) string {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		if ip, _, err := net.ParseCIDR(address); err == nil {
+			return ipFamily(ip.String())
+		}
+		return ""
+	}
+	if ip.To4() != nil {
+		return ipStackIPv4
+	}
+	return ipStackIPv6
+}
+
+// ipStack tells whether the cluster runs IPv4, IPv6 or both, from the address of the API server service
+// and the addresses and pod ranges of the nodes
+func (oClient *Client) ipStack(ctx context.Context) (string, error) {
+	families := map[string]bool{}
+	svc, err := oClient.clientset(ctx).CoreV1().Services(metav1.NamespaceDefault).Get("kubernetes", metav1.GetOptions{})
+	if err != nil {
+		err = errors.Wrapf(err, "unable to get the kubernetes service")
+		logrus.Error(err)
+		return "", err
+	}
+	families[ipFamily(svc.Spec.ClusterIP)] = true
+	nodes, err := oClient.clientset(ctx).CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		err = errors.Wrapf(err, "unable to list the nodes")
+		logrus.Error(err)
+		return "", err
+	}
+	for _, node := range nodes.Items {
+		families[ipFamily(node.Spec.PodCIDR)] = true
+		for _, addr := range node.Status.Addresses {
+			if addr.Type == corev1.NodeInternalIP {
+				families[ipFamily(addr.Address)] = true
+			}
+		}
+	}
+	switch {
+	case families[ipStackIPv4] && families[ipStackIPv6]:
+		return ipStackDualStack, nil
+	case families[ipStackIPv6]:
+		return ipStackIPv6, nil
+	}
+	return ipStackIPv4, nil
+}
+
+// manifestVersion is the release the images of a manifest share, empty when they don't or aren't releases
+func manifestVersion(manifest string) (string, error) {
+	images, err := manifestImages(manifest)
+	if err != nil {
+		return "", err
+	}
+	version := ""
+	for _, image := range images {
+		tag := normalizeVersion(parseImageRef(image).tag)
+		if !isRelease(tag) || (version != "" && tag != version) {
+			return "", nil
+		}
+		version = tag
+	}
+	return version, nil
+}
+
+// preflightIPStack fails on IPv6 and dual-stack clusters when the release to install has no IPv6 support,
+// and otherwise adapts the services and listen addresses of the dataplane to the stack
+func (oClient *Client) preflightIPStack(ctx context.Context, d *deployment, manifest string) (string, error) {
+	stack, err := oClient.ipStack(ctx)
+	if err != nil {
+		return "", err
+	}
+	if stack == ipStackIPv4 {
+		return manifest, nil
+	}
+	version, err := manifestVersion(manifest)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case version == "":
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: operationIDFrom(ctx),
+			EventType:   meshes.EventType_WARN,
+			Summary:     fmt.Sprintf("Unable to check the %s support of Octarine %s", stack, d.versionName()),
+			Details:     fmt.Sprintf("The images of the dataplane don't share a release number, IPv6 needs Octarine %s or later.", ipv6MinVersion),
+		}
+	case compareVersions(version, ipv6MinVersion) < 0:
+		return "", fmt.Errorf("error: the cluster is %s and Octarine %s has no IPv6 support, install Octarine %s or later", stack, version, ipv6MinVersion)
+	}
+	return adaptToIPStack(manifest, stack)
+}
+
+// adaptToIPStack makes the services of a manifest prefer both families on dual-stack clusters, and turns
+// the IPv4 wildcard addresses of its containers into the IPv6 ones on IPv6 clusters
+func adaptToIPStack(manifest, stack string) (string, error) {
+	wildcards := strings.NewReplacer("0.0.0.0:", "[::]:")
+	return transformManifest(manifest, func(data *unstructured.Unstructured) error {
+		if data.GetKind() == "Service" && stack == ipStackDualStack {
+			if _, found, _ := unstructured.NestedString(data.Object, "spec", "ipFamilyPolicy"); !found {
+				_ = unstructured.SetNestedField(data.Object, "PreferDualStack", "spec", "ipFamilyPolicy")
+			}
+			return nil
+		}
+		path := podSpecPath(data.GetKind())
+		if path == nil || stack != ipStackIPv6 {
+			return nil
+		}
+		containers, found, err := nestedObjects(data.Object, append(path, "containers")...)
+		if !found || err != nil {
+			return err
+		}
+		for _, container := range containers {
+			args, found, err := unstructured.NestedStringSlice(container, "args")
+			if err != nil {
+				return err
+			}
+			if found {
+				for i, arg := range args {
+					args[i] = wildcards.Replace(arg)
+				}
+				if err := unstructured.SetNestedStringSlice(container, args, "args"); err != nil {
+					return err
+				}
+			}
+			env, _, err := nestedObjects(container, "env")
+			if err != nil {
+				return err
+			}
+			for _, v := range env {
+				s, found, err := unstructured.NestedString(v, "value")
+				if err != nil {
+					return err
+				}
+				if !found {
+					continue
+				}
+				if s == "0.0.0.0" {
+					s = "::"
+				}
+				v["value"] = wildcards.Replace(s)
+			}
+			if env != nil {
+				if err := setNestedObjects(container, env, "env"); err != nil {
+					return err
+				}
+			}
+		}
+		return setNestedObjects(data.Object, containers, append(path, "containers")...)
+	})
+}
@@ -0,0 +1,216 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	operatorRoleName   = "octarine-operator"
+	operatorNamePrefix = "octarine-operator-"
+
+	defaultKubeconfigTTL = time.Hour
+	// tokens outliving a debugging session are what the TTL is there to prevent
+	maxKubeconfigTTL = 24 * time.Hour
+)
+
+// operatorRules let an operator inspect and debug the dataplane, but not read its secrets
+var operatorRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{""},
+		Resources: []string{"pods", "services", "endpoints", "configmaps", "events", "serviceaccounts"},
+		Verbs:     []string{"get", "list", "watch"},
+	},
+	{
+		APIGroups: []string{"apps"},
+		Resources: []string{"deployments", "replicasets", "daemonsets", "statefulsets"},
+		Verbs:     []string{"get", "list", "watch"},
+	},
+	{
+		APIGroups: []string{""},
+		Resources: []string{"pods/log"},
+		Verbs:     []string{"get"},
+	},
+	{
+		APIGroups: []string{""},
+		Resources: []string{"pods/exec", "pods/portforward"},
+		Verbs:     []string{"create"},
+	},
+	{
+		// deleting a pod is how a stuck component gets restarted
+		APIGroups: []string{""},
+		Resources: []string{"pods"},
+		Verbs:     []string{"delete"},
+	},
+}
+
+// operatorAccountName turns a user name into the name of its service account
+func operatorAccountName(username string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, username)
+	name = operatorNamePrefix + strings.Trim(name, "-")
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return strings.TrimRight(name, "-")
+}
+
+// ensureOperatorAccess creates the service account of an operator and binds it to the operator role,
+// both are owned by the anchor so they go away with the deployment
+func (oClient *Client) ensureOperatorAccess(ctx context.Context, d *deployment, account string) error {
+	anchor, err := oClient.anchorOwner(ctx, d)
+	if err != nil {
+		return err
+	}
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       d.namespace,
+			Labels:          d.managedLabels(),
+			OwnerReferences: []metav1.OwnerReference{*anchor},
+		}
+	}
+
+	role := &rbacv1.Role{ObjectMeta: meta(operatorRoleName), Rules: operatorRules}
+	if _, err = oClient.clientset(ctx).RbacV1().Roles(d.namespace).Create(role); apierrors.IsAlreadyExists(err) {
+		_, err = oClient.clientset(ctx).RbacV1().Roles(d.namespace).Update(role)
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "unable to create role %s", operatorRoleName)
+		logrus.Error(err)
+		return err
+	}
+	sa := &corev1.ServiceAccount{ObjectMeta: meta(account)}
+	if _, err = oClient.clientset(ctx).CoreV1().ServiceAccounts(d.namespace).Create(sa); err != nil && !apierrors.IsAlreadyExists(err) {
+		err = errors.Wrapf(err, "unable to create service account %s", account)
+		logrus.Error(err)
+		return err
+	}
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: meta(account),
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: operatorRoleName},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: account, Namespace: d.namespace}},
+	}
+	if _, err = oClient.clientset(ctx).RbacV1().RoleBindings(d.namespace).Create(binding); err != nil && !apierrors.IsAlreadyExists(err) {
+		err = errors.Wrapf(err, "unable to create role binding %s", account)
+		logrus.Error(err)
+		return err
+	}
+	return nil
+}
+
+// operatorKubeconfig renders a kubeconfig for the cluster the adapter talks to, authenticating with the token
+func (oClient *Client) operatorKubeconfig(d *deployment, account, token string) ([]byte, error) {
+	config := oClient.restConfig()
+	cluster := &clientcmdapi.Cluster{
+		Server:                   config.Host,
+		CertificateAuthorityData: config.CAData,
+		InsecureSkipTLSVerify:    config.Insecure,
+	}
+	if len(cluster.CertificateAuthorityData) == 0 && config.CAFile != "" {
+		ca, err := ioutil.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read the certificate authority of the cluster")
+		}
+		cluster.CertificateAuthorityData = ca
+	}
+	name := "octarine-" + d.name
+	cfg := clientcmdapi.NewConfig()
+	cfg.Clusters[name] = cluster
+	cfg.AuthInfos[account] = &clientcmdapi.AuthInfo{Token: token}
+	cfg.Contexts[name] = &clientcmdapi.Context{Cluster: name, AuthInfo: account, Namespace: d.namespace}
+	cfg.CurrentContext = name
+	return clientcmd.Write(*cfg)
+}
+
+// ExportKubeconfig issues a kubeconfig with a short lived token, limited to the namespace of a deployment,
+// for operators debugging the dataplane directly
+func (oClient *Client) ExportKubeconfig(ctx context.Context, req *meshes.ExportKubeconfigRequest) (*meshes.ExportKubeconfigResponse, error) {
+	if oClient.clientset(ctx) == nil {
+		return &meshes.ExportKubeconfigResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	if req.GetUsername() == "" {
+		return &meshes.ExportKubeconfigResponse{Error: "error: the user the kubeconfig is issued to is required"}, nil
+	}
+	ttl := defaultKubeconfigTTL
+	if req.GetTtl() != "" {
+		var err error
+		if ttl, err = time.ParseDuration(req.GetTtl()); err != nil || ttl < 10*time.Minute || ttl > maxKubeconfigTTL {
+			// the API server doesn't issue tokens for less than 10 minutes
+			return &meshes.ExportKubeconfigResponse{
+				Error: fmt.Sprintf("error: ttl must be a duration between 10m and %s, got %q", maxKubeconfigTTL, req.GetTtl()),
+			}, nil
+		}
+	}
+	d, err := oClient.getDeployment(req.GetDeployment())
+	if err != nil {
+		return &meshes.ExportKubeconfigResponse{Error: err.Error()}, nil
+	}
+	account := operatorAccountName(req.GetUsername())
+	entry := auditEntry{
+		User:       req.GetUsername(),
+		Action:     "kubeconfig.export",
+		Deployment: d.name,
+		Target:     d.namespace + "/" + account,
+		Details:    fmt.Sprintf("issued a kubeconfig for namespace %s valid for %s", d.namespace, ttl),
+	}
+
+	if err := oClient.ensureOperatorAccess(ctx, d, account); err != nil {
+		recordAudit(entry, err)
+		return &meshes.ExportKubeconfigResponse{Error: err.Error()}, nil
+	}
+	seconds := int64(ttl / time.Second)
+	tr, err := oClient.clientset(ctx).CoreV1().ServiceAccounts(d.namespace).CreateToken(account, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &seconds},
+	})
+	if err != nil {
+		err = errors.Wrapf(err, "unable to issue a token for service account %s", account)
+		logrus.Error(err)
+		recordAudit(entry, err)
+		return &meshes.ExportKubeconfigResponse{Error: err.Error()}, nil
+	}
+	kubeconfig, err := oClient.operatorKubeconfig(d, account, tr.Status.Token)
+	recordAudit(entry, err)
+	if err != nil {
+		return &meshes.ExportKubeconfigResponse{Error: err.Error()}, nil
+	}
+	return &meshes.ExportKubeconfigResponse{
+		Kubeconfig: kubeconfig,
+		ExpiresAt:  tr.Status.ExpirationTimestamp.UTC().Format(time.RFC3339),
+	}, nil
+}
@@ -0,0 +1,240 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	labelStateName    = "octarine-namespace-labels"
+	labelStateDataKey = "labels.json"
+)
+
+var namespacesResource = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// namespaceLabelState is a namespace the adapter labeled for injection, with the injection label it had
+// before, so removing the injection gives the namespace its label back instead of deleting it
+type namespaceLabelState struct {
+	Namespace  string `json:"namespace"`
+	Deployment string `json:"deployment"`
+	// Value is the value the adapter set
+	Value string `json:"value"`
+	// Existed tells whether the namespace had the label before, with the value Previous
+	Existed     bool      `json:"existed"`
+	Previous    string    `json:"previous,omitempty"`
+	OperationID string    `json:"operationId,omitempty"`
+	Labeled     time.Time `json:"labeled"`
+}
+
+// loadLabelState reads the namespaces the adapter labeled, by name; the caller holds labelStateMu
+func (oClient *Client) loadLabelState(ctx context.Context) (map[string]*namespaceLabelState, error) {
+	ns := dataplaneNamespace()
+	states := map[string]*namespaceLabelState{}
+	cm, err := oClient.clientset(ctx).CoreV1().ConfigMaps(ns).Get(resourceName(labelStateName), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get the namespace label state in namespace %s", ns)
+	}
+	stored := []*namespaceLabelState{}
+	if err := json.Unmarshal([]byte(cm.Data[labelStateDataKey]), &stored); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the namespace label state in %s/%s", ns, cm.GetName())
+	}
+	for _, s := range stored {
+		states[s.Namespace] = s
+	}
+	return states, nil
+}
+
+// saveLabelState writes the namespaces the adapter labeled to the cluster; the caller holds labelStateMu
+func (oClient *Client) saveLabelState(ctx context.Context, states map[string]*namespaceLabelState) error {
+	stored := make([]*namespaceLabelState, 0, len(states))
+	for _, s := range states {
+		stored = append(stored, s)
+	}
+	sort.Slice(stored, func(i, j int) bool { return stored[i].Namespace < stored[j].Namespace })
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	ns := dataplaneNamespace()
+	labels := map[string]string{managedByLabel: managedByValue}
+	_, err = oClient.clientset(ctx).CoreV1().Namespaces().Create(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: ns, Labels: labels},
+	})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "unable to create namespace %s", ns)
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: resourceName(labelStateName), Namespace: ns, Labels: labels},
+		Data:       map[string]string{labelStateDataKey: string(data)},
+	}
+	_, err = oClient.clientset(ctx).CoreV1().ConfigMaps(ns).Update(cm)
+	if apierrors.IsNotFound(err) {
+		_, err = oClient.clientset(ctx).CoreV1().ConfigMaps(ns).Create(cm)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "unable to save the namespace label state in namespace %s", ns)
+	}
+	return nil
+}
+
+// setInjectionLabel labels a namespace for injection by a deployment, recording the label it had first. A
+// namespace labeled again keeps the label it had before the adapter first labeled it.
+func (oClient *Client) setInjectionLabel(ctx context.Context, namespace string, d *deployment) error {
+	ns := &unstructured.Unstructured{}
+	ns.SetName(namespace)
+	ns, err := oClient.getResource(ctx, namespacesResource, ns)
+	if err != nil {
+		return err
+	}
+	labels := ns.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	oClient.labelStateMu.Lock()
+	defer oClient.labelStateMu.Unlock()
+	states, err := oClient.loadLabelState(ctx)
+	if err != nil {
+		return err
+	}
+	state, ok := states[namespace]
+	if !ok {
+		previous, existed := labels[injectionLabel]
+		state = &namespaceLabelState{Namespace: namespace, Existed: existed, Previous: previous}
+		states[namespace] = state
+	}
+	state.Deployment = d.name
+	state.Value = d.injectionValue()
+	state.OperationID = operationIDFrom(ctx)
+	state.Labeled = time.Now().UTC()
+	// the state is saved first, so the original label isn't lost when labeling fails half way
+	if err := oClient.saveLabelState(ctx, states); err != nil {
+		return err
+	}
+	labels[injectionLabel] = d.injectionValue()
+	ns.SetLabels(labels)
+	return oClient.updateResource(ctx, namespacesResource, ns)
+}
+
+// restoreInjectionLabel gives a namespace back the injection label it had before the adapter labeled it, or
+// removes the label when it had none. A namespace relabeled by someone else since keeps its label. Namespaces
+// the adapter has no state of, labeled by an older adapter, have the label removed.
+func (oClient *Client) restoreInjectionLabel(ctx context.Context, namespace string) error {
+	oClient.labelStateMu.Lock()
+	defer oClient.labelStateMu.Unlock()
+	states, err := oClient.loadLabelState(ctx)
+	if err != nil {
+		return err
+	}
+	if err := oClient.restoreLabel(ctx, namespace, states[namespace]); err != nil {
+		return err
+	}
+	if _, ok := states[namespace]; !ok {
+		return nil
+	}
+	delete(states, namespace)
+	return oClient.saveLabelState(ctx, states)
+}
+
+// restoreDeploymentLabels restores the injection label of every namespace the adapter labeled for a deployment
+func (oClient *Client) restoreDeploymentLabels(ctx context.Context, d *deployment) error {
+	oClient.labelStateMu.Lock()
+	defer oClient.labelStateMu.Unlock()
+	states, err := oClient.loadLabelState(ctx)
+	if err != nil {
+		return err
+	}
+	restored := 0
+	for _, namespace := range sortedStateNamespaces(states) {
+		state := states[namespace]
+		if state.Deployment != d.name {
+			continue
+		}
+		workingOn(ctx, "restoring the injection label of namespace %s", namespace)
+		if err := oClient.restoreLabel(ctx, namespace, state); err != nil {
+			// the namespaces restored so far leave the state
+			if restored > 0 {
+				_ = oClient.saveLabelState(ctx, states)
+			}
+			return err
+		}
+		delete(states, namespace)
+		restored++
+		progressed(ctx)
+	}
+	if restored == 0 {
+		return nil
+	}
+	return oClient.saveLabelState(ctx, states)
+}
+
+func sortedStateNamespaces(states map[string]*namespaceLabelState) []string {
+	namespaces := make([]string, 0, len(states))
+	for namespace := range states {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// restoreLabel sets the injection label of a namespace back to its state, the caller holds labelStateMu
+func (oClient *Client) restoreLabel(ctx context.Context, namespace string, state *namespaceLabelState) error {
+	ns := &unstructured.Unstructured{}
+	ns.SetName(namespace)
+	ns, err := oClient.getResource(ctx, namespacesResource, ns)
+	if apierrors.IsNotFound(errors.Cause(err)) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	labels := ns.GetLabels()
+	current, labeled := labels[injectionLabel]
+	switch {
+	case state == nil:
+		if !labeled {
+			return nil
+		}
+		delete(labels, injectionLabel)
+	case !labeled || current != state.Value:
+		logrus.Warnf("Leaving the injection label of namespace %s as is, it was changed since the adapter set it", namespace)
+		return nil
+	case state.Existed:
+		if current == state.Previous {
+			return nil
+		}
+		labels[injectionLabel] = state.Previous
+	default:
+		delete(labels, injectionLabel)
+	}
+	ns.SetLabels(labels)
+	return oClient.updateResource(ctx, namespacesResource, ns)
+}
@@ -0,0 +1,351 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// fortio is the load generator of Meshery's performance tests, its server is the echo service
+	defaultFortioImage = "fortio/fortio:1.3.1"
+	fortioPort         = 8080
+
+	latencyServerContainer = "echo"
+	latencyLoadContainer   = "load"
+
+	defaultLatencyDuration    = 30 * time.Second
+	defaultLatencyConnections = 8
+	// the load pod has to finish within the deadline of probe pods
+	maxLatencyDuration    = time.Minute
+	maxLatencyConnections = 64
+)
+
+func fortioImage() string {
+	if image := os.Getenv("OCTARINE_FORTIO_IMAGE"); image != "" {
+		return image
+	}
+	return defaultFortioImage
+}
+
+// latencyLoad is the load both sides of a latency probe are put under
+type latencyLoad struct {
+	duration    time.Duration
+	connections int
+	qps         int
+}
+
+func parseLatencyLoad(params *deploymentParams) (latencyLoad, error) {
+	load := latencyLoad{duration: defaultLatencyDuration, connections: defaultLatencyConnections, qps: params.QPS}
+	if params.Duration != "" {
+		d, err := time.ParseDuration(params.Duration)
+		if err != nil {
+			return load, fmt.Errorf("error: invalid duration %q: %v", params.Duration, err)
+		}
+		load.duration = d
+	}
+	if load.duration < time.Second || load.duration > maxLatencyDuration {
+		return load, fmt.Errorf("error: the duration of a latency probe must be between 1s and %s", maxLatencyDuration)
+	}
+	if params.Connections != 0 {
+		load.connections = params.Connections
+	}
+	if load.connections < 1 || load.connections > maxLatencyConnections {
+		return load, fmt.Errorf("error: a latency probe uses between 1 and %d connections", maxLatencyConnections)
+	}
+	if load.qps < 0 {
+		return load, fmt.Errorf("error: the qps of a latency probe can't be negative")
+	}
+	return load, nil
+}
+
+// fortioResult is the part of the JSON result of fortio load the probe reads, durations are in seconds
+type fortioResult struct {
+	ActualQPS         float64
+	DurationHistogram struct {
+		Count       int64
+		Avg         float64
+		Percentiles []struct {
+			Percentile float64
+			Value      float64
+		}
+	}
+	RetCodes map[string]int64
+}
+
+// parseFortioResult reads the JSON result fortio writes to stdout at the end of its log
+func parseFortioResult(log string) (*meshes.LatencyResult, error) {
+	start := strings.Index(log, "\n{")
+	if strings.HasPrefix(log, "{") {
+		start = 0
+	}
+	end := strings.LastIndex(log, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("error: the load generator reported no result: %s", strings.TrimSpace(log))
+	}
+	fr := &fortioResult{}
+	if err := json.Unmarshal([]byte(log[start:end+1]), fr); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the result of the load generator")
+	}
+	result := &meshes.LatencyResult{
+		AvgMs:    fr.DurationHistogram.Avg * 1000,
+		Qps:      fr.ActualQPS,
+		Requests: fr.DurationHistogram.Count,
+		Errors:   fr.DurationHistogram.Count - fr.RetCodes["200"],
+	}
+	for _, p := range fr.DurationHistogram.Percentiles {
+		switch p.Percentile {
+		case 50:
+			result.P50Ms = p.Value * 1000
+		case 95:
+			result.P95Ms = p.Value * 1000
+		case 99:
+			result.P99Ms = p.Value * 1000
+		}
+	}
+	return result, nil
+}
+
+// latencyServer is the echo service of one side of the probe, a Deployment so its rollout can be waited on
+func latencyServer(name, namespace string, labels map[string]string) (*appsv1.Deployment, *corev1.Service) {
+	labels[sampleAppLabel] = sampleProbe
+	selector := map[string]string{"app": name}
+	podLabels := map[string]string{"app": name}
+	for k, v := range labels {
+		podLabels[k] = v
+	}
+	replicas := int32(1)
+	depl := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  latencyServerContainer,
+						Image: fortioImage(),
+						Args:  []string{"server", "-http-port", strconv.Itoa(fortioPort)},
+						Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: fortioPort}},
+						Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("500m"),
+								corev1.ResourceMemory: resource.MustParse("64Mi"),
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Spec: corev1.ServiceSpec{
+			Selector: selector,
+			Ports:    []corev1.ServicePort{{Name: "http", Port: fortioPort, TargetPort: intstr.FromInt(fortioPort)}},
+		},
+	}
+	return depl, svc
+}
+
+// measureLatency deploys an echo service in a namespace and puts it under load from a pod of the same
+// namespace, so both ends have sidecars or neither has
+func (oClient *Client) measureLatency(ctx context.Context, d *deployment, name, namespace string, load latencyLoad) (*meshes.LatencyResult, error) {
+	depl, svc := latencyServer(name, namespace, d.managedLabels())
+	workingOn(ctx, "deploying the echo service %s/%s", namespace, name)
+	if _, err := oClient.clientset(ctx).AppsV1().Deployments(namespace).Create(depl); err != nil {
+		return nil, errors.Wrapf(err, "unable to create deployment %s/%s", namespace, name)
+	}
+	defer func() {
+		propagation := metav1.DeletePropagationForeground
+		if err := oClient.clientset(ctx).AppsV1().Deployments(namespace).Delete(name, &metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil {
+			logrus.Warnf("Unable to delete the echo deployment %s/%s: %v", namespace, name, err)
+		}
+	}()
+	if _, err := oClient.clientset(ctx).CoreV1().Services(namespace).Create(svc); err != nil {
+		return nil, errors.Wrapf(err, "unable to create service %s/%s", namespace, name)
+	}
+	defer func() {
+		if err := oClient.clientset(ctx).CoreV1().Services(namespace).Delete(name, &metav1.DeleteOptions{}); err != nil {
+			logrus.Warnf("Unable to delete the echo service %s/%s: %v", namespace, name, err)
+		}
+	}()
+	progressed(ctx)
+	if err := oClient.waitForDeployments(ctx, namespace, []string{name}); err != nil {
+		return nil, err
+	}
+
+	target := fmt.Sprintf("http://%s.%s.svc:%d/echo", name, namespace, fortioPort)
+	pod := probePod(name+"-load", namespace, latencyLoadContainer, "", d.managedLabels(), nil)
+	container := &pod.Spec.Containers[0]
+	container.Image = fortioImage()
+	container.Command = nil
+	container.Args = []string{"load", "-quiet", "-json", "-",
+		"-t", load.duration.String(), "-c", strconv.Itoa(load.connections), "-qps", strconv.Itoa(load.qps),
+		"-p", "50,95,99", target}
+	container.Resources.Limits = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("64Mi"),
+	}
+	log, err := oClient.runProbe(ctx, pod)
+	if err != nil {
+		return nil, err
+	}
+	return parseFortioResult(log)
+}
+
+// executeLatencyProbe measures what the sidecars of a deployment cost: the same echo service is put under
+// the same load in a temporary namespace without sidecars and in an injected namespace, and the difference
+// of their latency percentiles and throughput is reported
+func (oClient *Client) executeLatencyProbe(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	if oClient.clientset(ctx) == nil {
+		return fmt.Errorf("error: mesh client has not been created")
+	}
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	load, err := parseLatencyLoad(params)
+	if err != nil {
+		return err
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return err
+	}
+	namespace := arReq.GetNamespace()
+	if namespace == "" {
+		return fmt.Errorf("error: the injected namespace to probe the latency in is required")
+	}
+	if err := oClient.requireInjected(ctx, d, namespace); err != nil {
+		return err
+	}
+
+	id := newOperationID()[:8]
+	name := resourceName("octarine-latency-" + id)
+	plainNamespace := name
+	labels := d.managedLabels()
+	labels[sampleAppLabel] = sampleProbe
+	workingOn(ctx, "creating the namespace %s without sidecars", plainNamespace)
+	if _, err := oClient.clientset(ctx).CoreV1().Namespaces().Create(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: plainNamespace, Labels: labels},
+	}); err != nil {
+		return errors.Wrapf(err, "unable to create namespace %s", plainNamespace)
+	}
+	defer func() {
+		err := oClient.clientset(ctx).CoreV1().Namespaces().Delete(plainNamespace, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			logrus.Warnf("Unable to delete the namespace %s of the latency probe: %v", plainNamespace, err)
+		}
+	}()
+	progressed(ctx)
+
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Probing the latency added by the sidecars in namespace %s", namespace),
+		Details: fmt.Sprintf("Each side is put under load for %s over %d connections, against namespace %s without sidecars first",
+			load.duration, load.connections, plainNamespace),
+	}
+	plain, err := oClient.measureLatency(ctx, d, name, plainNamespace, load)
+	if err != nil {
+		return errors.Wrapf(err, "unable to measure the latency without sidecars")
+	}
+	meshed, err := oClient.measureLatency(ctx, d, name, namespace, load)
+	if err != nil {
+		return errors.Wrapf(err, "unable to measure the latency with sidecars")
+	}
+
+	report := &meshes.LatencyProbeReportResponse{
+		Namespace:     namespace,
+		Deployment:    d.name,
+		OperationId:   arReq.GetOperationId(),
+		ProbedAt:      time.Now().UTC().Format(time.RFC3339),
+		Duration:      load.duration.String(),
+		Connections:   int32(load.connections),
+		Qps:           int32(load.qps),
+		Plain:         plain,
+		Meshed:        meshed,
+		P50OverheadMs: meshed.P50Ms - plain.P50Ms,
+		P95OverheadMs: meshed.P95Ms - plain.P95Ms,
+		P99OverheadMs: meshed.P99Ms - plain.P99Ms,
+	}
+	if plain.Qps > 0 {
+		report.ThroughputDeltaPercent = (meshed.Qps - plain.Qps) / plain.Qps * 100
+	}
+	oClient.latencyMu.Lock()
+	if oClient.latencyProbes == nil {
+		oClient.latencyProbes = map[string]*meshes.LatencyProbeReportResponse{}
+	}
+	oClient.latencyProbes[namespace] = report
+	oClient.latencyMu.Unlock()
+
+	event := &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary: fmt.Sprintf("The sidecars in namespace %s add %.2fms at p50, %.2fms at p99, throughput %+.1f%%",
+			namespace, report.P50OverheadMs, report.P99OverheadMs, report.ThroughputDeltaPercent),
+		Details: strings.Join([]string{
+			latencyLine("without sidecars", plain),
+			latencyLine("with sidecars", meshed),
+			fmt.Sprintf("overhead: p50 %+.2fms, p95 %+.2fms, p99 %+.2fms", report.P50OverheadMs, report.P95OverheadMs, report.P99OverheadMs),
+		}, "\n"),
+	}
+	if plain.Errors > 0 || meshed.Errors > 0 {
+		event.EventType = meshes.EventType_WARN
+		event.Details += "\nrequests failed during the probe, the results are skewed; in enforce mode the policies may block the probe"
+	}
+	oClient.eventChan <- event
+	return nil
+}
+
+func latencyLine(side string, r *meshes.LatencyResult) string {
+	return fmt.Sprintf("%s: p50 %.2fms, p95 %.2fms, p99 %.2fms, avg %.2fms, %.1f qps, %d requests, %d errors",
+		side, r.P50Ms, r.P95Ms, r.P99Ms, r.AvgMs, r.Qps, r.Requests, r.Errors)
+}
+
+// LatencyProbeReport returns the result of the last latency probe of an injected namespace
+func (oClient *Client) LatencyProbeReport(ctx context.Context, req *meshes.LatencyProbeReportRequest) (*meshes.LatencyProbeReportResponse, error) {
+	if oClient.clientset(ctx) == nil {
+		return &meshes.LatencyProbeReportResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	if req.GetNamespace() == "" {
+		return &meshes.LatencyProbeReportResponse{Error: "error: a namespace is required"}, nil
+	}
+	oClient.latencyMu.Lock()
+	report := oClient.latencyProbes[req.GetNamespace()]
+	oClient.latencyMu.Unlock()
+	if report == nil {
+		return &meshes.LatencyProbeReportResponse{Error: fmt.Sprintf("error: the latency of namespace %s was not probed since the adapter started, run %s first", req.GetNamespace(), latencyProbeCommand)}, nil
+	}
+	return report, nil
+}
@@ -0,0 +1,268 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os/exec"
+	"sort"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	licenseCheckIntervalEnv = "OCTARINE_LICENSE_CHECK_INTERVAL"
+	// licenseWarnPercentEnv is the share of an entitlement in use, and licenseWarnDaysEnv the days left
+	// before the license expires, from which the adapter warns
+	licenseWarnPercentEnv = "OCTARINE_LICENSE_WARN_PERCENT"
+	licenseWarnDaysEnv    = "OCTARINE_LICENSE_WARN_DAYS"
+
+	defaultLicenseCheckInterval = time.Hour
+	defaultLicenseWarnPercent   = 80
+	defaultLicenseWarnDays      = 30
+	licenseCheckTimeout         = time.Minute
+)
+
+// accountLicense is the license of an Octarine account as octactl reports it
+type accountLicense struct {
+	Tier string `json:"tier"`
+	// Expires is empty for a license which doesn't expire
+	Expires      string               `json:"expires,omitempty"`
+	Features     []string             `json:"features"`
+	Entitlements []licenseEntitlement `json:"entitlements"`
+}
+
+// licenseEntitlement is what a license grants of something it counts, a zero limit doesn't limit it
+type licenseEntitlement struct {
+	Name  string `json:"name"`
+	Used  int64  `json:"used"`
+	Limit int64  `json:"limit"`
+}
+
+// cachedLicense is the license of a deployment the control plane reported last, nil along with the error
+// when it couldn't be asked
+type cachedLicense struct {
+	license *accountLicense
+	err     error
+	fetched time.Time
+}
+
+// licenseWarning is a limit an account is close to or past, the key tells the warnings apart across checks
+type licenseWarning struct {
+	key     string
+	message string
+}
+
+// startLicenseWatch checks the licenses of the deployments every OCTARINE_LICENSE_CHECK_INTERVAL, once
+func (oClient *Client) startLicenseWatch() {
+	oClient.licenseWatchOnce.Do(func() {
+		interval := durationFromEnv(licenseCheckIntervalEnv, defaultLicenseCheckInterval)
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for now := range ticker.C {
+				oClient.checkLicenses(now)
+			}
+		}()
+	})
+}
+
+// checkLicenses asks the control plane for the license of every deployment and warns about the limits
+// they come close to
+func (oClient *Client) checkLicenses(now time.Time) {
+	oClient.deploymentsMu.Lock()
+	deployments := make([]*deployment, 0, len(oClient.deployments))
+	for _, d := range oClient.deployments {
+		if d.account != "" {
+			deployments = append(deployments, d)
+		}
+	}
+	oClient.deploymentsMu.Unlock()
+	sort.Slice(deployments, func(i, j int) bool { return deployments[i].name < deployments[j].name })
+	for _, d := range deployments {
+		// a control plane which doesn't answer doesn't hold up the checks after it
+		ctx, cancel := context.WithTimeout(context.Background(), licenseCheckTimeout)
+		license, err := oClient.refreshLicense(ctx, d)
+		cancel()
+		if err != nil {
+			logrus.Warnf("Unable to check the license of deployment %s: %v", d.name, err)
+			continue
+		}
+		oClient.reportLicenseWarnings(d, licenseWarnings(license, now))
+	}
+}
+
+// cachedLicenseOf is the license of a deployment, asked again once older than OCTARINE_LICENSED_FEATURES_TTL.
+// Failures are kept as well, so a control plane that is down isn't asked each time.
+func (oClient *Client) cachedLicenseOf(ctx context.Context, d *deployment) (*accountLicense, error) {
+	oClient.licenseMu.Lock()
+	cached, ok := oClient.licenses[d.name]
+	oClient.licenseMu.Unlock()
+	if ok && time.Since(cached.fetched) < durationFromEnv(licensedFeaturesTTLEnv, defaultLicensedFeaturesTTL) {
+		return cached.license, cached.err
+	}
+	return oClient.refreshLicense(ctx, d)
+}
+
+// refreshLicense asks the control plane for the license of a deployment and caches it
+func (oClient *Client) refreshLicense(ctx context.Context, d *deployment) (*accountLicense, error) {
+	license, err := oClient.fetchLicense(ctx, d)
+	oClient.licenseMu.Lock()
+	defer oClient.licenseMu.Unlock()
+	if oClient.licenses == nil {
+		oClient.licenses = map[string]*cachedLicense{}
+	}
+	oClient.licenses[d.name] = &cachedLicense{license: license, err: err, fetched: time.Now()}
+	return license, err
+}
+
+func (oClient *Client) fetchLicense(ctx context.Context, d *deployment) (*accountLicense, error) {
+	var out []byte
+	err := oClient.asAccount(ctx, d, func() error {
+		var err error
+		out, err = exec.CommandContext(ctx, "octactl", "account", "license", d.account, "--output", "json").Output()
+		return errors.Wrapf(err, "unable to get the license of account %s", d.account)
+	})
+	if err != nil {
+		return nil, err
+	}
+	license := &accountLicense{}
+	if err := json.Unmarshal(out, license); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the license of account %s", d.account)
+	}
+	if _, err := time.Parse(time.RFC3339, license.Expires); license.Expires != "" && err != nil {
+		return nil, errors.Wrapf(err, "the license of account %s expires at an invalid time", d.account)
+	}
+	return license, nil
+}
+
+// daysLeft are the whole days until a license expires, negative once it expired
+func (l *accountLicense) daysLeft(now time.Time) (int, bool) {
+	if l.Expires == "" {
+		return 0, false
+	}
+	expires, err := time.Parse(time.RFC3339, l.Expires)
+	if err != nil {
+		return 0, false
+	}
+	return int(math.Floor(expires.Sub(now).Hours() / 24)), true
+}
+
+// licenseWarnings are the limits of a license in use beyond OCTARINE_LICENSE_WARN_PERCENT, and its expiry
+// within OCTARINE_LICENSE_WARN_DAYS
+func licenseWarnings(l *accountLicense, now time.Time) []licenseWarning {
+	warnings := []licenseWarning{}
+	if days, ok := l.daysLeft(now); ok {
+		switch {
+		case days < 0:
+			warnings = append(warnings, licenseWarning{"expiry/expired", fmt.Sprintf("the %s license expired on %s", l.Tier, l.Expires)})
+		case days <= intFromEnv(licenseWarnDaysEnv, defaultLicenseWarnDays):
+			warnings = append(warnings, licenseWarning{"expiry/approaching", fmt.Sprintf("the %s license expires in %d days, on %s", l.Tier, days, l.Expires)})
+		}
+	}
+	percent := int64(intFromEnv(licenseWarnPercentEnv, defaultLicenseWarnPercent))
+	for _, e := range l.Entitlements {
+		switch {
+		case e.Limit <= 0:
+		case e.Used >= e.Limit:
+			warnings = append(warnings, licenseWarning{e.Name + "/exhausted", fmt.Sprintf("%d of the %d %s the license covers are in use, none are left", e.Used, e.Limit, e.Name)})
+		case e.Used*100 >= e.Limit*percent:
+			warnings = append(warnings, licenseWarning{e.Name + "/approaching", fmt.Sprintf("%d of the %d %s the license covers are in use, %d are left", e.Used, e.Limit, e.Name, e.Limit-e.Used)})
+		}
+	}
+	return warnings
+}
+
+// reportLicenseWarnings sends a WARN event for each warning a deployment didn't have at the last check, a
+// warning gone since is sent again when it comes back
+func (oClient *Client) reportLicenseWarnings(d *deployment, warnings []licenseWarning) {
+	oClient.licenseMu.Lock()
+	if oClient.licenseWarned == nil {
+		oClient.licenseWarned = map[string]map[string]bool{}
+	}
+	warned := oClient.licenseWarned[d.name]
+	current := make(map[string]bool, len(warnings))
+	fresh := []licenseWarning{}
+	for _, w := range warnings {
+		current[w.key] = true
+		if !warned[w.key] {
+			fresh = append(fresh, w)
+		}
+	}
+	oClient.licenseWarned[d.name] = current
+	oClient.licenseMu.Unlock()
+	for _, w := range fresh {
+		oClient.eventChan <- &meshes.EventsResponse{
+			EventType: meshes.EventType_WARN,
+			Summary:   fmt.Sprintf("The Octarine license of deployment %s is running out", d.name),
+			Details:   w.message,
+			Source:    sourceLicense,
+		}
+	}
+}
+
+// LicenseStatus reports the license of the Octarine account of a deployment and what it uses of it, asking
+// the control plane each time
+func (oClient *Client) LicenseStatus(ctx context.Context, req *meshes.LicenseStatusRequest) (*meshes.LicenseStatusResponse, error) {
+	if name := req.GetCluster(); name != oClient.cluster {
+		target, err := oClient.clusterClient(name)
+		if err != nil {
+			return &meshes.LicenseStatusResponse{Error: err.Error()}, nil
+		}
+		return target.LicenseStatus(ctx, req)
+	}
+	d, err := oClient.getDeployment(req.GetDeployment())
+	if err != nil {
+		return &meshes.LicenseStatusResponse{Error: err.Error()}, nil
+	}
+	license, err := oClient.refreshLicense(ctx, d)
+	if err != nil {
+		return &meshes.LicenseStatusResponse{Error: err.Error()}, nil
+	}
+	now := time.Now()
+	warnings := licenseWarnings(license, now)
+	oClient.reportLicenseWarnings(d, warnings)
+
+	locale := catalogFrom(ctx)
+	resp := &meshes.LicenseStatusResponse{
+		Account:  d.account,
+		Tier:     license.Tier,
+		Expires:  license.Expires,
+		Features: append([]string{}, license.Features...),
+	}
+	if days, ok := license.daysLeft(now); ok {
+		resp.DaysLeft = int32(days)
+	}
+	sort.Strings(resp.Features)
+	for _, e := range license.Entitlements {
+		available := int64(-1)
+		if e.Limit > 0 {
+			if available = e.Limit - e.Used; available < 0 {
+				available = 0
+			}
+		}
+		resp.Entitlements = append(resp.Entitlements, &meshes.LicenseEntitlement{Name: e.Name, Used: e.Used, Limit: e.Limit, Available: available})
+	}
+	for _, w := range warnings {
+		resp.Warnings = append(resp.Warnings, locale.translate(w.message))
+	}
+	return resp, nil
+}
@@ -0,0 +1,267 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	localesDirEnv = "OCTARINE_LOCALES_DIR"
+	// localeMetadataKey is the gRPC metadata the languages of the caller are read from, in the format of the
+	// Accept-Language header, which the HTTP gateway passes as it
+	localeMetadataKey = "accept-language"
+)
+
+// builtinLocalesDir holds a JSON file per language, named after its tag, e.g. es.json or pt-br.json, mapping
+// the English messages to their translation
+var builtinLocalesDir = path.Join("octarine", "locales")
+
+// formatVerb matches the verbs of the messages of the catalogs, which are the formats the adapter writes its
+// messages with
+var formatVerb = regexp.MustCompile(`%(\[[0-9]+\])?[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z%]`)
+
+// messageCatalog translates the English messages of the adapter to a language. Messages without verbs are
+// translated as they are, the others are matched with their verbs standing for any text, which is put in
+// the translation, itself translated when the catalog has it, e.g. "Octarine %s successfully" with
+// "deployed".
+type messageCatalog struct {
+	exact    map[string]string
+	patterns []messagePattern
+}
+
+type messagePattern struct {
+	format      string
+	re          *regexp.Regexp
+	translation string
+}
+
+var (
+	catalogsOnce sync.Once
+	catalogs     map[string]*messageCatalog
+)
+
+func newMessageCatalog(messages map[string]string) *messageCatalog {
+	c := &messageCatalog{exact: map[string]string{}}
+	for format, translation := range messages {
+		if !formatVerb.MatchString(strings.Replace(format, "%%", "", -1)) {
+			c.exact[strings.Replace(format, "%%", "%", -1)] = translation
+			continue
+		}
+		// every argument is captured as text, so the translation takes them with %s, or %[n]s to reorder them
+		expr := "(?s)^"
+		last := 0
+		for _, loc := range formatVerb.FindAllStringIndex(format, -1) {
+			expr += regexp.QuoteMeta(format[last:loc[0]])
+			if format[loc[0]:loc[1]] == "%%" {
+				expr += "%"
+			} else {
+				expr += "(.*?)"
+			}
+			last = loc[1]
+		}
+		expr += regexp.QuoteMeta(format[last:]) + "$"
+		c.patterns = append(c.patterns, messagePattern{
+			format:      format,
+			re:          regexp.MustCompile(expr),
+			translation: formatVerb.ReplaceAllStringFunc(translation, textVerb),
+		})
+	}
+	// the longest formats are the most specific
+	sort.Slice(c.patterns, func(i, j int) bool {
+		if len(c.patterns[i].format) != len(c.patterns[j].format) {
+			return len(c.patterns[i].format) > len(c.patterns[j].format)
+		}
+		return c.patterns[i].format < c.patterns[j].format
+	})
+	return c
+}
+
+// textVerb turns a verb of a translation into the %s of its argument, keeping its index
+func textVerb(verb string) string {
+	if verb == "%%" {
+		return verb
+	}
+	if m := formatVerb.FindStringSubmatch(verb); m[1] != "" {
+		return "%" + m[1] + "s"
+	}
+	return "%s"
+}
+
+// translate returns the message in the language of the catalog, as it is when it has no translation
+func (c *messageCatalog) translate(msg string) string {
+	if c == nil || msg == "" {
+		return msg
+	}
+	if t, ok := c.exact[msg]; ok {
+		return t
+	}
+	for _, p := range c.patterns {
+		m := p.re.FindStringSubmatch(msg)
+		if m == nil {
+			continue
+		}
+		args := make([]interface{}, 0, len(m)-1)
+		for _, arg := range m[1:] {
+			if t, ok := c.exact[arg]; ok {
+				arg = t
+			}
+			args = append(args, arg)
+		}
+		return fmt.Sprintf(p.translation, args...)
+	}
+	return msg
+}
+
+func localesDir() string {
+	if dir := os.Getenv(localesDirEnv); dir != "" {
+		return dir
+	}
+	return builtinLocalesDir
+}
+
+// loadCatalogs reads the catalogs of localesDir, once, a broken catalog is logged and left out
+func loadCatalogs() map[string]*messageCatalog {
+	catalogsOnce.Do(func() {
+		catalogs = map[string]*messageCatalog{}
+		dir := localesDir()
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				logrus.Warn(errors.Wrapf(err, "unable to read the message catalogs of %s", dir))
+			}
+			return
+		}
+		for _, f := range files {
+			if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
+				continue
+			}
+			file := filepath.Join(dir, f.Name())
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				logrus.Warn(errors.Wrapf(err, "unable to read message catalog %s", file))
+				continue
+			}
+			messages := map[string]string{}
+			if err := json.Unmarshal(data, &messages); err != nil {
+				logrus.Warn(errors.Wrapf(err, "unable to parse message catalog %s", file))
+				continue
+			}
+			catalogs[strings.ToLower(strings.TrimSuffix(f.Name(), ".json"))] = newMessageCatalog(messages)
+		}
+	})
+	return catalogs
+}
+
+// catalogFor picks the catalog of the first language of an Accept-Language list the adapter has one for,
+// trying the base language of a regional one, e.g. es for es-AR. English and unknown languages get none.
+func catalogFor(languages string) *messageCatalog {
+	if languages == "" {
+		return nil
+	}
+	known := loadCatalogs()
+	for _, lang := range strings.Split(languages, ",") {
+		tag := strings.ToLower(strings.TrimSpace(strings.Split(lang, ";")[0]))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		if tag == "en" || strings.HasPrefix(tag, "en-") {
+			return nil
+		}
+		if c, ok := known[tag]; ok {
+			return c
+		}
+		if i := strings.Index(tag, "-"); i > 0 {
+			if c, ok := known[tag[:i]]; ok {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// catalogFrom is the catalog of the languages of a call, from its gRPC metadata
+func catalogFrom(ctx context.Context) *messageCatalog {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+	return catalogFor(strings.Join(md.Get(localeMetadataKey), ","))
+}
+
+// localizeEvent is an event with its summary translated, a copy as the other streams share the event
+func localizeEvent(c *messageCatalog, event *meshes.EventsResponse) *meshes.EventsResponse {
+	if c == nil {
+		return event
+	}
+	localized := proto.Clone(event).(*meshes.EventsResponse)
+	localized.Summary = c.translate(event.GetSummary())
+	return localized
+}
+
+// LocaleInterceptor translates the errors of the calls to the language of their accept-language metadata,
+// both the gRPC errors and the error field of the responses
+func LocaleInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	c := catalogFrom(ctx)
+	if c == nil {
+		return resp, err
+	}
+	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			return resp, status.Error(st.Code(), c.translate(st.Message()))
+		}
+		return resp, errors.New(c.translate(err.Error()))
+	}
+	if v := reflect.ValueOf(resp); v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		if f := v.Elem().FieldByName("Error"); f.IsValid() && f.Kind() == reflect.String && f.CanSet() {
+			f.SetString(c.translate(f.String()))
+		}
+	}
+	return resp, nil
+}
+
+// LocaleStreamInterceptor translates the error a stream ends with to the language of its accept-language
+// metadata, the events it sends are translated by the stream itself
+func LocaleStreamInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	err := handler(srv, ss)
+	c := catalogFrom(ss.Context())
+	if c == nil || err == nil {
+		return err
+	}
+	if st, ok := status.FromError(err); ok {
+		return status.Error(st.Code(), c.translate(st.Message()))
+	}
+	return errors.New(c.translate(err.Error()))
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	mesheryOctarineAPIVersion = "meshery.layer5.io/v1alpha1"
+	mesheryOctarineKind       = "MesheryOctarine"
+)
+
+// MesheryOctarine is the custom resource of deploy/mesheryoctarine-crd.yaml, the operator mode reconciles its
+// spec as a MeshSpec and reports how it went in its status
+type MesheryOctarine struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MeshSpec              `json:"spec,omitempty"`
+	Status MesheryOctarineStatus `json:"status,omitempty"`
+}
+
+// MesheryOctarineStatus is the status of a MesheryOctarine resource
+type MesheryOctarineStatus struct {
+	// Phase is Reconciling, Ready or Failed
+	Phase string `json:"phase,omitempty"`
+	// ObservedGeneration is the generation of the spec the phase is about
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastReconcileTime is the RFC 3339 time the phase was set
+	LastReconcileTime string `json:"lastReconcileTime,omitempty"`
+	// Message is the error the reconciliation failed with
+	Message string `json:"message,omitempty"`
+}
+
+// mesheryOctarineFromUnstructured converts a resource read through the dynamic client, failing on fields of
+// the wrong type instead of panicking on them later
+func mesheryOctarineFromUnstructured(u *unstructured.Unstructured) (*MesheryOctarine, error) {
+	res := &MesheryOctarine{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), res); err != nil {
+		return nil, errors.Wrapf(err, "unable to read %s %s/%s", mesheryOctarineKind, u.GetNamespace(), u.GetName())
+	}
+	return res, nil
+}
+
+func (res *MesheryOctarine) toUnstructured() (*unstructured.Unstructured, error) {
+	res.APIVersion, res.Kind = mesheryOctarineAPIVersion, mesheryOctarineKind
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(res)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to write %s %s/%s", mesheryOctarineKind, res.Namespace, res.Name)
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+// mesheryOctarineClient writes MesheryOctarine resources as typed objects through the dynamic client
+type mesheryOctarineClient struct {
+	client dynamic.Interface
+}
+
+func newMesheryOctarineClient(client dynamic.Interface) *mesheryOctarineClient {
+	return &mesheryOctarineClient{client: client}
+}
+
+// updateStatus writes the status of a resource, which takes the resource version of the update
+func (c *mesheryOctarineClient) updateStatus(res *MesheryOctarine) error {
+	u, err := res.toUnstructured()
+	if err != nil {
+		return err
+	}
+	updated, err := c.client.Resource(mesheryOctarineResource).Namespace(res.Namespace).UpdateStatus(u, metav1.UpdateOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "unable to update the status of %s", res.Name)
+	}
+	res.ResourceVersion = updated.GetResourceVersion()
+	return nil
+}
@@ -0,0 +1,529 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultDataplaneNs  = "octarine-dataplane"
+	injectionLabel      = "octarine-injection"
+	injectionLabelValue = "enabled"
+
+	sampleAppBookInfo = "bookinfo"
+
+	// appliedSpecKey is the key of the anchor ConfigMap of a deployment keeping the last spec reconciled,
+	// so a restarted adapter compares a spec with what it applied before
+	appliedSpecKey = "meshspec"
+)
+
+// MeshSpec is the desired state of an Octarine deployment managed by the adapter
+type MeshSpec struct {
+	// Name of the Octarine deployment the spec describes
+	Name string `json:"name,omitempty"`
+	// Domain registered in Octarine for the deployment, defaults to OCTARINE_DOMAIN
+	Domain string `json:"domain,omitempty"`
+	// Version is the Octarine release to run, empty means whatever octactl defaults to
+	Version string `json:"version,omitempty"`
+	// Namespace is where the Octarine dataplane is deployed
+	Namespace string `json:"namespace,omitempty"`
+	// Certificates tells who issues the certificates of the dataplane when it is installed: auto,
+	// cert-manager or self-signed
+	Certificates string `json:"certificates,omitempty"`
+	// Mirror is the registry the images of the dataplane are pulled from when it is installed
+	Mirror string `json:"mirror,omitempty"`
+	// Replicas is how many replicas each component of the dataplane runs, 2 or more make it highly available
+	Replicas int `json:"replicas,omitempty"`
+	// InjectedNamespaces are labeled for automatic sidecar injection
+	InjectedNamespaces []string `json:"injectedNamespaces,omitempty"`
+	// Policies are raw YAML manifests applied as-is
+	Policies []string `json:"policies,omitempty"`
+	// SampleApps are the sample applications to keep deployed
+	SampleApps []SampleApp `json:"sampleApps,omitempty"`
+}
+
+// SampleApp identifies a sample application deployed into a namespace
+type SampleApp struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// liveSpec is what the cluster still runs of the applied spec: the namespaces labeled for injection, and the
+// policies and sample applications of the spec someone removed since
+type liveSpec struct {
+	labeled         map[string]bool
+	missingPolicies map[string]bool
+	missingApps     map[SampleApp]bool
+}
+
+type specAction struct {
+	description string
+	apply       func(ctx context.Context) error
+}
+
+func parseMeshSpec(body string) (*MeshSpec, error) {
+	spec := &MeshSpec{}
+	if err := yaml.Unmarshal([]byte(body), spec); err != nil {
+		err = errors.Wrapf(err, "unable to parse the mesh spec")
+		logrus.Error(err)
+		return nil, err
+	}
+	if err := spec.complete(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// complete defaults the name and namespace of a spec and checks its sample applications
+func (spec *MeshSpec) complete() error {
+	if spec.Name == "" {
+		spec.Name = defaultDeploymentName
+	}
+	if spec.Namespace == "" {
+		spec.Namespace = dataplaneNamespace()
+	}
+	for _, app := range spec.SampleApps {
+		if app.Name != sampleAppBookInfo {
+			return fmt.Errorf("error: %s is not a supported sample application", app.Name)
+		}
+		if app.Namespace == "" {
+			return fmt.Errorf("error: namespace is required for sample application %s", app.Name)
+		}
+	}
+	return nil
+}
+
+// injectedNamespaces returns the namespaces currently labeled for auto injection by the named deployment
+func (oClient *Client) injectedNamespaces(ctx context.Context, name string) (map[string]bool, error) {
+	d := &deployment{name: name}
+	nsList, err := oClient.clientset(ctx).CoreV1().Namespaces().List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", injectionLabel, d.injectionValue()),
+	})
+	if err != nil {
+		err = errors.Wrapf(err, "unable to list namespaces labeled for injection")
+		logrus.Error(err)
+		return nil, err
+	}
+	result := map[string]bool{}
+	for _, ns := range nsList.Items {
+		result[ns.GetName()] = true
+	}
+	return result, nil
+}
+
+// requireInjected fails unless the deployment injects its sidecars into the namespace
+func (oClient *Client) requireInjected(ctx context.Context, d *deployment, namespace string) error {
+	injected, err := oClient.injectedNamespaces(ctx, d.name)
+	if err != nil {
+		return err
+	}
+	if !injected[namespace] {
+		return fmt.Errorf("error: namespace %s is not injected by deployment %s", namespace, d.name)
+	}
+	return nil
+}
+
+func stringSet(items []string) map[string]bool {
+	result := make(map[string]bool, len(items))
+	for _, item := range items {
+		result[item] = true
+	}
+	return result
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// computeSpecDelta works out the actions needed to move from the applied spec to the desired one, and to
+// bring back the injection labels, policies and sample applications of the applied spec the cluster lost.
+// applied is nil when no spec was reconciled yet. Changes to the dataplane itself are found from the specs only.
+func (oClient *Client) computeSpecDelta(applied, desired *MeshSpec, live liveSpec) []specAction {
+	labeled := live.labeled
+	actions := []specAction{}
+	if applied == nil {
+		applied = &MeshSpec{}
+	}
+
+	switch {
+	case applied.Namespace == "":
+		actions = append(actions, specAction{
+			description: fmt.Sprintf("install Octarine deployment %s in namespace %s", desired.Name, desired.Namespace),
+			apply: func(ctx context.Context) error {
+				return oClient.installDeployment(ctx, desired.Name, desired.Namespace, desired.Domain, desired.Version, desired.Certificates, desired.Mirror, desired.Replicas)
+			},
+		})
+	case applied.Namespace != desired.Namespace || applied.Name != desired.Name || applied.Domain != desired.Domain:
+		actions = append(actions, specAction{
+			description: fmt.Sprintf("move Octarine deployment %s in namespace %s to %s in namespace %s",
+				applied.Name, applied.Namespace, desired.Name, desired.Namespace),
+			apply: func(ctx context.Context) error {
+				if err := oClient.uninstallDeployment(ctx, applied.Name, applied.Namespace); err != nil {
+					return err
+				}
+				return oClient.installDeployment(ctx, desired.Name, desired.Namespace, desired.Domain, desired.Version, desired.Certificates, desired.Mirror, desired.Replicas)
+			},
+		})
+	case applied.Version != desired.Version:
+		actions = append(actions, specAction{
+			description: fmt.Sprintf("change Octarine dataplane version from %q to %q", applied.Version, desired.Version),
+			apply: func(ctx context.Context) error {
+				d, err := oClient.getDeployment(desired.Name)
+				if err != nil {
+					return err
+				}
+				if err := oClient.backupBeforeUpgrade(ctx, d, desired.Version); err != nil {
+					return err
+				}
+				d.version = desired.Version
+				d.replicas = desired.Replicas
+				if err := oClient.reapplyDataplane(ctx, d, applied.Replicas); err != nil {
+					return err
+				}
+				d.updatedAt = time.Now()
+				return nil
+			},
+		})
+	case applied.Replicas != desired.Replicas:
+		actions = append(actions, specAction{
+			description: fmt.Sprintf("change the replicas of the Octarine dataplane from %d to %d", applied.Replicas, desired.Replicas),
+			apply: func(ctx context.Context) error {
+				d, err := oClient.getDeployment(desired.Name)
+				if err != nil {
+					return err
+				}
+				d.replicas = desired.Replicas
+				if err := oClient.reapplyDataplane(ctx, d, applied.Replicas); err != nil {
+					return err
+				}
+				d.updatedAt = time.Now()
+				return nil
+			},
+		})
+	}
+
+	wantInjected := stringSet(desired.InjectedNamespaces)
+	for _, ns := range sortedKeys(wantInjected) {
+		if labeled[ns] {
+			continue
+		}
+		namespace := ns
+		actions = append(actions, specAction{
+			description: fmt.Sprintf("enable injection in namespace %s", namespace),
+			apply: func(ctx context.Context) error {
+				d, err := oClient.getDeployment(desired.Name)
+				if err != nil {
+					return err
+				}
+				return oClient.labelNamespaceForAutoInjection(ctx, namespace, d)
+			},
+		})
+	}
+	// only namespaces labeled through a previous spec are unlabeled, others were labeled by someone else
+	for _, ns := range sortedKeys(stringSet(applied.InjectedNamespaces)) {
+		if wantInjected[ns] || !labeled[ns] {
+			continue
+		}
+		namespace := ns
+		actions = append(actions, specAction{
+			description: fmt.Sprintf("disable injection in namespace %s", namespace),
+			apply: func(ctx context.Context) error {
+				return oClient.restoreInjectionLabel(ctx, namespace)
+			},
+		})
+	}
+
+	wantPolicies := stringSet(desired.Policies)
+	havePolicies := stringSet(applied.Policies)
+	for i, policy := range desired.Policies {
+		if havePolicies[policy] && !live.missingPolicies[policy] {
+			continue
+		}
+		description := fmt.Sprintf("apply policy #%d", i+1)
+		if havePolicies[policy] {
+			description = fmt.Sprintf("apply policy #%d again, it was removed from the cluster", i+1)
+		}
+		body := policy
+		actions = append(actions, specAction{
+			description: description,
+			apply: func(ctx context.Context) error {
+				return oClient.applyConfigChange(ctx, body, "", false)
+			},
+		})
+	}
+	for _, policy := range applied.Policies {
+		if wantPolicies[policy] {
+			continue
+		}
+		body := policy
+		actions = append(actions, specAction{
+			description: "delete policy no longer in the spec",
+			apply: func(ctx context.Context) error {
+				return oClient.applyConfigChange(ctx, body, "", true)
+			},
+		})
+	}
+
+	wantApps := map[SampleApp]bool{}
+	for _, app := range desired.SampleApps {
+		wantApps[app] = true
+	}
+	haveApps := map[SampleApp]bool{}
+	for _, app := range applied.SampleApps {
+		haveApps[app] = true
+	}
+	for _, app := range desired.SampleApps {
+		if haveApps[app] && !live.missingApps[app] {
+			continue
+		}
+		description := fmt.Sprintf("deploy %s in namespace %s", app.Name, app.Namespace)
+		if haveApps[app] {
+			description = fmt.Sprintf("deploy %s in namespace %s again, it was removed from the cluster", app.Name, app.Namespace)
+		}
+		sample := app
+		actions = append(actions, specAction{
+			description: description,
+			apply: func(ctx context.Context) error {
+				return oClient.executeBookInfoInstall(ctx, &meshes.ApplyRuleRequest{
+					Namespace:  sample.Namespace,
+					CustomBody: fmt.Sprintf("deployment: %s", desired.Name),
+				})
+			},
+		})
+	}
+	for _, app := range applied.SampleApps {
+		if wantApps[app] {
+			continue
+		}
+		sample := app
+		actions = append(actions, specAction{
+			description: fmt.Sprintf("remove %s from namespace %s", sample.Name, sample.Namespace),
+			apply: func(ctx context.Context) error {
+				return oClient.executeBookInfoInstall(ctx, &meshes.ApplyRuleRequest{Namespace: sample.Namespace, DeleteOp: true})
+			},
+		})
+	}
+	return actions
+}
+
+// liveSpecOf checks what the cluster still runs of the applied spec, the objects which can't be read are
+// taken as present rather than applied again
+func (oClient *Client) liveSpecOf(ctx context.Context, applied, desired *MeshSpec) (liveSpec, error) {
+	labeled, err := oClient.injectedNamespaces(ctx, desired.Name)
+	if err != nil {
+		return liveSpec{}, err
+	}
+	live := liveSpec{labeled: labeled, missingPolicies: map[string]bool{}, missingApps: map[SampleApp]bool{}}
+	if applied == nil {
+		return live, nil
+	}
+	for _, policy := range applied.Policies {
+		objects, err := parseManifestObjects(policy)
+		if err != nil {
+			continue
+		}
+		for _, obj := range objects {
+			_, err := oClient.dynamicClient(ctx).Resource(resourceFor(obj)).Namespace(obj.GetNamespace()).Get(obj.GetName(), metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				live.missingPolicies[policy] = true
+				break
+			}
+		}
+	}
+	for _, app := range applied.SampleApps {
+		samples, err := oClient.findSamples(ctx, app.Namespace)
+		if err != nil {
+			continue
+		}
+		found := false
+		for _, sample := range samples {
+			if sample.data.GetLabels()[sampleAppLabel] == app.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			live.missingApps[app] = true
+		}
+	}
+	return live, nil
+}
+
+// loadAppliedSpec reads the last spec reconciled for a deployment from its anchor, nil when there is none
+func (oClient *Client) loadAppliedSpec(ctx context.Context, name string) (*MeshSpec, error) {
+	d := &deployment{name: name}
+	anchors, err := oClient.clientset(ctx).CoreV1().ConfigMaps(metav1.NamespaceAll).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%s", managedByLabel, managedByValue, deploymentNameLabel, d.name),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the anchors of deployment %s", name)
+	}
+	for _, anchor := range anchors.Items {
+		data, ok := anchor.Data[appliedSpecKey]
+		if anchor.GetName() != resourceName(anchorName) || !ok {
+			continue
+		}
+		spec := &MeshSpec{}
+		if err := json.Unmarshal([]byte(data), spec); err != nil {
+			return nil, errors.Wrapf(err, "unable to parse the mesh spec of anchor %s/%s", anchor.GetNamespace(), anchor.GetName())
+		}
+		return spec, nil
+	}
+	return nil, nil
+}
+
+// saveAppliedSpec keeps a reconciled spec on the anchor of its deployment
+func (oClient *Client) saveAppliedSpec(ctx context.Context, spec *MeshSpec) error {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return errors.Wrapf(err, "unable to write the mesh spec")
+	}
+	anchors := oClient.clientset(ctx).CoreV1().ConfigMaps(spec.Namespace)
+	anchor, err := anchors.Get(resourceName(anchorName), metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "unable to get the anchor of deployment %s", spec.Name)
+	}
+	if anchor.Data == nil {
+		anchor.Data = map[string]string{}
+	}
+	anchor.Data[appliedSpecKey] = string(data)
+	if _, err := anchors.Update(anchor); err != nil {
+		return errors.Wrapf(err, "unable to keep the mesh spec on the anchor of deployment %s", spec.Name)
+	}
+	return nil
+}
+
+// reconcileMeshSpec drives the cluster towards the given spec, a nil spec re-runs the last submitted one
+func (oClient *Client) reconcileMeshSpec(ctx context.Context, operationID string, desired *MeshSpec) error {
+	workingOn(ctx, "waiting for the mesh spec reconciliation in progress to finish")
+	oClient.specMu.Lock()
+	defer oClient.specMu.Unlock()
+	workingOn(ctx, "reconciling the mesh spec")
+
+	// re-running the same spec only finds changes to make when the cluster drifted from it
+	rerun := desired == nil
+	name := defaultDeploymentName
+	if desired != nil {
+		name = desired.Name
+	} else if oClient.desiredSpec != nil {
+		name = oClient.desiredSpec.Name
+	}
+	if oClient.appliedSpec == nil || oClient.appliedSpec.Name != name {
+		applied, err := oClient.loadAppliedSpec(ctx, name)
+		if err != nil {
+			logrus.Warnf("reconciling as if no mesh spec was applied: %v", err)
+		}
+		oClient.appliedSpec = applied
+	}
+	if rerun {
+		desired = oClient.desiredSpec
+		if desired == nil {
+			// after a restart the last spec submitted is the one applied
+			desired = oClient.appliedSpec
+		}
+	}
+	if desired == nil {
+		err := errors.New("no mesh spec has been submitted yet")
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: operationID,
+			EventType:   meshes.EventType_ERROR,
+			Summary:     "Error while reconciling the mesh spec",
+			Details:     err.Error(),
+		}
+		return err
+	}
+	oClient.desiredSpec = desired
+
+	live, err := oClient.liveSpecOf(ctx, oClient.appliedSpec, desired)
+	if err != nil {
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: operationID,
+			EventType:   meshes.EventType_ERROR,
+			Summary:     "Error while reconciling the mesh spec",
+			Details:     err.Error(),
+		}
+		return err
+	}
+
+	actions := oClient.computeSpecDelta(oClient.appliedSpec, desired, live)
+	if len(actions) == 0 {
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: operationID,
+			EventType:   meshes.EventType_INFO,
+			Summary:     "Mesh spec already reconciled",
+			Details:     "The cluster is in the desired state, no changes were made.",
+		}
+		return nil
+	}
+
+	descriptions := make([]string, len(actions))
+	for i, action := range actions {
+		descriptions[i] = action.description
+	}
+	event := &meshes.EventsResponse{
+		OperationId: operationID,
+		Severity:    meshes.Severity_SEVERITY_INFO,
+		Summary:     fmt.Sprintf("Reconciling the mesh spec with %d change(s)", len(actions)),
+		Details:     strings.Join(descriptions, "\n"),
+	}
+	if rerun {
+		event.Severity = meshes.Severity_SEVERITY_WARN
+		event.Summary = fmt.Sprintf("The cluster drifted from the mesh spec, reconciling it with %d change(s)", len(actions))
+	}
+	oClient.eventChan <- event
+
+	for _, action := range actions {
+		if err := action.apply(ctx); err != nil {
+			oClient.eventChan <- &meshes.EventsResponse{
+				OperationId: operationID,
+				EventType:   meshes.EventType_ERROR,
+				Summary:     fmt.Sprintf("Error while trying to %s", action.description),
+				Details:     stallError(ctx, err).Error(),
+			}
+			return errors.Wrapf(stallError(ctx, err), "unable to %s", action.description)
+		}
+		logrus.Infof("mesh spec: completed %s", action.description)
+	}
+
+	oClient.appliedSpec = desired
+	if err := oClient.saveAppliedSpec(ctx, desired); err != nil {
+		logrus.Warnf("a restarted adapter will reconcile the mesh spec as if it wasn't applied: %v", err)
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: operationID,
+		EventType:   meshes.EventType_INFO,
+		Summary:     "Mesh spec reconciled successfully",
+		Details:     fmt.Sprintf("Applied %d change(s) to reach the desired state.", len(actions)),
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComputeSpecDelta(t *testing.T) {
+	bookinfo := SampleApp{Name: sampleAppBookInfo, Namespace: "demo"}
+	applied := &MeshSpec{
+		Name:               defaultDeploymentName,
+		Namespace:          defaultDataplaneNs,
+		InjectedNamespaces: []string{"demo"},
+		Policies:           []string{"kind: Policy"},
+		SampleApps:         []SampleApp{bookinfo},
+	}
+	tests := []struct {
+		name    string
+		applied *MeshSpec
+		live    liveSpec
+		want    []string
+	}{
+		{
+			name:    "reconciled",
+			applied: applied,
+			live:    liveSpec{labeled: map[string]bool{"demo": true}},
+			want:    []string{},
+		},
+		{
+			name:    "not applied yet",
+			applied: nil,
+			want: []string{
+				"install Octarine deployment default in namespace octarine-dataplane",
+				"enable injection in namespace demo",
+				"apply policy #1",
+				"deploy bookinfo in namespace demo",
+			},
+		},
+		{
+			name:    "label removed",
+			applied: applied,
+			want:    []string{"enable injection in namespace demo"},
+		},
+		{
+			name:    "policy removed",
+			applied: applied,
+			live:    liveSpec{labeled: map[string]bool{"demo": true}, missingPolicies: map[string]bool{"kind: Policy": true}},
+			want:    []string{"apply policy #1 again, it was removed from the cluster"},
+		},
+		{
+			name:    "sample removed",
+			applied: applied,
+			live:    liveSpec{labeled: map[string]bool{"demo": true}, missingApps: map[SampleApp]bool{bookinfo: true}},
+			want:    []string{"deploy bookinfo in namespace demo again, it was removed from the cluster"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := *applied
+			actions := (&Client{}).computeSpecDelta(tt.applied, &desired, tt.live)
+			got := []string{}
+			for _, action := range actions {
+				got = append(got, action.description)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("computeSpecDelta() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
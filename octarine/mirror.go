@@ -0,0 +1,489 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	mirrorToolSkopeo = "skopeo"
+	mirrorToolCrane  = "crane"
+
+	defaultSkopeoImage = "quay.io/skopeo/stable:v1.14"
+	// the debug tag of crane has the shell the copy script runs in
+	defaultCraneImage = "gcr.io/go-containerregistry/crane:debug"
+
+	// imageMirrorEnv is the registry new deployments pull their images from when the install doesn't name one
+	imageMirrorEnv = "OCTARINE_IMAGE_MIRROR"
+
+	mirrorJobName        = "octarine-mirror"
+	mirrorSourceSecret   = "octarine-mirror-source"
+	mirrorPushSecretPath = "/etc/octarine-mirror"
+	// copying a release takes minutes, the deadline keeps a stuck copy from lingering
+	mirrorJobDeadline = 30 * time.Minute
+)
+
+// mirrorToolImages are the images the copy Job runs, OCTARINE_SKOPEO_IMAGE and OCTARINE_CRANE_IMAGE
+// replace them when the defaults can't be pulled
+var mirrorToolImages = map[string]struct{ env, image string }{
+	mirrorToolSkopeo: {"OCTARINE_SKOPEO_IMAGE", defaultSkopeoImage},
+	mirrorToolCrane:  {"OCTARINE_CRANE_IMAGE", defaultCraneImage},
+}
+
+func mirrorToolImage(tool string) string {
+	image := mirrorToolImages[tool]
+	if value := os.Getenv(image.env); value != "" {
+		return value
+	}
+	return image.image
+}
+
+// imageToken is a run of characters which may be an image reference inside an argument, an environment
+// variable or the data of a ConfigMap
+var imageToken = regexp.MustCompile(`[^\s"',;=]+`)
+
+// validateMirror checks a registry images are mirrored to: a host with an optional path, without a scheme
+// or a tag
+func validateMirror(mirror string) error {
+	if mirror == "" {
+		return nil
+	}
+	if strings.Contains(mirror, "://") {
+		return fmt.Errorf("error: mirror %s must not have a scheme, use registry.example.com/octarine", mirror)
+	}
+	host := strings.SplitN(mirror, "/", 2)[0]
+	if !strings.ContainsAny(host, ".:") && host != "localhost" {
+		return fmt.Errorf("error: mirror %s must start with the host of a registry, like registry.example.com", mirror)
+	}
+	if strings.Contains(mirror, "@") || strings.Contains(mirror[len(host):], ":") || strings.HasSuffix(mirror, "/") {
+		return fmt.Errorf("error: mirror %s must be a registry and a path, without a tag", mirror)
+	}
+	return nil
+}
+
+// validateMirrorParams checks the custom body of octarine_mirror_images
+func validateMirrorParams(params *deploymentParams, deleteOp bool) error {
+	if deleteOp {
+		return nil
+	}
+	if params.Mirror == "" {
+		return fmt.Errorf("error: the registry to mirror the images to is required, set mirror")
+	}
+	if err := validateMirror(params.Mirror); err != nil {
+		return err
+	}
+	if params.Tool != "" {
+		if _, ok := mirrorToolImages[params.Tool]; !ok {
+			return fmt.Errorf("error: tool %s is not one of %s, %s", params.Tool, mirrorToolSkopeo, mirrorToolCrane)
+		}
+	}
+	if params.PushSecret != "" {
+		if !params.Execute {
+			return fmt.Errorf("error: push_secret is only used by the copy Job, set execute")
+		}
+		if errs := validation.IsDNS1123Subdomain(params.PushSecret); len(errs) > 0 {
+			return fmt.Errorf("error: push_secret %s is not a valid Secret name: %s", params.PushSecret, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// mirroredImage is where an image is copied to in a mirror, under the repository it has in its registry so
+// images of different registries don't collide
+func mirroredImage(image, mirror string) string {
+	ref := parseImageRef(image)
+	separator := ":"
+	if strings.Contains(ref.tag, ":") {
+		separator = "@"
+	}
+	return mirror + "/" + ref.repository + separator + ref.tag
+}
+
+// sourceImage is an image in the form the copy tools take, with the registry Docker Hub images are known by
+func sourceImage(image string) string {
+	ref := parseImageRef(image)
+	registry := ref.registry
+	if registry == dockerHubRegistry {
+		registry = "docker.io"
+	}
+	separator := ":"
+	if strings.Contains(ref.tag, ":") {
+		separator = "@"
+	}
+	return registry + "/" + ref.repository + separator + ref.tag
+}
+
+// mirrorCommands are the commands copying every image with all its platforms to the mirror
+func mirrorCommands(images []string, mirror, tool string) []string {
+	commands := make([]string, 0, len(images))
+	for _, image := range images {
+		src, dst := sourceImage(image), mirroredImage(image, mirror)
+		if tool == mirrorToolCrane {
+			commands = append(commands, fmt.Sprintf("crane copy %s %s", src, dst))
+			continue
+		}
+		commands = append(commands, fmt.Sprintf("skopeo copy --all docker://%s docker://%s", src, dst))
+	}
+	return commands
+}
+
+// mirrorManifest rewrites the images of a manifest, and the references to them in the arguments and
+// environment of its containers and in its ConfigMaps, to their copies in a mirror
+func mirrorManifest(manifest, mirror string) (string, error) {
+	images, err := releaseManifestImages(manifest)
+	if err != nil {
+		return "", err
+	}
+	mirrored := map[string]string{}
+	for _, image := range images {
+		mirrored[image] = mirroredImage(image, mirror)
+	}
+	replace := func(value string) string {
+		return imageToken.ReplaceAllStringFunc(value, func(token string) string {
+			if m, ok := mirrored[token]; ok {
+				return m
+			}
+			return token
+		})
+	}
+	return transformManifest(manifest, func(obj *unstructured.Unstructured) error {
+		if obj.GetKind() == "ConfigMap" {
+			data, found, _ := unstructured.NestedStringMap(obj.Object, "data")
+			if !found {
+				return nil
+			}
+			for key, value := range data {
+				data[key] = replace(value)
+			}
+			return unstructured.SetNestedStringMap(obj.Object, data, "data")
+		}
+		path := podSpecPath(obj.GetKind())
+		if path == nil {
+			return nil
+		}
+		for _, field := range []string{"initContainers", "containers"} {
+			fieldPath := append(append([]string{}, path...), field)
+			containers, found, err := nestedObjects(obj.Object, fieldPath...)
+			if err != nil || !found {
+				continue
+			}
+			for _, container := range containers {
+				if image, _, _ := unstructured.NestedString(container, "image"); mirrored[image] != "" {
+					container["image"] = mirrored[image]
+				}
+				if args, found, _ := unstructured.NestedStringSlice(container, "args"); found {
+					for i := range args {
+						args[i] = replace(args[i])
+					}
+					if err := unstructured.SetNestedStringSlice(container, args, "args"); err != nil {
+						return err
+					}
+				}
+				env, _, _ := nestedObjects(container, "env")
+				for _, e := range env {
+					if value, ok := e["value"].(string); ok {
+						e["value"] = replace(value)
+					}
+				}
+				if len(env) > 0 {
+					if err := setNestedObjects(container, env, "env"); err != nil {
+						return err
+					}
+				}
+			}
+			if err := setNestedObjects(obj.Object, containers, fieldPath...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// mirrorJob copies the images with the tool in a Job of the namespace of the operation. It pulls with the
+// docker credentials of the adapter and pushes with the docker config of the push Secret, anonymously
+// when there is none.
+func mirrorJob(d *deployment, namespace, tool, pushSecret string, commands []string) *batchv1.Job {
+	script := []string{"set -e"}
+	pushConfig := mirrorPushSecretPath + "/" + corev1.DockerConfigJsonKey
+	if tool == mirrorToolCrane {
+		// crane reads a single docker config, the source login is added to a copy of the push one
+		script = append(script, "export DOCKER_CONFIG=/tmp/docker", "mkdir -p $DOCKER_CONFIG")
+		if pushSecret != "" {
+			script = append(script, "cp "+pushConfig+" $DOCKER_CONFIG/config.json")
+		}
+		script = append(script, `if [ -n "$SOURCE_USERNAME" ]; then for registry in $SOURCE_REGISTRIES; do crane auth login "$registry" -u "$SOURCE_USERNAME" -p "$SOURCE_PASSWORD"; done; fi`)
+	}
+	for _, command := range commands {
+		// the command is echoed before the credentials are added to it
+		script = append(script, "echo "+command)
+		if tool == mirrorToolSkopeo {
+			flags := `${SOURCE_USERNAME:+--src-creds "$SOURCE_USERNAME:$SOURCE_PASSWORD"}`
+			if pushSecret != "" {
+				flags += " --dest-authfile " + pushConfig
+			}
+			command = strings.Replace(command, "skopeo copy ", "skopeo copy "+flags+" ", 1)
+		}
+		script = append(script, command)
+	}
+
+	optional := true
+	env := []corev1.EnvVar{
+		{Name: "SOURCE_USERNAME", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: mirrorSourceSecret}, Key: "username", Optional: &optional}}},
+		{Name: "SOURCE_PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: mirrorSourceSecret}, Key: "password", Optional: &optional}}},
+		{Name: "SOURCE_REGISTRIES", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: mirrorSourceSecret}, Key: "registries", Optional: &optional}}},
+	}
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+	if pushSecret != "" {
+		volumes = []corev1.Volume{{Name: "push", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: pushSecret}}}}
+		mounts = []corev1.VolumeMount{{Name: "push", MountPath: mirrorPushSecretPath, ReadOnly: true}}
+	}
+	deadline := int64(mirrorJobDeadline / time.Second)
+	backoff := int32(0)
+	automount := false
+	labels := d.managedLabels()
+	podLabels := d.managedLabels()
+	// the copy runs in the namespace of the operation, which may be injected
+	podLabels[injectOptOutLabel] = "false"
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: mirrorJobName, Namespace: namespace, Labels: labels},
+		Spec: batchv1.JobSpec{
+			ActiveDeadlineSeconds: &deadline,
+			BackoffLimit:          &backoff,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{
+					RestartPolicy:                corev1.RestartPolicyNever,
+					AutomountServiceAccountToken: &automount,
+					Volumes:                      volumes,
+					Containers: []corev1.Container{{
+						Name:         tool,
+						Image:        mirrorToolImage(tool),
+						Command:      []string{"sh", "-c", strings.Join(script, "\n")},
+						Env:          env,
+						VolumeMounts: mounts,
+					}},
+				},
+			},
+		},
+	}
+}
+
+// mirrorSourceCredentials is the Secret the copy Job pulls with, the docker credentials of the adapter
+func mirrorSourceCredentials(d *deployment, namespace string, images []string) *corev1.Secret {
+	registries := map[string]bool{}
+	for _, image := range images {
+		registries[strings.SplitN(sourceImage(image), "/", 2)[0]] = true
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: mirrorSourceSecret, Namespace: namespace, Labels: d.managedLabels()},
+		StringData: map[string]string{
+			"username":   os.Getenv("OCTARINE_DOCKER_USERNAME"),
+			"password":   os.Getenv("OCTARINE_DOCKER_PASSWORD"),
+			"registries": strings.Join(sortedKeys(registries), " "),
+		},
+	}
+}
+
+// runMirrorJob runs the copy Job to completion, the Job, its pod and the source credentials are deleted
+// afterwards
+func (oClient *Client) runMirrorJob(ctx context.Context, job *batchv1.Job, source *corev1.Secret) error {
+	jobs := oClient.clientset(ctx).BatchV1().Jobs(job.Namespace)
+	secrets := oClient.clientset(ctx).CoreV1().Secrets(job.Namespace)
+	if err := oClient.deleteMirrorJob(ctx, job.Namespace); err != nil {
+		return err
+	}
+	workingOn(ctx, "creating the copy Job %s/%s", job.Namespace, job.Name)
+	if _, err := secrets.Create(source); err != nil {
+		return errors.Wrapf(err, "unable to create Secret %s/%s", source.Namespace, source.Name)
+	}
+	if _, err := jobs.Create(job); err != nil {
+		_ = secrets.Delete(source.Name, &metav1.DeleteOptions{})
+		return errors.Wrapf(err, "unable to create Job %s/%s", job.Namespace, job.Name)
+	}
+	defer func() {
+		if err := oClient.deleteMirrorJob(ctx, job.Namespace); err != nil {
+			logrus.Warn(err)
+		}
+	}()
+	for {
+		current, err := jobs.Get(job.Name, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "unable to get Job %s/%s", job.Namespace, job.Name)
+		}
+		for _, c := range current.Status.Conditions {
+			if c.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch c.Type {
+			case batchv1.JobComplete:
+				progressed(ctx)
+				return nil
+			case batchv1.JobFailed:
+				return fmt.Errorf("error: Job %s/%s failed: %s%s", job.Namespace, job.Name, c.Message, oClient.mirrorJobLog(ctx, job))
+			}
+		}
+		// the Job has its own deadline, a running copy is progress however long it takes
+		if current.Status.Active > 0 && oClient.mirrorPodRunning(ctx, job) {
+			progressed(ctx)
+		}
+		workingOn(ctx, "the copy Job %s/%s", job.Namespace, job.Name)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+func (oClient *Client) mirrorPods(ctx context.Context, job *batchv1.Job) []corev1.Pod {
+	pods, err := oClient.clientset(ctx).CoreV1().Pods(job.Namespace).List(metav1.ListOptions{LabelSelector: "job-name=" + job.Name})
+	if err != nil {
+		logrus.Warnf("Unable to list the pods of Job %s/%s: %v", job.Namespace, job.Name, err)
+		return nil
+	}
+	return pods.Items
+}
+
+func (oClient *Client) mirrorPodRunning(ctx context.Context, job *batchv1.Job) bool {
+	for _, pod := range oClient.mirrorPods(ctx, job) {
+		if pod.Status.Phase == corev1.PodRunning {
+			return true
+		}
+	}
+	return false
+}
+
+// mirrorJobLog is the end of the log of the failed copy, which names the image that couldn't be copied
+func (oClient *Client) mirrorJobLog(ctx context.Context, job *batchv1.Job) string {
+	lines := int64(20)
+	for _, pod := range oClient.mirrorPods(ctx, job) {
+		log, err := oClient.clientset(ctx).CoreV1().Pods(job.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{TailLines: &lines}).Do().Raw()
+		if err == nil && len(log) > 0 {
+			return "\n" + strings.TrimSpace(string(log))
+		}
+	}
+	return ""
+}
+
+// deleteMirrorJob removes the copy Job, its pods and the source credentials left in a namespace
+func (oClient *Client) deleteMirrorJob(ctx context.Context, namespace string) error {
+	propagation := metav1.DeletePropagationBackground
+	err := oClient.clientset(ctx).BatchV1().Jobs(namespace).Delete(mirrorJobName, &metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "unable to delete Job %s/%s", namespace, mirrorJobName)
+	}
+	err = oClient.clientset(ctx).CoreV1().Secrets(namespace).Delete(mirrorSourceSecret, &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "unable to delete Secret %s/%s", namespace, mirrorSourceSecret)
+	}
+	return nil
+}
+
+// executeMirrorImages lists the commands copying the images of a release to a mirror, runs them in a Job
+// when asked to, and has the deployment pull from the mirror from then on. Deleting removes a copy Job
+// left behind and has the deployment pull from the original registries again.
+func (oClient *Client) executeMirrorImages(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	if oClient.clientset(ctx) == nil {
+		return fmt.Errorf("error: mesh client has not been created")
+	}
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	namespace := arReq.GetNamespace()
+	if namespace == "" {
+		namespace = dataplaneNamespace()
+	}
+	installed, _ := oClient.getDeployment(params.Deployment)
+	if arReq.GetDeleteOp() {
+		if err := oClient.deleteMirrorJob(ctx, namespace); err != nil {
+			return err
+		}
+		summary := "Removed the image copy Job"
+		if installed != nil && installed.mirror != "" {
+			installed.mirror = ""
+			summary = fmt.Sprintf("Deployment %s pulls its images from their registries", installed.name)
+		}
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: arReq.GetOperationId(),
+			EventType:   meshes.EventType_INFO,
+			Summary:     summary,
+		}
+		return nil
+	}
+
+	d, err := oClient.releaseDeployment(ctx, params.Deployment, params.Version)
+	if err != nil {
+		return err
+	}
+	workingOn(ctx, "rendering the dataplane of Octarine %s", d.versionName())
+	images, err := oClient.releaseImages(ctx, d)
+	if err != nil {
+		return err
+	}
+	progressed(ctx)
+	tool := params.Tool
+	if tool == "" {
+		tool = mirrorToolSkopeo
+	}
+	commands := mirrorCommands(images, params.Mirror, tool)
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Copy commands for the %d image(s) of Octarine %s", len(images), d.versionName()),
+		Details:     strings.Join(commands, "\n"),
+	}
+	if params.Execute {
+		job := mirrorJob(d, namespace, tool, params.PushSecret, commands)
+		if err := oClient.runMirrorJob(ctx, job, mirrorSourceCredentials(d, namespace, images)); err != nil {
+			return err
+		}
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: arReq.GetOperationId(),
+			EventType:   meshes.EventType_INFO,
+			Summary:     fmt.Sprintf("Mirrored %d image(s) of Octarine %s to %s", len(images), d.versionName(), params.Mirror),
+		}
+	}
+	if installed != nil && installed.name == d.name {
+		installed.mirror = params.Mirror
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: arReq.GetOperationId(),
+			EventType:   meshes.EventType_INFO,
+			Summary:     fmt.Sprintf("Deployment %s pulls its images from %s", installed.name, params.Mirror),
+			Details:     "The images are rewritten the next time the dataplane is applied, e.g. when it is upgraded or installed again.",
+		}
+	}
+	return nil
+}
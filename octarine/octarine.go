@@ -15,54 +15,82 @@
 package octarine
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"path"
+	"os"
+	"sort"
 	"strings"
-	"text/template"
 	"time"
 
-	"github.com/ghodss/yaml"
 	"github.com/layer5io/meshery-octarine/meshes"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // CreateMeshInstance instantiates a client instance to interface with the Octarine Service Mesh
-func (oClient *Client) CreateMeshInstance(_ context.Context, k8sReq *meshes.CreateMeshInstanceRequest) (*meshes.CreateMeshInstanceResponse, error) {
-	var k8sConfig []byte
-	contextName := ""
+func (oClient *Client) CreateMeshInstance(ctx context.Context, k8sReq *meshes.CreateMeshInstanceRequest) (*meshes.CreateMeshInstanceResponse, error) {
+	creds := clusterCredentials{}
 	if k8sReq != nil {
-		k8sConfig = k8sReq.K8SConfig
-		contextName = k8sReq.ContextName
+		creds = clusterCredentials{
+			kubeconfig:  k8sReq.K8SConfig,
+			contextName: k8sReq.ContextName,
+			token:       k8sReq.Token,
+			server:      k8sReq.Server,
+			caData:      k8sReq.CertificateAuthority,
+		}
 	}
-	// logrus.Debugf("received k8sConfig: %s", k8sConfig)
-	logrus.Debugf("received contextName: %s", contextName)
+	// logrus.Debugf("received k8sConfig: %s", creds.kubeconfig)
+	logrus.Debugf("received contextName: %s", creds.contextName)
 
-	oc, err := newClient(k8sConfig, contextName)
+	oClient.startEvents()
+	oc, err := newClient(creds)
 	if err != nil {
 		err = errors.Wrapf(err, "unable to create a new Octarine client")
 		logrus.Error(err)
+		oClient.eventChan <- instanceFailedEvent(k8sReq.GetCluster(), err)
+		return nil, err
+	}
+	// a kubeconfig which can't reach the cluster is rejected now rather than by the first operation
+	access, err := probeClusterAccess(oc.config)
+	if err != nil {
+		logrus.Error(err)
+		oClient.eventChan <- instanceFailedEvent(k8sReq.GetCluster(), err)
 		return nil, err
 	}
-	oClient.k8sClientset = oc.k8sClientset
-	oClient.k8sDynamicClient = oc.k8sDynamicClient
-	oClient.eventChan = make(chan *meshes.EventsResponse, 100)
-	oClient.config = oc.config
-	return &meshes.CreateMeshInstanceResponse{}, nil
+	if name := k8sReq.GetCluster(); name != "" {
+		oClient.registerCluster(name, oc)
+		oClient.markConnected(name)
+		event := accessEvent(access)
+		event.Summary = fmt.Sprintf("Cluster %s: %s", name, event.Summary)
+		oClient.eventChan <- event
+		oClient.startConnectivityMonitor()
+		return &meshes.CreateMeshInstanceResponse{Access: access}, nil
+	}
+	oClient.setClients(oc)
+	oClient.markConnected("")
+	oClient.eventChan <- accessEvent(access)
+	oClient.startScheduler(ctx)
+	oClient.startWebhookProbe()
+	oClient.startConnectivityMonitor()
+	oClient.startTelemetry()
+	oClient.startLicenseWatch()
+	return &meshes.CreateMeshInstanceResponse{Access: access}, nil
 }
 
 func (oClient *Client) createResource(ctx context.Context, res schema.GroupVersionResource, data *unstructured.Unstructured) error {
-	_, err := oClient.k8sDynamicClient.Resource(res).Namespace(data.GetNamespace()).Create(data, metav1.CreateOptions{})
+	data = withOperationAnnotations(ctx, data)
+	_, err := oClient.dynamicClient(ctx).Resource(res).Namespace(data.GetNamespace()).Create(data, metav1.CreateOptions{FieldManager: fieldManager})
+	if denial, ok := asAdmissionDenial(err, data); ok {
+		// the webhook would deny the object without its namespace all the same
+		return denial
+	}
 	if err != nil {
 		err = errors.Wrapf(err, "unable to create the requested resource, attempting operation without namespace")
 		logrus.Warn(err)
-		_, err = oClient.k8sDynamicClient.Resource(res).Create(data, metav1.CreateOptions{})
+		_, err = oClient.dynamicClient(ctx).Resource(res).Create(data, metav1.CreateOptions{FieldManager: fieldManager})
 		if err != nil {
 			err = errors.Wrapf(err, "unable to create the requested resource, attempting to update")
 			logrus.Error(err)
@@ -74,7 +102,7 @@ func (oClient *Client) createResource(ctx context.Context, res schema.GroupVersi
 }
 
 func (oClient *Client) deleteResource(ctx context.Context, res schema.GroupVersionResource, data *unstructured.Unstructured) error {
-	if oClient.k8sDynamicClient == nil {
+	if oClient.dynamicClient(ctx) == nil {
 		return errors.New("mesh client has not been created")
 	}
 
@@ -88,22 +116,21 @@ func (oClient *Client) deleteResource(ctx context.Context, res schema.GroupVersi
 		if err != nil {
 			return err
 		}
-		depl := data1.UnstructuredContent()
-		spec1 := depl["spec"].(map[string]interface{})
-		spec1["replicas"] = 0
-		data1.SetUnstructuredContent(depl)
+		if err := unstructured.SetNestedField(data1.Object, int64(0), "spec", "replicas"); err != nil {
+			return errors.Wrapf(err, "unable to scale deployment %s down", data.GetName())
+		}
 		if err = oClient.updateResource(ctx, res, data1); err != nil {
 			return err
 		}
 	}
 	policy := metav1.DeletePropagationBackground
-	err := oClient.k8sDynamicClient.Resource(res).Namespace(data.GetNamespace()).Delete(data.GetName(),
+	err := oClient.dynamicClient(ctx).Resource(res).Namespace(data.GetNamespace()).Delete(data.GetName(),
 		&metav1.DeleteOptions{PropagationPolicy: &policy})
 	if err != nil {
 		err = errors.Wrapf(err, "unable to delete the requested resource, attempting operation without namespace")
 		logrus.Warn(err)
 
-		err := oClient.k8sDynamicClient.Resource(res).Delete(data.GetName(), &metav1.DeleteOptions{})
+		err := oClient.dynamicClient(ctx).Resource(res).Delete(data.GetName(), &metav1.DeleteOptions{})
 		if err != nil {
 			err = errors.Wrapf(err, "unable to delete the requested resource")
 			logrus.Error(err)
@@ -115,12 +142,12 @@ func (oClient *Client) deleteResource(ctx context.Context, res schema.GroupVersi
 }
 
 func (oClient *Client) getResource(ctx context.Context, res schema.GroupVersionResource, data *unstructured.Unstructured) (*unstructured.Unstructured, error) {
-	data1, err := oClient.k8sDynamicClient.Resource(res).Namespace(data.GetNamespace()).Get(data.GetName(), metav1.GetOptions{})
+	data1, err := oClient.dynamicClient(ctx).Resource(res).Namespace(data.GetNamespace()).Get(data.GetName(), metav1.GetOptions{})
 	if err != nil {
 		err = errors.Wrap(err, "unable to retrieve the resource with a matching name, attempting operation without namespace")
 		logrus.Warn(err)
 
-		data1, err = oClient.k8sDynamicClient.Resource(res).Get(data.GetName(), metav1.GetOptions{})
+		data1, err = oClient.dynamicClient(ctx).Resource(res).Get(data.GetName(), metav1.GetOptions{})
 		if err != nil {
 			err = errors.Wrap(err, "unable to retrieve the resource with a matching name, while attempting to apply the config")
 			logrus.Error(err)
@@ -132,11 +159,15 @@ func (oClient *Client) getResource(ctx context.Context, res schema.GroupVersionR
 }
 
 func (oClient *Client) updateResource(ctx context.Context, res schema.GroupVersionResource, data *unstructured.Unstructured) error {
-	if _, err := oClient.k8sDynamicClient.Resource(res).Namespace(data.GetNamespace()).Update(data, metav1.UpdateOptions{}); err != nil {
+	data = withOperationAnnotations(ctx, data)
+	if _, err := oClient.dynamicClient(ctx).Resource(res).Namespace(data.GetNamespace()).Update(data, metav1.UpdateOptions{FieldManager: fieldManager}); err != nil {
+		if denial, ok := asAdmissionDenial(err, data); ok {
+			return denial
+		}
 		err = errors.Wrap(err, "unable to update resource with the given name, attempting operation without namespace")
 		logrus.Warn(err)
 
-		if _, err = oClient.k8sDynamicClient.Resource(res).Update(data, metav1.UpdateOptions{}); err != nil {
+		if _, err = oClient.dynamicClient(ctx).Resource(res).Update(data, metav1.UpdateOptions{FieldManager: fieldManager}); err != nil {
 			err = errors.Wrap(err, "unable to update resource with the given name, while attempting to apply the config")
 			logrus.Error(err)
 			return err
@@ -151,44 +182,20 @@ func (oClient *Client) MeshName(context.Context, *meshes.MeshNameRequest) (*mesh
 	return &meshes.MeshNameResponse{Name: "Octarine"}, nil
 }
 
-func (oClient *Client) applyManifestPayload(ctx context.Context, namespace string, newBytes []byte, delete bool) error {
-	if oClient.k8sDynamicClient == nil {
+func (oClient *Client) applyManifestPayload(ctx context.Context, namespace string, objects []*unstructured.Unstructured, delete bool) error {
+	if oClient.dynamicClient(ctx) == nil {
 		return errors.New("mesh client has not been created")
 	}
-	// logrus.Debugf("received yaml bytes: %s", newBytes)
-	jsonBytes, err := yaml.YAMLToJSON(newBytes)
-	if err != nil {
-		err = errors.Wrapf(err, "unable to convert yaml to json")
-		logrus.Error(err)
-		return err
-	}
-	// logrus.Debugf("created json: %s, length: %d", jsonBytes, len(jsonBytes))
-	if len(jsonBytes) > 5 { // attempting to skip 'null' json
-		data := &unstructured.Unstructured{}
-		err = data.UnmarshalJSON(jsonBytes)
-		if err != nil {
-			err = errors.Wrapf(err, "unable to unmarshal json created from yaml")
-			logrus.Error(err)
+	for _, obj := range objects {
+		if err := oClient.executeManifest(ctx, obj, namespace, delete); err != nil {
 			return err
 		}
-		if data.IsList() {
-			err = data.EachListItem(func(r runtime.Object) error {
-				dataL, _ := r.(*unstructured.Unstructured)
-				return oClient.executeManifest(ctx, dataL, namespace, delete)
-			})
-			return err
-		}
-		return oClient.executeManifest(ctx, data, namespace, delete)
 	}
 	return nil
 }
 
-func (oClient *Client) executeManifest(ctx context.Context, data *unstructured.Unstructured, namespace string, delete bool) error {
-	// logrus.Debug("========================================================")
-	// logrus.Debugf("Received data: %+#v", data)
-	if namespace != "" {
-		data.SetNamespace(namespace)
-	}
+// resourceFor computes the resource of an object from its apiVersion and kind
+func resourceFor(data *unstructured.Unstructured) schema.GroupVersionResource {
 	groupVersion := strings.Split(data.GetAPIVersion(), "/")
 	logrus.Debugf("groupVersion: %v", groupVersion)
 	var group, version string
@@ -215,86 +222,228 @@ func (oClient *Client) executeManifest(ctx context.Context, data *unstructured.U
 		Resource: kind,
 	}
 	logrus.Debugf("Computed Resource: %+#v", res)
+	return res
+}
+
+func (oClient *Client) executeManifest(ctx context.Context, data *unstructured.Unstructured, namespace string, delete bool) error {
+	// logrus.Debug("========================================================")
+	// logrus.Debugf("Received data: %+#v", data)
+	if namespace != "" {
+		data.SetNamespace(namespace)
+	}
+	res := resourceFor(data)
+	workingOn(ctx, "%s %s", data.GetKind(), data.GetName())
 
 	if delete {
-		return oClient.deleteResource(ctx, res, data)
+		if err := oClient.deleteResource(ctx, res, data); err != nil {
+			return err
+		}
+		progressed(ctx)
+		return nil
 	}
 
 	if err := oClient.createResource(ctx, res, data); err != nil {
-		data1, err := oClient.getResource(ctx, res, data)
+		if denial, ok := asAdmissionDenial(err, data); ok {
+			return oClient.denied(ctx, denial, data)
+		}
+		live, err := oClient.getResource(ctx, res, data)
 		if err != nil {
 			return err
 		}
-		if err = oClient.updateResource(ctx, res, data1); err != nil {
+		merged := mergeObject(live, data)
+		changes := objectChanges(live, merged)
+		if oClient.resolveConflicts(ctx, live, changes) {
+			progressed(ctx)
+			return nil
+		}
+		if err := oClient.validateObject(ctx, merged); err != nil {
+			return err
+		}
+		if err = oClient.updateResource(ctx, res, merged); err != nil {
+			if denial, ok := asAdmissionDenial(err, merged); ok {
+				return oClient.denied(ctx, denial, merged)
+			}
 			return err
 		}
+		oClient.reportUpdate(ctx, merged, changes)
 	}
+	progressed(ctx)
 	return nil
 }
 
-func (oClient *Client) labelNamespaceForAutoInjection(ctx context.Context, namespace string) error {
-	ns := &unstructured.Unstructured{}
-	res := schema.GroupVersionResource{
-		Version:  "v1",
-		Resource: "namespaces",
-	}
-	ns.SetName(namespace)
-	ns, err := oClient.getResource(ctx, res, ns)
-	if err != nil {
-		return err
-	}
-	ns.SetLabels(map[string]string{
-		"octarine-injection": "enabled",
-	})
-	err = oClient.updateResource(ctx, res, ns)
-	if err != nil {
+func (oClient *Client) labelNamespaceForAutoInjection(ctx context.Context, namespace string, d *deployment) error {
+	if err := oClient.setInjectionLabel(ctx, namespace, d); err != nil {
 		return err
 	}
 	secret := &unstructured.Unstructured{}
-	res = schema.GroupVersionResource{
+	res := schema.GroupVersionResource{
 		Version:  "v1",
 		Resource: "secrets",
 	}
 	secret.SetName("docker-registry-secret")
-	secret.SetNamespace(oClient.octarineDataplaneNs)
-	secret, err = oClient.getResource(ctx, res, secret)
+	secret.SetNamespace(d.namespace)
+	secret, err := oClient.getResource(ctx, res, secret)
 	if err != nil {
 		return err
 	}
 	secret.SetNamespace(namespace)
 	secret.SetResourceVersion("")
+	// owner references can't point across namespaces, the copy is found through its labels instead
+	secret.SetOwnerReferences(nil)
 	err = oClient.createResource(ctx, res, secret)
 	if err != nil {
 		return err
 	}
+	oClient.syncInjectionExclusions(ctx, namespace)
+	oClient.warnWindowsWorkloads(ctx, namespace)
 	return nil
 }
 
-func (oClient *Client) executeInstall(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
-	if arReq.GetNamespace() == "" {
-		arReq.Namespace = "octarine-dataplane"
-	}
-	oClient.octarineDataplaneNs = arReq.GetNamespace()
-	if arReq.GetDeleteOp() {
-		defer oClient.deleteCpObjects()
+func (oClient *Client) installDeployment(ctx context.Context, name, namespace, domain, version, certificates, mirror string, replicas int) error {
+	d, err := oClient.newDeployment(name, namespace, domain, version)
+	if err != nil {
+		// a retry picks up the deployment the failed install registered, along with its account
+		if d = oClient.resumedDeployment(ctx, name, namespace); d == nil {
+			return err
+		}
 	} else {
-		if err := oClient.createCpObjects(); err != nil {
+		d.mirror = mirror
+		d.replicas = replicas
+		if d.mirror == "" {
+			d.mirror = os.Getenv(imageMirrorEnv)
+		}
+		bootstrapped, err := oClient.useBootstrap(ctx, d)
+		if err != nil {
+			oClient.removeDeployment(name)
 			return err
 		}
+		if !bootstrapped {
+			if err := oClient.createCpObjects(ctx, d); err != nil {
+				oClient.removeDeployment(name)
+				return err
+			}
+		}
 	}
-	dataplaneYaml, err := oClient.getOctarineYAMLs(arReq.GetNamespace())
+	if err := oClient.ensureAnchor(ctx, d); err != nil {
+		return err
+	}
+	d.certManager = oClient.useCertManager(ctx, d, certificates)
+	dataplaneYaml, err := oClient.getOctarineYAMLs(ctx, d)
 	if err != nil {
 		return err
 	}
-	if err := oClient.applyConfigChange(ctx, dataplaneYaml, arReq.GetNamespace(), arReq.GetDeleteOp()); err != nil {
+	dataplaneYaml, err = oClient.preflightPlatforms(ctx, d, dataplaneYaml)
+	if err == nil {
+		dataplaneYaml, err = oClient.preflightIPStack(ctx, d, dataplaneYaml)
+	}
+	if err == nil {
+		err = oClient.preflightQuota(ctx, dataplaneYaml, d.namespace)
+	}
+	if err != nil {
+		// nothing was installed yet, don't leave the account and anchor behind
+		_ = oClient.deleteAnchor(ctx, d)
+		if !d.bootstrapped {
+			_ = oClient.deleteCpObjects(ctx, d)
+		}
+		oClient.removeDeployment(name)
+		return err
+	}
+	if err := oClient.applyConfigChange(ctx, dataplaneYaml, d.namespace, false); err != nil {
 		return err
 	}
+	if err := oClient.restoreSidecarResources(ctx, d); err != nil {
+		return err
+	}
+	if d.certManager {
+		if err := oClient.waitForCertificates(ctx, d); err != nil {
+			return err
+		}
+	}
+	names, err := deploymentNames(dataplaneYaml)
+	if err != nil {
+		return err
+	}
+	if err := oClient.waitForDeployments(ctx, d.namespace, names); err != nil {
+		return err
+	}
+	d.updatedAt = time.Now()
 	return nil
 }
 
+// uninstallDeployment removes a deployment, the namespace is used when the adapter doesn't know the deployment
+func (oClient *Client) uninstallDeployment(ctx context.Context, name, namespace string) error {
+	d, err := oClient.getDeployment(name)
+	if err != nil {
+		logrus.Warnf("deployment %s is not tracked by the adapter, removing the dataplane from namespace %s", name, namespace)
+		d = &deployment{name: name, namespace: namespace, domain: os.Getenv("OCTARINE_DOMAIN")}
+	}
+	defer func() {
+		if d.account != "" && !d.bootstrapped {
+			_ = oClient.deleteCpObjects(ctx, d)
+		}
+		oClient.removeDeployment(name)
+	}()
+	dataplaneYaml, err := oClient.getOctarineYAMLs(ctx, d)
+	if err != nil {
+		return err
+	}
+	if err := oClient.applyConfigChange(ctx, dataplaneYaml, d.namespace, true); err != nil {
+		return err
+	}
+	if err := oClient.restoreDeploymentLabels(ctx, d); err != nil {
+		return err
+	}
+	if err := oClient.deleteIssuedSecrets(ctx, d); err != nil {
+		return err
+	}
+	return oClient.deleteAnchor(ctx, d)
+}
+
+func (oClient *Client) executeInstall(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	name := params.Deployment
+	if name == "" {
+		name = defaultDeploymentName
+	}
+	if arReq.GetNamespace() == "" {
+		arReq.Namespace = dataplaneNamespace()
+	}
+	if arReq.GetDeleteOp() {
+		return oClient.uninstallDeployment(ctx, name, arReq.GetNamespace())
+	}
+	return oClient.installDeployment(ctx, name, arReq.GetNamespace(), params.Domain, params.Version, params.Certificates, params.Mirror, params.Replicas)
+}
+
+// injectionDeployment returns the deployment whose sidecars get injected into sample applications
+func (oClient *Client) injectionDeployment(name string) (*deployment, error) {
+	d, err := oClient.getDeployment(name)
+	if err == nil {
+		return d, nil
+	}
+	oClient.deploymentsMu.Lock()
+	untracked := name == "" && len(oClient.deployments) == 0
+	oClient.deploymentsMu.Unlock()
+	if untracked {
+		// the dataplane may have been installed before the adapter was restarted
+		return &deployment{name: defaultDeploymentName, namespace: dataplaneNamespace()}, nil
+	}
+	return nil, err
+}
+
 func (oClient *Client) executeBookInfoInstall(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
 	if !arReq.GetDeleteOp() {
-		if err := oClient.labelNamespaceForAutoInjection(ctx, arReq.GetNamespace()); err != nil {
+		params, err := parseDeploymentParams(arReq.GetCustomBody())
+		if err != nil {
+			return err
+		}
+		d, err := oClient.injectionDeployment(params.Deployment)
+		if err != nil {
+			return err
+		}
+		if err := oClient.labelNamespaceForAutoInjection(ctx, arReq.GetNamespace(), d); err != nil {
 			return err
 		}
 	}
@@ -302,177 +451,315 @@ func (oClient *Client) executeBookInfoInstall(ctx context.Context, arReq *meshes
 	if err != nil {
 		return err
 	}
+	if !arReq.GetDeleteOp() {
+		if yamlFileContents, err = labelSampleApp(yamlFileContents, sampleAppBookInfo); err != nil {
+			return err
+		}
+		if err := oClient.preflightQuota(ctx, yamlFileContents, arReq.GetNamespace()); err != nil {
+			return err
+		}
+	}
 	if err := oClient.applyConfigChange(ctx, yamlFileContents, arReq.GetNamespace(), arReq.GetDeleteOp()); err != nil {
 		return err
 	}
-	return nil
+	if arReq.GetDeleteOp() {
+		return nil
+	}
+	names, err := deploymentNames(yamlFileContents)
+	if err != nil {
+		return err
+	}
+	return oClient.waitForDeployments(ctx, arReq.GetNamespace(), names)
 }
 
 // ApplyOperation is a method invoked to apply a particular operation on the mesh in a namespace
-func (oClient *Client) ApplyOperation(ctx context.Context, arReq *meshes.ApplyRuleRequest) (*meshes.ApplyRuleResponse, error) {
+func (oClient *Client) ApplyOperation(ctx context.Context, arReq *meshes.ApplyRuleRequest) (resp *meshes.ApplyRuleResponse, err error) {
 	if arReq == nil {
 		return nil, errors.New("mesh client has not been created")
 	}
+	if err := oClient.checkConnected(arReq.GetCluster()); err != nil {
+		return nil, err
+	}
+	if name := arReq.GetCluster(); name != oClient.cluster {
+		target, err := oClient.clusterClient(name)
+		if err != nil {
+			return nil, err
+		}
+		return target.ApplyOperation(ctx, arReq)
+	}
+
+	result := oClient.startResult(arReq)
+	defer func() {
+		// the operations running on finish their result along with their watchdog
+		if err != nil {
+			oClient.finishResult(result, err)
+		}
+	}()
+	// most operations keep running after the response was sent, so they can't use the request context
+	ctx = withResult(withOperationID(context.Background(), arReq.GetOperationId()), result)
 
-	op, ok := supportedOps[arReq.GetOpName()]
+	var warnings []string
+	if arReq, warnings, err = withParameters(arReq); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if resp != nil {
+			resp.OperationId = arReq.GetOperationId()
+			resp.Warnings = warnings
+		}
+	}()
+
+	op, ok := lookupOp(arReq.GetOpName())
 	if !ok {
+		if cause := disabledOpCause(arReq.GetOpName()); cause != nil {
+			return nil, fmt.Errorf("error: operation %s is disabled, %v", arReq.GetOpName(), cause)
+		}
 		return nil, fmt.Errorf("error: %s is not a valid operation name", arReq.GetOpName())
 	}
+	countOperation(arReq.GetOpName())
+	ctx = withConflictPolicy(ctx, arReq.GetConflictPolicy())
 
-	if arReq.GetOpName() == customOpCommand && arReq.GetCustomBody() == "" {
-		return nil, fmt.Errorf("error: yaml body is empty for %s operation", arReq.GetOpName())
+	if arReq.GetResumeOperationId() != "" {
+		rp, err := oClient.resumeOperation(ctx, arReq)
+		if err != nil {
+			return nil, err
+		}
+		ctx = withResume(ctx, rp)
+		if result != nil {
+			result.ResumedFrom = rp.operationID
+		}
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: arReq.GetOperationId(),
+			EventType:   meshes.EventType_INFO,
+			Summary:     fmt.Sprintf("Resuming operation %s", rp.operationID),
+			Details:     fmt.Sprintf("The %d manifest document(s) it applied are skipped unless they changed.", len(rp.applied)),
+		}
 	}
 
-	var yamlFileContents string
-	// var err error
+	if arReq.GetOpName() == customOpCommand && arReq.GetDeleteOp() && strings.TrimSpace(arReq.GetCustomBody()) == "" {
+		if err := oClient.deleteInventory(ctx, arReq); err != nil {
+			return nil, err
+		}
+		oClient.finishResult(result, nil)
+		return &meshes.ApplyRuleResponse{}, nil
+	}
+
+	if (arReq.GetOpName() == customOpCommand || arReq.GetOpName() == customLabelDeleteOp ||
+		arReq.GetOpName() == applyMeshSpecCommand) && arReq.GetCustomBody() == "" {
+		return nil, fmt.Errorf("error: yaml body is empty for %s operation", arReq.GetOpName())
+	}
 
 	switch arReq.GetOpName() {
-	case customOpCommand:
-		yamlFileContents = arReq.GetCustomBody()
-	case installOctarineCommand:
-		go func() {
-			opName1 := "deploying"
-			if arReq.GetDeleteOp() {
-				opName1 = "removing"
-			}
-			if err := oClient.executeInstall(ctx, arReq); err != nil {
-				oClient.eventChan <- &meshes.EventsResponse{
-					OperationId: arReq.GetOperationId(),
-					EventType:   meshes.EventType_ERROR,
-					Summary:     fmt.Sprintf("Error while %s Octarine", opName1),
-					Details:     err.Error(),
-				}
-				return
-			}
-			opName := "deployed"
-			if arReq.DeleteOp {
-				opName = "removed"
-			}
-			oClient.eventChan <- &meshes.EventsResponse{
-				OperationId: arReq.GetOperationId(),
-				EventType:   meshes.EventType_INFO,
-				Summary:     fmt.Sprintf("Octarine %s successfully", opName),
-				Details:     fmt.Sprintf("The latest version of Octarine is now %s.", opName),
-			}
-			return
-		}()
-		return &meshes.ApplyRuleResponse{}, nil
-	case installBookInfoCommand:
-		go func() {
-			opName1 := "deploying"
-			if arReq.GetDeleteOp() {
-				opName1 = "removing"
-			}
-			if err := oClient.executeBookInfoInstall(ctx, arReq); err != nil {
-				oClient.eventChan <- &meshes.EventsResponse{
-					OperationId: arReq.GetOperationId(),
-					EventType:   meshes.EventType_ERROR,
-					Summary:     fmt.Sprintf("Error while %s the canonical Book Info App", opName1),
-					Details:     err.Error(),
-				}
-				return
-			}
-			opName := "deployed"
-			if arReq.GetDeleteOp() {
-				opName = "removed"
-			}
-			oClient.eventChan <- &meshes.EventsResponse{
-				OperationId: arReq.GetOperationId(),
-				EventType:   meshes.EventType_INFO,
-				Summary:     fmt.Sprintf("Book Info app %s successfully", opName),
-				Details:     fmt.Sprintf("The canonical Book Info app is now %s.", opName),
-			}
-			return
-		}()
-		return &meshes.ApplyRuleResponse{}, nil
 	case runVet:
-		go oClient.runVet()
+		if oClient.clientset(ctx) == nil {
+			return nil, fmt.Errorf("error: mesh instance has not been created")
+		}
+	case applyMeshSpecCommand:
+		if _, err := parseMeshSpec(arReq.GetCustomBody()); err != nil {
+			return nil, err
+		}
+	}
+	if executor, ok := operationExecutors[arReq.GetOpName()]; ok {
+		oClient.runOperation(ctx, arReq, executor)
 		return &meshes.ApplyRuleResponse{}, nil
-	default:
-		tmpl, err := template.ParseFiles(path.Join("octarine", "config_templates", op.templateName))
+	}
+
+	var yamlFileContents string
+	switch arReq.GetOpName() {
+	case customOpCommand:
+		if oClient.dynamicClient(ctx) == nil {
+			return nil, fmt.Errorf("error: mesh instance has not been created")
+		}
+		manifest, err := normalizeManifest(arReq.GetCustomBody())
 		if err != nil {
-			err = errors.Wrapf(err, "unable to parse template")
-			logrus.Error(err)
 			return nil, err
 		}
-		buf := bytes.NewBufferString("")
-		err = tmpl.Execute(buf, map[string]string{
-			"user_name": arReq.GetUsername(),
-			"namespace": arReq.GetNamespace(),
-		})
+		yamlFileContents = manifest
+		targets, err := manifestTargets(yamlFileContents, arReq.GetNamespace())
+		if err != nil {
+			return nil, err
+		}
+		if err := oClient.guardBulkChange(arReq, targets, arReq.GetDeleteOp()); err != nil {
+			return nil, err
+		}
+	default:
+		ctx = withResolvedSecrets(ctx, &resolvedSecrets{})
+		manifest, err := oClient.renderOperationTemplate(ctx, arReq, op, oClient.secretResolver(ctx))
 		if err != nil {
-			err = errors.Wrapf(err, "unable to execute template")
-			logrus.Error(err)
 			return nil, err
 		}
-		yamlFileContents = buf.String()
+		yamlFileContents = manifest
 	}
 
-	if err := oClient.applyConfigChange(ctx, yamlFileContents, arReq.GetNamespace(), arReq.GetDeleteOp()); err != nil {
-		return nil, err
+	report := &denialReport{}
+	applyCtx := ctx
+	if arReq.GetReportDenials() && !arReq.GetDeleteOp() {
+		applyCtx = withDenialReport(ctx, report)
+	}
+	if err := oClient.applyConfigChange(applyCtx, yamlFileContents, arReq.GetNamespace(), arReq.GetDeleteOp()); err != nil {
+		return nil, redactError(ctx, err)
+	}
+	warnings = append(warnings, oClient.reportDenials(ctx, report)...)
+	if err := oClient.recordInventory(ctx, arReq, yamlFileContents); err != nil {
+		// the objects are applied, only deleting them by operation id is unavailable
+		logrus.Errorf("Unable to record the resources of operation %s in the inventory: %v", arReq.GetOperationId(), err)
 	}
+	if targets, err := manifestTargets(yamlFileContents, arReq.GetNamespace()); err == nil && result != nil {
+		for _, t := range targets {
+			result.Resources = append(result.Resources, t.String())
+		}
+	}
+	oClient.finishResult(result, nil)
 
 	return &meshes.ApplyRuleResponse{}, nil
 }
 
 func (oClient *Client) applyConfigChange(ctx context.Context, yamlFileContents, namespace string, delete bool) error {
-	yamls := strings.Split(yamlFileContents, "---")
-
-	for _, yml := range yamls {
-		if strings.TrimSpace(yml) != "" {
-			if err := oClient.applyManifestPayload(ctx, namespace, []byte(yml), delete); err != nil {
-				errStr := strings.TrimSpace(err.Error())
-				if delete && (strings.HasSuffix(errStr, "not found") ||
-					strings.HasSuffix(errStr, "the server could not find the requested resource")) {
-					// logrus.Debugf("skipping error. . .")
-					continue
-				}
-				// logrus.Debugf("returning error: %v", err)
+	// the documents are walked in place, a huge manifest isn't split into a copy of its documents, and only the
+	// objects of the document at hand are held: each pass decodes the documents again
+	dec := newDocumentDecoder(yamlFileContents)
+	documents := 0
+	err := eachDocument(yamlFileContents, func(doc string) error {
+		documents++
+		if delete {
+			return nil
+		}
+		// a mistyped field fails the operation before any object of the manifest is applied
+		objects, err := dec.decode(doc)
+		if err != nil {
+			return err
+		}
+		for _, obj := range objects {
+			if err := oClient.validateObject(ctx, obj); err != nil {
 				return err
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	return nil
+	progress := oClient.newProgressReporter(ctx, documents, delete)
+	resume := resumeFrom(ctx)
+
+	return eachDocument(yamlFileContents, func(yml string) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		digest := documentDigest(namespace, yml, delete)
+		if resume.skips(digest) {
+			// applied by the failed operation this one resumes
+			oClient.recordApplied(ctx, digest)
+			progress.step()
+			return nil
+		}
+		objects, err := dec.decode(yml)
+		if err == nil {
+			err = oClient.applyManifestPayload(ctx, namespace, objects, delete)
+		}
+		if err != nil {
+			errStr := strings.TrimSpace(err.Error())
+			if delete && (strings.HasSuffix(errStr, "not found") ||
+				strings.HasSuffix(errStr, "the server could not find the requested resource")) {
+				// logrus.Debugf("skipping error. . .")
+				oClient.recordApplied(ctx, digest)
+				progress.step()
+				return nil
+			}
+			// logrus.Debugf("returning error: %v", err)
+			return err
+		}
+		oClient.recordApplied(ctx, digest)
+		progress.step()
+		return nil
+	})
 }
 
-// SupportedOperations - returns a list of supported operations on the mesh
-func (oClient *Client) SupportedOperations(context.Context, *meshes.SupportedOperationsRequest) (*meshes.SupportedOperationsResponse, error) {
-	supportedOpsCount := len(supportedOps)
-	result := make([]*meshes.SupportedOperation, supportedOpsCount)
-	i := 0
-	for k, sp := range supportedOps {
-		result[i] = &meshes.SupportedOperation{
-			Key:      k,
-			Value:    sp.name,
-			Category: sp.opType,
+// SupportedOperations - returns a list of supported operations on the mesh. The operations the release or the
+// licensed features of the deployment don't support are left out, or listed with the reason when asked for.
+func (oClient *Client) SupportedOperations(ctx context.Context, req *meshes.SupportedOperationsRequest) (*meshes.SupportedOperationsResponse, error) {
+	if name := req.GetCluster(); name != oClient.cluster {
+		target, err := oClient.clusterClient(name)
+		if err != nil {
+			return &meshes.SupportedOperationsResponse{Error: err.Error()}, nil
+		}
+		return target.SupportedOperations(ctx, req)
+	}
+	support := oClient.opSupportFor(ctx, req.GetDeployment())
+	categories := map[meshes.OpCategory]bool{}
+	for _, c := range req.GetCategories() {
+		categories[c] = true
+	}
+	ops := supportedOpsSnapshot()
+	keys := make([]string, 0, len(ops))
+	for k, sp := range ops {
+		if len(categories) > 0 && !categories[sp.opType] {
+			continue
 		}
-		i++
+		if f := req.GetFilter(); f != "" && !strings.Contains(k, f) && !strings.Contains(sp.name, f) {
+			continue
+		}
+		if support.unavailable(sp) != "" && !req.GetIncludeUnavailable() {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	start, end, next, err := paginate(keys, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return &meshes.SupportedOperationsResponse{Error: err.Error()}, nil
+	}
+	locale := catalogFrom(ctx)
+	result := make([]*meshes.SupportedOperation, 0, end-start)
+	for _, k := range keys[start:end] {
+		result = append(result, &meshes.SupportedOperation{
+			Key:         k,
+			Value:       locale.translate(ops[k].name),
+			Category:    ops[k].opType,
+			MinVersion:  ops[k].minVersion,
+			Features:    ops[k].features,
+			Unavailable: locale.translate(support.unavailable(ops[k])),
+		})
 	}
 	return &meshes.SupportedOperationsResponse{
-		Ops: result,
+		Ops:              result,
+		NextPageToken:    next,
+		InstalledVersion: support.version,
+		LicensedFeatures: support.featureNames(),
 	}, nil
 }
 
 // StreamEvents - streams generated/collected events to the client
 func (oClient *Client) StreamEvents(in *meshes.EventsRequest, stream meshes.MeshService_StreamEventsServer) error {
 	logrus.Debugf("waiting on event stream. . .")
+	broker := oClient.startEvents()
+	sub := broker.subscribe()
+	locale := catalogFrom(stream.Context())
 	for {
 		select {
-		case event := <-oClient.eventChan:
+		case event := <-sub.queue:
+			if !wantsEvent(in, event) {
+				continue
+			}
+			if n := sub.takeDropped(); n > 0 {
+				if err := stream.Send(localizeEvent(locale, droppedEvent(n))); err != nil {
+					broker.unsubscribe(sub, event)
+					logrus.Error(errors.Wrapf(err, "unable to send event"))
+					return err
+				}
+			}
 			logrus.Debugf("sending event: %+#v", event)
-			if err := stream.Send(event); err != nil {
+			if err := stream.Send(localizeEvent(locale, event)); err != nil {
 				err = errors.Wrapf(err, "unable to send event")
-
-				// to prevent loosing the event, will re-add to the channel
-				go func() {
-					oClient.eventChan <- event
-				}()
+				// the event is kept for the next stream, unless another one is open
+				broker.unsubscribe(sub, event)
 				logrus.Error(err)
 				return err
 			}
-		default:
+		case <-stream.Context().Done():
+			broker.unsubscribe(sub, nil)
+			logrus.Debugf("event stream closed by the client")
+			return stream.Context().Err()
 		}
-		time.Sleep(500 * time.Millisecond)
 	}
-	return nil
 }
@@ -0,0 +1,187 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// bundleManifest is a manifest of n ConfigMaps of a few KB each, the size of the bundles of large installs
+// at a few hundred documents
+func bundleManifest(n int) string {
+	data := strings.Repeat("x", 4<<10)
+	docs := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		docs = append(docs, fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: bundle-%d
+  labels:
+    app: bundle
+data:
+  payload: %s
+`, i, data))
+	}
+	return strings.Join(docs, "---\n")
+}
+
+// peakHeap runs fn and returns how far HeapInuse rose above where it started while fn ran, sampled every
+// 100µs
+func peakHeap(fn func()) uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	base, peak := stats.HeapInuse, stats.HeapInuse
+	done := make(chan struct{})
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		ticker := time.NewTicker(100 * time.Microsecond)
+		defer ticker.Stop()
+		for {
+			var stats runtime.MemStats
+			runtime.ReadMemStats(&stats)
+			if stats.HeapInuse > peak {
+				peak = stats.HeapInuse
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	fn()
+	close(done)
+	<-sampled
+	return peak - base
+}
+
+func BenchmarkApplyConfigChange(b *testing.B) {
+	level := logrus.GetLevel()
+	logrus.SetLevel(logrus.ErrorLevel)
+	defer logrus.SetLevel(level)
+
+	for _, n := range []int{10, 500} {
+		manifest := bundleManifest(n)
+		b.Run(fmt.Sprintf("%d documents", n), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(manifest)))
+			peak := uint64(0)
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				oClient := &Client{k8sDynamicClient: newDiscardingDynamic()}
+				b.StartTimer()
+				inUse := peakHeap(func() {
+					if err := oClient.applyConfigChange(context.Background(), manifest, "default", false); err != nil {
+						b.Fatal(err)
+					}
+				})
+				if inUse > peak {
+					peak = inUse
+				}
+			}
+			// the heap the apply grows by, which stays flat as the manifest grows when documents are held one at a time
+			b.ReportMetric(float64(peak), "peak-heap-B")
+		})
+	}
+}
+
+func TestDeleteResource(t *testing.T) {
+	level := logrus.GetLevel()
+	logrus.SetLevel(logrus.PanicLevel)
+	defer logrus.SetLevel(level)
+
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	object := func(kind string, fields map[string]interface{}) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"kind": kind}}
+		obj.SetName("web")
+		obj.SetNamespace("default")
+		for key, value := range fields {
+			obj.Object[key] = value
+		}
+		return obj
+	}
+	tests := []struct {
+		name    string
+		res     schema.GroupVersionResource
+		live    *unstructured.Unstructured
+		wantErr bool
+	}{
+		{
+			name: "deployment",
+			res:  deployments,
+			live: object("Deployment", map[string]interface{}{"spec": map[string]interface{}{"replicas": int64(3)}}),
+		},
+		{
+			name: "replicas a string",
+			res:  deployments,
+			live: object("Deployment", map[string]interface{}{"spec": map[string]interface{}{"replicas": "three"}}),
+		},
+		{
+			name:    "spec a scalar",
+			res:     deployments,
+			live:    object("Deployment", map[string]interface{}{"spec": "three"}),
+			wantErr: true,
+		},
+		{
+			name: "annotations a scalar",
+			res:  deployments,
+			live: func() *unstructured.Unstructured {
+				obj := object("Deployment", nil)
+				obj.Object["metadata"].(map[string]interface{})["annotations"] = int64(3)
+				return obj
+			}(),
+		},
+		{
+			name:    "missing deployment",
+			res:     deployments,
+			wantErr: true,
+		},
+		{
+			name: "configmap with a scalar spec",
+			res:  configMaps,
+			live: object("ConfigMap", map[string]interface{}{"spec": "three"}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamicClient := newMemoryDynamic()
+			if tt.live != nil {
+				dynamicClient.add(tt.res, tt.live)
+			}
+			oClient := &Client{k8sDynamicClient: dynamicClient}
+			err := oClient.deleteResource(context.Background(), tt.res, object("", nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("deleteResource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, err := dynamicClient.Resource(tt.res).Namespace("default").Get("web", metav1.GetOptions{}); !tt.wantErr && !apierrors.IsNotFound(err) {
+				t.Errorf("deleteResource() left the object, Get() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// the schema only changes when the cluster is upgraded or CRDs are added, it is fetched again after a while
+	openAPITTL = 10 * time.Minute
+	// an invalid object is reported with this many of its problems
+	maxSchemaProblems = 10
+)
+
+// openAPISchema is the part of the OpenAPI v2 document of the API server needed to check the types of fields
+type openAPISchema struct {
+	Definitions map[string]*schemaProps `json:"definitions"`
+
+	byKind map[schema.GroupVersionKind]string
+}
+
+type schemaProps struct {
+	Type                 string                  `json:"type"`
+	Format               string                  `json:"format"`
+	Ref                  string                  `json:"$ref"`
+	Properties           map[string]*schemaProps `json:"properties"`
+	Items                *schemaProps            `json:"items"`
+	AdditionalProperties *schemaProps            `json:"additionalProperties"`
+	IntOrString          bool                    `json:"x-kubernetes-int-or-string"`
+	GroupVersionKinds    []struct {
+		Group   string `json:"group"`
+		Version string `json:"version"`
+		Kind    string `json:"kind"`
+	} `json:"x-kubernetes-group-version-kind"`
+}
+
+// clusterOpenAPI returns the OpenAPI schema of the cluster, cached for a while
+func (oClient *Client) clusterOpenAPI(ctx context.Context) (*openAPISchema, error) {
+	oClient.openAPIMu.Lock()
+	defer oClient.openAPIMu.Unlock()
+	if oClient.openAPI != nil && time.Since(oClient.openAPIFetched) < openAPITTL {
+		return oClient.openAPI, nil
+	}
+	raw, err := oClient.clientset(ctx).Discovery().RESTClient().Get().AbsPath("/openapi/v2").Context(ctx).Do().Raw()
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get the OpenAPI schema of the cluster")
+	}
+	doc := &openAPISchema{}
+	if err := json.Unmarshal(raw, doc); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the OpenAPI schema of the cluster")
+	}
+	doc.byKind = map[schema.GroupVersionKind]string{}
+	for name, def := range doc.Definitions {
+		for _, gvk := range def.GroupVersionKinds {
+			doc.byKind[schema.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind}] = name
+		}
+	}
+	oClient.openAPI, oClient.openAPIFetched = doc, time.Now()
+	return doc, nil
+}
+
+// validateObject checks the fields of an object against the schema of its kind, so a field of the wrong
+// type fails before the object is sent rather than as an opaque error of the API server. Kinds the schema
+// doesn't describe, like the custom resources of older clusters, aren't checked.
+func (oClient *Client) validateObject(ctx context.Context, obj *unstructured.Unstructured) error {
+	if oClient.clientset(ctx) == nil {
+		return nil
+	}
+	doc, err := oClient.clusterOpenAPI(ctx)
+	if err != nil {
+		// the API server checks the object anyway
+		logrus.Warnf("Skipping the validation of %s %s: %v", obj.GetKind(), obj.GetName(), err)
+		return nil
+	}
+	return doc.validate(obj)
+}
+
+func (doc *openAPISchema) validate(obj *unstructured.Unstructured) error {
+	name, ok := doc.byKind[obj.GroupVersionKind()]
+	if !ok {
+		return nil
+	}
+	problems := doc.check(doc.Definitions[name], obj.Object, "", 0)
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	if len(problems) > maxSchemaProblems {
+		problems = append(problems[:maxSchemaProblems], fmt.Sprintf("and %d more", len(problems)-maxSchemaProblems))
+	}
+	return fmt.Errorf("error: %s %s doesn't match the schema of the cluster: %s", obj.GetKind(), obj.GetName(), strings.Join(problems, "; "))
+}
+
+// check compares a value with its schema and returns the fields of the wrong type, unknown fields are left
+// to the API server
+func (doc *openAPISchema) check(props *schemaProps, value interface{}, path string, depth int) []string {
+	// schemas can be recursive, like the JSONSchemaProps of CRDs
+	if props == nil || value == nil || depth > 32 {
+		return nil
+	}
+	if props.Ref != "" {
+		name := strings.TrimPrefix(props.Ref, "#/definitions/")
+		if strings.HasSuffix(name, ".resource.Quantity") {
+			// quantities are strings in the schema but numbers are accepted too
+			return expectType(value, path, "string", "number")
+		}
+		return doc.check(doc.Definitions[name], value, path, depth+1)
+	}
+	if props.IntOrString || props.Format == "int-or-string" {
+		return expectType(value, path, "string", "integer")
+	}
+	switch props.Type {
+	case "string", "boolean", "integer", "number":
+		return expectType(value, path, props.Type)
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return expectType(value, path, "array")
+		}
+		problems := []string{}
+		for i, item := range items {
+			problems = append(problems, doc.check(props.Items, item, fmt.Sprintf("%s[%d]", path, i), depth+1)...)
+		}
+		return problems
+	case "object", "":
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			if props.Type == "" {
+				// a schema without a type, like RawExtension, takes anything
+				return nil
+			}
+			return expectType(value, path, "object")
+		}
+		problems := []string{}
+		for _, key := range sortedFields(fields) {
+			field := props.Properties[key]
+			if field == nil {
+				field = props.AdditionalProperties
+			}
+			problems = append(problems, doc.check(field, fields[key], joinPath(path, key), depth+1)...)
+		}
+		return problems
+	}
+	return nil
+}
+
+func sortedFields(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// expectType checks that a value is of one of the JSON types
+func expectType(value interface{}, path string, types ...string) []string {
+	got := jsonType(value)
+	for _, t := range types {
+		if t == got || t == "number" && got == "integer" {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("%s must be %s, not %s %v", path, strings.Join(types, " or "), got, value)}
+}
+
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int32, int64:
+		return "integer"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// mergeObject overlays the fields of the desired object on the live one, maps are merged and anything else is
+// replaced, so the fields the cluster filled in, like the cluster IP of a service, are kept. The status
+// belongs to the cluster.
+func mergeObject(live, desired *unstructured.Unstructured) *unstructured.Unstructured {
+	merged := live.DeepCopy()
+	for key, value := range desired.Object {
+		if key == "status" {
+			continue
+		}
+		merged.Object[key] = mergeValue(merged.Object[key], value)
+	}
+	merged.SetResourceVersion(live.GetResourceVersion())
+	return merged
+}
+
+func mergeValue(live, desired interface{}) interface{} {
+	liveMap, ok := live.(map[string]interface{})
+	desiredMap, ok2 := desired.(map[string]interface{})
+	if !ok || !ok2 {
+		return runtime.DeepCopyJSONValue(desired)
+	}
+	for key, value := range desiredMap {
+		liveMap[key] = mergeValue(liveMap[key], value)
+	}
+	return liveMap
+}
@@ -0,0 +1,272 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+)
+
+// operationExecutor runs an operation after ApplyOperation responded to it
+type operationExecutor struct {
+	// failure is the summary of the ERROR event of the operation
+	failure func(arReq *meshes.ApplyRuleRequest) string
+	// execute runs the operation, the operations sending their own ERROR events return nil
+	execute func(oClient *Client, ctx context.Context, arReq *meshes.ApplyRuleRequest) error
+}
+
+// failure is an ERROR summary which doesn't depend on the request
+func failure(summary string) func(*meshes.ApplyRuleRequest) string {
+	return func(*meshes.ApplyRuleRequest) string { return summary }
+}
+
+// operationExecutors are the operations which keep running after ApplyOperation responded, by name
+var operationExecutors = map[string]operationExecutor{
+	installOctarineCommand: {
+		failure: func(arReq *meshes.ApplyRuleRequest) string {
+			return fmt.Sprintf("Error while %s Octarine", deletingOr(arReq, "deploying", "removing"))
+		},
+		execute: func(oClient *Client, ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+			if err := oClient.executeInstall(ctx, arReq); err != nil {
+				return err
+			}
+			opName := deletingOr(arReq, "deployed", "removed")
+			oClient.succeeded(arReq, fmt.Sprintf("Octarine %s successfully", opName), fmt.Sprintf("The latest version of Octarine is now %s.", opName))
+			return nil
+		},
+	},
+	installBookInfoCommand: {
+		failure: func(arReq *meshes.ApplyRuleRequest) string {
+			return fmt.Sprintf("Error while %s the canonical Book Info App", deletingOr(arReq, "deploying", "removing"))
+		},
+		execute: func(oClient *Client, ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+			if err := oClient.executeBookInfoInstall(ctx, arReq); err != nil {
+				return err
+			}
+			opName := deletingOr(arReq, "deployed", "removed")
+			oClient.succeeded(arReq, fmt.Sprintf("Book Info app %s successfully", opName), fmt.Sprintf("The canonical Book Info app is now %s.", opName))
+			return nil
+		},
+	},
+	cleanupSamplesCommand: {
+		failure: failure("Error while cleaning up the sample resources"),
+		execute: func(oClient *Client, ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+			details, err := oClient.executeCleanupSamples(ctx, arReq)
+			if err != nil {
+				return err
+			}
+			oClient.succeeded(arReq, "Sample resources cleaned up successfully", details)
+			return nil
+		},
+	},
+	customLabelDeleteOp: {
+		failure: failure("Error while deleting resources by labels"),
+		execute: func(oClient *Client, ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+			if err := oClient.executeSelectorDelete(ctx, arReq); err != nil {
+				return err
+			}
+			oClient.succeeded(arReq, "Resources matching the labels deleted successfully", "All resources matching the labels of the custom YAML are now removed.")
+			return nil
+		},
+	},
+	proxyUpgradeCommand: {
+		failure: failure("Error while upgrading sidecars"),
+		execute: func(oClient *Client, ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+			if err := oClient.executeProxyUpgrade(ctx, arReq); err != nil {
+				return err
+			}
+			oClient.succeeded(arReq, "Sidecars upgraded successfully", "The injected workloads now run the sidecar version of the dataplane.")
+			return nil
+		},
+	},
+	enforcementModeCommand: {
+		failure: func(arReq *meshes.ApplyRuleRequest) string {
+			return fmt.Sprintf("Error while switching the enforcement mode of %s", enforcementScope(arReq))
+		},
+		execute: func(oClient *Client, ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+			if err := oClient.executeEnforcementMode(ctx, arReq); err != nil {
+				return err
+			}
+			oClient.succeeded(arReq, fmt.Sprintf("Enforcement mode of %s switched successfully", enforcementScope(arReq)), "Use the enforcement status to confirm the mode in effect.")
+			return nil
+		},
+	},
+	runtimeProtectionCommand: {
+		failure: func(arReq *meshes.ApplyRuleRequest) string {
+			return fmt.Sprintf("Error while changing the runtime protection of namespace %s", arReq.GetNamespace())
+		},
+		execute: func(oClient *Client, ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+			if err := oClient.executeRuntimeProtection(ctx, arReq); err != nil {
+				return err
+			}
+			action := deletingOr(arReq, "enabled", "disabled")
+			oClient.succeeded(arReq, fmt.Sprintf("Runtime protection %s successfully", action), fmt.Sprintf("The features are %s in namespace %s.", action, arReq.GetNamespace()))
+			return nil
+		},
+	},
+	admissionTestCommand: {
+		failure: failure("Error while testing the admission policies"),
+		execute: (*Client).executeAdmissionTest,
+	},
+	bootstrapCommand: {
+		failure: failure("Error while bootstrapping the Octarine account"),
+		execute: (*Client).executeBootstrap,
+	},
+	trialCommand: {
+		failure: failure("Error while setting up the Octarine trial"),
+		execute: (*Client).executeTrial,
+	},
+	breachSimulationCommand: {
+		failure: failure("Error while simulating a policy breach"),
+		execute: (*Client).executeBreachSimulation,
+	},
+	latencyProbeCommand: {
+		failure: failure("Error while probing the latency of the sidecars"),
+		execute: (*Client).executeLatencyProbe,
+	},
+	protectComponentsCommand: {
+		failure: failure("Error while changing the policies protecting the Octarine components"),
+		execute: (*Client).executeProtectComponents,
+	},
+	spireFederationCommand: {
+		failure: failure("Error while changing the SPIRE federation"),
+		execute: (*Client).executeSpireFederation,
+	},
+	exposeCommand: {
+		failure: failure("Error while exposing the Octarine services"),
+		execute: (*Client).executeExpose,
+	},
+	routePoliciesCommand: {
+		failure: failure("Error while generating the route policies"),
+		execute: (*Client).executeRoutePolicies,
+	},
+	mirrorImagesCommand: {
+		failure: failure("Error while mirroring the Octarine images"),
+		execute: (*Client).executeMirrorImages,
+	},
+	selfTestCommand: {
+		failure: failure("Error while running the self-test"),
+		execute: (*Client).executeSelfTest,
+	},
+	injectionExclusionCommand: {
+		failure: failure("Error while changing the injection exclusions"),
+		execute: (*Client).executeInjectionExclusion,
+	},
+	sidecarResourcesCommand: {
+		failure: failure("Error while setting the sidecar resources"),
+		execute: (*Client).executeSidecarResources,
+	},
+	batchWorkloadsCommand: {
+		failure: failure("Error while handling the batch workloads"),
+		execute: (*Client).executeBatchWorkloads,
+	},
+	policyImportCommand: {
+		failure: failure("Error while importing the policy bundle"),
+		execute: (*Client).executePolicyImport,
+	},
+	demoBlockRatingsCommand:   demoExecutor,
+	demoMTLSCommand:           demoExecutor,
+	demoRestrictEgressCommand: demoExecutor,
+	backupCommand: {
+		failure: failure("Error while backing up Octarine"),
+		execute: func(oClient *Client, ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+			name, err := oClient.executeBackup(ctx, arReq)
+			if err != nil {
+				return err
+			}
+			oClient.succeeded(arReq, fmt.Sprintf("Octarine backed up as %s", name), fmt.Sprintf("Restore it with the %s operation and the custom body backup: %s", backupRestoreCommand, name))
+			return nil
+		},
+	},
+	backupRestoreCommand: {
+		failure: failure("Error while restoring the Octarine backup"),
+		execute: func(oClient *Client, ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+			b, err := oClient.executeRestore(ctx, arReq)
+			if err != nil {
+				return err
+			}
+			oClient.succeeded(arReq, "Octarine backup restored successfully", fmt.Sprintf("Restored %d objects backed up %s.", len(b.Objects), b.Created.Format(time.RFC3339)))
+			return nil
+		},
+	},
+	runVet: {
+		failure: failure("Error while vetting Octarine"),
+		execute: (*Client).runVet,
+	},
+	applyMeshSpecCommand: {
+		failure: failure("Error while reconciling the mesh spec"),
+		execute: func(oClient *Client, ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+			spec, err := parseMeshSpec(arReq.GetCustomBody())
+			if err != nil {
+				return err
+			}
+			_ = oClient.reconcileMeshSpec(ctx, arReq.GetOperationId(), spec)
+			return nil
+		},
+	},
+	reconcileMeshSpecCommand: {
+		failure: failure("Error while reconciling the mesh spec"),
+		execute: func(oClient *Client, ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+			_ = oClient.reconcileMeshSpec(ctx, arReq.GetOperationId(), nil)
+			return nil
+		},
+	},
+}
+
+var demoExecutor = operationExecutor{
+	failure: func(arReq *meshes.ApplyRuleRequest) string {
+		return fmt.Sprintf("Error while running the BookInfo demo step %q", demoScenarios[arReq.GetOpName()].title)
+	},
+	execute: func(oClient *Client, ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+		return oClient.executeDemoScenario(ctx, arReq, demoScenarios[arReq.GetOpName()])
+	},
+}
+
+// runOperation runs an operation in the background under a watchdog, which sends its ERROR event and
+// finishes its result
+func (oClient *Client) runOperation(ctx context.Context, arReq *meshes.ApplyRuleRequest, executor operationExecutor) {
+	go func() {
+		ctx, finish := oClient.startWatchdog(ctx, executor.failure(arReq))
+		finish(executor.execute(oClient, ctx, arReq))
+	}()
+}
+
+// succeeded sends the INFO event of an operation which completed
+func (oClient *Client) succeeded(arReq *meshes.ApplyRuleRequest, summary, details string) {
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     summary,
+		Details:     details,
+	}
+}
+
+// deletingOr is the word describing an operation, the second one when it deletes
+func deletingOr(arReq *meshes.ApplyRuleRequest, applying, deleting string) string {
+	if arReq.GetDeleteOp() {
+		return deleting
+	}
+	return applying
+}
+
+func enforcementScope(arReq *meshes.ApplyRuleRequest) string {
+	if arReq.GetNamespace() != "" {
+		return "namespace " + arReq.GetNamespace()
+	}
+	return "all namespaces"
+}
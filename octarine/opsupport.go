@@ -0,0 +1,104 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// licensedFeaturesTTLEnv is how long the license of an account, and the features it grants, are kept
+	// before the control plane is asked again
+	licensedFeaturesTTLEnv     = "OCTARINE_LICENSED_FEATURES_TTL"
+	defaultLicensedFeaturesTTL = 5 * time.Minute
+)
+
+// opSupport is what a deployment offers the operations: the release it runs and the features of its account.
+// Either is unknown, and doesn't filter the operations, when the version is empty or the features nil.
+type opSupport struct {
+	deployment string
+	version    string
+	features   map[string]bool
+}
+
+// opSupportFor looks up the release and the licensed features of a deployment, before the mesh instance is
+// created or the deployment installed nothing is known
+func (oClient *Client) opSupportFor(ctx context.Context, name string) *opSupport {
+	if name == "" {
+		name = defaultDeploymentName
+	}
+	support := &opSupport{deployment: name}
+	if oClient.clientset(ctx) == nil {
+		return support
+	}
+	d, err := oClient.getDeployment(name)
+	if err != nil {
+		return support
+	}
+	if version, _, err := oClient.dataplaneImages(ctx, d); err == nil && isRelease(version) {
+		support.version = version
+	}
+	support.features = oClient.licensedFeaturesOf(ctx, d)
+	return support
+}
+
+// licensedFeaturesOf are the features the license of the account of a deployment grants, nil when the
+// control plane couldn't be asked
+func (oClient *Client) licensedFeaturesOf(ctx context.Context, d *deployment) map[string]bool {
+	license, err := oClient.cachedLicenseOf(ctx, d)
+	if err != nil {
+		logrus.Warnf("Unable to get the licensed features of deployment %s, operations are not filtered on them: %v", d.name, err)
+		return nil
+	}
+	features := make(map[string]bool, len(license.Features))
+	for _, name := range license.Features {
+		features[name] = true
+	}
+	return features
+}
+
+// featureNames are the licensed features, sorted, nil when unknown
+func (s *opSupport) featureNames() []string {
+	if s.features == nil {
+		return nil
+	}
+	names := make([]string, 0, len(s.features))
+	for name := range s.features {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// unavailable tells why the deployment doesn't support an operation, empty when it does or when it isn't known
+func (s *opSupport) unavailable(op supportedOperation) string {
+	if op.minVersion != "" && s.version != "" && compareVersions(s.version, op.minVersion) < 0 {
+		return fmt.Sprintf("needs Octarine %s or later, deployment %s runs %s", op.minVersion, s.deployment, s.version)
+	}
+	if s.features == nil {
+		return ""
+	}
+	for _, feature := range op.features {
+		if !s.features[feature] {
+			return fmt.Sprintf("needs the %s feature, which the account of deployment %s is not licensed for", feature, s.deployment)
+		}
+	}
+	return ""
+}
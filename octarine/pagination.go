@@ -0,0 +1,81 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// pageCursor is the key of the last item of a page, a page token is the encoded cursor. Pages start after
+// the cursor rather than at an offset, so items added or removed between requests don't shift them.
+type pageCursor string
+
+func (c pageCursor) token() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c))
+}
+
+func parsePageToken(token string) (pageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", fmt.Errorf("error: page_token %q is not a token of a previous page", token)
+	}
+	return pageCursor(b), nil
+}
+
+// paginate picks the page of items, sorted by their unique keys, following the page token. It returns the
+// bounds of the page and the token of the next one, empty on the last page. Page sizes over the maximum
+// are lowered to it.
+func paginate(keys []string, pageSize int32, token string) (int, int, string, error) {
+	size := int(pageSize)
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+	start := 0
+	if token != "" {
+		cursor, err := parsePageToken(token)
+		if err != nil {
+			return 0, 0, "", err
+		}
+		start = sort.Search(len(keys), func(i int) bool { return keys[i] > string(cursor) })
+	}
+	end := start + size
+	if end >= len(keys) {
+		return start, len(keys), "", nil
+	}
+	return start, end, pageCursor(keys[end-1]).token(), nil
+}
+
+// validatePage checks the page size and token of a list request
+func validatePage(pageSize int32, token string) error {
+	if pageSize < 0 {
+		return invalidArgument("page_size must not be negative, got %d", pageSize)
+	}
+	if token != "" {
+		if _, err := parsePageToken(token); err != nil {
+			return invalidArgument("page_token %q is not a token of a previous page", token)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/golang/protobuf/proto"
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+)
+
+// withParameters folds the parameters of a request into its custom body, where the operations read their
+// parameters from. It returns a copy of the request without parameters, and a warning for every parameter
+// replacing a field the custom body set.
+func withParameters(arReq *meshes.ApplyRuleRequest) (*meshes.ApplyRuleRequest, []string, error) {
+	if len(arReq.GetParameters()) == 0 {
+		return arReq, nil, nil
+	}
+	if manifestBodyOps[arReq.GetOpName()] || arReq.GetOpName() == applyMeshSpecCommand {
+		return nil, nil, fmt.Errorf("error: operation %s takes a manifest in its custom body, not parameters", arReq.GetOpName())
+	}
+	body, warnings, err := mergeParameters(arReq.GetCustomBody(), arReq.GetParameters())
+	if err != nil {
+		return nil, nil, err
+	}
+	merged := proto.Clone(arReq).(*meshes.ApplyRuleRequest)
+	merged.CustomBody = body
+	merged.Parameters = nil
+	return merged, warnings, nil
+}
+
+func mergeParameters(body string, parameters map[string]string) (string, []string, error) {
+	values := map[string]interface{}{}
+	if strings.TrimSpace(body) != "" {
+		if err := yaml.Unmarshal([]byte(body), &values); err != nil {
+			return "", nil, errors.Wrapf(err, "unable to parse the operation parameters")
+		}
+	}
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	warnings := []string{}
+	for _, key := range keys {
+		value, err := parameterValue(key, parameters[key])
+		if err != nil {
+			return "", nil, err
+		}
+		if _, ok := values[key]; ok {
+			warnings = append(warnings, fmt.Sprintf("parameter %s replaces the %s of the custom body", key, key))
+		}
+		values[key] = value
+	}
+	merged, err := yaml.Marshal(values)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "unable to write the operation parameters")
+	}
+	return string(merged), warnings, nil
+}
+
+// parameterValue converts a parameter to the type of the field of deploymentParams it sets, the other
+// parameters, such as the values of templates, stay strings
+func parameterValue(key, value string) (interface{}, error) {
+	t := reflect.TypeOf(deploymentParams{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if strings.Split(field.Tag.Get("json"), ",")[0] != key {
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.Int:
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("error: parameter %s must be a number, got %q", key, value)
+			}
+			return n, nil
+		case reflect.Bool:
+			b, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("error: parameter %s must be true or false, got %q", key, value)
+			}
+			return b, nil
+		case reflect.Slice:
+			items := []interface{}{}
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			return items, nil
+		}
+		return value, nil
+	}
+	return value, nil
+}
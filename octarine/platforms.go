@@ -0,0 +1,277 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	osLabel       = "kubernetes.io/os"
+	archLabel     = "kubernetes.io/arch"
+	betaOSLabel   = "beta.kubernetes.io/os"
+	betaArchLabel = "beta.kubernetes.io/arch"
+)
+
+// platform is an os/arch pair nodes run and images are published for
+type platform struct {
+	os   string
+	arch string
+}
+
+func (p platform) String() string {
+	return p.os + "/" + p.arch
+}
+
+func parsePlatforms(value string) []platform {
+	platforms := []platform{}
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(item), "/", 2)
+		if len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+			platforms = append(platforms, platform{os: parts[0], arch: parts[1]})
+		}
+	}
+	return platforms
+}
+
+func platformNames(platforms map[platform]bool) string {
+	names := make([]string, 0, len(platforms))
+	for p := range platforms {
+		names = append(names, p.String())
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// nodePlatforms returns the platforms of the schedulable nodes, and whether all of them carry the stable labels
+func (oClient *Client) nodePlatforms(ctx context.Context) (map[platform]bool, bool, error) {
+	nodes, err := oClient.clientset(ctx).CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		err = errors.Wrapf(err, "unable to list the nodes")
+		logrus.Error(err)
+		return nil, false, err
+	}
+	platforms := map[platform]bool{}
+	stable := true
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		labels := node.GetLabels()
+		if labels[osLabel] == "" || labels[archLabel] == "" {
+			stable = false
+		}
+		platforms[platform{os: node.Status.NodeInfo.OperatingSystem, arch: node.Status.NodeInfo.Architecture}] = true
+	}
+	return platforms, stable, nil
+}
+
+// podSpecPath is where the pod template of a workload kind keeps its spec
+func podSpecPath(kind string) []string {
+	switch kind {
+	case "Deployment", "DaemonSet", "StatefulSet", "ReplicaSet", "Job":
+		return []string{"spec", "template", "spec"}
+	case "CronJob":
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	case "Pod":
+		return []string{"spec"}
+	}
+	return nil
+}
+
+// manifestImages lists the container images of the workloads in a manifest
+func manifestImages(manifest string) ([]string, error) {
+	objects, err := parseManifestObjects(manifest)
+	if err != nil {
+		return nil, err
+	}
+	found := map[string]bool{}
+	for _, obj := range objects {
+		path := podSpecPath(obj.GetKind())
+		if path == nil {
+			continue
+		}
+		for _, field := range []string{"initContainers", "containers"} {
+			containers, _, err := nestedObjects(obj.Object, append(path, field)...)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to read the containers of %s %s", obj.GetKind(), obj.GetName())
+			}
+			for _, container := range containers {
+				if image, _, _ := unstructured.NestedString(container, "image"); image != "" {
+					found[image] = true
+				}
+			}
+		}
+	}
+	return sortedKeys(found), nil
+}
+
+// imagePlatforms returns the platforms every image of the manifest is available for,
+// OCTARINE_IMAGE_PLATFORMS replaces the registry lookups for air-gapped clusters
+func (oClient *Client) imagePlatforms(ctx context.Context, d *deployment, manifest string) (map[platform]bool, error) {
+	if value := os.Getenv("OCTARINE_IMAGE_PLATFORMS"); value != "" {
+		result := map[platform]bool{}
+		for _, p := range parsePlatforms(value) {
+			result[p] = true
+		}
+		return result, nil
+	}
+	images, err := manifestImages(manifest)
+	if err != nil {
+		return nil, err
+	}
+	rc := newRegistryClient()
+	var result map[platform]bool
+	for _, image := range images {
+		workingOn(ctx, "looking up the platforms of image %s", image)
+		platforms, err := rc.imagePlatforms(ctx, image)
+		if err != nil {
+			// a registry we can't read doesn't mean the nodes can't pull, leave the image out of the check
+			logrus.Warn(err)
+			oClient.eventChan <- &meshes.EventsResponse{
+				OperationId: operationIDFrom(ctx),
+				EventType:   meshes.EventType_WARN,
+				Summary:     fmt.Sprintf("Unable to check the platforms of image %s", image),
+				Details:     err.Error(),
+			}
+			continue
+		}
+		progressed(ctx)
+		available := map[platform]bool{}
+		for _, p := range platforms {
+			if result == nil || result[p] {
+				available[p] = true
+			}
+		}
+		result = available
+		if len(result) == 0 {
+			return nil, fmt.Errorf("error: the images of Octarine %s are not published for a common platform, image %s supports %s",
+				d.versionName(), image, platformList(platforms))
+		}
+	}
+	return result, nil
+}
+
+func platformList(platforms []platform) string {
+	set := map[platform]bool{}
+	for _, p := range platforms {
+		set[p] = true
+	}
+	return platformNames(set)
+}
+
+// preflightPlatforms fails when no node can run the images of the manifest, and restricts
+// the workloads to the nodes which can
+func (oClient *Client) preflightPlatforms(ctx context.Context, d *deployment, manifest string) (string, error) {
+	nodes, stable, err := oClient.nodePlatforms(ctx)
+	if err != nil {
+		return "", err
+	}
+	supported, err := oClient.imagePlatforms(ctx, d, manifest)
+	if err != nil {
+		return "", err
+	}
+	if supported == nil {
+		// no image could be checked, the dataplane is built for Linux so it is at least kept off Windows nodes
+		supported = withoutWindows(nodes)
+		if len(supported) == len(nodes) {
+			return manifest, nil
+		}
+		if len(supported) == 0 {
+			return "", fmt.Errorf("error: the nodes of the cluster all run %s, the Octarine dataplane needs Linux nodes", platformNames(nodes))
+		}
+	}
+	usable := map[platform]bool{}
+	for p := range nodes {
+		if supported[p] {
+			usable[p] = true
+		}
+	}
+	if len(usable) == 0 {
+		return "", fmt.Errorf("error: Octarine %s is published for %s but the nodes of the cluster run %s",
+			d.versionName(), platformNames(supported), platformNames(nodes))
+	}
+	if len(usable) < len(nodes) {
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: operationIDFrom(ctx),
+			EventType:   meshes.EventType_WARN,
+			Summary:     fmt.Sprintf("The dataplane only runs on %s nodes", platformNames(usable)),
+			Details:     fmt.Sprintf("Octarine %s is published for %s, the nodes of the cluster run %s.", d.versionName(), platformNames(supported), platformNames(nodes)),
+		}
+	}
+	// restricting homogeneous clusters too keeps the pods off nodes of other platforms joining later
+	return restrictToPlatforms(manifest, usable, stable)
+}
+
+// restrictToPlatforms adds a node affinity for the given platforms to the workloads of a manifest
+func restrictToPlatforms(manifest string, platforms map[platform]bool, stableLabels bool) (string, error) {
+	osKey, archKey := osLabel, archLabel
+	if !stableLabels {
+		osKey, archKey = betaOSLabel, betaArchLabel
+	}
+	sorted := make([]platform, 0, len(platforms))
+	for p := range platforms {
+		sorted = append(sorted, p)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].String() < sorted[j].String() })
+
+	return transformManifest(manifest, func(data *unstructured.Unstructured) error {
+		path := podSpecPath(data.GetKind())
+		if path == nil {
+			return nil
+		}
+		termsPath := append(path, "affinity", "nodeAffinity", "requiredDuringSchedulingIgnoredDuringExecution", "nodeSelectorTerms")
+		existing, _, err := nestedObjects(data.Object, termsPath...)
+		if err != nil {
+			return err
+		}
+		if len(existing) == 0 {
+			existing = []map[string]interface{}{{}}
+		}
+		// terms are ORed and their expressions ANDed, so every existing term is combined with every platform
+		terms := []interface{}{}
+		for _, term := range existing {
+			for _, p := range sorted {
+				expressions, _, _ := unstructured.NestedSlice(term, "matchExpressions")
+				expressions = append(append([]interface{}{}, expressions...),
+					nodeSelectorRequirement(osKey, p.os), nodeSelectorRequirement(archKey, p.arch))
+				copied := runtime.DeepCopyJSON(term)
+				copied["matchExpressions"] = expressions
+				terms = append(terms, copied)
+			}
+		}
+		return unstructured.SetNestedSlice(data.Object, terms, termsPath...)
+	})
+}
+
+func nodeSelectorRequirement(key, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"key":      key,
+		"operator": string(corev1.NodeSelectorOpIn),
+		"values":   []interface{}{value},
+	}
+}
@@ -0,0 +1,370 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// policyRemovalLimitEnv is how many policies an import may remove before it needs confirm
+	policyRemovalLimitEnv     = "OCTARINE_POLICY_REMOVAL_LIMIT"
+	defaultPolicyRemovalLimit = 3
+)
+
+// bundlePolicy is an Octarine policy of a bundle, or of a namespace of the domain
+type bundlePolicy struct {
+	kind string
+	name string
+	doc  map[string]interface{}
+}
+
+func (p *bundlePolicy) key() string {
+	return p.kind + " " + p.name
+}
+
+// spec is the specification of the policy, with its rules
+func (p *bundlePolicy) spec() map[string]interface{} {
+	spec, _ := p.doc["spec"].(map[string]interface{})
+	return spec
+}
+
+func (p *bundlePolicy) rules() []string {
+	rules, _ := p.spec()["rules"].([]interface{})
+	out := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		out = append(out, compactJSON(rule))
+	}
+	return out
+}
+
+func compactJSON(v interface{}) string {
+	// maps are written with sorted keys, so equal values compare equal as strings
+	out, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(out)
+}
+
+// parsePolicyBundle reads the policies of a bundle, YAML documents of Octarine policies, into the namespace
+// they are imported to
+func parsePolicyBundle(bundle, namespace string) ([]*bundlePolicy, error) {
+	policies := []*bundlePolicy{}
+	seen := map[string]bool{}
+	for i, doc := range strings.Split(bundle, "\n---") {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		p := &bundlePolicy{doc: map[string]interface{}{}}
+		if err := yaml.Unmarshal([]byte(doc), &p.doc); err != nil {
+			return nil, errors.Wrapf(err, "unable to parse document %d of the policy bundle", i+1)
+		}
+		if len(p.doc) == 0 {
+			continue
+		}
+		p.kind, _ = p.doc["kind"].(string)
+		p.name, _ = p.doc["name"].(string)
+		if p.kind == "" || p.name == "" {
+			return nil, fmt.Errorf("error: document %d of the policy bundle has no kind or name", i+1)
+		}
+		if ns, _ := p.doc["namespace"].(string); ns != "" && ns != namespace {
+			return nil, fmt.Errorf("error: policy %s of the bundle is for namespace %s, the bundle is imported to namespace %s", p.key(), ns, namespace)
+		}
+		p.doc["namespace"] = namespace
+		if seen[p.key()] {
+			return nil, fmt.Errorf("error: the policy bundle has policy %s twice", p.key())
+		}
+		seen[p.key()] = true
+		policies = append(policies, p)
+	}
+	if len(policies) == 0 {
+		return nil, fmt.Errorf("error: the policy bundle has no policies")
+	}
+	return policies, nil
+}
+
+// validatePolicyImport checks the bundle of a policy import before it runs
+func validatePolicyImport(params *deploymentParams, namespace string, deleteOp bool) error {
+	if deleteOp {
+		return fmt.Errorf("error: %s can't be deleted, import a bundle without the policies to remove them", policyImportCommand)
+	}
+	if namespace == "" {
+		return fmt.Errorf("error: the namespace the policy bundle is imported to is required")
+	}
+	if strings.TrimSpace(params.Bundle) == "" {
+		return fmt.Errorf("error: the bundle of policies to import is required")
+	}
+	_, err := parsePolicyBundle(params.Bundle, namespace)
+	return err
+}
+
+// policyChange is how a policy of a bundle differs from the one applied
+type policyChange struct {
+	policy *bundlePolicy
+	// addedRules and removedRules are the rules of the policy only the bundle, or only the applied policy, has
+	addedRules, removedRules []string
+	// fields are the other fields of the specification which change, with their values
+	fields []string
+}
+
+// policyDiff is what importing a bundle changes in a namespace of the domain
+type policyDiff struct {
+	added    []*bundlePolicy
+	removed  []*bundlePolicy
+	modified []*policyChange
+}
+
+func (d *policyDiff) empty() bool {
+	return len(d.added) == 0 && len(d.removed) == 0 && len(d.modified) == 0
+}
+
+// String lists the changes, a line per added or removed policy and per changed rule or field
+func (d *policyDiff) String() string {
+	lines := []string{}
+	for _, p := range d.added {
+		lines = append(lines, fmt.Sprintf("+ %s (%d rule(s))", p.key(), len(p.rules())))
+	}
+	for _, p := range d.removed {
+		lines = append(lines, fmt.Sprintf("- %s (%d rule(s))", p.key(), len(p.rules())))
+	}
+	for _, c := range d.modified {
+		lines = append(lines, "~ "+c.policy.key())
+		for _, rule := range c.removedRules {
+			lines = append(lines, "    - rule "+rule)
+		}
+		for _, rule := range c.addedRules {
+			lines = append(lines, "    + rule "+rule)
+		}
+		for _, field := range c.fields {
+			lines = append(lines, "    "+field)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// diffPolicies compares the policies of a bundle with those applied to the namespace: the policies only the
+// bundle has are added, those only the namespace has removed, and the others compared rule by rule
+func diffPolicies(current, incoming []*bundlePolicy) *policyDiff {
+	diff := &policyDiff{}
+	applied := map[string]*bundlePolicy{}
+	for _, p := range current {
+		applied[p.key()] = p
+	}
+	kept := map[string]bool{}
+	for _, p := range incoming {
+		kept[p.key()] = true
+		old, ok := applied[p.key()]
+		if !ok {
+			diff.added = append(diff.added, p)
+			continue
+		}
+		if change := comparePolicies(old, p); change != nil {
+			diff.modified = append(diff.modified, change)
+		}
+	}
+	for _, p := range current {
+		if !kept[p.key()] {
+			diff.removed = append(diff.removed, p)
+		}
+	}
+	return diff
+}
+
+func comparePolicies(old, p *bundlePolicy) *policyChange {
+	change := &policyChange{policy: p}
+	oldRules := map[string]int{}
+	for _, rule := range old.rules() {
+		oldRules[rule]++
+	}
+	for _, rule := range p.rules() {
+		if oldRules[rule] > 0 {
+			oldRules[rule]--
+			continue
+		}
+		change.addedRules = append(change.addedRules, rule)
+	}
+	for _, rule := range old.rules() {
+		if oldRules[rule] > 0 {
+			oldRules[rule]--
+			change.removedRules = append(change.removedRules, rule)
+		}
+	}
+	fields := map[string]bool{}
+	for field := range old.spec() {
+		fields[field] = true
+	}
+	for field := range p.spec() {
+		fields[field] = true
+	}
+	delete(fields, "rules")
+	for _, field := range sortedKeys(fields) {
+		before, after := old.spec()[field], p.spec()[field]
+		if compactJSON(before) == compactJSON(after) {
+			continue
+		}
+		switch {
+		case before == nil:
+			change.fields = append(change.fields, fmt.Sprintf("spec.%s: %s", field, compactJSON(after)))
+		case after == nil:
+			change.fields = append(change.fields, fmt.Sprintf("spec.%s: %s removed", field, compactJSON(before)))
+		default:
+			change.fields = append(change.fields, fmt.Sprintf("spec.%s: %s -> %s", field, compactJSON(before), compactJSON(after)))
+		}
+	}
+	if len(change.addedRules) == 0 && len(change.removedRules) == 0 && len(change.fields) == 0 {
+		return nil
+	}
+	return change
+}
+
+// appliedPolicies reads the policies applied to a namespace of the domain. The policies generated from
+// HTTPRoutes are left out, octarine_route_policies keeps them.
+func (oClient *Client) appliedPolicies(ctx context.Context, d *deployment, namespace string) ([]*bundlePolicy, error) {
+	var out []byte
+	err := oClient.asAccount(ctx, d, func() error {
+		var err error
+		out, err = policyCommand(ctx, d, namespace, "list", "--output", "json").Output()
+		return err
+	})
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			logrus.Errorf("Command finished with error: %v: %s", err, exitErr.Stderr)
+		}
+		return nil, errors.Wrapf(err, "unable to list the policies of namespace %s", namespace)
+	}
+	records := []policyRecord{}
+	if err := json.Unmarshal(out, &records); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the policies of namespace %s", namespace)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Kind+" "+records[i].Name < records[j].Kind+" "+records[j].Name
+	})
+	policies := []*bundlePolicy{}
+	for _, r := range records {
+		if r.Kind == httpPolicyKind && strings.HasPrefix(r.Name, routePolicyPrefix) {
+			continue
+		}
+		manifest, err := oClient.policyManifest(ctx, d, namespace, r.Name)
+		if err != nil {
+			return nil, err
+		}
+		p := &bundlePolicy{kind: r.Kind, name: r.Name, doc: map[string]interface{}{}}
+		if err := yaml.Unmarshal([]byte(manifest), &p.doc); err != nil {
+			return nil, errors.Wrapf(err, "unable to parse policy %s of namespace %s", r.Name, namespace)
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+// executePolicyImport makes the policies of a namespace of the domain those of a bundle. The changes are
+// reviewed first in an event: the policies added, removed, and modified rule by rule. An import removing more
+// policies than OCTARINE_POLICY_REMOVAL_LIMIT changes nothing unless it is confirmed. Removed policies go to the
+// trash.
+func (oClient *Client) executePolicyImport(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	if oClient.clientset(ctx) == nil {
+		return fmt.Errorf("error: mesh client has not been created")
+	}
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	namespace := arReq.GetNamespace()
+	incoming, err := parsePolicyBundle(params.Bundle, namespace)
+	if err != nil {
+		return err
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return err
+	}
+	workingOn(ctx, "the policies of namespace %s", namespace)
+	current, err := oClient.appliedPolicies(ctx, d, namespace)
+	if err != nil {
+		return err
+	}
+	progressed(ctx)
+
+	diff := diffPolicies(current, incoming)
+	if diff.empty() {
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: arReq.GetOperationId(),
+			EventType:   meshes.EventType_INFO,
+			Summary:     fmt.Sprintf("The policies of namespace %s already match the bundle", namespace),
+		}
+		return nil
+	}
+	review := &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Policy bundle for namespace %s adds %d, removes %d and modifies %d policies", namespace, len(diff.added), len(diff.removed), len(diff.modified)),
+		Details:     diff.String(),
+	}
+	limit := intFromEnv(policyRemovalLimitEnv, defaultPolicyRemovalLimit)
+	if len(diff.removed) > limit && !params.Confirm {
+		review.EventType = meshes.EventType_WARN
+		review.Details = fmt.Sprintf("Nothing was changed, this removes more than %d policies. Run the operation again with confirm set to proceed.\n%s", limit, review.Details)
+		oClient.eventChan <- review
+		return fmt.Errorf("error: the policy bundle removes %d policies of namespace %s, more than the limit of %d; set confirm to proceed", len(diff.removed), namespace, limit)
+	}
+	oClient.eventChan <- review
+
+	docs := make([]string, 0, len(incoming))
+	for _, p := range incoming {
+		out, err := yaml.Marshal(p.doc)
+		if err != nil {
+			return errors.Wrapf(err, "unable to write policy %s", p.key())
+		}
+		docs = append(docs, string(out))
+	}
+	workingOn(ctx, "applying the policy bundle to namespace %s", namespace)
+	err = oClient.asAccount(ctx, d, func() error {
+		cmd := policyCommand(ctx, d, namespace, "apply", "-f", "-")
+		cmd.Stdin = strings.NewReader(strings.Join(docs, "---\n"))
+		if out, err := cmd.CombinedOutput(); err != nil {
+			logrus.Errorf("Command finished with error: %v: %s", err, out)
+			return errors.Wrapf(err, "unable to apply the policy bundle to namespace %s", namespace)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	progressed(ctx)
+	for _, p := range diff.removed {
+		workingOn(ctx, "removing policy %s of namespace %s", p.key(), namespace)
+		if err := oClient.deletePolicy(ctx, d, namespace, p.name); err != nil {
+			return err
+		}
+		progressed(ctx)
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Imported %d policies to namespace %s", len(incoming), namespace),
+	}
+	return nil
+}
@@ -0,0 +1,449 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	policyEngineGatekeeper = "gatekeeper"
+	policyEngineKyverno    = "kyverno"
+
+	gatekeeperTemplateVersion   = "templates.gatekeeper.sh/v1beta1"
+	gatekeeperConstraintVersion = "constraints.gatekeeper.sh/v1beta1"
+	kyvernoPolicyVersion        = "kyverno.io/v1"
+
+	// the ConstraintTemplate is shared by the deployments, each of them has its own constraint
+	protectTemplateName   = "octarineprotectcomponents"
+	protectConstraintKind = "OctarineProtectComponents"
+	protectPolicyName     = "octarine-protect-components"
+
+	// Gatekeeper serves the kind of a constraint a few seconds after its template was created
+	constraintKindTimeout      = time.Minute
+	constraintKindPollInterval = 2 * time.Second
+)
+
+var (
+	gatekeeperTemplateResource   = schema.GroupVersionResource{Group: "templates.gatekeeper.sh", Version: "v1beta1", Resource: "constrainttemplates"}
+	gatekeeperConstraintResource = schema.GroupVersionResource{Group: "constraints.gatekeeper.sh", Version: "v1beta1", Resource: "octarineprotectcomponents"}
+	kyvernoPolicyResource        = schema.GroupVersionResource{Group: "kyverno.io", Version: "v1", Resource: "clusterpolicies"}
+
+	// protectedKinds are the kinds of the components of a deployment the policies guard, by API group; pods
+	// are left out as their controllers replace them
+	protectedKinds = []struct {
+		group string
+		kinds []string
+	}{
+		{"", []string{"Namespace", "ConfigMap", "Secret", "Service", "ServiceAccount"}},
+		{"apps", []string{"Deployment", "DaemonSet", "StatefulSet"}},
+		{"rbac.authorization.k8s.io", []string{"Role", "RoleBinding", "ClusterRole", "ClusterRoleBinding"}},
+		{"admissionregistration.k8s.io", []string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"}},
+	}
+
+	// the controllers of kube-system, such as the garbage collector, keep changing what they own
+	protectExemptGroups = []string{"system:serviceaccounts:kube-system"}
+)
+
+// protectTemplateRego denies changing or deleting the objects a constraint matches to the users and groups
+// it doesn't exempt
+const protectTemplateRego = `package octarineprotectcomponents
+
+exempt {
+  input.review.userInfo.username == input.parameters.exemptUsers[_]
+}
+
+exempt {
+  input.review.userInfo.groups[_] == input.parameters.exemptGroups[_]
+}
+
+violation[{"msg": msg}] {
+  not exempt
+  input.review.operation != "CREATE"
+  msg := sprintf("%v %v is an Octarine component managed by meshery-octarine, change it through the adapter", [input.review.kind.kind, input.review.name])
+}
+`
+
+func validatePolicyEngine(engine string) error {
+	switch engine {
+	case "", policyEngineGatekeeper, policyEngineKyverno:
+		return nil
+	}
+	return fmt.Errorf("error: unknown policy engine %q, known engines are %s and %s", engine, policyEngineGatekeeper, policyEngineKyverno)
+}
+
+// detectPolicyEngine tells which policy engine of the cluster protects the components, the one of the
+// custom body when both are installed
+func (oClient *Client) detectPolicyEngine(ctx context.Context, engine string) (string, error) {
+	served := map[string]bool{}
+	for name, groupVersion := range map[string]string{policyEngineGatekeeper: gatekeeperTemplateVersion, policyEngineKyverno: kyvernoPolicyVersion} {
+		if _, err := oClient.clientset(ctx).Discovery().ServerResourcesForGroupVersion(groupVersion); err == nil {
+			served[name] = true
+		} else if !apierrors.IsNotFound(err) {
+			return "", errors.Wrapf(err, "unable to discover %s", groupVersion)
+		}
+	}
+	switch {
+	case engine != "" && !served[engine]:
+		return "", fmt.Errorf("error: %s is not installed in the cluster", engine)
+	case engine != "":
+		return engine, nil
+	case served[policyEngineGatekeeper] && served[policyEngineKyverno]:
+		return "", fmt.Errorf("error: both %s and %s are installed, set the engine of the custom body", policyEngineGatekeeper, policyEngineKyverno)
+	case served[policyEngineGatekeeper]:
+		return policyEngineGatekeeper, nil
+	case served[policyEngineKyverno]:
+		return policyEngineKyverno, nil
+	}
+	return "", fmt.Errorf("error: neither %s nor %s is installed in the cluster", policyEngineGatekeeper, policyEngineKyverno)
+}
+
+// adapterUser is the user the adapter authenticates as, read from its credentials, empty when they don't
+// tell, as with exec and auth provider plugins. Tokens aren't verified, the name only exempts the adapter
+// from its own policies.
+func adapterUser(config *rest.Config) string {
+	if config == nil {
+		return ""
+	}
+	if config.Username != "" {
+		return config.Username
+	}
+	certData := config.CertData
+	if len(certData) == 0 && config.CertFile != "" {
+		certData, _ = ioutil.ReadFile(config.CertFile)
+	}
+	if block, _ := pem.Decode(certData); block != nil {
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			return cert.Subject.CommonName
+		}
+	}
+	token := config.BearerToken
+	if token == "" && config.BearerTokenFile != "" {
+		data, _ := ioutil.ReadFile(config.BearerTokenFile)
+		token = strings.TrimSpace(string(data))
+	}
+	// ServiceAccount tokens are JWTs whose subject is the user name
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	claims := struct {
+		Subject string `json:"sub"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+	return claims.Subject
+}
+
+// protectExemptUsers are the users still allowed to change the components: the adapter and those of the
+// custom body
+func (oClient *Client) protectExemptUsers(exempt []string) ([]string, error) {
+	users := map[string]bool{}
+	for _, u := range exempt {
+		if u = strings.TrimSpace(u); u != "" {
+			users[u] = true
+		}
+	}
+	if u := adapterUser(oClient.restConfig()); u != "" {
+		users[u] = true
+	} else if len(users) == 0 {
+		return nil, errors.New("error: the user the adapter connects as is unknown, list it in the exempt users of the custom body so it can still manage Octarine")
+	}
+	return sortedKeys(users), nil
+}
+
+func stringItems(values []string) []interface{} {
+	items := make([]interface{}, len(values))
+	for i, v := range values {
+		items[i] = v
+	}
+	return items
+}
+
+// gatekeeperPolicyPack is the template of the protection and the constraint of a deployment
+func gatekeeperPolicyPack(d *deployment, users []string) (*unstructured.Unstructured, *unstructured.Unstructured) {
+	template := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": gatekeeperTemplateVersion,
+		"kind":       "ConstraintTemplate",
+		"metadata":   map[string]interface{}{"name": protectTemplateName},
+		"spec": map[string]interface{}{
+			"crd": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"names": map[string]interface{}{"kind": protectConstraintKind},
+					"validation": map[string]interface{}{
+						"openAPIV3Schema": map[string]interface{}{
+							"properties": map[string]interface{}{
+								"exemptUsers":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+								"exemptGroups": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+							},
+						},
+					},
+				},
+			},
+			"targets": []interface{}{
+				map[string]interface{}{"target": "admission.k8s.gatekeeper.sh", "rego": protectTemplateRego},
+			},
+		},
+	}}
+	template.SetLabels(map[string]string{managedByLabel: managedByValue})
+
+	kinds := []interface{}{}
+	for _, k := range protectedKinds {
+		kinds = append(kinds, map[string]interface{}{"apiGroups": []interface{}{k.group}, "kinds": stringItems(k.kinds)})
+	}
+	matchLabels := map[string]interface{}{}
+	for k, v := range d.managedLabels() {
+		matchLabels[k] = v
+	}
+	constraint := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": gatekeeperConstraintVersion,
+		"kind":       protectConstraintKind,
+		"metadata":   map[string]interface{}{"name": d.clusterResourceName(protectPolicyName)},
+		"spec": map[string]interface{}{
+			"enforcementAction": "deny",
+			"match": map[string]interface{}{
+				"kinds":         kinds,
+				"labelSelector": map[string]interface{}{"matchLabels": matchLabels},
+			},
+			"parameters": map[string]interface{}{
+				"exemptUsers":  stringItems(users),
+				"exemptGroups": stringItems(protectExemptGroups),
+			},
+		},
+	}}
+	constraint.SetLabels(d.managedLabels())
+	return template, constraint
+}
+
+// kyvernoPolicyPack is the ClusterPolicy protecting the components of a deployment
+func kyvernoPolicyPack(d *deployment, users []string) *unstructured.Unstructured {
+	kinds := []string{}
+	for _, k := range protectedKinds {
+		kinds = append(kinds, k.kinds...)
+	}
+	matchLabels := map[string]interface{}{}
+	for k, v := range d.managedLabels() {
+		matchLabels[k] = v
+	}
+	subjects := []interface{}{}
+	for _, u := range users {
+		subjects = append(subjects, map[string]interface{}{"kind": "User", "name": u})
+	}
+	for _, g := range protectExemptGroups {
+		subjects = append(subjects, map[string]interface{}{"kind": "Group", "name": g})
+	}
+	policy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": kyvernoPolicyVersion,
+		"kind":       "ClusterPolicy",
+		"metadata":   map[string]interface{}{"name": d.clusterResourceName(protectPolicyName)},
+		"spec": map[string]interface{}{
+			"validationFailureAction": "enforce",
+			"background":              false,
+			"rules": []interface{}{
+				map[string]interface{}{
+					"name": "protect-components",
+					"match": map[string]interface{}{
+						"resources": map[string]interface{}{
+							"kinds":    stringItems(kinds),
+							"selector": map[string]interface{}{"matchLabels": matchLabels},
+						},
+					},
+					"exclude": map[string]interface{}{"subjects": subjects},
+					"validate": map[string]interface{}{
+						"message": "{{request.object.kind || request.oldObject.kind}} {{request.name}} is an Octarine component managed by meshery-octarine, change it through the adapter",
+						"deny": map[string]interface{}{
+							"conditions": []interface{}{
+								map[string]interface{}{
+									"key":      "{{request.operation}}",
+									"operator": "In",
+									"value":    []interface{}{"UPDATE", "DELETE"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+	policy.SetLabels(d.managedLabels())
+	return policy
+}
+
+// applyConstraint creates the constraint of a deployment once Gatekeeper serves its kind
+func (oClient *Client) applyConstraint(ctx context.Context, constraint *unstructured.Unstructured) error {
+	deadline := time.Now().Add(constraintKindTimeout)
+	for !oClient.constraintKindServed(ctx) {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("error: Gatekeeper didn't serve the %s constraints within %s of creating template %s", protectConstraintKind, constraintKindTimeout, protectTemplateName)
+		}
+		workingOn(ctx, "Gatekeeper to serve the %s constraints", protectConstraintKind)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(constraintKindPollInterval):
+		}
+	}
+	return oClient.applyClusterObject(ctx, gatekeeperConstraintResource, constraint)
+}
+
+// applyClusterObject creates or updates a cluster scoped object of the policy engines, whose resources
+// resourceFor can't tell from their kind
+func (oClient *Client) applyClusterObject(ctx context.Context, res schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	if err := oClient.createResource(ctx, res, obj); err != nil {
+		if denial, ok := asAdmissionDenial(err, obj); ok {
+			return oClient.denied(ctx, denial, obj)
+		}
+		live, err := oClient.getResource(ctx, res, obj)
+		if err != nil {
+			return err
+		}
+		if err := oClient.updateResource(ctx, res, mergeObject(live, obj)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// constraintKindServed tells whether the constraints of the template can be created already
+func (oClient *Client) constraintKindServed(ctx context.Context) bool {
+	list, err := oClient.clientset(ctx).Discovery().ServerResourcesForGroupVersion(gatekeeperConstraintVersion)
+	if err != nil {
+		return false
+	}
+	for _, r := range list.APIResources {
+		if r.Kind == protectConstraintKind {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteGatekeeperPolicyPack deletes the constraint of a deployment, and the template along with the last
+// constraint
+func (oClient *Client) deleteGatekeeperPolicyPack(ctx context.Context, template, constraint *unstructured.Unstructured) error {
+	if oClient.constraintKindServed(ctx) {
+		err := oClient.dynamicClient(ctx).Resource(gatekeeperConstraintResource).Delete(constraint.GetName(), &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "unable to delete constraint %s", constraint.GetName())
+		}
+		remaining, err := oClient.dynamicClient(ctx).Resource(gatekeeperConstraintResource).List(metav1.ListOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "unable to list the %s constraints", protectConstraintKind)
+		}
+		if len(remaining.Items) > 0 {
+			return nil
+		}
+	}
+	progressed(ctx)
+	err := oClient.dynamicClient(ctx).Resource(gatekeeperTemplateResource).Delete(template.GetName(), &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "unable to delete constraint template %s", template.GetName())
+	}
+	return nil
+}
+
+// executeProtectComponents installs the policies denying other users to change or delete the components of
+// a deployment, or uninstalls them when deleting
+func (oClient *Client) executeProtectComponents(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	if oClient.clientset(ctx) == nil || oClient.dynamicClient(ctx) == nil {
+		return fmt.Errorf("error: mesh client has not been created")
+	}
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	if err := validatePolicyEngine(params.Engine); err != nil {
+		return err
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return err
+	}
+	engine, err := oClient.detectPolicyEngine(ctx, params.Engine)
+	if err != nil {
+		return err
+	}
+	users, err := oClient.protectExemptUsers(params.Exempt)
+	if err != nil && !arReq.GetDeleteOp() {
+		return err
+	}
+
+	workingOn(ctx, "the %s policies protecting deployment %s", engine, d.name)
+	switch {
+	case engine == policyEngineKyverno && arReq.GetDeleteOp():
+		policy := kyvernoPolicyPack(d, users)
+		err := oClient.dynamicClient(ctx).Resource(kyvernoPolicyResource).Delete(policy.GetName(), &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "unable to delete cluster policy %s", policy.GetName())
+		}
+	case engine == policyEngineKyverno:
+		if err := oClient.applyClusterObject(ctx, kyvernoPolicyResource, kyvernoPolicyPack(d, users)); err != nil {
+			return err
+		}
+	case arReq.GetDeleteOp():
+		template, constraint := gatekeeperPolicyPack(d, users)
+		if err := oClient.deleteGatekeeperPolicyPack(ctx, template, constraint); err != nil {
+			return err
+		}
+	default:
+		template, constraint := gatekeeperPolicyPack(d, users)
+		if err := oClient.applyClusterObject(ctx, gatekeeperTemplateResource, template); err != nil {
+			return err
+		}
+		if err := oClient.applyConstraint(ctx, constraint); err != nil {
+			return err
+		}
+	}
+	progressed(ctx)
+
+	if arReq.GetDeleteOp() {
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: arReq.GetOperationId(),
+			EventType:   meshes.EventType_INFO,
+			Summary:     fmt.Sprintf("The components of deployment %s are no longer protected", d.name),
+			Details:     fmt.Sprintf("The %s policies protecting them were removed.", engine),
+		}
+		return nil
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("The components of deployment %s are protected by %s", d.name, engine),
+		Details: fmt.Sprintf("Only %s and the groups %s may change or delete the resources of the deployment.",
+			strings.Join(users, ", "), strings.Join(protectExemptGroups, ", ")),
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultProbeImage = "busybox:1.31"
+	// probes take seconds, the deadline keeps a stuck pod from lingering
+	probePodDeadline = 2 * time.Minute
+)
+
+func probeImage() string {
+	if image := os.Getenv("OCTARINE_PROBE_IMAGE"); image != "" {
+		return image
+	}
+	return defaultProbeImage
+}
+
+// probePod is a short lived pod running a shell script whose log is the outcome of the probe, it is labeled
+// as a sample so a pod left behind by a crashed adapter gets cleaned up along with the sample apps
+func probePod(name, namespace, container, script string, labels map[string]string, env []corev1.EnvVar) *corev1.Pod {
+	labels[sampleAppLabel] = sampleProbe
+	deadline := int64(probePodDeadline / time.Second)
+	grace := int64(0)
+	automount := false
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Spec: corev1.PodSpec{
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			ActiveDeadlineSeconds:         &deadline,
+			TerminationGracePeriodSeconds: &grace,
+			AutomountServiceAccountToken:  &automount,
+			Containers: []corev1.Container{{
+				Name:    container,
+				Image:   probeImage(),
+				Command: []string{"sh", "-c", script},
+				Env:     env,
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("100m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			}},
+		},
+	}
+}
+
+// runProbe runs a probe pod to completion and returns the log of its container, the pod is deleted afterwards
+func (oClient *Client) runProbe(ctx context.Context, pod *corev1.Pod) (string, error) {
+	pods := oClient.clientset(ctx).CoreV1().Pods(pod.Namespace)
+	container := pod.Spec.Containers[0].Name
+	workingOn(ctx, "creating the probe pod %s/%s", pod.Namespace, pod.Name)
+	if _, err := pods.Create(pod); err != nil {
+		err = errors.Wrapf(err, "unable to create pod %s/%s", pod.Namespace, pod.Name)
+		logrus.Error(err)
+		return "", err
+	}
+	defer func() {
+		grace := int64(0)
+		if err := pods.Delete(pod.Name, &metav1.DeleteOptions{GracePeriodSeconds: &grace}); err != nil {
+			logrus.Warnf("Unable to delete the probe pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+	}()
+	if err := oClient.waitForProbe(ctx, pod, container); err != nil {
+		return "", err
+	}
+	log, err := pods.GetLogs(pod.Name, &corev1.PodLogOptions{Container: container}).Context(ctx).Do().Raw()
+	if err != nil {
+		err = errors.Wrapf(err, "unable to read the log of pod %s/%s", pod.Namespace, pod.Name)
+		logrus.Error(err)
+		return "", err
+	}
+	return string(log), nil
+}
+
+// waitForProbe waits for the container of a probe to exit, the pod itself keeps running when a sidecar was injected
+func (oClient *Client) waitForProbe(ctx context.Context, pod *corev1.Pod, container string) error {
+	deadline := time.Now().Add(probePodDeadline)
+	for {
+		current, err := oClient.clientset(ctx).CoreV1().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{})
+		if err != nil {
+			err = errors.Wrapf(err, "unable to get pod %s/%s", pod.Namespace, pod.Name)
+			logrus.Error(err)
+			return err
+		}
+		for _, status := range current.Status.ContainerStatuses {
+			if status.Name == container && status.State.Terminated != nil {
+				progressed(ctx)
+				return nil
+			}
+		}
+		if current.Status.Phase == corev1.PodFailed {
+			return fmt.Errorf("error: pod %s/%s failed: %s", pod.Namespace, pod.Name, current.Status.Message)
+		}
+		if time.Now().After(deadline) {
+			return oClient.withDiagnosis(ctx, fmt.Errorf("error: timed out waiting for pod %s/%s", pod.Namespace, pod.Name),
+				pod.Namespace, nil)
+		}
+		workingOn(ctx, "the probe pod %s/%s", pod.Namespace, pod.Name)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	rolloutTimeout      = 5 * time.Minute
+	rolloutPollInterval = 2 * time.Second
+	// progress events are sent for every tenth of the work done
+	progressSteps = 10
+)
+
+type contextKey int
+
+const operationIDKey contextKey = iota
+
+func withOperationID(ctx context.Context, operationID string) context.Context {
+	return context.WithValue(ctx, operationIDKey, operationID)
+}
+
+func operationIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(operationIDKey).(string)
+	return id
+}
+
+// progressReporter sends events while the documents of a manifest are applied
+type progressReporter struct {
+	oClient  *Client
+	opID     string
+	verb     string
+	total    int
+	done     int
+	lastStep int
+}
+
+func (oClient *Client) newProgressReporter(ctx context.Context, total int, delete bool) *progressReporter {
+	verb := "applied"
+	if delete {
+		verb = "deleted"
+	}
+	return &progressReporter{
+		oClient: oClient,
+		opID:    operationIDFrom(ctx),
+		verb:    verb,
+		total:   total,
+	}
+}
+
+func (p *progressReporter) step() {
+	p.done++
+	if p.total < progressSteps || p.oClient.eventChan == nil {
+		return
+	}
+	step := p.done * progressSteps / p.total
+	if step == p.lastStep && p.done != p.total {
+		return
+	}
+	p.lastStep = step
+	p.oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: p.opID,
+		Severity:    meshes.Severity_SEVERITY_DEBUG,
+		Summary:     fmt.Sprintf("%d/%d resources %s", p.done, p.total, p.verb),
+		Details:     fmt.Sprintf("%d%% of the resources are %s.", p.done*100/p.total, p.verb),
+	}
+}
+
+// deploymentNames returns the names of the Deployments in a manifest
+func deploymentNames(manifest string) ([]string, error) {
+	objects, err := parseManifestObjects(manifest)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, obj := range objects {
+		if obj.GetKind() == "Deployment" {
+			names = append(names, obj.GetName())
+		}
+	}
+	return names, nil
+}
+
+// waitForDeployments blocks until the named deployments are rolled out, reporting the ones still pending
+func (oClient *Client) waitForDeployments(ctx context.Context, namespace string, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	if oClient.simulated {
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: operationIDFrom(ctx),
+			Severity:    meshes.Severity_SEVERITY_DEBUG,
+			Summary:     fmt.Sprintf("Not waiting on %d deployments in namespace %s, workloads are simulated", len(names), namespace),
+			Details:     strings.Join(names, ", "),
+		}
+		return nil
+	}
+	deadline := time.Now().Add(rolloutTimeout)
+	pause := &capacityPause{}
+	lastPending := ""
+	for {
+		pending := []string{}
+		for _, name := range names {
+			depl, err := oClient.clientset(ctx).AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+			if err != nil {
+				err = errors.Wrapf(err, "unable to get deployment %s/%s", namespace, name)
+				logrus.Error(err)
+				return err
+			}
+			replicas := int32(1)
+			if depl.Spec.Replicas != nil {
+				replicas = *depl.Spec.Replicas
+			}
+			if depl.Status.ObservedGeneration < depl.Generation ||
+				depl.Status.UpdatedReplicas < replicas || depl.Status.AvailableReplicas < replicas {
+				pending = append(pending, name)
+			}
+		}
+		if len(pending) == 0 {
+			progressed(ctx)
+			return nil
+		}
+		sort.Strings(pending)
+		workingOn(ctx, "the rollout of deployments %s in namespace %s", strings.Join(pending, ", "), namespace)
+		if current := strings.Join(pending, ", "); current != lastPending {
+			if lastPending != "" {
+				progressed(ctx)
+			}
+			lastPending = current
+			oClient.eventChan <- &meshes.EventsResponse{
+				OperationId: operationIDFrom(ctx),
+				Severity:    meshes.Severity_SEVERITY_DEBUG,
+				Summary:     fmt.Sprintf("Waiting on %d/%d deployments in namespace %s", len(pending), len(names), namespace),
+				Details:     current,
+			}
+		}
+		paused, err := oClient.paused(ctx, namespace, pause)
+		if err != nil {
+			return oClient.withDiagnosis(ctx, err, namespace, pending)
+		}
+		if paused {
+			// the rollout doesn't time out while it waits for capacity, the pause has its own bound
+			deadline = time.Now().Add(rolloutTimeout)
+		}
+		if time.Now().After(deadline) {
+			err := fmt.Errorf("error: timed out waiting for deployments %s in namespace %s", lastPending, namespace)
+			return oClient.withDiagnosis(ctx, err, namespace, pending)
+		}
+		select {
+		case <-ctx.Done():
+			return oClient.withDiagnosis(ctx, ctx.Err(), namespace, pending)
+		case <-time.After(rolloutPollInterval):
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// protectionAnnotation lists the runtime protection features enabled in a namespace
+const protectionAnnotation = "octarine.io/runtime-protection"
+
+// protectionFeatures maps the runtime protection features to the first Octarine release shipping them
+var protectionFeatures = map[string]string{
+	"file_integrity":       "1.6",
+	"process_allowlist":    "1.7",
+	"east_west_encryption": "1.8",
+}
+
+// compareVersions compares dotted release numbers, ignoring a v prefix and pre-release suffixes
+func compareVersions(a, b string) int {
+	as := strings.Split(normalizeVersion(a), ".")
+	bs := strings.Split(normalizeVersion(b), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := 0, 0
+		if i < len(as) {
+			x = leadingNumber(as[i])
+		}
+		if i < len(bs) {
+			y = leadingNumber(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// isRelease tells whether a version is a release number rather than a tag like latest
+func isRelease(version string) bool {
+	v := normalizeVersion(version)
+	return v != "" && v[0] >= '0' && v[0] <= '9'
+}
+
+func validateProtectionFeatures(features []string) error {
+	known := strings.Join(protectionFeatureNames(), ", ")
+	if len(features) == 0 {
+		return fmt.Errorf("error: no runtime protection features given, known features are %s", known)
+	}
+	for _, f := range features {
+		if _, ok := protectionFeatures[f]; !ok {
+			return fmt.Errorf("error: unknown runtime protection feature %q, known features are %s", f, known)
+		}
+	}
+	return nil
+}
+
+// checkProtectionFeatures fails for features the installed release doesn't ship
+func (oClient *Client) checkProtectionFeatures(ctx context.Context, d *deployment, features []string) error {
+	installed, _, err := oClient.dataplaneImages(ctx, d)
+	if err != nil {
+		return err
+	}
+	if !isRelease(installed) {
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: operationIDFrom(ctx),
+			EventType:   meshes.EventType_WARN,
+			Summary:     "Unable to check which runtime protection features are available",
+			Details:     fmt.Sprintf("Deployment %s runs Octarine %q, which is not a release number.", d.name, installed),
+		}
+		return nil
+	}
+	for _, f := range features {
+		if min := protectionFeatures[f]; compareVersions(installed, min) < 0 {
+			return fmt.Errorf("error: %s requires Octarine %s or later, deployment %s runs %s", f, min, d.name, installed)
+		}
+	}
+	return nil
+}
+
+func protectionFeatureNames() []string {
+	names := make([]string, 0, len(protectionFeatures))
+	for f := range protectionFeatures {
+		names = append(names, f)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// executeRuntimeProtection enables the features of the custom body in the namespace of the operation,
+// or disables them when deleting
+func (oClient *Client) executeRuntimeProtection(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	namespace := arReq.GetNamespace()
+	if namespace == "" {
+		return errors.New("error: runtime protection is set per namespace, a namespace is required")
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return err
+	}
+	if err := oClient.requireInjected(ctx, d, namespace); err != nil {
+		return err
+	}
+	if err := validateProtectionFeatures(params.Features); err != nil {
+		return err
+	}
+	// disabling is always allowed so features can be turned off after a downgrade
+	if !arReq.GetDeleteOp() {
+		if err := oClient.checkProtectionFeatures(ctx, d, params.Features); err != nil {
+			return err
+		}
+	}
+
+	action := "enable"
+	if arReq.GetDeleteOp() {
+		action = "disable"
+	}
+	err = oClient.asAccount(ctx, d, func() error {
+		for _, f := range params.Features {
+			workingOn(ctx, "%s %s in namespace %s", action, f, namespace)
+			cmd := exec.CommandContext(ctx, "octactl", "domain", "protection", d.domain, f, action, "--k8s-namespace", namespace)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				logrus.Errorf("Command finished with error: %v: %s", err, out)
+				return errors.Wrapf(err, "unable to %s %s in namespace %s", action, f, namespace)
+			}
+			progressed(ctx)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return oClient.recordProtectionFeatures(ctx, namespace, params.Features, !arReq.GetDeleteOp())
+}
+
+// recordProtectionFeatures keeps the features enabled in a namespace in its annotation
+func (oClient *Client) recordProtectionFeatures(ctx context.Context, namespace string, features []string, enabled bool) error {
+	ns, err := oClient.clientset(ctx).CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+	if err != nil {
+		err = errors.Wrapf(err, "unable to get namespace %s", namespace)
+		logrus.Error(err)
+		return err
+	}
+	current := map[string]bool{}
+	for _, f := range strings.Split(ns.GetAnnotations()[protectionAnnotation], ",") {
+		if f != "" {
+			current[f] = true
+		}
+	}
+	for _, f := range features {
+		if enabled {
+			current[f] = true
+		} else {
+			delete(current, f)
+		}
+	}
+	var value interface{}
+	if len(current) > 0 {
+		value = strings.Join(sortedKeys(current), ",")
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]interface{}{protectionAnnotation: value}},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := oClient.clientset(ctx).CoreV1().Namespaces().Patch(namespace, types.MergePatchType, patch); err != nil {
+		err = errors.Wrapf(err, "unable to record the runtime protection of namespace %s", namespace)
+		logrus.Error(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// operationAnnotationsEnv set to true annotates the objects the adapter creates and updates with the
+	// Meshery operation that last changed them
+	operationAnnotationsEnv = "OCTARINE_OPERATION_ANNOTATIONS"
+
+	operationIDAnnotation = "meshery.layer5.io/operation-id"
+	requestedByAnnotation = "meshery.layer5.io/requested-by"
+	appliedAtAnnotation   = "meshery.layer5.io/applied-at"
+)
+
+// operationAnnotations are the annotations of the operation of the context, the requesting user and the time,
+// nil unless OCTARINE_OPERATION_ANNOTATIONS is set. An empty user removes the user of an earlier operation.
+func operationAnnotations(ctx context.Context) map[string]string {
+	if os.Getenv(operationAnnotationsEnv) != "true" {
+		return nil
+	}
+	opID := operationIDFrom(ctx)
+	if opID == "" {
+		// the changes of the background watchers belong to no operation
+		return nil
+	}
+	user := ""
+	if r := resultFrom(ctx); r != nil {
+		user = r.Username
+	}
+	return map[string]string{
+		operationIDAnnotation: opID,
+		requestedByAnnotation: user,
+		appliedAtAnnotation:   time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// withOperationAnnotations is the object to create or update annotated with the operationAnnotations. The
+// object is copied, callers compare it with the live object.
+func withOperationAnnotations(ctx context.Context, obj *unstructured.Unstructured) *unstructured.Unstructured {
+	operation := operationAnnotations(ctx)
+	if operation == nil {
+		return obj
+	}
+	annotated := obj.DeepCopy()
+	annotations := annotated.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for key, value := range operation {
+		if value == "" {
+			delete(annotations, key)
+			continue
+		}
+		annotations[key] = value
+	}
+	annotated.SetAnnotations(annotations)
+	return annotated
+}
+
+// patchAnnotations are the annotations of a merge patch along with the operationAnnotations
+func patchAnnotations(ctx context.Context, annotations map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+	for key, value := range annotations {
+		patch[key] = value
+	}
+	for key, value := range operationAnnotations(ctx) {
+		if value == "" {
+			patch[key] = nil
+			continue
+		}
+		patch[key] = value
+	}
+	return patch
+}
@@ -0,0 +1,327 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// quotaKinds are the kinds whose new objects count against a quota, with the object count they add to
+var quotaKinds = map[string]struct {
+	res   schema.GroupVersionResource
+	count corev1.ResourceName
+}{
+	"Deployment":  {res: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+	"StatefulSet": {res: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}},
+	"DaemonSet":   {res: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}},
+	"ReplicaSet":  {res: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}},
+	"Job":         {res: schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}},
+	"Pod":         {res: schema.GroupVersionResource{Version: "v1", Resource: "pods"}},
+	"Service":     {res: schema.GroupVersionResource{Version: "v1", Resource: "services"}, count: corev1.ResourceServices},
+	"ConfigMap":   {res: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, count: corev1.ResourceConfigMaps},
+	"Secret":      {res: schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, count: corev1.ResourceSecrets},
+}
+
+// quotaDemand is what the new objects of a manifest add to the usage of a namespace
+type quotaDemand struct {
+	resources corev1.ResourceList
+	// unset lists the containers lacking a request or limit after the LimitRange defaults, per resource
+	unset     map[corev1.ResourceName][]string
+	overLimit []string
+}
+
+func newQuotaDemand() *quotaDemand {
+	return &quotaDemand{resources: corev1.ResourceList{}, unset: map[corev1.ResourceName][]string{}}
+}
+
+func (q *quotaDemand) add(name corev1.ResourceName, value resource.Quantity) {
+	current := q.resources[name]
+	current.Add(value)
+	q.resources[name] = current
+}
+
+// workloadReplicas is how many pods a workload runs, DaemonSets run one per node
+func workloadReplicas(data *unstructured.Unstructured, nodes int64) int64 {
+	switch data.GetKind() {
+	case "Pod":
+		return 1
+	case "DaemonSet":
+		return nodes
+	case "Job":
+		if n, found, _ := unstructured.NestedInt64(data.Object, "spec", "parallelism"); found {
+			return n
+		}
+		return 1
+	}
+	if n, found, _ := unstructured.NestedInt64(data.Object, "spec", "replicas"); found {
+		return n
+	}
+	return 1
+}
+
+// containerResources applies the defaults of the LimitRanges to a container, the way admission does
+func containerResources(c corev1.Container, limitRanges []corev1.LimitRange) corev1.ResourceRequirements {
+	res := corev1.ResourceRequirements{Requests: corev1.ResourceList{}, Limits: corev1.ResourceList{}}
+	for k, v := range c.Resources.Requests {
+		res.Requests[k] = v
+	}
+	for k, v := range c.Resources.Limits {
+		res.Limits[k] = v
+	}
+	for _, lr := range limitRanges {
+		for _, item := range lr.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			for k, v := range item.Default {
+				if _, ok := res.Limits[k]; !ok {
+					res.Limits[k] = v
+				}
+			}
+			for k, v := range item.DefaultRequest {
+				if _, ok := res.Requests[k]; !ok {
+					res.Requests[k] = v
+				}
+			}
+		}
+	}
+	// a container with a limit and no request is given a request equal to the limit
+	for k, v := range res.Limits {
+		if _, ok := res.Requests[k]; !ok {
+			res.Requests[k] = v
+		}
+	}
+	return res
+}
+
+// addPod adds the resources of the pods of a workload to the demand, checking its containers against the
+// maximums of the LimitRanges
+func (q *quotaDemand) addPod(workload string, spec corev1.PodSpec, replicas int64, limitRanges []corev1.LimitRange) {
+	requests, limits := corev1.ResourceList{}, corev1.ResourceList{}
+	resourcesOf := func(c corev1.Container) corev1.ResourceRequirements {
+		res := containerResources(c, limitRanges)
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			if _, ok := res.Requests[name]; !ok {
+				q.unset["requests."+name] = append(q.unset["requests."+name], workload+"/"+c.Name)
+			}
+			if _, ok := res.Limits[name]; !ok {
+				q.unset["limits."+name] = append(q.unset["limits."+name], workload+"/"+c.Name)
+			}
+		}
+		for _, lr := range limitRanges {
+			for _, item := range lr.Spec.Limits {
+				if item.Type != corev1.LimitTypeContainer {
+					continue
+				}
+				for k, max := range item.Max {
+					if limit, ok := res.Limits[k]; ok && limit.Cmp(max) > 0 {
+						q.overLimit = append(q.overLimit, fmt.Sprintf("container %s/%s has a %s limit of %s, LimitRange %s allows at most %s",
+							workload, c.Name, k, limit.String(), lr.Name, max.String()))
+					}
+				}
+			}
+		}
+		return res
+	}
+	for _, c := range spec.Containers {
+		res := resourcesOf(c)
+		for k, v := range res.Requests {
+			sum := requests[k]
+			sum.Add(v)
+			requests[k] = sum
+		}
+		for k, v := range res.Limits {
+			sum := limits[k]
+			sum.Add(v)
+			limits[k] = sum
+		}
+	}
+	// init containers run one at a time before the others, the pod needs the most any of them needs
+	for _, c := range spec.InitContainers {
+		res := resourcesOf(c)
+		for k, v := range res.Requests {
+			if current := requests[k]; v.Cmp(current) > 0 {
+				requests[k] = v
+			}
+		}
+		for k, v := range res.Limits {
+			if current := limits[k]; v.Cmp(current) > 0 {
+				limits[k] = v
+			}
+		}
+	}
+	for i := int64(0); i < replicas; i++ {
+		q.add(corev1.ResourcePods, *resource.NewQuantity(1, resource.DecimalSI))
+		for k, v := range requests {
+			q.add("requests."+k, v)
+		}
+		for k, v := range limits {
+			q.add("limits."+k, v)
+		}
+	}
+}
+
+// manifestDemand sums what the objects of a manifest not yet in the namespace would use
+func (oClient *Client) manifestDemand(ctx context.Context, namespace string, objects []*unstructured.Unstructured, limitRanges []corev1.LimitRange) (*quotaDemand, error) {
+	nodes, err := oClient.clientset(ctx).CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the nodes")
+	}
+	demand := newQuotaDemand()
+	for _, data := range objects {
+		kind, ok := quotaKinds[data.GetKind()]
+		if !ok {
+			continue
+		}
+		// objects already there are updated in place, their usage is counted already
+		if _, err := oClient.dynamicClient(ctx).Resource(kind.res).Namespace(namespace).Get(data.GetName(), metav1.GetOptions{}); err == nil {
+			continue
+		} else if !apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "unable to get %s %s/%s", data.GetKind(), namespace, data.GetName())
+		}
+		if kind.count != "" {
+			demand.add(kind.count, *resource.NewQuantity(1, resource.DecimalSI))
+			continue
+		}
+		podSpec, found, _ := unstructured.NestedMap(data.Object, podSpecPath(data.GetKind())...)
+		if !found {
+			continue
+		}
+		spec := corev1.PodSpec{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(podSpec, &spec); err != nil {
+			return nil, errors.Wrapf(err, "unable to read the pod spec of %s %s", data.GetKind(), data.GetName())
+		}
+		workload := strings.ToLower(data.GetKind()) + "/" + data.GetName()
+		demand.addPod(workload, spec, workloadReplicas(data, int64(len(nodes.Items))), limitRanges)
+	}
+	return demand, nil
+}
+
+// quotaKey maps the names a quota can use for compute resources to the ones the demand is kept under
+func quotaKey(name corev1.ResourceName) corev1.ResourceName {
+	switch name {
+	case corev1.ResourceCPU, corev1.ResourceMemory:
+		return "requests." + name
+	}
+	return name
+}
+
+// quotaShortfalls compares the demand with what the quotas of the namespace have left
+func quotaShortfalls(demand *quotaDemand, quotas []corev1.ResourceQuota) []string {
+	shortfalls := append([]string{}, demand.overLimit...)
+	for _, quota := range quotas {
+		// scoped quotas only apply to some pods, which the adapter can't tell apart
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			continue
+		}
+		names := make([]string, 0, len(quota.Status.Hard))
+		for name := range quota.Status.Hard {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			name := corev1.ResourceName(n)
+			key := quotaKey(name)
+			if containers := demand.unset[key]; len(containers) > 0 {
+				shortfalls = append(shortfalls, fmt.Sprintf("quota %s limits %s but containers %s set none and no LimitRange provides a default",
+					quota.Name, name, strings.Join(containers, ", ")))
+				continue
+			}
+			need, ok := demand.resources[key]
+			if !ok || need.IsZero() {
+				continue
+			}
+			hard := quota.Status.Hard[name]
+			available := hard.DeepCopy()
+			available.Sub(quota.Status.Used[name])
+			if need.Cmp(available) > 0 {
+				short := need.DeepCopy()
+				short.Sub(available)
+				shortfalls = append(shortfalls, fmt.Sprintf("quota %s: %s needs %s, %s of %s left, short by %s",
+					quota.Name, name, need.String(), available.String(), hard.String(), short.String()))
+			}
+		}
+	}
+	return shortfalls
+}
+
+// preflightQuota fails when the objects a manifest adds to a namespace exceed its ResourceQuotas or the
+// maximums of its LimitRanges, the error lists the exact shortfalls; injected sidecars aren't counted
+func (oClient *Client) preflightQuota(ctx context.Context, manifest, namespace string) error {
+	objects, err := parseManifestObjects(manifest)
+	if err != nil {
+		return err
+	}
+	byNamespace := map[string][]*unstructured.Unstructured{}
+	for _, obj := range objects {
+		ns := obj.GetNamespace()
+		if ns == "" {
+			ns = namespace
+		}
+		byNamespace[ns] = append(byNamespace[ns], obj)
+	}
+	for _, ns := range sortedNamespaces(byNamespace) {
+		quotas, err := oClient.clientset(ctx).CoreV1().ResourceQuotas(ns).List(metav1.ListOptions{})
+		if err != nil {
+			// a quota the adapter can't read will still be enforced, but that's no reason not to try
+			logrus.Warnf("Unable to list the resource quotas of namespace %s: %v", ns, err)
+			continue
+		}
+		limitRanges, err := oClient.clientset(ctx).CoreV1().LimitRanges(ns).List(metav1.ListOptions{})
+		if err != nil {
+			logrus.Warnf("Unable to list the limit ranges of namespace %s: %v", ns, err)
+		}
+		if len(quotas.Items) == 0 && (limitRanges == nil || len(limitRanges.Items) == 0) {
+			continue
+		}
+		workingOn(ctx, "checking the resource quotas of namespace %s", ns)
+		var ranges []corev1.LimitRange
+		if limitRanges != nil {
+			ranges = limitRanges.Items
+		}
+		demand, err := oClient.manifestDemand(ctx, ns, byNamespace[ns], ranges)
+		if err != nil {
+			logrus.Error(err)
+			return err
+		}
+		progressed(ctx)
+		if shortfalls := quotaShortfalls(demand, quotas.Items); len(shortfalls) > 0 {
+			return fmt.Errorf("error: the resources to create exceed the quota of namespace %s: %s", ns, strings.Join(shortfalls, "; "))
+		}
+	}
+	return nil
+}
+
+func sortedNamespaces(m map[string][]*unstructured.Unstructured) []string {
+	names := make([]string, 0, len(m))
+	for ns := range m {
+		names = append(names, ns)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,314 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	dockerHubRegistry = "registry-1.docker.io"
+	registryTimeout   = 30 * time.Second
+	// manifests are a few KiB, a bigger answer is something else
+	maxManifestSize = 4 << 20
+
+	mediaTypeManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeOCIIndex     = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIManifest  = "application/vnd.oci.image.manifest.v1+json"
+)
+
+// imageRef is a parsed image reference, tag holds the digest for references pinned by digest
+type imageRef struct {
+	registry   string
+	repository string
+	tag        string
+}
+
+func parseImageRef(image string) imageRef {
+	ref := imageRef{registry: dockerHubRegistry, tag: "latest"}
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.tag = name[i+1:]
+		name = name[:i]
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.tag = name[i+1:]
+		name = name[:i]
+	}
+	if i := strings.Index(name, "/"); i >= 0 && strings.ContainsAny(name[:i], ".:") || strings.HasPrefix(name, "localhost/") {
+		ref.registry = name[:i]
+		name = name[i+1:]
+	}
+	if ref.registry == "docker.io" || ref.registry == "index.docker.io" {
+		ref.registry = dockerHubRegistry
+	}
+	if ref.registry == dockerHubRegistry && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	ref.repository = name
+	return ref
+}
+
+// registryClient reads image manifests with the docker credentials the dataplane pulls its images with
+type registryClient struct {
+	http     *http.Client
+	username string
+	password string
+	tokens   map[string]string
+}
+
+func newRegistryClient() *registryClient {
+	return &registryClient{
+		http:     &http.Client{Timeout: registryTimeout},
+		username: os.Getenv("OCTARINE_DOCKER_USERNAME"),
+		password: os.Getenv("OCTARINE_DOCKER_PASSWORD"),
+		tokens:   map[string]string{},
+	}
+}
+
+type registryManifest struct {
+	MediaType string `json:"mediaType"`
+	Manifests []struct {
+		MediaType string `json:"mediaType"`
+		Digest    string `json:"digest"`
+		Size      int64  `json:"size"`
+		Platform  struct {
+			Architecture string `json:"architecture"`
+			OS           string `json:"os"`
+			Variant      string `json:"variant"`
+		} `json:"platform"`
+	} `json:"manifests"`
+	Config struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+}
+
+// imagePlatforms lists the platforms an image is published for
+func (rc *registryClient) imagePlatforms(ctx context.Context, image string) ([]platform, error) {
+	index, err := rc.imageIndex(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+	platforms := make([]platform, 0, len(index.platforms))
+	for _, p := range index.platforms {
+		platforms = append(platforms, p.platform)
+	}
+	return platforms, nil
+}
+
+// manifestAccept are the media types of the manifests of single and multi-platform images
+var manifestAccept = strings.Join([]string{mediaTypeManifestList, mediaTypeOCIIndex, mediaTypeManifest, mediaTypeOCIManifest}, ", ")
+
+// platformManifest is the manifest of an image for a platform, by the digest it is pulled with
+type platformManifest struct {
+	platform
+	variant string
+	digest  string
+	size    int64
+}
+
+// imageIndex is the manifest an image reference resolves to and the manifests of its platforms, the
+// manifest itself for a single platform image
+type imageIndex struct {
+	digest    string
+	mediaType string
+	platforms []platformManifest
+}
+
+// imageIndex resolves an image to the digest of its manifest and lists the digest of the manifest of every
+// platform it is published for
+func (rc *registryClient) imageIndex(ctx context.Context, image string) (*imageIndex, error) {
+	ref := parseImageRef(image)
+	body, header, err := rc.fetch(ctx, ref, "manifests/"+ref.tag, manifestAccept)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get the manifest of image %s", image)
+	}
+	manifest := &registryManifest{}
+	if err := json.Unmarshal(body, manifest); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the manifest of image %s", image)
+	}
+	index := &imageIndex{digest: header.Get("Docker-Content-Digest"), mediaType: manifest.MediaType}
+	if index.digest == "" {
+		sum := sha256.Sum256(body)
+		index.digest = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	if index.mediaType == "" {
+		index.mediaType = strings.TrimSpace(strings.Split(header.Get("Content-Type"), ";")[0])
+	}
+	if len(manifest.Manifests) > 0 {
+		for _, m := range manifest.Manifests {
+			// attestation manifests of buildkit are listed with an unknown platform
+			if m.Platform.OS == "unknown" || m.Platform.Architecture == "unknown" {
+				continue
+			}
+			index.platforms = append(index.platforms, platformManifest{
+				platform: platform{os: m.Platform.OS, arch: m.Platform.Architecture},
+				variant:  m.Platform.Variant,
+				digest:   m.Digest,
+				size:     m.Size,
+			})
+		}
+		return index, nil
+	}
+	if manifest.Config.Digest == "" {
+		return nil, fmt.Errorf("error: the manifest of image %s names neither platforms nor a config", image)
+	}
+	config := &struct {
+		Architecture string `json:"architecture"`
+		OS           string `json:"os"`
+		Variant      string `json:"variant"`
+	}{}
+	if err := rc.get(ctx, ref, "blobs/"+manifest.Config.Digest, "", config); err != nil {
+		return nil, errors.Wrapf(err, "unable to get the config of image %s", image)
+	}
+	index.platforms = append(index.platforms, platformManifest{
+		platform: platform{os: config.OS, arch: config.Architecture},
+		variant:  config.Variant,
+		digest:   index.digest,
+		size:     int64(len(body)),
+	})
+	return index, nil
+}
+
+func (rc *registryClient) get(ctx context.Context, ref imageRef, path, accept string, into interface{}) error {
+	body, _, err := rc.fetch(ctx, ref, path, accept)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, into)
+}
+
+// fetch reads a manifest or a blob of a repository, authenticating when the registry asks to
+func (rc *registryClient) fetch(ctx context.Context, ref imageRef, path, accept string) ([]byte, http.Header, error) {
+	u := fmt.Sprintf("https://%s/v2/%s/%s", ref.registry, ref.repository, path)
+	resp, err := rc.do(ctx, u, accept, rc.tokens[ref.registry+"/"+ref.repository])
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		drain(resp)
+		token, err := rc.authenticate(ctx, challenge)
+		if err != nil {
+			return nil, nil, err
+		}
+		rc.tokens[ref.registry+"/"+ref.repository] = token
+		if resp, err = rc.do(ctx, u, accept, token); err != nil {
+			return nil, nil, err
+		}
+	}
+	defer drain(resp)
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("error: registry answered %s for %s", resp.Status, u)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to read %s", u)
+	}
+	return body, resp.Header, nil
+}
+
+func (rc *registryClient) do(ctx context.Context, u, accept, authorization string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return rc.http.Do(req.WithContext(ctx))
+}
+
+// authenticate answers a Basic or Bearer challenge, returning the Authorization header to retry with
+func (rc *registryClient) authenticate(ctx context.Context, challenge string) (string, error) {
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if rc.username == "" {
+			return "", errors.New("error: the registry requires credentials and OCTARINE_DOCKER_USERNAME is not set")
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(rc.username+":"+rc.password)), nil
+	case "bearer":
+		q := url.Values{}
+		for _, key := range []string{"service", "scope"} {
+			if params[key] != "" {
+				q.Set(key, params[key])
+			}
+		}
+		req, err := http.NewRequest(http.MethodGet, params["realm"]+"?"+q.Encode(), nil)
+		if err != nil {
+			return "", err
+		}
+		if rc.username != "" {
+			req.SetBasicAuth(rc.username, rc.password)
+		}
+		resp, err := rc.http.Do(req.WithContext(ctx))
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to get a registry token")
+		}
+		defer drain(resp)
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("error: the token service answered %s", resp.Status)
+		}
+		token := &struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}{}
+		if err := json.NewDecoder(resp.Body).Decode(token); err != nil {
+			return "", errors.Wrapf(err, "unable to parse the registry token")
+		}
+		if token.Token == "" {
+			token.Token = token.AccessToken
+		}
+		return "Bearer " + token.Token, nil
+	}
+	return "", fmt.Errorf("error: unsupported registry authentication %q", challenge)
+}
+
+// parseChallenge splits a WWW-Authenticate header like: Bearer realm="...",service="..."
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := map[string]string{}
+	parts := strings.SplitN(strings.TrimSpace(challenge), " ", 2)
+	if len(parts) == 2 {
+		for _, kv := range strings.Split(parts[1], ",") {
+			if i := strings.Index(kv, "="); i > 0 {
+				params[strings.TrimSpace(kv[:i])] = strings.Trim(strings.TrimSpace(kv[i+1:]), `"`)
+			}
+		}
+	}
+	return strings.ToLower(parts[0]), params
+}
+
+func drain(resp *http.Response) {
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+}
@@ -0,0 +1,198 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// renderOperationTemplate renders the template of an operation the way ApplyOperation applies it, with the
+// secret references resolved by resolve
+func (oClient *Client) renderOperationTemplate(ctx context.Context, arReq *meshes.ApplyRuleRequest, op supportedOperation, resolve secretResolver) (string, error) {
+	if oClient.clientset(ctx) == nil {
+		return "", fmt.Errorf("error: mesh instance has not been created")
+	}
+	caps, err := oClient.clusterTemplateCapabilities(ctx)
+	if err != nil {
+		logrus.Error(err)
+		return "", err
+	}
+	version, err := oClient.templateVersion(arReq.GetCustomBody())
+	if err != nil {
+		return "", err
+	}
+	root, release := useTemplatesDir()
+	defer release()
+	set, err := selectTemplateSet(root, op.templateName, version)
+	if err != nil {
+		logrus.Error(err)
+		return "", err
+	}
+	overlay, err := parseValues(arReq.GetCustomBody())
+	if err != nil {
+		return "", err
+	}
+	values, err := templateValues(set, overlay)
+	if err != nil {
+		logrus.Error(err)
+		return "", err
+	}
+	manifest, err := renderTemplate(set, op.templateName, map[string]interface{}{
+		"user_name":    arReq.GetUsername(),
+		"namespace":    arReq.GetNamespace(),
+		"capabilities": caps,
+		"values":       values,
+	}, resolve)
+	if err != nil {
+		logrus.Error(err)
+		return "", err
+	}
+	return manifest, nil
+}
+
+// RenderOperation renders the manifest an operation would apply, or delete, without touching the cluster, to
+// review it, commit it or run it through other policy checks
+func (oClient *Client) RenderOperation(ctx context.Context, req *meshes.RenderOperationRequest) (*meshes.RenderOperationResponse, error) {
+	if name := req.GetCluster(); name != oClient.cluster {
+		target, err := oClient.clusterClient(name)
+		if err != nil {
+			return &meshes.RenderOperationResponse{Error: err.Error()}, nil
+		}
+		return target.RenderOperation(ctx, req)
+	}
+	if oClient.clientset(ctx) == nil {
+		return &meshes.RenderOperationResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	arReq := &meshes.ApplyRuleRequest{
+		OpName:     req.GetOpName(),
+		Namespace:  req.GetNamespace(),
+		Username:   req.GetUsername(),
+		CustomBody: req.GetCustomBody(),
+		DeleteOp:   req.GetDeleteOp(),
+		Parameters: req.GetParameters(),
+	}
+	arReq, _, err := withParameters(arReq)
+	if err != nil {
+		return &meshes.RenderOperationResponse{Error: err.Error()}, nil
+	}
+	manifest, err := oClient.renderOperation(ctx, arReq)
+	if err == nil {
+		manifest, err = canonicalManifest(manifest)
+	}
+	if err != nil {
+		logrus.Error(err)
+		return &meshes.RenderOperationResponse{Error: err.Error()}, nil
+	}
+	objects, err := manifestTargets(manifest, arReq.GetNamespace())
+	if err != nil {
+		return &meshes.RenderOperationResponse{Error: err.Error()}, nil
+	}
+	resp := &meshes.RenderOperationResponse{Manifest: manifest, Namespace: arReq.GetNamespace()}
+	for _, obj := range objects {
+		resp.Objects = append(resp.Objects, obj.String())
+	}
+	return resp, nil
+}
+
+// renderOperation makes the manifest of the operations applying one, the others change the cluster in code
+func (oClient *Client) renderOperation(ctx context.Context, arReq *meshes.ApplyRuleRequest) (string, error) {
+	op, ok := lookupOp(arReq.GetOpName())
+	if !ok {
+		if cause := disabledOpCause(arReq.GetOpName()); cause != nil {
+			return "", fmt.Errorf("error: operation %s is disabled, %v", arReq.GetOpName(), cause)
+		}
+		return "", fmt.Errorf("error: %s is not a valid operation name", arReq.GetOpName())
+	}
+	switch arReq.GetOpName() {
+	case admissionTestCommand:
+		if strings.TrimSpace(arReq.GetCustomBody()) == "" {
+			return admissionTestWorkloads, nil
+		}
+		return normalizeManifest(arReq.GetCustomBody())
+	case customOpCommand, customLabelDeleteOp:
+		if strings.TrimSpace(arReq.GetCustomBody()) == "" {
+			return "", fmt.Errorf("error: yaml body is empty for %s operation", arReq.GetOpName())
+		}
+		return normalizeManifest(arReq.GetCustomBody())
+	case installOctarineCommand:
+		return oClient.renderDataplane(ctx, arReq)
+	case installBookInfoCommand:
+		manifest, err := oClient.getBookInfoAppYAML()
+		if err != nil || arReq.GetDeleteOp() {
+			return manifest, err
+		}
+		return labelSampleApp(manifest, sampleAppBookInfo)
+	}
+	if op.templateName == "" {
+		return "", fmt.Errorf("error: operation %s does not apply a manifest which could be rendered", arReq.GetOpName())
+	}
+	// the rendered manifest is shown, the secrets it refers to stay in their managers
+	return oClient.renderOperationTemplate(ctx, arReq, op, redactedSecrets)
+}
+
+// renderDataplane renders the dataplane of a deployment, octactl renders it for an existing domain only: the
+// domain of an installed deployment or of a bootstrapped namespace
+func (oClient *Client) renderDataplane(ctx context.Context, arReq *meshes.ApplyRuleRequest) (string, error) {
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return "", err
+	}
+	name := params.Deployment
+	if name == "" {
+		name = defaultDeploymentName
+	}
+	namespace := arReq.GetNamespace()
+	if namespace == "" {
+		namespace = dataplaneNamespace()
+	}
+	d, err := oClient.getDeployment(name)
+	if err != nil {
+		d = &deployment{name: name, namespace: namespace, version: params.Version}
+		bootstrapped, err := oClient.useBootstrap(ctx, d)
+		if err != nil {
+			return "", err
+		}
+		if !bootstrapped {
+			return "", fmt.Errorf("error: deployment %s has no Octarine domain yet, bootstrap it with %s to render its dataplane", name, bootstrapCommand)
+		}
+	}
+	oClient.loadControlPlaneCredentials()
+	return oClient.getOctarineYAMLs(ctx, d)
+}
+
+// canonicalManifest writes every object of a manifest with its fields sorted, so rendering the same operation
+// twice gives the same text however the manifest was written
+func canonicalManifest(manifest string) (string, error) {
+	objects, err := parseManifestObjects(manifest)
+	if err != nil {
+		return "", err
+	}
+	docs := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		doc, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return "", errors.Wrapf(err, "unable to write %s %s", obj.GetKind(), obj.GetName())
+		}
+		docs = append(docs, string(doc))
+	}
+	return strings.Join(docs, "---\n"), nil
+}
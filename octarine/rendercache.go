@@ -0,0 +1,203 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// renderCacheEnv set to false renders and parses every template and manifest again
+	renderCacheEnv = "OCTARINE_RENDER_CACHE"
+
+	// maxRenderCacheEntries bounds the parsed templates, and the rendered manifests, the cache keeps
+	maxRenderCacheEntries = 64
+	// maxCachedManifestSize bounds the manifests kept with their objects
+	maxCachedManifestSize = 1 << 20
+)
+
+// renderCache keeps the parsed templates and the manifests rendered from them by their parameters, along with
+// the objects of their documents, so repeated operations such as retries and reconciliations don't parse the
+// templates and their YAML again. Templates are keyed by a fingerprint of their files, so editing one or
+// syncing a catalog misses the old entries, which age out.
+type renderCache struct {
+	mu        sync.Mutex
+	templates map[string]*template.Template
+	// templateOrder and manifestOrder are the keys, the least recently used first
+	templateOrder []string
+	manifests     map[string]*renderedManifest
+	manifestOrder []string
+	// byDigest are the manifests by the digest of their text, for parseManifestObjects
+	byDigest map[string]*renderedManifest
+}
+
+// renderedManifest is a manifest rendered from a template, and the objects of its documents by their text,
+// nil when the manifest doesn't parse
+type renderedManifest struct {
+	digest    string
+	manifest  string
+	documents map[string][]*unstructured.Unstructured
+}
+
+var renders = &renderCache{
+	templates: map[string]*template.Template{},
+	manifests: map[string]*renderedManifest{},
+	byDigest:  map[string]*renderedManifest{},
+}
+
+func renderCacheEnabled() bool {
+	return os.Getenv(renderCacheEnv) != "false"
+}
+
+// templateFingerprint identifies the files of a template by their paths, modification times and sizes
+func templateFingerprint(files []string) (string, error) {
+	h := sha256.New()
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\n%d\n%d\n", file, info.ModTime().UnixNano(), info.Size())
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// renderKey is the key of the manifest of a template rendered with parameters, false when it can't be cached
+func renderKey(fingerprint string, params map[string]interface{}) (string, bool) {
+	if fingerprint == "" {
+		return "", false
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", false
+	}
+	h := sha256.New()
+	io.WriteString(h, fingerprint+"\n")
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+func manifestDigest(manifest string) string {
+	h := sha256.New()
+	io.WriteString(h, manifest)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// touch moves a key to the end of a least recently used order, and returns the keys beyond the bound
+func touch(order []string, key string) ([]string, []string) {
+	for i, k := range order {
+		if k == key {
+			order = append(order[:i], order[i+1:]...)
+			break
+		}
+	}
+	order = append(order, key)
+	if len(order) <= maxRenderCacheEntries {
+		return order, nil
+	}
+	evicted := append([]string{}, order[:len(order)-maxRenderCacheEntries]...)
+	return order[len(order)-maxRenderCacheEntries:], evicted
+}
+
+// template is a clone of a template parsed from its files, ready to be bound and executed
+func (c *renderCache) template(fingerprint, name string, files []string) (*template.Template, error) {
+	if fingerprint == "" {
+		return newTemplate(name, redactedSecrets).ParseFiles(files...)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tmpl, ok := c.templates[fingerprint]
+	if !ok {
+		var err error
+		// the functions are bound again on each clone, secret only has to exist to parse
+		if tmpl, err = newTemplate(name, redactedSecrets).ParseFiles(files...); err != nil {
+			return nil, err
+		}
+		c.templates[fingerprint] = tmpl
+	}
+	var evicted []string
+	c.templateOrder, evicted = touch(c.templateOrder, fingerprint)
+	for _, key := range evicted {
+		delete(c.templates, key)
+	}
+	return tmpl.Clone()
+}
+
+// manifest is the manifest rendered for a key
+func (c *renderCache) manifest(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.manifests[key]
+	if !ok {
+		return "", false
+	}
+	c.manifestOrder, _ = touch(c.manifestOrder, key)
+	return r.manifest, true
+}
+
+// store keeps the manifest rendered for a key with the objects of its documents
+func (c *renderCache) store(key, manifest string) {
+	if len(manifest) > maxCachedManifestSize {
+		return
+	}
+	r := &renderedManifest{digest: manifestDigest(manifest), manifest: manifest, documents: map[string][]*unstructured.Unstructured{}}
+	dec := &documentDecoder{}
+	err := eachDocument(manifest, func(doc string) error {
+		objects, err := dec.decode(doc)
+		r.documents[doc] = objects
+		return err
+	})
+	if err != nil {
+		// the operation reports the error when it parses the manifest
+		r.documents = nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.manifests[key] = r
+	c.byDigest[r.digest] = r
+	var evicted []string
+	c.manifestOrder, evicted = touch(c.manifestOrder, key)
+	for _, key := range evicted {
+		if old, ok := c.manifests[key]; ok {
+			delete(c.manifests, key)
+			if c.byDigest[old.digest] == old {
+				delete(c.byDigest, old.digest)
+			}
+		}
+	}
+}
+
+// documents are the objects of the documents of a rendered manifest, nil for other manifests
+func (c *renderCache) documents(manifest string) map[string][]*unstructured.Unstructured {
+	if len(manifest) > maxCachedManifestSize || !renderCacheEnabled() {
+		return nil
+	}
+	digest := manifestDigest(manifest)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if r, ok := c.byDigest[digest]; ok {
+		return r.documents
+	}
+	return nil
+}
@@ -0,0 +1,354 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	resultRetentionEnv     = "OCTARINE_RESULT_RETENTION"
+	defaultResultRetention = 7 * 24 * time.Hour
+
+	// resultLabel marks the ConfigMaps holding the result of an operation, one per operation
+	resultLabel   = "octarine.io/operation-result"
+	resultDataKey = "result.json"
+
+	// a result keeps this many errors of this many bytes, ConfigMaps are limited to 1MiB
+	maxResultErrors    = 20
+	maxResultErrorSize = 4096
+
+	resultRunning   = "running"
+	resultSucceeded = "succeeded"
+	resultFailed    = "failed"
+)
+
+const resultKey contextKey = operationIDKey + 2
+
+// operationResult is what an operation ended with, kept in a ConfigMap of the dataplane namespace for
+// OCTARINE_RESULT_RETENTION so it can be looked up without having watched the event stream
+type operationResult struct {
+	OperationID string    `json:"operationId"`
+	Operation   string    `json:"operation"`
+	Namespace   string    `json:"namespace,omitempty"`
+	Username    string    `json:"username,omitempty"`
+	DeleteOp    bool      `json:"deleteOp,omitempty"`
+	Started     time.Time `json:"started"`
+	Finished    time.Time `json:"finished,omitempty"`
+	Resources   []string  `json:"resources,omitempty"`
+	// Errors are the errors of the operation, its error events and the error it was rejected with
+	Errors   []string `json:"errors,omitempty"`
+	Warnings int      `json:"warnings,omitempty"`
+	// Applied are the digests of the manifest documents the operation applied, a retry resuming it skips them
+	Applied     []string `json:"applied,omitempty"`
+	ResumedFrom string   `json:"resumedFrom,omitempty"`
+
+	// client is the client of the cluster the operation ran in, the result is kept there
+	client *Client
+	// watchdog follows the operation once it runs on after its request, it tells what the operation is doing
+	watchdog *watchdog
+}
+
+func (r *operationResult) status() string {
+	switch {
+	case r.Finished.IsZero():
+		return resultRunning
+	case len(r.Errors) > 0:
+		return resultFailed
+	}
+	return resultSucceeded
+}
+
+func (r *operationResult) addError(msg string) {
+	if len(r.Errors) == maxResultErrors {
+		return
+	}
+	if len(msg) > maxResultErrorSize {
+		msg = msg[:maxResultErrorSize] + "..."
+	}
+	r.Errors = append(r.Errors, msg)
+}
+
+// resultTracker follows the running operations through the events they emit, it belongs to the event broker
+// so the events of the registered clusters are followed too
+type resultTracker struct {
+	mu      sync.Mutex
+	running map[string]*operationResult
+	// done are the markers queued behind the last event of a finished operation
+	done map[*meshes.EventsResponse]*operationResult
+}
+
+// track records an event in the result of its operation, it tells whether the event is the marker of a
+// finished operation, which isn't published
+func (t *resultTracker) track(event *meshes.EventsResponse) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if r, ok := t.done[event]; ok {
+		delete(t.done, event)
+		if t.running[r.OperationID] == r {
+			delete(t.running, r.OperationID)
+		}
+		go r.client.saveResult(context.Background(), r)
+		return true
+	}
+	r := t.running[event.GetOperationId()]
+	if r == nil {
+		return false
+	}
+	switch event.GetEventType() {
+	case meshes.EventType_ERROR:
+		msg := event.GetSummary()
+		if details := strings.TrimSpace(event.GetDetails()); details != "" {
+			msg += ": " + details
+		}
+		r.addError(msg)
+	case meshes.EventType_WARN:
+		r.Warnings++
+	}
+	return false
+}
+
+// namespace is the namespace of a running operation
+func (t *resultTracker) namespace(opID string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if r := t.running[opID]; r != nil {
+		return r.Namespace
+	}
+	return ""
+}
+
+// started is when a running operation started, zero for the operations not running
+func (t *resultTracker) started(opID string) time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if r := t.running[opID]; r != nil {
+		return r.Started
+	}
+	return time.Time{}
+}
+
+func (t *resultTracker) lookup(opID string) *meshes.GetOperationResultResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if r := t.running[opID]; r != nil {
+		return r.response()
+	}
+	return nil
+}
+
+// startResult starts following an operation, nil when it has no id to look its result up by
+func (oClient *Client) startResult(arReq *meshes.ApplyRuleRequest) *operationResult {
+	broker := oClient.events
+	if arReq.GetOperationId() == "" || broker == nil {
+		return nil
+	}
+	r := &operationResult{
+		OperationID: arReq.GetOperationId(),
+		Operation:   arReq.GetOpName(),
+		Namespace:   arReq.GetNamespace(),
+		Username:    arReq.GetUsername(),
+		DeleteOp:    arReq.GetDeleteOp(),
+		Started:     time.Now().UTC(),
+		client:      oClient,
+	}
+	broker.results.mu.Lock()
+	broker.results.running[r.OperationID] = r
+	broker.results.mu.Unlock()
+	return r
+}
+
+// finishResult ends an operation, the result is saved once the events it emitted went through the broker
+func (oClient *Client) finishResult(r *operationResult, err error) {
+	if r == nil {
+		return
+	}
+	t := oClient.events.results
+	t.mu.Lock()
+	r.Finished = time.Now().UTC()
+	if err != nil {
+		r.addError(strings.Join(errorChain(err), "\ncaused by: "))
+	}
+	marker := &meshes.EventsResponse{OperationId: r.OperationID}
+	t.done[marker] = r
+	t.mu.Unlock()
+	oClient.eventChan <- marker
+}
+
+// errorChain lists an error followed by the messages of its causes, which the message of the error repeats,
+// without the repetition
+func errorChain(err error) []string {
+	chain := []string{}
+	msg := err.Error()
+	for {
+		causer, ok := err.(interface{ Cause() error })
+		if !ok {
+			break
+		}
+		err = causer.Cause()
+		cause := err.Error()
+		if cause == msg {
+			continue
+		}
+		if prefix := strings.TrimSuffix(msg, ": "+cause); prefix != msg {
+			chain = append(chain, prefix)
+			msg = cause
+		}
+	}
+	return append(chain, msg)
+}
+
+func withResult(ctx context.Context, r *operationResult) context.Context {
+	return context.WithValue(ctx, resultKey, r)
+}
+
+func resultFrom(ctx context.Context) *operationResult {
+	r, _ := ctx.Value(resultKey).(*operationResult)
+	return r
+}
+
+func resultName(opID string) string {
+	sum := sha256.Sum256([]byte(opID))
+	return resourceName("octarine-result-" + hex.EncodeToString(sum[:])[:16])
+}
+
+// saveResult writes the result of a finished operation and deletes the results older than the retention
+func (oClient *Client) saveResult(ctx context.Context, r *operationResult) {
+	if len(r.Resources) == 0 && !r.DeleteOp {
+		// the operations applying manifests record their objects in the inventory
+		oClient.inventoryMu.Lock()
+		inventory, err := oClient.loadInventory(ctx)
+		oClient.inventoryMu.Unlock()
+		if err == nil {
+			for _, key := range sortedInventoryKeys(inventory) {
+				if e := inventory[key]; e.OperationID == r.OperationID {
+					r.Resources = append(r.Resources, changeTarget{kind: e.Kind, namespace: e.Namespace, name: e.Name}.String())
+				}
+			}
+		}
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		logrus.Errorf("Unable to marshal the result of operation %s: %v", r.OperationID, err)
+		return
+	}
+	ns := dataplaneNamespace()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resultName(r.OperationID),
+			Namespace: ns,
+			Labels:    map[string]string{managedByLabel: managedByValue, resultLabel: "true"},
+		},
+		Data: map[string]string{resultDataKey: string(data)},
+	}
+	configMaps := oClient.clientset(ctx).CoreV1().ConfigMaps(ns)
+	_, err = configMaps.Update(cm)
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(cm)
+	}
+	if err != nil {
+		// the namespace is gone after uninstalling, results are only kept while there is a dataplane namespace
+		logrus.Warnf("Unable to save the result of operation %s in namespace %s: %v", r.OperationID, ns, err)
+		return
+	}
+	oClient.pruneResults(ctx)
+}
+
+// pruneResults deletes the results finished longer than OCTARINE_RESULT_RETENTION ago
+func (oClient *Client) pruneResults(ctx context.Context) {
+	retention := durationFromEnv(resultRetentionEnv, defaultResultRetention)
+	ns := dataplaneNamespace()
+	configMaps := oClient.clientset(ctx).CoreV1().ConfigMaps(ns)
+	list, err := configMaps.List(metav1.ListOptions{LabelSelector: resultLabel + "=true"})
+	if err != nil {
+		logrus.Warnf("Unable to list the operation results in namespace %s: %v", ns, err)
+		return
+	}
+	for _, cm := range list.Items {
+		if time.Since(cm.GetCreationTimestamp().Time) <= retention {
+			continue
+		}
+		if err := configMaps.Delete(cm.GetName(), &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			logrus.Warnf("Unable to delete the expired operation result %s/%s: %v", ns, cm.GetName(), err)
+		}
+	}
+}
+
+func (r *operationResult) response() *meshes.GetOperationResultResponse {
+	resp := &meshes.GetOperationResultResponse{
+		OperationId: r.OperationID,
+		OpName:      r.Operation,
+		Namespace:   r.Namespace,
+		Username:    r.Username,
+		DeleteOp:    r.DeleteOp,
+		Status:      r.status(),
+		Started:     r.Started.Format(time.RFC3339),
+		Resources:   append([]string{}, r.Resources...),
+		Errors:      append([]string{}, r.Errors...),
+		Warnings:    int32(r.Warnings),
+		ResumedFrom: r.ResumedFrom,
+
+		AppliedDocuments: int32(len(r.Applied)),
+	}
+	sort.Strings(resp.Resources)
+	end := time.Now()
+	if !r.Finished.IsZero() {
+		resp.Finished = r.Finished.Format(time.RFC3339)
+		end = r.Finished
+	}
+	resp.Duration = end.Sub(r.Started).Round(time.Millisecond).String()
+	return resp
+}
+
+// GetOperationResult returns how an operation ended, or how it is doing while it runs
+func (oClient *Client) GetOperationResult(ctx context.Context, req *meshes.GetOperationResultRequest) (*meshes.GetOperationResultResponse, error) {
+	if name := req.GetCluster(); name != oClient.cluster {
+		target, err := oClient.clusterClient(name)
+		if err != nil {
+			return &meshes.GetOperationResultResponse{Error: err.Error()}, nil
+		}
+		return target.GetOperationResult(ctx, req)
+	}
+	if oClient.clientset(ctx) == nil {
+		return &meshes.GetOperationResultResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	opID := req.GetOperationId()
+	if opID == "" {
+		return &meshes.GetOperationResultResponse{Error: "error: an operation id is required"}, nil
+	}
+	if oClient.events != nil {
+		if resp := oClient.events.results.lookup(opID); resp != nil {
+			return resp, nil
+		}
+	}
+	r, err := oClient.loadResult(ctx, opID)
+	if err != nil {
+		return &meshes.GetOperationResultResponse{Error: err.Error()}, nil
+	}
+	return r.response(), nil
+}
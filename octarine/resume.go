@@ -0,0 +1,150 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const resumeKey contextKey = operationIDKey + 5
+
+// maxResultDocuments bounds the digests a result keeps, a 16 byte digest each keeps them far below the size
+// of a ConfigMap
+const maxResultDocuments = 10000
+
+// resumePoint is what a retry knows of the failed operation it resumes: the digests of the manifest documents
+// that operation applied
+type resumePoint struct {
+	operationID string
+	applied     map[string]bool
+}
+
+func withResume(ctx context.Context, rp *resumePoint) context.Context {
+	return context.WithValue(ctx, resumeKey, rp)
+}
+
+func resumeFrom(ctx context.Context) *resumePoint {
+	rp, _ := ctx.Value(resumeKey).(*resumePoint)
+	return rp
+}
+
+// skips tells whether a document was applied by the resumed operation
+func (rp *resumePoint) skips(digest string) bool {
+	return rp != nil && rp.applied[digest]
+}
+
+// documentDigest identifies a manifest document applied to a namespace, a document changed since the failed
+// attempt gets a different digest and is applied again
+func documentDigest(namespace, doc string, delete bool) string {
+	// hashed as it is written, a document isn't copied
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%t\n", namespace, delete)
+	io.WriteString(h, strings.TrimSpace(doc))
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// recordApplied adds a document to the result of the operation, so a retry can skip it
+func (oClient *Client) recordApplied(ctx context.Context, digest string) {
+	r := resultFrom(ctx)
+	if r == nil || oClient.events == nil {
+		return
+	}
+	t := oClient.events.results
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(r.Applied) < maxResultDocuments {
+		r.Applied = append(r.Applied, digest)
+	}
+}
+
+// loadResult reads the saved result of a finished operation
+func (oClient *Client) loadResult(ctx context.Context, opID string) (*operationResult, error) {
+	if oClient.events != nil {
+		if resp := oClient.events.results.lookup(opID); resp != nil {
+			return nil, fmt.Errorf("error: operation %s is still running", opID)
+		}
+	}
+	ns := dataplaneNamespace()
+	cm, err := oClient.clientset(ctx).CoreV1().ConfigMaps(ns).Get(resultName(opID), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		retention := durationFromEnv(resultRetentionEnv, defaultResultRetention)
+		return nil, fmt.Errorf("error: no result of operation %s, it is unknown or finished more than %s ago", opID, retention)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get the result of operation %s", opID)
+	}
+	r := &operationResult{}
+	if err := json.Unmarshal([]byte(cm.Data[resultDataKey]), r); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the result in %s/%s", ns, cm.GetName())
+	}
+	if r.OperationID != opID {
+		return nil, fmt.Errorf("error: no result of operation %s", opID)
+	}
+	return r, nil
+}
+
+// resumeOperation checks that a request retries the failed operation it names, and returns what that
+// operation applied
+func (oClient *Client) resumeOperation(ctx context.Context, arReq *meshes.ApplyRuleRequest) (*resumePoint, error) {
+	opID := arReq.GetResumeOperationId()
+	if opID == arReq.GetOperationId() {
+		return nil, fmt.Errorf("error: operation %s can't resume itself", opID)
+	}
+	if oClient.clientset(ctx) == nil {
+		return nil, fmt.Errorf("error: mesh instance has not been created")
+	}
+	previous, err := oClient.loadResult(ctx, opID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case previous.status() != resultFailed:
+		return nil, fmt.Errorf("error: operation %s %s, only failed operations are resumed", opID, previous.status())
+	case previous.Operation != arReq.GetOpName():
+		return nil, fmt.Errorf("error: operation %s was %s, not %s", opID, previous.Operation, arReq.GetOpName())
+	case previous.Namespace != arReq.GetNamespace():
+		return nil, fmt.Errorf("error: operation %s ran in namespace %q, not %q", opID, previous.Namespace, arReq.GetNamespace())
+	case previous.DeleteOp != arReq.GetDeleteOp():
+		return nil, fmt.Errorf("error: operation %s and its retry must both apply or both delete", opID)
+	}
+	rp := &resumePoint{operationID: opID, applied: map[string]bool{}}
+	for _, digest := range previous.Applied {
+		rp.applied[digest] = true
+	}
+	return rp, nil
+}
+
+// resumedDeployment is the deployment a failed install left registered, when the install is retried
+func (oClient *Client) resumedDeployment(ctx context.Context, name, namespace string) *deployment {
+	if resumeFrom(ctx) == nil {
+		return nil
+	}
+	d, err := oClient.getDeployment(name)
+	if err != nil || d.namespace != namespace || d.account == "" {
+		return nil
+	}
+	return d
+}
@@ -0,0 +1,451 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	httpPolicyKind = "HTTPPolicy"
+	// routePolicyPrefix starts the names of the policies generated from routes, the others are left alone
+	routePolicyPrefix = "httproute-"
+)
+
+// httpPolicy is an Octarine L7 policy allowing the requests a route sends to the services of a namespace
+type httpPolicy struct {
+	Kind      string         `json:"kind"`
+	Name      string         `json:"name"`
+	Namespace string         `json:"namespace"`
+	Spec      httpPolicySpec `json:"spec"`
+}
+
+type httpPolicySpec struct {
+	Action string           `json:"action"`
+	From   []policyPeer     `json:"from"`
+	Rules  []httpPolicyRule `json:"rules"`
+}
+
+type policyPeer struct {
+	Namespace string `json:"namespace"`
+}
+
+type httpPolicyRule struct {
+	To      policyTarget `json:"to"`
+	Hosts   []string     `json:"hosts,omitempty"`
+	Path    httpMatch    `json:"path"`
+	Methods []string     `json:"methods,omitempty"`
+	Headers []httpMatch  `json:"headers,omitempty"`
+	Query   []httpMatch  `json:"query,omitempty"`
+}
+
+type policyTarget struct {
+	Service string  `json:"service"`
+	Ports   []int64 `json:"ports"`
+}
+
+// httpMatch matches a path, or a header or query parameter by its name, exactly, by prefix or by regex
+type httpMatch struct {
+	Name  string `json:"name,omitempty"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// policyRecord is a policy of a namespace as octactl lists it
+type policyRecord struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// gatewayMatchTypes map the match types of the Gateway API to those of the Octarine policies
+var gatewayMatchTypes = map[string]string{
+	"PathPrefix":        "prefix",
+	"Exact":             "exact",
+	"RegularExpression": "regex",
+}
+
+// routeCoverage is what the policies generated from a route cover and the rules they can't
+type routeCoverage struct {
+	route      string
+	rules      int
+	namespaces []string
+	skipped    []string
+}
+
+// routePolicies translates an HTTPRoute into a policy per namespace of its backends injected by the
+// deployment. The requests come from the namespaces of the Gateways the route is attached to, and are
+// matched as the backends get them, after the URL rewrites of the route.
+func routePolicies(route *unstructured.Unstructured, injected map[string]bool) ([]*httpPolicy, *routeCoverage) {
+	coverage := &routeCoverage{route: fmt.Sprintf("HTTPRoute %s/%s", route.GetNamespace(), route.GetName())}
+	from := map[string]bool{}
+	parents, _, _ := nestedObjects(route.Object, "spec", "parentRefs")
+	for _, parent := range parents {
+		if kind, found, _ := unstructured.NestedString(parent, "kind"); found && kind != "Gateway" {
+			continue
+		}
+		namespace, _, _ := unstructured.NestedString(parent, "namespace")
+		if namespace == "" {
+			namespace = route.GetNamespace()
+		}
+		from[namespace] = true
+	}
+	if len(from) == 0 {
+		coverage.skipped = append(coverage.skipped, "it isn't attached to a Gateway")
+		return nil, coverage
+	}
+	peers := []policyPeer{}
+	for _, namespace := range sortedKeys(from) {
+		peers = append(peers, policyPeer{Namespace: namespace})
+	}
+	hosts, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+
+	byNamespace := map[string]*httpPolicy{}
+	rules, _, _ := nestedObjects(route.Object, "spec", "rules")
+	for i, rule := range rules {
+		ruleHosts, rewrite := hosts, rewrittenPath(rule)
+		if hostname := rewrittenHostname(rule); hostname != "" {
+			ruleHosts = []string{hostname}
+		}
+		matches, _, _ := nestedObjects(rule, "matches")
+		if len(matches) == 0 {
+			matches = []map[string]interface{}{{}}
+		}
+		backends, _, _ := nestedObjects(rule, "backendRefs")
+		for _, backend := range backends {
+			group, _, _ := unstructured.NestedString(backend, "group")
+			kind, _, _ := unstructured.NestedString(backend, "kind")
+			name, _, _ := unstructured.NestedString(backend, "name")
+			if (group != "" && group != "core") || (kind != "" && kind != "Service") {
+				coverage.skipped = append(coverage.skipped, fmt.Sprintf("rule %d routes to %s %s, which isn't a Service", i+1, kind, name))
+				continue
+			}
+			namespace, _, _ := unstructured.NestedString(backend, "namespace")
+			if namespace == "" {
+				namespace = route.GetNamespace()
+			}
+			if !injected[namespace] {
+				coverage.skipped = append(coverage.skipped, fmt.Sprintf("rule %d routes to service %s/%s, in a namespace the deployment doesn't inject", i+1, namespace, name))
+				continue
+			}
+			port, found, _ := unstructured.NestedInt64(backend, "port")
+			if !found {
+				coverage.skipped = append(coverage.skipped, fmt.Sprintf("rule %d routes to service %s/%s without a port", i+1, namespace, name))
+				continue
+			}
+			policy := byNamespace[namespace]
+			if policy == nil {
+				policy = &httpPolicy{
+					Kind:      httpPolicyKind,
+					Name:      routePolicyName(route),
+					Namespace: namespace,
+					Spec:      httpPolicySpec{Action: "allow", From: peers},
+				}
+				byNamespace[namespace] = policy
+			}
+			for _, match := range matches {
+				r := httpPolicyRule{
+					To:    policyTarget{Service: name, Ports: []int64{port}},
+					Hosts: ruleHosts,
+					Path:  pathMatch(match, rewrite),
+				}
+				if method, _, _ := unstructured.NestedString(match, "method"); method != "" {
+					r.Methods = []string{method}
+				}
+				r.Headers = valueMatches(match, "headers")
+				r.Query = valueMatches(match, "queryParams")
+				policy.Spec.Rules = append(policy.Spec.Rules, r)
+				coverage.rules++
+			}
+		}
+	}
+	policies := make([]*httpPolicy, 0, len(byNamespace))
+	for _, namespace := range sortedPolicyNamespaces(byNamespace) {
+		policies = append(policies, byNamespace[namespace])
+		coverage.namespaces = append(coverage.namespaces, namespace)
+	}
+	return policies, coverage
+}
+
+func sortedPolicyNamespaces(policies map[string]*httpPolicy) []string {
+	namespaces := make([]string, 0, len(policies))
+	for namespace := range policies {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// routePolicyName keeps the policies of routes of different namespaces with the same backends apart,
+// namespaces have no dots so the prefix up to the first one tells the namespace of the route
+func routePolicyName(route *unstructured.Unstructured) string {
+	return routePolicyPrefix + route.GetNamespace() + "." + route.GetName()
+}
+
+// routeNamespaceOf is the namespace of the route a policy was generated from
+func routeNamespaceOf(policy string) string {
+	return strings.SplitN(strings.TrimPrefix(policy, routePolicyPrefix), ".", 2)[0]
+}
+
+// rewrittenPath is the URLRewrite filter of a rule replacing the path, nil without one
+func rewrittenPath(rule map[string]interface{}) map[string]interface{} {
+	filters, _, _ := nestedObjects(rule, "filters")
+	for _, filter := range filters {
+		if path, found, _ := unstructured.NestedMap(filter, "urlRewrite", "path"); found {
+			return path
+		}
+	}
+	return nil
+}
+
+// rewrittenHostname is the host the URLRewrite filter of a rule sends the requests to, empty without one
+func rewrittenHostname(rule map[string]interface{}) string {
+	filters, _, _ := nestedObjects(rule, "filters")
+	for _, filter := range filters {
+		if hostname, _, _ := unstructured.NestedString(filter, "urlRewrite", "hostname"); hostname != "" {
+			return hostname
+		}
+	}
+	return ""
+}
+
+// pathMatch is the path of a match as the backend gets it, PathPrefix / when the match has none
+func pathMatch(match, rewrite map[string]interface{}) httpMatch {
+	m := httpMatch{Type: "prefix", Value: "/"}
+	if t, _, _ := unstructured.NestedString(match, "path", "type"); gatewayMatchTypes[t] != "" {
+		m.Type = gatewayMatchTypes[t]
+	}
+	if value, _, _ := unstructured.NestedString(match, "path", "value"); value != "" {
+		m.Value = value
+	}
+	if rewrite == nil {
+		return m
+	}
+	switch t, _, _ := unstructured.NestedString(rewrite, "type"); t {
+	case "ReplaceFullPath":
+		full, _, _ := unstructured.NestedString(rewrite, "replaceFullPath")
+		return httpMatch{Type: "exact", Value: full}
+	case "ReplacePrefixMatch":
+		prefix, _, _ := unstructured.NestedString(rewrite, "replacePrefixMatch")
+		if prefix == "" {
+			prefix = "/"
+		}
+		return httpMatch{Type: "prefix", Value: prefix}
+	}
+	return m
+}
+
+// valueMatches are the header or query parameter matches of a match, Exact by default
+func valueMatches(match map[string]interface{}, field string) []httpMatch {
+	items, _, _ := nestedObjects(match, field)
+	result := []httpMatch{}
+	for _, item := range items {
+		m := httpMatch{Type: "exact"}
+		m.Name, _, _ = unstructured.NestedString(item, "name")
+		m.Value, _, _ = unstructured.NestedString(item, "value")
+		if t, _, _ := unstructured.NestedString(item, "type"); gatewayMatchTypes[t] != "" {
+			m.Type = gatewayMatchTypes[t]
+		}
+		result = append(result, m)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// listRoutePolicies lists the names of the policies generated from routes in a namespace of the domain
+func (oClient *Client) listRoutePolicies(ctx context.Context, d *deployment, namespace string) ([]string, error) {
+	var out []byte
+	err := oClient.asAccount(ctx, d, func() error {
+		var err error
+		out, err = exec.CommandContext(ctx, "octactl", "policy", "list", d.domain, "--k8s-namespace", namespace, "--output", "json").Output()
+		return err
+	})
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			logrus.Errorf("Command finished with error: %v: %s", err, exitErr.Stderr)
+		}
+		return nil, errors.Wrapf(err, "unable to list the policies of namespace %s", namespace)
+	}
+	records := []policyRecord{}
+	if err := json.Unmarshal(out, &records); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the policies of namespace %s", namespace)
+	}
+	names := []string{}
+	for _, r := range records {
+		if r.Kind == httpPolicyKind && strings.HasPrefix(r.Name, routePolicyPrefix) {
+			names = append(names, r.Name)
+		}
+	}
+	return names, nil
+}
+
+func (oClient *Client) applyRoutePolicies(ctx context.Context, d *deployment, namespace string, policies []*httpPolicy) error {
+	docs := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		out, err := yaml.Marshal(policy)
+		if err != nil {
+			return errors.Wrapf(err, "unable to write policy %s", policy.Name)
+		}
+		docs = append(docs, string(out))
+	}
+	return oClient.asAccount(ctx, d, func() error {
+		cmd := exec.CommandContext(ctx, "octactl", "policy", "apply", d.domain, "--k8s-namespace", namespace, "-f", "-")
+		cmd.Stdin = strings.NewReader(strings.Join(docs, "---\n"))
+		if out, err := cmd.CombinedOutput(); err != nil {
+			logrus.Errorf("Command finished with error: %v: %s", err, out)
+			return errors.Wrapf(err, "unable to apply the route policies of namespace %s", namespace)
+		}
+		return nil
+	})
+}
+
+// executeRoutePolicies generates the Octarine L7 policies of the HTTPRoutes of the namespaces a deployment
+// injects, or of the namespace of the operation, and removes those of the routes which are gone. Deleting
+// removes every policy generated from routes.
+func (oClient *Client) executeRoutePolicies(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	if oClient.clientset(ctx) == nil || oClient.dynamicClient(ctx) == nil {
+		return fmt.Errorf("error: mesh client has not been created")
+	}
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return err
+	}
+	injected, err := oClient.injectedNamespaces(ctx, d.name)
+	if err != nil {
+		return err
+	}
+	namespaces := sortedKeys(injected)
+	if arReq.GetNamespace() != "" {
+		if err := oClient.requireInjected(ctx, d, arReq.GetNamespace()); err != nil {
+			return err
+		}
+		namespaces = []string{arReq.GetNamespace()}
+	}
+	generated := map[string][]*httpPolicy{}
+	coverages := []*routeCoverage{}
+	if !arReq.GetDeleteOp() {
+		version, err := oClient.gatewayVersion(ctx)
+		if err != nil {
+			return err
+		}
+		routes := schema.GroupVersionResource{Group: gatewayGroup, Version: version, Resource: "httproutes"}
+		for _, namespace := range namespaces {
+			workingOn(ctx, "the HTTPRoutes of namespace %s", namespace)
+			list, err := oClient.dynamicClient(ctx).Resource(routes).Namespace(namespace).List(metav1.ListOptions{})
+			if err != nil {
+				return errors.Wrapf(err, "unable to list the HTTPRoutes of namespace %s", namespace)
+			}
+			for i := range list.Items {
+				policies, coverage := routePolicies(&list.Items[i], injected)
+				coverages = append(coverages, coverage)
+				for _, policy := range policies {
+					generated[policy.Namespace] = append(generated[policy.Namespace], policy)
+				}
+			}
+			progressed(ctx)
+		}
+	}
+
+	// the policies of a route land in the namespaces of its backends, which may not be the one of the route,
+	// so the policies of the routes covered are looked for in every injected namespace
+	covered := map[string]bool{}
+	for _, namespace := range namespaces {
+		covered[namespace] = true
+	}
+	removed := 0
+	for _, namespace := range sortedKeys(injected) {
+		workingOn(ctx, "the route policies of namespace %s", namespace)
+		if policies := generated[namespace]; len(policies) > 0 {
+			if err := oClient.applyRoutePolicies(ctx, d, namespace, policies); err != nil {
+				return err
+			}
+		}
+		keep := map[string]bool{}
+		for _, policy := range generated[namespace] {
+			keep[policy.Name] = true
+		}
+		existing, err := oClient.listRoutePolicies(ctx, d, namespace)
+		if err != nil {
+			return err
+		}
+		for _, name := range existing {
+			if keep[name] || !covered[routeNamespaceOf(name)] {
+				continue
+			}
+			if err := oClient.deletePolicy(ctx, d, namespace, name); err != nil {
+				return err
+			}
+			removed++
+		}
+		progressed(ctx)
+	}
+
+	if arReq.GetDeleteOp() {
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: arReq.GetOperationId(),
+			EventType:   meshes.EventType_INFO,
+			Summary:     fmt.Sprintf("Removed %d route policies of deployment %s", removed, d.name),
+		}
+		return nil
+	}
+	lines := []string{}
+	skipped := 0
+	count := 0
+	for _, c := range coverages {
+		line := fmt.Sprintf("%s: %d rule(s)", c.route, c.rules)
+		if len(c.namespaces) > 0 {
+			line += " in namespace(s) " + strings.Join(c.namespaces, ", ")
+			count += len(c.namespaces)
+		}
+		if len(c.skipped) > 0 {
+			line += ", not covered: " + strings.Join(c.skipped, "; ")
+			skipped++
+		}
+		lines = append(lines, line)
+	}
+	if removed > 0 {
+		lines = append(lines, fmt.Sprintf("Removed %d policies of routes which are gone.", removed))
+	}
+	event := &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Generated %d route policies for %d HTTPRoute(s) of deployment %s", count, len(coverages), d.name),
+		Details:     strings.Join(lines, "\n"),
+	}
+	if skipped > 0 {
+		event.EventType = meshes.EventType_WARN
+		event.Summary = fmt.Sprintf("%d of %d HTTPRoute(s) of deployment %s aren't fully covered by route policies", skipped, len(coverages), d.name)
+	}
+	oClient.eventChan <- event
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// sampleAppLabel marks what the adapter creates for demos and tests, its value names the sample
+	sampleAppLabel = "meshery.layer5.io/sample-app"
+	sampleProbe    = "probe"
+)
+
+// sampleResources are the kinds samples are made of, workloads first so their pods go before the rest
+var sampleResources = []schema.GroupVersionResource{
+	{Group: "apps", Version: "v1", Resource: "deployments"},
+	{Group: "apps", Version: "v1", Resource: "statefulsets"},
+	{Version: "v1", Resource: "pods"},
+	{Version: "v1", Resource: "services"},
+	{Version: "v1", Resource: "serviceaccounts"},
+	{Version: "v1", Resource: "configmaps"},
+}
+
+func sampleLabels(app string) map[string]string {
+	return map[string]string{managedByLabel: managedByValue, sampleAppLabel: app}
+}
+
+// labelSampleApp marks every resource of a sample app manifest so it can be found without knowing its namespace
+func labelSampleApp(manifest, app string) (string, error) {
+	return transformManifest(manifest, func(data *unstructured.Unstructured) error {
+		objLabels := data.GetLabels()
+		if objLabels == nil {
+			objLabels = map[string]string{}
+		}
+		for k, v := range sampleLabels(app) {
+			objLabels[k] = v
+		}
+		data.SetLabels(objLabels)
+		return nil
+	})
+}
+
+// findSamples lists the sample resources the adapter created in a namespace, or in all of them
+func (oClient *Client) findSamples(ctx context.Context, namespace string) ([]matchedResource, error) {
+	selector := fmt.Sprintf("%s=%s,%s", managedByLabel, managedByValue, sampleAppLabel)
+	matched := []matchedResource{}
+	for _, res := range sampleResources {
+		list, err := oClient.dynamicClient(ctx).Resource(res).Namespace(namespace).List(metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			err = errors.Wrapf(err, "unable to list the sample %s", res.Resource)
+			logrus.Error(err)
+			return nil, err
+		}
+		for i := range list.Items {
+			matched = append(matched, matchedResource{res: res, data: &list.Items[i]})
+		}
+	}
+	return matched, nil
+}
+
+// executeCleanupSamples removes the sample apps and probe pods left behind, in the namespace of the operation
+// or across the cluster when it names none
+func (oClient *Client) executeCleanupSamples(ctx context.Context, arReq *meshes.ApplyRuleRequest) (string, error) {
+	if oClient.dynamicClient(ctx) == nil {
+		return "", errors.New("mesh client has not been created")
+	}
+	workingOn(ctx, "looking for sample resources")
+	matched, err := oClient.findSamples(ctx, arReq.GetNamespace())
+	if err != nil {
+		return "", err
+	}
+	progressed(ctx)
+	if len(matched) == 0 {
+		return "No sample resources were found.", nil
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Deleting %d sample resource(s)", len(matched)),
+		Details:     describeMatches(matched),
+	}
+	namespaces := map[string]bool{}
+	for _, m := range matched {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if err := oClient.deleteResource(ctx, m.res, m.data); err != nil && !apierrors.IsNotFound(errors.Cause(err)) {
+			return "", err
+		}
+		namespaces[m.data.GetNamespace()] = true
+		progressed(ctx)
+	}
+	names := make([]string, 0, len(namespaces))
+	for ns := range namespaces {
+		names = append(names, ns)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("Removed %d sample resource(s) from namespaces %v.", len(matched), names), nil
+}
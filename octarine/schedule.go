@@ -0,0 +1,340 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	schedulesName    = "octarine-schedules"
+	schedulesDataKey = "schedules.json"
+
+	// cron has a resolution of a minute, checking twice a minute runs every schedule within 30s of its time
+	schedulerInterval = 30 * time.Second
+)
+
+// schedule is an operation run whenever its cron expression matches, the schedules are kept in a ConfigMap
+// of the dataplane namespace so they survive restarts of the adapter
+type schedule struct {
+	Name            string                   `json:"name"`
+	Cron            string                   `json:"cron"`
+	Operation       *meshes.ApplyRuleRequest `json:"operation"`
+	LastRun         string                   `json:"lastRun,omitempty"`
+	LastOperationID string                   `json:"lastOperationId,omitempty"`
+	LastError       string                   `json:"lastError,omitempty"`
+
+	cron *cronSchedule
+	next time.Time
+}
+
+func newSchedule(name, expr string, op *meshes.ApplyRuleRequest, now time.Time) (*schedule, error) {
+	c, err := parseCron(expr)
+	if err != nil {
+		return nil, err
+	}
+	s := &schedule{Name: name, Cron: expr, Operation: op, cron: c, next: c.next(now)}
+	if s.next.IsZero() {
+		return nil, fmt.Errorf("error: cron expression %q never matches", expr)
+	}
+	return s, nil
+}
+
+// newOperationID returns a random version 4 UUID, the form Meshery uses for operation ids
+func newOperationID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// loadSchedules reads the schedules of the cluster, runs missed while the adapter was down are skipped
+func (oClient *Client) loadSchedules(ctx context.Context) error {
+	ns := dataplaneNamespace()
+	cm, err := oClient.clientset(ctx).CoreV1().ConfigMaps(ns).Get(resourceName(schedulesName), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		oClient.schedulesMu.Lock()
+		oClient.schedules = map[string]*schedule{}
+		oClient.schedulesMu.Unlock()
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "unable to get the schedules in namespace %s", ns)
+	}
+	stored := []*schedule{}
+	if err := json.Unmarshal([]byte(cm.Data[schedulesDataKey]), &stored); err != nil {
+		return errors.Wrapf(err, "unable to parse the schedules in %s/%s", ns, cm.GetName())
+	}
+	now := time.Now()
+	schedules := map[string]*schedule{}
+	for _, s := range stored {
+		loaded, err := newSchedule(s.Name, s.Cron, s.Operation, now)
+		if err != nil {
+			logrus.Warnf("Skipping schedule %s: %v", s.Name, err)
+			continue
+		}
+		loaded.LastRun, loaded.LastOperationID, loaded.LastError = s.LastRun, s.LastOperationID, s.LastError
+		schedules[s.Name] = loaded
+	}
+	oClient.schedulesMu.Lock()
+	oClient.schedules = schedules
+	oClient.schedulesMu.Unlock()
+	logrus.Infof("Loaded %d schedules from %s/%s", len(schedules), ns, cm.GetName())
+	return nil
+}
+
+// saveSchedules writes the schedules to the cluster, the caller holds schedulesMu
+func (oClient *Client) saveSchedules(ctx context.Context) error {
+	stored := make([]*schedule, 0, len(oClient.schedules))
+	for _, s := range oClient.schedules {
+		stored = append(stored, s)
+	}
+	sort.Slice(stored, func(i, j int) bool { return stored[i].Name < stored[j].Name })
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	ns := dataplaneNamespace()
+	labels := map[string]string{managedByLabel: managedByValue}
+	_, err = oClient.clientset(ctx).CoreV1().Namespaces().Create(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: ns, Labels: labels},
+	})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		err = errors.Wrapf(err, "unable to create namespace %s", ns)
+		logrus.Error(err)
+		return err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: resourceName(schedulesName), Namespace: ns, Labels: labels},
+		Data:       map[string]string{schedulesDataKey: string(data)},
+	}
+	_, err = oClient.clientset(ctx).CoreV1().ConfigMaps(ns).Update(cm)
+	if apierrors.IsNotFound(err) {
+		_, err = oClient.clientset(ctx).CoreV1().ConfigMaps(ns).Create(cm)
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "unable to save the schedules in namespace %s", ns)
+		logrus.Error(err)
+		return err
+	}
+	return nil
+}
+
+// startScheduler loads the schedules of the cluster the adapter now talks to, the loop running them is started once
+func (oClient *Client) startScheduler(ctx context.Context) {
+	if err := oClient.loadSchedules(ctx); err != nil {
+		logrus.Error(err)
+	}
+	oClient.schedulerOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(schedulerInterval)
+			defer ticker.Stop()
+			for now := range ticker.C {
+				oClient.runDueSchedules(now)
+			}
+		}()
+	})
+}
+
+func (oClient *Client) runDueSchedules(now time.Time) {
+	oClient.schedulesMu.Lock()
+	due := []*schedule{}
+	for _, s := range oClient.schedules {
+		if !now.Before(s.next) {
+			due = append(due, s)
+			s.next = s.cron.next(now)
+		}
+	}
+	oClient.schedulesMu.Unlock()
+
+	for _, s := range due {
+		id, err := oClient.runSchedule(s)
+		oClient.schedulesMu.Lock()
+		// the schedule may have been deleted or replaced while it was starting
+		if current, ok := oClient.schedules[s.Name]; ok && current == s {
+			s.LastRun = now.UTC().Format(time.RFC3339)
+			s.LastOperationID = id
+			s.LastError = ""
+			if err != nil {
+				s.LastError = err.Error()
+			}
+			if err := oClient.saveSchedules(context.Background()); err != nil {
+				logrus.Warnf("Unable to record the run of schedule %s: %v", s.Name, err)
+			}
+		}
+		oClient.schedulesMu.Unlock()
+	}
+}
+
+// runSchedule starts the operation of a schedule under a new operation id, its events report the outcome
+func (oClient *Client) runSchedule(s *schedule) (string, error) {
+	req := *s.Operation
+	req.OperationId = newOperationID()
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: req.OperationId,
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Scheduled operation %s started", s.Name),
+		Details:     fmt.Sprintf("Running %s as scheduled by %q.", req.GetOpName(), s.Cron),
+	}
+	if _, err := oClient.ApplyOperation(context.Background(), &req); err != nil {
+		logrus.Errorf("Unable to run schedule %s: %v", s.Name, err)
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: req.OperationId,
+			EventType:   meshes.EventType_ERROR,
+			Summary:     fmt.Sprintf("Scheduled operation %s could not be started", s.Name),
+			Details:     err.Error(),
+		}
+		return req.OperationId, err
+	}
+	return req.OperationId, nil
+}
+
+func scheduleMessage(s *schedule) *meshes.Schedule {
+	m := &meshes.Schedule{
+		Name:            s.Name,
+		Cron:            s.Cron,
+		Operation:       s.Operation,
+		LastRun:         s.LastRun,
+		LastOperationId: s.LastOperationID,
+		LastError:       s.LastError,
+	}
+	if !s.next.IsZero() {
+		m.NextRun = s.next.UTC().Format(time.RFC3339)
+	}
+	return m
+}
+
+// ScheduleOperation registers an operation to run on a cron schedule, replacing a schedule of the same name
+func (oClient *Client) ScheduleOperation(ctx context.Context, req *meshes.ScheduleOperationRequest) (*meshes.ScheduleOperationResponse, error) {
+	if oClient.clientset(ctx) == nil {
+		return &meshes.ScheduleOperationResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	if req.GetName() == "" {
+		return &meshes.ScheduleOperationResponse{Error: "error: the name of the schedule is required"}, nil
+	}
+	op := req.GetOperation()
+	if op == nil || op.GetOpName() == "" {
+		return &meshes.ScheduleOperationResponse{Error: "error: the operation to schedule is required"}, nil
+	}
+	if _, ok := lookupOp(op.GetOpName()); !ok {
+		return &meshes.ScheduleOperationResponse{Error: fmt.Sprintf("error: %s is not a valid operation name", op.GetOpName())}, nil
+	}
+	// every run is an operation of its own, the ids of the operation scheduled don't carry over
+	scheduled := proto.Clone(op).(*meshes.ApplyRuleRequest)
+	scheduled.OperationId = ""
+	scheduled.ResumeOperationId = ""
+	scheduled.AppliedOperationId = ""
+	s, err := newSchedule(req.GetName(), req.GetCron(), scheduled, time.Now())
+	if err != nil {
+		return &meshes.ScheduleOperationResponse{Error: err.Error()}, nil
+	}
+
+	oClient.schedulesMu.Lock()
+	if oClient.schedules == nil {
+		oClient.schedules = map[string]*schedule{}
+	}
+	previous := oClient.schedules[s.Name]
+	oClient.schedules[s.Name] = s
+	err = oClient.saveSchedules(ctx)
+	if err != nil {
+		if previous != nil {
+			oClient.schedules[s.Name] = previous
+		} else {
+			delete(oClient.schedules, s.Name)
+		}
+	}
+	oClient.schedulesMu.Unlock()
+	recordAudit(auditEntry{
+		User:    req.GetUsername(),
+		Action:  "schedule.register",
+		Target:  s.Name,
+		Details: fmt.Sprintf("runs %s on %q", op.GetOpName(), s.Cron),
+	}, err)
+	if err != nil {
+		return &meshes.ScheduleOperationResponse{Error: err.Error()}, nil
+	}
+	return &meshes.ScheduleOperationResponse{NextRun: s.next.UTC().Format(time.RFC3339)}, nil
+}
+
+// ListSchedules returns the registered schedules ordered by name
+func (oClient *Client) ListSchedules(ctx context.Context, req *meshes.ListSchedulesRequest) (*meshes.ListSchedulesResponse, error) {
+	if oClient.clientset(ctx) == nil {
+		return &meshes.ListSchedulesResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	oClient.schedulesMu.Lock()
+	defer oClient.schedulesMu.Unlock()
+	names := []string{}
+	for name, s := range oClient.schedules {
+		if f := req.GetFilter(); f != "" && !strings.Contains(name, f) && !strings.Contains(s.Operation.GetOpName(), f) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	start, end, next, err := paginate(names, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return &meshes.ListSchedulesResponse{Error: err.Error()}, nil
+	}
+	resp := &meshes.ListSchedulesResponse{NextPageToken: next}
+	for _, name := range names[start:end] {
+		resp.Schedules = append(resp.Schedules, scheduleMessage(oClient.schedules[name]))
+	}
+	return resp, nil
+}
+
+// DeleteSchedule removes a schedule, operations it already started keep running
+func (oClient *Client) DeleteSchedule(ctx context.Context, req *meshes.DeleteScheduleRequest) (*meshes.DeleteScheduleResponse, error) {
+	if oClient.clientset(ctx) == nil {
+		return &meshes.DeleteScheduleResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	oClient.schedulesMu.Lock()
+	s, ok := oClient.schedules[req.GetName()]
+	if !ok {
+		oClient.schedulesMu.Unlock()
+		return &meshes.DeleteScheduleResponse{Error: fmt.Sprintf("error: no schedule named %q", req.GetName())}, nil
+	}
+	delete(oClient.schedules, s.Name)
+	err := oClient.saveSchedules(ctx)
+	if err != nil {
+		oClient.schedules[s.Name] = s
+	}
+	oClient.schedulesMu.Unlock()
+	recordAudit(auditEntry{
+		User:    req.GetUsername(),
+		Action:  "schedule.delete",
+		Target:  s.Name,
+		Details: fmt.Sprintf("ran %s on %q", s.Operation.GetOpName(), s.Cron),
+	}, err)
+	if err != nil {
+		return &meshes.DeleteScheduleResponse{Error: err.Error()}, nil
+	}
+	return &meshes.DeleteScheduleResponse{}, nil
+}
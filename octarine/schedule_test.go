@@ -0,0 +1,83 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"k8s.io/client-go/rest"
+)
+
+// echoAPIServer stores nothing: it answers the objects created and updated with themselves and finds none
+func echoAPIServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Write(body)
+	}))
+}
+
+func TestScheduledRunTargetsCluster(t *testing.T) {
+	server := echoAPIServer()
+	defer server.Close()
+	oClient, err := clientForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	oClient.eventChan = make(chan *meshes.EventsResponse, 10)
+
+	resp, err := oClient.ScheduleOperation(context.Background(), &meshes.ScheduleOperationRequest{
+		Name: "nightly-isolation",
+		Cron: "0 2 * * *",
+		Operation: &meshes.ApplyRuleRequest{
+			OpName:             namespaceIsolationCommand,
+			Namespace:          "default",
+			OperationId:        "scheduling",
+			AppliedOperationId: "applied",
+			ResumeOperationId:  "failed",
+			Cluster:            "east",
+			Force:              true,
+			ConflictPolicy:     "skip",
+		},
+	})
+	if err != nil || resp.GetError() != "" {
+		t.Fatalf("the schedule was not registered: %v %s", err, resp.GetError())
+	}
+	s := oClient.schedules["nightly-isolation"]
+	op := s.Operation
+	if op.GetCluster() != "east" || !op.GetForce() || op.GetConflictPolicy() != "skip" {
+		t.Errorf("the schedule runs %v, the cluster, force and conflict policy of the operation are lost", op)
+	}
+	if op.GetOperationId() != "" || op.GetAppliedOperationId() != "" || op.GetResumeOperationId() != "" {
+		t.Errorf("the schedule runs %v, with the ids of the operation scheduled", op)
+	}
+
+	// no cluster is registered as east, the run fails reaching for it rather than running on the default cluster
+	if _, err := oClient.runSchedule(s); err == nil || !strings.Contains(err.Error(), "cluster east") {
+		t.Errorf("the scheduled run returned %v, want it to target cluster east", err)
+	}
+}
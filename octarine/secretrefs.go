@@ -0,0 +1,137 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/layer5io/meshery-octarine/secrets"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const resolvedSecretsKey contextKey = operationIDKey + 4
+
+// redactedSecret stands for the value of a resolved secret in what operations report
+const redactedSecret = "<redacted>"
+
+// secretResolver is the secret function of templates, it returns the value of a reference like
+// vault://secret/data/octarine#token
+type secretResolver func(ref string) (string, error)
+
+// redactedSecrets resolves references to placeholders naming them, for manifests rendered to be reviewed or
+// linted, which must not reach the secret managers
+func redactedSecrets(ref string) (string, error) {
+	r, err := secrets.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("<secret %s>", r), nil
+}
+
+// resolvedSecrets are the values of the secrets an operation resolved while rendering its template
+type resolvedSecrets struct {
+	mu     sync.Mutex
+	values []string
+}
+
+func withResolvedSecrets(ctx context.Context, resolved *resolvedSecrets) context.Context {
+	return context.WithValue(ctx, resolvedSecretsKey, resolved)
+}
+
+func resolvedSecretsFrom(ctx context.Context) *resolvedSecrets {
+	resolved, _ := ctx.Value(resolvedSecretsKey).(*resolvedSecrets)
+	return resolved
+}
+
+// secretResolver resolves the references of a template from the secret managers, or from the Secrets of the
+// cluster for k8s references, and remembers their values in the resolved secrets of the context so they can
+// be redacted
+func (oClient *Client) secretResolver(ctx context.Context) secretResolver {
+	cache := map[string]string{}
+	return func(ref string) (string, error) {
+		if value, ok := cache[ref]; ok {
+			return value, nil
+		}
+		r, err := secrets.Parse(ref)
+		if err != nil {
+			return "", err
+		}
+		var value string
+		if r.Scheme == "k8s" {
+			value, err = oClient.kubernetesSecret(ctx, r)
+		} else {
+			value, err = secrets.Lookup(ctx, r)
+		}
+		if err != nil {
+			return "", err
+		}
+		cache[ref] = value
+		if resolved := resolvedSecretsFrom(ctx); resolved != nil && value != "" {
+			resolved.mu.Lock()
+			// the data of Secrets holds the values encoded with b64enc
+			resolved.values = append(resolved.values, value, base64.StdEncoding.EncodeToString([]byte(value)))
+			resolved.mu.Unlock()
+		}
+		return value, nil
+	}
+}
+
+// kubernetesSecret reads a key of a Secret of the cluster, referred to as k8s://namespace/name#key
+func (oClient *Client) kubernetesSecret(ctx context.Context, r secrets.Reference) (string, error) {
+	parts := strings.Split(r.Location, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("error: secret reference %s is not of the form k8s://namespace/name#key", r)
+	}
+	if oClient.clientset(ctx) == nil {
+		return "", fmt.Errorf("error: mesh instance has not been created")
+	}
+	secret, err := oClient.clientset(ctx).CoreV1().Secrets(parts[0]).Get(parts[1], metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to read secret %s", r)
+	}
+	value, ok := secret.Data[r.Key]
+	if !ok {
+		return "", fmt.Errorf("error: secret %s/%s has no key %s", parts[0], parts[1], r.Key)
+	}
+	return string(value), nil
+}
+
+// redactSecrets replaces the values of the secrets resolved by the operation of the context in a text it
+// reports, such as the manifest of a denied object or an error echoing a field
+func redactSecrets(ctx context.Context, text string) string {
+	resolved := resolvedSecretsFrom(ctx)
+	if resolved == nil {
+		return text
+	}
+	resolved.mu.Lock()
+	defer resolved.mu.Unlock()
+	for _, value := range resolved.values {
+		text = strings.Replace(text, value, redactedSecret, -1)
+	}
+	return text
+}
+
+// redactError is an error with the resolved secrets of the context redacted, the error itself when it holds none
+func redactError(ctx context.Context, err error) error {
+	if redacted := redactSecrets(ctx, err.Error()); redacted != err.Error() {
+		return errors.New(redacted)
+	}
+	return err
+}
@@ -0,0 +1,208 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type matchedResource struct {
+	res  schema.GroupVersionResource
+	data *unstructured.Unstructured
+}
+
+// parseManifestObjects splits a multi document YAML manifest into objects, unwrapping lists
+func parseManifestObjects(manifest string) ([]*unstructured.Unstructured, error) {
+	objects := []*unstructured.Unstructured{}
+	dec := newDocumentDecoder(manifest)
+	err := eachDocument(manifest, func(doc string) error {
+		docObjects, err := dec.decode(doc)
+		objects = append(objects, docObjects...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// eachDocument calls fn with the documents of a multi document YAML manifest in turn, skipping the empty ones.
+// The documents are slices of the manifest, a huge manifest isn't copied.
+func eachDocument(manifest string, fn func(doc string) error) error {
+	for {
+		i := strings.Index(manifest, "---")
+		doc := manifest
+		if i >= 0 {
+			doc = manifest[:i]
+		}
+		if strings.TrimSpace(doc) != "" {
+			if err := fn(doc); err != nil {
+				return err
+			}
+		}
+		if i < 0 {
+			return nil
+		}
+		manifest = manifest[i+len("---"):]
+	}
+}
+
+// documentDecoder converts YAML documents to objects one at a time
+type documentDecoder struct {
+	// parsed are the objects of the documents of a rendered manifest, copied instead of parsed again
+	parsed map[string][]*unstructured.Unstructured
+}
+
+// newDocumentDecoder is a decoder of the documents of a manifest, which takes the objects of a manifest
+// rendered from a template from the render cache
+func newDocumentDecoder(manifest string) *documentDecoder {
+	return &documentDecoder{parsed: renders.documents(manifest)}
+}
+
+// decode converts a YAML document to its objects, unwrapping lists. A null document has none.
+func (dec *documentDecoder) decode(doc string) ([]*unstructured.Unstructured, error) {
+	if objects, ok := dec.parsed[doc]; ok {
+		copies := make([]*unstructured.Unstructured, 0, len(objects))
+		for _, obj := range objects {
+			copies = append(copies, obj.DeepCopy())
+		}
+		return copies, nil
+	}
+	jsonBytes, err := yaml.YAMLToJSON([]byte(doc))
+	if err != nil {
+		err = errors.Wrapf(err, "unable to convert yaml to json")
+		logrus.Error(err)
+		return nil, err
+	}
+	if len(jsonBytes) <= 5 { // attempting to skip 'null' json
+		return nil, nil
+	}
+	data := &unstructured.Unstructured{}
+	if err := data.UnmarshalJSON(jsonBytes); err != nil {
+		err = errors.Wrapf(err, "unable to unmarshal json created from yaml")
+		logrus.Error(err)
+		return nil, err
+	}
+	if !data.IsList() {
+		return []*unstructured.Unstructured{data}, nil
+	}
+	objects := []*unstructured.Unstructured{}
+	err = data.EachListItem(func(r runtime.Object) error {
+		item, _ := r.(*unstructured.Unstructured)
+		objects = append(objects, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// matchResourcesByLabels lists the live resources of the same kind carrying all the labels of each object
+func (oClient *Client) matchResourcesByLabels(ctx context.Context, objects []*unstructured.Unstructured, namespace string) ([]matchedResource, error) {
+	matched := []matchedResource{}
+	seen := map[string]bool{}
+	for _, obj := range objects {
+		objLabels := obj.GetLabels()
+		if len(objLabels) == 0 {
+			return nil, fmt.Errorf("error: %s %s has no labels to select resources with", obj.GetKind(), obj.GetName())
+		}
+		ns := namespace
+		if ns == "" {
+			ns = obj.GetNamespace()
+		}
+		res := resourceFor(obj)
+		list, err := oClient.dynamicClient(ctx).Resource(res).Namespace(ns).List(metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(objLabels).String(),
+		})
+		if err != nil {
+			err = errors.Wrapf(err, "unable to list %s matching the labels of %s", res.Resource, obj.GetName())
+			logrus.Error(err)
+			return nil, err
+		}
+		for i := range list.Items {
+			item := &list.Items[i]
+			key := fmt.Sprintf("%s/%s/%s", res.String(), item.GetNamespace(), item.GetName())
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			matched = append(matched, matchedResource{res: res, data: item})
+		}
+	}
+	return matched, nil
+}
+
+func describeMatches(matched []matchedResource) string {
+	lines := make([]string, len(matched))
+	for i, m := range matched {
+		name := m.data.GetName()
+		if m.data.GetNamespace() != "" {
+			name = m.data.GetNamespace() + "/" + name
+		}
+		lines[i] = fmt.Sprintf("%s %s", m.data.GetKind(), name)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// executeSelectorDelete deletes every resource matching the labels of the manifest objects instead of the exact names
+func (oClient *Client) executeSelectorDelete(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	if oClient.dynamicClient(ctx) == nil {
+		return errors.New("mesh client has not been created")
+	}
+	manifest, err := normalizeManifest(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	objects, err := parseManifestObjects(manifest)
+	if err != nil {
+		return err
+	}
+	matched, err := oClient.matchResourcesByLabels(ctx, objects, arReq.GetNamespace())
+	if err != nil {
+		return err
+	}
+	targets := make([]changeTarget, 0, len(matched))
+	for _, m := range matched {
+		targets = append(targets, changeTarget{kind: m.data.GetKind(), namespace: m.data.GetNamespace(), name: m.data.GetName()})
+	}
+	if err := oClient.guardBulkChange(arReq, targets, true); err != nil {
+		return err
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Deleting %d resource(s) matching the labels", len(matched)),
+		Details:     describeMatches(matched),
+	}
+	for _, m := range matched {
+		if err := oClient.deleteResource(ctx, m.res, m.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
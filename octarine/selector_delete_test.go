@@ -0,0 +1,45 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"fmt"
+	"testing"
+)
+
+func BenchmarkDocumentDecoder(b *testing.B) {
+	for _, n := range []int{10, 500} {
+		manifest := bundleManifest(n)
+		b.Run(fmt.Sprintf("%d documents", n), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(manifest)))
+			for i := 0; i < b.N; i++ {
+				dec := newDocumentDecoder(manifest)
+				count := 0
+				err := eachDocument(manifest, func(doc string) error {
+					objects, err := dec.decode(doc)
+					count += len(objects)
+					return err
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+				if count != n {
+					b.Fatalf("decoded %d objects, want %d", count, n)
+				}
+			}
+		})
+	}
+}
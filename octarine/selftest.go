@@ -0,0 +1,226 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	sampleSelfTest = "self-test"
+	// selfTestMarkerKey holds a value unique to the run in the ConfigMap of the self-test, reading it back checks
+	// the object round trips
+	selfTestMarkerKey = "marker"
+
+	selfTestDeleteTimeout = time.Minute
+	selfTestPollInterval  = 2 * time.Second
+)
+
+// selfTestResources are the dummy objects of the self-test, the Deployment has no replicas so nothing is scheduled
+const selfTestResources = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: octarine-self-test
+data:
+  marker: "%s"
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: octarine-self-test
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: octarine-self-test
+spec:
+  selector:
+    app: octarine-self-test
+  ports:
+  - name: http
+    port: 80
+    targetPort: 8080
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: octarine-self-test
+spec:
+  replicas: 0
+  selector:
+    matchLabels:
+      app: octarine-self-test
+  template:
+    metadata:
+      labels:
+        app: octarine-self-test
+    spec:
+      serviceAccountName: octarine-self-test
+      containers:
+      - name: app
+        image: busybox:1.32
+        command: ["sleep", "3600"]
+`
+
+// selfTestStage is a step of the self-test, in the order they run
+type selfTestStage struct {
+	name string
+	run  func() (string, error)
+}
+
+// executeSelfTest checks the adapter end to end: it renders dummy objects the way the custom operation does,
+// applies them to a throwaway namespace, reads them back, deletes them and checks they are gone
+func (oClient *Client) executeSelfTest(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	if oClient.clientset(ctx) == nil {
+		return fmt.Errorf("error: mesh client has not been created")
+	}
+	marker := newOperationID()[:8]
+	namespace := resourceName("octarine-self-test-" + marker)
+	started := time.Now()
+
+	workingOn(ctx, "creating the namespace %s of the self-test", namespace)
+	if _, err := oClient.clientset(ctx).CoreV1().Namespaces().Create(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Labels: sampleLabels(sampleSelfTest)},
+	}); err != nil {
+		return errors.Wrapf(err, "self-test failed, unable to create namespace %s", namespace)
+	}
+	defer func() {
+		err := oClient.clientset(ctx).CoreV1().Namespaces().Delete(namespace, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			logrus.Warnf("Unable to delete the namespace %s of the self-test: %v", namespace, err)
+		}
+	}()
+	progressed(ctx)
+
+	var manifest string
+	var objects []*unstructured.Unstructured
+	stages := []selfTestStage{
+		{name: "render", run: func() (string, error) {
+			rendered, err := oClient.renderOperation(ctx, &meshes.ApplyRuleRequest{
+				OpName:     customOpCommand,
+				Namespace:  namespace,
+				CustomBody: fmt.Sprintf(selfTestResources, marker),
+			})
+			if err == nil {
+				rendered, err = labelSampleApp(rendered, sampleSelfTest)
+			}
+			if err != nil {
+				return "", err
+			}
+			if objects, err = parseManifestObjects(rendered); err != nil {
+				return "", err
+			}
+			manifest = rendered
+			return fmt.Sprintf("%d objects", len(objects)), nil
+		}},
+		{name: "apply", run: func() (string, error) {
+			if err := oClient.applyConfigChange(ctx, manifest, namespace, false); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("to namespace %s", namespace), nil
+		}},
+		{name: "verify", run: func() (string, error) {
+			for _, obj := range objects {
+				live, err := oClient.dynamicClient(ctx).Resource(resourceFor(obj)).Namespace(namespace).Get(obj.GetName(), metav1.GetOptions{})
+				if err != nil {
+					return "", errors.Wrapf(err, "unable to read %s %s back", obj.GetKind(), obj.GetName())
+				}
+				if live.GetLabels()[sampleAppLabel] != sampleSelfTest {
+					return "", fmt.Errorf("error: %s %s lost its labels", obj.GetKind(), obj.GetName())
+				}
+				if obj.GetKind() == "ConfigMap" {
+					if value, _, _ := unstructured.NestedString(live.Object, "data", selfTestMarkerKey); value != marker {
+						return "", fmt.Errorf("error: ConfigMap %s holds %q instead of %q", obj.GetName(), value, marker)
+					}
+				}
+			}
+			return fmt.Sprintf("%d objects read back", len(objects)), nil
+		}},
+		{name: "delete", run: func() (string, error) {
+			if err := oClient.applyConfigChange(ctx, manifest, namespace, true); err != nil {
+				return "", err
+			}
+			return "", oClient.awaitSelfTestDeleted(ctx, namespace, objects)
+		}},
+	}
+
+	passed := []string{}
+	for _, stage := range stages {
+		stageStarted := time.Now()
+		workingOn(ctx, "the %s stage of the self-test", stage.name)
+		outcome, err := stage.run()
+		if err != nil {
+			err = errors.Wrapf(err, "self-test failed at the %s stage", stage.name)
+			if len(passed) > 0 {
+				return fmt.Errorf("%v\n%s", err, strings.Join(passed, "\n"))
+			}
+			return err
+		}
+		progressed(ctx)
+		line := fmt.Sprintf("%s: passed in %s", stage.name, time.Since(stageStarted).Round(time.Millisecond))
+		if outcome != "" {
+			line += ", " + outcome
+		}
+		passed = append(passed, line)
+	}
+
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Self-test passed in %s", time.Since(started).Round(time.Millisecond)),
+		Details:     strings.Join(passed, "\n"),
+	}
+	return nil
+}
+
+// awaitSelfTestDeleted waits for the objects of the self-test to be gone, the deletion of some being carried
+// out by the garbage collector
+func (oClient *Client) awaitSelfTestDeleted(ctx context.Context, namespace string, objects []*unstructured.Unstructured) error {
+	deadline := time.Now().Add(selfTestDeleteTimeout)
+	for {
+		left := []string{}
+		for _, obj := range objects {
+			_, err := oClient.dynamicClient(ctx).Resource(resourceFor(obj)).Namespace(namespace).Get(obj.GetName(), metav1.GetOptions{})
+			if err == nil {
+				left = append(left, obj.GetKind()+" "+obj.GetName())
+			} else if !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "unable to check that %s %s was deleted", obj.GetKind(), obj.GetName())
+			}
+		}
+		if len(left) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("error: %s still exist %s after they were deleted", strings.Join(left, ", "), selfTestDeleteTimeout)
+		}
+		workingOn(ctx, "%d object(s) of the self-test to be deleted", len(left))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(selfTestPollInterval):
+		}
+	}
+}
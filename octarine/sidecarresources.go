@@ -0,0 +1,305 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// sidecarResourcesName is the ConfigMap of the dataplane the injector reads the resources of the sidecars
+	// from: the cluster policy under clusterResourcesKey and the policy of a namespace under <namespace>.json
+	sidecarResourcesName = "octarine-sidecar-resources"
+	clusterResourcesKey  = "cluster.json"
+
+	// the injector is the dataplane container told which sidecar image to inject, the ConfigMap is mounted
+	// into it so later changes need no restart
+	sidecarImageArg       = "--sidecar-image="
+	sidecarResourcesArg   = "--sidecar-resources-dir="
+	sidecarResourcesMount = "/etc/octarine/sidecar-resources"
+	sidecarResourcesVol   = "sidecar-resources"
+)
+
+// sidecarRequirements are the requests and limits of the parameters of an operation
+func sidecarRequirements(params *deploymentParams) corev1.ResourceRequirements {
+	req := corev1.ResourceRequirements{}
+	set := func(list *corev1.ResourceList, name corev1.ResourceName, value string) {
+		if value == "" {
+			return
+		}
+		if *list == nil {
+			*list = corev1.ResourceList{}
+		}
+		(*list)[name] = resource.MustParse(value)
+	}
+	set(&req.Requests, corev1.ResourceCPU, params.CPURequest)
+	set(&req.Requests, corev1.ResourceMemory, params.MemoryRequest)
+	set(&req.Limits, corev1.ResourceCPU, params.CPULimit)
+	set(&req.Limits, corev1.ResourceMemory, params.MemoryLimit)
+	return req
+}
+
+// validateSidecarResources checks the quantities of a sidecar resources policy, and that no request is above
+// its limit. Removing a policy takes no quantities.
+func validateSidecarResources(params *deploymentParams, deleteOp bool) error {
+	quantities := map[string]string{
+		"cpu_request":    params.CPURequest,
+		"cpu_limit":      params.CPULimit,
+		"memory_request": params.MemoryRequest,
+		"memory_limit":   params.MemoryLimit,
+	}
+	set := 0
+	for _, field := range []string{"cpu_request", "cpu_limit", "memory_request", "memory_limit"} {
+		value := quantities[field]
+		if value == "" {
+			continue
+		}
+		set++
+		q, err := resource.ParseQuantity(value)
+		if err != nil {
+			return fmt.Errorf("error: %s %q is not a quantity like 100m or 128Mi", field, value)
+		}
+		if q.Sign() <= 0 {
+			return fmt.Errorf("error: %s must be above zero, got %s", field, value)
+		}
+	}
+	if deleteOp {
+		return nil
+	}
+	if set == 0 {
+		return errors.New("error: at least one of cpu_request, cpu_limit, memory_request and memory_limit is required")
+	}
+	req := sidecarRequirements(params)
+	for name, request := range req.Requests {
+		if limit, ok := req.Limits[name]; ok && request.Cmp(limit) > 0 {
+			return fmt.Errorf("error: the %s request %s is above its limit %s", name, request.String(), limit.String())
+		}
+	}
+	return nil
+}
+
+// describeRequirements writes requests and limits as "requests cpu=100m memory=64Mi, limits memory=128Mi"
+func describeRequirements(req corev1.ResourceRequirements) string {
+	parts := []string{}
+	for _, list := range []struct {
+		name   string
+		values corev1.ResourceList
+	}{{"requests", req.Requests}, {"limits", req.Limits}} {
+		values := []string{}
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			if q, ok := list.values[name]; ok {
+				values = append(values, fmt.Sprintf("%s=%s", name, q.String()))
+			}
+		}
+		if len(values) > 0 {
+			parts = append(parts, list.name+" "+strings.Join(values, " "))
+		}
+	}
+	if len(parts) == 0 {
+		return "the injector defaults"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// executeSidecarResources sets the resources of the sidecars injected into a namespace, or into every
+// namespace of the deployment when no namespace is given; deleting removes the policy, a namespace then
+// follows the cluster policy again
+func (oClient *Client) executeSidecarResources(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	if err := validateSidecarResources(params, arReq.GetDeleteOp()); err != nil {
+		return err
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return err
+	}
+	namespace := arReq.GetNamespace()
+	key, scope := clusterResourcesKey, "deployment "+d.name
+	if namespace != "" {
+		key, scope = namespace+".json", "namespace "+namespace
+		// a policy can be removed after injection was disabled
+		if !arReq.GetDeleteOp() {
+			if err := oClient.requireInjected(ctx, d, namespace); err != nil {
+				return err
+			}
+		}
+	}
+
+	workingOn(ctx, "recording the sidecar resources of %s", scope)
+	configMaps := oClient.clientset(ctx).CoreV1().ConfigMaps(d.namespace)
+	cm, err := configMaps.Get(resourceName(sidecarResourcesName), metav1.GetOptions{})
+	exists := err == nil
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "unable to get the sidecar resources of deployment %s", d.name)
+	}
+	if !exists {
+		if arReq.GetDeleteOp() {
+			return fmt.Errorf("error: %s has no sidecar resources policy", scope)
+		}
+		owner, err := oClient.anchorOwner(ctx, d)
+		if err != nil {
+			return err
+		}
+		cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:            resourceName(sidecarResourcesName),
+			Namespace:       d.namespace,
+			Labels:          d.managedLabels(),
+			OwnerReferences: []metav1.OwnerReference{*owner},
+		}}
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	req := sidecarRequirements(params)
+	if arReq.GetDeleteOp() {
+		if _, ok := cm.Data[key]; !ok {
+			return fmt.Errorf("error: %s has no sidecar resources policy", scope)
+		}
+		delete(cm.Data, key)
+	} else {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return err
+		}
+		cm.Data[key] = string(data)
+	}
+	if exists {
+		_, err = configMaps.Update(cm)
+	} else {
+		_, err = configMaps.Create(cm)
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "unable to record the sidecar resources of %s", scope)
+		logrus.Error(err)
+		return err
+	}
+	progressed(ctx)
+
+	workingOn(ctx, "pointing the injector of deployment %s at the sidecar resources", d.name)
+	if err := oClient.wireSidecarResources(ctx, d); err != nil {
+		return err
+	}
+	progressed(ctx)
+
+	summary := fmt.Sprintf("Set the sidecar resources of %s", scope)
+	details := fmt.Sprintf("Sidecars injected from now on get %s.", describeRequirements(req))
+	if arReq.GetDeleteOp() {
+		summary = fmt.Sprintf("Removed the sidecar resources policy of %s", scope)
+		details = "Sidecars injected from now on get the resources of the cluster policy, or the injector defaults."
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     summary,
+		Details:     details + " Running pods keep the sidecars they have until they are replaced.",
+	}
+	return nil
+}
+
+// injectorContainer finds the dataplane Deployment and container injecting the sidecars of a deployment
+func (oClient *Client) injectorContainer(ctx context.Context, d *deployment) (*appsv1.Deployment, int, error) {
+	depls, err := oClient.clientset(ctx).AppsV1().Deployments(d.namespace).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", deploymentNameLabel, d.name),
+	})
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "unable to list the dataplane of deployment %s", d.name)
+	}
+	for i := range depls.Items {
+		for j, c := range depls.Items[i].Spec.Template.Spec.Containers {
+			for _, arg := range c.Args {
+				if strings.HasPrefix(arg, sidecarImageArg) {
+					return &depls.Items[i], j, nil
+				}
+			}
+		}
+	}
+	return nil, 0, fmt.Errorf("error: no injector found in the dataplane of deployment %s", d.name)
+}
+
+// wireSidecarResources mounts the sidecar resources ConfigMap into the injector and tells it where to find
+// it. The injector restarts the first time only, it reads later changes from the mount.
+func (oClient *Client) wireSidecarResources(ctx context.Context, d *deployment) error {
+	injector, i, err := oClient.injectorContainer(ctx, d)
+	if err != nil {
+		return err
+	}
+	container := injector.Spec.Template.Spec.Containers[i]
+	args := []string{}
+	for _, arg := range container.Args {
+		if strings.HasPrefix(arg, sidecarResourcesArg) {
+			return nil
+		}
+		args = append(args, arg)
+	}
+	args = append(args, sidecarResourcesArg+sidecarResourcesMount)
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+			"volumes": []interface{}{map[string]interface{}{
+				"name": sidecarResourcesVol,
+				"configMap": map[string]interface{}{
+					"name":     resourceName(sidecarResourcesName),
+					"optional": true,
+				},
+			}},
+			"containers": []interface{}{map[string]interface{}{
+				"name": container.Name,
+				"args": args,
+				"volumeMounts": []interface{}{map[string]interface{}{
+					"name":      sidecarResourcesVol,
+					"mountPath": sidecarResourcesMount,
+					"readOnly":  true,
+				}},
+			}},
+		}}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = oClient.clientset(ctx).AppsV1().Deployments(d.namespace).Patch(injector.Name, types.StrategicMergePatchType, patch)
+	if err != nil {
+		err = errors.Wrapf(err, "unable to mount the sidecar resources into injector %s/%s", d.namespace, injector.Name)
+		logrus.Error(err)
+	}
+	return err
+}
+
+// restoreSidecarResources points a reinstalled or upgraded injector at the sidecar resources of its
+// deployment again, applying the dataplane manifest reverts the injector to the release's
+func (oClient *Client) restoreSidecarResources(ctx context.Context, d *deployment) error {
+	_, err := oClient.clientset(ctx).CoreV1().ConfigMaps(d.namespace).Get(resourceName(sidecarResourcesName), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "unable to get the sidecar resources of deployment %s", d.name)
+	}
+	return oClient.wireSidecarResources(ctx, d)
+}
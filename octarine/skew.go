@@ -0,0 +1,314 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// workloadRef identifies the controller of injected pods
+type workloadRef struct {
+	namespace string
+	kind      string
+	name      string
+}
+
+func (w workloadRef) String() string {
+	return fmt.Sprintf("%s %s/%s", w.kind, w.namespace, w.name)
+}
+
+// imageRepoPrefix is the registry and organization of an image, sidecars share it with the dataplane
+func imageRepoPrefix(image string) string {
+	ref := parseImageRef(image)
+	org := ref.repository
+	if i := strings.Index(org, "/"); i >= 0 {
+		org = org[:i]
+	}
+	return ref.registry + "/" + org
+}
+
+func normalizeVersion(version string) string {
+	return strings.TrimPrefix(version, "v")
+}
+
+// dataplaneImages returns the version the dataplane of a deployment runs and the repository prefixes of its images
+func (oClient *Client) dataplaneImages(ctx context.Context, d *deployment) (string, map[string]bool, error) {
+	depls, err := oClient.clientset(ctx).AppsV1().Deployments(d.namespace).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", deploymentNameLabel, d.name),
+	})
+	if err != nil {
+		err = errors.Wrapf(err, "unable to list the dataplane of deployment %s", d.name)
+		logrus.Error(err)
+		return "", nil, err
+	}
+	prefixes := map[string]bool{}
+	tags := map[string]int{}
+	for _, depl := range depls.Items {
+		for _, c := range depl.Spec.Template.Spec.Containers {
+			prefixes[imageRepoPrefix(c.Image)] = true
+			tags[normalizeVersion(parseImageRef(c.Image).tag)]++
+		}
+	}
+	if len(prefixes) == 0 {
+		return "", nil, fmt.Errorf("error: no dataplane deployments found for deployment %s in namespace %s", d.name, d.namespace)
+	}
+	if d.version != "" {
+		return normalizeVersion(d.version), prefixes, nil
+	}
+	// the release is the tag most of the dataplane images carry
+	version, count := "", 0
+	for tag, n := range tags {
+		if n > count || n == count && tag < version {
+			version, count = tag, n
+		}
+	}
+	return version, prefixes, nil
+}
+
+// sidecarVersion returns the version of the sidecar of a pod, OCTARINE_SIDECAR_CONTAINER names
+// the sidecar container when its image isn't published next to the dataplane images
+func sidecarVersion(pod *corev1.Pod, prefixes map[string]bool) (string, bool) {
+	sidecarName := os.Getenv("OCTARINE_SIDECAR_CONTAINER")
+	for _, c := range pod.Spec.Containers {
+		if sidecarName != "" && c.Name == sidecarName || sidecarName == "" && prefixes[imageRepoPrefix(c.Image)] {
+			return normalizeVersion(parseImageRef(c.Image).tag), true
+		}
+	}
+	return "", false
+}
+
+// podWorkload resolves the controller owning a pod, going through the ReplicaSet of deployments
+func (oClient *Client) podWorkload(ctx context.Context, pod *corev1.Pod) workloadRef {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return workloadRef{namespace: pod.GetNamespace(), kind: "Pod", name: pod.GetName()}
+	}
+	if owner.Kind == "ReplicaSet" {
+		rs, err := oClient.clientset(ctx).AppsV1().ReplicaSets(pod.GetNamespace()).Get(owner.Name, metav1.GetOptions{})
+		if err == nil {
+			if rsOwner := metav1.GetControllerOf(rs); rsOwner != nil {
+				return workloadRef{namespace: pod.GetNamespace(), kind: rsOwner.Kind, name: rsOwner.Name}
+			}
+		}
+	}
+	return workloadRef{namespace: pod.GetNamespace(), kind: owner.Kind, name: owner.Name}
+}
+
+// proxyVersions lists the workloads of the namespaces injected by a deployment with the versions of their sidecars
+func (oClient *Client) proxyVersions(ctx context.Context, d *deployment) (string, []*meshes.WorkloadProxy, error) {
+	current, prefixes, err := oClient.dataplaneImages(ctx, d)
+	if err != nil {
+		return "", nil, err
+	}
+	namespaces, err := oClient.injectedNamespaces(ctx, d.name)
+	if err != nil {
+		return "", nil, err
+	}
+	versions := map[workloadRef]map[string]bool{}
+	for _, ns := range sortedKeys(namespaces) {
+		pods, err := oClient.clientset(ctx).CoreV1().Pods(ns).List(metav1.ListOptions{})
+		if err != nil {
+			err = errors.Wrapf(err, "unable to list the pods in namespace %s", ns)
+			logrus.Error(err)
+			return "", nil, err
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			version, ok := sidecarVersion(pod, prefixes)
+			if !ok || pod.GetDeletionTimestamp() != nil {
+				continue
+			}
+			ref := oClient.podWorkload(ctx, pod)
+			if versions[ref] == nil {
+				versions[ref] = map[string]bool{}
+			}
+			versions[ref][version] = true
+		}
+	}
+	workloads := make([]*meshes.WorkloadProxy, 0, len(versions))
+	for ref, vs := range versions {
+		workloads = append(workloads, &meshes.WorkloadProxy{
+			Namespace: ref.namespace,
+			Kind:      ref.kind,
+			Name:      ref.name,
+			Versions:  sortedKeys(vs),
+			UpToDate:  len(vs) == 1 && vs[current],
+		})
+	}
+	sort.Slice(workloads, func(i, j int) bool {
+		a, b := workloads[i], workloads[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return current, workloads, nil
+}
+
+// ProxyVersions reports the sidecar versions of the injected workloads compared to the dataplane version
+func (oClient *Client) ProxyVersions(ctx context.Context, req *meshes.ProxyVersionsRequest) (*meshes.ProxyVersionsResponse, error) {
+	if oClient.clientset(ctx) == nil {
+		return &meshes.ProxyVersionsResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	d, err := oClient.getDeployment(req.GetDeployment())
+	if err != nil {
+		return &meshes.ProxyVersionsResponse{Error: err.Error()}, nil
+	}
+	current, workloads, err := oClient.proxyVersions(ctx, d)
+	if err != nil {
+		return &meshes.ProxyVersionsResponse{Error: err.Error()}, nil
+	}
+	keys := []string{}
+	byKey := map[string]*meshes.WorkloadProxy{}
+	for _, w := range workloads {
+		if (req.GetNamespace() != "" && w.Namespace != req.GetNamespace()) || (req.GetOutdatedOnly() && w.UpToDate) {
+			continue
+		}
+		// slashes can't be part of names, the keys sort the way the workloads do
+		key := strings.Join([]string{w.Namespace, w.Kind, w.Name}, "/")
+		keys = append(keys, key)
+		byKey[key] = w
+	}
+	sort.Strings(keys)
+	start, end, next, err := paginate(keys, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return &meshes.ProxyVersionsResponse{Error: err.Error()}, nil
+	}
+	resp := &meshes.ProxyVersionsResponse{ControlPlaneVersion: current, NextPageToken: next}
+	for _, key := range keys[start:end] {
+		resp.Workloads = append(resp.Workloads, byKey[key])
+	}
+	return resp, nil
+}
+
+// restartWorkload rolls the pods of a workload the way kubectl rollout restart does
+func (oClient *Client) restartWorkload(ctx context.Context, ref workloadRef) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`,
+		restartedAtAnnotation, time.Now().Format(time.RFC3339)))
+	var err error
+	switch ref.kind {
+	case "Deployment":
+		_, err = oClient.clientset(ctx).AppsV1().Deployments(ref.namespace).Patch(ref.name, types.StrategicMergePatchType, patch)
+	case "DaemonSet":
+		_, err = oClient.clientset(ctx).AppsV1().DaemonSets(ref.namespace).Patch(ref.name, types.StrategicMergePatchType, patch)
+	case "StatefulSet":
+		_, err = oClient.clientset(ctx).AppsV1().StatefulSets(ref.namespace).Patch(ref.name, types.StrategicMergePatchType, patch)
+	default:
+		return fmt.Errorf("error: %s can't be restarted, recreate it to update its sidecar", ref)
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "unable to restart %s", ref)
+		logrus.Error(err)
+	}
+	return err
+}
+
+// executeProxyUpgrade restarts the workloads whose sidecars don't run the dataplane version
+func (oClient *Client) executeProxyUpgrade(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return err
+	}
+	current, workloads, err := oClient.proxyVersions(ctx, d)
+	if err != nil {
+		return err
+	}
+	outdated := []workloadRef{}
+	details := []string{}
+	for _, w := range workloads {
+		if w.GetUpToDate() || arReq.GetNamespace() != "" && w.GetNamespace() != arReq.GetNamespace() {
+			continue
+		}
+		ref := workloadRef{namespace: w.GetNamespace(), kind: w.GetKind(), name: w.GetName()}
+		outdated = append(outdated, ref)
+		details = append(details, fmt.Sprintf("%s: %s", ref, strings.Join(w.GetVersions(), ", ")))
+	}
+	if len(outdated) == 0 {
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: arReq.GetOperationId(),
+			EventType:   meshes.EventType_INFO,
+			Summary:     "All sidecars are up to date",
+			Details:     fmt.Sprintf("Every injected workload runs sidecar version %s.", current),
+		}
+		return nil
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_WARN,
+		Summary:     fmt.Sprintf("%d workloads run sidecars other than version %s", len(outdated), current),
+		Details:     strings.Join(details, "\n"),
+	}
+
+	failed := []string{}
+	restarted := map[string][]string{}
+	for _, ref := range outdated {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// restarting adds sidecars, not while the namespace already waits for capacity
+		if err := oClient.awaitCapacity(ctx, ref.namespace); err != nil {
+			return err
+		}
+		workingOn(ctx, "restarting %s", ref)
+		if err := oClient.restartWorkload(ctx, ref); err != nil {
+			failed = append(failed, err.Error())
+			continue
+		}
+		progressed(ctx)
+		if ref.kind == "Deployment" {
+			restarted[ref.namespace] = append(restarted[ref.namespace], ref.name)
+		}
+	}
+	namespaces := mapKeys(restarted)
+	sort.Strings(namespaces)
+	for _, ns := range namespaces {
+		if err := oClient.waitForDeployments(ctx, ns, restarted[ns]); err != nil {
+			return err
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("error: %d workloads were not restarted:\n%s", len(failed), strings.Join(failed, "\n"))
+	}
+	return nil
+}
+
+func mapKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,486 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	spireVersion = "spire.spiffe.io/v1alpha1"
+
+	defaultSpireNamespace = "spire"
+	// spireServerConfig is the ConfigMap of the server.conf of the SPIRE server, spireBundleConfig the one
+	// its k8sbundle notifier publishes the trust bundle to
+	spireServerConfig = "spire-server"
+	spireBundleConfig = "spire-bundle"
+	spireBundleKey    = "bundle.crt"
+
+	// defaultIdentityTemplate maps the identity of a workload, its namespace and service account, to the
+	// SPIFFE ID SPIRE issues it, in the template syntax of the SPIRE controller manager
+	defaultIdentityTemplate = "spiffe://{{ .TrustDomain }}/ns/{{ .PodMeta.Namespace }}/sa/{{ .PodSpec.ServiceAccountName }}"
+
+	spireFederationName = "octarine-spire-federation"
+
+	// the controller manager registers the pods a ClusterSPIFFEID selects within a few seconds
+	spireRegistrationTimeout      = 2 * time.Minute
+	spireRegistrationPollInterval = 5 * time.Second
+)
+
+var (
+	spiffeIDResource      = schema.GroupVersionResource{Group: "spire.spiffe.io", Version: "v1alpha1", Resource: "clusterspiffeids"}
+	federatedTrustDomains = schema.GroupVersionResource{Group: "spire.spiffe.io", Version: "v1alpha1", Resource: "clusterfederatedtrustdomains"}
+
+	serverTrustDomain = regexp.MustCompile(`(?m)^\s*trust_domain\s*=\s*"([^"]+)"`)
+	trustDomainName   = regexp.MustCompile(`^[a-z0-9._-]+$`)
+)
+
+// octarineTrustBundle is the trust domain of an Octarine domain, as octactl reports it
+type octarineTrustBundle struct {
+	TrustDomain string `json:"trustDomain"`
+	// BundleEndpoint serves the bundle to the federated trust domains, SPIRE refreshes it from there
+	BundleEndpoint string `json:"bundleEndpoint"`
+	Bundle         string `json:"bundle"`
+}
+
+// spireFederation is what federating a deployment with SPIRE sets up
+type spireFederation struct {
+	namespace        string
+	trustDomain      string
+	bundle           string
+	identityTemplate string
+	octarine         *octarineTrustBundle
+}
+
+// validateSpireParams checks the trust domain and the identity template of a federation
+func validateSpireParams(params *deploymentParams) error {
+	if td := params.TrustDomain; td != "" && !trustDomainName.MatchString(td) {
+		return fmt.Errorf("error: trust domain %q may only have lowercase letters, digits, dots, dashes and underscores", td)
+	}
+	if t := params.IdentityTemplate; t != "" && !strings.HasPrefix(t, "spiffe://") {
+		return fmt.Errorf("error: identity template %q must make spiffe:// IDs", t)
+	}
+	return nil
+}
+
+// spireTrustDomain reads the trust domain from the configuration of the SPIRE server, or takes the one of
+// the custom body
+func (oClient *Client) spireTrustDomain(ctx context.Context, namespace, given string) (string, error) {
+	if given != "" {
+		return given, nil
+	}
+	cm, err := oClient.clientset(ctx).CoreV1().ConfigMaps(namespace).Get(spireServerConfig, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to read the configuration of the SPIRE server in namespace %s, set the trust_domain of the custom body", namespace)
+	}
+	m := serverTrustDomain.FindStringSubmatch(cm.Data["server.conf"])
+	if m == nil {
+		return "", fmt.Errorf("error: the configuration of the SPIRE server in namespace %s names no trust domain, set the trust_domain of the custom body", namespace)
+	}
+	return m[1], nil
+}
+
+// spireBundle is the trust bundle the SPIRE server publishes in the cluster
+func (oClient *Client) spireBundle(ctx context.Context, namespace string) (string, error) {
+	cm, err := oClient.clientset(ctx).CoreV1().ConfigMaps(namespace).Get(spireBundleConfig, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to read the SPIRE trust bundle, the k8sbundle notifier of the server publishes it to ConfigMap %s/%s", namespace, spireBundleConfig)
+	}
+	bundle := strings.TrimSpace(cm.Data[spireBundleKey])
+	if _, err := parseBundle(bundle); err != nil {
+		return "", errors.Wrapf(err, "ConfigMap %s/%s", namespace, spireBundleConfig)
+	}
+	return bundle, nil
+}
+
+// octarineTrustBundle asks the control plane for the trust domain and bundle of the domain of a deployment
+func (oClient *Client) octarineTrustBundle(ctx context.Context, d *deployment) (*octarineTrustBundle, error) {
+	var out []byte
+	err := oClient.asAccount(ctx, d, func() error {
+		var err error
+		out, err = exec.CommandContext(ctx, "octactl", "domain", "trust-bundle", d.domain, "--output", "json").Output()
+		return err
+	})
+	if err != nil {
+		logrus.Errorf("Command finished with error: %v", err)
+		return nil, errors.Wrapf(err, "unable to get the trust bundle of domain %s", d.domain)
+	}
+	bundle := &octarineTrustBundle{}
+	if err := json.Unmarshal(out, bundle); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the trust bundle of domain %s", d.domain)
+	}
+	if bundle.TrustDomain == "" || bundle.BundleEndpoint == "" {
+		return nil, fmt.Errorf("error: the control plane reported no trust domain or bundle endpoint for domain %s", d.domain)
+	}
+	if _, err := parseBundle(bundle.Bundle); err != nil {
+		return nil, errors.Wrapf(err, "domain %s", d.domain)
+	}
+	return bundle, nil
+}
+
+func parseBundle(bundle string) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	rest := []byte(bundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to parse the trust bundle")
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("error: the trust bundle holds no certificate")
+	}
+	return certs, nil
+}
+
+// spiffeBundle writes a PEM bundle in the JWKS format of SPIFFE bundles, which SPIRE takes as the initial
+// bundle of a federated trust domain
+func spiffeBundle(bundle string) (string, error) {
+	certs, err := parseBundle(bundle)
+	if err != nil {
+		return "", err
+	}
+	keys := []map[string]interface{}{}
+	for _, cert := range certs {
+		key := map[string]interface{}{
+			"use": "x509-svid",
+			"x5c": []string{base64.StdEncoding.EncodeToString(cert.Raw)},
+		}
+		switch pub := cert.PublicKey.(type) {
+		case *rsa.PublicKey:
+			key["kty"] = "RSA"
+			key["n"] = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			key["e"] = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case *ecdsa.PublicKey:
+			size := (pub.Curve.Params().BitSize + 7) / 8
+			key["kty"] = "EC"
+			key["crv"] = pub.Curve.Params().Name
+			key["x"] = base64.RawURLEncoding.EncodeToString(padded(pub.X.Bytes(), size))
+			key["y"] = base64.RawURLEncoding.EncodeToString(padded(pub.Y.Bytes(), size))
+		default:
+			return "", fmt.Errorf("error: the trust bundle has a %T key, only RSA and EC keys are supported", pub)
+		}
+		keys = append(keys, key)
+	}
+	out, err := json.Marshal(map[string]interface{}{"keys": keys})
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to write the SPIFFE bundle")
+	}
+	return string(out), nil
+}
+
+func padded(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	return append(make([]byte, size-len(b)), b...)
+}
+
+// spiffeIDPolicy registers the workloads of the namespaces a deployment injects with SPIRE, federated with
+// the trust domain of Octarine so they accept its identities
+func spiffeIDPolicy(d *deployment, f *spireFederation) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": spireVersion,
+		"kind":       "ClusterSPIFFEID",
+		"metadata":   map[string]interface{}{"name": d.clusterResourceName(spireFederationName)},
+		"spec": map[string]interface{}{
+			"spiffeIDTemplate":  f.identityTemplate,
+			"namespaceSelector": map[string]interface{}{"matchLabels": map[string]interface{}{injectionLabel: d.injectionValue()}},
+			"federatesWith":     []interface{}{f.octarine.TrustDomain},
+		},
+	}}
+	obj.SetLabels(d.managedLabels())
+	return obj
+}
+
+// federatedTrustDomain makes SPIRE trust the identities of Octarine, the bundle is refreshed from the endpoint
+// of the control plane
+func federatedTrustDomain(d *deployment, f *spireFederation, initialBundle string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": spireVersion,
+		"kind":       "ClusterFederatedTrustDomain",
+		"metadata":   map[string]interface{}{"name": d.clusterResourceName(spireFederationName)},
+		"spec": map[string]interface{}{
+			"trustDomain":           f.octarine.TrustDomain,
+			"bundleEndpointURL":     f.octarine.BundleEndpoint,
+			"bundleEndpointProfile": map[string]interface{}{"type": "https_web"},
+			"trustDomainBundle":     initialBundle,
+		},
+	}}
+	obj.SetLabels(d.managedLabels())
+	return obj
+}
+
+// identityFederationPolicy makes the Octarine domain trust the identities SPIRE issues and tells it how they
+// map to the identities of its workloads
+func identityFederationPolicy(f *spireFederation) string {
+	return fmt.Sprintf(`kind: IdentityFederation
+name: %s
+spec:
+  trustDomain: %s
+  identityTemplate: %q
+  bundle: |
+%s
+`, spireFederationName, f.trustDomain, f.identityTemplate, indent(4, f.bundle))
+}
+
+func (oClient *Client) applyIdentityFederation(ctx context.Context, d *deployment, f *spireFederation, remove bool) error {
+	if remove {
+		return oClient.deletePolicy(ctx, d, "", spireFederationName)
+	}
+	return oClient.asAccount(ctx, d, func() error {
+		cmd := exec.CommandContext(ctx, "octactl", "policy", "apply", d.domain, "-f", "-")
+		cmd.Stdin = strings.NewReader(identityFederationPolicy(f))
+		if out, err := cmd.CombinedOutput(); err != nil {
+			logrus.Errorf("Command finished with error: %v: %s", err, out)
+			return errors.Wrapf(err, "unable to apply the identity federation of domain %s", d.domain)
+		}
+		return nil
+	})
+}
+
+// spireRegistration tells how many of the injected pods of a deployment SPIRE registered, and why the others
+// aren't
+type spireRegistration struct {
+	injected   int
+	registered int
+	failures   int
+	agents     string
+	problems   []string
+}
+
+func (r *spireRegistration) complete() bool {
+	return r.injected > 0 && r.registered >= r.injected && r.failures == 0 && len(r.problems) == 0
+}
+
+// checkSpireRegistration compares the pods the ClusterSPIFFEID of a deployment made entries for with the
+// injected pods, and checks the agents handing out the SVIDs are ready
+func (oClient *Client) checkSpireRegistration(ctx context.Context, d *deployment, f *spireFederation, name string) (*spireRegistration, error) {
+	r := &spireRegistration{}
+	_, prefixes, err := oClient.dataplaneImages(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	namespaces, err := oClient.injectedNamespaces(ctx, d.name)
+	if err != nil {
+		return nil, err
+	}
+	for _, ns := range sortedKeys(namespaces) {
+		pods, err := oClient.clientset(ctx).CoreV1().Pods(ns).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to list the pods of namespace %s", ns)
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if pod.Status.Phase != corev1.PodRunning || pod.GetDeletionTimestamp() != nil {
+				continue
+			}
+			if _, injected := sidecarVersion(pod, prefixes); injected {
+				r.injected++
+			}
+		}
+	}
+
+	live, err := oClient.dynamicClient(ctx).Resource(spiffeIDResource).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get ClusterSPIFFEID %s", name)
+	}
+	stat := func(name string) int {
+		n, _, _ := unstructured.NestedInt64(live.Object, "status", "stats", name)
+		return int(n)
+	}
+	r.registered = stat("podsSelected")
+	r.failures = stat("entryFailures") + stat("podEntryRenderFailures")
+	if r.failures > 0 {
+		r.problems = append(r.problems, fmt.Sprintf("SPIRE failed to make %d entries, check the identity template", r.failures))
+	}
+
+	daemonSets, err := oClient.clientset(ctx).AppsV1().DaemonSets(f.namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the SPIRE agents of namespace %s", f.namespace)
+	}
+	for _, ds := range daemonSets.Items {
+		if !strings.Contains(ds.Name, "agent") {
+			continue
+		}
+		r.agents = fmt.Sprintf("%d of %d SPIRE agents ready", ds.Status.NumberReady, ds.Status.DesiredNumberScheduled)
+		if ds.Status.NumberReady < ds.Status.DesiredNumberScheduled {
+			r.problems = append(r.problems, fmt.Sprintf("%s, the pods of the other nodes get no SVID", r.agents))
+		}
+	}
+	if r.agents == "" {
+		r.problems = append(r.problems, fmt.Sprintf("no SPIRE agent DaemonSet in namespace %s hands out the SVIDs", f.namespace))
+	}
+	return r, nil
+}
+
+// waitForSpireRegistration waits until SPIRE registered every injected pod of the deployment, it returns the
+// last registration checked when it doesn't within spireRegistrationTimeout
+func (oClient *Client) waitForSpireRegistration(ctx context.Context, d *deployment, f *spireFederation, name string) (*spireRegistration, error) {
+	deadline := time.Now().Add(spireRegistrationTimeout)
+	for {
+		r, err := oClient.checkSpireRegistration(ctx, d, f, name)
+		if err != nil {
+			return nil, err
+		}
+		if r.complete() || time.Now().After(deadline) {
+			return r, nil
+		}
+		workingOn(ctx, "SPIRE to register the injected pods of deployment %s, %d of %d registered", d.name, r.registered, r.injected)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(spireRegistrationPollInterval):
+		}
+	}
+}
+
+// executeSpireFederation federates the workload identities of a deployment with the SPIRE deployment of the
+// cluster: each side trusts the bundle of the other, and SPIRE issues the injected workloads SVIDs mapped from
+// their Octarine identity. Deleting removes the federation on both sides.
+func (oClient *Client) executeSpireFederation(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	if oClient.clientset(ctx) == nil || oClient.dynamicClient(ctx) == nil {
+		return fmt.Errorf("error: mesh client has not been created")
+	}
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	if err := validateSpireParams(params); err != nil {
+		return err
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return err
+	}
+	if _, err := oClient.clientset(ctx).Discovery().ServerResourcesForGroupVersion(spireVersion); err != nil {
+		return errors.Wrapf(err, "unable to find the SPIRE controller manager, %s isn't served", spireVersion)
+	}
+	f := &spireFederation{namespace: params.SpireNamespace, identityTemplate: params.IdentityTemplate, octarine: &octarineTrustBundle{}}
+	if f.namespace == "" {
+		f.namespace = defaultSpireNamespace
+	}
+	if f.identityTemplate == "" {
+		f.identityTemplate = defaultIdentityTemplate
+	}
+	name := d.clusterResourceName(spireFederationName)
+
+	if arReq.GetDeleteOp() {
+		workingOn(ctx, "the SPIRE federation of deployment %s", d.name)
+		for _, res := range []schema.GroupVersionResource{spiffeIDResource, federatedTrustDomains} {
+			err := oClient.dynamicClient(ctx).Resource(res).Delete(name, &metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "unable to delete %s %s", res.Resource, name)
+			}
+		}
+		progressed(ctx)
+		if err := oClient.applyIdentityFederation(ctx, d, f, true); err != nil {
+			return err
+		}
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: arReq.GetOperationId(),
+			EventType:   meshes.EventType_INFO,
+			Summary:     fmt.Sprintf("Deployment %s no longer federates with SPIRE", d.name),
+		}
+		return nil
+	}
+
+	workingOn(ctx, "the trust bundles of SPIRE and deployment %s", d.name)
+	if f.trustDomain, err = oClient.spireTrustDomain(ctx, f.namespace, params.TrustDomain); err != nil {
+		return err
+	}
+	if f.bundle, err = oClient.spireBundle(ctx, f.namespace); err != nil {
+		return err
+	}
+	if f.octarine, err = oClient.octarineTrustBundle(ctx, d); err != nil {
+		return err
+	}
+	initialBundle, err := spiffeBundle(f.octarine.Bundle)
+	if err != nil {
+		return err
+	}
+	progressed(ctx)
+
+	workingOn(ctx, "the SPIRE federation of deployment %s", d.name)
+	if err := oClient.applyClusterObject(ctx, federatedTrustDomains, federatedTrustDomain(d, f, initialBundle)); err != nil {
+		return err
+	}
+	if err := oClient.applyClusterObject(ctx, spiffeIDResource, spiffeIDPolicy(d, f)); err != nil {
+		return err
+	}
+	if err := oClient.applyIdentityFederation(ctx, d, f, false); err != nil {
+		return err
+	}
+	progressed(ctx)
+
+	r, err := oClient.waitForSpireRegistration(ctx, d, f, name)
+	if err != nil {
+		return err
+	}
+	details := fmt.Sprintf("Octarine trust domain %s and SPIRE trust domain %s trust each other's bundle. %d of %d injected pods are registered with SPIRE as %s, %s.",
+		f.octarine.TrustDomain, f.trustDomain, r.registered, r.injected, f.identityTemplate, r.agents)
+	if !r.complete() {
+		problems := r.problems
+		if r.injected == 0 {
+			problems = append(problems, "no injected pod runs yet")
+		} else if r.registered < r.injected {
+			problems = append(problems, fmt.Sprintf("%d injected pod(s) weren't registered within %s", r.injected-r.registered, spireRegistrationTimeout))
+		}
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: arReq.GetOperationId(),
+			EventType:   meshes.EventType_WARN,
+			Summary:     fmt.Sprintf("Deployment %s federates with SPIRE but its workloads may not get SVIDs", d.name),
+			Details:     details + "\n" + strings.Join(problems, "\n"),
+		}
+		return nil
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("Deployment %s federates with SPIRE trust domain %s", d.name, f.trustDomain),
+		Details:     details,
+	}
+	return nil
+}
@@ -22,13 +22,47 @@ type supportedOperation struct {
 	// the template file name
 	templateName string
 	opType       meshes.OpCategory
+	// minVersion is the first Octarine release running the operation, features the licensed features it needs
+	minVersion string
+	features   []string
 }
 
 const (
 	customOpCommand        = "custom"
+	customLabelDeleteOp    = "custom_label_delete"
 	runVet                 = "octarine_vet"
 	installOctarineCommand = "octarine_install"
+	bootstrapCommand       = "octarine_bootstrap"
+	trialCommand           = "octarine_trial"
 	installBookInfoCommand = "install_book_info"
+	cleanupSamplesCommand  = "octarine_cleanup_samples"
+
+	applyMeshSpecCommand     = "octarine_meshspec_apply"
+	reconcileMeshSpecCommand = "octarine_meshspec_reconcile"
+
+	proxyUpgradeCommand       = "octarine_proxy_upgrade"
+	enforcementModeCommand    = "octarine_enforcement_mode"
+	runtimeProtectionCommand  = "octarine_runtime_protection"
+	admissionTestCommand      = "octarine_admission_test"
+	backupCommand             = "octarine_backup"
+	backupRestoreCommand      = "octarine_backup_restore"
+	breachSimulationCommand   = "octarine_breach_simulation"
+	latencyProbeCommand       = "octarine_latency_probe"
+	protectComponentsCommand  = "octarine_protect_components"
+	spireFederationCommand    = "octarine_spire_federation"
+	exposeCommand             = "octarine_expose"
+	routePoliciesCommand      = "octarine_route_policies"
+	mirrorImagesCommand       = "octarine_mirror_images"
+	selfTestCommand           = "octarine_self_test"
+	injectionExclusionCommand = "octarine_injection_exclusion"
+	sidecarResourcesCommand   = "octarine_sidecar_resources"
+	batchWorkloadsCommand     = "octarine_batch_workloads"
+	policyImportCommand       = "octarine_policy_import"
+	namespaceIsolationCommand = "octarine_namespace_isolation"
+
+	demoBlockRatingsCommand   = "octarine_demo_block_ratings"
+	demoMTLSCommand           = "octarine_demo_mtls"
+	demoRestrictEgressCommand = "octarine_demo_restrict_egress"
 )
 
 var supportedOps = map[string]supportedOperation{
@@ -37,11 +71,23 @@ var supportedOps = map[string]supportedOperation{
 		// templateName: "install_octarine.tmpl",
 		opType: meshes.OpCategory_INSTALL,
 	},
+	bootstrapCommand: {
+		name:   "Create the Octarine account and domain of a deployment",
+		opType: meshes.OpCategory_INSTALL,
+	},
+	trialCommand: {
+		name:   "Sign up an Octarine trial account and install its data plane",
+		opType: meshes.OpCategory_INSTALL,
+	},
 	installBookInfoCommand: {
 		name: "Sample application BookInfo",
 		// templateName: "install_bookinfo.tmpl",
 		opType: meshes.OpCategory_SAMPLE_APPLICATION,
 	},
+	cleanupSamplesCommand: {
+		name:   "Remove the sample apps and probes left in any namespace",
+		opType: meshes.OpCategory_SAMPLE_APPLICATION,
+	},
 	runVet: {
 		name: "Vet Ocatarine's deployment",
 		// templateName: "octarine_vet.tmpl",
@@ -51,4 +97,109 @@ var supportedOps = map[string]supportedOperation{
 		name:   "Apply custom configuration (YAML)",
 		opType: meshes.OpCategory_CUSTOM,
 	},
+	customLabelDeleteOp: {
+		name:   "Delete resources matching the labels of custom configuration (YAML)",
+		opType: meshes.OpCategory_CUSTOM,
+	},
+	applyMeshSpecCommand: {
+		name:   "Apply desired state (MeshSpec YAML)",
+		opType: meshes.OpCategory_CONFIGURE,
+	},
+	reconcileMeshSpecCommand: {
+		name:   "Reconcile the last applied MeshSpec",
+		opType: meshes.OpCategory_CONFIGURE,
+	},
+	proxyUpgradeCommand: {
+		name:   "Restart workloads with out of date sidecars",
+		opType: meshes.OpCategory_CONFIGURE,
+	},
+	enforcementModeCommand: {
+		name:   "Switch between observing and enforcing policies",
+		opType: meshes.OpCategory_CONFIGURE,
+	},
+	runtimeProtectionCommand: {
+		name:   "Enable runtime protection features in a namespace",
+		opType: meshes.OpCategory_CONFIGURE,
+		// the release of the first protection feature, checkProtectionFeatures checks the others
+		minVersion: "1.6",
+		features:   []string{"runtime_protection"},
+	},
+	admissionTestCommand: {
+		name:   "Dry-run sample workloads against the admission policies",
+		opType: meshes.OpCategory_VALIDATE,
+	},
+	backupCommand: {
+		name:   "Back up Octarine's resources and secrets",
+		opType: meshes.OpCategory_CONFIGURE,
+	},
+	backupRestoreCommand: {
+		name:   "Restore a backup of Octarine's resources and secrets",
+		opType: meshes.OpCategory_CONFIGURE,
+	},
+	breachSimulationCommand: {
+		name:   "Simulate a policy breach and check that Octarine catches it",
+		opType: meshes.OpCategory_VALIDATE,
+	},
+	latencyProbeCommand: {
+		name:   "Measure the latency and throughput cost of the sidecars",
+		opType: meshes.OpCategory_VALIDATE,
+	},
+	protectComponentsCommand: {
+		name:   "Protect Octarine's components from changes by other users",
+		opType: meshes.OpCategory_CONFIGURE,
+	},
+	spireFederationCommand: {
+		name:     "Federate workload identities with SPIRE",
+		opType:   meshes.OpCategory_CONFIGURE,
+		features: []string{"identity_federation"},
+	},
+	exposeCommand: {
+		name:   "Expose the Octarine dashboard and API over HTTPS",
+		opType: meshes.OpCategory_CONFIGURE,
+	},
+	routePoliciesCommand: {
+		name:   "Generate L7 policies from Gateway API HTTPRoutes",
+		opType: meshes.OpCategory_CONFIGURE,
+	},
+	mirrorImagesCommand: {
+		name:   "Mirror the Octarine images to a private registry",
+		opType: meshes.OpCategory_INSTALL,
+	},
+	selfTestCommand: {
+		name:   "Check that the adapter renders, applies and deletes objects",
+		opType: meshes.OpCategory_VALIDATE,
+	},
+	injectionExclusionCommand: {
+		name:   "Exclude workloads matching a selector from sidecar injection",
+		opType: meshes.OpCategory_CONFIGURE,
+	},
+	sidecarResourcesCommand: {
+		name:   "Set the CPU and memory of the injected sidecars",
+		opType: meshes.OpCategory_CONFIGURE,
+	},
+	batchWorkloadsCommand: {
+		name:   "Find the Jobs kept from completing by their sidecar and shut it down with them",
+		opType: meshes.OpCategory_CONFIGURE,
+	},
+	policyImportCommand: {
+		name:   "Import a bundle of Octarine policies, reviewing what it changes",
+		opType: meshes.OpCategory_CONFIGURE,
+	},
+	namespaceIsolationCommand: {
+		name:         "Only let the injected namespaces reach the pods of a namespace",
+		templateName: "namespace_isolation.tmpl",
+		opType:       meshes.OpCategory_CONFIGURE,
+	},
+	demoBlockRatingsCommand: {
+		name:   "BookInfo demo step 1: block reviews from calling ratings",
+		opType: meshes.OpCategory_SAMPLE_APPLICATION,
+	},
+	demoMTLSCommand: {
+		name:   "BookInfo demo step 2: require mutual TLS",
+		opType: meshes.OpCategory_SAMPLE_APPLICATION,
+	},
+	demoRestrictEgressCommand: {
+		name:   "BookInfo demo step 3: block egress to the internet",
+		opType: meshes.OpCategory_SAMPLE_APPLICATION,
+	},
 }
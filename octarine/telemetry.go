@@ -0,0 +1,240 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// telemetry is opt-in, nothing is sent unless an endpoint is set
+	telemetryEndpointEnv     = "OCTARINE_TELEMETRY_ENDPOINT"
+	telemetryIntervalEnv     = "OCTARINE_TELEMETRY_INTERVAL"
+	defaultTelemetryInterval = 24 * time.Hour
+	telemetryTimeout         = 10 * time.Second
+
+	// operations of the template catalog are counted under this name, theirs may tell about the organization
+	catalogOpsName = "catalog"
+)
+
+// clusterSizeBuckets are the upper bounds of the node counts the size of a cluster is reported as
+var clusterSizeBuckets = []struct {
+	max  int
+	name string
+}{
+	{5, "1-5"},
+	{20, "6-20"},
+	{100, "21-100"},
+	{500, "101-500"},
+}
+
+// usageReport is the body of a telemetry report. It holds nothing naming the cluster, its users or their
+// resources.
+type usageReport struct {
+	// Installation is a hash of the uid of the kube-system namespace, which tells reports of the same cluster
+	// apart from the others without naming it
+	Installation       string         `json:"installation"`
+	AdapterVersion     string         `json:"adapterVersion"`
+	OctarineVersions   []string       `json:"octarineVersions"`
+	ClusterSize        string         `json:"clusterSize"`
+	RegisteredClusters int            `json:"registeredClusters"`
+	Since              string         `json:"since"`
+	Until              string         `json:"until"`
+	Operations         map[string]int `json:"operations"`
+}
+
+// usageCounter counts the operations run since the last report went through
+type usageCounter struct {
+	mu         sync.Mutex
+	since      time.Time
+	operations map[string]int
+}
+
+var usage = &usageCounter{since: time.Now().UTC(), operations: map[string]int{}}
+
+// countOperation counts an operation for the next telemetry report
+func countOperation(opName string) {
+	// a synced catalog adds its operations to the supported ones, they are still counted under catalogOpsName
+	if _, ok := lookupOp(opName); !ok || catalogOp(opName) {
+		opName = catalogOpsName
+	}
+	usage.mu.Lock()
+	usage.operations[opName]++
+	usage.mu.Unlock()
+}
+
+func (u *usageCounter) snapshot() (time.Time, map[string]int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	counts := make(map[string]int, len(u.operations))
+	for op, n := range u.operations {
+		counts[op] = n
+	}
+	return u.since, counts
+}
+
+// sent takes the operations of a report which went through off the counts
+func (u *usageCounter) sent(until time.Time, counts map[string]int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for op, n := range counts {
+		if u.operations[op] -= n; u.operations[op] <= 0 {
+			delete(u.operations, op)
+		}
+	}
+	u.since = until
+}
+
+func clusterSizeBucket(nodes int) string {
+	for _, b := range clusterSizeBuckets {
+		if nodes <= b.max {
+			return b.name
+		}
+	}
+	return fmt.Sprintf("%d+", clusterSizeBuckets[len(clusterSizeBuckets)-1].max+1)
+}
+
+// usageReport gathers the report the adapter would send now, along with the counts it covers
+func (oClient *Client) usageReport(ctx context.Context, now time.Time) (*usageReport, map[string]int) {
+	since, counts := usage.snapshot()
+	report := &usageReport{
+		AdapterVersion:   AdapterVersion,
+		OctarineVersions: []string{},
+		ClusterSize:      "unknown",
+		Since:            since.Format(time.RFC3339),
+		Until:            now.UTC().Format(time.RFC3339),
+		Operations:       counts,
+	}
+	if oClient.clientset(ctx) != nil {
+		if ns, err := oClient.clientset(ctx).CoreV1().Namespaces().Get("kube-system", metav1.GetOptions{}); err == nil {
+			sum := sha256.Sum256([]byte(ns.UID))
+			report.Installation = hex.EncodeToString(sum[:16])
+		}
+		if nodes, err := oClient.clientset(ctx).CoreV1().Nodes().List(metav1.ListOptions{}); err == nil {
+			report.ClusterSize = clusterSizeBucket(len(nodes.Items))
+		}
+	}
+	versions := map[string]bool{}
+	oClient.deploymentsMu.Lock()
+	for _, d := range oClient.deployments {
+		if d.version == "" {
+			versions["default"] = true
+			continue
+		}
+		versions[d.version] = true
+	}
+	oClient.deploymentsMu.Unlock()
+	for v := range versions {
+		report.OctarineVersions = append(report.OctarineVersions, v)
+	}
+	sort.Strings(report.OctarineVersions)
+	oClient.clustersMu.Lock()
+	report.RegisteredClusters = len(oClient.clusters)
+	oClient.clustersMu.Unlock()
+	return report, counts
+}
+
+// sendUsageReport posts the report to the endpoint, the counts it covers start over once it went through
+func (oClient *Client) sendUsageReport(ctx context.Context, endpoint string, now time.Time) error {
+	report, counts := oClient.usageReport(ctx, now)
+	body, err := json.Marshal(report)
+	if err != nil {
+		return errors.Wrapf(err, "unable to marshal the usage report")
+	}
+	ctx, cancel := context.WithTimeout(ctx, telemetryTimeout)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "unable to report usage to %s", endpoint)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Wrapf(err, "unable to report usage to %s", endpoint)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unable to report usage to %s: %s", endpoint, resp.Status)
+	}
+	usage.sent(now.UTC(), counts)
+	return nil
+}
+
+// startTelemetry reports the usage of the adapter every OCTARINE_TELEMETRY_INTERVAL, once the user opted in
+// by setting OCTARINE_TELEMETRY_ENDPOINT
+func (oClient *Client) startTelemetry() {
+	endpoint := os.Getenv(telemetryEndpointEnv)
+	if endpoint == "" {
+		return
+	}
+	oClient.telemetryOnce.Do(func() {
+		interval := durationFromEnv(telemetryIntervalEnv, defaultTelemetryInterval)
+		logrus.Infof("Reporting anonymous usage to %s every %s", endpoint, interval)
+		oClient.telemetryMu.Lock()
+		oClient.telemetryNext = time.Now().Add(interval)
+		oClient.telemetryMu.Unlock()
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for now := range ticker.C {
+				if err := oClient.sendUsageReport(context.Background(), endpoint, now); err != nil {
+					// the counts are kept for the next report
+					logrus.Warn(err)
+				}
+				oClient.telemetryMu.Lock()
+				oClient.telemetryNext = now.Add(interval)
+				oClient.telemetryMu.Unlock()
+			}
+		}()
+	})
+}
+
+// PreviewTelemetry returns the report the adapter would send now, byte for byte, and whether and where it
+// sends reports at all
+func (oClient *Client) PreviewTelemetry(ctx context.Context, _ *meshes.PreviewTelemetryRequest) (*meshes.PreviewTelemetryResponse, error) {
+	endpoint := os.Getenv(telemetryEndpointEnv)
+	resp := &meshes.PreviewTelemetryResponse{
+		Enabled:  endpoint != "",
+		Endpoint: endpoint,
+		Interval: durationFromEnv(telemetryIntervalEnv, defaultTelemetryInterval).String(),
+	}
+	oClient.telemetryMu.Lock()
+	if !oClient.telemetryNext.IsZero() {
+		resp.NextReport = oClient.telemetryNext.UTC().Format(time.RFC3339)
+	}
+	oClient.telemetryMu.Unlock()
+	report, _ := oClient.usageReport(ctx, time.Now())
+	body, err := json.Marshal(report)
+	if err != nil {
+		return &meshes.PreviewTelemetryResponse{Error: errors.Wrapf(err, "unable to marshal the usage report").Error()}, nil
+	}
+	resp.Report = string(body)
+	return resp, nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// templateCapabilities is what templates know about the target cluster, as .capabilities
+type templateCapabilities struct {
+	KubeVersion string
+	OpenShift   bool
+	// the group versions the API server serves, e.g. route.openshift.io/v1
+	APIVersions []string
+}
+
+// clusterTemplateCapabilities discovers the capabilities of the cluster of the client for templates
+func (oClient *Client) clusterTemplateCapabilities(ctx context.Context) (templateCapabilities, error) {
+	caps := templateCapabilities{}
+	version, err := oClient.clientset(ctx).Discovery().ServerVersion()
+	if err != nil {
+		return caps, fmt.Errorf("error: unable to get the server version for the template: %v", err)
+	}
+	caps.KubeVersion = version.GitVersion
+	groups, err := oClient.clientset(ctx).Discovery().ServerGroups()
+	if err != nil {
+		return caps, fmt.Errorf("error: unable to list the API groups for the template: %v", err)
+	}
+	caps.APIVersions = groupVersions(groups)
+	for _, g := range groups.Groups {
+		if strings.HasSuffix(g.Name, ".openshift.io") {
+			caps.OpenShift = true
+		}
+	}
+	return caps, nil
+}
+
+func groupVersions(groups *metav1.APIGroupList) []string {
+	versions := []string{}
+	for _, g := range groups.Groups {
+		for _, v := range g.Versions {
+			versions = append(versions, v.GroupVersion)
+		}
+	}
+	return versions
+}
+
+// templateFuncs are the sprig functions templates use the most, sprig itself isn't a dependency of the adapter.
+// include renders a partial and secret resolves a secret reference, they are bound to the template being
+// rendered by newTemplate.
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"default": func(def interface{}, given ...interface{}) interface{} {
+			if len(given) == 0 || isEmpty(given[0]) {
+				return def
+			}
+			return given[0]
+		},
+		"empty": isEmpty,
+		"required": func(msg string, v interface{}) (interface{}, error) {
+			if isEmpty(v) {
+				return nil, fmt.Errorf("%s", msg)
+			}
+			return v, nil
+		},
+		"ternary": func(yes, no interface{}, cond bool) interface{} {
+			if cond {
+				return yes
+			}
+			return no
+		},
+		"quote":      func(s interface{}) string { return fmt.Sprintf("%q", fmt.Sprint(s)) },
+		"squote":     func(s interface{}) string { return "'" + fmt.Sprint(s) + "'" },
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.Replace(s, old, new, -1) },
+		"contains":   func(sub, s string) bool { return strings.Contains(s, sub) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"indent":     indent,
+		"nindent":    func(spaces int, s string) string { return "\n" + indent(spaces, s) },
+		"b64enc":     func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+		"list":       func(items ...interface{}) []interface{} { return items },
+		"has": func(needle interface{}, haystack interface{}) bool {
+			v := reflect.ValueOf(haystack)
+			if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+				return false
+			}
+			for i := 0; i < v.Len(); i++ {
+				if reflect.DeepEqual(v.Index(i).Interface(), needle) {
+					return true
+				}
+			}
+			return false
+		},
+		"toYaml": func(v interface{}) (string, error) {
+			out, err := yaml.Marshal(v)
+			return strings.TrimSuffix(string(out), "\n"), err
+		},
+		"semverCompare": semverCompare,
+		"include": func(string, interface{}) (string, error) {
+			return "", fmt.Errorf("include is only available while rendering")
+		},
+		"secret": func(string) (string, error) {
+			return "", fmt.Errorf("secret is only available while rendering")
+		},
+	}
+}
+
+func isEmpty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() == 0
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	}
+	return reflect.DeepEqual(v, reflect.Zero(rv.Type()).Interface())
+}
+
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.Replace(s, "\n", "\n"+pad, -1)
+}
+
+// semverCompare checks a version against a constraint like ">=1.16", several constraints separated by
+// commas must all hold
+func semverCompare(constraint, version string) (bool, error) {
+	for _, c := range strings.Split(constraint, ",") {
+		c = strings.TrimSpace(c)
+		op := strings.TrimRight(c, "v0123456789.")
+		want := strings.TrimSpace(strings.TrimPrefix(c, op))
+		if !isRelease(want) {
+			return false, fmt.Errorf("semverCompare: %q is not a version constraint", c)
+		}
+		cmp := compareVersions(version, want)
+		var ok bool
+		switch strings.TrimSpace(op) {
+		case "", "=", "==":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		default:
+			return false, fmt.Errorf("semverCompare: unknown operator in %q", c)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// newTemplate creates a template with the template functions, its include renders the partials parsed into it
+// and its secret resolves references with the given resolver
+func newTemplate(name string, resolve secretResolver) *template.Template {
+	return bindTemplate(template.New(name).Option("missingkey=error").Funcs(templateFuncs()), resolve)
+}
+
+// bindTemplate points the include and secret functions of a template at the template and a resolver, a clone
+// of a parsed template is bound before it is executed
+func bindTemplate(tmpl *template.Template, resolve secretResolver) *template.Template {
+	return tmpl.Funcs(template.FuncMap{
+		"include": func(partial string, data interface{}) (string, error) {
+			buf := bytes.NewBufferString("")
+			err := tmpl.ExecuteTemplate(buf, partial, data)
+			return buf.String(), err
+		},
+		"secret": resolve,
+	})
+}
@@ -0,0 +1,419 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// builtinTemplatesDir holds the templates of the image, a synced catalog replaces them
+var builtinTemplatesDir = path.Join("octarine", "config_templates")
+
+// opsMu guards supportedOps, disabledOps and the templates directory, which a catalog sync replaces
+// while operations run
+var (
+	opsMu        sync.RWMutex
+	templatesDir = builtinTemplatesDir
+)
+
+// templateDirUses counts the renders reading each templates directory, a directory a catalog sync replaced
+// is removed once they are done
+var (
+	templateDirUsesMu sync.Mutex
+	templateDirUses   = map[string]*sync.WaitGroup{}
+)
+
+// templateParamSets are the parameters templates are linted with: the ones ApplyOperation passes them, on a
+// plain Kubernetes cluster and on an older OpenShift one so both sides of capability conditionals are rendered
+var templateParamSets = []map[string]interface{}{
+	{
+		"user_name": "meshery",
+		"namespace": "default",
+		"capabilities": templateCapabilities{
+			KubeVersion: "v1.16.0",
+			APIVersions: []string{"v1", "apps/v1", "admissionregistration.k8s.io/v1beta1", "networking.k8s.io/v1"},
+		},
+	},
+	{
+		"user_name": "",
+		"namespace": "octarine-lint",
+		"capabilities": templateCapabilities{
+			KubeVersion: "v1.11.0",
+			OpenShift:   true,
+			APIVersions: []string{"v1", "apps/v1", "admissionregistration.k8s.io/v1beta1", "route.openshift.io/v1", "security.openshift.io/v1"},
+		},
+	},
+}
+
+// disabledOps are the operations left out of supportedOps because their templates are broken
+var disabledOps = map[string]disabledOp{}
+
+type disabledOp struct {
+	op  supportedOperation
+	err error
+}
+
+// templateSet is a directory of templates, the unversioned templates of config_templates or a subdirectory
+// named after the first Octarine release its templates support, e.g. config_templates/1.10
+type templateSet struct {
+	root    string
+	version string
+	dir     string
+}
+
+func (s templateSet) String() string {
+	if s.version == "" {
+		return "unversioned"
+	}
+	return s.version
+}
+
+// templateSets lists the unversioned set of a templates directory and the versioned ones from the oldest release on
+func templateSets(root string) ([]templateSet, error) {
+	sets := []templateSet{{root: root, dir: root}}
+	entries, err := ioutil.ReadDir(root)
+	if os.IsNotExist(err) {
+		return sets, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the template sets")
+	}
+	versioned := []templateSet{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if !isRelease(e.Name()) {
+			logrus.Debugf("Ignored template directory %s, it isn't named after an Octarine release", e.Name())
+			continue
+		}
+		versioned = append(versioned, templateSet{root: root, version: normalizeVersion(e.Name()), dir: path.Join(root, e.Name())})
+	}
+	sort.Slice(versioned, func(i, j int) bool { return compareVersions(versioned[i].version, versioned[j].version) < 0 })
+	return append(sets, versioned...), nil
+}
+
+// templateSetsWith lists the sets which have a template
+func templateSetsWith(root, name string) ([]templateSet, error) {
+	sets, err := templateSets(root)
+	if err != nil {
+		return nil, err
+	}
+	found := []templateSet{}
+	for _, s := range sets {
+		if _, err := os.Stat(path.Join(s.dir, name)); err == nil {
+			found = append(found, s)
+		}
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("template %s is in no template set", name)
+	}
+	return found, nil
+}
+
+// selectTemplateSet picks the set of a template for an Octarine release: the one of the latest release not
+// after it, or the unversioned template when there is none. The latest set is picked when the release isn't known.
+func selectTemplateSet(root, name, version string) (templateSet, error) {
+	sets, err := templateSetsWith(root, name)
+	if err != nil {
+		return templateSet{}, err
+	}
+	var unversioned, selected *templateSet
+	for i, s := range sets {
+		switch {
+		case s.version == "":
+			unversioned = &sets[i]
+		case !isRelease(version) || compareVersions(s.version, version) <= 0:
+			selected = &sets[i]
+		}
+	}
+	if selected == nil {
+		selected = unversioned
+	}
+	if selected == nil {
+		return templateSet{}, fmt.Errorf("error: template %s has no set for Octarine %s, the oldest starts at %s", name, version, sets[0].version)
+	}
+	return *selected, nil
+}
+
+// templateVersion is the Octarine release a template operation renders for: the version of its custom body,
+// or the one of the deployment it targets, empty when neither is known
+func (oClient *Client) templateVersion(body string) (string, error) {
+	params, err := parseDeploymentParams(body)
+	if err != nil {
+		return "", err
+	}
+	if params.Version != "" {
+		return params.Version, nil
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		if params.Deployment != "" {
+			return "", err
+		}
+		return "", nil
+	}
+	return d.version, nil
+}
+
+// templatePartials are the files starting with an underscore of the unversioned set and of the given one,
+// their defines can be included by every template and the given set overrides the unversioned defines
+func templatePartials(set templateSet) ([]string, error) {
+	partials, err := filepath.Glob(path.Join(set.root, "_*"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the template partials")
+	}
+	if set.dir != set.root {
+		own, err := filepath.Glob(path.Join(set.dir, "_*"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to list the template partials")
+		}
+		partials = append(partials, own...)
+	}
+	return partials, nil
+}
+
+// renderTemplate renders a template of a set, referring to a parameter it wasn't given is an error. The
+// manifests of the renders referring to no secret are cached by their parameters.
+func renderTemplate(set templateSet, name string, params map[string]interface{}, resolve secretResolver) (string, error) {
+	partials, err := templatePartials(set)
+	if err != nil {
+		return "", err
+	}
+	files := append([]string{path.Join(set.dir, name)}, partials...)
+	fingerprint := ""
+	if renderCacheEnabled() {
+		// a missing file fails the parse below
+		fingerprint, _ = templateFingerprint(files)
+	}
+	key, cacheable := renderKey(fingerprint, params)
+	if cacheable {
+		if manifest, ok := renders.manifest(key); ok {
+			return manifest, nil
+		}
+	}
+	tmpl, err := renders.template(fingerprint, name, files)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to parse template")
+	}
+	// the secrets may change, and their values aren't kept in memory
+	referencesSecrets := false
+	bindTemplate(tmpl, func(ref string) (string, error) {
+		referencesSecrets = true
+		return resolve(ref)
+	})
+	buf := bytes.NewBufferString("")
+	if err := tmpl.Execute(buf, params); err != nil {
+		return "", errors.Wrapf(err, "unable to execute template")
+	}
+	if cacheable && !referencesSecrets {
+		renders.store(key, buf.String())
+	}
+	return buf.String(), nil
+}
+
+// lintTemplate renders a template of a set with every parameter set and checks it makes Kubernetes objects,
+// it returns the number of objects of the first parameter set
+func lintTemplate(set templateSet, name string) (int, error) {
+	count := 0
+	values, err := templateValues(set, nil)
+	if err != nil {
+		return 0, err
+	}
+	for i, paramSet := range templateParamSets {
+		params := map[string]interface{}{"values": values}
+		for k, v := range paramSet {
+			params[k] = v
+		}
+		// templates are linted without reaching the secret managers
+		manifest, err := renderTemplate(set, name, params, redactedSecrets)
+		if err != nil {
+			return 0, errors.Wrapf(err, "parameter set %d", i+1)
+		}
+		objects, err := parseManifestObjects(manifest)
+		if err != nil {
+			return 0, errors.Wrapf(err, "parameter set %d", i+1)
+		}
+		if len(objects) == 0 {
+			return 0, fmt.Errorf("parameter set %d: no objects", i+1)
+		}
+		for _, obj := range objects {
+			if obj.GetAPIVersion() == "" || obj.GetKind() == "" || obj.GetName() == "" {
+				return 0, fmt.Errorf("parameter set %d: an object lacks its apiVersion, kind or metadata.name", i+1)
+			}
+		}
+		if i == 0 {
+			count = len(objects)
+		}
+	}
+	return count, nil
+}
+
+// lintTemplateSets lints a template in every set which has it, the error is the first broken set's
+func lintTemplateSets(root, op, name string) ([]*meshes.TemplateLint, error) {
+	sets, err := templateSetsWith(root, name)
+	if err != nil {
+		return []*meshes.TemplateLint{{Operation: op, Template: name, Error: err.Error()}}, err
+	}
+	lints := []*meshes.TemplateLint{}
+	var broken error
+	for _, s := range sets {
+		lint := &meshes.TemplateLint{Operation: op, Template: name, TemplateSet: s.version}
+		count, err := lintTemplate(s, name)
+		if err != nil {
+			lint.Error = err.Error()
+			if broken == nil {
+				broken = errors.Wrapf(err, "%s set", s)
+			}
+		}
+		lint.Objects = int32(count)
+		lints = append(lints, lint)
+	}
+	return lints, broken
+}
+
+// DisableBrokenTemplates lints the templates of the supported operations and leaves out the operations
+// whose templates are broken, it is meant to be called once at startup
+func DisableBrokenTemplates() {
+	opsMu.Lock()
+	defer opsMu.Unlock()
+	for _, key := range sortedKeys(templateOps(supportedOps)) {
+		op := supportedOps[key]
+		if _, err := lintTemplateSets(templatesDir, key, op.templateName); err != nil {
+			err = errors.Wrapf(err, "template %s", op.templateName)
+			logrus.Errorf("Disabled operation %s: %v", key, err)
+			disabledOps[key] = disabledOp{op: op, err: err}
+			delete(supportedOps, key)
+		}
+	}
+}
+
+// templateOps are the operations rendering a template
+func templateOps(ops map[string]supportedOperation) map[string]bool {
+	keys := map[string]bool{}
+	for key, op := range ops {
+		if op.templateName != "" {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// lookupOp returns a supported operation
+func lookupOp(name string) (supportedOperation, bool) {
+	opsMu.RLock()
+	defer opsMu.RUnlock()
+	op, ok := supportedOps[name]
+	return op, ok
+}
+
+// supportedOpsSnapshot copies the supported operations for callers ranging over them
+func supportedOpsSnapshot() map[string]supportedOperation {
+	opsMu.RLock()
+	defer opsMu.RUnlock()
+	ops := make(map[string]supportedOperation, len(supportedOps))
+	for key, op := range supportedOps {
+		ops[key] = op
+	}
+	return ops
+}
+
+// activeTemplatesDir is the directory templates are rendered from, the image's or the synced catalog's
+func activeTemplatesDir() string {
+	opsMu.RLock()
+	defer opsMu.RUnlock()
+	return templatesDir
+}
+
+// useTemplatesDir is the directory templates are rendered from, the caller calls release once it is done
+// reading it so a catalog sync doesn't remove it in the meantime
+func useTemplatesDir() (string, func()) {
+	opsMu.RLock()
+	defer opsMu.RUnlock()
+	dir := templatesDir
+	templateDirUsesMu.Lock()
+	uses, ok := templateDirUses[dir]
+	if !ok {
+		uses = &sync.WaitGroup{}
+		templateDirUses[dir] = uses
+	}
+	uses.Add(1)
+	templateDirUsesMu.Unlock()
+	return dir, uses.Done
+}
+
+// awaitTemplatesDir waits for the renders of a directory no longer in use to finish
+func awaitTemplatesDir(dir string) {
+	templateDirUsesMu.Lock()
+	uses, ok := templateDirUses[dir]
+	delete(templateDirUses, dir)
+	templateDirUsesMu.Unlock()
+	if ok {
+		uses.Wait()
+	}
+}
+
+// disabledOpCause tells why an operation was disabled, nil when it wasn't
+func disabledOpCause(name string) error {
+	opsMu.RLock()
+	defer opsMu.RUnlock()
+	if d, ok := disabledOps[name]; ok {
+		return d.err
+	}
+	return nil
+}
+
+// LintTemplates renders the templates of the operations again, including the disabled ones,
+// to check them after they were edited without restarting the adapter, and reports the catalog sync
+func (oClient *Client) LintTemplates(context.Context, *meshes.LintTemplatesRequest) (*meshes.LintTemplatesResponse, error) {
+	opsMu.RLock()
+	ops := map[string]supportedOperation{}
+	for key, op := range supportedOps {
+		if op.templateName != "" {
+			ops[key] = op
+		}
+	}
+	disabled := map[string]bool{}
+	for key, d := range disabledOps {
+		ops[key] = d.op
+		disabled[key] = true
+	}
+	opsMu.RUnlock()
+	root, release := useTemplatesDir()
+	defer release()
+
+	resp := &meshes.LintTemplatesResponse{}
+	for _, key := range sortedKeys(templateOps(ops)) {
+		lints, _ := lintTemplateSets(root, key, ops[key].templateName)
+		for _, lint := range lints {
+			lint.Disabled = disabled[key]
+		}
+		resp.Templates = append(resp.Templates, lints...)
+	}
+	catalogSummary(resp)
+	return resp, nil
+}
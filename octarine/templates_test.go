@@ -0,0 +1,95 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+)
+
+// the tests run in the directory of the package, builtinTemplatesDir is relative to the one of the adapter
+const testTemplatesDir = "config_templates"
+
+var updateGolden = flag.Bool("update", false, "write the rendered templates to their golden files")
+
+// TestTemplatesGolden renders the template of every supported operation, in every set which has it, with
+// every parameter set it is linted with, and compares the manifests to testdata/<template>.<set>.<n>.golden
+func TestTemplatesGolden(t *testing.T) {
+	ops := supportedOpsSnapshot()
+	keys := sortedKeys(templateOps(ops))
+	if len(keys) == 0 {
+		t.Fatal("no supported operation renders a template")
+	}
+	for _, key := range keys {
+		name := ops[key].templateName
+		sets, err := templateSetsWith(testTemplatesDir, name)
+		if err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		for _, set := range sets {
+			values, err := templateValues(set, nil)
+			if err != nil {
+				t.Fatalf("%s: %v", key, err)
+			}
+			for i, paramSet := range templateParamSets {
+				golden := path.Join("testdata", fmt.Sprintf("%s.%s.%d.golden", strings.TrimSuffix(name, path.Ext(name)), set, i+1))
+				t.Run(golden, func(t *testing.T) {
+					params := map[string]interface{}{"values": values}
+					for k, v := range paramSet {
+						params[k] = v
+					}
+					manifest, err := renderTemplate(set, name, params, redactedSecrets)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if *updateGolden {
+						if err := ioutil.WriteFile(golden, []byte(manifest), 0644); err != nil {
+							t.Fatal(err)
+						}
+						return
+					}
+					want, err := ioutil.ReadFile(golden)
+					if err != nil {
+						t.Fatalf("%v, run the tests with -update to write it", err)
+					}
+					if manifest != string(want) {
+						t.Errorf("%s rendered\n%s\nwant\n%s", name, manifest, want)
+					}
+				})
+			}
+		}
+	}
+}
+
+// TestLintTemplates checks the templates of the supported operations pass the startup lint
+func TestLintTemplates(t *testing.T) {
+	ops := supportedOpsSnapshot()
+	for _, key := range sortedKeys(templateOps(ops)) {
+		lints, err := lintTemplateSets(testTemplatesDir, key, ops[key].templateName)
+		if err != nil {
+			t.Errorf("%s: %v", key, err)
+			continue
+		}
+		for _, lint := range lints {
+			if lint.Objects == 0 {
+				t.Errorf("%s: the %q set renders no objects", key, lint.TemplateSet)
+			}
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	eventDedupWindowEnv     = "OCTARINE_EVENT_DEDUP_WINDOW"
+	eventRateEnv            = "OCTARINE_EVENT_RATE"
+	defaultEventDedupWindow = 10 * time.Minute
+	// defaultEventRate is how many events a source may emit per minute
+	defaultEventRate = 30
+	// throttleFlushInterval is how often the repeats and the rate limited events are reported
+	throttleFlushInterval = 30 * time.Second
+
+	sourceWebhookProbe = "webhook-probe"
+	sourceConnectivity = "connectivity"
+	sourceLicense      = "license"
+)
+
+// eventThrottle keeps the watchers from flooding the streams: an event of a source is let through once per
+// dedup window, how often it repeated meanwhile is reported when the window closes, and a source emitting
+// more than its rate has its events dropped and counted. The events of operations have no source and are
+// never throttled.
+type eventThrottle struct {
+	window time.Duration
+	rate   int
+	// sources are only used from the goroutine of the broker
+	sources map[string]*sourceThrottle
+}
+
+type sourceThrottle struct {
+	tokens  float64
+	updated time.Time
+	// limited counts the events dropped for the rate since they were last reported
+	limited int
+	seen    map[eventKey]*seenEvent
+}
+
+type eventKey struct {
+	severity meshes.Severity
+	summary  string
+	details  string
+}
+
+type seenEvent struct {
+	event *meshes.EventsResponse
+	at    time.Time
+	// last is when the event was last suppressed
+	last    time.Time
+	repeats int
+}
+
+func newEventThrottle() *eventThrottle {
+	t := &eventThrottle{sources: map[string]*sourceThrottle{}}
+	// 0 turns deduplication or rate limiting off, the env helpers would take it as invalid
+	if os.Getenv(eventDedupWindowEnv) != "0" {
+		t.window = durationFromEnv(eventDedupWindowEnv, defaultEventDedupWindow)
+	}
+	if os.Getenv(eventRateEnv) != "0" {
+		t.rate = intFromEnv(eventRateEnv, defaultEventRate)
+	}
+	return t
+}
+
+// admit returns the events to publish for an event: none when it is throttled, the event itself, preceded by
+// the report of its repeats when its last dedup window just closed
+func (t *eventThrottle) admit(event *meshes.EventsResponse, now time.Time) []*meshes.EventsResponse {
+	if event.GetSource() == "" {
+		return []*meshes.EventsResponse{event}
+	}
+	s := t.source(event.GetSource(), now)
+	published := []*meshes.EventsResponse{}
+	key := eventKey{severity: event.GetSeverity(), summary: event.GetSummary(), details: event.GetDetails()}
+	if t.window > 0 {
+		if seen := s.seen[key]; seen != nil {
+			if now.Sub(seen.at) < t.window {
+				seen.repeats++
+				seen.last = now
+				return nil
+			}
+			if seen.repeats > 0 {
+				published = append(published, t.repeated(seen))
+			}
+			delete(s.seen, key)
+		}
+	}
+	if t.rate > 0 {
+		if s.tokens < 1 {
+			s.limited++
+			return published
+		}
+		s.tokens--
+	}
+	if t.window > 0 {
+		s.seen[key] = &seenEvent{event: event, at: now}
+	}
+	return append(published, event)
+}
+
+// source refills the rate of a source for the time passed, a source may emit a minute worth of events at once
+func (t *eventThrottle) source(name string, now time.Time) *sourceThrottle {
+	s := t.sources[name]
+	if s == nil {
+		s = &sourceThrottle{tokens: float64(t.rate), updated: now, seen: map[eventKey]*seenEvent{}}
+		t.sources[name] = s
+		return s
+	}
+	s.tokens += now.Sub(s.updated).Minutes() * float64(t.rate)
+	if s.tokens > float64(t.rate) {
+		s.tokens = float64(t.rate)
+	}
+	s.updated = now
+	return s
+}
+
+// flush reports the repeats of the events whose dedup window closed and the events dropped for the rate of
+// their source, so a flood which stopped is still accounted for
+func (t *eventThrottle) flush(now time.Time) []*meshes.EventsResponse {
+	if len(t.sources) == 0 {
+		return nil
+	}
+	names := map[string]bool{}
+	for name := range t.sources {
+		names[name] = true
+	}
+	published := []*meshes.EventsResponse{}
+	for _, name := range sortedKeys(names) {
+		s := t.source(name, now)
+		closed := []*seenEvent{}
+		for key, seen := range s.seen {
+			if now.Sub(seen.at) < t.window {
+				continue
+			}
+			if seen.repeats > 0 {
+				closed = append(closed, seen)
+			}
+			delete(s.seen, key)
+		}
+		sort.Slice(closed, func(i, j int) bool { return closed[i].at.Before(closed[j].at) })
+		for _, seen := range closed {
+			published = append(published, t.repeated(seen))
+		}
+		if s.limited > 0 {
+			logrus.Warnf("Dropped %d event(s) of %s over its rate of %d per minute", s.limited, name, t.rate)
+			published = append(published, &meshes.EventsResponse{
+				EventType: meshes.EventType_WARN,
+				Severity:  meshes.Severity_SEVERITY_WARN,
+				Summary:   fmt.Sprintf("Dropped %d event(s) of %s", s.limited, name),
+				Details:   fmt.Sprintf("It emitted more than %d events per minute, set %s to change the rate.", t.rate, eventRateEnv),
+				Source:    name,
+			})
+			s.limited = 0
+		}
+		if len(s.seen) == 0 && s.tokens >= float64(t.rate) {
+			delete(t.sources, name)
+		}
+	}
+	return published
+}
+
+// repeated reports how often an event was suppressed within its dedup window, the first occurrence having
+// been published
+func (t *eventThrottle) repeated(seen *seenEvent) *meshes.EventsResponse {
+	details := fmt.Sprintf("Repeated %d more time(s) between %s and %s.", seen.repeats,
+		seen.at.UTC().Format(time.RFC3339), seen.last.UTC().Format(time.RFC3339))
+	if seen.event.GetDetails() != "" {
+		details = seen.event.GetDetails() + "\n" + details
+	}
+	return &meshes.EventsResponse{
+		EventType: seen.event.GetEventType(),
+		Severity:  seen.event.GetSeverity(),
+		Summary:   seen.event.GetSummary(),
+		Details:   details,
+		Source:    seen.event.GetSource(),
+	}
+}
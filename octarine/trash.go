@@ -0,0 +1,310 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	trashRetentionEnv     = "OCTARINE_TRASH_RETENTION"
+	defaultTrashRetention = 30 * 24 * time.Hour
+
+	// trashLabel marks the ConfigMaps holding a deleted policy, one per policy
+	trashLabel   = "octarine.io/trashed-policy"
+	trashDataKey = "policy.json"
+	// trashIDLength is how much of a random id names a deleted policy
+	trashIDLength = 12
+)
+
+// trashedPolicy is a copy of an Octarine policy taken before an operation deleted it, kept in a ConfigMap of the
+// dataplane namespace for OCTARINE_TRASH_RETENTION so an accidental removal can be undone
+type trashedPolicy struct {
+	ID          string    `json:"id"`
+	Deployment  string    `json:"deployment"`
+	Namespace   string    `json:"namespace,omitempty"`
+	Name        string    `json:"name"`
+	Kind        string    `json:"kind,omitempty"`
+	Manifest    string    `json:"manifest"`
+	Deleted     time.Time `json:"deleted"`
+	DeletedBy   string    `json:"deletedBy,omitempty"`
+	OperationID string    `json:"operationId,omitempty"`
+}
+
+func trashName(id string) string {
+	return resourceName("octarine-trash-" + id)
+}
+
+func (t *trashedPolicy) message(withManifest bool) *meshes.TrashedPolicy {
+	p := &meshes.TrashedPolicy{
+		Id:          t.ID,
+		Deployment:  t.Deployment,
+		Namespace:   t.Namespace,
+		Name:        t.Name,
+		Kind:        t.Kind,
+		Deleted:     t.Deleted.Format(time.RFC3339),
+		Expires:     t.Deleted.Add(durationFromEnv(trashRetentionEnv, defaultTrashRetention)).Format(time.RFC3339),
+		DeletedBy:   t.DeletedBy,
+		OperationId: t.OperationID,
+	}
+	if withManifest {
+		p.Manifest = t.Manifest
+	}
+	return p
+}
+
+// policyCommand is an octactl policy command on a namespace of the domain of a deployment, or on the whole
+// domain when the namespace is empty
+func policyCommand(ctx context.Context, d *deployment, namespace, verb string, args ...string) *exec.Cmd {
+	args = append([]string{"policy", verb, d.domain}, args...)
+	if namespace != "" {
+		args = append(args, "--k8s-namespace", namespace)
+	}
+	return exec.CommandContext(ctx, "octactl", args...)
+}
+
+// policyManifest is a policy of the domain as octactl prints it
+func (oClient *Client) policyManifest(ctx context.Context, d *deployment, namespace, name string) (string, error) {
+	var out []byte
+	err := oClient.asAccount(ctx, d, func() error {
+		var err error
+		out, err = policyCommand(ctx, d, namespace, "get", name, "--output", "yaml").Output()
+		return err
+	})
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			logrus.Errorf("Command finished with error: %v: %s", err, exitErr.Stderr)
+		}
+		return "", errors.Wrapf(err, "unable to get policy %s", name)
+	}
+	return string(out), nil
+}
+
+// deletePolicy deletes a policy of the domain of a deployment, after keeping a copy of it in the trash. A policy
+// which can't be copied isn't deleted.
+func (oClient *Client) deletePolicy(ctx context.Context, d *deployment, namespace, name string) error {
+	manifest, err := oClient.policyManifest(ctx, d, namespace, name)
+	if err != nil {
+		return errors.Wrapf(err, "unable to keep a copy of policy %s in the trash, it was not deleted", name)
+	}
+	entry := &trashedPolicy{
+		ID:          strings.Replace(newOperationID(), "-", "", -1)[:trashIDLength],
+		Deployment:  d.name,
+		Namespace:   namespace,
+		Name:        name,
+		Manifest:    manifest,
+		Deleted:     time.Now().UTC(),
+		OperationID: operationIDFrom(ctx),
+	}
+	header := struct {
+		Kind string `json:"kind"`
+	}{}
+	if err := yaml.Unmarshal([]byte(manifest), &header); err == nil {
+		entry.Kind = header.Kind
+	}
+	if r := resultFrom(ctx); r != nil {
+		entry.DeletedBy = r.Username
+	}
+	if err := oClient.saveTrashedPolicy(ctx, entry); err != nil {
+		return errors.Wrapf(err, "unable to keep a copy of policy %s in the trash, it was not deleted", name)
+	}
+
+	var out []byte
+	err = oClient.asAccount(ctx, d, func() error {
+		var err error
+		out, err = policyCommand(ctx, d, namespace, "delete", name).CombinedOutput()
+		return err
+	})
+	if err != nil {
+		logrus.Errorf("Command finished with error: %v: %s", err, out)
+		// the policy is still there
+		oClient.discardTrashedPolicy(ctx, entry.ID)
+		err = errors.Wrapf(err, "unable to remove policy %s of domain %s", name, d.domain)
+		recordAudit(auditEntry{User: entry.DeletedBy, Action: "policy.delete", Deployment: d.name, Target: name}, err)
+		return err
+	}
+	recordAudit(auditEntry{
+		User:       entry.DeletedBy,
+		Action:     "policy.delete",
+		Deployment: d.name,
+		Target:     name,
+		Details:    fmt.Sprintf("kept in the trash as %s", entry.ID),
+	}, nil)
+	oClient.pruneTrash(ctx)
+	return nil
+}
+
+func (oClient *Client) saveTrashedPolicy(ctx context.Context, entry *trashedPolicy) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      trashName(entry.ID),
+			Namespace: dataplaneNamespace(),
+			Labels:    map[string]string{managedByLabel: managedByValue, trashLabel: "true"},
+		},
+		Data: map[string]string{trashDataKey: string(data)},
+	}
+	_, err = oClient.clientset(ctx).CoreV1().ConfigMaps(cm.Namespace).Create(cm)
+	return err
+}
+
+func (oClient *Client) discardTrashedPolicy(ctx context.Context, id string) {
+	ns := dataplaneNamespace()
+	if err := oClient.clientset(ctx).CoreV1().ConfigMaps(ns).Delete(trashName(id), &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		logrus.Warnf("Unable to delete the trashed policy %s/%s: %v", ns, trashName(id), err)
+	}
+}
+
+// loadTrash reads the policies of the trash, their unexpired ones by id
+func (oClient *Client) loadTrash(ctx context.Context) (map[string]*trashedPolicy, error) {
+	ns := dataplaneNamespace()
+	list, err := oClient.clientset(ctx).CoreV1().ConfigMaps(ns).List(metav1.ListOptions{LabelSelector: trashLabel + "=true"})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the trashed policies in namespace %s", ns)
+	}
+	retention := durationFromEnv(trashRetentionEnv, defaultTrashRetention)
+	trash := map[string]*trashedPolicy{}
+	for _, cm := range list.Items {
+		entry := &trashedPolicy{}
+		if err := json.Unmarshal([]byte(cm.Data[trashDataKey]), entry); err != nil {
+			logrus.Warnf("Unable to parse the trashed policy in %s/%s: %v", ns, cm.GetName(), err)
+			continue
+		}
+		if time.Since(entry.Deleted) > retention {
+			continue
+		}
+		trash[entry.ID] = entry
+	}
+	return trash, nil
+}
+
+// pruneTrash deletes the policies deleted longer than OCTARINE_TRASH_RETENTION ago
+func (oClient *Client) pruneTrash(ctx context.Context) {
+	retention := durationFromEnv(trashRetentionEnv, defaultTrashRetention)
+	ns := dataplaneNamespace()
+	configMaps := oClient.clientset(ctx).CoreV1().ConfigMaps(ns)
+	list, err := configMaps.List(metav1.ListOptions{LabelSelector: trashLabel + "=true"})
+	if err != nil {
+		logrus.Warnf("Unable to list the trashed policies in namespace %s: %v", ns, err)
+		return
+	}
+	for _, cm := range list.Items {
+		if time.Since(cm.GetCreationTimestamp().Time) <= retention {
+			continue
+		}
+		if err := configMaps.Delete(cm.GetName(), &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			logrus.Warnf("Unable to delete the expired trashed policy %s/%s: %v", ns, cm.GetName(), err)
+		}
+	}
+}
+
+// ListTrashedPolicies lists the policies the operations deleted which can still be restored
+func (oClient *Client) ListTrashedPolicies(ctx context.Context, req *meshes.ListTrashedPoliciesRequest) (*meshes.ListTrashedPoliciesResponse, error) {
+	if name := req.GetCluster(); name != oClient.cluster {
+		target, err := oClient.clusterClient(name)
+		if err != nil {
+			return &meshes.ListTrashedPoliciesResponse{Error: err.Error()}, nil
+		}
+		return target.ListTrashedPolicies(ctx, req)
+	}
+	if oClient.clientset(ctx) == nil {
+		return &meshes.ListTrashedPoliciesResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	trash, err := oClient.loadTrash(ctx)
+	if err != nil {
+		return &meshes.ListTrashedPoliciesResponse{Error: err.Error()}, nil
+	}
+	keys := []string{}
+	byKey := map[string]*trashedPolicy{}
+	for _, entry := range trash {
+		if (req.GetDeployment() != "" && entry.Deployment != req.GetDeployment()) ||
+			(req.GetNamespace() != "" && entry.Namespace != req.GetNamespace()) {
+			continue
+		}
+		key := entry.Deleted.Format(eventKeyTime) + "/" + entry.ID
+		keys = append(keys, key)
+		byKey[key] = entry
+	}
+	sort.Strings(keys)
+	start, end, next, err := paginate(keys, req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return &meshes.ListTrashedPoliciesResponse{Error: err.Error()}, nil
+	}
+	resp := &meshes.ListTrashedPoliciesResponse{NextPageToken: next}
+	for _, key := range keys[start:end] {
+		resp.Policies = append(resp.Policies, byKey[key].message(false))
+	}
+	return resp, nil
+}
+
+// RestorePolicy applies a deleted policy again to the domain it was deleted from, and takes it out of the trash.
+// A policy of the same name created since is replaced.
+func (oClient *Client) RestorePolicy(ctx context.Context, req *meshes.RestorePolicyRequest) (*meshes.RestorePolicyResponse, error) {
+	if name := req.GetCluster(); name != oClient.cluster {
+		target, err := oClient.clusterClient(name)
+		if err != nil {
+			return &meshes.RestorePolicyResponse{Error: err.Error()}, nil
+		}
+		return target.RestorePolicy(ctx, req)
+	}
+	if oClient.clientset(ctx) == nil {
+		return &meshes.RestorePolicyResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	trash, err := oClient.loadTrash(ctx)
+	if err != nil {
+		return &meshes.RestorePolicyResponse{Error: err.Error()}, nil
+	}
+	entry, ok := trash[req.GetId()]
+	if !ok {
+		retention := durationFromEnv(trashRetentionEnv, defaultTrashRetention)
+		return &meshes.RestorePolicyResponse{Error: fmt.Sprintf("error: no trashed policy %s, it is unknown, restored or deleted more than %s ago", req.GetId(), retention)}, nil
+	}
+	audit := auditEntry{User: req.GetUsername(), Action: "policy.restore", Deployment: entry.Deployment, Target: entry.Name, Details: "from the trash " + entry.ID}
+	d, err := oClient.getDeployment(entry.Deployment)
+	if err == nil {
+		err = oClient.asAccount(ctx, d, func() error {
+			cmd := policyCommand(ctx, d, entry.Namespace, "apply", "-f", "-")
+			cmd.Stdin = strings.NewReader(entry.Manifest)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				logrus.Errorf("Command finished with error: %v: %s", err, out)
+				return errors.Wrapf(err, "unable to restore policy %s", entry.Name)
+			}
+			return nil
+		})
+	}
+	recordAudit(audit, err)
+	if err != nil {
+		return &meshes.RestorePolicyResponse{Policy: entry.message(true), Error: err.Error()}, nil
+	}
+	oClient.discardTrashedPolicy(ctx, entry.ID)
+	return &meshes.RestorePolicyResponse{Policy: entry.message(true)}, nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// trialControlPlaneEnv is the control plane trial accounts are signed up with, the one of the adapter when unset
+const trialControlPlaneEnv = "OCTARINE_TRIAL_CP"
+
+// accountLogin are the credentials octactl logs in to an account with
+type accountLogin struct {
+	controlPlane string
+	username     string
+	password     string
+}
+
+// trialAccount is a trial account as the control plane signs it up
+type trialAccount struct {
+	Account  string `json:"account"`
+	Domain   string `json:"domain"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Expires is the RFC 3339 time the control plane deletes the account at
+	Expires string `json:"expires"`
+}
+
+func (oClient *Client) trialControlPlane() string {
+	if cp := os.Getenv(trialControlPlaneEnv); cp != "" {
+		return cp
+	}
+	oClient.loadControlPlaneCredentials()
+	return oClient.octarineControlPlane
+}
+
+// validateTrialParams checks the custom body of octarine_trial
+func validateTrialParams(params *deploymentParams, deleteOp bool) error {
+	if deleteOp {
+		return nil
+	}
+	if params.Email == "" {
+		return fmt.Errorf("error: the email to sign the trial up for is required, set email")
+	}
+	if addr, err := mail.ParseAddress(params.Email); err != nil || addr.Address != params.Email {
+		return fmt.Errorf("error: email %q is not an email address", params.Email)
+	}
+	return nil
+}
+
+// executeTrial signs an Octarine trial account up with the control plane for an email, keeps its credentials
+// in the bootstrap Secret of the namespace and installs the dataplane of the deployment with it. A namespace
+// holding a trial already is installed with it, so a failed install can be retried without a second sign-up.
+func (oClient *Client) executeTrial(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	if oClient.clientset(ctx) == nil {
+		return errors.New("mesh client has not been created")
+	}
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	name := params.Deployment
+	if name == "" {
+		name = defaultDeploymentName
+	}
+	namespace := arReq.GetNamespace()
+	if namespace == "" {
+		namespace = dataplaneNamespace()
+	}
+	if arReq.GetDeleteOp() {
+		return oClient.deleteTrial(ctx, arReq, name, namespace)
+	}
+
+	b, err := oClient.loadBootstrap(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	switch {
+	case b == nil:
+		if b, err = oClient.signUpTrial(ctx, name, namespace, params.Email); err != nil {
+			return err
+		}
+	case !b.trial:
+		return fmt.Errorf("error: namespace %s is bootstrapped for deployment %s without a trial", namespace, b.deployment)
+	case b.deployment != name:
+		return fmt.Errorf("error: namespace %s holds the trial of deployment %s", namespace, b.deployment)
+	}
+
+	if err := oClient.installDeployment(ctx, name, namespace, b.domain, params.Version, params.Certificates, params.Mirror, params.Replicas); err != nil {
+		return errors.Wrapf(err, "the trial account %s was signed up but its dataplane wasn't installed, run the operation again to retry", b.account)
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_INFO,
+		Summary:     fmt.Sprintf("The Octarine trial of deployment %s is ready", name),
+		Details: fmt.Sprintf("Control plane: %s\nAccount: %s\nDomain: %s\nExpires: %s\nThe dataplane runs in namespace %s. The credentials of the account are in the keys %s and %s of the Secret %s/%s",
+			b.controlPlane, b.account, b.domain, b.expires, namespace, bootstrapUsernameKey, bootstrapPasswordKey, namespace, resourceName(bootstrapSecretName)),
+	}
+	return nil
+}
+
+// signUpTrial signs a trial account up with the control plane and stores it as the bootstrap of the namespace
+func (oClient *Client) signUpTrial(ctx context.Context, name, namespace, email string) (*bootstrap, error) {
+	controlPlane := oClient.trialControlPlane()
+	if controlPlane == "" {
+		return nil, fmt.Errorf("error: no control plane to sign the trial up with, set %s or OCTARINE_CP", trialControlPlaneEnv)
+	}
+	d := &deployment{name: name, namespace: namespace}
+	_, err := oClient.clientset(ctx).CoreV1().Namespaces().Create(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Labels: d.managedLabels()},
+	})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, errors.Wrapf(err, "unable to create namespace %s", namespace)
+	}
+	progressed(ctx)
+
+	workingOn(ctx, "signing up an Octarine trial for %s", email)
+	cmd := exec.CommandContext(ctx, "octactl", "trial", "create", controlPlane, "--email", email, "--output", "json")
+	out, err := cmd.Output()
+	if err != nil {
+		logrus.Errorf("Command finished with error: %v", err)
+		return nil, errors.Wrapf(err, "unable to sign up an Octarine trial for %s", email)
+	}
+	trial := &trialAccount{}
+	if err := json.Unmarshal(out, trial); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the trial account signed up for %s", email)
+	}
+	if trial.Account == "" || trial.Domain == "" || trial.Username == "" {
+		return nil, fmt.Errorf("error: the control plane signed up a trial for %s without an account, a domain or a user", email)
+	}
+	if _, err := time.Parse(time.RFC3339, trial.Expires); err != nil {
+		return nil, errors.Wrapf(err, "the trial account %s expires at an invalid time", trial.Account)
+	}
+	progressed(ctx)
+
+	b := &bootstrap{
+		controlPlane: controlPlane,
+		account:      trial.Account,
+		domain:       trial.Domain,
+		deployment:   name,
+		trial:        true,
+		username:     trial.Username,
+		password:     trial.Password,
+		expires:      trial.Expires,
+	}
+	_, err = oClient.clientset(ctx).CoreV1().Secrets(namespace).Create(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: resourceName(bootstrapSecretName), Namespace: namespace, Labels: d.managedLabels()},
+		Type:       corev1.SecretTypeOpaque,
+		StringData: map[string]string{
+			bootstrapControlPlaneKey: b.controlPlane,
+			bootstrapAccountKey:      b.account,
+			bootstrapDomainKey:       b.domain,
+			bootstrapDeploymentKey:   b.deployment,
+			bootstrapUsernameKey:     b.username,
+			bootstrapPasswordKey:     b.password,
+			bootstrapTrialKey:        "true",
+			bootstrapExpiresKey:      b.expires,
+			bootstrapEmailKey:        email,
+		},
+	})
+	if err != nil {
+		// the trial can't be deleted with its own credentials, it is left to expire
+		return nil, errors.Wrapf(err, "unable to create the bootstrap Secret in namespace %s, the trial account %s signed up for %s is left to expire on %s",
+			namespace, trial.Account, email, trial.Expires)
+	}
+	logrus.Infof("Signed up trial account %s and domain %s for deployment %s, it expires on %s", b.account, b.domain, name, b.expires)
+	return b, nil
+}
+
+// deleteTrial uninstalls the dataplane of a trial and deletes its Secret, the account is left to expire
+func (oClient *Client) deleteTrial(ctx context.Context, arReq *meshes.ApplyRuleRequest, name, namespace string) error {
+	b, err := oClient.loadBootstrap(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	if b == nil || !b.trial {
+		return fmt.Errorf("error: namespace %s holds no trial", namespace)
+	}
+	if d, err := oClient.getDeployment(b.deployment); err == nil && d.namespace == namespace {
+		if err := oClient.uninstallDeployment(ctx, d.name, namespace); err != nil {
+			return err
+		}
+	}
+	return oClient.deleteBootstrap(ctx, arReq, name, namespace)
+}
@@ -0,0 +1,426 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// the API server rejects objects over 1.5MiB, a bigger body can't be anything but a mistake
+	maxCustomBodySize = 3 << 20
+	maxKubeconfigSize = 1 << 20
+)
+
+// manifestBodyOps take a Kubernetes manifest as their custom body, the other operations take deploymentParams
+var manifestBodyOps = map[string]bool{
+	customOpCommand:      true,
+	customLabelDeleteOp:  true,
+	admissionTestCommand: true,
+}
+
+// ValidationInterceptor rejects malformed requests with InvalidArgument before they reach the handler
+func ValidationInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := ValidateRequest(req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// ValidationStreamInterceptor rejects the malformed request a stream is opened with, with InvalidArgument
+func ValidationStreamInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &validatedStream{ServerStream: ss})
+}
+
+// validatedStream validates the first message received, the request of a server streaming call
+type validatedStream struct {
+	grpc.ServerStream
+	received bool
+}
+
+func (s *validatedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.received {
+		return nil
+	}
+	s.received = true
+	return ValidateRequest(m)
+}
+
+func invalidArgument(format string, args ...interface{}) error {
+	return status.Errorf(codes.InvalidArgument, format, args...)
+}
+
+// validateName checks a name that ends up in the name or the labels of Kubernetes resources
+func validateName(field, value string) error {
+	if value == "" {
+		return nil
+	}
+	if errs := validation.IsDNS1123Label(value); len(errs) > 0 {
+		return invalidArgument("%s %q is not a valid name: %s", field, value, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// ValidateRequest checks the fields of a request which would otherwise fail deep into an operation
+func ValidateRequest(req interface{}) error {
+	if r, ok := req.(interface{ GetNamespace() string }); ok {
+		if err := validateName("namespace", r.GetNamespace()); err != nil {
+			return err
+		}
+	}
+	if r, ok := req.(interface{ GetCluster() string }); ok {
+		if err := validateName("cluster", r.GetCluster()); err != nil {
+			return err
+		}
+	}
+	if r, ok := req.(interface{ GetDeployment() string }); ok {
+		if err := validateName("deployment", r.GetDeployment()); err != nil {
+			return err
+		}
+	}
+	if r, ok := req.(interface {
+		GetPageSize() int32
+		GetPageToken() string
+	}); ok {
+		if err := validatePage(r.GetPageSize(), r.GetPageToken()); err != nil {
+			return err
+		}
+	}
+	switch r := req.(type) {
+	case *meshes.CreateMeshInstanceRequest:
+		if r.GetServer() != "" {
+			return validateServiceAccount(r)
+		}
+		return validateKubeconfig(r.GetK8SConfig(), r.GetContextName())
+	case *meshes.ApplyRuleRequest:
+		return validateOperation(r)
+	case *meshes.RenderOperationRequest:
+		return validateOperation(&meshes.ApplyRuleRequest{
+			OpName:     r.GetOpName(),
+			Namespace:  r.GetNamespace(),
+			Username:   r.GetUsername(),
+			CustomBody: r.GetCustomBody(),
+			DeleteOp:   r.GetDeleteOp(),
+			Cluster:    r.GetCluster(),
+			Parameters: r.GetParameters(),
+		})
+	case *meshes.EventsRequest:
+		if _, ok := meshes.Severity_name[int32(r.GetMinSeverity())]; !ok {
+			return invalidArgument("min_severity %d is not a known severity", r.GetMinSeverity())
+		}
+	case *meshes.QueryEventsRequest:
+		if _, ok := meshes.Severity_name[int32(r.GetMinSeverity())]; !ok {
+			return invalidArgument("min_severity %d is not a known severity", r.GetMinSeverity())
+		}
+		if _, err := time.Parse(time.RFC3339, r.GetSince()); r.GetSince() != "" && err != nil {
+			return invalidArgument("since %q is not an RFC 3339 time", r.GetSince())
+		}
+		if _, err := time.Parse(time.RFC3339, r.GetUntil()); r.GetUntil() != "" && err != nil {
+			return invalidArgument("until %q is not an RFC 3339 time", r.GetUntil())
+		}
+		if err := validatePage(r.GetPageSize(), r.GetPageToken()); err != nil {
+			return err
+		}
+	case *meshes.GetOperationResultRequest:
+		if r.GetOperationId() == "" {
+			return invalidArgument("an operation id is required")
+		}
+	case *meshes.LatencyProbeReportRequest:
+		if r.GetNamespace() == "" {
+			return invalidArgument("a namespace is required")
+		}
+	case *meshes.WorkloadIdentitiesRequest:
+		if r.GetNamespace() == "" {
+			return invalidArgument("a namespace is required")
+		}
+		return validateName("namespace", r.GetNamespace())
+	case *meshes.EstimateFootprintRequest:
+		for _, ns := range r.GetNamespaces() {
+			if err := validateName("namespace", ns); err != nil {
+				return err
+			}
+		}
+		for field, value := range map[string]string{"sidecar_cpu": r.GetSidecarCpu(), "sidecar_memory": r.GetSidecarMemory()} {
+			if _, err := resource.ParseQuantity(value); value != "" && err != nil {
+				return invalidArgument("%s %q is not a quantity like 100m or 128Mi", field, value)
+			}
+		}
+		if len(r.GetManifest()) > maxCustomBodySize {
+			return invalidArgument("the manifest is %d bytes, at most %d are accepted", len(r.GetManifest()), maxCustomBodySize)
+		}
+	case *meshes.ImageManifestsRequest:
+		for _, image := range r.GetImages() {
+			if image == "" || strings.ContainsAny(image, " \t\n") {
+				return invalidArgument("image %q is not an image reference", image)
+			}
+		}
+	case *meshes.ScheduleOperationRequest:
+		if r.GetName() == "" {
+			return invalidArgument("the name of the schedule is required")
+		}
+		if r.GetOperation() == nil {
+			return invalidArgument("the operation to schedule is required")
+		}
+		if _, err := parseCron(r.GetCron()); err != nil {
+			return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+		}
+		return validateOperation(r.GetOperation())
+	case *meshes.ListTrashedPoliciesRequest:
+		if err := validatePage(r.GetPageSize(), r.GetPageToken()); err != nil {
+			return err
+		}
+	case *meshes.RestorePolicyRequest:
+		if r.GetId() == "" {
+			return invalidArgument("the id of the trashed policy is required")
+		}
+	}
+	return nil
+}
+
+// validateKubeconfig parses a kubeconfig and checks that the context to use exists and is complete
+func validateKubeconfig(kubeconfig []byte, contextName string) error {
+	if len(kubeconfig) == 0 {
+		// the in-cluster config is used
+		return nil
+	}
+	if len(kubeconfig) > maxKubeconfigSize {
+		return invalidArgument("the kubeconfig is %d bytes, more than the %d a kubeconfig can sensibly be", len(kubeconfig), maxKubeconfigSize)
+	}
+	cfg, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return invalidArgument("the kubeconfig could not be parsed, upload the file kubectl uses: %v", err)
+	}
+	if contextName == "" {
+		contextName = cfg.CurrentContext
+	}
+	if contextName == "" {
+		return invalidArgument("the kubeconfig has no current-context, name the context to use")
+	}
+	kubeContext, ok := cfg.Contexts[contextName]
+	if !ok {
+		names := make([]string, 0, len(cfg.Contexts))
+		for name := range cfg.Contexts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return invalidArgument("context %q is not in the kubeconfig, it has %s", contextName, strings.Join(names, ", "))
+	}
+	cluster, ok := cfg.Clusters[kubeContext.Cluster]
+	if !ok {
+		return invalidArgument("cluster %q of context %q is not in the kubeconfig", kubeContext.Cluster, contextName)
+	}
+	if cluster.Server == "" {
+		return invalidArgument("cluster %q of context %q has no server address", kubeContext.Cluster, contextName)
+	}
+	if _, ok := cfg.AuthInfos[kubeContext.AuthInfo]; kubeContext.AuthInfo != "" && !ok {
+		return invalidArgument("user %q of context %q is not in the kubeconfig", kubeContext.AuthInfo, contextName)
+	}
+	return nil
+}
+
+// validateServiceAccount checks the server, token and certificates registering a cluster without a kubeconfig
+func validateServiceAccount(r *meshes.CreateMeshInstanceRequest) error {
+	if len(r.GetK8SConfig()) > 0 {
+		return invalidArgument("pass either a kubeconfig or a server and token, not both")
+	}
+	server, err := url.Parse(r.GetServer())
+	if err != nil || server.Host == "" {
+		return invalidArgument("server %q is not the URL of an API server", r.GetServer())
+	}
+	if server.Scheme != "https" {
+		// the token would go over the wire in the clear
+		return invalidArgument("server %q must be an https URL", r.GetServer())
+	}
+	if r.GetToken() == "" {
+		return invalidArgument("a token is required along with the server")
+	}
+	if strings.ContainsAny(r.GetToken(), " \t\r\n") {
+		return invalidArgument("the token contains whitespace, pass the token alone")
+	}
+	if ca := r.GetCertificateAuthority(); len(ca) > 0 && !x509.NewCertPool().AppendCertsFromPEM(ca) {
+		return invalidArgument("the certificate authority holds no PEM encoded certificate")
+	}
+	return nil
+}
+
+// validateOperation checks the name and the custom body of an operation
+func validateOperation(r *meshes.ApplyRuleRequest) error {
+	if _, ok := lookupOp(r.GetOpName()); !ok {
+		if cause := disabledOpCause(r.GetOpName()); cause != nil {
+			return invalidArgument("operation %s is disabled, %v", r.GetOpName(), cause)
+		}
+		return invalidArgument("%s is not a valid operation name", r.GetOpName())
+	}
+	if err := validateName("namespace", r.GetNamespace()); err != nil {
+		return err
+	}
+	r, _, err := withParameters(r)
+	if err != nil {
+		return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+	}
+	body := r.GetCustomBody()
+	if len(body) > maxCustomBodySize {
+		return invalidArgument("the custom body of %s is %d bytes, at most %d are accepted", r.GetOpName(), len(body), maxCustomBodySize)
+	}
+	if r.GetAppliedOperationId() != "" && (r.GetOpName() != customOpCommand || !r.GetDeleteOp() || strings.TrimSpace(body) != "") {
+		return invalidArgument("applied_operation_id only goes with delete_op and an empty custom body of the %s operation", customOpCommand)
+	}
+	if p := r.GetConflictPolicy(); p != "" && !conflictPolicies[p] {
+		return invalidArgument("conflict_policy %q is not one of %s", p, strings.Join(sortedKeys(conflictPolicies), ", "))
+	}
+	switch {
+	case r.GetOpName() == customOpCommand && r.GetDeleteOp() && strings.TrimSpace(body) == "":
+		// deletes what the operation of applied_operation_id applied, recorded in the inventory
+	case r.GetOpName() == applyMeshSpecCommand:
+		if strings.TrimSpace(body) == "" {
+			return invalidArgument("yaml body is empty for %s operation", r.GetOpName())
+		}
+		spec, err := parseMeshSpec(body)
+		if err != nil {
+			return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+		}
+		return validateMeshSpec(spec)
+	case manifestBodyOps[r.GetOpName()]:
+		if r.GetOpName() != admissionTestCommand && strings.TrimSpace(body) == "" {
+			return invalidArgument("yaml body is empty for %s operation", r.GetOpName())
+		}
+		manifest, err := normalizeManifest(body)
+		if err != nil {
+			return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+		}
+		if _, err := parseManifestObjects(manifest); err != nil {
+			return invalidArgument("the custom body of %s is not a valid manifest: %v", r.GetOpName(), err)
+		}
+	default:
+		params, err := parseDeploymentParams(body)
+		if err != nil {
+			return invalidArgument("the custom body of %s is not valid: %v", r.GetOpName(), err)
+		}
+		if err := checkOpParams(r.GetOpName(), body); err != nil {
+			return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+		}
+		if err := validateName("deployment", params.Deployment); err != nil {
+			return err
+		}
+		if r.GetOpName() == protectComponentsCommand {
+			if err := validatePolicyEngine(params.Engine); err != nil {
+				return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+			}
+		}
+		if r.GetOpName() == installOctarineCommand || r.GetOpName() == trialCommand {
+			if err := validateCertificates(params.Certificates); err != nil {
+				return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+			}
+			if err := validateMirror(params.Mirror); err != nil {
+				return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+			}
+			if err := validateReplicas(params.Replicas); err != nil {
+				return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+			}
+		}
+		if r.GetOpName() == spireFederationCommand {
+			if err := validateSpireParams(params); err != nil {
+				return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+			}
+		}
+		if r.GetOpName() == mirrorImagesCommand {
+			if err := validateMirrorParams(params, r.GetDeleteOp()); err != nil {
+				return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+			}
+		}
+		if r.GetOpName() == exposeCommand {
+			if err := validateExposeParams(params, r.GetDeleteOp()); err != nil {
+				return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+			}
+		}
+		if r.GetOpName() == injectionExclusionCommand {
+			if err := validateExclusionParams(params); err != nil {
+				return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+			}
+		}
+		if r.GetOpName() == sidecarResourcesCommand {
+			if err := validateSidecarResources(params, r.GetDeleteOp()); err != nil {
+				return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+			}
+		}
+		if r.GetOpName() == batchWorkloadsCommand {
+			if err := validateBatchMode(params.Batch); err != nil {
+				return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+			}
+		}
+		if r.GetOpName() == trialCommand {
+			if err := validateTrialParams(params, r.GetDeleteOp()); err != nil {
+				return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+			}
+		}
+		if r.GetOpName() == policyImportCommand {
+			if err := validatePolicyImport(params, r.GetNamespace(), r.GetDeleteOp()); err != nil {
+				return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+			}
+		}
+		if r.GetOpName() == latencyProbeCommand {
+			if _, err := parseLatencyLoad(params); err != nil {
+				return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+			}
+		}
+	}
+	return nil
+}
+
+func validateMeshSpec(spec *MeshSpec) error {
+	if err := validateName("deployment", spec.Name); err != nil {
+		return err
+	}
+	if err := validateName("namespace", spec.Namespace); err != nil {
+		return err
+	}
+	if err := validateCertificates(spec.Certificates); err != nil {
+		return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+	}
+	if err := validateMirror(spec.Mirror); err != nil {
+		return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+	}
+	if err := validateReplicas(spec.Replicas); err != nil {
+		return invalidArgument("%s", strings.TrimPrefix(err.Error(), "error: "))
+	}
+	for _, ns := range spec.InjectedNamespaces {
+		if err := validateName("injected namespace", ns); err != nil {
+			return err
+		}
+	}
+	for _, app := range spec.SampleApps {
+		if err := validateName(fmt.Sprintf("namespace of sample application %s", app.Name), app.Namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
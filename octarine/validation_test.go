@@ -0,0 +1,73 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/layer5io/meshery-octarine/meshes"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestStream is a server stream whose client opened it with req
+type requestStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	req proto.Message
+}
+
+func (s *requestStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *requestStream) RecvMsg(m interface{}) error {
+	proto.Merge(m.(proto.Message), s.req)
+	return nil
+}
+
+func TestStreamRequestValidated(t *testing.T) {
+	os.Setenv(localesDirEnv, "locales")
+	defer os.Unsetenv(localesDirEnv)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(localeMetadataKey, "es"))
+	stream := &requestStream{ctx: ctx, req: &meshes.EventsRequest{MinSeverity: 42}}
+	streamed := false
+	streamEvents := func(srv interface{}, ss grpc.ServerStream) error {
+		if err := ss.RecvMsg(&meshes.EventsRequest{}); err != nil {
+			return err
+		}
+		streamed = true
+		return nil
+	}
+	// chained as the server chains them, the error of the validation is translated
+	err := LocaleStreamInterceptor(nil, stream, nil, func(srv interface{}, ss grpc.ServerStream) error {
+		return ValidationStreamInterceptor(srv, ss, nil, streamEvents)
+	})
+	if streamed {
+		t.Error("the events were streamed for a request with an unknown severity")
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("the stream ended with %v, want InvalidArgument", err)
+	}
+	if want := "min_severity 42 no es una gravedad conocida"; status.Convert(err).Message() != want {
+		t.Errorf("the stream ended with %q, want %q", status.Convert(err).Message(), want)
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/secrets"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// vaultCredentialsEnv is the API path of the Vault secret holding the control plane credentials, e.g.
+	// secret/data/octarine, the environment variables are used when it isn't set
+	vaultCredentialsEnv = "OCTARINE_VAULT_CREDENTIALS"
+	// vaultRoleEnv is the role the adapter logs in to Vault as with its service account, VAULT_TOKEN is used
+	// when it isn't set
+	vaultRoleEnv        = "OCTARINE_VAULT_ROLE"
+	vaultAuthPathEnv    = "OCTARINE_VAULT_AUTH_PATH"
+	vaultRefreshEnv     = "OCTARINE_VAULT_REFRESH"
+	defaultVaultAuth    = "kubernetes"
+	defaultVaultRefresh = 5 * time.Minute
+	vaultTimeout        = 30 * time.Second
+
+	serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// vaultCredentialKeys are the fields of the Vault secret and the environment variables they replace, the
+// control plane address is optional
+var vaultCredentialKeys = []struct {
+	key      string
+	env      string
+	optional bool
+}{
+	{"control_plane", "OCTARINE_CP", true},
+	{"acc_mgr_password", "OCTARINE_ACC_MGR_PASSWD", false},
+	{"creator_password", "OCTARINE_CREATOR_PASSWD", false},
+	{"deleter_password", "OCTARINE_DELETER_PASSWD", false},
+}
+
+// vaultCredentialStore keeps the control plane credentials read from Vault, read again and with its token
+// renewed every OCTARINE_VAULT_REFRESH. The credentials last read are kept while Vault can't be reached.
+type vaultCredentialStore struct {
+	vault *secrets.Vault
+	path  string
+	role  string
+	mount string
+
+	mu          sync.Mutex
+	credentials map[string]string
+	readAt      time.Time
+	token       secrets.VaultToken
+	tokenAt     time.Time
+	err         error
+}
+
+// vaultCredentials is set when the credentials are kept in Vault
+var vaultCredentials *vaultCredentialStore
+
+// LoadVaultCredentials reads the control plane credentials from Vault when OCTARINE_VAULT_CREDENTIALS is set,
+// and keeps them and the token of the adapter fresh. It is meant to be called once at startup, the adapter
+// doesn't start with credentials it can't read.
+func LoadVaultCredentials() error {
+	path := os.Getenv(vaultCredentialsEnv)
+	if path == "" {
+		return nil
+	}
+	vault, err := secrets.NewVault()
+	if err != nil {
+		return errors.Wrapf(err, "%s is set", vaultCredentialsEnv)
+	}
+	store := &vaultCredentialStore{
+		vault: vault,
+		path:  strings.Trim(path, "/"),
+		role:  os.Getenv(vaultRoleEnv),
+		mount: os.Getenv(vaultAuthPathEnv),
+	}
+	if store.mount == "" {
+		store.mount = defaultVaultAuth
+	}
+	if store.role == "" && os.Getenv("VAULT_TOKEN") == "" {
+		return fmt.Errorf("error: %s is set but neither %s nor VAULT_TOKEN, the adapter can't authenticate to Vault", vaultCredentialsEnv, vaultRoleEnv)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), vaultTimeout)
+	defer cancel()
+	if err := store.authenticate(ctx); err != nil {
+		return err
+	}
+	if err := store.read(ctx); err != nil {
+		return err
+	}
+	vaultCredentials = store
+	logrus.Infof("Reading the Octarine credentials from Vault %s at %s", vault, store.path)
+	go store.refresh(durationFromEnv(vaultRefreshEnv, defaultVaultRefresh))
+	return nil
+}
+
+// authenticate logs in with the service account of the adapter when a role is set, or else looks up the
+// lease of VAULT_TOKEN
+func (s *vaultCredentialStore) authenticate(ctx context.Context) error {
+	var token secrets.VaultToken
+	var err error
+	if s.role != "" {
+		jwt, readErr := ioutil.ReadFile(serviceAccountTokenFile)
+		if readErr != nil {
+			return errors.Wrapf(readErr, "unable to read the service account token to log in to Vault")
+		}
+		token, err = s.vault.LoginKubernetes(ctx, s.mount, s.role, strings.TrimSpace(string(jwt)))
+	} else {
+		token, err = s.vault.LookupToken(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.token, s.tokenAt = token, time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// renew extends the lease of the token, a token which can't be renewed any more is replaced by logging in
+// again when the adapter logs in with a role. Tokens without a lease, like root tokens, are left as they are.
+func (s *vaultCredentialStore) renew(ctx context.Context) error {
+	s.mu.Lock()
+	token := s.token
+	s.mu.Unlock()
+	if token.TTL == 0 {
+		return nil
+	}
+	if token.Renewable {
+		renewed, err := s.vault.RenewToken(ctx)
+		if err == nil {
+			s.mu.Lock()
+			s.token, s.tokenAt = renewed, time.Now()
+			s.mu.Unlock()
+			return nil
+		}
+		if s.role == "" {
+			return err
+		}
+		logrus.Warnf("Logging in to Vault again: %v", err)
+	}
+	if s.role == "" {
+		left, _ := s.expiresIn()
+		return fmt.Errorf("error: the Vault token can't be renewed, it expires in %s", left.Round(time.Second))
+	}
+	return s.authenticate(ctx)
+}
+
+// expiresIn is how long the token is still valid, false for tokens without a lease
+func (s *vaultCredentialStore) expiresIn() (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token.TTL - time.Since(s.tokenAt), s.token.TTL > 0
+}
+
+func (s *vaultCredentialStore) read(ctx context.Context) error {
+	data, err := s.vault.Read(ctx, s.path)
+	if err != nil {
+		return err
+	}
+	credentials := map[string]string{}
+	missing := []string{}
+	for _, k := range vaultCredentialKeys {
+		value, ok := secrets.VaultString(data, k.key)
+		if !ok || value == "" {
+			if !k.optional {
+				missing = append(missing, k.key)
+			}
+			continue
+		}
+		credentials[k.env] = value
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("error: Vault secret %s lacks %s", s.path, strings.Join(missing, ", "))
+	}
+	s.mu.Lock()
+	s.credentials, s.readAt = credentials, time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// refresh renews the token and reads the credentials again every interval, or sooner when the token expires
+// before, so rotated passwords are picked up without restarting the adapter
+func (s *vaultCredentialStore) refresh(interval time.Duration) {
+	for {
+		wait := interval
+		if left, ok := s.expiresIn(); ok && left/2 < wait {
+			wait = left / 2
+		}
+		if wait < time.Second {
+			wait = time.Second
+		}
+		time.Sleep(wait)
+		ctx, cancel := context.WithTimeout(context.Background(), vaultTimeout)
+		err := s.renew(ctx)
+		if err == nil {
+			err = s.read(ctx)
+		}
+		cancel()
+		if err != nil {
+			logrus.Errorf("Unable to refresh the Octarine credentials from Vault: %v", err)
+		}
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+	}
+}
+
+// get returns a credential by the environment variable it replaces, the variable itself for the optional ones
+// the secret doesn't hold
+func (s *vaultCredentialStore) get(env string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if value, ok := s.credentials[env]; ok {
+		return value
+	}
+	return os.Getenv(env)
+}
+
+// providesEnv tells whether Vault holds a credential instead of its environment variable
+func (s *vaultCredentialStore) providesEnv(env string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.credentials[env]
+	return ok
+}
+
+// checkVault reports whether Vault can be reached with the token of the adapter, and how fresh the
+// credentials are
+func checkVault() (string, string) {
+	s := vaultCredentials
+	if s == nil {
+		return checkSkipped, "the credentials are read from the environment"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), vaultTimeout)
+	defer cancel()
+	if err := s.vault.Health(ctx); err != nil {
+		return checkFail, err.Error()
+	}
+	if _, err := s.vault.LookupToken(ctx); err != nil {
+		return checkFail, err.Error()
+	}
+	s.mu.Lock()
+	readAt, err := s.readAt, s.err
+	s.mu.Unlock()
+	message := fmt.Sprintf("credentials read %s ago", time.Since(readAt).Round(time.Second))
+	if left, ok := s.expiresIn(); ok {
+		message += fmt.Sprintf(", token expires in %s", left.Round(time.Second))
+	}
+	if err != nil {
+		message += fmt.Sprintf(", last refresh failed: %v", err)
+	}
+	return checkPass, message
+}
@@ -15,12 +15,27 @@
 package octarine
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/layer5io/meshery-octarine/meshes"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
 )
 
+const (
+	enforceVetWindowEnv     = "OCTARINE_ENFORCE_VET_WINDOW"
+	defaultEnforceVetWindow = 30 * time.Minute
+
+	// a failing check lists this many of the pods or nodes it found
+	maxVetNames = 5
+)
+
 type metaInformerFactory struct {
 	k8s informers.SharedInformerFactory
 }
@@ -29,13 +44,46 @@ func (m *metaInformerFactory) K8s() informers.SharedInformerFactory {
 	return m.k8s
 }
 
-func (oClient *Client) runVet() error {
-	kubeInformerFactory := informers.NewSharedInformerFactory(oClient.k8sClientset, 0)
+// vetCheck is the outcome of a check of a deployment, namespace is empty for the checks of the whole
+// deployment
+type vetCheck struct {
+	name      string
+	namespace string
+	failure   string
+	severity  string
+	// references are the benchmark recommendations and documentation behind the check
+	references []string
+	// advisory checks are reported but don't gate enforcement
+	advisory bool
+	// skipped tells why the check couldn't be run
+	skipped string
+}
+
+// vetRun is the last vet of a deployment
+type vetRun struct {
+	at     time.Time
+	checks []vetCheck
+}
+
+// failures lists the failed checks gating enforcement of a namespace, or of the whole deployment
+func (r *vetRun) failures(namespace string) []string {
+	failed := []string{}
+	for _, c := range r.checks {
+		if c.failure != "" && !c.advisory && (namespace == "" || c.namespace == "" || c.namespace == namespace) {
+			failed = append(failed, fmt.Sprintf("%s: %s", c.name, c.failure))
+		}
+	}
+	return failed
+}
+
+func (oClient *Client) runVet(ctx context.Context, arReq *meshes.ApplyRuleRequest) error {
+	kubeInformerFactory := informers.NewSharedInformerFactory(oClient.clientset(ctx), 0)
 	//	informerFactory := &metaInformerFactory{
 	//		k8s: kubeInformerFactory,
 	//	}
 
 	stopCh := make(chan struct{})
+	defer close(stopCh)
 
 	kubeInformerFactory.Start(stopCh)
 	oks := kubeInformerFactory.WaitForCacheSync(stopCh)
@@ -46,18 +94,219 @@ func (oClient *Client) runVet() error {
 			return err
 		}
 	}
+
+	params, err := parseDeploymentParams(arReq.GetCustomBody())
+	if err != nil {
+		return err
+	}
+	d, err := oClient.getDeployment(params.Deployment)
+	if err != nil {
+		return err
+	}
+	workingOn(ctx, "vetting deployment %s", d.name)
+	run, err := oClient.vetDeployment(ctx, d)
+	if err != nil {
+		return err
+	}
+	progressed(ctx)
+	oClient.vetMu.Lock()
+	if oClient.vetRuns == nil {
+		oClient.vetRuns = map[string]*vetRun{}
+	}
+	oClient.vetRuns[d.name] = run
+	oClient.vetMu.Unlock()
+
+	failed := []string{}
+	for _, c := range run.checks {
+		if c.failure == "" {
+			continue
+		}
+		failed = append(failed, fmt.Sprintf("%s: %s", c.name, c.failure))
+		details := c.failure
+		if len(c.references) > 0 {
+			details += "\nReferences: " + strings.Join(c.references, ", ")
+		}
+		oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: arReq.GetOperationId(),
+			Severity:    vetEventSeverity(c.severity),
+			Summary:     fmt.Sprintf("Vet check %s failed (%s severity)", c.name, c.severity),
+			Details:     details,
+		}
+	}
+	event := &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		Severity:    meshes.Severity_SEVERITY_INFO,
+		Summary:     fmt.Sprintf("Deployment %s passed all %d vet checks", d.name, len(run.checks)),
+	}
+	if len(failed) > 0 {
+		event.Severity = meshes.Severity_SEVERITY_WARN
+		event.Summary = fmt.Sprintf("Deployment %s failed %d of %d vet checks", d.name, len(failed), len(run.checks))
+		event.Details = strings.Join(failed, "\n")
+	}
+	oClient.eventChan <- event
 	return nil
 }
 
-func convertVetLevelToMesheryLevel(level string) meshes.EventType {
-	switch level {
-	// case "INFO":
-	// 	return
-	case "WARNING":
-		return meshes.EventType_WARN
-	case "ERROR":
-		return meshes.EventType_ERROR
+// vetDeployment runs the baseline checks of a deployment: its dataplane is available, its webhooks answer,
+// and every running pod of its injected namespaces has a sidecar, followed by the CIS checks
+func (oClient *Client) vetDeployment(ctx context.Context, d *deployment) (*vetRun, error) {
+	run := &vetRun{at: time.Now()}
+
+	dataplane := vetCheck{name: "dataplane", severity: severityHigh}
+	depls, err := oClient.clientset(ctx).AppsV1().Deployments(d.namespace).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", deploymentNameLabel, d.name),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the dataplane of deployment %s", d.name)
+	}
+	unavailable := []string{}
+	for _, depl := range depls.Items {
+		replicas := int32(1)
+		if depl.Spec.Replicas != nil {
+			replicas = *depl.Spec.Replicas
+		}
+		if depl.Status.AvailableReplicas < replicas {
+			unavailable = append(unavailable, fmt.Sprintf("%s (%d/%d available)", depl.Name, depl.Status.AvailableReplicas, replicas))
+		}
+	}
+	switch {
+	case len(depls.Items) == 0:
+		dataplane.failure = fmt.Sprintf("no dataplane deployments in namespace %s", d.namespace)
+	case len(unavailable) > 0:
+		dataplane.failure = "unavailable: " + strings.Join(unavailable, ", ")
+	}
+	run.checks = append(run.checks, dataplane)
+	run.checks = append(run.checks, oClient.highAvailabilityCheck(ctx, d, depls.Items))
+
+	webhooks := vetCheck{name: "webhooks", severity: severityHigh}
+	oClient.webhooksMu.Lock()
+	failing := map[string]bool{}
+	for key, failed := range oClient.failingWebhooks {
+		if failed {
+			failing[key] = true
+		}
+	}
+	oClient.webhooksMu.Unlock()
+	if len(failing) > 0 {
+		webhooks.failure = "failing probes: " + strings.Join(sortedKeys(failing), ", ")
+	}
+	run.checks = append(run.checks, webhooks)
+
+	injected, err := oClient.injectedNamespaces(ctx, d.name)
+	if err != nil {
+		return nil, err
+	}
+	_, prefixes, err := oClient.dataplaneImages(ctx, d)
+	if err != nil {
+		// without its dataplane the sidecars of the deployment can't be told apart, the dataplane check failed
+		prefixes = map[string]bool{}
+	}
+	for _, ns := range sortedKeys(injected) {
+		check := vetCheck{name: "sidecars in " + ns, namespace: ns, severity: severityHigh}
+		pods, err := oClient.clientset(ctx).CoreV1().Pods(ns).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to list the pods of namespace %s", ns)
+		}
+		missing := []string{}
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != corev1.PodRunning || sidecarContainer(pod, prefixes) != nil {
+				continue
+			}
+			missing = append(missing, pod.Name)
+		}
+		if len(missing) > 0 {
+			check.failure = fmt.Sprintf("%d running pod(s) without a sidecar: %s", len(missing), truncatedList(missing))
+		}
+		run.checks = append(run.checks, check)
+	}
+
+	cis, err := oClient.cisChecks(ctx, injected)
+	if err != nil {
+		return nil, err
+	}
+	run.checks = append(run.checks, cis...)
+	return run, nil
+}
+
+// truncatedList lists the first maxVetNames names and how many more there are
+func truncatedList(names []string) string {
+	if len(names) > maxVetNames {
+		names = append(names[:maxVetNames:maxVetNames], fmt.Sprintf("and %d more", len(names)-maxVetNames))
+	}
+	return strings.Join(names, ", ")
+}
+
+// VetReport returns the checks of the last vet of a deployment with their severity and references
+func (oClient *Client) VetReport(ctx context.Context, req *meshes.VetReportRequest) (*meshes.VetReportResponse, error) {
+	if oClient.clientset(ctx) == nil {
+		return &meshes.VetReportResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	d, err := oClient.getDeployment(req.GetDeployment())
+	if err != nil {
+		return &meshes.VetReportResponse{Error: err.Error()}, nil
+	}
+	oClient.vetMu.Lock()
+	run := oClient.vetRuns[d.name]
+	oClient.vetMu.Unlock()
+	if run == nil {
+		return &meshes.VetReportResponse{Error: fmt.Sprintf("error: deployment %s was not vetted since the adapter started, run %s first", d.name, runVet)}, nil
+	}
+	resp := &meshes.VetReportResponse{Deployment: d.name, VettedAt: run.at.UTC().Format(time.RFC3339)}
+	for _, c := range run.checks {
+		if c.failure != "" {
+			resp.Failed++
+		}
+		resp.Checks = append(resp.Checks, &meshes.VetCheckResult{
+			Name:       c.name,
+			Namespace:  c.namespace,
+			Passed:     c.failure == "" && c.skipped == "",
+			Failure:    c.failure,
+			Severity:   c.severity,
+			References: c.references,
+			Advisory:   c.advisory,
+			Skipped:    c.skipped,
+		})
+	}
+	return resp, nil
+}
+
+// gateEnforcement refuses to enforce the policies of a deployment, or of one of its namespaces, unless a vet
+// within OCTARINE_ENFORCE_VET_WINDOW passed the checks concerned, so enforcing doesn't break workloads of an
+// obviously misconfigured namespace. Forced requests skip the gate.
+func (oClient *Client) gateEnforcement(arReq *meshes.ApplyRuleRequest, d *deployment, namespace string) error {
+	window := durationFromEnv(enforceVetWindowEnv, defaultEnforceVetWindow)
+	oClient.vetMu.Lock()
+	run := oClient.vetRuns[d.name]
+	oClient.vetMu.Unlock()
+	problem := ""
+	switch {
+	case run == nil || time.Since(run.at) > window:
+		problem = fmt.Sprintf("deployment %s was not vetted in the last %s, run %s first", d.name, window, runVet)
+	case len(run.failures(namespace)) > 0:
+		problem = fmt.Sprintf("the vet of deployment %s at %s failed: %s", d.name, run.at.UTC().Format(time.RFC3339), strings.Join(run.failures(namespace), "; "))
 	default:
-		return meshes.EventType_INFO
+		return nil
+	}
+	if !arReq.GetForce() {
+		return fmt.Errorf("error: refusing to enforce, %s; set force to enforce anyway", problem)
+	}
+	logrus.Warnf("Enforcing deployment %s although %s", d.name, problem)
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: arReq.GetOperationId(),
+		EventType:   meshes.EventType_WARN,
+		Summary:     fmt.Sprintf("Enforcing the policies of deployment %s without a passing vet", d.name),
+		Details:     problem,
+	}
+	return nil
+}
+
+// vetEventSeverity is the severity of the event of a failed check
+func vetEventSeverity(severity string) meshes.Severity {
+	switch severity {
+	case severityHigh:
+		return meshes.Severity_SEVERITY_ERROR
+	case severityMedium:
+		return meshes.Severity_SEVERITY_WARN
 	}
+	return meshes.Severity_SEVERITY_INFO
 }
@@ -0,0 +1,179 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultViolationWindow = 24 * time.Hour
+	defaultViolationBucket = time.Hour
+	// keeps a long window with narrow buckets from producing an unplottable trend
+	maxViolationBuckets = 1000
+)
+
+// violationRecord is a violation as octactl reports it, repeated occurrences are folded into count
+type violationRecord struct {
+	Namespace string    `json:"namespace"`
+	Workload  string    `json:"workload"`
+	Policy    string    `json:"policy"`
+	Timestamp time.Time `json:"timestamp"`
+	Count     int64     `json:"count"`
+}
+
+// listViolations fetches the violations the control plane recorded for the domain of a deployment since a time
+func (oClient *Client) listViolations(ctx context.Context, d *deployment, since time.Time) ([]violationRecord, error) {
+	logrus.Debugf("Listing the violations of domain %s since %s", d.domain, since.Format(time.RFC3339))
+	var out []byte
+	err := oClient.asAccount(ctx, d, func() error {
+		var err error
+		out, err = exec.CommandContext(ctx, "octactl", "violation", "list", d.domain, "--since", since.Format(time.RFC3339), "--output", "json").Output()
+		return err
+	})
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			logrus.Errorf("Command finished with error: %v: %s", err, exitErr.Stderr)
+		}
+		return nil, errors.Wrapf(err, "unable to list the violations of domain %s", d.domain)
+	}
+	records := []violationRecord{}
+	if err := json.Unmarshal(out, &records); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the violations of domain %s", d.domain)
+	}
+	return records, nil
+}
+
+// violationKey is what violations are counted by, fields not grouped on stay empty
+type violationKey struct {
+	namespace string
+	workload  string
+	policy    string
+}
+
+// violationCounts sorts counts by the most violations first
+func violationCounts(counts map[violationKey]int64) []*meshes.ViolationCount {
+	result := make([]*meshes.ViolationCount, 0, len(counts))
+	for key, n := range counts {
+		result = append(result, &meshes.ViolationCount{
+			Namespace: key.namespace,
+			Workload:  key.workload,
+			Policy:    key.policy,
+			Count:     n,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Workload != b.Workload {
+			return a.Workload < b.Workload
+		}
+		return a.Policy < b.Policy
+	})
+	return result
+}
+
+// summarizeViolations aggregates the records of a namespace, or of all of them, falling in [since, until)
+func summarizeViolations(records []violationRecord, namespace string, since, until time.Time, bucket time.Duration) *meshes.PolicyViolationsResponse {
+	resp := &meshes.PolicyViolationsResponse{
+		Since: since.Format(time.RFC3339),
+		Until: until.Format(time.RFC3339),
+	}
+	buckets := int((until.Sub(since) + bucket - 1) / bucket)
+	trend := make([]int64, buckets)
+	namespaces := map[violationKey]int64{}
+	workloads := map[violationKey]int64{}
+	policies := map[violationKey]int64{}
+	for _, r := range records {
+		if namespace != "" && r.Namespace != namespace || r.Timestamp.Before(since) || !r.Timestamp.Before(until) {
+			continue
+		}
+		n := r.Count
+		if n <= 0 {
+			n = 1
+		}
+		resp.Total += n
+		trend[int(r.Timestamp.Sub(since)/bucket)] += n
+		namespaces[violationKey{namespace: r.Namespace}] += n
+		workloads[violationKey{namespace: r.Namespace, workload: r.Workload}] += n
+		policies[violationKey{policy: r.Policy}] += n
+	}
+	resp.Namespaces = violationCounts(namespaces)
+	resp.Workloads = violationCounts(workloads)
+	resp.Policies = violationCounts(policies)
+	for i, n := range trend {
+		resp.Trend = append(resp.Trend, &meshes.ViolationBucket{
+			Start: since.Add(time.Duration(i) * bucket).Format(time.RFC3339),
+			Count: n,
+		})
+	}
+	return resp
+}
+
+func parseViolationDuration(name, value string, def time.Duration) (time.Duration, error) {
+	if value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("error: %s must be a positive duration like 24h, got %q", name, value)
+	}
+	return d, nil
+}
+
+// PolicyViolations counts the policy violations of a deployment by namespace, workload and policy over a window
+func (oClient *Client) PolicyViolations(ctx context.Context, req *meshes.PolicyViolationsRequest) (*meshes.PolicyViolationsResponse, error) {
+	if oClient.clientset(ctx) == nil {
+		return &meshes.PolicyViolationsResponse{Error: "error: mesh instance has not been created"}, nil
+	}
+	window, err := parseViolationDuration("window", req.GetWindow(), defaultViolationWindow)
+	if err != nil {
+		return &meshes.PolicyViolationsResponse{Error: err.Error()}, nil
+	}
+	bucket, err := parseViolationDuration("bucket", req.GetBucket(), defaultViolationBucket)
+	if err != nil {
+		return &meshes.PolicyViolationsResponse{Error: err.Error()}, nil
+	}
+	if window/bucket > maxViolationBuckets {
+		return &meshes.PolicyViolationsResponse{
+			Error: fmt.Sprintf("error: a %s window has more than %d buckets of %s", window, maxViolationBuckets, bucket),
+		}, nil
+	}
+	d, err := oClient.getDeployment(req.GetDeployment())
+	if err != nil {
+		return &meshes.PolicyViolationsResponse{Error: err.Error()}, nil
+	}
+	until := time.Now().UTC().Truncate(time.Second)
+	since := until.Add(-window)
+	records, err := oClient.listViolations(ctx, d, since)
+	if err != nil {
+		return &meshes.PolicyViolationsResponse{Error: err.Error()}, nil
+	}
+	return summarizeViolations(records, req.GetNamespace(), since, until, bucket), nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultStallWarning = time.Minute
+	defaultStallTimeout = 5 * time.Minute
+	watchdogInterval    = 5 * time.Second
+)
+
+const watchdogKey contextKey = operationIDKey + 1
+
+// watchdog warns about operations which stopped making progress and fails them once they stalled for too
+// long. Client calls ignoring the cancel may keep the operation running, so the watchdog finishes its result
+// and sends its ERROR event itself rather than waiting for it to return.
+type watchdog struct {
+	oClient *Client
+	opID    string
+	result  *operationResult
+	cancel  context.CancelFunc
+	warnAt  time.Duration
+	failAt  time.Duration
+	// failure is the summary of the ERROR event of the operation
+	failure string
+
+	mu           sync.Mutex
+	lastProgress time.Time
+	current      string
+	warned       bool
+	err          error
+	settled      bool
+}
+
+func durationFromEnv(name string, def time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		logrus.Warnf("ignoring invalid duration %q in %s, using %s", value, name, def)
+		return def
+	}
+	return d
+}
+
+// startWatchdog watches the operation carried by ctx, the returned func must be called with the error of the
+// operation once it is done and finishes the result of the operation, unless the watchdog failed it already
+func (oClient *Client) startWatchdog(ctx context.Context, failure string) (context.Context, func(error)) {
+	ctx, cancel := context.WithCancel(ctx)
+	// the requests of the operation end with it, when it is failed or cancelled
+	ctx = bindClients(ctx)
+	wd := &watchdog{
+		oClient:      oClient,
+		opID:         operationIDFrom(ctx),
+		result:       resultFrom(ctx),
+		cancel:       cancel,
+		warnAt:       durationFromEnv("OCTARINE_STALL_WARNING", defaultStallWarning),
+		failAt:       durationFromEnv("OCTARINE_STALL_TIMEOUT", defaultStallTimeout),
+		failure:      failure,
+		lastProgress: time.Now(),
+	}
+	if wd.result != nil {
+		t := oClient.events.results
+		t.mu.Lock()
+		wd.result.watchdog = wd
+		t.mu.Unlock()
+	}
+	done := make(chan struct{})
+	go wd.run(done)
+	ctx = context.WithValue(ctx, watchdogKey, wd)
+	return ctx, func(err error) {
+		close(done)
+		cancel()
+		if err != nil {
+			err = stallError(ctx, err)
+		}
+		if !wd.settle(err) && err != nil {
+			logrus.Warnf("Operation %s returned after its watchdog failed it: %v", wd.opID, err)
+		}
+	}
+}
+
+// settle sends the ERROR event of a failed operation and finishes its result, only the first call does,
+// whether the operation returned or its watchdog gave up on it
+func (wd *watchdog) settle(err error) bool {
+	wd.mu.Lock()
+	settled := wd.settled
+	wd.settled = true
+	wd.mu.Unlock()
+	if settled {
+		return false
+	}
+	if err != nil {
+		wd.oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: wd.opID,
+			EventType:   meshes.EventType_ERROR,
+			Summary:     wd.failure,
+			Details:     err.Error(),
+		}
+	}
+	wd.oClient.finishResult(wd.result, nil)
+	return true
+}
+
+func (wd *watchdog) run(done <-chan struct{}) {
+	ticker := time.NewTicker(watchdogInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			wd.check()
+		}
+	}
+}
+
+func (wd *watchdog) check() {
+	wd.mu.Lock()
+	stalled := time.Since(wd.lastProgress)
+	current := wd.current
+	sendWarning := stalled >= wd.warnAt && !wd.warned
+	if sendWarning {
+		wd.warned = true
+	}
+	fail := stalled >= wd.failAt && wd.err == nil
+	if fail {
+		wd.err = fmt.Errorf("error: the operation made no progress for %s while working on %s", stalled.Round(time.Second), current)
+	}
+	wd.mu.Unlock()
+
+	if sendWarning {
+		wd.oClient.eventChan <- &meshes.EventsResponse{
+			OperationId: wd.opID,
+			EventType:   meshes.EventType_WARN,
+			Summary:     fmt.Sprintf("No progress for %s", stalled.Round(time.Second)),
+			Details:     fmt.Sprintf("Still working on %s, the operation is cancelled after %s without progress.", current, wd.failAt),
+		}
+	}
+	if fail {
+		logrus.Error(wd.err)
+		wd.cancel()
+		wd.settle(wd.err)
+	}
+}
+
+func watchdogFrom(ctx context.Context) *watchdog {
+	wd, _ := ctx.Value(watchdogKey).(*watchdog)
+	return wd
+}
+
+// workingOn records what the operation is busy with, for the stall diagnostics
+func workingOn(ctx context.Context, format string, args ...interface{}) {
+	if wd := watchdogFrom(ctx); wd != nil {
+		wd.mu.Lock()
+		wd.current = fmt.Sprintf(format, args...)
+		wd.mu.Unlock()
+	}
+}
+
+// progressed resets the stall timer of the operation
+func progressed(ctx context.Context) {
+	if wd := watchdogFrom(ctx); wd != nil {
+		wd.mu.Lock()
+		wd.lastProgress = time.Now()
+		wd.warned = false
+		wd.mu.Unlock()
+	}
+}
+
+// stallError replaces the error of an operation cancelled by its watchdog with the stall diagnostics
+func stallError(ctx context.Context, err error) error {
+	wd := watchdogFrom(ctx)
+	if err == nil || wd == nil {
+		return err
+	}
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+	if wd.err == nil {
+		return err
+	}
+	if diag, ok := err.(*diagnosisError); ok {
+		return &diagnosisError{err: wd.err, diagnosis: diag.diagnosis}
+	}
+	return wd.err
+}
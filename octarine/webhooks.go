@@ -0,0 +1,366 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	webhookProbeIntervalEnv = "OCTARINE_WEBHOOK_PROBE_INTERVAL"
+	webhookFailOpenEnv      = "OCTARINE_WEBHOOK_FAIL_OPEN"
+	webhookFailOpenForEnv   = "OCTARINE_WEBHOOK_FAIL_OPEN_FOR"
+
+	defaultWebhookProbeInterval = time.Minute
+	defaultWebhookFailOpenFor   = 10 * time.Minute
+
+	// failOpenAnnotation holds the webhooks of a configuration the adapter switched to Ignore, and when they
+	// go back to Fail, so a restart of the adapter doesn't leave them failing open
+	failOpenAnnotation = "meshery.layer5.io/fail-open-until"
+
+	mutatingWebhooks   = "MutatingWebhookConfiguration"
+	validatingWebhooks = "ValidatingWebhookConfiguration"
+
+	// the namespaces a failing webhook blocks are listed in its event up to this many
+	maxListedNamespaces = 10
+)
+
+// probedWebhook is a webhook of an Octarine webhook configuration, mutating or validating
+type probedWebhook struct {
+	kind              string
+	config            string
+	name              string
+	failurePolicy     *admissionv1beta1.FailurePolicyType
+	rules             []admissionv1beta1.RuleWithOperations
+	namespaceSelector *metav1.LabelSelector
+	// failOpenUntil is when the adapter puts the failure policy of the webhook back to Fail, zero unless it
+	// switched it to Ignore
+	failOpenUntil time.Time
+}
+
+func (wh *probedWebhook) String() string {
+	return fmt.Sprintf("%s of %s %s", wh.name, wh.kind, wh.config)
+}
+
+// startWebhookProbe starts exercising the Octarine admission webhooks of the cluster, once
+func (oClient *Client) startWebhookProbe() {
+	oClient.webhookProbeOnce.Do(func() {
+		interval := durationFromEnv(webhookProbeIntervalEnv, defaultWebhookProbeInterval)
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for now := range ticker.C {
+				oClient.probeWebhooks(now)
+			}
+		}()
+	})
+}
+
+// failOpen tells whether failing webhooks are switched to Ignore until they work again
+func failOpen() bool {
+	return os.Getenv(webhookFailOpenEnv) == "true"
+}
+
+// failOpenUntil parses the failOpenAnnotation of a webhook configuration
+func failOpenUntil(annotations map[string]string) map[string]time.Time {
+	until := map[string]time.Time{}
+	if value := annotations[failOpenAnnotation]; value != "" {
+		if err := json.Unmarshal([]byte(value), &until); err != nil {
+			logrus.Warnf("Ignoring the invalid %s annotation %q: %v", failOpenAnnotation, value, err)
+		}
+	}
+	return until
+}
+
+// managedWebhooks lists the webhooks of the webhook configurations the adapter installed
+func (oClient *Client) managedWebhooks(ctx context.Context) ([]*probedWebhook, error) {
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", managedByLabel, managedByValue)}
+	admission := oClient.clientset(ctx).AdmissionregistrationV1beta1()
+	webhooks := []*probedWebhook{}
+	mutating, err := admission.MutatingWebhookConfigurations().List(opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the mutating webhook configurations")
+	}
+	for _, config := range mutating.Items {
+		until := failOpenUntil(config.GetAnnotations())
+		for _, wh := range config.Webhooks {
+			webhooks = append(webhooks, &probedWebhook{
+				kind: mutatingWebhooks, config: config.GetName(), name: wh.Name,
+				failurePolicy: wh.FailurePolicy, rules: wh.Rules, namespaceSelector: wh.NamespaceSelector,
+				failOpenUntil: until[wh.Name],
+			})
+		}
+	}
+	validating, err := admission.ValidatingWebhookConfigurations().List(opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the validating webhook configurations")
+	}
+	for _, config := range validating.Items {
+		until := failOpenUntil(config.GetAnnotations())
+		for _, wh := range config.Webhooks {
+			webhooks = append(webhooks, &probedWebhook{
+				kind: validatingWebhooks, config: config.GetName(), name: wh.Name,
+				failurePolicy: wh.FailurePolicy, rules: wh.Rules, namespaceSelector: wh.NamespaceSelector,
+				failOpenUntil: until[wh.Name],
+			})
+		}
+	}
+	return webhooks, nil
+}
+
+// admitsPods tells whether a webhook is called when a pod is created
+func (wh *probedWebhook) admitsPods() bool {
+	for _, rule := range wh.rules {
+		ops := map[string]bool{}
+		for _, op := range rule.Operations {
+			ops[string(op)] = true
+		}
+		resources := stringSet(rule.Resources)
+		if (ops["CREATE"] || ops["*"]) && (resources["pods"] || resources["*"]) && stringSet(rule.APIGroups)[""] {
+			return true
+		}
+	}
+	return false
+}
+
+// blockedNamespaces are the namespaces whose pods can't be created while the webhook fails
+func (oClient *Client) blockedNamespaces(ctx context.Context, wh *probedWebhook) ([]string, error) {
+	opts := metav1.ListOptions{}
+	if wh.namespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(wh.namespaceSelector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read the namespace selector of webhook %s", wh)
+		}
+		opts.LabelSelector = selector.String()
+	}
+	list, err := oClient.clientset(ctx).CoreV1().Namespaces().List(opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list the namespaces of webhook %s", wh)
+	}
+	namespaces := make([]string, 0, len(list.Items))
+	for _, ns := range list.Items {
+		namespaces = append(namespaces, ns.GetName())
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+// probeWebhook dry-runs the creation of a pod in a namespace of the webhook and tells why the webhook failed
+// to answer, empty when it answered. Rejections are answers, only the webhook being unreachable, timing out or
+// failing the TLS handshake blocks pod creation wholesale.
+func (oClient *Client) probeWebhook(ctx context.Context, wh *probedWebhook, namespace string) string {
+	pod := probePod("", namespace, "probe", "true", map[string]string{managedByLabel: managedByValue}, nil)
+	pod.GenerateName = "octarine-webhook-probe-"
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+	if err != nil {
+		logrus.Warnf("Unable to build the probe pod of webhook %s: %v", wh, err)
+		return ""
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Pod")
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	_, err = oClient.dynamicClient(ctx).Resource(pods).Namespace(namespace).Create(obj, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if err == nil {
+		return ""
+	}
+	msg := err.Error()
+	if strings.Contains(msg, "does not support dry run") {
+		logrus.Debugf("Webhook %s can't be probed, it has side effects: %v", wh, err)
+		return ""
+	}
+	if !strings.Contains(msg, fmt.Sprintf("failed calling webhook %q", wh.name)) {
+		return ""
+	}
+	switch {
+	case strings.Contains(msg, "x509") || strings.Contains(msg, "certificate"):
+		return "its certificate is not trusted: " + msg
+	case strings.Contains(msg, "deadline exceeded") || strings.Contains(msg, "timeout") || strings.Contains(msg, "Timeout"):
+		return "it timed out: " + msg
+	}
+	return "it can't be reached: " + msg
+}
+
+// probeWebhooks exercises every Octarine webhook that fails closed on pod creation, reports the ones which
+// started or stopped failing, and switches failing ones to Ignore when OCTARINE_WEBHOOK_FAIL_OPEN is set
+func (oClient *Client) probeWebhooks(now time.Time) {
+	ctx := context.Background()
+	if oClient.clientset(ctx) == nil || oClient.dynamicClient(ctx) == nil {
+		return
+	}
+	webhooks, err := oClient.managedWebhooks(ctx)
+	if err != nil {
+		logrus.Warnf("Unable to probe the Octarine webhooks: %v", err)
+		return
+	}
+	for _, wh := range webhooks {
+		if !wh.failOpenUntil.IsZero() {
+			// while failing open the probe passes whether or not the webhook works
+			if now.Before(wh.failOpenUntil) {
+				continue
+			}
+			if err := oClient.setFailurePolicy(ctx, wh, admissionv1beta1.Fail, time.Time{}); err != nil {
+				logrus.Error(err)
+				continue
+			}
+			fail := admissionv1beta1.Fail
+			wh.failurePolicy = &fail
+			logrus.Infof("Put the failure policy of webhook %s back to Fail", wh)
+		}
+		if wh.failurePolicy == nil || *wh.failurePolicy != admissionv1beta1.Fail || !wh.admitsPods() {
+			continue
+		}
+		namespaces, err := oClient.blockedNamespaces(ctx, wh)
+		if err != nil {
+			logrus.Warn(err)
+			continue
+		}
+		if len(namespaces) == 0 {
+			continue
+		}
+		oClient.reportWebhook(ctx, wh, namespaces, oClient.probeWebhook(ctx, wh, namespaces[0]), now)
+	}
+}
+
+func (oClient *Client) reportWebhook(ctx context.Context, wh *probedWebhook, namespaces []string, failure string, now time.Time) {
+	key := wh.String()
+	oClient.webhooksMu.Lock()
+	if oClient.failingWebhooks == nil {
+		oClient.failingWebhooks = map[string]bool{}
+	}
+	previously := oClient.failingWebhooks[key]
+	oClient.failingWebhooks[key] = failure != ""
+	oClient.webhooksMu.Unlock()
+
+	if failure == "" {
+		if previously {
+			oClient.eventChan <- &meshes.EventsResponse{
+				EventType: meshes.EventType_INFO,
+				Summary:   fmt.Sprintf("Octarine webhook %s works again", wh.name),
+				Details:   fmt.Sprintf("Pods can be created again in the namespaces of webhook %s.", wh),
+				Source:    sourceWebhookProbe,
+			}
+		}
+		return
+	}
+	if !previously {
+		listed := namespaces
+		if len(listed) > maxListedNamespaces {
+			listed = append(listed[:maxListedNamespaces:maxListedNamespaces], fmt.Sprintf("and %d more", len(namespaces)-maxListedNamespaces))
+		}
+		logrus.Errorf("Octarine webhook %s is failing, %s", wh, failure)
+		oClient.eventChan <- &meshes.EventsResponse{
+			Severity: meshes.Severity_SEVERITY_CRITICAL,
+			Summary:  fmt.Sprintf("Octarine webhook %s is failing, pods can't be created in %d namespace(s)", wh.name, len(namespaces)),
+			Details: fmt.Sprintf("Webhook %s fails closed and %s\nBlocked namespaces: %s",
+				wh, failure, strings.Join(listed, ", ")),
+			Source: sourceWebhookProbe,
+		}
+	}
+	if !failOpen() {
+		return
+	}
+	until := now.Add(durationFromEnv(webhookFailOpenForEnv, defaultWebhookFailOpenFor))
+	if err := oClient.setFailurePolicy(ctx, wh, admissionv1beta1.Ignore, until); err != nil {
+		logrus.Error(err)
+		return
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		EventType: meshes.EventType_WARN,
+		Summary:   fmt.Sprintf("Failure policy of Octarine webhook %s set to Ignore until %s", wh.name, until.UTC().Format(time.RFC3339)),
+		Details: "Pods are created without going through the webhook meanwhile, so they aren't injected or checked by it. " +
+			"The policy goes back to Fail then and the webhook is probed again.",
+		Source: sourceWebhookProbe,
+	}
+}
+
+// setFailurePolicy changes the failure policy of a webhook, recording until when it fails open
+func (oClient *Client) setFailurePolicy(ctx context.Context, wh *probedWebhook, policy admissionv1beta1.FailurePolicyType, until time.Time) error {
+	admission := oClient.clientset(ctx).AdmissionregistrationV1beta1()
+	var err error
+	switch wh.kind {
+	case mutatingWebhooks:
+		var config *admissionv1beta1.MutatingWebhookConfiguration
+		if config, err = admission.MutatingWebhookConfigurations().Get(wh.config, metav1.GetOptions{}); err != nil {
+			break
+		}
+		for i := range config.Webhooks {
+			if config.Webhooks[i].Name == wh.name {
+				config.Webhooks[i].FailurePolicy = &policy
+			}
+		}
+		if err = annotateFailOpen(&config.ObjectMeta, wh.name, until); err == nil {
+			_, err = admission.MutatingWebhookConfigurations().Update(config)
+		}
+	case validatingWebhooks:
+		var config *admissionv1beta1.ValidatingWebhookConfiguration
+		if config, err = admission.ValidatingWebhookConfigurations().Get(wh.config, metav1.GetOptions{}); err != nil {
+			break
+		}
+		for i := range config.Webhooks {
+			if config.Webhooks[i].Name == wh.name {
+				config.Webhooks[i].FailurePolicy = &policy
+			}
+		}
+		if err = annotateFailOpen(&config.ObjectMeta, wh.name, until); err == nil {
+			_, err = admission.ValidatingWebhookConfigurations().Update(config)
+		}
+	}
+	if err != nil {
+		return errors.Wrapf(err, "unable to set the failure policy of webhook %s to %s", wh, policy)
+	}
+	return nil
+}
+
+// annotateFailOpen records until when a webhook fails open, a zero time removes it
+func annotateFailOpen(meta *metav1.ObjectMeta, name string, until time.Time) error {
+	entries := failOpenUntil(meta.GetAnnotations())
+	if until.IsZero() {
+		delete(entries, name)
+	} else {
+		entries[name] = until.UTC()
+	}
+	annotations := meta.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if len(entries) == 0 {
+		delete(annotations, failOpenAnnotation)
+		meta.SetAnnotations(annotations)
+		return nil
+	}
+	value, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	annotations[failOpenAnnotation] = string(value)
+	meta.SetAnnotations(annotations)
+	return nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	windowsOS = "windows"
+
+	// injectOptOutLabel keeps the sidecar out of the pods carrying it, the sidecar only runs on Linux
+	injectOptOutLabel = "octarine.io/inject"
+	injectOptOutValue = "false"
+)
+
+// withoutWindows drops the Windows platforms, the dataplane is only built for Linux
+func withoutWindows(platforms map[platform]bool) map[platform]bool {
+	linux := map[platform]bool{}
+	for p := range platforms {
+		if p.os != windowsOS {
+			linux[p] = true
+		}
+	}
+	return linux
+}
+
+// addInjectionOptOut makes the webhooks of a MutatingWebhookConfiguration skip the pods opted out of injection
+func addInjectionOptOut(data *unstructured.Unstructured) error {
+	webhooks, found, err := nestedObjects(data.Object, "webhooks")
+	if !found || err != nil {
+		return err
+	}
+	for _, webhook := range webhooks {
+		expressions, _, err := nestedObjects(webhook, "objectSelector", "matchExpressions")
+		if err != nil {
+			return err
+		}
+		expressions = append(expressions, map[string]interface{}{
+			"key":      injectOptOutLabel,
+			"operator": string(metav1.LabelSelectorOpNotIn),
+			"values":   []interface{}{injectOptOutValue},
+		})
+		if err := setNestedObjects(webhook, expressions, "objectSelector", "matchExpressions"); err != nil {
+			return err
+		}
+	}
+	return setNestedObjects(data.Object, webhooks, "webhooks")
+}
+
+// targetsWindows tells whether a pod spec can only be scheduled on Windows nodes
+func targetsWindows(spec corev1.PodSpec) bool {
+	for _, key := range []string{osLabel, betaOSLabel} {
+		if spec.NodeSelector[key] == windowsOS {
+			return true
+		}
+	}
+	if spec.Affinity == nil || spec.Affinity.NodeAffinity == nil || spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return false
+	}
+	terms := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	for _, term := range terms {
+		windows := false
+		for _, expr := range term.MatchExpressions {
+			if (expr.Key == osLabel || expr.Key == betaOSLabel) && expr.Operator == corev1.NodeSelectorOpIn &&
+				len(expr.Values) == 1 && expr.Values[0] == windowsOS {
+				windows = true
+			}
+		}
+		// terms are ORed, one term allowing Linux nodes is enough
+		if !windows {
+			return false
+		}
+	}
+	return len(terms) > 0
+}
+
+// windowsWorkloads lists the workloads of a namespace running on Windows which have not opted out of injection
+func (oClient *Client) windowsWorkloads(ctx context.Context, namespace string) ([]string, error) {
+	apps := oClient.clientset(ctx).AppsV1()
+	found := []string{}
+	optedOut := func(labels map[string]string) bool { return labels[injectOptOutLabel] == injectOptOutValue }
+
+	deployments, err := apps.Deployments(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range deployments.Items {
+		if targetsWindows(w.Spec.Template.Spec) && !optedOut(w.Spec.Template.Labels) {
+			found = append(found, "deployment/"+w.Name)
+		}
+	}
+	statefulSets, err := apps.StatefulSets(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range statefulSets.Items {
+		if targetsWindows(w.Spec.Template.Spec) && !optedOut(w.Spec.Template.Labels) {
+			found = append(found, "statefulset/"+w.Name)
+		}
+	}
+	daemonSets, err := apps.DaemonSets(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range daemonSets.Items {
+		if targetsWindows(w.Spec.Template.Spec) && !optedOut(w.Spec.Template.Labels) {
+			found = append(found, "daemonset/"+w.Name)
+		}
+	}
+	return found, nil
+}
+
+// warnWindowsWorkloads tells about the Windows workloads of a namespace injection was enabled in,
+// a Linux sidecar keeps their pods from starting
+func (oClient *Client) warnWindowsWorkloads(ctx context.Context, namespace string) {
+	workloads, err := oClient.windowsWorkloads(ctx, namespace)
+	if err != nil {
+		logrus.Warnf("Unable to look for Windows workloads in namespace %s: %v", namespace, err)
+		return
+	}
+	if len(workloads) == 0 {
+		return
+	}
+	oClient.eventChan <- &meshes.EventsResponse{
+		OperationId: operationIDFrom(ctx),
+		EventType:   meshes.EventType_WARN,
+		Summary:     fmt.Sprintf("Windows workloads in namespace %s can't run the Octarine sidecar", namespace),
+		Details: fmt.Sprintf("Label the pod templates of %s with %s=%s to keep the sidecar out of them.",
+			strings.Join(workloads, ", "), injectOptOutLabel, injectOptOutValue),
+	}
+}
+
+// windowsNote is the capability note of a cluster with Windows nodes
+func windowsNote(platforms map[platform]bool) string {
+	windows := []string{}
+	for p := range platforms {
+		if p.os == windowsOS {
+			windows = append(windows, p.String())
+		}
+	}
+	if len(windows) == 0 {
+		return ""
+	}
+	sort.Strings(windows)
+	return fmt.Sprintf("the cluster has %s nodes: the dataplane is kept off them, and pods scheduled there must be labeled %s=%s to opt out of sidecar injection",
+		strings.Join(windows, ", "), injectOptOutLabel, injectOptOutValue)
+}
@@ -0,0 +1,276 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarinetest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// AssetsEnv is the directory of the etcd and kube-apiserver binaries, as for the envtest of controller-runtime
+	AssetsEnv = "KUBEBUILDER_ASSETS"
+
+	// controlPlaneStartup bounds how long etcd and the API server take to serve
+	controlPlaneStartup = time.Minute
+	// controlPlaneUser is the user of the kubeconfig of the control plane, a cluster admin
+	controlPlaneUser  = "octarine-dev"
+	controlPlaneToken = "octarine-dev-token"
+	serviceIPRange    = "10.0.0.0/24"
+	// maxLogTail is how much of the log of a binary failing to start is returned with the error
+	maxLogTail = 4 << 10
+)
+
+// ControlPlane is a local etcd and kube-apiserver, with no controllers, scheduler or kubelet: objects are
+// stored and validated with the semantics of a real cluster but no workload ever runs
+type ControlPlane struct {
+	dir        string
+	etcd       *process
+	apiServer  *process
+	kubeconfig []byte
+}
+
+// process is a running binary of the control plane, exited is closed once it exits
+type process struct {
+	name   string
+	cmd    *exec.Cmd
+	exited chan struct{}
+}
+
+// StartControlPlane starts etcd and kube-apiserver from the binaries in assetsDir, or in the directory of
+// KUBEBUILDER_ASSETS when empty, and waits for the API server to serve
+func StartControlPlane(assetsDir string) (*ControlPlane, error) {
+	if assetsDir == "" {
+		assetsDir = os.Getenv(AssetsEnv)
+	}
+	if assetsDir == "" {
+		return nil, fmt.Errorf("error: %s must be set to the directory of the etcd and kube-apiserver binaries", AssetsEnv)
+	}
+	for _, binary := range []string{"etcd", "kube-apiserver"} {
+		if _, err := os.Stat(filepath.Join(assetsDir, binary)); err != nil {
+			return nil, errors.Wrapf(err, "unable to find %s in %s", binary, assetsDir)
+		}
+	}
+	dir, err := ioutil.TempDir("", "octarine-control-plane")
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create the directory of the control plane")
+	}
+	cp := &ControlPlane{dir: dir}
+	if err := cp.start(assetsDir); err != nil {
+		cp.Stop()
+		return nil, err
+	}
+	return cp, nil
+}
+
+func (cp *ControlPlane) start(assetsDir string) error {
+	ports, err := freePorts(3)
+	if err != nil {
+		return err
+	}
+	etcdURL := fmt.Sprintf("http://127.0.0.1:%d", ports[0])
+	cp.etcd, err = cp.run(filepath.Join(assetsDir, "etcd"),
+		"--data-dir="+filepath.Join(cp.dir, "etcd"),
+		"--listen-client-urls="+etcdURL,
+		"--advertise-client-urls="+etcdURL,
+		fmt.Sprintf("--listen-peer-urls=http://127.0.0.1:%d", ports[1]),
+	)
+	if err != nil {
+		return err
+	}
+
+	// the API server signs and verifies ServiceAccount tokens with this key
+	saKey, err := cp.writeServiceAccountKey()
+	if err != nil {
+		return err
+	}
+	tokens := filepath.Join(cp.dir, "tokens.csv")
+	if err := ioutil.WriteFile(tokens, []byte(fmt.Sprintf("%s,%s,%s,\"system:masters\"\n", controlPlaneToken, controlPlaneUser, controlPlaneUser)), 0600); err != nil {
+		return errors.Wrap(err, "unable to write the tokens of the API server")
+	}
+	server := fmt.Sprintf("https://127.0.0.1:%d", ports[2])
+	cp.apiServer, err = cp.run(filepath.Join(assetsDir, "kube-apiserver"),
+		"--etcd-servers="+etcdURL,
+		"--bind-address=127.0.0.1",
+		fmt.Sprintf("--secure-port=%d", ports[2]),
+		// the API server generates a self-signed serving certificate there
+		"--cert-dir="+filepath.Join(cp.dir, "certs"),
+		"--token-auth-file="+tokens,
+		"--authorization-mode=RBAC",
+		"--service-account-key-file="+saKey,
+		"--service-account-signing-key-file="+saKey,
+		"--service-account-issuer=https://kubernetes.default.svc",
+		"--service-cluster-ip-range="+serviceIPRange,
+		"--allow-privileged=true",
+		// no controller creates the default ServiceAccounts the admission plugin requires pods to have
+		"--disable-admission-plugins=ServiceAccount",
+	)
+	if err != nil {
+		return err
+	}
+	if err := cp.waitForHealthy(server); err != nil {
+		return err
+	}
+
+	config := clientcmdapi.NewConfig()
+	config.Clusters["octarine-dev"] = &clientcmdapi.Cluster{Server: server, InsecureSkipTLSVerify: true}
+	config.AuthInfos[controlPlaneUser] = &clientcmdapi.AuthInfo{Token: controlPlaneToken}
+	config.Contexts["octarine-dev"] = &clientcmdapi.Context{Cluster: "octarine-dev", AuthInfo: controlPlaneUser}
+	config.CurrentContext = "octarine-dev"
+	cp.kubeconfig, err = clientcmd.Write(*config)
+	if err != nil {
+		return errors.Wrap(err, "unable to write the kubeconfig of the control plane")
+	}
+	return ioutil.WriteFile(cp.KubeconfigPath(), cp.kubeconfig, 0600)
+}
+
+// run starts a binary of the control plane, logging to a file of the directory of the control plane
+func (cp *ControlPlane) run(binary string, args ...string) (*process, error) {
+	p := &process{name: filepath.Base(binary), exited: make(chan struct{})}
+	log, err := os.Create(filepath.Join(cp.dir, p.name+".log"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to create the log of %s", p.name)
+	}
+	p.cmd = exec.Command(binary, args...)
+	p.cmd.Stdout = log
+	p.cmd.Stderr = log
+	if err := p.cmd.Start(); err != nil {
+		log.Close()
+		return nil, errors.Wrapf(err, "unable to start %s", p.name)
+	}
+	go func() {
+		p.cmd.Wait()
+		log.Close()
+		close(p.exited)
+	}()
+	return p, nil
+}
+
+func (cp *ControlPlane) writeServiceAccountKey() (string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to generate the ServiceAccount key")
+	}
+	path := filepath.Join(cp.dir, "sa.key")
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return "", errors.Wrap(err, "unable to write the ServiceAccount key")
+	}
+	return path, nil
+}
+
+// waitForHealthy waits for the API server to answer its health check, failing early when it or etcd exited
+func (cp *ControlPlane) waitForHealthy(server string) error {
+	client := &http.Client{
+		Timeout:   5 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	deadline := time.Now().Add(controlPlaneStartup)
+	for {
+		for _, p := range []*process{cp.etcd, cp.apiServer} {
+			select {
+			case <-p.exited:
+				return fmt.Errorf("error: %s exited: %s", p.name, cp.logTail(p))
+			default:
+			}
+		}
+		req, err := http.NewRequest(http.MethodGet, server+"/healthz", nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+controlPlaneToken)
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("error: the API server didn't serve within %s: %s", controlPlaneStartup, cp.logTail(cp.apiServer))
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// logTail is the end of the log of a binary of the control plane
+func (cp *ControlPlane) logTail(p *process) string {
+	data, err := ioutil.ReadFile(filepath.Join(cp.dir, p.name+".log"))
+	if err != nil {
+		return err.Error()
+	}
+	if len(data) > maxLogTail {
+		data = data[len(data)-maxLogTail:]
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// Kubeconfig is a kubeconfig of a cluster admin of the control plane
+func (cp *ControlPlane) Kubeconfig() []byte {
+	return cp.kubeconfig
+}
+
+// KubeconfigPath is the file the kubeconfig is written to, for kubectl to look at the objects the operations made
+func (cp *ControlPlane) KubeconfigPath() string {
+	return filepath.Join(cp.dir, "kubeconfig")
+}
+
+// Stop kills the API server and etcd and removes their data
+func (cp *ControlPlane) Stop() {
+	for _, p := range []*process{cp.apiServer, cp.etcd} {
+		if p == nil {
+			continue
+		}
+		p.cmd.Process.Kill()
+		<-p.exited
+	}
+	os.RemoveAll(cp.dir)
+}
+
+// freePorts finds ports of the loopback interface nothing listens on
+func freePorts(n int) ([]int, error) {
+	ports := make([]int, 0, n)
+	listeners := make([]net.Listener, 0, n)
+	defer func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}()
+	for i := 0; i < n; i++ {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to find a free port for the control plane")
+		}
+		listeners = append(listeners, l)
+		ports = append(ports, l.Addr().(*net.TCPAddr).Port)
+	}
+	return ports, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarinetest
+
+import (
+	"fmt"
+
+	"github.com/layer5io/meshery-octarine/meshes"
+)
+
+const (
+	// InstallOperation and CustomOperation name the operations of the adapter, custom_label_delete is applied like
+	// custom
+	InstallOperation = "octarine_install"
+	CustomOperation  = "custom"
+
+	// DataplaneNamespace is the namespace of the install fixture
+	DataplaneNamespace = "octarine-dataplane"
+)
+
+// InstallManifest is a trimmed down dataplane, with the objects the Meshery server looks for after an install
+const InstallManifest = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: octarine-dataplane
+  labels:
+    app.kubernetes.io/managed-by: meshery-octarine
+    meshery.layer5.io/deployment: default
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: octarine-anchor
+  namespace: octarine-dataplane
+  labels:
+    app.kubernetes.io/managed-by: meshery-octarine
+    meshery.layer5.io/deployment: default
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: octarine-guardrails
+  namespace: octarine-dataplane
+  labels:
+    app: octarine-guardrails
+    app.kubernetes.io/managed-by: meshery-octarine
+    meshery.layer5.io/deployment: default
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: octarine-guardrails
+  template:
+    metadata:
+      labels:
+        app: octarine-guardrails
+    spec:
+      containers:
+      - name: guardrails
+        image: octarinesec/guardrails:latest
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: MutatingWebhookConfiguration
+metadata:
+  name: octarine-injection
+  labels:
+    app.kubernetes.io/managed-by: meshery-octarine
+    meshery.layer5.io/deployment: default
+webhooks: []
+`
+
+// InstallEvents are the events of a successful octarine_install, or of its removal with deleteOp
+func InstallEvents(deleteOp bool) []*meshes.EventsResponse {
+	done := "deployed"
+	if deleteOp {
+		done = "removed"
+	}
+	return []*meshes.EventsResponse{{
+		EventType: meshes.EventType_INFO,
+		Summary:   fmt.Sprintf("Octarine %s successfully", done),
+		Details:   fmt.Sprintf("The latest version of Octarine is now %s.", done),
+	}}
+}
+
+// FailedEvent is the ERROR event failing an operation, to script an operation which fails
+func FailedEvent(summary, details string) *meshes.EventsResponse {
+	return &meshes.EventsResponse{
+		EventType: meshes.EventType_ERROR,
+		Summary:   summary,
+		Details:   details,
+	}
+}
+
+// InstallRequest is the request installing the default deployment
+func InstallRequest(opID string) *meshes.ApplyRuleRequest {
+	return &meshes.ApplyRuleRequest{OperationId: opID, OpName: InstallOperation, Namespace: DataplaneNamespace}
+}
+
+// CustomRequest is the request applying a manifest to a namespace with the custom operation
+func CustomRequest(opID, namespace, manifest string) *meshes.ApplyRuleRequest {
+	return &meshes.ApplyRuleRequest{OperationId: opID, OpName: CustomOperation, Namespace: namespace, CustomBody: manifest}
+}
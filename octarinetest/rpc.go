@@ -0,0 +1,365 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octarinetest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/layer5io/meshery-octarine/meshes"
+)
+
+// CreateMeshInstance accepts any cluster, the fake has none
+func (s *Server) CreateMeshInstance(_ context.Context, req *meshes.CreateMeshInstanceRequest) (*meshes.CreateMeshInstanceResponse, error) {
+	resp := &meshes.CreateMeshInstanceResponse{}
+	if err := s.respond("CreateMeshInstance", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) MeshName(_ context.Context, req *meshes.MeshNameRequest) (*meshes.MeshNameResponse, error) {
+	resp := &meshes.MeshNameResponse{}
+	if err := s.respond("MeshName", req, resp); err != nil {
+		return nil, err
+	}
+	if resp.GetName() == "" {
+		resp.Name = "Octarine"
+	}
+	return resp, nil
+}
+
+// SupportedOperations lists the operations of the adapter, unless a response is set
+func (s *Server) SupportedOperations(ctx context.Context, req *meshes.SupportedOperationsRequest) (*meshes.SupportedOperationsResponse, error) {
+	resp := &meshes.SupportedOperationsResponse{}
+	if err := s.respond("SupportedOperations", req, resp); err != nil {
+		return nil, err
+	}
+	if len(resp.GetOps()) > 0 || resp.GetError() != "" {
+		return resp, nil
+	}
+	return s.adapter.SupportedOperations(ctx, req)
+}
+
+// ApplyOperation applies the manifest of an operation to the objects of the fake, the custom body for the custom
+// operations, then emits the scripted events of the operation and records its result
+func (s *Server) ApplyOperation(ctx context.Context, req *meshes.ApplyRuleRequest) (*meshes.ApplyRuleResponse, error) {
+	resp := &meshes.ApplyRuleResponse{}
+	if err := s.respond("ApplyOperation", req, resp); err != nil {
+		return nil, err
+	}
+	ok, err := s.supported(ctx, req.GetOpName())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("error: %s is not a valid operation name", req.GetOpName())
+	}
+	started := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	manifest := s.manifests[req.GetOpName()]
+	if strings.HasPrefix(req.GetOpName(), CustomOperation) {
+		manifest = req.GetCustomBody()
+	}
+	resources, err := s.apply(manifest, req.GetNamespace(), req.GetDeleteOp())
+	if err != nil {
+		return nil, err
+	}
+	result := finished(req, started)
+	result.Resources = resources
+	for _, scripted := range s.scripts[scriptKey{opName: req.GetOpName(), deleteOp: req.GetDeleteOp()}] {
+		event := proto.Clone(scripted).(*meshes.EventsResponse)
+		event.OperationId = req.GetOperationId()
+		event.Elapsed = time.Since(started).Round(time.Millisecond).String()
+		s.publish(event)
+		switch event.GetEventType() {
+		case meshes.EventType_WARN:
+			result.Warnings++
+		case meshes.EventType_ERROR:
+			result.Status = "failed"
+			result.Errors = append(result.Errors, strings.TrimSpace(event.GetSummary()+"\n"+event.GetDetails()))
+		}
+	}
+	if req.GetOperationId() != "" {
+		s.results[req.GetOperationId()] = result
+	}
+	resp.OperationId = req.GetOperationId()
+	return resp, nil
+}
+
+// StreamEvents streams the events of the operations and those emitted, the events emitted while no stream was
+// open first
+func (s *Server) StreamEvents(req *meshes.EventsRequest, stream meshes.MeshService_StreamEventsServer) error {
+	if err := s.respond("StreamEvents", req, &meshes.EventsResponse{}); err != nil {
+		return err
+	}
+	events := make(chan *meshes.EventsResponse, streamBuffer)
+	s.mu.Lock()
+	for _, event := range s.backlog {
+		events <- event
+	}
+	s.backlog = nil
+	s.streams[events] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.streams, events)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case event := <-events:
+			if event.GetSeverity() < req.GetMinSeverity() {
+				continue
+			}
+			if id := req.GetOperationId(); id != "" && event.GetOperationId() != id {
+				continue
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// GetOperationResult returns the result of an operation the fake ran, unless a response is set
+func (s *Server) GetOperationResult(_ context.Context, req *meshes.GetOperationResultRequest) (*meshes.GetOperationResultResponse, error) {
+	resp := &meshes.GetOperationResultResponse{}
+	if err := s.respond("GetOperationResult", req, resp); err != nil {
+		return nil, err
+	}
+	if resp.GetOperationId() != "" || resp.GetError() != "" {
+		return resp, nil
+	}
+	if req.GetOperationId() == "" {
+		return &meshes.GetOperationResultResponse{Error: "error: an operation id is required"}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r := s.results[req.GetOperationId()]; r != nil {
+		return proto.Clone(r).(*meshes.GetOperationResultResponse), nil
+	}
+	return &meshes.GetOperationResultResponse{Error: fmt.Sprintf("error: no result of operation %s", req.GetOperationId())}, nil
+}
+
+// The other RPCs return the response set for them, empty otherwise
+
+func (s *Server) ClusterCapabilities(_ context.Context, req *meshes.ClusterCapabilitiesRequest) (*meshes.ClusterCapabilitiesResponse, error) {
+	resp := &meshes.ClusterCapabilitiesResponse{}
+	if err := s.respond("ClusterCapabilities", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) ProxyVersions(_ context.Context, req *meshes.ProxyVersionsRequest) (*meshes.ProxyVersionsResponse, error) {
+	resp := &meshes.ProxyVersionsResponse{}
+	if err := s.respond("ProxyVersions", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) EnforcementStatus(_ context.Context, req *meshes.EnforcementStatusRequest) (*meshes.EnforcementStatusResponse, error) {
+	resp := &meshes.EnforcementStatusResponse{}
+	if err := s.respond("EnforcementStatus", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) PolicyViolations(_ context.Context, req *meshes.PolicyViolationsRequest) (*meshes.PolicyViolationsResponse, error) {
+	resp := &meshes.PolicyViolationsResponse{}
+	if err := s.respond("PolicyViolations", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) AcknowledgeAlert(_ context.Context, req *meshes.AcknowledgeAlertRequest) (*meshes.AcknowledgeAlertResponse, error) {
+	resp := &meshes.AcknowledgeAlertResponse{}
+	if err := s.respond("AcknowledgeAlert", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) MuteAlert(_ context.Context, req *meshes.MuteAlertRequest) (*meshes.MuteAlertResponse, error) {
+	resp := &meshes.MuteAlertResponse{}
+	if err := s.respond("MuteAlert", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) ExportKubeconfig(_ context.Context, req *meshes.ExportKubeconfigRequest) (*meshes.ExportKubeconfigResponse, error) {
+	resp := &meshes.ExportKubeconfigResponse{}
+	if err := s.respond("ExportKubeconfig", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) ScheduleOperation(_ context.Context, req *meshes.ScheduleOperationRequest) (*meshes.ScheduleOperationResponse, error) {
+	resp := &meshes.ScheduleOperationResponse{}
+	if err := s.respond("ScheduleOperation", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) ListSchedules(_ context.Context, req *meshes.ListSchedulesRequest) (*meshes.ListSchedulesResponse, error) {
+	resp := &meshes.ListSchedulesResponse{}
+	if err := s.respond("ListSchedules", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) DeleteSchedule(_ context.Context, req *meshes.DeleteScheduleRequest) (*meshes.DeleteScheduleResponse, error) {
+	resp := &meshes.DeleteScheduleResponse{}
+	if err := s.respond("DeleteSchedule", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) EstimateFootprint(_ context.Context, req *meshes.EstimateFootprintRequest) (*meshes.EstimateFootprintResponse, error) {
+	resp := &meshes.EstimateFootprintResponse{}
+	if err := s.respond("EstimateFootprint", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) LintTemplates(_ context.Context, req *meshes.LintTemplatesRequest) (*meshes.LintTemplatesResponse, error) {
+	resp := &meshes.LintTemplatesResponse{}
+	if err := s.respond("LintTemplates", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) AdapterCapabilities(_ context.Context, req *meshes.AdapterCapabilitiesRequest) (*meshes.AdapterCapabilitiesResponse, error) {
+	resp := &meshes.AdapterCapabilitiesResponse{}
+	if err := s.respond("AdapterCapabilities", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) Inventory(_ context.Context, req *meshes.InventoryRequest) (*meshes.InventoryResponse, error) {
+	resp := &meshes.InventoryResponse{}
+	if err := s.respond("Inventory", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) RenderOperation(_ context.Context, req *meshes.RenderOperationRequest) (*meshes.RenderOperationResponse, error) {
+	resp := &meshes.RenderOperationResponse{}
+	if err := s.respond("RenderOperation", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) WorkloadIdentities(_ context.Context, req *meshes.WorkloadIdentitiesRequest) (*meshes.WorkloadIdentitiesResponse, error) {
+	resp := &meshes.WorkloadIdentitiesResponse{}
+	if err := s.respond("WorkloadIdentities", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) VetReport(_ context.Context, req *meshes.VetReportRequest) (*meshes.VetReportResponse, error) {
+	resp := &meshes.VetReportResponse{}
+	if err := s.respond("VetReport", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) LatencyProbeReport(_ context.Context, req *meshes.LatencyProbeReportRequest) (*meshes.LatencyProbeReportResponse, error) {
+	resp := &meshes.LatencyProbeReportResponse{}
+	if err := s.respond("LatencyProbeReport", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) QueryEvents(_ context.Context, req *meshes.QueryEventsRequest) (*meshes.QueryEventsResponse, error) {
+	resp := &meshes.QueryEventsResponse{}
+	if err := s.respond("QueryEvents", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) ListActiveOperations(_ context.Context, req *meshes.ListActiveOperationsRequest) (*meshes.ListActiveOperationsResponse, error) {
+	resp := &meshes.ListActiveOperationsResponse{}
+	if err := s.respond("ListActiveOperations", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) PreviewTelemetry(_ context.Context, req *meshes.PreviewTelemetryRequest) (*meshes.PreviewTelemetryResponse, error) {
+	resp := &meshes.PreviewTelemetryResponse{}
+	if err := s.respond("PreviewTelemetry", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) ImageManifests(_ context.Context, req *meshes.ImageManifestsRequest) (*meshes.ImageManifestsResponse, error) {
+	resp := &meshes.ImageManifestsResponse{}
+	if err := s.respond("ImageManifests", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) ListTrashedPolicies(_ context.Context, req *meshes.ListTrashedPoliciesRequest) (*meshes.ListTrashedPoliciesResponse, error) {
+	resp := &meshes.ListTrashedPoliciesResponse{}
+	if err := s.respond("ListTrashedPolicies", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) RestorePolicy(_ context.Context, req *meshes.RestorePolicyRequest) (*meshes.RestorePolicyResponse, error) {
+	resp := &meshes.RestorePolicyResponse{}
+	if err := s.respond("RestorePolicy", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Server) LicenseStatus(_ context.Context, req *meshes.LicenseStatusRequest) (*meshes.LicenseStatusResponse, error) {
+	resp := &meshes.LicenseStatusResponse{}
+	if err := s.respond("LicenseStatus", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,390 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package octarinetest provides a fake of the Octarine adapter, for the Meshery server and CI to integration test
+// against without a cluster. The fake serves the MeshService from memory: the manifests of the operations are
+// applied to an in-memory object store, the events of each operation are scripted, and the responses of the
+// other RPCs can be set. Requests are validated like the adapter validates them.
+package octarinetest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/golang/protobuf/proto"
+	"github.com/layer5io/meshery-octarine/meshes"
+	"github.com/layer5io/meshery-octarine/octarine"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// streamBuffer is how many events a stream falling behind keeps, the later ones are dropped
+	streamBuffer = 100
+	// maxBacklog is how many events are kept for the next stream while none is open
+	maxBacklog = 100
+)
+
+// clusterScoped are the kinds the namespace of an operation doesn't apply to
+var clusterScoped = map[string]bool{
+	"Namespace":                      true,
+	"ClusterRole":                    true,
+	"ClusterRoleBinding":             true,
+	"CustomResourceDefinition":       true,
+	"MutatingWebhookConfiguration":   true,
+	"ValidatingWebhookConfiguration": true,
+	"PersistentVolume":               true,
+	"StorageClass":                   true,
+	"PriorityClass":                  true,
+}
+
+// Call is a request the fake received
+type Call struct {
+	Method  string
+	Request proto.Message
+}
+
+type scriptKey struct {
+	opName   string
+	deleteOp bool
+}
+
+type objectKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+func (k objectKey) String() string {
+	if k.namespace == "" {
+		return fmt.Sprintf("%s %s", k.kind, k.name)
+	}
+	return fmt.Sprintf("%s %s/%s", k.kind, k.namespace, k.name)
+}
+
+// Server is a fake MeshService. Its zero value isn't usable, create it with NewServer.
+type Server struct {
+	mu        sync.Mutex
+	objects   map[objectKey]*unstructured.Unstructured
+	calls     []Call
+	responses map[string]proto.Message
+	failures  map[string]error
+	manifests map[string]string
+	scripts   map[scriptKey][]*meshes.EventsResponse
+	results   map[string]*meshes.GetOperationResultResponse
+	streams   map[chan *meshes.EventsResponse]bool
+	backlog   []*meshes.EventsResponse
+	// sequence numbers the published events like the adapter does
+	sequence uint64
+
+	// adapter lists the operations of the adapter, it is never connected to a cluster
+	adapter *octarine.Client
+}
+
+// NewServer creates a fake with the manifest and the events of the install fixture for octarine_install, and no
+// objects
+func NewServer() *Server {
+	s := &Server{
+		objects:   map[objectKey]*unstructured.Unstructured{},
+		responses: map[string]proto.Message{},
+		failures:  map[string]error{},
+		manifests: map[string]string{},
+		scripts:   map[scriptKey][]*meshes.EventsResponse{},
+		results:   map[string]*meshes.GetOperationResultResponse{},
+		streams:   map[chan *meshes.EventsResponse]bool{},
+		adapter:   &octarine.Client{},
+	}
+	s.Manifest(InstallOperation, InstallManifest)
+	s.Script(InstallOperation, false, InstallEvents(false)...)
+	s.Script(InstallOperation, true, InstallEvents(true)...)
+	return s
+}
+
+// Manifest sets the manifest an operation applies, or deletes with delete_op. The custom operations apply
+// their custom body instead.
+func (s *Server) Manifest(opName, manifest string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.manifests[opName] = manifest
+}
+
+// Script sets the events an operation emits once its manifest is applied, or deleted with deleteOp, in order. An
+// ERROR event fails the operation and a WARN event counts as a warning of its result.
+func (s *Server) Script(opName string, deleteOp bool, events ...*meshes.EventsResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts[scriptKey{opName: opName, deleteOp: deleteOp}] = events
+}
+
+// Respond sets the response of an RPC, named like ProxyVersions, the RPCs have an empty response otherwise
+func (s *Server) Respond(method string, resp proto.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[method] = proto.Clone(resp)
+}
+
+// Fail makes an RPC return an error, until it is failed with nil
+func (s *Server) Fail(method string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		delete(s.failures, method)
+		return
+	}
+	s.failures[method] = err
+}
+
+// Emit sends events to the streams, like the adapter's watchers do outside of operations
+func (s *Server) Emit(events ...*meshes.EventsResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range events {
+		s.publish(proto.Clone(event).(*meshes.EventsResponse))
+	}
+}
+
+// Calls lists the requests an RPC received, oldest first, every RPC when the method is empty
+func (s *Server) Calls(method string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	calls := []Call{}
+	for _, c := range s.calls {
+		if method == "" || c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Objects lists the objects the operations applied, sorted by kind, namespace and name
+func (s *Server) Objects() []*unstructured.Unstructured {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]objectKey, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	objects := make([]*unstructured.Unstructured, 0, len(keys))
+	for _, key := range keys {
+		objects = append(objects, s.objects[key].DeepCopy())
+	}
+	return objects
+}
+
+// Object returns an applied object, nil when there is none. The namespace is empty for cluster scoped kinds.
+func (s *Server) Object(kind, namespace, name string) *unstructured.Unstructured {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if obj := s.objects[objectKey{kind: kind, namespace: namespace, name: name}]; obj != nil {
+		return obj.DeepCopy()
+	}
+	return nil
+}
+
+// Reset forgets the objects, the calls, the results and the pending events, keeping the scripts and the
+// responses
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects = map[objectKey]*unstructured.Unstructured{}
+	s.calls = nil
+	s.results = map[string]*meshes.GetOperationResultResponse{}
+	s.backlog = nil
+}
+
+// Start serves the fake on a TCP address, like localhost:0 for any free port, for clients in other processes.
+// It returns the address it listens on and a function stopping it.
+func (s *Server) Start(addr string) (string, func(), error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", nil, err
+	}
+	srv := s.grpcServer()
+	go func() { _ = srv.Serve(lis) }()
+	return lis.Addr().String(), srv.Stop, nil
+}
+
+// Dial serves the fake in memory and connects a client to it, for tests in the same process. The returned
+// function closes the client and stops the fake.
+func (s *Server) Dial(ctx context.Context) (meshes.MeshServiceClient, func(), error) {
+	lis := bufconn.Listen(1 << 20)
+	srv := s.grpcServer()
+	go func() { _ = srv.Serve(lis) }()
+	conn, err := grpc.DialContext(ctx, "octarinetest",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure())
+	if err != nil {
+		srv.Stop()
+		return nil, nil, err
+	}
+	return meshes.NewMeshServiceClient(conn), func() {
+		_ = conn.Close()
+		srv.Stop()
+	}, nil
+}
+
+func (s *Server) grpcServer() *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(octarine.LocaleInterceptor, octarine.ValidationInterceptor),
+		grpc.ChainStreamInterceptor(octarine.LocaleStreamInterceptor, octarine.ValidationStreamInterceptor),
+	)
+	meshes.RegisterMeshServiceServer(srv, s)
+	return srv
+}
+
+// respond records a call and fills its response with the one set for the RPC
+func (s *Server) respond(method string, req, resp proto.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, Call{Method: method, Request: proto.Clone(req)})
+	if err := s.failures[method]; err != nil {
+		return err
+	}
+	if canned := s.responses[method]; canned != nil {
+		proto.Merge(resp, canned)
+	}
+	return nil
+}
+
+// publish sends an event to every stream, or keeps it for the next stream when none is open
+func (s *Server) publish(event *meshes.EventsResponse) {
+	if event.GetSeverity() == meshes.Severity_SEVERITY_UNSPECIFIED {
+		switch event.GetEventType() {
+		case meshes.EventType_WARN:
+			event.Severity = meshes.Severity_SEVERITY_WARN
+		case meshes.EventType_ERROR:
+			event.Severity = meshes.Severity_SEVERITY_ERROR
+		default:
+			event.Severity = meshes.Severity_SEVERITY_INFO
+		}
+	}
+	if event.GetTime() == "" {
+		event.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	s.sequence++
+	event.Sequence = s.sequence
+	if len(s.streams) == 0 {
+		if len(s.backlog) == maxBacklog {
+			s.backlog = s.backlog[1:]
+		}
+		s.backlog = append(s.backlog, event)
+		return
+	}
+	for stream := range s.streams {
+		select {
+		case stream <- event:
+		default:
+		}
+	}
+}
+
+// apply applies or deletes the objects of a manifest and returns them as kind namespace/name
+func (s *Server) apply(manifest, namespace string, deleteOp bool) ([]string, error) {
+	objects, err := parseManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	resources := []string{}
+	for _, obj := range objects {
+		if obj.GetNamespace() == "" && !clusterScoped[obj.GetKind()] {
+			obj.SetNamespace(namespace)
+		}
+		key := objectKey{kind: obj.GetKind(), namespace: obj.GetNamespace(), name: obj.GetName()}
+		if deleteOp {
+			delete(s.objects, key)
+		} else {
+			s.objects[key] = obj
+		}
+		resources = append(resources, key.String())
+	}
+	sort.Strings(resources)
+	return resources, nil
+}
+
+// parseManifest parses the YAML or JSON documents of a manifest, expanding lists
+func parseManifest(manifest string) ([]*unstructured.Unstructured, error) {
+	objects := []*unstructured.Unstructured{}
+	for i, doc := range strings.Split(manifest, "\n---") {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		data, err := yaml.YAMLToJSON([]byte(doc))
+		if err != nil {
+			return nil, fmt.Errorf("error: unable to parse document %d of the manifest: %v", i+1, err)
+		}
+		content := map[string]interface{}{}
+		if err := json.Unmarshal(data, &content); err != nil {
+			return nil, fmt.Errorf("error: document %d of the manifest is not an object: %v", i+1, err)
+		}
+		obj := &unstructured.Unstructured{Object: content}
+		if obj.IsList() {
+			err := obj.EachListItem(func(item runtime.Object) error {
+				objects = append(objects, item.(*unstructured.Unstructured))
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		objects = append(objects, obj)
+	}
+	for _, obj := range objects {
+		if obj.GetKind() == "" || obj.GetName() == "" {
+			return nil, fmt.Errorf("error: an object of the manifest has no kind or no name")
+		}
+	}
+	return objects, nil
+}
+
+// supported tells whether the adapter has an operation
+func (s *Server) supported(ctx context.Context, opName string) (bool, error) {
+	resp, err := s.adapter.SupportedOperations(ctx, &meshes.SupportedOperationsRequest{Filter: opName, IncludeUnavailable: true})
+	if err != nil {
+		return false, err
+	}
+	for _, op := range resp.GetOps() {
+		if op.GetKey() == opName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// finished is the result of an operation which just ran
+func finished(req *meshes.ApplyRuleRequest, started time.Time) *meshes.GetOperationResultResponse {
+	now := time.Now()
+	return &meshes.GetOperationResultResponse{
+		OperationId: req.GetOperationId(),
+		OpName:      req.GetOpName(),
+		Namespace:   req.GetNamespace(),
+		Username:    req.GetUsername(),
+		DeleteOp:    req.GetDeleteOp(),
+		Status:      "succeeded",
+		Started:     started.UTC().Format(time.RFC3339),
+		Finished:    now.UTC().Format(time.RFC3339),
+		Duration:    now.Sub(started).Round(time.Millisecond).String(),
+		ResumedFrom: req.GetResumeOperationId(),
+	}
+}
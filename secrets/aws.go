@@ -0,0 +1,164 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const awsService = "secretsmanager"
+
+// lookupAWS reads a secret of AWS Secrets Manager, by name or ARN, with the credentials of the AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables. The key picks a field of a secret
+// holding JSON. The region is the one of the reference, of its ARN or of AWS_REGION, and the endpoint
+// parameter addresses a compatible service.
+func lookupAWS(ctx context.Context, r Reference) (string, error) {
+	accessKey, secretKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return "", fmt.Errorf("error: secret %s requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY", r)
+	}
+	region := r.Query.Get("region")
+	if arn := strings.Split(r.Location, ":"); region == "" && len(arn) > 3 && arn[0] == "arn" {
+		region = arn[3]
+	}
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := fmt.Sprintf("https://%s.%s.amazonaws.com/", awsService, region)
+	if e := r.Query.Get("endpoint"); e != "" {
+		endpoint = strings.TrimRight(e, "/") + "/"
+	}
+	body, err := json.Marshal(map[string]string{"SecretId": r.Location})
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to read secret %s", r)
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to read secret %s", r)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	signAWS(req, body, region, accessKey, secretKey, os.Getenv("AWS_SESSION_TOKEN"), time.Now().UTC())
+	resp, err := newHTTPClient().Do(req.WithContext(ctx))
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to read secret %s", r)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error: unable to read secret %s: AWS Secrets Manager answered %s", r, resp.Status)
+	}
+	value := struct {
+		SecretString string `json:"SecretString"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&value); err != nil {
+		return "", errors.Wrapf(err, "unable to parse secret %s", r)
+	}
+	if r.Key == "" {
+		return value.SecretString, nil
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(value.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("error: secret %s doesn't hold JSON, its key %s can't be read", r.Location, r.Key)
+	}
+	field, ok := fields[r.Key]
+	if !ok {
+		return "", fmt.Errorf("error: secret %s has no key %s", r.Location, r.Key)
+	}
+	if s, ok := field.(string); ok {
+		return s, nil
+	}
+	out, err := json.Marshal(field)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to write secret %s", r)
+	}
+	return string(out), nil
+}
+
+// signAWS adds the AWS Signature V4 headers to a request of Secrets Manager
+func signAWS(req *http.Request, body []byte, region, accessKey, secretKey, sessionToken string, now time.Time) {
+	payload := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(payload[:])
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+	}
+
+	names := []string{"content-type", "host", "x-amz-date"}
+	if sessionToken != "" {
+		names = append(names, "x-amz-security-token")
+	}
+	names = append(names, "x-amz-target")
+	headers := ""
+	for _, name := range names {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		headers += name + ":" + strings.TrimSpace(value) + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonical := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		headers,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/" + awsService + "/aws4_request"
+	digest := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	for _, part := range []string{region, awsService, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery is the query string sorted by key, which url.Values.Encode does, with spaces as %20
+func canonicalQuery(q url.Values) string {
+	return strings.Replace(q.Encode(), "+", "%20", -1)
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secrets resolves the secret references of templates from external secret managers.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const requestTimeout = 30 * time.Second
+
+// Reference names a secret, or a key of it:
+//
+//	vault://secret/data/octarine#token
+//	awssm://octarine/controlplane?region=eu-west-1#token
+//	k8s://namespace/name#key
+type Reference struct {
+	Scheme string
+	// Location is the path of the secret in its manager, an ARN for AWS
+	Location string
+	// Key picks a field of a secret holding several, empty for the whole secret
+	Key   string
+	Query url.Values
+}
+
+func (r Reference) String() string {
+	s := r.Scheme + "://" + r.Location
+	if len(r.Query) > 0 {
+		s += "?" + r.Query.Encode()
+	}
+	if r.Key != "" {
+		s += "#" + r.Key
+	}
+	return s
+}
+
+// Parse reads a reference. It isn't parsed as a URL as the ARNs of AWS secrets aren't valid hosts.
+func Parse(ref string) (Reference, error) {
+	parts := strings.SplitN(ref, "://", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return Reference{}, fmt.Errorf("error: secret reference %q is not of the form scheme://location#key", ref)
+	}
+	r := Reference{Scheme: strings.ToLower(parts[0]), Location: parts[1]}
+	if i := strings.Index(r.Location, "#"); i >= 0 {
+		r.Location, r.Key = r.Location[:i], r.Location[i+1:]
+	}
+	if i := strings.Index(r.Location, "?"); i >= 0 {
+		q, err := url.ParseQuery(r.Location[i+1:])
+		if err != nil {
+			return Reference{}, errors.Wrapf(err, "unable to parse secret reference %q", ref)
+		}
+		r.Location, r.Query = r.Location[:i], q
+	}
+	r.Location = strings.Trim(r.Location, "/")
+	if r.Location == "" {
+		return Reference{}, fmt.Errorf("error: secret reference %q names no secret", ref)
+	}
+	switch r.Scheme {
+	case "vault", "k8s":
+		if r.Key == "" {
+			return Reference{}, fmt.Errorf("error: secret reference %q names no key, %s secrets hold several", ref, r.Scheme)
+		}
+	case "awssm":
+	default:
+		return Reference{}, fmt.Errorf("error: secret reference %q has an unknown scheme, use vault, awssm or k8s", ref)
+	}
+	return r, nil
+}
+
+// Lookup reads a secret of an external manager, the k8s references are resolved by the adapter itself with
+// the credentials of the cluster
+func Lookup(ctx context.Context, r Reference) (string, error) {
+	switch r.Scheme {
+	case "vault":
+		return lookupVault(ctx, r)
+	case "awssm":
+		return lookupAWS(ctx, r)
+	}
+	return "", fmt.Errorf("error: secret reference %s can't be looked up in an external manager", r)
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: requestTimeout}
+}
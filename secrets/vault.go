@@ -0,0 +1,230 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Vault is a client of the Vault server of VAULT_ADDR, in the namespace of VAULT_NAMESPACE
+type Vault struct {
+	http      *http.Client
+	addr      string
+	namespace string
+
+	mu    sync.Mutex
+	token string
+}
+
+// VaultToken is the token a Vault client authenticates with, and how long it is valid
+type VaultToken struct {
+	TTL       time.Duration
+	Renewable bool
+}
+
+// NewVault creates a client of the Vault server of VAULT_ADDR, authenticated with the token of VAULT_TOKEN
+// unless it logs in
+func NewVault() (*Vault, error) {
+	addr := strings.TrimRight(os.Getenv("VAULT_ADDR"), "/")
+	if addr == "" {
+		return nil, fmt.Errorf("error: VAULT_ADDR is not set")
+	}
+	return &Vault{
+		http:      newHTTPClient(),
+		addr:      addr,
+		namespace: os.Getenv("VAULT_NAMESPACE"),
+		token:     os.Getenv("VAULT_TOKEN"),
+	}, nil
+}
+
+func (v *Vault) String() string {
+	return v.addr
+}
+
+func (v *Vault) do(ctx context.Context, method, path string, body interface{}, out interface{}) (*http.Response, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequest(method, v.addr+"/v1/"+strings.TrimLeft(path, "/"), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	v.mu.Lock()
+	if v.token != "" {
+		req.Header.Set("X-Vault-Token", v.token)
+	}
+	v.mu.Unlock()
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+	resp, err := v.http.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if out != nil && resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp, errors.Wrapf(err, "unable to parse the answer of Vault")
+		}
+	}
+	return resp, nil
+}
+
+// vaultAuth is the auth block of the Vault answers creating or renewing a token
+type vaultAuth struct {
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+		Renewable     bool   `json:"renewable"`
+	} `json:"auth"`
+}
+
+func (a vaultAuth) token() VaultToken {
+	return VaultToken{TTL: time.Duration(a.Auth.LeaseDuration) * time.Second, Renewable: a.Auth.Renewable}
+}
+
+// LoginKubernetes logs in with the Kubernetes auth method mounted at mount, with the token of a service account
+func (v *Vault) LoginKubernetes(ctx context.Context, mount, role, jwt string) (VaultToken, error) {
+	auth := vaultAuth{}
+	resp, err := v.do(ctx, http.MethodPost, "auth/"+strings.Trim(mount, "/")+"/login", map[string]string{"role": role, "jwt": jwt}, &auth)
+	if err != nil {
+		return VaultToken{}, errors.Wrapf(err, "unable to log in to Vault %s", v)
+	}
+	if resp.StatusCode != http.StatusOK || auth.Auth.ClientToken == "" {
+		return VaultToken{}, fmt.Errorf("error: unable to log in to Vault %s as role %s: Vault answered %s", v, role, resp.Status)
+	}
+	v.mu.Lock()
+	v.token = auth.Auth.ClientToken
+	v.mu.Unlock()
+	return auth.token(), nil
+}
+
+// LookupToken tells how long the token of the client is valid
+func (v *Vault) LookupToken(ctx context.Context) (VaultToken, error) {
+	body := struct {
+		Data struct {
+			TTL       int  `json:"ttl"`
+			Renewable bool `json:"renewable"`
+		} `json:"data"`
+	}{}
+	resp, err := v.do(ctx, http.MethodGet, "auth/token/lookup-self", nil, &body)
+	if err != nil {
+		return VaultToken{}, errors.Wrapf(err, "unable to look up the Vault token")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return VaultToken{}, fmt.Errorf("error: unable to look up the Vault token: Vault answered %s", resp.Status)
+	}
+	return VaultToken{TTL: time.Duration(body.Data.TTL) * time.Second, Renewable: body.Data.Renewable}, nil
+}
+
+// RenewToken extends the lease of the token of the client
+func (v *Vault) RenewToken(ctx context.Context) (VaultToken, error) {
+	auth := vaultAuth{}
+	resp, err := v.do(ctx, http.MethodPost, "auth/token/renew-self", map[string]string{}, &auth)
+	if err != nil {
+		return VaultToken{}, errors.Wrapf(err, "unable to renew the Vault token")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return VaultToken{}, fmt.Errorf("error: unable to renew the Vault token: Vault answered %s", resp.Status)
+	}
+	return auth.token(), nil
+}
+
+// Health checks that the server is initialized, unsealed and active, or a standby answering reads
+func (v *Vault) Health(ctx context.Context) error {
+	resp, err := v.do(ctx, http.MethodGet, "sys/health?standbyok=true&perfstandbyok=true", nil, nil)
+	if err != nil {
+		return errors.Wrapf(err, "unable to reach Vault %s", v)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotImplemented:
+		return fmt.Errorf("error: Vault %s is not initialized", v)
+	case http.StatusServiceUnavailable:
+		return fmt.Errorf("error: Vault %s is sealed", v)
+	}
+	return fmt.Errorf("error: Vault %s is unhealthy: %s", v, resp.Status)
+}
+
+// Read reads the fields of the secret at an API path, e.g. secret/data/octarine for the KV version 2 engine
+func (v *Vault) Read(ctx context.Context, path string) (map[string]interface{}, error) {
+	body := struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	resp, err := v.do(ctx, http.MethodGet, path, nil, &body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read Vault secret %s", path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error: unable to read Vault secret %s: Vault answered %s", path, resp.Status)
+	}
+	data := body.Data
+	// the KV version 2 engine nests the secret under data, next to its metadata
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+	return data, nil
+}
+
+// VaultString is a field of a Vault secret as text, fields which aren't strings are written as JSON
+func VaultString(data map[string]interface{}, key string) (string, bool) {
+	value, ok := data[key]
+	if !ok {
+		return "", false
+	}
+	if s, ok := value.(string); ok {
+		return s, true
+	}
+	out, err := json.Marshal(value)
+	if err != nil {
+		return "", false
+	}
+	return string(out), true
+}
+
+// lookupVault reads a key of a Vault secret with the token of VAULT_TOKEN. The location is the API path of
+// the secret.
+func lookupVault(ctx context.Context, r Reference) (string, error) {
+	v, err := NewVault()
+	if err != nil || v.token == "" {
+		return "", fmt.Errorf("error: secret %s requires VAULT_ADDR and VAULT_TOKEN", r)
+	}
+	data, err := v.Read(ctx, r.Location)
+	if err != nil {
+		return "", err
+	}
+	value, ok := VaultString(data, r.Key)
+	if !ok {
+		return "", fmt.Errorf("error: secret %s has no key %s", r.Location, r.Key)
+	}
+	return value, nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const azureAPIVersion = "2019-12-12"
+
+// azureStore talks to Azure Blob Storage, authenticating with the account key or a SAS token
+type azureStore struct {
+	http      *http.Client
+	account   string
+	container string
+	prefix    string
+	endpoint  string
+	key       []byte
+	sasToken  string
+}
+
+func newAzureStore(account, container, prefix string, q url.Values, creds Credentials) (*azureStore, error) {
+	s := &azureStore{
+		http:      newHTTPClient(),
+		account:   account,
+		container: container,
+		prefix:    strings.Trim(prefix, "/"),
+		endpoint:  fmt.Sprintf("https://%s.blob.core.windows.net", account),
+		sasToken:  strings.TrimPrefix(creds.get("sas_token", "AZURE_STORAGE_SAS_TOKEN"), "?"),
+	}
+	if e := q.Get("endpoint"); e != "" {
+		s.endpoint = strings.TrimRight(e, "/")
+	}
+	if key := creds.get("account_key", "AZURE_STORAGE_KEY"); key != "" {
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to decode the Azure account key")
+		}
+		s.key = decoded
+	}
+	if s.key == nil && s.sasToken == "" {
+		return nil, fmt.Errorf("error: Azure Blob storage requires account_key or sas_token")
+	}
+	return s, nil
+}
+
+func (s *azureStore) String() string {
+	return fmt.Sprintf("azblob://%s/%s/%s", s.account, s.container, s.prefix)
+}
+
+func (s *azureStore) do(ctx context.Context, method, blob string, q url.Values, body []byte, headers map[string]string) (*http.Response, error) {
+	path := "/" + s.container
+	if blob != "" {
+		path += "/" + blob
+	}
+	u, err := url.Parse(s.endpoint + uriEncode(path, false))
+	if err != nil {
+		return nil, err
+	}
+	query := q.Encode()
+	if s.key == nil {
+		query = strings.Trim(query+"&"+s.sasToken, "&")
+	}
+	u.RawQuery = query
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureAPIVersion)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if s.key != nil {
+		s.sign(req, len(body), q)
+	}
+	return s.http.Do(req.WithContext(ctx))
+}
+
+// sign adds the SharedKey authorization of the request
+func (s *azureStore) sign(req *http.Request, length int, q url.Values) {
+	contentLength := ""
+	if length > 0 {
+		contentLength = strconv.Itoa(length)
+	}
+	msHeaders := []string{}
+	for name := range req.Header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-ms-") {
+			msHeaders = append(msHeaders, lower)
+		}
+	}
+	sort.Strings(msHeaders)
+	canonicalHeaders := ""
+	for _, name := range msHeaders {
+		canonicalHeaders += name + ":" + strings.TrimSpace(req.Header.Get(name)) + "\n"
+	}
+	resource := "/" + s.account + req.URL.EscapedPath()
+	params := make([]string, 0, len(q))
+	for k := range q {
+		params = append(params, k)
+	}
+	sort.Strings(params)
+	for _, k := range params {
+		values := append([]string{}, q[k]...)
+		sort.Strings(values)
+		resource += "\n" + strings.ToLower(k) + ":" + strings.Join(values, ",")
+	}
+	toSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, x-ms-date is used instead
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+	}, "\n") + "\n" + canonicalHeaders + resource
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(toSign))
+	req.Header.Set("Authorization", "SharedKey "+s.account+":"+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+}
+
+func (s *azureStore) Put(ctx context.Context, key string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, joinKey(s.prefix, key), url.Values{}, data, map[string]string{
+		"x-ms-blob-type": "BlockBlob",
+		"Content-Type":   "application/octet-stream",
+	})
+	if err != nil {
+		return err
+	}
+	defer drain(resp)
+	return checkResponse(resp, "store "+key)
+}
+
+func (s *azureStore) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, joinKey(s.prefix, key), url.Values{}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer drain(resp)
+	if err := checkResponse(resp, "read "+key); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (s *azureStore) List(ctx context.Context, prefix string) ([]string, error) {
+	keys := []string{}
+	marker := ""
+	for {
+		q := url.Values{"restype": {"container"}, "comp": {"list"}, "prefix": {joinKey(s.prefix, prefix)}}
+		if marker != "" {
+			q.Set("marker", marker)
+		}
+		resp, err := s.do(ctx, http.MethodGet, "", q, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		result := struct {
+			Blobs []struct {
+				Name string `xml:"Name"`
+			} `xml:"Blobs>Blob"`
+			NextMarker string `xml:"NextMarker"`
+		}{}
+		err = checkResponse(resp, "list "+prefix)
+		if err == nil {
+			err = xml.NewDecoder(resp.Body).Decode(&result)
+		}
+		drain(resp)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range result.Blobs {
+			keys = append(keys, strings.TrimPrefix(strings.TrimPrefix(b.Name, s.prefix), "/"))
+		}
+		if result.NextMarker == "" {
+			return keys, nil
+		}
+		marker = result.NextMarker
+	}
+}
+
+func (s *azureStore) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, joinKey(s.prefix, key), url.Values{}, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer drain(resp)
+	return checkResponse(resp, "delete "+key)
+}
@@ -0,0 +1,259 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	gcsEndpoint = "https://storage.googleapis.com"
+	gcsScope    = "https://www.googleapis.com/auth/devstorage.read_write"
+	googleToken = "https://oauth2.googleapis.com/token"
+)
+
+// serviceAccount is the part of a Google service account key the token exchange needs
+type serviceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// gcsStore talks to the JSON API of Google Cloud Storage, authenticating as a service account
+type gcsStore struct {
+	http     *http.Client
+	bucket   string
+	prefix   string
+	endpoint string
+	account  *serviceAccount
+	key      *rsa.PrivateKey
+
+	mu sync.Mutex
+	// token is refreshed with the service account key, a token given without one is used as is
+	token   string
+	expires time.Time
+}
+
+func newGCSStore(bucket, prefix string, q url.Values, creds Credentials) (*gcsStore, error) {
+	s := &gcsStore{http: newHTTPClient(), bucket: bucket, prefix: prefix, endpoint: gcsEndpoint}
+	if e := q.Get("endpoint"); e != "" {
+		s.endpoint = strings.TrimRight(e, "/")
+	}
+	if token := creds["access_token"]; token != "" {
+		s.token = token
+		return s, nil
+	}
+	keyJSON := creds["service_account.json"]
+	if keyJSON == "" {
+		if path := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"); path != "" {
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to read the Google credentials")
+			}
+			keyJSON = string(b)
+		}
+	}
+	if keyJSON == "" {
+		return nil, fmt.Errorf("error: GCS storage requires service_account.json or access_token")
+	}
+	s.account = &serviceAccount{}
+	if err := json.Unmarshal([]byte(keyJSON), s.account); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the service account key")
+	}
+	if s.account.TokenURI == "" {
+		s.account.TokenURI = googleToken
+	}
+	block, _ := pem.Decode([]byte(s.account.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("error: the service account key holds no PEM private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return nil, errors.Wrapf(err, "unable to parse the private key of the service account")
+		}
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("error: the private key of the service account is not an RSA key")
+	}
+	s.key = key
+	return s, nil
+}
+
+func (s *gcsStore) String() string {
+	return fmt.Sprintf("gs://%s/%s", s.bucket, s.prefix)
+}
+
+// accessToken exchanges a JWT signed with the service account key for an OAuth token, reusing it until it expires
+func (s *gcsStore) accessToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.account == nil {
+		return s.token, nil
+	}
+	if s.token != "" && time.Now().Before(s.expires.Add(-time.Minute)) {
+		return s.token, nil
+	}
+	now := time.Now()
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":   s.account.ClientEmail,
+		"scope": gcsScope,
+		"aud":   s.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	unsigned := header + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to sign the token request")
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {unsigned + "." + enc.EncodeToString(sig)},
+	}
+	req, err := http.NewRequest(http.MethodPost, s.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.http.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to get a Google access token")
+	}
+	defer drain(resp)
+	if err := checkResponse(resp, "get a Google access token"); err != nil {
+		return "", err
+	}
+	token := struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", errors.Wrapf(err, "unable to parse the Google access token")
+	}
+	s.token, s.expires = token.AccessToken, now.Add(time.Duration(token.ExpiresIn)*time.Second)
+	return s.token, nil
+}
+
+func (s *gcsStore) do(ctx context.Context, method, u string, body []byte) (*http.Response, error) {
+	token, err := s.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	return s.http.Do(req.WithContext(ctx))
+}
+
+func (s *gcsStore) objectURL(key string) string {
+	return fmt.Sprintf("%s/storage/v1/b/%s/o/%s", s.endpoint, url.PathEscape(s.bucket), url.PathEscape(joinKey(s.prefix, key)))
+}
+
+func (s *gcsStore) Put(ctx context.Context, key string, data []byte) error {
+	u := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		s.endpoint, url.PathEscape(s.bucket), url.QueryEscape(joinKey(s.prefix, key)))
+	resp, err := s.do(ctx, http.MethodPost, u, data)
+	if err != nil {
+		return err
+	}
+	defer drain(resp)
+	return checkResponse(resp, "store "+key)
+}
+
+func (s *gcsStore) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.objectURL(key)+"?alt=media", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer drain(resp)
+	if err := checkResponse(resp, "read "+key); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (s *gcsStore) List(ctx context.Context, prefix string) ([]string, error) {
+	keys := []string{}
+	pageToken := ""
+	for {
+		q := url.Values{"prefix": {joinKey(s.prefix, prefix)}, "fields": {"items(name),nextPageToken"}}
+		if pageToken != "" {
+			q.Set("pageToken", pageToken)
+		}
+		resp, err := s.do(ctx, http.MethodGet, fmt.Sprintf("%s/storage/v1/b/%s/o?%s", s.endpoint, url.PathEscape(s.bucket), q.Encode()), nil)
+		if err != nil {
+			return nil, err
+		}
+		result := struct {
+			Items []struct {
+				Name string `json:"name"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}{}
+		err = checkResponse(resp, "list "+prefix)
+		if err == nil {
+			err = json.NewDecoder(resp.Body).Decode(&result)
+		}
+		drain(resp)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range result.Items {
+			keys = append(keys, strings.TrimPrefix(strings.TrimPrefix(item.Name, s.prefix), "/"))
+		}
+		if result.NextPageToken == "" {
+			return keys, nil
+		}
+		pageToken = result.NextPageToken
+	}
+}
+
+func (s *gcsStore) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	defer drain(resp)
+	return checkResponse(resp, "delete "+key)
+}
@@ -0,0 +1,243 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// s3Store talks to S3, or to a compatible service when an endpoint is given, signing with AWS Signature V4
+type s3Store struct {
+	http   *http.Client
+	bucket string
+	prefix string
+	region string
+	// endpoint is set for S3 compatible services, which are addressed path style
+	endpoint     string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+func newS3Store(bucket, prefix string, q url.Values, creds Credentials) (*s3Store, error) {
+	s := &s3Store{
+		http:         newHTTPClient(),
+		bucket:       bucket,
+		prefix:       prefix,
+		region:       q.Get("region"),
+		endpoint:     strings.TrimRight(q.Get("endpoint"), "/"),
+		accessKey:    creds.get("access_key_id", "AWS_ACCESS_KEY_ID"),
+		secretKey:    creds.get("secret_access_key", "AWS_SECRET_ACCESS_KEY"),
+		sessionToken: creds.get("session_token", "AWS_SESSION_TOKEN"),
+	}
+	if s.region == "" {
+		s.region = creds.get("region", "AWS_REGION")
+	}
+	if s.region == "" {
+		s.region = "us-east-1"
+	}
+	if s.accessKey == "" || s.secretKey == "" {
+		return nil, fmt.Errorf("error: S3 storage requires access_key_id and secret_access_key")
+	}
+	return s, nil
+}
+
+func (s *s3Store) String() string {
+	return fmt.Sprintf("s3://%s/%s", s.bucket, s.prefix)
+}
+
+// objectURL addresses a key of the bucket, an empty key addresses the bucket
+func (s *s3Store) objectURL(key string, q url.Values) *url.URL {
+	u := &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.s3.%s.amazonaws.com", s.bucket, s.region), Path: "/" + key}
+	if s.endpoint != "" {
+		if e, err := url.Parse(s.endpoint); err == nil {
+			u.Scheme, u.Host, u.Path = e.Scheme, e.Host, strings.TrimRight(e.Path, "/")+"/"+s.bucket+"/"+key
+		}
+	}
+	// the path is sent exactly as it is signed
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = canonicalQuery(q)
+	return u
+}
+
+func (s *s3Store) do(ctx context.Context, method string, u *url.URL, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, body, time.Now().UTC())
+	return s.http.Do(req.WithContext(ctx))
+}
+
+// sign adds the AWS Signature V4 headers to a request
+func (s *s3Store) sign(req *http.Request, body []byte, now time.Time) {
+	payload := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(payload[:])
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	names := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if s.sessionToken != "" {
+		names = append(names, "x-amz-security-token")
+	}
+	headers := ""
+	for _, name := range names {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		headers += name + ":" + strings.TrimSpace(value) + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		headers,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	digest := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	for _, part := range []string{s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// uriEncode escapes everything but the unreserved characters, and slashes unless encodeSlash is set
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' || c == '/' && !encodeSlash {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// canonicalQuery is the query string sorted by key and encoded the way Signature V4 expects
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := []string{}
+	for _, k := range keys {
+		for _, v := range q[k] {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, s.objectURL(joinKey(s.prefix, key), nil), data)
+	if err != nil {
+		return err
+	}
+	defer drain(resp)
+	return checkResponse(resp, "store "+key)
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.objectURL(joinKey(s.prefix, key), nil), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer drain(resp)
+	if err := checkResponse(resp, "read "+key); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (s *s3Store) List(ctx context.Context, prefix string) ([]string, error) {
+	keys := []string{}
+	token := ""
+	for {
+		q := url.Values{"list-type": {"2"}, "prefix": {joinKey(s.prefix, prefix)}}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		resp, err := s.do(ctx, http.MethodGet, s.objectURL("", q), nil)
+		if err != nil {
+			return nil, err
+		}
+		result := struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}{}
+		err = checkResponse(resp, "list "+prefix)
+		if err == nil {
+			err = xml.NewDecoder(resp.Body).Decode(&result)
+		}
+		drain(resp)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range result.Contents {
+			keys = append(keys, strings.TrimPrefix(strings.TrimPrefix(c.Key, s.prefix), "/"))
+		}
+		if !result.IsTruncated {
+			return keys, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, s.objectURL(joinKey(s.prefix, key), nil), nil)
+	if err != nil {
+		return err
+	}
+	defer drain(resp)
+	return checkResponse(resp, "delete "+key)
+}
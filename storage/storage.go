@@ -0,0 +1,122 @@
+// Copyright 2019 The Meshery Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package storage keeps the artifacts of the adapter, like backups, in object storage.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const requestTimeout = 2 * time.Minute
+
+// ErrNotFound is returned by Get and Delete for keys the store doesn't hold
+var ErrNotFound = errors.New("object not found")
+
+// Store is a bucket of objects addressed by slash separated keys
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	// List returns the keys starting with prefix
+	List(ctx context.Context, prefix string) ([]string, error)
+	Delete(ctx context.Context, key string) error
+	// String names the location of the store for messages, without credentials
+	String() string
+}
+
+// Credentials are the keys of the Secret holding the credentials of a store, missing keys fall back
+// to the environment variables the provider's own tools read
+type Credentials map[string]string
+
+func (c Credentials) get(key, env string) string {
+	if v := c[key]; v != "" {
+		return v
+	}
+	return os.Getenv(env)
+}
+
+// New opens the store a URL names:
+//
+//	s3://bucket/prefix?region=us-east-1&endpoint=https://minio:9000
+//	gs://bucket/prefix
+//	azblob://account/container/prefix
+func New(location string, creds Credentials) (Store, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse the storage location %q", location)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("error: the storage location %q names no bucket", location)
+	}
+	prefix := strings.Trim(u.Path, "/")
+	switch u.Scheme {
+	case "s3":
+		return newS3Store(u.Host, prefix, u.Query(), creds)
+	case "gs":
+		return newGCSStore(u.Host, prefix, u.Query(), creds)
+	case "azblob":
+		parts := strings.SplitN(prefix, "/", 2)
+		if parts[0] == "" {
+			return nil, fmt.Errorf("error: the storage location %q names no container", location)
+		}
+		if len(parts) == 1 {
+			parts = append(parts, "")
+		}
+		return newAzureStore(u.Host, parts[0], parts[1], u.Query(), creds)
+	}
+	return nil, fmt.Errorf("error: unsupported storage %q, use s3://, gs:// or azblob://", u.Scheme)
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "/" + key
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: requestTimeout}
+}
+
+// checkResponse turns the error statuses of a provider into errors, reading at most a page of the body
+func checkResponse(resp *http.Response, what string) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return errors.Wrapf(ErrNotFound, "%s", what)
+	}
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("error: unable to %s: %s: %s", what, resp.Status, strings.TrimSpace(string(body)))
+}
+
+func drain(resp *http.Response) {
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// IsNotFound tells whether an error of a store is about a missing object
+func IsNotFound(err error) bool {
+	return errors.Cause(err) == ErrNotFound
+}